@@ -0,0 +1,174 @@
+// dataimport loads the YAML template tables under data/yaml into their
+// PostgreSQL tmpl_* tables, for servers running with [data] source = "db".
+//
+// Usage:
+//
+//	go run ./cmd/dataimport [-config path] [-dir path] [-only list] [-prune] [-dry-run]
+//
+// Each file is first loaded with the server's own data.Load* function, so a
+// file the server would reject is never imported. Entries are upserted by
+// their ID; -prune also deletes rows that are no longer in the file.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/l1jgo/server/internal/config"
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/persist"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+func main() {
+	defaultCfg := "config/server.toml"
+	if p := os.Getenv("L1JGO_CONFIG"); p != "" {
+		defaultCfg = p
+	}
+	cfgPath := flag.String("config", defaultCfg, "server config (database DSN)")
+	dir := flag.String("dir", filepath.Join("data", "yaml"), "YAML data directory")
+	only := flag.String("only", "", "comma-separated files or tables to import (default: all)")
+	prune := flag.Bool("prune", false, "delete rows whose IDs are no longer in the YAML file")
+	dryRun := flag.Bool("dry-run", false, "validate and count entries without writing")
+	flag.Parse()
+
+	if err := run(*cfgPath, *dir, *only, *prune, *dryRun); err != nil {
+		fmt.Fprintln(os.Stderr, "dataimport:", err)
+		os.Exit(1)
+	}
+}
+
+func run(cfgPath, dir, only string, prune, dryRun bool) error {
+	sets, err := selectSets(only)
+	if err != nil {
+		return err
+	}
+
+	var repo *persist.TemplateRepo
+	if !dryRun {
+		cfg, err := config.Load(cfgPath)
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		db, err := persist.NewDB(ctx, cfg.Database, zap.NewNop())
+		if err != nil {
+			return fmt.Errorf("database: %w", err)
+		}
+		defer db.Close()
+		if err := persist.RunMigrations(ctx, db.Pool); err != nil {
+			return fmt.Errorf("migrations: %w", err)
+		}
+		repo = persist.NewTemplateRepo(db)
+	}
+
+	validated := make(map[string]bool)
+	for _, set := range sets {
+		path := filepath.Join(dir, set.File)
+		if err := validate(set, dir, validated); err != nil {
+			return fmt.Errorf("%s: %w", set.File, err)
+		}
+		entries, err := readEntries(path, set.Root)
+		if err != nil {
+			return fmt.Errorf("%s: %w", set.File, err)
+		}
+		if dryRun {
+			fmt.Printf("%-20s %6d entries (dry run)\n", set.File, len(entries))
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		n, err := repo.Import(ctx, set, entries, prune)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("%s → %s: %w", set.File, set.Table, err)
+		}
+		fmt.Printf("%-20s %6d entries → %s\n", set.File, n, set.Table)
+	}
+	return nil
+}
+
+// selectSets resolves -only (file names or table names) to template sets.
+func selectSets(only string) ([]*persist.TemplateSet, error) {
+	var sets []*persist.TemplateSet
+	if only == "" {
+		for i := range persist.TemplateSets {
+			sets = append(sets, &persist.TemplateSets[i])
+		}
+		return sets, nil
+	}
+	for _, name := range strings.Split(only, ",") {
+		name = strings.TrimSpace(name)
+		var found *persist.TemplateSet
+		for i := range persist.TemplateSets {
+			s := &persist.TemplateSets[i]
+			if s.File == name || s.Table == name || strings.TrimSuffix(s.File, ".yaml") == name {
+				found = s
+				break
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("unknown table %q", name)
+		}
+		sets = append(sets, found)
+	}
+	return sets, nil
+}
+
+// validate loads a file with the server's loader. The three item files are
+// loaded together (data.LoadItemTable), once.
+func validate(set *persist.TemplateSet, dir string, done map[string]bool) error {
+	path := filepath.Join(dir, set.File)
+	var err error
+	switch set.File {
+	case "npc_list.yaml":
+		_, err = data.LoadNpcTable(path)
+	case "spawn_list.yaml":
+		_, err = data.LoadSpawnList(path)
+	case "weapon_list.yaml", "armor_list.yaml", "etcitem_list.yaml":
+		if done["items"] {
+			return nil
+		}
+		done["items"] = true
+		_, err = data.LoadItemTable(
+			filepath.Join(dir, "weapon_list.yaml"),
+			filepath.Join(dir, "armor_list.yaml"),
+			filepath.Join(dir, "etcitem_list.yaml"),
+		)
+	case "drop_list.yaml":
+		_, err = data.LoadDropTable(path)
+	case "shop_list.yaml":
+		_, err = data.LoadShopTable(path)
+	case "skill_list.yaml":
+		_, err = data.LoadSkillTable(path)
+	case "mob_skill_list.yaml":
+		_, err = data.LoadMobSkillTable(path)
+	default:
+		return fmt.Errorf("no loader for %s", set.File)
+	}
+	return err
+}
+
+// readEntries decodes the entry list under the root key as generic maps, so
+// every YAML field is kept in the JSONB row under its original key.
+func readEntries(path, root string) ([]map[string]any, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string][]map[string]any
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+	entries, ok := doc[root]
+	if !ok {
+		return nil, fmt.Errorf("missing top-level key %q", root)
+	}
+	return entries, nil
+}
@@ -9,14 +9,13 @@ import (
 	"syscall"
 	"time"
 
-	"math/rand"
-
 	"github.com/l1jgo/server/internal/config"
 	"github.com/l1jgo/server/internal/core/ecs"
 	"github.com/l1jgo/server/internal/core/event"
 	coresys "github.com/l1jgo/server/internal/core/system"
 	"github.com/l1jgo/server/internal/data"
 	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/metrics"
 	gonet "github.com/l1jgo/server/internal/net"
 	"github.com/l1jgo/server/internal/net/packet"
 	"github.com/l1jgo/server/internal/persist"
@@ -36,14 +35,18 @@ func main() {
 
 // ── Startup display helpers ────────────────────────────────────────
 
-func printBanner(serverName string, serverID int) {
+func printBanner(serverName string, serverID, maxOnline int) {
 	fmt.Println()
 	fmt.Println("\033[36;1m  ┌───────────────────────────────────────────┐\033[0m")
 	fmt.Println("\033[36;1m  │\033[0m           L1JGO-Whale  v0.1.0             \033[36;1m│\033[0m")
 	fmt.Println("\033[36;1m  │\033[0m      天堂 3.80C · Go 遊戲伺服器           \033[36;1m│\033[0m")
 	fmt.Println("\033[36;1m  └───────────────────────────────────────────┘\033[0m")
 	fmt.Println()
-	fmt.Printf("  \033[1m伺服器:\033[0m %s \033[90m(編號: %d)\033[0m\n\n", serverName, serverID)
+	limit := "不限"
+	if maxOnline > 0 {
+		limit = fmt.Sprintf("%d", maxOnline)
+	}
+	fmt.Printf("  \033[1m伺服器:\033[0m %s \033[90m(編號: %d · 人數上限: %s)\033[0m\n\n", serverName, serverID, limit)
 }
 
 func printSection(title string) {
@@ -109,7 +112,15 @@ func run() error {
 	}
 	defer log.Sync()
 
-	printBanner(cfg.Server.Name, cfg.Server.ID)
+	printBanner(cfg.Server.Name, cfg.Server.ID, cfg.Server.MaxOnline)
+
+	// 遊戲亂數種子：記錄於日誌，回報問題時可用相同種子重現
+	seed := cfg.Server.RandSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	world.SetRand(world.NewRand(seed))
+	log.Info("遊戲亂數種子", zap.Int64("seed", seed))
 
 	// 3. Connect to PostgreSQL and run migrations
 	printSection("資料庫")
@@ -143,6 +154,7 @@ func run() error {
 	boardRepo := persist.NewBoardRepo(db)
 	mailRepo := persist.NewMailRepo(db)
 	petRepo := persist.NewPetRepo(db)
+	bossKillRepo := persist.NewBossKillRepo(db)
 
 	// 4a. WAL crash recovery — replay unprocessed economic transactions
 	{
@@ -160,10 +172,30 @@ func run() error {
 	// 5. Create ECS World and game World State
 	ecsWorld := ecs.NewWorld()
 	worldState := world.NewState()
+	worldState.SetViewRange(int32(cfg.World.ViewRange))
+	world.SetAgroNameLawful(int32(cfg.World.NpcAgroNameLawful))
+	{
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		killed, err := bossKillRepo.KilledBossIDs(ctx)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("load boss kill history: %w", err)
+		}
+		worldState.SetKilledBosses(killed)
+	}
 
 	// 5a. Load NPC data and spawn NPCs
 	printSection("資料載入")
 
+	switch cfg.Data.Source {
+	case "", "yaml":
+	case "db":
+		data.SetFileReader(persist.NewTemplateRepo(db).FileReader())
+		printOK("模板資料來源：資料庫（空表改讀 YAML）")
+	default:
+		return fmt.Errorf("data.source: unknown source %q (want yaml or db)", cfg.Data.Source)
+	}
+
 	npcTable, err := data.LoadNpcTable("data/yaml/npc_list.yaml")
 	if err != nil {
 		return fmt.Errorf("load npc table: %w", err)
@@ -179,7 +211,13 @@ func run() error {
 	if err != nil {
 		return fmt.Errorf("load map data: %w", err)
 	}
+	if _, err := mapDataTable.LoadMapTiles("map"); err != nil {
+		return fmt.Errorf("load map tiles: %w", err)
+	}
 	printStat("地圖資料", mapDataTable.Count())
+	if n := mapDataTable.FallbackCount(); n > 0 {
+		log.Warn("部分地圖缺少地形檔，範圍內視為全部可通行", zap.Int("地圖數", n))
+	}
 
 	sprTable, err := data.LoadSprTable("data/yaml/spr_action.yaml")
 	if err != nil {
@@ -217,7 +255,11 @@ func run() error {
 	if err != nil {
 		return fmt.Errorf("load drop table: %w", err)
 	}
+	if err := dropTable.LoadGlobalDrops("data/yaml/global_drops.yaml"); err != nil {
+		return fmt.Errorf("load global drops: %w", err)
+	}
 	printStat("掉寶表", dropTable.Count())
+	printStat("全域掉落", len(dropTable.Global()))
 
 	teleportTable, err := data.LoadTeleportTable("data/yaml/teleport_list.yaml")
 	if err != nil {
@@ -243,6 +285,12 @@ func run() error {
 	}
 	printStat("隨機傳送門", randomPortalTable.Count())
 
+	instanceTable, err := data.LoadInstanceTable("data/yaml/instance_maps.yaml")
+	if err != nil {
+		return fmt.Errorf("load instance maps: %w", err)
+	}
+	printStat("限制進入地圖", instanceTable.Count())
+
 	skillTable, err := data.LoadSkillTable("data/yaml/skill_list.yaml")
 	if err != nil {
 		return fmt.Errorf("load skill table: %w", err)
@@ -321,6 +369,45 @@ func run() error {
 	}
 	printStat("武器技能", weaponSkillTable.Count())
 
+	bossSchedule, err := data.LoadBossSchedule("data/yaml/boss_schedule.yaml")
+	if err != nil {
+		return fmt.Errorf("load boss schedule: %w", err)
+	}
+	printStat("世界頭目排程", len(bossSchedule))
+
+	messageTable, err := data.LoadMessageTable("data/yaml/messages.yaml")
+	if err != nil {
+		return fmt.Errorf("load messages: %w", err)
+	}
+	printStat("訊息字串", messageTable.Count())
+	if cfg.Logging.Level == "debug" {
+		handler.EnableMessageCheck(messageTable, log)
+	}
+
+	starterKit, err := data.LoadStarterKit("data/yaml/starter_kit.yaml")
+	if err != nil {
+		return fmt.Errorf("load starter kit: %w", err)
+	}
+	printStat("新手禮包", starterKit.Count())
+
+	chatFilter, err := data.LoadChatFilter("data/yaml/chat_filter.yaml")
+	if err != nil {
+		return fmt.Errorf("load chat filter: %w", err)
+	}
+	printStat("聊天禁用字詞", chatFilter.Count())
+
+	getbackTable, err := data.LoadGetbackTable("data/yaml/getback_list.yaml")
+	if err != nil {
+		return fmt.Errorf("load getback table: %w", err)
+	}
+	printStat("回城座標", getbackTable.Count())
+
+	weatherTable, err := data.LoadWeatherTable("data/yaml/weather.yaml")
+	if err != nil {
+		return fmt.Errorf("load weather table: %w", err)
+	}
+	printStat("地圖天氣", weatherTable.Count())
+
 	doorTable, err := data.LoadDoorTable("data/yaml/door_gfx.yaml", "data/yaml/door_spawn.yaml")
 	if err != nil {
 		return fmt.Errorf("load door table: %w", err)
@@ -334,6 +421,7 @@ func run() error {
 		return fmt.Errorf("lua engine: %w", err)
 	}
 	defer luaEngine.Close()
+	luaEngine.SetCallTimeout(cfg.Lua.Timeout)
 	printOK("Lua 腳本載入完成")
 
 	// 5d. Load clans from DB
@@ -367,7 +455,9 @@ func run() error {
 
 	// 6. Create packet handler registry and register handlers
 	pktReg := packet.NewRegistry(log)
+	writeQueue := persist.NewWriteQueue(1024, log)
 	deps := &handler.Deps{
+		Writes:      writeQueue,
 		AccountRepo: accountRepo,
 		CharRepo:    charRepo,
 		ItemRepo:    itemRepo,
@@ -383,6 +473,7 @@ func run() error {
 		TeleportHtml: teleportHtmlTable,
 		Portals:      portalTable,
 		RandomPortals: randomPortalTable,
+		Instances:     instanceTable,
 		Skills:       skillTable,
 		Npcs:         npcTable,
 		MobSkills:      mobSkillTable,
@@ -404,13 +495,21 @@ func run() error {
 		BoardRepo:     boardRepo,
 		MailRepo:      mailRepo,
 		PetRepo:       petRepo,
+		BossKillRepo:  bossKillRepo,
+		BossRank:      &handler.BossRankCache{},
 		PetTypes:      petTypeTable,
 		PetItems:      petItemTable,
 		Dolls:         dollTable,
 		TeleportPages: teleportPageTable,
 		WeaponSkills:  weaponSkillTable,
+		Messages:      messageTable,
+		StarterKit:    starterKit,
+		ChatFilter:    chatFilter,
+		Getback:       getbackTable,
+		Weather:       weatherTable,
 	}
 	handler.RegisterAll(pktReg, deps)
+	handler.RefreshBossRank(deps)
 
 	// 7. Create network server
 	pktPerSec := 0
@@ -422,6 +521,7 @@ func run() error {
 		cfg.Network.InQueueSize,
 		cfg.Network.OutQueueSize,
 		pktPerSec,
+		cfg.Network.MaxPacketSize,
 		log,
 	)
 	if err != nil {
@@ -480,7 +580,8 @@ func run() error {
 	// 製作系統（直接呼叫，非 Phase 系統）
 	deps.Craft = system.NewCraftSystem(deps)
 	// 物品地面操作系統（銷毀、掉落、撿取）
-	deps.ItemGround = system.NewItemGroundSystem(deps)
+	itemGroundSys := system.NewItemGroundSystem(deps)
+	deps.ItemGround = itemGroundSys
 	// 寵物生命週期系統（召喚/收回/解放/死亡/經驗/指令）
 	deps.PetLife = system.NewPetSystem(deps)
 	// 魔法娃娃系統（召喚/解散/屬性加成）
@@ -508,27 +609,57 @@ func run() error {
 	runner.Register(system.NewNpcAISystem(worldState, deps))
 	runner.Register(system.NewCompanionAISystem(worldState, deps))
 	// Phase 3: Post-update
-	runner.Register(system.NewRegenSystem(worldState, luaEngine))
-	runner.Register(system.NewWeatherSystem(worldState))
+	runner.Register(system.NewRegenSystem(worldState, luaEngine, &cfg.Gameplay))
+	runner.Register(system.NewWeatherSystem(worldState, weatherTable))
 	runner.Register(system.NewMapTimerSystem(worldState, deps))
 	hauntedHouseSys := system.NewHauntedHouseSystem(worldState, deps)
 	deps.HauntedHouse = hauntedHouseSys
 	inputSys.SetHauntedHouse(hauntedHouseSys)
+	inputSys.SetDeps(deps)
+	deps.LinkDead = inputSys
 	runner.Register(hauntedHouseSys)
 	dragonDoorSys := system.NewDragonDoorSystem(worldState, deps)
 	deps.DragonDoor = dragonDoorSys
 	runner.Register(dragonDoorSys)
-	runner.Register(system.NewGroundItemSystem(worldState))
+	runner.Register(system.NewGroundItemSystem(worldState, deps, itemGroundSys))
 	runner.Register(system.NewPartyRefreshSystem(worldState, deps, 10)) // 10 ticks = 2 seconds
+	runner.Register(system.NewDuelSystem(worldState, deps, 5))          // 5 ticks = 1 second
+	if cfg.Server.MaxOnline > 0 && cfg.Server.LoginQueueSize > 0 {
+		runner.Register(system.NewLoginQueueSystem(deps, 5)) // 5 ticks = 1 second
+	}
 	rankingSys := system.NewRankingSystem(worldState, deps)
 	deps.Ranking = rankingSys
 	runner.Register(rankingSys)
+	rateEventSys := system.NewRateEventSystem(deps)
+	deps.RateEvent = rateEventSys
+	runner.Register(rateEventSys)
+	worldBossSys := system.NewWorldBossSystem(worldState, deps, bossSchedule)
+	event.Subscribe(eventBus, worldBossSys.OnEntityKilled)
+	runner.Register(worldBossSys)
 	runner.Register(system.NewVisibilitySystem(worldState, deps))
 	// Phase 4: Output — flush buffered packets to TCP
 	runner.Register(system.NewOutputSystem(sessStore))
 	// Phase 5: Persistence (auto-save interval from config)
-	persistSys := system.NewPersistenceSystem(worldState, charRepo, itemRepo, buffRepo, walRepo, log, cfg.Persistence.BatchIntervalTicks)
+	persistSys := system.NewPersistenceSystem(worldState, charRepo, itemRepo, buffRepo, walRepo, petRepo, log, cfg.Persistence.BatchIntervalTicks)
 	runner.Register(persistSys)
+
+	// 慢 tick 監測：超過 tick_rate 時記錄耗時最多的 System
+	if cfg.Network.SlowTickWarn {
+		runner.SetSlowTickHandler(cfg.Network.TickRate, slowTickLogger(log, cfg.Network.TickRate, cfg.Network.SlowTickLogInterval))
+	}
+
+	// 監控（[metrics] addr 為空則停用）
+	var metricsCollector *metrics.Collector
+	if cfg.Metrics.Addr != "" {
+		metricsCollector = metrics.New()
+		persistSys.SetMetrics(metricsCollector)
+		runner.Register(system.NewMetricsSystem(worldState, metricsCollector, 5)) // 5 ticks = 1 second
+		metricsSrv, err := metrics.Serve(cfg.Metrics.Addr, metricsCollector, log)
+		if err != nil {
+			return err
+		}
+		defer metricsSrv.Close()
+	}
 	// Phase 6: Cleanup
 	runner.Register(system.NewCleanupSystem(ecsWorld))
 
@@ -550,13 +681,20 @@ func run() error {
 	printSection("伺服器就緒")
 	printReady(fmt.Sprintf("監聽位址 %s", netServer.Addr().String()))
 	printReady(fmt.Sprintf("遊戲迴圈啟動 (系統tick: %s, 輸入輪詢: 2ms)", cfg.Network.TickRate))
+	if cfg.Metrics.Addr != "" {
+		printReady(fmt.Sprintf("監控位址 http://%s/status", cfg.Metrics.Addr))
+	}
 	fmt.Println()
 
 	for {
 		select {
 		case <-systemTicker.C:
 			// 完整 tick：Phase 0-6 按順序執行（Phase 0 可能是空操作，因 inputPoll 已排空）
+			tickStart := time.Now()
 			runner.Tick(cfg.Network.TickRate)
+			if metricsCollector != nil {
+				metricsCollector.RecordTick(time.Since(tickStart))
+			}
 		case <-inputPoll.C:
 			// 高頻輸入輪詢：只跑 Phase 0（透過 Runner.TickPhase 維持架構合規）
 			runner.TickPhase(coresys.PhaseInput, 0)
@@ -564,6 +702,7 @@ func run() error {
 			log.Info("收到關閉信號", zap.String("signal", sig.String()))
 			// Save all players before stopping
 			persistSys.SaveAllPlayers()
+			writeQueue.Close()
 			netServer.Shutdown()
 			log.Info("伺服器已停止")
 			return nil
@@ -571,6 +710,30 @@ func run() error {
 	}
 }
 
+// slowTickLogger 回傳慢 tick 回報函式：每 interval 最多記錄一次警告（含耗時前 3 名的 System），
+// 期間內其餘超時只計數，下次警告一併回報。
+func slowTickLogger(log *zap.Logger, budget, interval time.Duration) func(time.Duration, []coresys.SystemCost) {
+	var lastLog time.Time
+	suppressed := 0
+	return func(total time.Duration, costs []coresys.SystemCost) {
+		if time.Since(lastLog) < interval {
+			suppressed++
+			return
+		}
+		fields := []zap.Field{
+			zap.Duration("耗時", total),
+			zap.Duration("預算", budget),
+			zap.Int("期間其他超時", suppressed),
+		}
+		for i := 0; i < len(costs) && i < 3; i++ {
+			fields = append(fields, zap.Duration(strings.TrimPrefix(costs[i].Name, "*system."), costs[i].Duration))
+		}
+		log.Warn("遊戲迴圈 tick 超時", fields...)
+		lastLog = time.Now()
+		suppressed = 0
+	}
+}
+
 // loadClans loads all clans and members from DB into world state.
 func loadClans(ctx context.Context, ws *world.State, clanRepo *persist.ClanRepo) (int, error) {
 	clans, members, err := clanRepo.LoadAll(ctx)
@@ -628,69 +791,32 @@ func spawnNpcs(ws *world.State, npcTable *data.NpcTable, spawns []data.SpawnEntr
 			log.Warn("生成: 未知的 NPC ID", zap.Int32("npc_id", spawn.NpcID))
 			continue
 		}
+		area := spawn.Area
+		patrol := patrolRoute(spawn, maps, log)
 		for i := 0; i < spawn.Count; i++ {
 			x := spawn.X
 			y := spawn.Y
-			if spawn.RandomX > 0 {
-				x += int32(rand.Intn(int(spawn.RandomX*2+1))) - spawn.RandomX
-			}
-			if spawn.RandomY > 0 {
-				y += int32(rand.Intn(int(spawn.RandomY*2+1))) - spawn.RandomY
-			}
-
-			// Resolve animation-based speeds from SprTable (mirrors Java L1NpcInstance.initStats).
-			// Only override when the template marks the action as enabled (non-zero).
-			atkSpeed := tmpl.AtkSpeed
-			moveSpeed := tmpl.PassiveSpeed
-			if sprTable != nil {
-				gfx := int(tmpl.GfxID)
-				if tmpl.AtkSpeed != 0 {
-					if v := sprTable.GetAttackSpeed(gfx, data.ActAttack); v > 0 {
-						atkSpeed = int16(v)
-					}
+			if area != nil {
+				// 區域生成：在矩形內隨機挑選可通行的空格，找不到時退回矩形中心
+				if px, py, ok := system.PickAreaTile(ws, maps, spawn.MapID, area, 0); ok {
+					x, y = px, py
+				} else {
+					x, y = (area.X1+area.X2)/2, (area.Y1+area.Y2)/2
 				}
-				if tmpl.PassiveSpeed != 0 {
-					if v := sprTable.GetMoveSpeed(gfx, data.ActWalk); v > 0 {
-						moveSpeed = int16(v)
-					}
+			} else {
+				if spawn.RandomX > 0 {
+					x += int32(world.RandInt(int(spawn.RandomX*2+1))) - spawn.RandomX
+				}
+				if spawn.RandomY > 0 {
+					y += int32(world.RandInt(int(spawn.RandomY*2+1))) - spawn.RandomY
 				}
 			}
 
-			npc := &world.NpcInfo{
-				ID:           world.NextNpcID(),
-				NpcID:        tmpl.NpcID,
-				Impl:         tmpl.Impl,
-				GfxID:        tmpl.GfxID,
-				Name:         tmpl.Name,
-				NameID:       tmpl.NameID,
-				Level:        tmpl.Level,
-				X:            x,
-				Y:            y,
-				MapID:        spawn.MapID,
-				Heading:      spawn.Heading,
-				HP:           tmpl.HP,
-				MaxHP:        tmpl.HP,
-				MP:           tmpl.MP,
-				MaxMP:        tmpl.MP,
-				AC:           tmpl.AC,
-				STR:          tmpl.STR,
-				DEX:          tmpl.DEX,
-				Exp:          tmpl.Exp,
-				Lawful:       tmpl.Lawful,
-				Size:         tmpl.Size,
-				MR:           tmpl.MR,
-				Undead:       tmpl.Undead,
-				Agro:         tmpl.Agro,
-				AtkDmg:       int32(tmpl.Level) + int32(tmpl.STR)/3,
-				Ranged:       tmpl.Ranged,
-				AtkSpeed:     atkSpeed,
-				MoveSpeed:    moveSpeed,
-				PoisonAtk:    tmpl.PoisonAtk,
-				SpawnX:       x,
-				SpawnY:       y,
-				SpawnMapID:   spawn.MapID,
-				RespawnDelay: spawn.RespawnDelay,
-			}
+			npc := system.NewNpcFromTemplate(tmpl, sprTable, x, y, spawn.MapID, spawn.Heading)
+			npc.RespawnDelay = spawn.RespawnDelay
+			npc.SpawnArea = area
+			npc.Patrol = patrol
+			npc.PatrolPause = spawn.PatrolPause * 5 // 秒 → ticks
 			ws.AddNpc(npc)
 			if maps != nil {
 				maps.SetImpassable(npc.MapID, npc.X, npc.Y, true)
@@ -701,6 +827,24 @@ func spawnNpcs(ws *world.State, npcTable *data.NpcTable, spawns []data.SpawnEntr
 	return total
 }
 
+// patrolRoute converts a spawn's patrol waypoints. The whole route is dropped
+// (the NPC falls back to wandering) when a waypoint is outside the map or on
+// an impassable tile.
+func patrolRoute(spawn data.SpawnEntry, maps *data.MapDataTable, log *zap.Logger) []data.PatrolPoint {
+	if len(spawn.Patrol) == 0 {
+		return nil
+	}
+	for _, wp := range spawn.Patrol {
+		if maps != nil && !(maps.IsInMap(spawn.MapID, wp.X, wp.Y) && maps.IsPassablePoint(spawn.MapID, wp.X, wp.Y)) {
+			log.Warn("生成: 巡邏路點不可通行，忽略巡邏路線",
+				zap.Int32("npc_id", spawn.NpcID), zap.Int16("map", spawn.MapID),
+				zap.Int32("x", wp.X), zap.Int32("y", wp.Y))
+			return nil
+		}
+	}
+	return spawn.Patrol
+}
+
 // spawnDoors creates door instances from door spawn data and adds them to world state.
 func spawnDoors(ws *world.State, doorTable *data.DoorTable) int {
 	total := 0
@@ -1,6 +1,7 @@
 package system
 
 import (
+	"fmt"
 	"sort"
 	"time"
 )
@@ -9,6 +10,18 @@ import (
 type Runner struct {
 	systems []System
 	sorted  bool
+
+	// 慢 tick 監測（budget <= 0 = 停用）
+	budget time.Duration
+	onSlow func(total time.Duration, costs []SystemCost)
+	costs  []SystemCost // 可重用 buffer（遊戲迴圈單線程）
+}
+
+// SystemCost is one system's share of a tick.
+type SystemCost struct {
+	Name     string
+	Phase    Phase
+	Duration time.Duration
 }
 
 func NewRunner() *Runner {
@@ -22,11 +35,41 @@ func (r *Runner) Register(s System) {
 	r.sorted = false
 }
 
+// SetSlowTickHandler 設定慢 tick 回報：整個 Tick 超過 budget 時以各 System 耗時呼叫 fn。
+// costs 僅在回呼期間有效（buffer 會被下一個 tick 重用）。
+func (r *Runner) SetSlowTickHandler(budget time.Duration, fn func(total time.Duration, costs []SystemCost)) {
+	r.budget = budget
+	r.onSlow = fn
+}
+
 func (r *Runner) Tick(dt time.Duration) {
 	r.ensureSorted()
+	if r.budget <= 0 || r.onSlow == nil {
+		for _, s := range r.systems {
+			s.Update(dt)
+		}
+		return
+	}
+
+	r.costs = r.costs[:0]
+	tickStart := time.Now()
 	for _, s := range r.systems {
+		start := time.Now()
 		s.Update(dt)
+		r.costs = append(r.costs, SystemCost{Phase: s.Phase(), Duration: time.Since(start)})
+	}
+	total := time.Since(tickStart)
+	if total <= r.budget {
+		return
+	}
+	// 名稱只在超時時才計算（避免每 tick 反射）
+	for i, s := range r.systems {
+		r.costs[i].Name = fmt.Sprintf("%T", s)
 	}
+	sort.Slice(r.costs, func(i, j int) bool {
+		return r.costs[i].Duration > r.costs[j].Duration
+	})
+	r.onSlow(total, r.costs)
 }
 
 // TickPhase 只執行指定 Phase 的 System。
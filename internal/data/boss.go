@@ -0,0 +1,107 @@
+package data
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BossSpawn defines one scheduled world boss.
+//
+// Schedule is either a fixed interval (Interval, counted from server start and
+// then from each spawn) or a list of daily wall-clock times (Times, "HH:MM").
+// When both are set, Times wins.
+type BossSpawn struct {
+	NpcID    int32
+	MapID    int16
+	X        int32
+	Y        int32
+	Heading  int16
+	Interval time.Duration
+	Times    []time.Duration // offsets from local midnight
+	Announce string          // custom spawn announcement; empty = default text
+}
+
+// NextSpawn returns the next spawn time strictly after `after`.
+func (b *BossSpawn) NextSpawn(after time.Time) time.Time {
+	if len(b.Times) > 0 {
+		midnight := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, after.Location())
+		for day := 0; day < 2; day++ {
+			base := midnight.AddDate(0, 0, day)
+			for _, off := range b.Times {
+				if t := base.Add(off); t.After(after) {
+					return t
+				}
+			}
+		}
+	}
+	return after.Add(b.Interval)
+}
+
+// --- YAML loading ---
+
+type bossEntry struct {
+	NpcID    int32    `yaml:"npc_id"`
+	MapID    int16    `yaml:"map_id"`
+	X        int32    `yaml:"x"`
+	Y        int32    `yaml:"y"`
+	Heading  int16    `yaml:"heading"`
+	Interval string   `yaml:"interval"` // Go duration, e.g. "6h", "90m"
+	Times    []string `yaml:"times"`    // daily "HH:MM" list
+	Announce string   `yaml:"announce"`
+}
+
+type bossFile struct {
+	Bosses []bossEntry `yaml:"bosses"`
+}
+
+// LoadBossSchedule loads scheduled world boss spawns from a YAML file.
+// A missing file is not an error (no scheduled bosses).
+func LoadBossSchedule(path string) ([]BossSpawn, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read boss_schedule: %w", err)
+	}
+	var f bossFile
+	if err := yaml.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("parse boss_schedule: %w", err)
+	}
+
+	result := make([]BossSpawn, 0, len(f.Bosses))
+	for i, e := range f.Bosses {
+		b := BossSpawn{
+			NpcID:    e.NpcID,
+			MapID:    e.MapID,
+			X:        e.X,
+			Y:        e.Y,
+			Heading:  e.Heading,
+			Announce: e.Announce,
+		}
+		for _, s := range e.Times {
+			t, err := time.Parse("15:04", s)
+			if err != nil {
+				return nil, fmt.Errorf("boss_schedule[%d] npc %d: invalid time %q", i, e.NpcID, s)
+			}
+			b.Times = append(b.Times, time.Duration(t.Hour())*time.Hour+time.Duration(t.Minute())*time.Minute)
+		}
+		sort.Slice(b.Times, func(a, c int) bool { return b.Times[a] < b.Times[c] })
+		if e.Interval != "" {
+			d, err := time.ParseDuration(e.Interval)
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("boss_schedule[%d] npc %d: invalid interval %q", i, e.NpcID, e.Interval)
+			}
+			b.Interval = d
+		}
+		if len(b.Times) == 0 && b.Interval == 0 {
+			return nil, fmt.Errorf("boss_schedule[%d] npc %d: needs interval or times", i, e.NpcID)
+		}
+		result = append(result, b)
+	}
+	return result, nil
+}
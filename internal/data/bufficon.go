@@ -11,7 +11,7 @@ import (
 type BuffIconInfo struct {
 	SkillID int32
 	Type    string // "shield", "strup", "dexup", "aura", "invis", "wisdom", "blue_potion"
-	Param   byte   // icon sub-type (used by shield/strup/dexup)
+	Param   byte   // icon sub-type (shield/strup/dexup) or client aura icon ID (aura)
 }
 
 // BuffIconTable maps skill ID to icon display info.
@@ -55,6 +55,12 @@ func LoadBuffIconTable(path string) (*BuffIconTable, error) {
 		icons: make(map[int32]*BuffIconInfo, len(f.Icons)),
 	}
 	for _, e := range f.Icons {
+		if e.Param < 0 || e.Param > 255 {
+			return nil, fmt.Errorf("buff icon skill %d: param %d out of range", e.SkillID, e.Param)
+		}
+		if e.Type == "aura" && e.Param == 0 {
+			return nil, fmt.Errorf("buff icon skill %d: aura needs param (client aura icon ID)", e.SkillID)
+		}
 		t.icons[e.SkillID] = &BuffIconInfo{
 			SkillID: e.SkillID,
 			Type:    e.Type,
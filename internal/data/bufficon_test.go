@@ -0,0 +1,24 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadBuffIconTableRejectsBadAura(t *testing.T) {
+	cases := map[string]string{
+		"aura without param": "buff_icons:\n  - {skill_id: 114, type: aura}\n",
+		"param out of range": "buff_icons:\n  - {skill_id: 114, type: aura, param: 300}\n",
+	}
+	for name, body := range cases {
+		path := filepath.Join(t.TempDir(), "buff_icon_map.yaml")
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadBuffIconTable(path); err == nil || !strings.Contains(err.Error(), "skill 114") {
+			t.Errorf("%s: err = %v", name, err)
+		}
+	}
+}
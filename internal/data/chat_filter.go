@@ -0,0 +1,119 @@
+package data
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Chat filter modes.
+const (
+	ChatFilterReplace = "replace" // mask banned words with the replacement rune
+	ChatFilterBlock   = "block"   // reject the whole message
+)
+
+// ChatFilterTable is a banned-word list applied to player chat.
+// Matching is case-insensitive substring matching.
+type ChatFilterTable struct {
+	mode        string
+	replacement rune
+	words       [][]rune // lower-cased
+}
+
+// Filter applies the banned-word list to text. It returns the (possibly
+// masked) text and false when the message must be blocked.
+func (t *ChatFilterTable) Filter(text string) (string, bool) {
+	if t == nil || len(t.words) == 0 {
+		return text, true
+	}
+	src := []rune(text)
+	lower := make([]rune, len(src))
+	for i, r := range src {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	matched := false
+	for _, w := range t.words {
+		for i := 0; i+len(w) <= len(lower); i++ {
+			if !runesHasPrefix(lower[i:], w) {
+				continue
+			}
+			if t.mode == ChatFilterBlock {
+				return text, false
+			}
+			for j := i; j < i+len(w); j++ {
+				src[j] = t.replacement
+			}
+			matched = true
+			i += len(w) - 1
+		}
+	}
+	if !matched {
+		return text, true
+	}
+	return string(src), true
+}
+
+// Count returns the number of banned words.
+func (t *ChatFilterTable) Count() int {
+	if t == nil {
+		return 0
+	}
+	return len(t.words)
+}
+
+func runesHasPrefix(s, prefix []rune) bool {
+	for i, r := range prefix {
+		if s[i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// --- YAML loading ---
+
+type chatFilterFile struct {
+	Mode        string   `yaml:"mode"`
+	Replacement string   `yaml:"replacement"`
+	Words       []string `yaml:"words"`
+}
+
+// LoadChatFilter loads the chat banned-word list from YAML.
+// A missing file is not an error (no filtering).
+func LoadChatFilter(path string) (*ChatFilterTable, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read chat filter: %w", err)
+	}
+	var f chatFilterFile
+	if err := yaml.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("parse chat filter: %w", err)
+	}
+
+	t := &ChatFilterTable{mode: f.Mode, replacement: '*'}
+	switch f.Mode {
+	case "":
+		t.mode = ChatFilterReplace
+	case ChatFilterReplace, ChatFilterBlock:
+	default:
+		return nil, fmt.Errorf("chat filter: invalid mode %q", f.Mode)
+	}
+	if r := []rune(f.Replacement); len(r) > 0 {
+		t.replacement = r[0]
+	}
+	for _, w := range f.Words {
+		w = strings.TrimSpace(w)
+		if w == "" {
+			continue
+		}
+		t.words = append(t.words, []rune(strings.ToLower(w)))
+	}
+	return t, nil
+}
@@ -21,13 +21,48 @@ type mobDropEntry struct {
 	Items []DropItem `yaml:"items"`
 }
 
+// GlobalDrop is a drop rolled for every NPC kill in addition to the mob's
+// own list (event coins and the like). It uses its own chance, which the
+// server drop rate does not scale, and can be scoped to maps and a mob
+// level range.
+type GlobalDrop struct {
+	DropItem `yaml:",inline"`
+	MapIDs   []int16 `yaml:"map_ids"`   // empty = every map
+	MinLevel int16   `yaml:"min_level"` // 0 = no lower bound
+	MaxLevel int16   `yaml:"max_level"` // 0 = no upper bound
+}
+
+// Applies reports whether the drop is rolled for a mob of the given level on mapID.
+func (g *GlobalDrop) Applies(mapID, level int16) bool {
+	if g.MinLevel > 0 && level < g.MinLevel {
+		return false
+	}
+	if g.MaxLevel > 0 && level > g.MaxLevel {
+		return false
+	}
+	if len(g.MapIDs) == 0 {
+		return true
+	}
+	for _, id := range g.MapIDs {
+		if id == mapID {
+			return true
+		}
+	}
+	return false
+}
+
 type dropListFile struct {
 	Drops []mobDropEntry `yaml:"drops"`
 }
 
+type globalDropFile struct {
+	GlobalDrops []GlobalDrop `yaml:"global_drops"`
+}
+
 // DropTable holds all mob drop data indexed by mob template ID.
 type DropTable struct {
-	drops map[int32][]DropItem
+	drops  map[int32][]DropItem
+	global []GlobalDrop
 }
 
 // Get returns the drop list for a mob, or nil if none defined.
@@ -35,6 +70,11 @@ func (t *DropTable) Get(mobID int32) []DropItem {
 	return t.drops[mobID]
 }
 
+// Global returns the drops rolled for every NPC kill.
+func (t *DropTable) Global() []GlobalDrop {
+	return t.global
+}
+
 // Count returns the number of mobs with drop entries.
 func (t *DropTable) Count() int {
 	return len(t.drops)
@@ -42,7 +82,7 @@ func (t *DropTable) Count() int {
 
 // LoadDropTable loads mob drop data from a YAML file.
 func LoadDropTable(path string) (*DropTable, error) {
-	raw, err := os.ReadFile(path)
+	raw, err := readFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read drop_list: %w", err)
 	}
@@ -56,3 +96,32 @@ func LoadDropTable(path string) (*DropTable, error) {
 	}
 	return t, nil
 }
+
+// LoadGlobalDrops loads the global_drops section from a YAML file into the
+// table. A missing file is not an error (no global drops).
+func (t *DropTable) LoadGlobalDrops(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("read global_drops: %w", err)
+	}
+	var f globalDropFile
+	if err := yaml.Unmarshal(raw, &f); err != nil {
+		return fmt.Errorf("parse global_drops: %w", err)
+	}
+	for i, g := range f.GlobalDrops {
+		if g.ItemID <= 0 || g.Chance <= 0 {
+			return fmt.Errorf("global_drops[%d]: item_id and chance are required", i)
+		}
+		if g.Min > g.Max && g.Max > 0 {
+			return fmt.Errorf("global_drops[%d]: min above max", i)
+		}
+		if g.MaxLevel > 0 && g.MaxLevel < g.MinLevel {
+			return fmt.Errorf("global_drops[%d]: max_level below min_level", i)
+		}
+	}
+	t.global = f.GlobalDrops
+	return nil
+}
@@ -0,0 +1,81 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestGlobalDrops(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "global_drops.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadGlobalDrops(t *testing.T) {
+	path := writeTestGlobalDrops(t, `global_drops:
+  - {item_id: 41159, min: 1, max: 3, chance: 10000, map_ids: [0, 4], min_level: 20}
+  - {item_id: 40308, min: 100, max: 100, chance: 500000, max_level: 10}
+`)
+	drops := &DropTable{}
+	if err := drops.LoadGlobalDrops(path); err != nil {
+		t.Fatalf("LoadGlobalDrops: %v", err)
+	}
+	g := drops.Global()
+	if len(g) != 2 {
+		t.Fatalf("loaded %d global drops, want 2", len(g))
+	}
+	if g[0].ItemID != 41159 || g[0].Min != 1 || g[0].Max != 3 || g[0].Chance != 10000 {
+		t.Errorf("inline drop fields not parsed: %+v", g[0].DropItem)
+	}
+
+	tests := []struct {
+		drop  int
+		mapID int16
+		level int16
+		want  bool
+	}{
+		{0, 4, 20, true},
+		{0, 4, 19, false},
+		{0, 5, 50, false},
+		{0, 0, 99, true},
+		{1, 123, 1, true},
+		{1, 123, 10, true},
+		{1, 123, 11, false},
+	}
+	for _, tt := range tests {
+		if got := g[tt.drop].Applies(tt.mapID, tt.level); got != tt.want {
+			t.Errorf("drop %d Applies(map %d, level %d) = %v, want %v", tt.drop, tt.mapID, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestLoadGlobalDropsRejectsBadEntries(t *testing.T) {
+	for name, entry := range map[string]string{
+		"missing item":  "{chance: 100}",
+		"missing odds":  "{item_id: 40308}",
+		"min above max": "{item_id: 40308, chance: 100, min: 5, max: 2}",
+		"level range":   "{item_id: 40308, chance: 100, min_level: 30, max_level: 20}",
+	} {
+		path := writeTestGlobalDrops(t, "global_drops:\n  - "+entry+"\n")
+		if err := (&DropTable{}).LoadGlobalDrops(path); err == nil {
+			t.Errorf("%s: accepted %s", name, entry)
+		}
+	}
+}
+
+func TestGlobalDropsDefaultEmpty(t *testing.T) {
+	drops := &DropTable{}
+	if err := drops.LoadGlobalDrops(filepath.Join(t.TempDir(), "missing.yaml")); err != nil {
+		t.Fatalf("missing file: %v", err)
+	}
+	if err := drops.LoadGlobalDrops(filepath.Join("..", "..", "data", "yaml", "global_drops.yaml")); err != nil {
+		t.Fatalf("shipped global_drops.yaml: %v", err)
+	}
+	if n := len(drops.Global()); n != 0 {
+		t.Errorf("shipped global_drops.yaml has %d entries, want none", n)
+	}
+}
@@ -0,0 +1,184 @@
+package data
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// townSearchMap is the mainland map where home scrolls go to the nearest town.
+const townSearchMap int16 = 4
+
+// defaultGetback is Silver Knight Village, used when the table has no entry
+// for a map (or the table failed to load).
+var defaultGetback = GetbackLoc{X: 33084, Y: 33391, MapID: 4}
+
+// GetbackLoc is a return destination.
+type GetbackLoc struct {
+	X, Y  int32
+	MapID int16
+}
+
+// getbackEntry holds the destinations configured for one source map.
+type getbackEntry struct {
+	restart GetbackLoc
+	home    *GetbackLoc
+	lawful  *GetbackLoc
+	chaotic *GetbackLoc
+}
+
+// GetbackTable maps a player's current map to its restart / home scroll
+// destination (Java: GetbackTable + L1TownLocation).
+type GetbackTable struct {
+	def     GetbackLoc
+	towns   []GetbackLoc
+	entries map[int16]*getbackEntry
+}
+
+// Restart returns the death-restart destination for mapID.
+// lawful selects the lawful/chaotic variant when one is configured.
+func (t *GetbackTable) Restart(mapID int16, lawful int32) GetbackLoc {
+	if t == nil {
+		return defaultGetback
+	}
+	e := t.entries[mapID]
+	if e == nil {
+		return t.def
+	}
+	if v := e.variant(lawful); v != nil {
+		return *v
+	}
+	return e.restart
+}
+
+// Home returns the home scroll destination. On the mainland the nearest town
+// to (x, y) is chosen; elsewhere the map entry (home override if set) is used.
+func (t *GetbackTable) Home(mapID int16, x, y int32, lawful int32) GetbackLoc {
+	if t == nil {
+		return defaultGetback
+	}
+	e := t.entries[mapID]
+	if e != nil {
+		if v := e.variant(lawful); v != nil {
+			return *v
+		}
+	}
+	if mapID == townSearchMap && len(t.towns) > 0 {
+		return t.nearestTown(x, y)
+	}
+	if e == nil {
+		return t.def
+	}
+	if e.home != nil {
+		return *e.home
+	}
+	return e.restart
+}
+
+// Count returns the number of configured maps.
+func (t *GetbackTable) Count() int {
+	if t == nil {
+		return 0
+	}
+	return len(t.entries)
+}
+
+func (e *getbackEntry) variant(lawful int32) *GetbackLoc {
+	if lawful < 0 {
+		return e.chaotic
+	}
+	return e.lawful
+}
+
+func (t *GetbackTable) nearestTown(x, y int32) GetbackLoc {
+	best := t.towns[0]
+	bestDist := int64(-1)
+	for _, town := range t.towns {
+		dx := int64(x - town.X)
+		dy := int64(y - town.Y)
+		if d := dx*dx + dy*dy; bestDist < 0 || d < bestDist {
+			bestDist = d
+			best = town
+		}
+	}
+	return best
+}
+
+// --- YAML loading ---
+
+type getbackLocEntry struct {
+	X   int32 `yaml:"x"`
+	Y   int32 `yaml:"y"`
+	Map int16 `yaml:"map"`
+}
+
+type getbackMapEntry struct {
+	MapID   int16            `yaml:"map_id"`
+	X       int32            `yaml:"x"`
+	Y       int32            `yaml:"y"`
+	Map     int16            `yaml:"map"`
+	Home    *getbackLocEntry `yaml:"home"`
+	Lawful  *getbackLocEntry `yaml:"lawful"`
+	Chaotic *getbackLocEntry `yaml:"chaotic"`
+}
+
+type getbackTownEntry struct {
+	Name string `yaml:"name"`
+	X    int32  `yaml:"x"`
+	Y    int32  `yaml:"y"`
+}
+
+type getbackFile struct {
+	Default *getbackLocEntry   `yaml:"default"`
+	Towns   []getbackTownEntry `yaml:"towns"`
+	Maps    []getbackMapEntry  `yaml:"maps"`
+}
+
+// LoadGetbackTable loads return locations from YAML.
+// A missing file is not an error (every lookup returns the default).
+func LoadGetbackTable(path string) (*GetbackTable, error) {
+	t := &GetbackTable{def: defaultGetback, entries: make(map[int16]*getbackEntry)}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return t, nil
+		}
+		return nil, fmt.Errorf("read getback list: %w", err)
+	}
+	var f getbackFile
+	if err := yaml.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("parse getback list: %w", err)
+	}
+
+	if f.Default != nil {
+		t.def = f.Default.loc()
+	}
+	for _, town := range f.Towns {
+		t.towns = append(t.towns, GetbackLoc{X: town.X, Y: town.Y, MapID: townSearchMap})
+	}
+	for _, m := range f.Maps {
+		if _, dup := t.entries[m.MapID]; dup {
+			return nil, fmt.Errorf("getback list: duplicate map_id %d", m.MapID)
+		}
+		t.entries[m.MapID] = &getbackEntry{
+			restart: GetbackLoc{X: m.X, Y: m.Y, MapID: m.Map},
+			home:    m.Home.ptr(),
+			lawful:  m.Lawful.ptr(),
+			chaotic: m.Chaotic.ptr(),
+		}
+	}
+	return t, nil
+}
+
+func (e *getbackLocEntry) loc() GetbackLoc {
+	return GetbackLoc{X: e.X, Y: e.Y, MapID: e.Map}
+}
+
+func (e *getbackLocEntry) ptr() *GetbackLoc {
+	if e == nil {
+		return nil
+	}
+	l := e.loc()
+	return &l
+}
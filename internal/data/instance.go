@@ -0,0 +1,70 @@
+package data
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// InstanceMap gates portal entry into one map (boss rooms, key dungeons).
+type InstanceMap struct {
+	MapID      int16  `yaml:"map_id"`
+	MaxPlayers int    `yaml:"max_players"` // 0 = unlimited
+	OneParty   bool   `yaml:"one_party"`   // only one party (or one solo player) inside at a time
+	KeyItemID  int32  `yaml:"key_item_id"` // required item (0 = none)
+	ConsumeKey bool   `yaml:"consume_key"` // remove one key on entry
+	Note       string `yaml:"note"`
+}
+
+// InstanceTable maps destination map IDs to their entry limits.
+type InstanceTable struct {
+	maps map[int16]*InstanceMap
+}
+
+// LoadInstanceTable loads instance_maps.yaml.
+// A missing file is not an error (no map is gated).
+func LoadInstanceTable(path string) (*InstanceTable, error) {
+	t := &InstanceTable{maps: make(map[int16]*InstanceMap)}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return t, nil
+		}
+		return nil, fmt.Errorf("read instance maps: %w", err)
+	}
+	var entries []InstanceMap
+	if err := yaml.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("parse instance maps: %w", err)
+	}
+	for i := range entries {
+		e := &entries[i]
+		if _, dup := t.maps[e.MapID]; dup {
+			return nil, fmt.Errorf("instance maps: duplicate map_id %d", e.MapID)
+		}
+		if e.MaxPlayers < 0 {
+			return nil, fmt.Errorf("instance maps: map %d max_players must be >= 0", e.MapID)
+		}
+		if e.ConsumeKey && e.KeyItemID == 0 {
+			return nil, fmt.Errorf("instance maps: map %d consume_key without key_item_id", e.MapID)
+		}
+		t.maps[e.MapID] = e
+	}
+	return t, nil
+}
+
+// Get returns the entry limits for mapID, or nil if the map is not gated.
+func (t *InstanceTable) Get(mapID int16) *InstanceMap {
+	if t == nil {
+		return nil
+	}
+	return t.maps[mapID]
+}
+
+// Count returns the number of gated maps.
+func (t *InstanceTable) Count() int {
+	if t == nil {
+		return 0
+	}
+	return len(t.maps)
+}
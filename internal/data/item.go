@@ -2,7 +2,6 @@ package data
 
 import (
 	"fmt"
-	"os"
 
 	"gopkg.in/yaml.v3"
 )
@@ -108,6 +107,13 @@ func MaterialToID(s string) byte {
 	return 0
 }
 
+// Item rarity grades (yaml "grade"). Unranked items default to GradeNormal.
+const (
+	GradeNormal = 0
+	GradeRare   = 1
+	GradeLegend = 2
+)
+
 // ItemInfo holds item template data needed for game logic.
 // Flat struct — fields that don't apply to a category are zero-valued.
 type ItemInfo struct {
@@ -151,7 +157,9 @@ type ItemInfo struct {
 	// Meta
 	SafeEnchant int
 	Bless       int
+	Grade       int // drop rarity shown as ground-item name color (GradeNormal..GradeLegend)
 	Tradeable   bool
+	CantDelete  bool // 不可銷毀（C_DESTROY_ITEM 拒絕）
 	MinLevel    int
 	MaxLevel    int
 
@@ -186,6 +194,23 @@ type ItemInfo struct {
 	LocX     int32
 	LocY     int32
 	LocMapID int16
+
+	// Attack enhancement consumable (etcitem only): while toggled on, one is
+	// consumed per attack and a landed hit deals AttackBoost extra damage.
+	// Data-only: no shipped etcitem sets attack_boost; add it to an item in
+	// etcitem_list.yaml to enable the feature.
+	AttackBoost    int32
+	AttackBoostGfx int32 // effect on the target of a boosted hit (0 = none)
+
+	// Random teleport radius in tiles for teleport scrolls used without a
+	// bookmark (0 = [gameplay] teleport_scroll_radius).
+	TeleportRadius int32
+
+	// Targeted consumable (etcitem only): using the item applies skill UseSkill
+	// to the target object sent with C_USE_ITEM (self when no target is sent).
+	// UseRange is the max target distance in tiles (0 = the skill's range).
+	UseSkill int32
+	UseRange int32
 }
 
 // ItemTable holds all item templates indexed by ItemID.
@@ -220,6 +245,17 @@ func LoadItemTable(weaponPath, armorPath, etcitemPath string) (*ItemTable, error
 	return t, nil
 }
 
+// clampGrade limits a yaml grade to the known range (out-of-range = nearest grade).
+func clampGrade(g int) int {
+	if g < GradeNormal {
+		return GradeNormal
+	}
+	if g > GradeLegend {
+		return GradeLegend
+	}
+	return g
+}
+
 // --- weapon loading ---
 
 type weaponEntry struct {
@@ -257,7 +293,9 @@ type weaponEntry struct {
 	AddSP           int    `yaml:"add_sp"`
 	MDef            int    `yaml:"m_def"`
 	Bless           int    `yaml:"bless"`
+	Grade           int    `yaml:"grade,omitempty"`
 	Tradeable       bool   `yaml:"tradeable"`
+	CantDelete      bool   `yaml:"cant_delete"`
 	MinLevel        int    `yaml:"min_level"`
 	MaxLevel        int    `yaml:"max_level"`
 }
@@ -267,7 +305,7 @@ type weaponListFile struct {
 }
 
 func loadWeapons(t *ItemTable, path string) error {
-	raw, err := os.ReadFile(path)
+	raw, err := readFile(path)
 	if err != nil {
 		return fmt.Errorf("read weapons: %w", err)
 	}
@@ -295,7 +333,9 @@ func loadWeapons(t *ItemTable, path string) error {
 			DmgMod:          w.DmgModifier,
 			SafeEnchant:     w.SafeEnchant,
 			Bless:           w.Bless,
+			Grade:           clampGrade(w.Grade),
 			Tradeable:       w.Tradeable,
+			CantDelete:      w.CantDelete,
 			MinLevel:        w.MinLevel,
 			MaxLevel:        w.MaxLevel,
 			UseRoyal:        w.UseRoyal,
@@ -359,7 +399,9 @@ type armorEntry struct {
 	BowHitModifier  int    `yaml:"bow_hit_modifier"`
 	BowDmgModifier  int    `yaml:"bow_dmg_modifier"`
 	Bless           int    `yaml:"bless"`
+	Grade           int    `yaml:"grade,omitempty"`
 	Tradeable       bool   `yaml:"tradeable"`
+	CantDelete      bool   `yaml:"cant_delete"`
 	MinLevel        int    `yaml:"min_level"`
 	MaxLevel        int    `yaml:"max_level"`
 }
@@ -369,7 +411,7 @@ type armorListFile struct {
 }
 
 func loadArmors(t *ItemTable, path string) error {
-	raw, err := os.ReadFile(path)
+	raw, err := readFile(path)
 	if err != nil {
 		return fmt.Errorf("read armors: %w", err)
 	}
@@ -397,7 +439,9 @@ func loadArmors(t *ItemTable, path string) error {
 			BowDmgMod:       a.BowDmgModifier,
 			SafeEnchant:     a.SafeEnchant,
 			Bless:           a.Bless,
+			Grade:           clampGrade(a.Grade),
 			Tradeable:       a.Tradeable,
+			CantDelete:      a.CantDelete,
 			MinLevel:        a.MinLevel,
 			MaxLevel:        a.MaxLevel,
 			UseRoyal:        a.UseRoyal,
@@ -445,8 +489,15 @@ type etcItemEntry struct {
 	LocX           int32  `yaml:"loc_x"`
 	LocY           int32  `yaml:"loc_y"`
 	MapID          int16  `yaml:"map_id"`
+	TeleportRadius int32  `yaml:"teleport_radius,omitempty"`
+	UseSkill       int32  `yaml:"use_skill,omitempty"`
+	UseRange       int32  `yaml:"use_range,omitempty"`
+	AttackBoost    int32  `yaml:"attack_boost,omitempty"`
+	AttackBoostGfx int32  `yaml:"attack_boost_gfx,omitempty"`
 	Bless          int    `yaml:"bless"`
+	Grade          int    `yaml:"grade,omitempty"`
 	Tradeable      bool   `yaml:"tradeable"`
+	CantDelete     bool   `yaml:"cant_delete"`
 	DelayID        int    `yaml:"delay_id"`
 	DelayTime      int    `yaml:"delay_time"`
 	FoodVolume     int    `yaml:"food_volume"`
@@ -457,7 +508,7 @@ type etcItemListFile struct {
 }
 
 func loadEtcItems(t *ItemTable, path string) error {
-	raw, err := os.ReadFile(path)
+	raw, err := readFile(path)
 	if err != nil {
 		return fmt.Errorf("read etcitems: %w", err)
 	}
@@ -485,7 +536,9 @@ func loadEtcItems(t *ItemTable, path string) error {
 			Stackable:      e.Stackable,
 			MaxChargeCount: e.MaxChargeCount,
 			Bless:          e.Bless,
+			Grade:          clampGrade(e.Grade),
 			Tradeable:      e.Tradeable,
+			CantDelete:     e.CantDelete,
 			MinLevel:       e.MinLevel,
 			MaxLevel:       e.MaxLevel,
 			FoodVolume:     e.FoodVolume,
@@ -494,6 +547,11 @@ func loadEtcItems(t *ItemTable, path string) error {
 			LocX:           e.LocX,
 			LocY:           e.LocY,
 			LocMapID:       e.MapID,
+			TeleportRadius: e.TeleportRadius,
+			UseSkill:       e.UseSkill,
+			UseRange:       e.UseRange,
+			AttackBoost:    e.AttackBoost,
+			AttackBoostGfx: e.AttackBoostGfx,
 		}
 	}
 	return nil
@@ -2,7 +2,9 @@ package data
 
 import (
 	"bufio"
+	"encoding/binary"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -32,14 +34,19 @@ type MapInfo struct {
 	RecallPets    bool    `yaml:"recall_pets"`
 	UsableItem    bool    `yaml:"usable_item"`
 	UsableSkill   bool    `yaml:"usable_skill"`
+
+	// ExpMultiplier scales kill EXP on this map, on top of [rates] exp_rate and
+	// rate events (nil = 1.0, 0 = no kill EXP).
+	ExpMultiplier *float64 `yaml:"exp_multiplier,omitempty"`
 }
 
 // mapEntry stores loaded tile data + metadata for one map.
 type mapEntry struct {
 	info   MapInfo
-	tiles  []byte // flat array [x * height + y], row-major by X
-	width  int32
-	height int32
+	tiles    []byte // flat array [x * height + y], row-major by X; nil until needed for fallback maps
+	width    int32
+	height   int32
+	fallback bool // no terrain file: every tile in bounds is passable
 }
 
 // MapDataTable provides map tile data and metadata lookups.
@@ -88,24 +95,105 @@ func LoadMapData(yamlPath, tileDir string) (*MapDataTable, error) {
 			continue
 		}
 
-		tiles, err := loadTileFile(tileDir, int(info.MapID), int(width), int(height))
-		if err != nil {
-			// Map file missing is non-fatal — log and skip
-			continue
-		}
-
 		entry := &mapEntry{
 			info:   info,
-			tiles:  tiles,
 			width:  width,
 			height: height,
 		}
+		tiles, err := loadTileFile(tileDir, int(info.MapID), int(width), int(height))
+		if err != nil {
+			// Map file missing is non-fatal — treat the whole map as passable
+			// within its bounds until LoadMapTiles supplies real terrain. The
+			// tile array is only allocated once something blocks a tile.
+			tiles = nil
+			entry.fallback = true
+		}
+		entry.tiles = tiles
 		table.maps[info.MapID] = entry
 	}
 
 	return table, nil
 }
 
+// fallbackTile is the terrain of a map without a terrain file: passable, normal zone.
+const fallbackTile = tilePassableEast | tilePassableNorth
+
+// passableTiles builds fallback terrain: every tile passable, normal zone.
+func passableTiles(xSize, ySize int) []byte {
+	tiles := make([]byte, xSize*ySize)
+	for i := range tiles {
+		tiles[i] = fallbackTile
+	}
+	return tiles
+}
+
+// LoadMapTiles reads binary passability bitmaps ({mapid}.map) from dir into
+// already-loaded maps, replacing text or fallback terrain. Maps without a
+// binary file keep their current tiles. Returns the number of maps loaded.
+// A missing directory is not an error.
+//
+// File layout (L1J CachedMapReader, big-endian):
+//
+//	int32 mapID, int32 startX, int32 startY, int32 width, int32 height
+//	width × (int32 length, length bytes) — one column of tiles per X
+func (t *MapDataTable) LoadMapTiles(dir string) (int, error) {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return 0, nil
+	}
+	loaded := 0
+	for mapID, e := range t.maps {
+		path := filepath.Join(dir, strconv.Itoa(int(mapID))+".map")
+		f, err := os.Open(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return loaded, fmt.Errorf("open map tiles %s: %w", path, err)
+		}
+		tiles, err := readBinaryTiles(bufio.NewReader(f), mapID, e)
+		f.Close()
+		if err != nil {
+			return loaded, fmt.Errorf("map tiles %s: %w", path, err)
+		}
+		e.tiles = tiles
+		e.fallback = false
+		loaded++
+	}
+	return loaded, nil
+}
+
+// readBinaryTiles decodes one binary map file and checks it against the
+// map_list.yaml bounds.
+func readBinaryTiles(r io.Reader, mapID int16, e *mapEntry) ([]byte, error) {
+	var hdr [5]int32
+	if err := binary.Read(r, binary.BigEndian, &hdr); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if int16(hdr[0]) != mapID {
+		return nil, fmt.Errorf("header map id %d, want %d", hdr[0], mapID)
+	}
+	if hdr[1] != e.info.StartX || hdr[2] != e.info.StartY || hdr[3] != e.width || hdr[4] != e.height {
+		return nil, fmt.Errorf("bounds (%d,%d %dx%d) do not match map list (%d,%d %dx%d)",
+			hdr[1], hdr[2], hdr[3], hdr[4], e.info.StartX, e.info.StartY, e.width, e.height)
+	}
+
+	tiles := make([]byte, int(e.width)*int(e.height))
+	for x := 0; x < int(e.width); x++ {
+		var n int32
+		if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+			return nil, fmt.Errorf("column %d: %w", x, err)
+		}
+		if n != e.height {
+			return nil, fmt.Errorf("column %d length %d, want %d", x, n, e.height)
+		}
+		col := tiles[x*int(e.height) : (x+1)*int(e.height)]
+		if _, err := io.ReadFull(r, col); err != nil {
+			return nil, fmt.Errorf("column %d: %w", x, err)
+		}
+	}
+	return tiles, nil
+}
+
 // loadTileFile reads a CSV tile file: each line is a row of comma-separated byte values.
 // Layout matches Java TextMapReader: map[x][y], file rows = Y lines, columns = X values.
 func loadTileFile(dir string, mapID, xSize, ySize int) ([]byte, error) {
@@ -153,6 +241,18 @@ func (t *MapDataTable) Count() int {
 	return len(t.maps)
 }
 
+// FallbackCount returns the number of maps without a terrain file, which are
+// treated as passable everywhere within their bounds.
+func (t *MapDataTable) FallbackCount() int {
+	n := 0
+	for _, e := range t.maps {
+		if e.fallback {
+			n++
+		}
+	}
+	return n
+}
+
 // GetInfo returns metadata for a map, or nil if not found.
 func (t *MapDataTable) GetInfo(mapID int16) *MapInfo {
 	e := t.maps[mapID]
@@ -162,6 +262,26 @@ func (t *MapDataTable) GetInfo(mapID int16) *MapInfo {
 	return &e.info
 }
 
+// ExpMultiplier returns the kill EXP multiplier for a map (1.0 when the map is
+// unknown or has no exp_multiplier; negative values count as 0).
+func (t *MapDataTable) ExpMultiplier(mapID int16) float64 {
+	e := t.maps[mapID]
+	if e == nil || e.info.ExpMultiplier == nil {
+		return 1.0
+	}
+	if m := *e.info.ExpMultiplier; m > 0 {
+		return m
+	}
+	return 0
+}
+
+// DeathPenalty reports whether dying on a map costs EXP and PvE item drops
+// (map_list penalty; unknown maps default to true). Java: L1Map.isEnabledDeathPenalty.
+func (t *MapDataTable) DeathPenalty(mapID int16) bool {
+	e := t.maps[mapID]
+	return e == nil || e.info.Penalty
+}
+
 // accessTile returns the tile byte at world coordinates, or 0 if out of bounds.
 func (t *MapDataTable) accessTile(mapID int16, x, y int32) byte {
 	e := t.maps[mapID]
@@ -173,6 +293,9 @@ func (t *MapDataTable) accessTile(mapID int16, x, y int32) byte {
 	if lx < 0 || lx >= e.width || ly < 0 || ly >= e.height {
 		return 0
 	}
+	if e.tiles == nil {
+		return fallbackTile
+	}
 	return e.tiles[int(lx)*int(e.height)+int(ly)]
 }
 
@@ -330,6 +453,12 @@ func (t *MapDataTable) SetImpassable(mapID int16, x, y int32, blocked bool) {
 	if lx < 0 || lx >= e.width || ly < 0 || ly >= e.height {
 		return
 	}
+	if e.tiles == nil {
+		if !blocked {
+			return
+		}
+		e.tiles = passableTiles(int(e.width), int(e.height))
+	}
 	idx := int(lx)*int(e.height) + int(ly)
 	if blocked {
 		e.tiles[idx] |= tileImpassable
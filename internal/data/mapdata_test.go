@@ -0,0 +1,88 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestMapList(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "map_list.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDeathPenaltyFollowsMapFlag(t *testing.T) {
+	path := writeTestMapList(t, `maps:
+  - {map_id: 4, start_x: 0, end_x: 9, start_y: 0, end_y: 9, penalty: true}
+  - {map_id: 303, start_x: 0, end_x: 9, start_y: 0, end_y: 9, penalty: false}
+`)
+	maps, err := LoadMapData(path, t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadMapData: %v", err)
+	}
+	if !maps.DeathPenalty(4) {
+		t.Error("map 4 (penalty: true) reported penalty-free")
+	}
+	if maps.DeathPenalty(303) {
+		t.Error("map 303 (penalty: false) still applies the death penalty")
+	}
+	if !maps.DeathPenalty(12345) {
+		t.Error("unknown map should default to the death penalty")
+	}
+}
+
+func TestExpMultiplierDefaultsToOne(t *testing.T) {
+	path := writeTestMapList(t, `maps:
+  - {map_id: 4, start_x: 0, end_x: 9, start_y: 0, end_y: 9}
+  - {map_id: 7, start_x: 0, end_x: 9, start_y: 0, end_y: 9, exp_multiplier: 1.5}
+  - {map_id: 8, start_x: 0, end_x: 9, start_y: 0, end_y: 9, exp_multiplier: 0}
+`)
+	maps, err := LoadMapData(path, t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadMapData: %v", err)
+	}
+	for mapID, want := range map[int16]float64{4: 1, 7: 1.5, 8: 0, 999: 1} {
+		if got := maps.ExpMultiplier(mapID); got != want {
+			t.Errorf("ExpMultiplier(%d) = %v, want %v", mapID, got, want)
+		}
+	}
+}
+
+func TestFallbackTerrainAllocatedLazily(t *testing.T) {
+	path := writeTestMapList(t, `maps:
+  - {map_id: 4, start_x: 100, end_x: 109, start_y: 200, end_y: 209}
+  - {map_id: 5, start_x: 100, end_x: 109, start_y: 200, end_y: 209}
+`)
+	maps, err := LoadMapData(path, t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadMapData: %v", err)
+	}
+	if maps.FallbackCount() != 2 {
+		t.Fatalf("FallbackCount = %d, want 2", maps.FallbackCount())
+	}
+	if maps.maps[4].tiles != nil || maps.maps[5].tiles != nil {
+		t.Fatal("fallback terrain allocated at load")
+	}
+	if !maps.IsPassable(4, 105, 205, 2) {
+		t.Error("fallback map not passable")
+	}
+
+	maps.SetImpassable(5, 105, 205, false)
+	if maps.maps[5].tiles != nil {
+		t.Error("clearing a tile allocated fallback terrain")
+	}
+	maps.SetImpassable(4, 106, 205, true)
+	if maps.maps[4].tiles == nil {
+		t.Fatal("blocking a tile did not allocate terrain")
+	}
+	if maps.IsPassable(4, 105, 205, 2) {
+		t.Error("blocked tile still passable")
+	}
+	if maps.maps[5].tiles != nil {
+		t.Error("untouched map 5 allocated terrain")
+	}
+}
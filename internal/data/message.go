@@ -0,0 +1,177 @@
+package data
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MessageTable holds server-side copies of the client's S_ServerMessage format
+// strings. The client still renders every message; the table is only used to
+// validate argument counts and to compose readable previews for GM tools and
+// logs.
+//
+// Format strings use the client's tokens: %0..%9 are positional arguments,
+// %s / %o are particle markers the client resolves and are dropped in previews.
+// Arguments of the form "$N" reference the client string table and are
+// resolved through the optional strings section.
+type MessageTable struct {
+	formats map[uint16]string
+	argc    map[uint16]int
+	strs    map[int32]string
+}
+
+// Get returns the format string for a message ID.
+func (t *MessageTable) Get(id uint16) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	f, ok := t.formats[id]
+	return f, ok
+}
+
+// ArgCount returns the number of arguments a message expects, or -1 if the
+// message is not in the table.
+func (t *MessageTable) ArgCount(id uint16) int {
+	if t == nil {
+		return -1
+	}
+	n, ok := t.argc[id]
+	if !ok {
+		return -1
+	}
+	return n
+}
+
+// Validate reports an error if the argument count does not match the format.
+// Unknown message IDs always pass.
+func (t *MessageTable) Validate(id uint16, argc int) error {
+	want := t.ArgCount(id)
+	if want < 0 || want == argc {
+		return nil
+	}
+	return fmt.Errorf("message %d expects %d args, got %d", id, want, argc)
+}
+
+// Preview composes a message the way the client would display it. Unknown
+// IDs fall back to "#id(args...)".
+func (t *MessageTable) Preview(id uint16, args ...string) string {
+	resolved := make([]string, len(args))
+	for i, a := range args {
+		resolved[i] = t.resolveArg(a)
+	}
+	f, ok := t.Get(id)
+	if !ok {
+		return fmt.Sprintf("#%d(%s)", id, strings.Join(resolved, ", "))
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(f); i++ {
+		if f[i] != '%' || i+1 >= len(f) {
+			b.WriteByte(f[i])
+			continue
+		}
+		c := f[i+1]
+		switch {
+		case c >= '0' && c <= '9':
+			if n := int(c - '0'); n < len(resolved) {
+				b.WriteString(resolved[n])
+			}
+			i++
+		case c == 's' || c == 'o':
+			i++
+		default:
+			b.WriteByte(f[i])
+		}
+	}
+	return b.String()
+}
+
+// Count returns the number of loaded message formats.
+func (t *MessageTable) Count() int {
+	if t == nil {
+		return 0
+	}
+	return len(t.formats)
+}
+
+// resolveArg expands a "$N" client string reference when the table knows it.
+func (t *MessageTable) resolveArg(a string) string {
+	if t == nil || len(a) < 2 || a[0] != '$' {
+		return a
+	}
+	n, err := strconv.Atoi(a[1:])
+	if err != nil {
+		return a
+	}
+	if s, ok := t.strs[int32(n)]; ok {
+		return s
+	}
+	return a
+}
+
+// countArgs returns highest %N index + 1 in a format string.
+func countArgs(f string) int {
+	n := 0
+	for i := 0; i+1 < len(f); i++ {
+		if f[i] == '%' && f[i+1] >= '0' && f[i+1] <= '9' {
+			if idx := int(f[i+1]-'0') + 1; idx > n {
+				n = idx
+			}
+			i++
+		}
+	}
+	return n
+}
+
+// --- YAML loading ---
+
+type messageEntry struct {
+	ID   uint16 `yaml:"id"`
+	Text string `yaml:"text"`
+}
+
+type stringEntry struct {
+	ID   int32  `yaml:"id"`
+	Text string `yaml:"text"`
+}
+
+type messageFile struct {
+	Messages []messageEntry `yaml:"messages"`
+	Strings  []stringEntry  `yaml:"strings"`
+}
+
+// LoadMessageTable loads message format strings from YAML.
+// A missing file is not an error (empty table).
+func LoadMessageTable(path string) (*MessageTable, error) {
+	t := &MessageTable{
+		formats: make(map[uint16]string),
+		argc:    make(map[uint16]int),
+		strs:    make(map[int32]string),
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return t, nil
+		}
+		return nil, fmt.Errorf("read messages: %w", err)
+	}
+	var f messageFile
+	if err := yaml.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("parse messages: %w", err)
+	}
+	for _, e := range f.Messages {
+		if _, dup := t.formats[e.ID]; dup {
+			return nil, fmt.Errorf("messages: duplicate id %d", e.ID)
+		}
+		t.formats[e.ID] = e.Text
+		t.argc[e.ID] = countArgs(e.Text)
+	}
+	for _, e := range f.Strings {
+		t.strs[e.ID] = e.Text
+	}
+	return t, nil
+}
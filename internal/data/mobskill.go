@@ -2,7 +2,6 @@ package data
 
 import (
 	"fmt"
-	"os"
 
 	"gopkg.in/yaml.v3"
 )
@@ -48,7 +47,7 @@ func (t *MobSkillTable) Count() int {
 
 // LoadMobSkillTable loads mob skill data from a YAML file.
 func LoadMobSkillTable(path string) (*MobSkillTable, error) {
-	raw, err := os.ReadFile(path)
+	raw, err := readFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read mob_skill_list: %w", err)
 	}
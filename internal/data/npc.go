@@ -12,6 +12,7 @@ type NpcTemplate struct {
 	NpcID        int32  `yaml:"npc_id"`
 	Name         string `yaml:"name"`
 	NameID       string `yaml:"nameid"`
+	Title        string `yaml:"title,omitempty"` // shown under the name (S_PUT_OBJECT title)
 	Impl         string `yaml:"impl"` // L1Monster, L1Merchant, L1Guard, etc.
 	GfxID        int32  `yaml:"gfx_id"`
 	Level        int16  `yaml:"level"`
@@ -30,10 +31,86 @@ type NpcTemplate struct {
 	Ranged       int16  `yaml:"ranged"`
 	AtkSpeed     int16  `yaml:"atk_speed"`
 	PassiveSpeed int16  `yaml:"passive_speed"`
+	RunSpeed     int16  `yaml:"run_speed,omitempty"` // 追擊移動速度（ms；0 = 與走路速度相同）
 	Undead       bool   `yaml:"undead"`
 	Agro         bool   `yaml:"agro"`
 	Tameable     bool   `yaml:"tameable"`
 	PoisonAtk    byte   `yaml:"poison_atk"` // 毒攻擊類型: 0=無, 1=傷害毒, 2=沉默毒, 4=麻痺毒
+	WanderRadius int32  `yaml:"wander_radius,omitempty"` // 閒晃離生成點的最大距離（0 = 使用 [world] wander_radius）
+	Boss         bool   `yaml:"boss,omitempty"`          // 頭目：被擊殺時全服公告並寫入 boss_kills 紀錄
+
+	// 狀態免疫（true = 該類控制技能對此 NPC 無效）
+	ImmuneParalyze bool `yaml:"immune_paralyze,omitempty"` // 麻痺/凍結/暈眩
+	ImmuneSleep    bool `yaml:"immune_sleep,omitempty"`    // 睡眠
+	ImmunePoison   bool `yaml:"immune_poison,omitempty"`   // 中毒
+
+	// 屬性抗性（正值減傷、負值為弱點加傷，交由 magic.lua 計算）
+	FireRes  int16 `yaml:"fire_res,omitempty"`
+	WaterRes int16 `yaml:"water_res,omitempty"`
+	WindRes  int16 `yaml:"wind_res,omitempty"`
+	EarthRes int16 `yaml:"earth_res,omitempty"`
+
+	// 命中特效：近戰/遠程攻擊造成傷害時依機率觸發
+	OnHit []OnHitEffect `yaml:"on_hit,omitempty"`
+
+	// 死亡分裂：被擊殺時在屍體附近生成的子怪物（代數上限見 [gameplay] npc_split_max_depth）
+	SplitInto []SplitEntry `yaml:"split_into,omitempty"`
+}
+
+// maxSplitCount caps the children a single split_into entry may spawn.
+const maxSplitCount = 8
+
+// SplitEntry is one kind of child NPC spawned when the parent dies.
+type SplitEntry struct {
+	NpcID int32 `yaml:"npc_id"`
+	Count int   `yaml:"count"`
+}
+
+// On-hit effect types.
+const (
+	OnHitPoison    = "poison"     // infect with poison (Poison: 1=damage, 2=silence, 4=paralysis)
+	OnHitStatDrain = "stat_drain" // lower Stat by Amount for Duration seconds
+	OnHitMPDrain   = "mp_drain"   // remove Amount MP
+)
+
+// OnHitEffect is a passive effect a monster inflicts when one of its melee or
+// ranged attacks deals damage to a player.
+type OnHitEffect struct {
+	Type     string `yaml:"type"`
+	Chance   int    `yaml:"chance"`             // percent per damaging hit (1-100)
+	Poison   byte   `yaml:"poison,omitempty"`   // poison: 1=damage, 2=silence, 4=paralysis
+	Stat     string `yaml:"stat,omitempty"`     // stat_drain: str, dex, con, int, wis, cha
+	Amount   int16  `yaml:"amount,omitempty"`   // stat_drain points / mp_drain MP
+	Duration int    `yaml:"duration,omitempty"` // stat_drain seconds
+	Gfx      int32  `yaml:"gfx,omitempty"`      // effect played on the player (0 = none)
+}
+
+func (e *OnHitEffect) validate() error {
+	if e.Chance <= 0 || e.Chance > 100 {
+		return fmt.Errorf("chance %d out of range 1-100", e.Chance)
+	}
+	switch e.Type {
+	case OnHitPoison:
+		if e.Poison != 1 && e.Poison != 2 && e.Poison != 4 {
+			return fmt.Errorf("poison %d must be 1, 2 or 4", e.Poison)
+		}
+	case OnHitStatDrain:
+		switch e.Stat {
+		case "str", "dex", "con", "int", "wis", "cha":
+		default:
+			return fmt.Errorf("unknown stat %q", e.Stat)
+		}
+		if e.Amount <= 0 || e.Duration <= 0 {
+			return fmt.Errorf("stat_drain needs positive amount and duration")
+		}
+	case OnHitMPDrain:
+		if e.Amount <= 0 {
+			return fmt.Errorf("mp_drain needs a positive amount")
+		}
+	default:
+		return fmt.Errorf("unknown type %q", e.Type)
+	}
+	return nil
 }
 
 // SpawnEntry defines where and how many NPCs to spawn.
@@ -47,6 +124,29 @@ type SpawnEntry struct {
 	RandomY      int32 `yaml:"randomy"`
 	Heading      int16 `yaml:"heading"`
 	RespawnDelay int   `yaml:"respawn_delay"` // seconds
+	// Area, when set, spreads the Count NPCs over passable tiles in the
+	// rectangle (X/Y and RandomX/RandomY are then ignored). Respawns pick a
+	// new tile in the same rectangle.
+	Area *SpawnArea `yaml:"area,omitempty"`
+	// Patrol, when set, walks the NPC through these waypoints in a loop
+	// (pausing PatrolPause seconds at each) instead of wandering. Routes with
+	// a waypoint outside the map or on an impassable tile are dropped at load.
+	Patrol      []PatrolPoint `yaml:"patrol,omitempty"`
+	PatrolPause int           `yaml:"patrol_pause,omitempty"` // seconds
+}
+
+// PatrolPoint is one patrol waypoint on the spawn's map.
+type PatrolPoint struct {
+	X int32 `yaml:"x"`
+	Y int32 `yaml:"y"`
+}
+
+// SpawnArea is an inclusive spawn rectangle.
+type SpawnArea struct {
+	X1 int32 `yaml:"x1"`
+	Y1 int32 `yaml:"y1"`
+	X2 int32 `yaml:"x2"`
+	Y2 int32 `yaml:"y2"`
 }
 
 type npcListFile struct {
@@ -64,7 +164,7 @@ type NpcTable struct {
 
 // LoadNpcTable loads NPC templates from a YAML file.
 func LoadNpcTable(path string) (*NpcTable, error) {
-	data, err := os.ReadFile(path)
+	data, err := readFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read npc_list: %w", err)
 	}
@@ -75,8 +175,23 @@ func LoadNpcTable(path string) (*NpcTable, error) {
 	t := &NpcTable{templates: make(map[int32]*NpcTemplate, len(f.Npcs))}
 	for i := range f.Npcs {
 		npc := &f.Npcs[i]
+		for j := range npc.OnHit {
+			if err := npc.OnHit[j].validate(); err != nil {
+				return nil, fmt.Errorf("npc %d on_hit[%d]: %w", npc.NpcID, j, err)
+			}
+		}
 		t.templates[npc.NpcID] = npc
 	}
+	for _, npc := range t.templates {
+		for j, sp := range npc.SplitInto {
+			if sp.Count <= 0 || sp.Count > maxSplitCount {
+				return nil, fmt.Errorf("npc %d split_into[%d]: count %d out of range 1-%d", npc.NpcID, j, sp.Count, maxSplitCount)
+			}
+			if t.templates[sp.NpcID] == nil {
+				return nil, fmt.Errorf("npc %d split_into[%d]: unknown npc_id %d", npc.NpcID, j, sp.NpcID)
+			}
+		}
+	}
 	return t, nil
 }
 
@@ -92,7 +207,7 @@ func (t *NpcTable) Count() int {
 
 // LoadSpawnList loads spawn entries from a YAML file.
 func LoadSpawnList(path string) ([]SpawnEntry, error) {
-	data, err := os.ReadFile(path)
+	data, err := readFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read spawn_list: %w", err)
 	}
@@ -100,6 +215,16 @@ func LoadSpawnList(path string) ([]SpawnEntry, error) {
 	if err := yaml.Unmarshal(data, &f); err != nil {
 		return nil, fmt.Errorf("parse spawn_list: %w", err)
 	}
+	for i := range f.Spawns {
+		if a := f.Spawns[i].Area; a != nil {
+			if a.X1 > a.X2 {
+				a.X1, a.X2 = a.X2, a.X1
+			}
+			if a.Y1 > a.Y2 {
+				a.Y1, a.Y2 = a.Y2, a.Y1
+			}
+		}
+	}
 	return f.Spawns, nil
 }
 
@@ -0,0 +1,121 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSpawnListNormalizesArea(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spawn_list.yaml")
+	yml := `spawns:
+  - npc_id: 45008
+    map_id: 4
+    count: 3
+    area: {x1: 32610, y1: 32790, x2: 32600, y2: 32780}
+  - npc_id: 70000
+    map_id: 4
+    x: 32600
+    y: 32780
+    count: 1
+    patrol:
+      - {x: 32600, y: 32780}
+      - {x: 32610, y: 32780}
+`
+	if err := os.WriteFile(path, []byte(yml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	spawns, err := LoadSpawnList(path)
+	if err != nil {
+		t.Fatalf("LoadSpawnList: %v", err)
+	}
+	if len(spawns) != 2 {
+		t.Fatalf("got %d spawns, want 2", len(spawns))
+	}
+	a := spawns[0].Area
+	if a == nil || *a != (SpawnArea{X1: 32600, Y1: 32780, X2: 32610, Y2: 32790}) {
+		t.Fatalf("area = %+v, want corners swapped into x1<=x2, y1<=y2", a)
+	}
+	if spawns[1].Area != nil {
+		t.Fatalf("point spawn got area %+v", spawns[1].Area)
+	}
+	if got := spawns[1].Patrol; len(got) != 2 || got[1] != (PatrolPoint{X: 32610, Y: 32780}) {
+		t.Fatalf("patrol = %+v", got)
+	}
+}
+
+func TestShippedNpcListFlagsBosses(t *testing.T) {
+	npcs, err := LoadNpcTable("../../data/yaml/npc_list.yaml")
+	if err != nil {
+		t.Fatalf("LoadNpcTable: %v", err)
+	}
+	// A few well-known field bosses must be flagged so kills are announced and recorded.
+	for _, id := range []int32{45516, 45545, 45573, 45583, 45614, 45753} {
+		tmpl := npcs.Get(id)
+		if tmpl == nil {
+			t.Errorf("npc %d missing from npc_list.yaml", id)
+			continue
+		}
+		if !tmpl.Boss {
+			t.Errorf("npc %d (%s) is not flagged boss", id, tmpl.Name)
+		}
+	}
+}
+
+func TestLoadNpcTableValidatesOnHit(t *testing.T) {
+	cases := map[string]string{
+		"chance":  "{type: mp_drain, chance: 0, amount: 5}",
+		"type":    "{type: curse, chance: 10}",
+		"poison":  "{type: poison, chance: 10, poison: 3}",
+		"stat":    "{type: stat_drain, chance: 10, stat: luck, amount: 1, duration: 5}",
+		"drain":   "{type: stat_drain, chance: 10, stat: str, amount: 1}",
+		"mp zero": "{type: mp_drain, chance: 10}",
+	}
+	for name, eff := range cases {
+		path := filepath.Join(t.TempDir(), "npc_list.yaml")
+		yml := "npcs:\n  - npc_id: 45020\n    name: test\n    on_hit:\n      - " + eff + "\n"
+		if err := os.WriteFile(path, []byte(yml), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadNpcTable(path); err == nil {
+			t.Errorf("%s: invalid on_hit %s loaded", name, eff)
+		}
+	}
+}
+
+func TestLoadNpcTableParsesOnHit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "npc_list.yaml")
+	yml := `npcs:
+  - npc_id: 45020
+    name: test
+    on_hit:
+      - type: stat_drain
+        chance: 10
+        stat: str
+        amount: 1
+        duration: 30
+      - {type: poison, chance: 5, poison: 2, gfx: 751}
+      - {type: mp_drain, chance: 20, amount: 3}
+`
+	if err := os.WriteFile(path, []byte(yml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	npcs, err := LoadNpcTable(path)
+	if err != nil {
+		t.Fatalf("LoadNpcTable: %v", err)
+	}
+	tmpl := npcs.Get(45020)
+	if tmpl == nil || len(tmpl.OnHit) != 3 {
+		t.Fatalf("45020 on_hit = %+v, want 3 effects", tmpl)
+	}
+	want := []OnHitEffect{
+		{Type: OnHitStatDrain, Chance: 10, Stat: "str", Amount: 1, Duration: 30},
+		{Type: OnHitPoison, Chance: 5, Poison: 2, Gfx: 751},
+		{Type: OnHitMPDrain, Chance: 20, Amount: 3},
+	}
+	for i, eff := range tmpl.OnHit {
+		if eff != want[i] {
+			t.Errorf("on_hit[%d] = %+v, want %+v", i, eff, want[i])
+		}
+	}
+}
@@ -15,6 +15,8 @@ type NpcServiceTable struct {
 	weaponEnchant WeaponEnchantDef
 	armorEnchant  ArmorEnchantDef
 	polymorph     PolymorphServiceDef
+	statReset     StatResetDef
+	repair        RepairDef
 	polyForms     map[string]int32 // action string → poly_id
 }
 
@@ -65,6 +67,29 @@ type PolymorphServiceDef struct {
 	DurationSec int
 }
 
+// StatResetDef defines the bonus stat reset NPC (action "statreset").
+type StatResetDef struct {
+	NpcID     int32 // 0 = service disabled
+	Cost      int32 // adena fee (0 = free)
+	ItemID    int32 // item consumed per reset (0 = none)
+	ItemCount int32
+	Gfx       int32
+}
+
+// RepairDef defines the weapon repair service (action "repair" and the
+// client's repair list). Fee per durability point comes from
+// [gameplay] repair_cost_per_durability and is scaled by the weapon's enchant.
+type RepairDef struct {
+	NpcIDs     map[int32]bool // NPCs offering repair (empty = any NPC)
+	EnchantPct int            // each positive enchant level adds this % to the fee
+	Gfx        int32          // effect played on the player after a repair (0 = none)
+}
+
+// IsRepairNpc reports whether npcID may repair weapons.
+func (d *RepairDef) IsRepairNpc(npcID int32) bool {
+	return len(d.NpcIDs) == 0 || d.NpcIDs[npcID]
+}
+
 // GetHealer returns healer definition for a NPC ID, or nil if not a healer.
 func (t *NpcServiceTable) GetHealer(npcID int32) *HealerDef {
 	return t.healers[npcID]
@@ -85,6 +110,12 @@ func (t *NpcServiceTable) ArmorEnchant() ArmorEnchantDef { return t.armorEnchant
 // Polymorph returns polymorph NPC parameters.
 func (t *NpcServiceTable) Polymorph() PolymorphServiceDef { return t.polymorph }
 
+// StatReset returns the bonus stat reset NPC definition.
+func (t *NpcServiceTable) StatReset() StatResetDef { return t.statReset }
+
+// Repair returns the weapon repair service definition.
+func (t *NpcServiceTable) Repair() *RepairDef { return &t.repair }
+
 // GetPolyForm returns the polymorph GFX ID for an action string, or 0 if not found.
 func (t *NpcServiceTable) GetPolyForm(action string) int32 {
 	return t.polyForms[action]
@@ -144,6 +175,20 @@ type polymorphYAML struct {
 	Forms       []polyFormYAML `yaml:"forms"`
 }
 
+type statResetYAML struct {
+	NpcID     int32 `yaml:"npc_id"`
+	Cost      int32 `yaml:"cost"`
+	ItemID    int32 `yaml:"item_id"`
+	ItemCount int32 `yaml:"item_count"`
+	Gfx       int32 `yaml:"gfx"`
+}
+
+type repairYAML struct {
+	NpcIDs     []int32 `yaml:"npc_ids"`
+	EnchantPct int     `yaml:"enchant_pct"`
+	Gfx        int32   `yaml:"gfx"`
+}
+
 type npcServicesYAML struct {
 	Healers       []healerYAML      `yaml:"healers"`
 	Cancel        cancelYAML        `yaml:"cancel"`
@@ -151,6 +196,8 @@ type npcServicesYAML struct {
 	WeaponEnchant weaponEnchantYAML `yaml:"weapon_enchant"`
 	ArmorEnchant  armorEnchantYAML  `yaml:"armor_enchant"`
 	Polymorph     polymorphYAML     `yaml:"polymorph"`
+	StatReset     statResetYAML     `yaml:"stat_reset"`
+	Repair        repairYAML        `yaml:"repair"`
 }
 
 type npcServiceFile struct {
@@ -212,6 +259,24 @@ func LoadNpcServiceTable(path string) (*NpcServiceTable, error) {
 		Cost:        s.Polymorph.Cost,
 		DurationSec: s.Polymorph.DurationSec,
 	}
+	t.statReset = StatResetDef{
+		NpcID:     s.StatReset.NpcID,
+		Cost:      s.StatReset.Cost,
+		ItemID:    s.StatReset.ItemID,
+		ItemCount: s.StatReset.ItemCount,
+		Gfx:       s.StatReset.Gfx,
+	}
+	if t.statReset.ItemID > 0 && t.statReset.ItemCount <= 0 {
+		t.statReset.ItemCount = 1
+	}
+	t.repair = RepairDef{
+		NpcIDs:     make(map[int32]bool, len(s.Repair.NpcIDs)),
+		EnchantPct: s.Repair.EnchantPct,
+		Gfx:        s.Repair.Gfx,
+	}
+	for _, id := range s.Repair.NpcIDs {
+		t.repair.NpcIDs[id] = true
+	}
 	for _, form := range s.Polymorph.Forms {
 		t.polyForms[form.Action] = form.PolyID
 	}
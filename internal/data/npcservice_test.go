@@ -0,0 +1,26 @@
+package data
+
+import "testing"
+
+func TestShippedRepairLimitedToBlacksmiths(t *testing.T) {
+	svc, err := LoadNpcServiceTable("../../data/yaml/npc_services.yaml")
+	if err != nil {
+		t.Fatalf("LoadNpcServiceTable: %v", err)
+	}
+	rep := svc.Repair()
+	for _, id := range []int32{80133, 80142} {
+		if !rep.IsRepairNpc(id) {
+			t.Errorf("blacksmith %d cannot repair", id)
+		}
+	}
+	if rep.IsRepairNpc(70001) {
+		t.Error("non-blacksmith NPC 70001 accepted as a repair NPC")
+	}
+}
+
+func TestRepairEmptyNpcIDsMeansAnyNpc(t *testing.T) {
+	rep := RepairDef{}
+	if !rep.IsRepairNpc(70001) {
+		t.Error("empty npc_ids should allow any NPC")
+	}
+}
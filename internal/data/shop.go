@@ -2,7 +2,6 @@ package data
 
 import (
 	"fmt"
-	"os"
 
 	"gopkg.in/yaml.v3"
 )
@@ -57,7 +56,7 @@ type shopListFile struct {
 
 // LoadShopTable loads NPC shop data from a YAML file.
 func LoadShopTable(path string) (*ShopTable, error) {
-	raw, err := os.ReadFile(path)
+	raw, err := readFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read shop_list: %w", err)
 	}
@@ -2,7 +2,6 @@ package data
 
 import (
 	"fmt"
-	"os"
 
 	"gopkg.in/yaml.v3"
 )
@@ -31,7 +30,7 @@ type SkillInfo struct {
 	Lawful          int   // alignment requirement
 	Ranged          int   // -1=touch, 0=self, positive=range
 	Area            int   // 0=single, >0=radius, -1=screen
-	Through         bool  // pierces obstacles
+	Through         bool  // piercing: hits every NPC on the line from caster through target (Area == 0)
 	ActionID        int   // cast animation action
 	CastGfx         int32 // visual effect GFX ID
 	CastGfx2        int32
@@ -39,8 +38,18 @@ type SkillInfo struct {
 	SysMsgStop      int   // message ID on buff end
 	SysMsgFail      int   // message ID on failure
 	IDBitmask       int   // bitmask for S_AddSkill packet (per-level)
+	Knockback       int   // KnockbackNone / KnockbackPush / KnockbackPull
+	KnockbackDist   int   // tiles to displace the target (shortened when blocked)
+	MaxDamage       int   // per-hit damage cap for this skill (0 = only [combat] max_damage)
 }
 
+// Knockback modes for SkillInfo.Knockback.
+const (
+	KnockbackNone = 0
+	KnockbackPush = 1 // push the target away from the caster
+	KnockbackPull = 2 // pull the target toward the caster
+)
+
 // SkillTable holds all skills indexed by SkillID.
 type SkillTable struct {
 	skills map[int32]*SkillInfo
@@ -105,6 +114,9 @@ type skillEntry struct {
 	SysMsgHappen    int    `yaml:"sys_msg_happen"`
 	SysMsgStop      int    `yaml:"sys_msg_stop"`
 	SysMsgFail      int    `yaml:"sys_msg_fail"`
+	Knockback       string `yaml:"knockback"`      // "push" / "pull" (optional)
+	KnockbackDist   int    `yaml:"knockback_dist"` // default 1 when knockback is set
+	MaxDamage       int    `yaml:"max_damage,omitempty"`
 }
 
 type skillListFile struct {
@@ -113,7 +125,7 @@ type skillListFile struct {
 
 // LoadSkillTable loads skill definitions from YAML.
 func LoadSkillTable(path string) (*SkillTable, error) {
-	raw, err := os.ReadFile(path)
+	raw, err := readFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read skills: %w", err)
 	}
@@ -127,6 +139,14 @@ func LoadSkillTable(path string) (*SkillTable, error) {
 	}
 	for i := range f.Skills {
 		e := &f.Skills[i]
+		knockback, err := parseKnockback(e.Knockback)
+		if err != nil {
+			return nil, fmt.Errorf("skill %d: %w", e.SkillID, err)
+		}
+		knockbackDist := e.KnockbackDist
+		if knockback != KnockbackNone && knockbackDist <= 0 {
+			knockbackDist = 1
+		}
 		t.skills[e.SkillID] = &SkillInfo{
 			SkillID:         e.SkillID,
 			Name:            e.Name,
@@ -158,8 +178,24 @@ func LoadSkillTable(path string) (*SkillTable, error) {
 			SysMsgStop:      e.SysMsgStop,
 			SysMsgFail:      e.SysMsgFail,
 			IDBitmask:       e.ID,
+			Knockback:       knockback,
+			KnockbackDist:   knockbackDist,
+			MaxDamage:       e.MaxDamage,
 		}
 		t.byName[e.Name] = t.skills[e.SkillID]
 	}
 	return t, nil
 }
+
+// parseKnockback converts the YAML knockback flag to a Knockback* mode.
+func parseKnockback(v string) (int, error) {
+	switch v {
+	case "":
+		return KnockbackNone, nil
+	case "push":
+		return KnockbackPush, nil
+	case "pull":
+		return KnockbackPull, nil
+	}
+	return 0, fmt.Errorf("invalid knockback %q", v)
+}
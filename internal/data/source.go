@@ -0,0 +1,13 @@
+package data
+
+import "os"
+
+// readFile reads the template tables (NPCs, items, spawns, drops, shops,
+// skills, mob skills). Replaced by SetFileReader when templates are served
+// from the database ([data] source = "db").
+var readFile = os.ReadFile
+
+// SetFileReader replaces the template table reader. Call before any Load*.
+func SetFileReader(fn func(path string) ([]byte, error)) {
+	readFile = fn
+}
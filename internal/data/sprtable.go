@@ -11,6 +11,7 @@ import (
 const (
 	ActWalk                = 0
 	ActAttack              = 1
+	ActIdle                = 3
 	ActSwordWalk           = 4
 	ActSwordAttack         = 5
 	ActAxeWalk             = 11
@@ -180,6 +181,57 @@ func (t *SprTable) GetMoveSpeed(sprID, actID int) int {
 	return spr.moveSpeed[ActWalk]
 }
 
+// NpcSpeeds holds the animation timings (ms) resolved for one NPC template.
+// A zero value means "use the AI default".
+type NpcSpeeds struct {
+	Atk       int16 // melee attack (ActAttack)
+	RangedAtk int16 // ranged attack (ActBowAttack)
+	Move      int16 // wander / return walk (ActWalk)
+	Run       int16 // chase (template run_speed, else Move)
+}
+
+// ResolveNpcSpeeds resolves an NPC template's per-action speeds from the
+// sprite table. Templates with a zero atk_speed / passive_speed keep 0 for
+// that group; actions absent for the sprite fall back to the template value.
+// list.spr has no run action, so the chase speed comes from the template's
+// run_speed and falls back to the resolved walk speed when that is unset.
+// Safe to call on a nil table (template values only).
+func (t *SprTable) ResolveNpcSpeeds(tmpl *NpcTemplate) NpcSpeeds {
+	sp := NpcSpeeds{
+		Atk:       tmpl.AtkSpeed,
+		RangedAtk: tmpl.AtkSpeed,
+		Move:      tmpl.PassiveSpeed,
+	}
+	if t != nil {
+		t.resolveSprSpeeds(tmpl, &sp)
+	}
+	sp.Run = tmpl.RunSpeed
+	if sp.Run == 0 {
+		sp.Run = sp.Move
+	}
+	return sp
+}
+
+// resolveSprSpeeds overrides the template attack/walk speeds with the
+// sprite's animation timings where the sprite defines them.
+func (t *SprTable) resolveSprSpeeds(tmpl *NpcTemplate, sp *NpcSpeeds) {
+	gfx := int(tmpl.GfxID)
+	if tmpl.AtkSpeed != 0 {
+		if v := t.GetAttackSpeed(gfx, ActAttack); v > 0 {
+			sp.Atk = int16(v)
+			sp.RangedAtk = int16(v)
+		}
+		if v := t.GetAttackSpeed(gfx, ActBowAttack); v > 0 {
+			sp.RangedAtk = int16(v)
+		}
+	}
+	if tmpl.PassiveSpeed != 0 {
+		if v := t.GetMoveSpeed(gfx, ActWalk); v > 0 {
+			sp.Move = int16(v)
+		}
+	}
+}
+
 // GetDirSpellSpeed returns the directional spell cast animation duration (ms).
 // Returns 0 if the sprite is unknown.
 func (t *SprTable) GetDirSpellSpeed(sprID int) int {
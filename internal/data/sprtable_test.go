@@ -0,0 +1,48 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveNpcSpeeds(t *testing.T) {
+	// sprite 100: walk 640ms, melee 800ms, bow 960ms, and a sword walk that
+	// must not change the NPC move speed. sprite 200: walk only.
+	body := `spr_actions:
+  - {spr_id: 100, act_id: 0, framecount: 16, framerate: 24}
+  - {spr_id: 100, act_id: 1, framecount: 20, framerate: 24}
+  - {spr_id: 100, act_id: 4, framecount: 8, framerate: 24}
+  - {spr_id: 100, act_id: 21, framecount: 24, framerate: 24}
+  - {spr_id: 200, act_id: 0, framecount: 12, framerate: 24}
+`
+	path := filepath.Join(t.TempDir(), "spr_action.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	spr, err := LoadSprTable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		tmpl NpcTemplate
+		want NpcSpeeds
+	}{
+		{"all actions", NpcTemplate{GfxID: 100, AtkSpeed: 1000, PassiveSpeed: 1000}, NpcSpeeds{Atk: 800, RangedAtk: 960, Move: 640, Run: 640}},
+		{"template run speed", NpcTemplate{GfxID: 100, AtkSpeed: 1000, PassiveSpeed: 1000, RunSpeed: 400}, NpcSpeeds{Atk: 800, RangedAtk: 960, Move: 640, Run: 400}},
+		{"no bow falls back to melee", NpcTemplate{GfxID: 200, AtkSpeed: 1000, PassiveSpeed: 1000}, NpcSpeeds{Atk: 1000, RangedAtk: 1000, Move: 480, Run: 480}},
+		{"zero template speed stays zero", NpcTemplate{GfxID: 100}, NpcSpeeds{}},
+		{"unknown sprite keeps template", NpcTemplate{GfxID: 999, AtkSpeed: 700, PassiveSpeed: 600}, NpcSpeeds{Atk: 700, RangedAtk: 700, Move: 600, Run: 600}},
+	}
+	for _, tt := range tests {
+		if got := spr.ResolveNpcSpeeds(&tt.tmpl); got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+	var nilTable *SprTable
+	if got := nilTable.ResolveNpcSpeeds(&NpcTemplate{GfxID: 100, AtkSpeed: 700, PassiveSpeed: 600, RunSpeed: 300}); got != (NpcSpeeds{Atk: 700, RangedAtk: 700, Move: 600, Run: 300}) {
+		t.Errorf("nil table: got %+v", got)
+	}
+}
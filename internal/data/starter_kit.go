@@ -0,0 +1,124 @@
+package data
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// starterAdenaID is the adena item ID used by the "adena" shorthand.
+const starterAdenaID int32 = 40308
+
+// StarterItem is one item granted on a character's first login.
+type StarterItem struct {
+	ItemID     int32
+	Count      int32
+	EnchantLvl int8
+}
+
+// StarterKitTable holds the first-login gift: a kit shared by every class plus
+// optional per-class extras, and an optional welcome S_ServerMessage.
+type StarterKitTable struct {
+	common         []StarterItem
+	byClass        map[int16][]StarterItem
+	WelcomeMessage uint16 // S_ServerMessage ID sent on first login (0 = none)
+}
+
+// ForClass returns the common kit followed by the class-specific items.
+func (t *StarterKitTable) ForClass(classType int16) []StarterItem {
+	if t == nil {
+		return nil
+	}
+	result := make([]StarterItem, 0, len(t.common)+len(t.byClass[classType]))
+	result = append(result, t.common...)
+	return append(result, t.byClass[classType]...)
+}
+
+// Count returns the number of distinct item entries across all kits.
+func (t *StarterKitTable) Count() int {
+	if t == nil {
+		return 0
+	}
+	n := len(t.common)
+	for _, items := range t.byClass {
+		n += len(items)
+	}
+	return n
+}
+
+// --- YAML loading ---
+
+type starterItemEntry struct {
+	ItemID  int32 `yaml:"item_id"`
+	Count   int32 `yaml:"count"`
+	Enchant int8  `yaml:"enchant"`
+}
+
+type starterClassEntry struct {
+	ClassType int16              `yaml:"class_type"`
+	Adena     int32              `yaml:"adena"`
+	Items     []starterItemEntry `yaml:"items"`
+}
+
+type starterKitFile struct {
+	WelcomeMessage uint16              `yaml:"welcome_message"`
+	Common         starterClassEntry   `yaml:"common"`
+	Classes        []starterClassEntry `yaml:"classes"`
+}
+
+// LoadStarterKit loads the first-login starter kit from YAML.
+// A missing file is not an error (no starter kit).
+func LoadStarterKit(path string) (*StarterKitTable, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read starter kit: %w", err)
+	}
+	var f starterKitFile
+	if err := yaml.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("parse starter kit: %w", err)
+	}
+
+	t := &StarterKitTable{
+		byClass:        make(map[int16][]StarterItem, len(f.Classes)),
+		WelcomeMessage: f.WelcomeMessage,
+	}
+	common, err := starterItems(f.Common)
+	if err != nil {
+		return nil, fmt.Errorf("starter kit common: %w", err)
+	}
+	t.common = common
+	for _, c := range f.Classes {
+		if c.ClassType < 0 || c.ClassType > 6 {
+			return nil, fmt.Errorf("starter kit: invalid class_type %d", c.ClassType)
+		}
+		items, err := starterItems(c)
+		if err != nil {
+			return nil, fmt.Errorf("starter kit class %d: %w", c.ClassType, err)
+		}
+		t.byClass[c.ClassType] = append(t.byClass[c.ClassType], items...)
+	}
+	return t, nil
+}
+
+// starterItems converts one YAML section (adena shorthand + item list).
+func starterItems(e starterClassEntry) ([]StarterItem, error) {
+	var result []StarterItem
+	if e.Adena > 0 {
+		result = append(result, StarterItem{ItemID: starterAdenaID, Count: e.Adena})
+	}
+	for _, it := range e.Items {
+		if it.ItemID <= 0 {
+			return nil, fmt.Errorf("invalid item_id %d", it.ItemID)
+		}
+		count := it.Count
+		if count <= 0 {
+			count = 1
+		}
+		result = append(result, StarterItem{ItemID: it.ItemID, Count: count, EnchantLvl: it.Enchant})
+	}
+	return result, nil
+}
@@ -0,0 +1,203 @@
+package data
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Weather kinds used by weather.yaml and world.MapWeather.
+const (
+	WeatherClear = "clear"
+	WeatherSnow  = "snow"
+	WeatherRain  = "rain"
+	WeatherFog   = "fog"
+)
+
+// weatherKinds is the fixed roll order for per-map weights.
+var weatherKinds = []string{WeatherClear, WeatherSnow, WeatherRain, WeatherFog}
+
+// weatherElementAttrs maps element names to skill attr bits
+// (matching the ATTR_* constants in scripts/combat/magic.lua).
+var weatherElementAttrs = map[string]int{
+	"earth": 1,
+	"fire":  2,
+	"water": 4,
+	"wind":  8,
+}
+
+// WeatherEffect is the gameplay effect of one weather kind.
+type WeatherEffect struct {
+	AggroRangePct int         // NPC aggro scan range percentage (0 = unchanged)
+	ElementDmgPct map[int]int // skill attr → damage percentage
+}
+
+// WeatherTable holds per-map weather weights and weather effects.
+// A nil table means no per-map weather and no effects.
+type WeatherTable struct {
+	maps    map[int16][]int // map ID → weights in weatherKinds order
+	effects map[string]*WeatherEffect
+}
+
+// Roll picks a weather kind for mapID. ok is false when the map has no entry.
+func (t *WeatherTable) Roll(mapID int16, randInt func(int) int) (kind string, ok bool) {
+	if t == nil {
+		return "", false
+	}
+	weights := t.maps[mapID]
+	if weights == nil {
+		return "", false
+	}
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	roll := randInt(total)
+	for i, w := range weights {
+		if roll < w {
+			return weatherKinds[i], true
+		}
+		roll -= w
+	}
+	return WeatherClear, true
+}
+
+// MapIDs returns the configured map IDs in ascending order.
+func (t *WeatherTable) MapIDs() []int16 {
+	if t == nil {
+		return nil
+	}
+	ids := make([]int16, 0, len(t.maps))
+	for id := range t.maps {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// AggroRange scales an NPC aggro scan range for the given weather.
+// The result never drops below 1 tile.
+func (t *WeatherTable) AggroRange(kind string, base int32) int32 {
+	e := t.effect(kind)
+	if e == nil || e.AggroRangePct <= 0 {
+		return base
+	}
+	return max(base*int32(e.AggroRangePct)/100, 1)
+}
+
+// ElementDamage scales skill damage of the given attr for the given weather.
+// Zero damage (miss / resisted) stays zero; a hit keeps at least 1 damage.
+func (t *WeatherTable) ElementDamage(kind string, attr int, dmg int32) int32 {
+	e := t.effect(kind)
+	if e == nil || dmg <= 0 || attr == 0 {
+		return dmg
+	}
+	pct, ok := e.ElementDmgPct[attr]
+	if !ok || pct == 100 {
+		return dmg
+	}
+	return max(int32(math.Round(float64(dmg)*float64(pct)/100)), 1)
+}
+
+// Count returns the number of configured maps.
+func (t *WeatherTable) Count() int {
+	if t == nil {
+		return 0
+	}
+	return len(t.maps)
+}
+
+func (t *WeatherTable) effect(kind string) *WeatherEffect {
+	if t == nil {
+		return nil
+	}
+	return t.effects[kind]
+}
+
+// --- YAML loading ---
+
+type weatherMapEntry struct {
+	MapID int16 `yaml:"map_id"`
+	Clear int   `yaml:"clear"`
+	Snow  int   `yaml:"snow"`
+	Rain  int   `yaml:"rain"`
+	Fog   int   `yaml:"fog"`
+}
+
+type weatherEffectEntry struct {
+	AggroRangePct int            `yaml:"aggro_range_pct"`
+	ElementDmgPct map[string]int `yaml:"element_dmg_pct"`
+}
+
+type weatherFile struct {
+	Effects map[string]weatherEffectEntry `yaml:"effects"`
+	Maps    []weatherMapEntry             `yaml:"maps"`
+}
+
+// LoadWeatherTable loads per-map weather from YAML.
+// A missing file is not an error (only the global weather is used).
+func LoadWeatherTable(path string) (*WeatherTable, error) {
+	t := &WeatherTable{maps: make(map[int16][]int), effects: make(map[string]*WeatherEffect)}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return t, nil
+		}
+		return nil, fmt.Errorf("read weather table: %w", err)
+	}
+	var f weatherFile
+	if err := yaml.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("parse weather table: %w", err)
+	}
+
+	for kind, e := range f.Effects {
+		if !validWeatherKind(kind) {
+			return nil, fmt.Errorf("weather table: unknown weather %q", kind)
+		}
+		if e.AggroRangePct < 0 {
+			return nil, fmt.Errorf("weather table: %s aggro_range_pct must be >= 0", kind)
+		}
+		eff := &WeatherEffect{AggroRangePct: e.AggroRangePct, ElementDmgPct: make(map[int]int)}
+		for elem, pct := range e.ElementDmgPct {
+			attr, ok := weatherElementAttrs[elem]
+			if !ok {
+				return nil, fmt.Errorf("weather table: %s unknown element %q", kind, elem)
+			}
+			if pct < 0 {
+				return nil, fmt.Errorf("weather table: %s %s damage pct must be >= 0", kind, elem)
+			}
+			eff.ElementDmgPct[attr] = pct
+		}
+		t.effects[kind] = eff
+	}
+	for _, m := range f.Maps {
+		if _, dup := t.maps[m.MapID]; dup {
+			return nil, fmt.Errorf("weather table: duplicate map_id %d", m.MapID)
+		}
+		weights := []int{m.Clear, m.Snow, m.Rain, m.Fog}
+		total := 0
+		for _, w := range weights {
+			if w < 0 {
+				return nil, fmt.Errorf("weather table: map %d has a negative weight", m.MapID)
+			}
+			total += w
+		}
+		if total == 0 {
+			return nil, fmt.Errorf("weather table: map %d has no weights", m.MapID)
+		}
+		t.maps[m.MapID] = weights
+	}
+	return t, nil
+}
+
+func validWeatherKind(kind string) bool {
+	for _, k := range weatherKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
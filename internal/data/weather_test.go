@@ -0,0 +1,51 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestShippedWeatherTableIsDisabled(t *testing.T) {
+	w, err := LoadWeatherTable(filepath.Join("..", "..", "data", "yaml", "weather.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Count() != 0 {
+		t.Errorf("shipped weather.yaml configures %d maps, want none", w.Count())
+	}
+	for _, kind := range weatherKinds {
+		if got := w.AggroRange(kind, 8); got != 8 {
+			t.Errorf("%s aggro range = %d, want 8", kind, got)
+		}
+		if got := w.ElementDamage(kind, 4, 100); got != 100 {
+			t.Errorf("%s water damage = %d, want 100", kind, got)
+		}
+	}
+}
+
+func TestWeatherEffects(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "weather.yaml")
+	body := `effects:
+  fog: {aggro_range_pct: 60}
+  rain: {element_dmg_pct: {water: 110}}
+maps:
+  - {map_id: 4, rain: 1}
+`
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := LoadWeatherTable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kind, ok := w.Roll(4, func(int) int { return 0 }); !ok || kind != WeatherRain {
+		t.Errorf("Roll(4) = %q, %v", kind, ok)
+	}
+	if got := w.AggroRange(WeatherFog, 8); got != 4 {
+		t.Errorf("fog aggro range = %d, want 4", got)
+	}
+	if got := w.ElementDamage(WeatherRain, 4, 100); got != 110 {
+		t.Errorf("rain water damage = %d, want 110", got)
+	}
+}
@@ -28,11 +28,29 @@ func CalcHeading(sx, sy, tx, ty int32) int16 {
 	return 0
 }
 
-// FindArrow 在玩家背包中找到可用的箭矢（handler 內部及 system 使用）。
+// RangedWeaponType 回傳玩家裝備的遠程武器類型（bow / singlebow / gauntlet），非遠程武器回傳 ""。
+func RangedWeaponType(player *world.PlayerInfo, deps *Deps) string {
+	wpn := player.Equip.Weapon()
+	if wpn == nil {
+		return ""
+	}
+	info := deps.Items.Get(wpn.ItemID)
+	if info == nil || !world.IsRangedWeapon(info.Type) {
+		return ""
+	}
+	return info.Type
+}
+
+// FindArrow 在玩家背包中找到與裝備中遠程武器相符的彈藥（弓 → 箭、鐵手甲 → 飛刀）。
+// 未裝備遠程武器時回傳 nil。銀箭等特殊箭同屬 arrow，任何弓皆可使用。
 func FindArrow(player *world.PlayerInfo, deps *Deps) *world.InvItem {
+	ammo := world.AmmoType(RangedWeaponType(player, deps))
+	if ammo == "" {
+		return nil
+	}
 	for _, item := range player.Inv.Items {
 		info := deps.Items.Get(item.ItemID)
-		if info != nil && info.ItemType == "arrow" && item.Count > 0 {
+		if info != nil && info.ItemType == ammo && item.Count > 0 {
 			return item
 		}
 	}
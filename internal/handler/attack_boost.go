@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/l1jgo/server/internal/net"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// toggleAttackBoost 使用強化攻擊道具時切換開關。開啟後每次攻擊消耗 1 個並增加命中傷害
+// （etcitem attack_boost），由 system 攻擊流程處理；改用另一種強化道具時直接切換。
+func toggleAttackBoost(sess *net.Session, player *world.PlayerInfo, invItem *world.InvItem) {
+	if player.AttackBoostItem == invItem.ItemID {
+		player.AttackBoostItem = 0
+		SendSystemMessage(sess, fmt.Sprintf("已關閉%s。", invItem.Name))
+		return
+	}
+	player.AttackBoostItem = invItem.ItemID
+	SendSystemMessage(sess, fmt.Sprintf("已開啟%s，每次攻擊消耗 1 個。", invItem.Name))
+}
@@ -0,0 +1,20 @@
+package handler
+
+import "testing"
+
+func TestCalcHeading(t *testing.T) {
+	tests := []struct {
+		dx, dy int32
+		want   int16
+	}{
+		{0, -1, 0}, {1, -1, 1}, {1, 0, 2}, {1, 1, 3},
+		{0, 1, 4}, {-1, 1, 5}, {-1, 0, 6}, {-1, -1, 7},
+		{5, -12, 1}, // 只看方向，不看距離
+		{0, 0, 0},   // 同一格
+	}
+	for _, tt := range tests {
+		if got := CalcHeading(32700, 32800, 32700+tt.dx, 32800+tt.dy); got != tt.want {
+			t.Errorf("CalcHeading(dx %d, dy %d) = %d, want %d", tt.dx, tt.dy, got, tt.want)
+		}
+	}
+}
@@ -65,6 +65,6 @@ func HandleAttr(sess *net.Session, r *packet.Reader, deps *Deps) {
 		HandleClanJoinResponse(sess, player, data, accepted, deps)
 
 	case 630: // 決鬥確認: %0 要與你決鬥。你是否同意？(Y/N)
-		HandleDuelResponse(sess, player, data, accepted, deps)
+		HandleDuelAccept(sess, player, data, accepted, deps)
 	}
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/l1jgo/server/internal/net"
 	"github.com/l1jgo/server/internal/net/packet"
+	"github.com/l1jgo/server/internal/world"
 	"go.uber.org/zap"
 )
 
@@ -22,6 +23,8 @@ const (
 	loginAlreadyExists   byte = 0x07
 	loginWrongPass       byte = 0x08 // REASON_ACCESS_FAILED — 一般登入失敗
 	loginAccountInUse    byte = 0x16
+	loginSystemError     byte = 0x01 // REASON_SYSTEM_ERROR — 客戶端版本不符（另以系統訊息說明）
+	loginServerFull      byte = 0x1a // 伺服器人數已滿（max_online，排隊也已滿）
 	loginAutoNoAccount   byte = 155  // EVENT_ERROR_USER — BeanFun 自動登入帳號不存在
 	loginAutoWrongPass   byte = 149  // EVENT_ERROR_PASS — BeanFun 自動登入密碼錯誤
 )
@@ -65,6 +68,27 @@ func handleLogin(sess *net.Session, r *packet.Reader, deps *Deps, auto bool) {
 		wrongPassCode = loginAutoWrongPass
 	}
 
+	// 客戶端版本限制（[network] min/max_client_version）
+	if !clientVersionAllowed(sess.ClientVersion, deps) {
+		deps.Log.Info(fmt.Sprintf("客戶端版本不符拒絕登入  帳號=%s  ip=%s  版本=0x%08x", accountName, ip, sess.ClientVersion))
+		sendGlobalChat(sess, 9, "客戶端版本不符，請更新至伺服器支援的版本。")
+		sendLoginResult(sess, loginSystemError)
+		return
+	}
+
+	// IP 封鎖列表
+	if deps.Config.Server.IPBans {
+		ban, err := deps.AccountRepo.LoadIPBan(ctx, ip)
+		if err != nil {
+			deps.Log.Error("載入 IP 封鎖資料庫錯誤", zap.Error(err))
+		} else if ban != nil {
+			deps.Log.Info(fmt.Sprintf("被封鎖 IP 嘗試登入  帳號=%s  ip=%s", accountName, ip))
+			sendGlobalChat(sess, 9, banNotice("此 IP 已被封鎖", ban.BannedUntil, ban.Reason))
+			sendLoginResult(sess, loginWrongPass)
+			return
+		}
+	}
+
 	// Load account
 	account, err := deps.AccountRepo.Load(ctx, accountName)
 	if err != nil {
@@ -95,28 +119,114 @@ func handleLogin(sess *net.Session, r *packet.Reader, deps *Deps, auto bool) {
 		}
 	}
 
-	// Check banned
-	if account.Banned {
+	// Check banned（定期停權到期後自動視為解除）
+	if account.BanActive(time.Now()) {
 		deps.Log.Info(fmt.Sprintf("被封鎖帳號嘗試登入  帳號=%s", accountName))
+		sendGlobalChat(sess, 9, banNotice("帳號已停權", account.BannedUntil, account.BanReason))
 		sendLoginResult(sess, loginWrongPass)
 		return
 	}
 
+	// 斷線保留中的角色：直接放行（沿用保留角色的在線名額），選擇該角色時接回原 PlayerInfo
+	if account.Online && deps.LinkDead != nil && deps.LinkDead.Holding(accountName) {
+		completeLogin(ctx, sess, accountName, deps)
+		return
+	}
+
 	// Check already online
-	if account.Online {
+	if account.Online || deps.World.Logins.IsQueued(accountName) {
 		sendLoginResult(sess, loginAlreadyExists)
 		return
 	}
 
-	// Success — mark online
+	// 在線人數上限（GM 帳號不受限）
+	if account.AccessLevel <= 0 && serverFull(deps) {
+		cfg := deps.Config.Server
+		if deps.World.Logins.QueueLen() < cfg.LoginQueueSize {
+			pos := deps.World.Logins.Enqueue(world.QueuedLogin{Session: sess, Account: accountName})
+			deps.Log.Info(fmt.Sprintf("伺服器人數已滿，登入排隊  帳號=%s  順位=%d", accountName, pos))
+			sendGlobalChat(sess, 9, queueNotice(pos))
+			return
+		}
+		deps.Log.Info(fmt.Sprintf("伺服器人數已滿，拒絕登入  帳號=%s  ip=%s", accountName, ip))
+		sendGlobalChat(sess, 9, "伺服器人數已滿，請稍後再試。")
+		sendLoginResult(sess, loginServerFull)
+		return
+	}
+
+	completeLogin(ctx, sess, accountName, deps)
+}
+
+// banNotice 組合停權通知文字（含解除日期與原因）。until 為 nil 表示永久。
+func banNotice(prefix string, until *time.Time, reason string) string {
+	msg := prefix + "（永久）"
+	if until != nil {
+		msg = prefix + "至 " + until.Local().Format("2006-01-02 15:04")
+	}
+	if reason != "" {
+		msg += "  原因: " + reason
+	}
+	return msg
+}
+
+// serverFull 判斷登入人數是否已達 [server] max_online（0 = 不限制）。
+func serverFull(deps *Deps) bool {
+	limit := deps.Config.Server.MaxOnline
+	return limit > 0 && deps.World.OnlineCount() >= limit
+}
+
+// AdmitQueuedLogins 人數空出時依序放行排隊中的登入。由 system.LoginQueueSystem 定期呼叫。
+// 有人放行後，通知仍在排隊的連線新的順位。
+func AdmitQueuedLogins(deps *Deps) {
+	admitQueued(deps, admitQueuedLogin)
+}
+
+// admitQueued 在未滿額時依序取出排隊登入交給 admit，之後通知剩餘排隊者新的順位。
+func admitQueued(deps *Deps, admit func(q world.QueuedLogin, deps *Deps)) {
+	admitted := false
+	for !serverFull(deps) {
+		q, ok := deps.World.Logins.PopQueue()
+		if !ok {
+			break
+		}
+		admitted = true
+		admit(q, deps)
+	}
+	if admitted {
+		for i, q := range deps.World.Logins.Waiting() {
+			sendGlobalChat(q.Session, 9, queueNotice(i+1))
+		}
+	}
+}
+
+// admitQueuedLogin 完成一筆排隊登入；排隊期間帳號可能已由其他連線登入。
+func admitQueuedLogin(q world.QueuedLogin, deps *Deps) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	account, err := deps.AccountRepo.Load(ctx, q.Account)
+	if err != nil || account == nil || account.Online {
+		sendLoginResult(q.Session, loginAlreadyExists)
+		return
+	}
+	completeLogin(ctx, q.Session, q.Account, deps)
+}
+
+// queueNotice 組合登入排隊通知文字（pos 為 1 起算的順位）。
+func queueNotice(pos int) string {
+	return fmt.Sprintf("伺服器人數已滿，目前排隊順位：第 %d 位，請稍候。", pos)
+}
+
+// completeLogin 標記帳號上線、計入在線人數並送出角色列表。
+func completeLogin(ctx context.Context, sess *net.Session, accountName string, deps *Deps) {
 	if err := deps.AccountRepo.SetOnline(ctx, accountName, true); err != nil {
 		deps.Log.Error("設定上線狀態資料庫錯誤", zap.Error(err))
 	}
-	if err := deps.AccountRepo.UpdateLastActive(ctx, accountName, ip); err != nil {
+	if err := deps.AccountRepo.UpdateLastActive(ctx, accountName, sess.IP); err != nil {
 		deps.Log.Error("更新最後活動時間資料庫錯誤", zap.Error(err))
 	}
 
 	sess.AccountName = accountName
+	deps.World.Logins.Admit(sess, accountName)
 	sendLoginResult(sess, loginOK)
 
 	// Transition to Authenticated
@@ -125,7 +235,7 @@ func handleLogin(sess *net.Session, r *packet.Reader, deps *Deps, auto bool) {
 	// Send character list
 	sendCharacterList(sess, deps)
 
-	deps.Log.Info(fmt.Sprintf("登入成功  帳號=%s  ip=%s", accountName, ip))
+	deps.Log.Info(fmt.Sprintf("登入成功  帳號=%s  ip=%s  在線=%d", accountName, sess.IP, deps.World.OnlineCount()))
 }
 
 // sendLoginResult 發送 S_LoginResult。
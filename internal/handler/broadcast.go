@@ -178,9 +178,9 @@ func SendNpcPack(viewer *net.Session, npc *world.NpcInfo) {
 	w.WriteC(0)                   // light
 	w.WriteC(0)                   // move speed
 	w.WriteD(npc.Exp)             // experience reward
-	w.WriteH(0)                   // lawful
+	w.WriteH(uint16(npc.NameLawful())) // lawful（客戶端依此決定名稱顏色）
 	w.WriteS(npc.NameID)
-	w.WriteS("")                  // title
+	w.WriteS(npc.Title)           // title
 	w.WriteC(0x00)                // ext status: NO PC flag
 	w.WriteD(0)                   // reserved
 	w.WriteS("")                  // no clan
@@ -209,9 +209,9 @@ func SendNpcDeadPack(viewer *net.Session, npc *world.NpcInfo) {
 	w.WriteC(0)                   // light
 	w.WriteC(0)                   // move speed
 	w.WriteD(npc.Exp)             // exp（Java: 死亡 NPC 仍發 exp）
-	w.WriteH(0)                   // lawful
+	w.WriteH(uint16(npc.NameLawful())) // lawful（名稱顏色）
 	w.WriteS(npc.NameID)
-	w.WriteS("")                  // title
+	w.WriteS(npc.Title)           // title
 	w.WriteC(0x00)                // ext status
 	w.WriteD(0)                   // reserved
 	w.WriteS("")                  // no clan
@@ -235,8 +235,14 @@ func sendAttackPacket(viewer *net.Session, attackerID, targetID, damage int32, h
 // BuildAttackPacket 建構近戰攻擊封包位元組（不發送）。
 // 用於廣播場景：序列化一次、發送多次。
 func BuildAttackPacket(attackerID, targetID, damage int32, heading int16) []byte {
+	return BuildActionAttackPacket(1, attackerID, targetID, damage, heading)
+}
+
+// BuildActionAttackPacket 建構指定動作代碼的近戰攻擊封包位元組（不發送）。
+// 玩家依武器類型使用不同攻擊動作（world.WeaponAttackAction），NPC 使用 1。
+func BuildActionAttackPacket(actionID byte, attackerID, targetID, damage int32, heading int16) []byte {
 	w := packet.NewWriterWithOpcode(packet.S_OPCODE_ATTACK)
-	w.WriteC(1)
+	w.WriteC(actionID)
 	w.WriteD(attackerID)
 	w.WriteD(targetID)
 	w.WriteH(uint16(damage))
@@ -443,7 +449,7 @@ func SendDropItem(viewer *net.Session, item *world.GroundItem) {
 	w.WriteC(0)                    // speed
 	w.WriteD(item.Count)           // item count
 	w.WriteH(0)                    // lawful
-	w.WriteS(item.Name)            // item display name
+	w.WriteS(groundItemName(item)) // item display name（依稀有度加色碼）
 	w.WriteS("")                   // title
 	w.WriteC(0x00)                 // status flags: 0 = item (not PC)
 	w.WriteD(0)                    // reserved
@@ -459,6 +465,18 @@ func SendDropItem(viewer *net.Session, item *world.GroundItem) {
 	viewer.Send(w.Bytes())
 }
 
+// groundGradeColors 地面物品名稱依稀有度加上的客戶端色碼（索引 = GroundItem.Grade）。
+// 0=一般（無色碼）、1=稀有（黃）、2=傳說（紅）。
+var groundGradeColors = [...]string{"", "\\f2", "\\f3"}
+
+// groundItemName 回傳地面物品顯示名稱，稀有物品加上色碼前綴。
+func groundItemName(item *world.GroundItem) string {
+	if int(item.Grade) >= len(groundGradeColors) {
+		return groundGradeColors[len(groundGradeColors)-1] + item.Name
+	}
+	return groundGradeColors[item.Grade] + item.Name
+}
+
 // ==================== Buff Icon Packets ====================
 
 // sendIconShield sends S_SkillIconShield (opcode 216) — AC buff icon.
@@ -499,7 +517,7 @@ func sendIconDexup(sess *net.Session, durationSec uint16, currentDex byte, iconT
 
 // sendIconAura sends S_SkillIconAura (opcode 250, sub-opcode 0x16) — aura buff icon.
 // Java: [C opcode=250][C 0x16][C iconId][H time]
-// iconId uses the Java skill constant (= our skill_id - 1 for aura/elf skills).
+// iconId comes from buff_icon_map.yaml (param of the aura entry).
 // Send time=0 to cancel.
 func sendIconAura(sess *net.Session, iconID byte, durationSec uint16) {
 	w := packet.NewWriterWithOpcode(packet.S_OPCODE_EVENT)
@@ -15,7 +15,7 @@ func BuffRowsFromPlayer(p *world.PlayerInfo) []persist.BuffRow {
 	rows := make([]persist.BuffRow, 0, len(p.ActiveBuffs))
 	for _, buff := range p.ActiveBuffs {
 		// Skip state-only buffs that shouldn't persist across login
-		if buff.SetInvisible || buff.SetParalyzed || buff.SetSleeped {
+		if buff.SetInvisible || buff.SetParalyzed || buff.SetSleeped || buff.SkillID == SkillStatusSpawnProtect {
 			continue
 		}
 
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/net/packet"
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestAuraBuffIconBytes(t *testing.T) {
+	icons, err := data.LoadBuffIconTable(filepath.Join("..", "..", "data", "yaml", "buff_icon_map.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps := &Deps{BuffIcons: icons}
+
+	// Java S_SkillIconAura 圖示 ID
+	want := map[int32]byte{
+		114: 113, // 激勵士氣
+		115: 114, // 鋼鐵士氣
+		117: 116, // 衝擊士氣
+		148: 147, // 火焰武器
+		149: 148, // 風之神射
+		156: 155, // 暴風之眼
+		163: 162, // 烈炎武器
+		166: 165, // 暴風神射
+	}
+	for skillID, iconID := range want {
+		sess := newTestSession(t)
+		sendBuffIcon(&world.PlayerInfo{Session: sess}, skillID, 300, deps)
+		sess.FlushOutput()
+		var got []byte
+		select {
+		case got = <-sess.OutQueue:
+		default:
+			t.Errorf("skill %d: no packet sent", skillID)
+			continue
+		}
+		// [C opcode=250][C 0x16][C iconId][H time]
+		exp := []byte{packet.S_OPCODE_EVENT, 0x16, iconID, 300 & 0xff, 300 >> 8}
+		if len(got) < len(exp) || string(got[:len(exp)]) != string(exp) {
+			t.Errorf("skill %d: packet % x, want % x", skillID, got, exp)
+		}
+	}
+}
@@ -81,6 +81,7 @@ func HandleChangeChar(sess *net.Session, _ *packet.Reader, deps *Deps) {
 			Cha:         player.Cha - int16(eq.AddCha) - bCha,
 			Intel:       player.Intel - int16(eq.AddInt) - bIntel,
 			BonusStats:  player.BonusStats,
+			BonusAlloc:  player.BonusAlloc,
 			ElixirStats: player.ElixirStats,
 			ClanID:      player.ClanID,
 			ClanName:    player.ClanName,
@@ -88,6 +89,7 @@ func HandleChangeChar(sess *net.Session, _ *packet.Reader, deps *Deps) {
 			Title:       player.Title,
 			Karma:       player.Karma,
 			PKCount:     player.PKCount,
+			RestedXP:    player.RestedXP,
 		}
 		if err := deps.CharRepo.SaveCharacter(ctx, row); err != nil {
 			deps.Log.Error("切換角色時存檔角色失敗",
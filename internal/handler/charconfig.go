@@ -4,11 +4,9 @@ import (
 	"context"
 	"encoding/binary"
 	"fmt"
-	"time"
 
 	"github.com/l1jgo/server/internal/net"
 	"github.com/l1jgo/server/internal/net/packet"
-	"go.uber.org/zap"
 )
 
 // HandleCharConfig processes C_SAVEIO (opcode 244).
@@ -44,17 +42,11 @@ func HandleCharConfig(sess *net.Session, r *packet.Reader, deps *Deps) {
 	binary.LittleEndian.PutUint32(blob[:4], uint32(javaLength))
 	copy(blob[4:], configData)
 
-	// Save to DB asynchronously (non-blocking for game loop)
-	go func(charID int32, b []byte) {
-		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-		defer cancel()
-		if err := deps.CharRepo.SaveCharConfig(ctx, charID, b); err != nil {
-			deps.Log.Error("儲存角色設定失敗",
-				zap.Int32("charID", charID),
-				zap.Error(err),
-			)
-		}
-	}(player.CharID, blob)
+	// Save via the write queue (ordered with other saves, non-blocking for game loop)
+	charID := player.CharID
+	QueueWrite(deps, "char_config", func(ctx context.Context) error {
+		return deps.CharRepo.SaveCharConfig(ctx, charID, blob)
+	})
 }
 
 // sendCharConfig sends S_CharacterConfig (opcode 250, sub-type 41) — hotkey/UI config.
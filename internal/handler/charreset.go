@@ -259,6 +259,8 @@ func finishCharReset(sess *net.Session, player *world.PlayerInfo, deps *Deps) {
 	} else {
 		player.BonusStats = 0
 	}
+	// 重置流程已重新分配所有屬性，舊配點紀錄作廢（不可再由洗配點退還）
+	player.BonusAlloc = [6]int16{}
 
 	// 充滿 HP/MP
 	player.HP = player.MaxHP
@@ -287,9 +289,7 @@ func finishCharReset(sess *net.Session, player *world.PlayerInfo, deps *Deps) {
 
 	// 傳送至重置完成點
 	if deps.World != nil {
-		player.X = resetEndX
-		player.Y = resetEndY
-		player.MapID = resetEndMapID
+		deps.World.UpdatePosition(sess.ID, resetEndX, resetEndY, resetEndMapID, player.Heading)
 		sendMapID(sess, uint16(resetEndMapID), true)
 		sendOwnCharPackFromPlayer(sess, player)
 	}
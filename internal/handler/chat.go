@@ -2,6 +2,7 @@ package handler
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/l1jgo/server/internal/net"
 	"github.com/l1jgo/server/internal/net/packet"
@@ -19,6 +20,15 @@ const (
 	ChatTrade  = 12
 )
 
+// 聊天冷卻頻道（PlayerInfo.ChatLastSent 索引）。
+const (
+	chatCDNone    = -1 // 一般頻道：不限制發言間隔
+	chatCDGlobal  = 0  // 大喊 / 全體 / 交易
+	chatCDWhisper = 1
+	chatCDParty   = 2
+	chatCDClan    = 3
+)
+
 // HandleChat processes C_CHAT (opcode 40) — multi-channel chat.
 func HandleChat(sess *net.Session, r *packet.Reader, deps *Deps) {
 	chatType := r.ReadC()
@@ -33,6 +43,11 @@ func HandleChat(sess *net.Session, r *packet.Reader, deps *Deps) {
 		return
 	}
 
+	// 玩家指令（.sit / .stand）：不經 GM 指令表
+	if chatType == ChatNormal && HandlePlayerCommand(sess, player, text, deps) {
+		return
+	}
+
 	// GM commands: intercept "." prefix in normal chat
 	if chatType == ChatNormal && HandleGMCommand(sess, player, text, deps) {
 		return
@@ -55,8 +70,12 @@ func HandleChat(sess *net.Session, r *packet.Reader, deps *Deps) {
 		zap.String("text", text),
 	)
 
+	var ok bool
 	switch chatType {
 	case ChatNormal:
+		if text, ok = chatAllowed(sess, player, chatCDNone, text, deps); !ok {
+			return
+		}
 		// Normal chat: broadcast to nearby players via S_SAY (opcode 81)
 		msg := fmt.Sprintf("%s: %s", player.Name, text)
 		sendNormalChat(sess, player.CharID, msg)
@@ -68,6 +87,9 @@ func HandleChat(sess *net.Session, r *packet.Reader, deps *Deps) {
 		}
 
 	case ChatShout:
+		if text, ok = chatAllowed(sess, player, chatCDGlobal, text, deps); !ok {
+			return
+		}
 		// Shout: wider range chat via S_SAY (opcode 81) type 2
 		msg := fmt.Sprintf("<%s> %s", player.Name, text)
 		sendShoutChat(sess, player.CharID, msg, player.X, player.Y)
@@ -83,6 +105,9 @@ func HandleChat(sess *net.Session, r *packet.Reader, deps *Deps) {
 		if player.Food < int16(deps.Config.Gameplay.WorldChatMinFood) {
 			return
 		}
+		if text, ok = chatAllowed(sess, player, chatCDGlobal, text, deps); !ok {
+			return
+		}
 		player.Food -= int16(deps.Config.Gameplay.WorldChatFoodCost)
 		sendPlayerStatus(sess, player)
 
@@ -96,6 +121,9 @@ func HandleChat(sess *net.Session, r *packet.Reader, deps *Deps) {
 		})
 
 	case ChatTrade:
+		if text, ok = chatAllowed(sess, player, chatCDGlobal, text, deps); !ok {
+			return
+		}
 		// Trade chat: all players via S_MESSAGE (opcode 243)
 		msg := fmt.Sprintf("[%s] %s", player.Name, text)
 		sendGlobalChat(sess, ChatTrade, msg)
@@ -114,6 +142,9 @@ func HandleChat(sess *net.Session, r *packet.Reader, deps *Deps) {
 		if clan == nil {
 			return
 		}
+		if text, ok = chatAllowed(sess, player, chatCDClan, text, deps); !ok {
+			return
+		}
 		msg := fmt.Sprintf("{%s} %s", player.Name, text)
 		for charID := range clan.Members {
 			member := deps.World.GetByCharID(charID)
@@ -128,6 +159,9 @@ func HandleChat(sess *net.Session, r *packet.Reader, deps *Deps) {
 		if party == nil {
 			return
 		}
+		if text, ok = chatAllowed(sess, player, chatCDParty, text, deps); !ok {
+			return
+		}
 		msg := fmt.Sprintf("((%s)) %s", player.Name, text)
 		for _, memberID := range party.Members {
 			member := deps.World.GetByCharID(memberID)
@@ -175,6 +209,17 @@ func HandleWhisper(sess *net.Session, r *packet.Reader, deps *Deps) {
 		return
 	}
 
+	// 對方開啟拒絕密語（GM 不受限制）
+	if target.RefuseWhisper && !player.IsGM() {
+		sendRefused(sess, target.Name, "密語")
+		return
+	}
+
+	text, ok := chatAllowed(sess, player, chatCDWhisper, text, deps)
+	if !ok {
+		return
+	}
+
 	// Send to receiver: S_TELL (opcode 67)
 	sendWhisperReceive(target.Session, player.Name, text)
 
@@ -183,6 +228,47 @@ func HandleWhisper(sess *net.Session, r *packet.Reader, deps *Deps) {
 	sendGlobalChat(sess, 9, outMsg)
 }
 
+// chatAllowed 檢查頻道發言冷卻並套用禁用字詞過濾（GM 不受限制）。
+// 回傳過濾後的訊息；false 表示不送出（已通知發言者）。通過時記錄該頻道發言時間。
+func chatAllowed(sess *net.Session, player *world.PlayerInfo, channel int, text string, deps *Deps) (string, bool) {
+	if player.IsGM() {
+		return text, true
+	}
+
+	now := time.Now().UnixNano()
+	if channel != chatCDNone {
+		var cdMs int
+		switch channel {
+		case chatCDGlobal:
+			cdMs = deps.Config.Gameplay.ChatGlobalCooldownMs
+		case chatCDWhisper:
+			cdMs = deps.Config.Gameplay.ChatWhisperCooldownMs
+		case chatCDParty:
+			cdMs = deps.Config.Gameplay.ChatPartyCooldownMs
+		case chatCDClan:
+			cdMs = deps.Config.Gameplay.ChatClanCooldownMs
+		}
+		if cdMs > 0 && now-player.ChatLastSent[channel] < int64(cdMs)*int64(time.Millisecond) {
+			SendSystemMessage(sess, "發言過於頻繁，請稍後再試。")
+			return text, false
+		}
+	}
+
+	if deps.ChatFilter != nil {
+		filtered, pass := deps.ChatFilter.Filter(text)
+		if !pass {
+			SendSystemMessage(sess, "訊息含有禁用字詞，無法送出。")
+			return text, false
+		}
+		text = filtered
+	}
+
+	if channel != chatCDNone {
+		player.ChatLastSent[channel] = now
+	}
+	return text, true
+}
+
 // --- Chat packet builders ---
 
 // sendNormalChat sends S_SAY (opcode 81) type 0 — normal chat.
@@ -1,7 +1,11 @@
 package handler
 
 import (
+	"fmt"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/l1jgo/server/internal/net"
 	"github.com/l1jgo/server/internal/net/packet"
@@ -219,11 +223,41 @@ func HandleTitle(sess *net.Session, r *packet.Reader, deps *Deps) {
 		return
 	}
 
+	title = strings.TrimSpace(title)
+	if msg := validateTitle(title, deps); msg != "" {
+		SendSystemMessage(sess, msg)
+		return
+	}
+
 	if deps.Clan != nil {
 		deps.Clan.SetTitle(sess, player, charName, title)
 	}
 }
 
+// validateTitle 檢查稱號長度與字元，回傳拒絕原因（空字串 = 通過）。
+// 空稱號表示清除，一律允許。
+func validateTitle(title string, deps *Deps) string {
+	if title == "" {
+		return ""
+	}
+	if !utf8.ValidString(title) {
+		return "稱號含有無效的文字。"
+	}
+	if utf8.RuneCountInString(title) > deps.Config.Character.TitleMaxLength {
+		return fmt.Sprintf("稱號最多 %d 個字。", deps.Config.Character.TitleMaxLength)
+	}
+	for _, r := range title {
+		// 控制字元與 \ 色碼前綴（\f）不允許
+		if unicode.IsControl(r) || r == '\\' {
+			return "稱號含有不允許的字元。"
+		}
+	}
+	if isBannedCharName(title, deps) {
+		return "稱號含有禁用字詞。"
+	}
+	return ""
+}
+
 // HandleEmblemUpload 處理 C_UPLOAD_EMBLEM (opcode 18) — 上傳盟徽。
 // 封包：[384 bytes 盟徽資料]
 func HandleEmblemUpload(sess *net.Session, r *packet.Reader, deps *Deps) {
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"time"
+
 	"github.com/l1jgo/server/internal/config"
 	"github.com/l1jgo/server/internal/core/event"
 	"github.com/l1jgo/server/internal/data"
@@ -56,12 +58,18 @@ type SkillManager interface {
 	CancelInvisibility(player *world.PlayerInfo)
 	// ApplyGMBuff GM 強制套用 buff（繞過已學/MP/材料驗證）。
 	ApplyGMBuff(player *world.PlayerInfo, skillID int32) bool
+	// ResurrectPlayer 以復活技能效果復活死亡玩家（復活道具用，不檢查機率與 MP）。
+	ResurrectPlayer(target, caster *world.PlayerInfo, skillID int32) bool
 }
 
 // DeathManager 處理玩家死亡與重生。由 system.DeathSystem 實作。
 type DeathManager interface {
 	// KillPlayer 處理玩家死亡（動畫、經驗懲罰、清 buff）。
 	KillPlayer(player *world.PlayerInfo)
+	// KillPlayerInPvP 被玩家擊殺：與 KillPlayer 相同但不套用 PvE 死亡掉落（PK 掉落由 PvPSystem 處理）。
+	KillPlayerInPvP(player *world.PlayerInfo)
+	// KillDuelLoser 決鬥落敗死亡：與 KillPlayer 相同但不扣經驗（決鬥不掉落物品）。
+	KillDuelLoser(player *world.PlayerInfo)
 	// ProcessRestart 處理死亡重生（回城、重建 Known）。
 	ProcessRestart(sess *net.Session, player *world.PlayerInfo)
 }
@@ -203,6 +211,14 @@ type MailManager interface {
 	MoveToStorage(sess *net.Session, player *world.PlayerInfo, mailID int32, subtype byte)
 	// BulkDelete 批次刪除信件。
 	BulkDelete(sess *net.Session, player *world.PlayerInfo, subtype byte, mailIDs []int32)
+	// OpenParcel 郵差 NPC 開啟包裹附件選擇視窗。
+	OpenParcel(sess *net.Session, player *world.PlayerInfo, npcObjID int32)
+	// StageParcel 記錄玩家選擇的包裹附件，隨下一封寄出的信件送出。
+	StageParcel(sess *net.Session, r *packet.Reader, count int, player *world.PlayerInfo)
+	// ClaimParcels 郵差 NPC 領取所有待領包裹。
+	ClaimParcels(sess *net.Session, player *world.PlayerInfo)
+	// NotifyUnread 登入時提示未讀信件與待領包裹。
+	NotifyUnread(sess *net.Session, player *world.PlayerInfo)
 }
 
 // ShopManager 處理 NPC 商店交易邏輯（購買/販賣）。由 system.ShopSystem 實作。
@@ -245,8 +261,24 @@ type PetLifecycleManager interface {
 	GiveToPet(sess *net.Session, player *world.PlayerInfo, pet *world.PetInfo, invItem *world.InvItem)
 	// TameNpc 處理馴服野生 NPC 為寵物。
 	TameNpc(sess *net.Session, player *world.PlayerInfo, npc *world.NpcInfo)
+	// AdoptNpc 將已通過驗證的野生 NPC 轉為寵物（項圈 + DB + 生成）。
+	AdoptNpc(sess *net.Session, player *world.PlayerInfo, npc *world.NpcInfo) bool
 	// UsePetItem 處理寵物裝備穿脫。
 	UsePetItem(sess *net.Session, pet *world.PetInfo, listNo int)
+	// RestorePets 登入時重新召喚登出前在外的寵物。
+	RestorePets(sess *net.Session, player *world.PlayerInfo)
+}
+
+// LinkDeadManager 斷線保留（linkdead）管理。由 system.InputSystem 實作。
+type LinkDeadManager interface {
+	// Holding 回傳帳號是否有斷線保留中的角色（重新登入時放行）。
+	Holding(account string) bool
+	// Reattach 進入世界時接回帳號保留中的角色：同一角色則把 PlayerInfo 換到新連線並回傳；
+	// 選擇其他角色則存檔移出保留中的角色並回傳 nil。
+	Reattach(sess *net.Session, charID int32) *world.PlayerInfo
+	// Reclaim 立即結束帳號的斷線保留：存檔、移出世界並標記離線（GM 踢除）。
+	// 回傳該帳號是否有保留中的角色。
+	Reclaim(account string) bool
 }
 
 // HauntedHouseManager 鬼屋副本管理器。由 system.HauntedHouseSystem 實作。
@@ -283,8 +315,10 @@ type ItemGroundManager interface {
 	DestroyItem(sess *net.Session, player *world.PlayerInfo, objectID, count int32)
 	// DropItem 將物品掉落至地面。
 	DropItem(sess *net.Session, player *world.PlayerInfo, objectID, count int32)
-	// PickupItem 從地面撿取物品。
-	PickupItem(sess *net.Session, player *world.PlayerInfo, objectID int32)
+	// PickupItem 從地面撿取物品。count > 0 且小於整堆數量時只撿取部分（可堆疊物品）。
+	PickupItem(sess *net.Session, player *world.PlayerInfo, objectID, count int32)
+	// LootCorpse 拾取怪物屍體內的掉落物（loot_corpse 模式）。
+	LootCorpse(sess *net.Session, player *world.PlayerInfo, corpseID int32)
 }
 
 // WarehouseManager 處理倉庫邏輯（存入/領出、DB 操作、血盟鎖定）。由 system.WarehouseSystem 實作。
@@ -335,8 +369,10 @@ type ItemUseManager interface {
 	UseHomeScroll(sess *net.Session, player *world.PlayerInfo, item *world.InvItem)
 	// UseFixedTeleportScroll 處理指定傳送卷軸使用。
 	UseFixedTeleportScroll(sess *net.Session, player *world.PlayerInfo, item *world.InvItem, itemInfo *data.ItemInfo)
-	// GiveDrops 為擊殺的 NPC 擲骰掉落物品。
-	GiveDrops(killer *world.PlayerInfo, npcID int32)
+	// UseTargetedItem 處理需指定目標的消耗品（復活卷軸、輔助魔法卷軸）。回傳 true 表示已消耗。
+	UseTargetedItem(sess *net.Session, r *packet.Reader, player *world.PlayerInfo, item *world.InvItem, itemInfo *data.ItemInfo) bool
+	// GiveDrops 為擊殺的 NPC 擲骰掉落物品，回傳實際掉落的物品。
+	GiveDrops(killer *world.PlayerInfo, npc *world.NpcInfo) []world.LootEntry
 	// ApplyHaste 套用加速效果。
 	ApplyHaste(sess *net.Session, player *world.PlayerInfo, durationSec int, gfxID int32)
 	// BroadcastEffect 向自己和附近玩家廣播特效。
@@ -349,6 +385,28 @@ type RankingChecker interface {
 	IsHero(name string) bool
 }
 
+// RateEventManager 提供活動倍率（經驗/掉寶/金幣）查詢與 GM 控制。由 system.RateEventSystem 實作。
+type RateEventManager interface {
+	// ExpRate 回傳目前生效的經驗倍率（活動期間回傳活動倍率，否則為 [rates] 基礎倍率）。
+	ExpRate() float64
+	// DropRate 回傳目前生效的掉寶倍率。
+	DropRate() float64
+	// GoldRate 回傳目前生效的金幣倍率。
+	GoldRate() float64
+	// StartEvent 開啟單項活動倍率（kind: exp/drop/gold），持續 dur 後自動恢復。回傳 false 表示 kind 無效。
+	StartEvent(kind string, rate float64, dur time.Duration) bool
+	// StopEvent 立即結束所有進行中的活動並恢復基礎倍率。
+	StopEvent()
+	// Status 回傳目前活動狀態描述（GM 查詢用）。
+	Status() []string
+}
+
+// ChatFilter 聊天禁用字詞過濾。預設由 data.ChatFilterTable 實作（data/yaml/chat_filter.yaml）。
+type ChatFilter interface {
+	// Filter 回傳過濾後的訊息；第二個回傳值為 false 表示整則訊息應封鎖。
+	Filter(text string) (string, bool)
+}
+
 // Deps holds shared dependencies injected into all packet handlers.
 type Deps struct {
 	AccountRepo *persist.AccountRepo
@@ -366,6 +424,7 @@ type Deps struct {
 	TeleportHtml  *data.TeleportHtmlTable
 	Portals       *data.PortalTable
 	RandomPortals *data.RandomPortalTable
+	Instances     *data.InstanceTable // 傳送門進入限制地圖（人數上限、單一隊伍、鑰匙）
 	Skills        *data.SkillTable
 	Npcs          *data.NpcTable
 	MobSkills      *data.MobSkillTable
@@ -387,6 +446,9 @@ type Deps struct {
 	BoardRepo     *persist.BoardRepo
 	MailRepo      *persist.MailRepo
 	PetRepo       *persist.PetRepo
+	BossKillRepo  *persist.BossKillRepo
+	BossRank      *BossRankCache      // .bossrank 排行榜快取（見 RefreshBossRank）
+	Writes        *persist.WriteQueue // 背景依序寫入（切換選項、過濾清單、存入金幣）
 	PetTypes      *data.PetTypeTable
 	PetItems      *data.PetItemTable
 	Dolls         *data.DollTable
@@ -410,10 +472,17 @@ type Deps struct {
 	PetLife       PetLifecycleManager // filled after PetSystem is created
 	DollMgr       DollManager         // filled after DollSystem is created
 	HauntedHouse  HauntedHouseManager // filled after HauntedHouseSystem is created
+	LinkDead      LinkDeadManager     // filled after InputSystem is created
 	DragonDoor    DragonDoorManager   // filled after DragonDoorSystem is created
 	Bus           *event.Bus  // event bus for emitting game events (EntityKilled, etc.)
 	WeaponSkills  *data.WeaponSkillTable
+	Messages      *data.MessageTable // S_ServerMessage 格式字串（選用，供驗證與預覽）
+	StarterKit    *data.StarterKitTable // 首次登入新手禮包（選用）
+	ChatFilter    ChatFilter            // 聊天禁用字詞過濾（nil = 不過濾）
+	Getback       *data.GetbackTable    // 回城座標（死亡重新開始、回家卷軸、禁止脫出地圖登入）
+	Weather       *data.WeatherTable    // 地圖天氣與天氣效果（霧降低仇恨範圍、雨影響屬性魔法）
 	Ranking       RankingChecker // filled after RankingSystem is created
+	RateEvent     RateEventManager // filled after RateEventSystem is created
 }
 
 // RegisterAll registers all packet handlers into the registry.
@@ -494,7 +563,7 @@ func RegisterAll(reg *packet.Registry, deps *Deps) {
 	)
 	reg.Register(packet.C_OPCODE_DUEL, inWorldStates,
 		func(sess any, r *packet.Reader) {
-			HandleDuel(sess.(*net.Session), r, deps)
+			HandleDuelRequest(sess.(*net.Session), r, deps)
 		},
 	)
 	reg.Register(packet.C_OPCODE_CHAR_RESET, inWorldStates,
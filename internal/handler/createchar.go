@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/l1jgo/server/internal/net"
@@ -38,7 +39,7 @@ func HandleCreateChar(sess *net.Session, r *packet.Reader, deps *Deps) {
 	intel := int16(r.ReadC())
 
 	// Validate name
-	if len(name) == 0 {
+	if len(name) == 0 || isBannedCharName(name, deps) {
 		sendCharCreateStatus(sess, charCreateInvalidName)
 		return
 	}
@@ -220,3 +221,15 @@ func sendNewCharPack(sess *net.Session, c *persist.CharacterRow) {
 	w.WriteC(xor)
 	sess.Send(w.Bytes())
 }
+
+// isBannedCharName 檢查角色名稱是否含有設定檔中的禁用字（不分大小寫）。
+// 建立角色與 GM 改名共用。
+func isBannedCharName(name string, deps *Deps) bool {
+	lower := strings.ToLower(name)
+	for _, w := range deps.Config.Character.BannedNameWords {
+		if w != "" && strings.Contains(lower, strings.ToLower(w)) {
+			return true
+		}
+	}
+	return false
+}
@@ -42,15 +42,15 @@ func HandleDeleteChar(sess *net.Session, r *packet.Reader, deps *Deps) {
 
 	cfg := deps.Config.Character
 
-	// Determine immediate vs 7-day delayed deletion
+	// Determine immediate vs delayed deletion (grace period, restorable by GM)
 	if cfg.Delete7Days && ch.Level >= int16(cfg.Delete7DaysMinLevel) {
-		// Soft delete (7 day delay)
-		if err := deps.CharRepo.SoftDelete(ctx, charName); err != nil {
+		// Soft delete (grace period)
+		if err := deps.CharRepo.SoftDelete(ctx, charName, cfg.DeleteGraceDays); err != nil {
 			deps.Log.Error("角色軟刪除失敗", zap.Error(err))
 			return
 		}
 		sendDeleteCharResult(sess, 0x51) // delayed
-		deps.Log.Info(fmt.Sprintf("角色已軟刪除 (7天)  角色=%s  等級=%d", charName, ch.Level))
+		deps.Log.Info(fmt.Sprintf("角色已軟刪除 (%d天)  角色=%s  等級=%d", cfg.DeleteGraceDays, charName, ch.Level))
 	} else {
 		// Hard delete (immediate)
 		if err := deps.CharRepo.HardDelete(ctx, charName); err != nil {
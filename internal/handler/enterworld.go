@@ -42,6 +42,14 @@ func HandleEnterWorld(sess *net.Session, r *packet.Reader, deps *Deps) {
 
 	deps.Log.Info(fmt.Sprintf("角色進入世界  帳號=%s  角色=%s", sess.AccountName, charName))
 
+	// 斷線保留中的同一角色：接回原本的 PlayerInfo（戰鬥與狀態延續，不從 DB 重新載入）
+	if deps.LinkDead != nil {
+		if held := deps.LinkDead.Reattach(sess, ch.ID); held != nil {
+			resumeLinkDead(sess, held, ch, deps)
+			return
+		}
+	}
+
 	// Register player in world state
 	player := &world.PlayerInfo{
 		SessionID: sess.ID,
@@ -72,26 +80,47 @@ func HandleEnterWorld(sess *net.Session, r *packet.Reader, deps *Deps) {
 		Cha:       ch.Cha,
 		Exp:        int32(ch.Exp),
 		BonusStats:  ch.BonusStats,
+		BonusAlloc:  ch.BonusAlloc,
 		ElixirStats: ch.ElixirStats,
 		Food:         ch.Food, // 從 DB 載入飽食度
+		RestedXP:     ch.RestedXP,
 		FoodFullTime: -1,     // 登入時重置生存吶喊計時（Java: _h_time = -1）
 		PKCount:     ch.PKCount,
 		Karma:       ch.Karma,
 		AttackView: true, // Java: is_attack_view 預設啟用浮動傷害數字
+		AccessLevel: ch.AccessLevel,
+		RefuseWhisper: ch.RefuseWhisper,
+		RefuseParty:   ch.RefuseParty,
+		RefuseTrade:   ch.RefuseTrade,
+		RefuseDuel:    ch.RefuseDuel,
 		Inv:        world.NewInventory(),
 	}
-	// 載入帳號的倉庫密碼
+	// 載入帳號 ID 與倉庫密碼（高級帳號狀態同樣取自此列）
+	var acct *persist.AccountRow
 	if deps.AccountRepo != nil {
-		acct, acctErr := deps.AccountRepo.Load(ctx, sess.AccountName)
+		var acctErr error
+		acct, acctErr = deps.AccountRepo.Load(ctx, sess.AccountName)
 		if acctErr == nil && acct != nil {
+			player.AccountID = acct.ID
 			player.WarehousePassword = acct.WarehousePassword
+			if acct.AccessLevel > player.AccessLevel {
+				player.AccessLevel = acct.AccessLevel
+			}
 		}
 	}
 
+	// 禁止脫出的地圖登入時強制回城（Java: GetbackTable），GM 除外
+	recallFromNonEscapableMap(player, ch, deps)
+
 	deps.World.AddPlayer(player)
 
-	// Load inventory from DB (or give starting gold if empty)
+	// Load inventory from DB
 	loadInventoryFromDB(player, deps)
+	// 合併重複堆疊（背包清單尚未送出，不需通知客戶端）
+	ConsolidateInventory(nil, player)
+
+	// 首次登入：發放新手禮包（與背包同一交易存檔並清除旗標）
+	firstLogin := ch.FirstLogin && grantStarterKit(player, deps)
 
 	// Load bookmarks from DB (JSONB column)
 	loadBookmarksFromDB(player, deps)
@@ -99,6 +128,9 @@ func HandleEnterWorld(sess *net.Session, r *packet.Reader, deps *Deps) {
 	// Load known spells from DB (JSONB column)
 	loadKnownSpellsFromDB(player, deps)
 
+	// 從 DB 載入掉落物過濾清單（JSONB column）
+	loadLootFilterFromDB(player, deps)
+
 	// 從 DB 載入限時地圖已使用時間（JSONB column）
 	loadMapTimesFromDB(player, deps)
 
@@ -116,8 +148,42 @@ func HandleEnterWorld(sess *net.Session, r *packet.Reader, deps *Deps) {
 	// Restore persisted buffs (including polymorph state)
 	loadAndRestoreBuffs(player, deps)
 
-	// --- 發送初始化封包（順序參考 Java C_LoginToServer）---
+	sendWorldEntry(sess, player, ch, deps)
+
+	// 登入保護（客戶端載入期間不被怪物攻擊）
+	grantSpawnProtect(player, deps)
+
+	// 高級帳號登入 buff
+	applyPremiumBuffs(player, acct, deps)
+
+	// S_GameTime — 最後發送，避免干擾客戶端初始化
+	sendGameTime(sess, world.GameTimeNow().Seconds())
+
+	// 未讀信件 / 待領包裹提示
+	if deps.Mail != nil {
+		deps.Mail.NotifyUnread(sess, player)
+	}
+
+	// 重新召喚登出前在外的寵物
+	if deps.PetLife != nil {
+		deps.PetLife.RestorePets(sess, player)
+	}
+
+	// 伺服器倍率
+	sendLoginRates(sess, deps)
+
+	// 休息經驗（離線期間累積）
+	accrueRestedExp(player, ch.LastLogout, deps)
+
+	// 首次登入歡迎訊息
+	if firstLogin && deps.StarterKit.WelcomeMessage > 0 {
+		sendServerMessage(sess, deps.StarterKit.WelcomeMessage)
+	}
+}
 
+// sendWorldEntry 發送進入世界的初始化封包（順序參考 Java C_LoginToServer）、
+// 附近物件與 buff 圖示，並重建 Known 集合。新登入與接回斷線保留共用。
+func sendWorldEntry(sess *net.Session, player *world.PlayerInfo, ch *persist.CharacterRow, deps *Deps) {
 	// 1. S_ENTER_WORLD_CHECK (opcode 223) — LoginToGame
 	sendLoginGame(sess, ch.ClanID, ch.ID)
 
@@ -137,21 +203,13 @@ func HandleEnterWorld(sess *net.Session, r *packet.Reader, deps *Deps) {
 	sendMagicStatus(sess, byte(player.SP), uint16(player.MR))
 
 	// 7. S_WEATHER (opcode 115)
-	sendWeather(sess, deps.World.Weather)
+	sendWeather(sess, deps.World.WeatherAt(player.MapID).Code)
 
 	// 8. S_ABILITY_SCORES (opcode 174) — AC + 屬性抗性
 	sendAbilityScores(sess, player)
 
 	// 9. S_SkillList (opcode 164) — 已學魔法
-	if deps.Skills != nil {
-		var spells []*data.SkillInfo
-		for _, sid := range player.KnownSpells {
-			if sk := deps.Skills.Get(sid); sk != nil {
-				spells = append(spells, sk)
-			}
-		}
-		sendSkillList(sess, spells)
-	}
+	sendAllSpells(sess, player, deps)
 
 	// 9b. S_EquipmentSlot (opcode 64, sub-type 0x42) — 已裝備欄位
 	if deps.Equip != nil {
@@ -272,9 +330,43 @@ func HandleEnterWorld(sess *net.Session, r *packet.Reader, deps *Deps) {
 
 	// --- 恢復 buff 圖示（必須在所有初始化封包之後）---
 	sendRestoredBuffIcons(player, deps)
+}
 
-	// S_GameTime — 最後發送，避免干擾客戶端初始化
+// resumeLinkDead 接回斷線保留中的角色：以現有 PlayerInfo 重送進入世界封包，
+// HP/MP、buff、仇恨與位置延續斷線前的狀態，不從 DB 重新載入。
+func resumeLinkDead(sess *net.Session, player *world.PlayerInfo, ch *persist.CharacterRow, deps *Deps) {
+	// 自身外觀與附近物件以 DB 列的座標組成；改用記憶體中的即時資料
+	ch.X, ch.Y, ch.MapID, ch.Heading = player.X, player.Y, player.MapID, player.Heading
+	ch.Lawful, ch.Title = player.Lawful, player.Title
+	ch.ClanID, ch.ClanName, ch.ClanRank = player.ClanID, player.ClanName, player.ClanRank
+
+	sendWorldEntry(sess, player, ch, deps)
+	grantSpawnProtect(player, deps)
 	sendGameTime(sess, world.GameTimeNow().Seconds())
+	SendSystemMessage(sess, "已接回斷線前的角色。")
+}
+
+// sendLoginRates 登入時列出目前生效的經驗/掉寶/金幣倍率（[rates] login_notice）。
+// 活動進行中時顯示活動倍率與結束時間。
+func sendLoginRates(sess *net.Session, deps *Deps) {
+	if !deps.Config.Rates.LoginNotice {
+		return
+	}
+	var lines []string
+	if deps.RateEvent != nil {
+		lines = deps.RateEvent.Status()
+	} else {
+		r := &deps.Config.Rates
+		lines = []string{
+			fmt.Sprintf("經驗值: %.1f 倍", r.ExpRate),
+			fmt.Sprintf("掉寶: %.1f 倍", r.DropRate),
+			fmt.Sprintf("金幣: %.1f 倍", r.GoldRate),
+		}
+	}
+	SendSystemMessage(sess, "\\f2目前伺服器倍率")
+	for _, line := range lines {
+		SendSystemMessage(sess, line)
+	}
 }
 
 func sendLoginGame(sess *net.Session, clanID int32, clanMemberID int32) {
@@ -293,7 +385,7 @@ func sendLoginGame(sess *net.Session, clanID int32, clanMemberID int32) {
 	sess.Send(w.Bytes())
 }
 
-// loadInventoryFromDB loads saved items from DB, or gives starting gold if no items exist.
+// loadInventoryFromDB loads saved items from DB.
 func loadInventoryFromDB(player *world.PlayerInfo, deps *Deps) {
 	if deps.ItemRepo != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -314,7 +406,7 @@ func loadInventoryFromDB(player *world.PlayerInfo, deps *Deps) {
 					row.ItemID, row.Count, itemInfo.Name, itemInfo.InvGfx,
 					itemInfo.Weight, stackable, byte(row.Bless),
 				)
-				invItem.EnchantLvl = int8(row.EnchantLvl)
+				invItem.EnchantLvl = world.ClampEnchant(int(row.EnchantLvl))
 				invItem.Identified = row.Identified
 				invItem.UseType = itemInfo.UseTypeID
 				invItem.Durability = int8(row.Durability)
@@ -327,17 +419,100 @@ func loadInventoryFromDB(player *world.PlayerInfo, deps *Deps) {
 					}
 				}
 			}
-			return
 		}
 	}
+}
 
-	// No saved items — give starting gold (bless=1 = normal)
-	adenaInfo := deps.Items.Get(world.AdenaItemID)
-	if adenaInfo != nil {
-		player.Inv.AddItem(world.AdenaItemID, 20000, adenaInfo.Name, adenaInfo.InvGfx, 0, true, byte(adenaInfo.Bless))
-	} else {
-		player.Inv.AddItem(world.AdenaItemID, 20000, "金幣", 318, 0, true, 1)
+// grantStarterKit 首次登入發放新手禮包（data/yaml/starter_kit.yaml）。
+// 在背包列表送出前加入背包，並以單一交易存檔背包 + 清除 first_login，
+// 確保只發放一次；存檔失敗或旗標已被清除時撤回本次加入的物品。
+// 回傳 true 表示已發放。
+func grantStarterKit(player *world.PlayerInfo, deps *Deps) bool {
+	if deps.ItemRepo == nil {
+		return false
+	}
+	kit := starterKitFor(deps, player.ClassType)
+
+	// 整份禮包放不下時不發放並保留 first_login，下次登入再發；
+	// 避免只發放部分物品卻清除旗標而遺失其餘物品。
+	if need := starterKitSlots(player.Inv, kit, deps.Items); player.Inv.Size()+need > world.MaxInventorySize {
+		deps.Log.Warn("新手禮包: 背包空間不足，保留首次登入旗標",
+			zap.String("name", player.Name), zap.Int("need", need), zap.Int("used", player.Inv.Size()))
+		return false
+	}
+
+	type granted struct {
+		objectID int32
+		count    int32
+	}
+	added := make([]granted, 0, len(kit))
+	for _, it := range kit {
+		itemInfo := deps.Items.Get(it.ItemID)
+		if itemInfo == nil {
+			deps.Log.Warn("新手禮包: 未知的物品 ID", zap.Int32("item_id", it.ItemID))
+			continue
+		}
+		stackable := itemInfo.Stackable || it.ItemID == world.AdenaItemID
+		invItem := player.Inv.AddItem(
+			it.ItemID, it.Count, itemInfo.Name, itemInfo.InvGfx,
+			itemInfo.Weight, stackable, byte(itemInfo.Bless),
+		)
+		invItem.EnchantLvl = it.EnchantLvl
+		invItem.Identified = true
+		invItem.UseType = itemInfo.UseTypeID
+		added = append(added, granted{objectID: invItem.ObjectID, count: it.Count})
+	}
+	if len(added) == 0 {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ok, err := deps.ItemRepo.SaveFirstLoginInventory(ctx, player.CharID, player.Inv, &player.Equip)
+	if err != nil || !ok {
+		if err != nil {
+			deps.Log.Error("新手禮包存檔失敗", zap.String("name", player.Name), zap.Error(err))
+		}
+		for _, g := range added {
+			player.Inv.RemoveItem(g.objectID, g.count)
+		}
+		return false
+	}
+
+	deps.Log.Info(fmt.Sprintf("發放新手禮包  角色=%s  物品數=%d", player.Name, len(added)))
+	return true
+}
+
+// defaultStarterAdena 未設定新手禮包（starter_kit.yaml 缺檔或該職業無任何物品）時發放的起始金幣。
+const defaultStarterAdena = 20000
+
+// starterKitFor 回傳職業的新手禮包；未設定時退回 defaultStarterAdena 金幣。
+func starterKitFor(deps *Deps, classType int16) []data.StarterItem {
+	if kit := deps.StarterKit.ForClass(classType); len(kit) > 0 {
+		return kit
+	}
+	return []data.StarterItem{{ItemID: world.AdenaItemID, Count: defaultStarterAdena}}
+}
+
+// starterKitSlots 計算發放禮包需要的新背包格數：可堆疊物品併入既有（或禮包內先前）
+// 的堆疊時不佔新格，未知物品不發放。
+func starterKitSlots(inv *world.Inventory, kit []data.StarterItem, items *data.ItemTable) int {
+	need := 0
+	seen := make(map[int32]bool, len(kit))
+	for _, it := range kit {
+		info := items.Get(it.ItemID)
+		if info == nil {
+			continue
+		}
+		if info.Stackable || it.ItemID == world.AdenaItemID {
+			if seen[it.ItemID] || inv.FindByItemID(it.ItemID) != nil {
+				continue
+			}
+			seen[it.ItemID] = true
+		}
+		need++
 	}
+	return need
 }
 
 // loadBookmarksFromDB loads saved bookmarks from the JSONB column.
@@ -374,6 +549,44 @@ func loadKnownSpellsFromDB(player *world.PlayerInfo, deps *Deps) {
 	player.KnownSpells = spells
 }
 
+// recallFromNonEscapableMap 角色在禁止脫出的地圖（escapable=false）登入時，
+// 依回城座標表移至回城點。目的地與原地圖相同時維持原位（例如新手區）。
+func recallFromNonEscapableMap(player *world.PlayerInfo, ch *persist.CharacterRow, deps *Deps) {
+	if deps.MapData == nil || player.AccessLevel > 0 {
+		return
+	}
+	mi := deps.MapData.GetInfo(player.MapID)
+	if mi == nil || mi.Escapable {
+		return
+	}
+	loc := deps.Getback.Restart(player.MapID, player.Lawful)
+	if loc.MapID == player.MapID {
+		return
+	}
+	deps.Log.Info(fmt.Sprintf("禁止脫出地圖登入回城  角色=%s  地圖=%d → (%d,%d) 地圖=%d",
+		player.Name, player.MapID, loc.X, loc.Y, loc.MapID))
+	player.X, player.Y, player.MapID = loc.X, loc.Y, loc.MapID
+	ch.X, ch.Y, ch.MapID = loc.X, loc.Y, loc.MapID
+}
+
+// loadLootFilterFromDB 從 JSONB 欄位載入掉落物過濾清單。
+func loadLootFilterFromDB(player *world.PlayerInfo, deps *Deps) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	ids, err := deps.CharRepo.LoadLootFilter(ctx, player.Name)
+	if err != nil {
+		deps.Log.Error("載入掉落物過濾清單失敗", zap.String("name", player.Name), zap.Error(err))
+		return
+	}
+	if len(ids) > 0 {
+		player.LootFilter = make(map[int32]bool, len(ids))
+		for _, id := range ids {
+			player.LootFilter[id] = true
+		}
+	}
+}
+
 // loadMapTimesFromDB 從 JSONB 欄位載入限時地圖已使用時間。
 func loadMapTimesFromDB(player *world.PlayerInfo, deps *Deps) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// loadTestItems 以給定的 etcitem 清單建立物品表（武器、防具為空）。
+func loadTestItems(t *testing.T, etcItems string) *data.ItemTable {
+	t.Helper()
+	dir := t.TempDir()
+	write := func(name, body string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	items, err := data.LoadItemTable(write("w.yaml", "weapons: []\n"), write("a.yaml", "armors: []\n"), write("e.yaml", "items:\n"+etcItems))
+	if err != nil {
+		t.Fatalf("load items: %v", err)
+	}
+	return items
+}
+
+func TestStarterKitFallsBackToAdena(t *testing.T) {
+	deps := &Deps{}
+	kit := starterKitFor(deps, 1)
+	if len(kit) != 1 || kit[0].ItemID != world.AdenaItemID || kit[0].Count != defaultStarterAdena {
+		t.Fatalf("kit without starter_kit.yaml = %+v, want %d adena", kit, defaultStarterAdena)
+	}
+}
+
+func TestStarterKitSlots(t *testing.T) {
+	items := loadTestItems(t, `  - {item_id: 40308, name: 金幣, stackable: true}
+  - {item_id: 40029, name: 治癒藥水, stackable: true}
+  - {item_id: 40001, name: 燈}
+`)
+	kit := []data.StarterItem{
+		{ItemID: world.AdenaItemID, Count: 100},
+		{ItemID: 40029, Count: 5},
+		{ItemID: 40029, Count: 5}, // 同一堆疊
+		{ItemID: 40001, Count: 1},
+		{ItemID: 40001, Count: 1}, // 不可堆疊，各佔一格
+		{ItemID: 99999, Count: 1}, // 未知物品不發放
+	}
+	inv := world.NewInventory()
+	if got := starterKitSlots(inv, kit, items); got != 4 {
+		t.Fatalf("empty inventory: slots = %d, want 4", got)
+	}
+	inv.AddItem(world.AdenaItemID, 1, "金幣", 0, 0, true, 1)
+	if got := starterKitSlots(inv, kit, items); got != 3 {
+		t.Fatalf("with adena stack: slots = %d, want 3", got)
+	}
+}
@@ -2,8 +2,12 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"math/rand"
+	"net/netip"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -86,6 +90,8 @@ func HandleGMCommand(sess *net.Session, player *world.PlayerInfo, text string, d
 		gmUndoPoly(sess, player, args, deps)
 	case "loc", "pos", "coord":
 		gmLoc(sess, player, args, deps)
+	case "setinfo":
+		gmSetInfo(sess, player, args, deps)
 	case "wall":
 		gmWall(sess, player, args, deps)
 	case "clearwall":
@@ -101,7 +107,57 @@ func HandleGMCommand(sess *net.Session, player *world.PlayerInfo, text string, d
 	case "cleartest":
 		gmClearTest(sess, player, deps)
 	case "invisible":
-		gmInvisible(sess, player, deps)
+		if requireGM(sess, player) {
+			gmInvisible(sess, player, !player.GMInvisible, deps)
+		}
+	case "invis":
+		if requireGM(sess, player) {
+			gmInvisible(sess, player, true, deps)
+		}
+	case "vis":
+		if requireGM(sess, player) {
+			gmInvisible(sess, player, false, deps)
+		}
+	case "event":
+		if requireGM(sess, player) {
+			gmEvent(sess, args, deps)
+		}
+	case "rename":
+		if requireGM(sess, player) {
+			gmRename(sess, args, deps)
+		}
+	case "restorechar":
+		if requireGM(sess, player) {
+			gmRestoreChar(sess, args, deps)
+		}
+	case "msg":
+		if requireGM(sess, player) {
+			gmMessage(sess, args, deps)
+		}
+	case "ban":
+		if requireGM(sess, player) {
+			gmBan(sess, args, deps)
+		}
+	case "unban":
+		if requireGM(sess, player) {
+			gmUnban(sess, args, deps)
+		}
+	case "premium":
+		if requireGM(sess, player) {
+			gmPremium(sess, args, deps)
+		}
+	case "banip":
+		if requireGM(sess, player) {
+			gmBanIP(sess, args, deps)
+		}
+	case "unbanip":
+		if requireGM(sess, player) {
+			gmUnbanIP(sess, args, deps)
+		}
+	case "dump":
+		if requireGM(sess, player) {
+			gmDump(sess, deps)
+		}
 	default:
 		gmMsg(sess, "\\f3未知的GM指令: ."+cmd+"  輸入 .help 查看指令列表")
 	}
@@ -111,6 +167,15 @@ func HandleGMCommand(sess *net.Session, player *world.PlayerInfo, text string, d
 
 // --- Helper ---
 
+// requireGM 限 GM（access_level > 0）執行的指令；一般玩家收到提示並回傳 false。
+func requireGM(sess *net.Session, player *world.PlayerInfo) bool {
+	if player.IsGM() {
+		return true
+	}
+	gmMsg(sess, "\\f3此指令僅限 GM 使用。")
+	return false
+}
+
 func gmMsg(sess *net.Session, msg string) {
 	sendGlobalChat(sess, 9, msg) // type 9 = system message (green text)
 }
@@ -148,12 +213,30 @@ func gmHelp(sess *net.Session) {
 	gmMsg(sess, ".save  — 手動存檔")
 	gmMsg(sess, ".ac  — 顯示角色詳細資訊")
 	gmMsg(sess, ".loc [玩家名]  — 顯示自己或指定玩家的當下座標")
+	gmMsg(sess, ".setinfo [玩家名]  — 顯示自己或指定玩家啟動中的套裝與加成")
 	gmMsg(sess, ".wall [1|2|3]  — 測試牆壁: 1=隱形門 2=僅封包 3=可見門")
 	gmMsg(sess, ".clearwall  — 清除測試牆壁")
 	gmMsg(sess, ".buff <skillID>  — 強制套用buff(繞過驗證)")
 	gmMsg(sess, ".allbuff  — 套用所有常用buff")
 	gmMsg(sess, ".stresstest <npcID> [數量] [半徑]  — 壓力測試(預設10000隻,半徑50)")
 	gmMsg(sess, ".cleartest  — 清除所有壓力測試怪物")
+	gmMsg(sess, ".event [exp|drop|gold] <倍率> <時間>  — 開啟活動倍率(例: .event exp 2.0 2h)")
+	gmMsg(sess, ".event off  — 結束所有活動倍率")
+	gmMsg(sess, ".rename <舊名> <新名>  — 角色改名(線上/離線皆可)")
+	gmMsg(sess, ".restorechar <角色名>  — 恢復刪除等待期內的角色")
+	gmMsg(sess, ".msg <訊息ID> [參數...]  — 預覽 S_ServerMessage 組合結果並實際發送給自己")
+	gmMsg(sess, ".invis / .vis  — 開啟/關閉 GM 隱身(偵測術無效、怪物與一般玩家看不到)")
+	gmMsg(sess, ".ban <帳號> <時間|perm> <原因>  — 停權帳號並踢下線(例: .ban foo 3d 洗錢)")
+	gmMsg(sess, ".unban <帳號>  — 解除帳號停權")
+	gmMsg(sess, ".premium <帳號> <時間|off>  — 設定/取消高級帳號(例: .premium foo 30d)")
+	gmMsg(sess, ".banip <IP> <時間|perm> [原因]  — 加入 IP 封鎖列表")
+	gmMsg(sess, ".unbanip <IP>  — 移出 IP 封鎖列表")
+	gmMsg(sess, ".dump  — 匯出世界狀態快照(JSON)供除錯")
+	gmMsg(sess, "=== 玩家指令 ===")
+	gmMsg(sess, ".sit / .stand  — 坐下休息/起身")
+	gmMsg(sess, ".bossrank  — 頭目擊殺排行")
+	gmMsg(sess, ".refuse [whisper|party|trade|duel]  — 切換拒絕密語/組隊/交易/決鬥")
+	gmMsg(sess, ".filter [add|del <itemID>|clear]  — 掉落物過濾清單(清單內物品擊殺掉落時直接捨棄)")
 }
 
 func gmLevel(sess *net.Session, player *world.PlayerInfo, args []string, deps *Deps) {
@@ -463,20 +546,6 @@ func gmSpell(sess *net.Session, player *world.PlayerInfo, args []string, deps *D
 	gmMsgf(sess, "已學會技能: %s (ID:%d)", sk.Name, skillID)
 }
 
-// sendAllSpells re-sends the complete spell list to the client.
-func sendAllSpells(sess *net.Session, player *world.PlayerInfo, deps *Deps) {
-	if deps.Skills == nil {
-		return
-	}
-	var spells []*data.SkillInfo
-	for _, sid := range player.KnownSpells {
-		if sk := deps.Skills.Get(sid); sk != nil {
-			spells = append(spells, sk)
-		}
-	}
-	sendSkillList(sess, spells)
-}
-
 // classSkillLevels maps ClassType → SkillLevel ranges for that class.
 // L1J skill_level groups:
 //   1-10  = Wizard    11-12 = Royal(Prince)
@@ -564,24 +633,10 @@ func gmSpawn(sess *net.Session, player *world.PlayerInfo, args []string, deps *D
 
 	for i := 0; i < count; i++ {
 		// Spawn near player with slight random offset
-		x := player.X + int32(rand.Intn(5)) - 2
-		y := player.Y + int32(rand.Intn(5)) - 2
-
-		atkSpeed := tmpl.AtkSpeed
-		moveSpeed := tmpl.PassiveSpeed
-		if deps.SprTable != nil {
-			gfx := int(tmpl.GfxID)
-			if tmpl.AtkSpeed != 0 {
-				if v := deps.SprTable.GetAttackSpeed(gfx, data.ActAttack); v > 0 {
-					atkSpeed = int16(v)
-				}
-			}
-			if tmpl.PassiveSpeed != 0 {
-				if v := deps.SprTable.GetMoveSpeed(gfx, data.ActWalk); v > 0 {
-					moveSpeed = int16(v)
-				}
-			}
-		}
+		x := player.X + int32(world.RandInt(5)) - 2
+		y := player.Y + int32(world.RandInt(5)) - 2
+
+		speeds := deps.SprTable.ResolveNpcSpeeds(tmpl)
 
 		npc := &world.NpcInfo{
 			ID:           world.NextNpcID(),
@@ -590,11 +645,12 @@ func gmSpawn(sess *net.Session, player *world.PlayerInfo, args []string, deps *D
 			GfxID:        tmpl.GfxID,
 			Name:         tmpl.Name,
 			NameID:       tmpl.NameID,
+			Title:        tmpl.Title,
 			Level:        tmpl.Level,
 			X:            x,
 			Y:            y,
 			MapID:        player.MapID,
-			Heading:      int16(rand.Intn(8)),
+			Heading:      int16(world.RandInt(8)),
 			HP:           tmpl.HP,
 			MaxHP:        tmpl.HP,
 			MP:           tmpl.MP,
@@ -608,11 +664,22 @@ func gmSpawn(sess *net.Session, player *world.PlayerInfo, args []string, deps *D
 			MR:           tmpl.MR,
 			Undead:       tmpl.Undead,
 			Agro:         tmpl.Agro,
+			Tameable:     tmpl.Tameable,
 			AtkDmg:       int32(tmpl.Level) + int32(tmpl.STR)/3,
 			Ranged:       tmpl.Ranged,
-			AtkSpeed:     atkSpeed,
-			MoveSpeed:    moveSpeed,
+			AtkSpeed:     speeds.Atk,
+			MoveSpeed:    speeds.Move,
+			RunSpeed:     speeds.Run,
+			RangedAtkSpeed: speeds.RangedAtk,
 			PoisonAtk:    tmpl.PoisonAtk,
+			WanderRadius: tmpl.WanderRadius,
+			ImmuneParalyze: tmpl.ImmuneParalyze,
+			ImmuneSleep:    tmpl.ImmuneSleep,
+			ImmunePoison:   tmpl.ImmunePoison,
+			FireRes:        tmpl.FireRes,
+			WaterRes:       tmpl.WaterRes,
+			WindRes:        tmpl.WindRes,
+			EarthRes:       tmpl.EarthRes,
 			SpawnX:       x,
 			SpawnY:       y,
 			SpawnMapID:   player.MapID,
@@ -899,10 +966,12 @@ func gmSave(sess *net.Session, player *world.PlayerInfo, deps *Deps) {
 		Cha:        player.Cha,
 		Intel:      player.Intel,
 		BonusStats: player.BonusStats,
+		BonusAlloc: player.BonusAlloc,
 		ClanID:     player.ClanID,
 		ClanName:   player.ClanName,
 		ClanRank:   player.ClanRank,
 		Title:      player.Title,
+		RestedXP:   player.RestedXP,
 	}
 	if err := deps.CharRepo.SaveCharacter(ctx, row); err != nil {
 		gmMsgf(sess, "\\f3存檔失敗: %v", err)
@@ -1106,6 +1175,59 @@ func gmLoc(sess *net.Session, player *world.PlayerInfo, args []string, deps *Dep
 		target.Name, target.X, target.Y, target.MapID, target.Heading)
 }
 
+// gmSetInfo 顯示玩家啟動中的套裝、組成物品與套裝加成（除錯用）。
+func gmSetInfo(sess *net.Session, player *world.PlayerInfo, args []string, deps *Deps) {
+	target := player
+	if len(args) >= 1 {
+		target = deps.World.GetByName(args[0])
+		if target == nil {
+			gmMsgf(sess, "\\f3找不到玩家: %s", args[0])
+			return
+		}
+	}
+	if target.ActiveSetID == 0 || deps.ArmorSets == nil {
+		gmMsgf(sess, "[%s] 沒有啟動中的套裝", target.Name)
+		return
+	}
+	set := deps.ArmorSets.GetByID(target.ActiveSetID)
+	if set == nil {
+		gmMsgf(sess, "\\f3[%s] 套裝 #%d 不存在於套裝表", target.Name, target.ActiveSetID)
+		return
+	}
+	gmMsgf(sess, "[%s] 套裝 #%d %s  變身: %d", target.Name, set.ID, set.Name, set.PolyID)
+
+	items := make([]string, 0, len(set.Items))
+	for _, id := range set.Items {
+		name := strconv.Itoa(int(id))
+		if info := deps.Items.Get(id); info != nil {
+			name = info.Name
+		}
+		items = append(items, name)
+	}
+	gmMsg(sess, "組成: "+strings.Join(items, ", "))
+
+	var bonus []string
+	for _, b := range []struct {
+		label string
+		v     int
+	}{
+		{"AC", set.AC}, {"HP", set.HP}, {"MP", set.MP}, {"HPR", set.HPR}, {"MPR", set.MPR},
+		{"MR", set.MR}, {"SP", set.SP}, {"STR", set.Str}, {"DEX", set.Dex}, {"CON", set.Con},
+		{"WIS", set.Wis}, {"INT", set.Intl}, {"CHA", set.Cha}, {"命中", set.Hit}, {"傷害", set.Dmg},
+		{"遠程命中", set.BowHit}, {"遠程傷害", set.BowDmg}, {"水抗", set.DefWater},
+		{"風抗", set.DefWind}, {"火抗", set.DefFire}, {"地抗", set.DefEarth},
+	} {
+		if b.v != 0 {
+			bonus = append(bonus, fmt.Sprintf("%s%+d", b.label, b.v))
+		}
+	}
+	if len(bonus) == 0 {
+		gmMsg(sess, "加成: 無（僅變身）")
+		return
+	}
+	gmMsg(sess, "加成: "+strings.Join(bonus, "  "))
+}
+
 // gmWall creates a collision wall (door) at the facing tile for testing.
 // Usage: .wall [mode]
 //   mode 1 (default): S_DoorPack(GfxId=0) + S_CHANGE_ATTR + S_REMOVE_OBJECT (invisible test)
@@ -1259,12 +1381,52 @@ func gmWeather(sess *net.Session, _ *world.PlayerInfo, args []string, deps *Deps
 		return
 	}
 	deps.World.Weather = byte(val)
+	deps.World.ClearMapWeather() // GM 指定天氣覆蓋各地圖天氣，直到下一個遊戲整點
 	deps.World.AllPlayers(func(p *world.PlayerInfo) {
 		sendWeather(p.Session, byte(val))
 	})
 	gmMsgf(sess, "天氣已變更為 %d", val)
 }
 
+// gmEvent 查詢或開關活動倍率。
+// 用法: .event（查詢） / .event <exp|drop|gold> <倍率> <時間> / .event off
+func gmEvent(sess *net.Session, args []string, deps *Deps) {
+	if deps.RateEvent == nil {
+		gmMsg(sess, "\\f3活動倍率系統未啟用")
+		return
+	}
+	if len(args) == 0 {
+		for _, line := range deps.RateEvent.Status() {
+			gmMsg(sess, line)
+		}
+		return
+	}
+	if strings.ToLower(args[0]) == "off" {
+		deps.RateEvent.StopEvent()
+		gmMsg(sess, "已結束所有活動倍率")
+		return
+	}
+	if len(args) < 3 {
+		gmMsg(sess, "\\f3用法: .event <exp|drop|gold> <倍率> <時間>  (例: .event exp 2.0 2h)")
+		return
+	}
+	rate, err := strconv.ParseFloat(args[1], 64)
+	if err != nil || rate <= 0 {
+		gmMsg(sess, "\\f3無效的倍率")
+		return
+	}
+	dur, err := time.ParseDuration(args[2])
+	if err != nil || dur <= 0 {
+		gmMsg(sess, "\\f3無效的時間（例: 30m, 2h）")
+		return
+	}
+	if !deps.RateEvent.StartEvent(strings.ToLower(args[0]), rate, dur) {
+		gmMsg(sess, "\\f3種類必須為 exp、drop 或 gold")
+		return
+	}
+	gmMsgf(sess, "活動倍率已開啟: %s %.1f 倍，持續 %s", args[0], rate, dur)
+}
+
 // gmStressTest 一次生成大量怪物用於壓力測試。
 // 用法: .stresstest <npcID> [數量] [半徑]
 // 怪物分散在玩家周圍，不會重生（關服即消失）。
@@ -1311,35 +1473,21 @@ func gmStressTest(sess *net.Session, player *world.PlayerInfo, args []string, de
 	}
 
 	// 查詢動畫速度（只查一次，所有 NPC 共用）
-	atkSpeed := tmpl.AtkSpeed
-	moveSpeed := tmpl.PassiveSpeed
-	if deps.SprTable != nil {
-		gfx := int(tmpl.GfxID)
-		if tmpl.AtkSpeed != 0 {
-			if v := deps.SprTable.GetAttackSpeed(gfx, data.ActAttack); v > 0 {
-				atkSpeed = int16(v)
-			}
-		}
-		if tmpl.PassiveSpeed != 0 {
-			if v := deps.SprTable.GetMoveSpeed(gfx, data.ActWalk); v > 0 {
-				moveSpeed = int16(v)
-			}
-		}
-	}
+	speeds := deps.SprTable.ResolveNpcSpeeds(tmpl)
 
 	gmMsgf(sess, "開始生成 %d 隻 %s（半徑 %d 格）...", count, tmpl.Name, radius)
 
 	spawned := 0
 	for i := 0; i < count; i++ {
-		x := player.X + int32(rand.Intn(int(radius*2+1))) - radius
-		y := player.Y + int32(rand.Intn(int(radius*2+1))) - radius
+		x := player.X + int32(world.RandInt(int(radius*2+1))) - radius
+		y := player.Y + int32(world.RandInt(int(radius*2+1))) - radius
 
 		// 可行走性檢查（最多重試 3 次）
 		if deps.MapData != nil {
 			ok := deps.MapData.IsPassablePoint(player.MapID, x, y)
 			for retry := 0; !ok && retry < 3; retry++ {
-				x = player.X + int32(rand.Intn(int(radius*2+1))) - radius
-				y = player.Y + int32(rand.Intn(int(radius*2+1))) - radius
+				x = player.X + int32(world.RandInt(int(radius*2+1))) - radius
+				y = player.Y + int32(world.RandInt(int(radius*2+1))) - radius
 				ok = deps.MapData.IsPassablePoint(player.MapID, x, y)
 			}
 			if !ok {
@@ -1354,11 +1502,12 @@ func gmStressTest(sess *net.Session, player *world.PlayerInfo, args []string, de
 			GfxID:        tmpl.GfxID,
 			Name:         tmpl.Name,
 			NameID:       tmpl.NameID,
+			Title:        tmpl.Title,
 			Level:        tmpl.Level,
 			X:            x,
 			Y:            y,
 			MapID:        player.MapID,
-			Heading:      int16(rand.Intn(8)),
+			Heading:      int16(world.RandInt(8)),
 			HP:           tmpl.HP,
 			MaxHP:        tmpl.HP,
 			MP:           tmpl.MP,
@@ -1372,11 +1521,22 @@ func gmStressTest(sess *net.Session, player *world.PlayerInfo, args []string, de
 			MR:           tmpl.MR,
 			Undead:       tmpl.Undead,
 			Agro:         tmpl.Agro,
+			Tameable:     tmpl.Tameable,
 			AtkDmg:       int32(tmpl.Level) + int32(tmpl.STR)/3,
 			Ranged:       tmpl.Ranged,
-			AtkSpeed:     atkSpeed,
-			MoveSpeed:    moveSpeed,
+			AtkSpeed:     speeds.Atk,
+			MoveSpeed:    speeds.Move,
+			RunSpeed:     speeds.Run,
+			RangedAtkSpeed: speeds.RangedAtk,
 			PoisonAtk:    tmpl.PoisonAtk,
+			WanderRadius: tmpl.WanderRadius,
+			ImmuneParalyze: tmpl.ImmuneParalyze,
+			ImmuneSleep:    tmpl.ImmuneSleep,
+			ImmunePoison:   tmpl.ImmunePoison,
+			FireRes:        tmpl.FireRes,
+			WaterRes:       tmpl.WaterRes,
+			WindRes:        tmpl.WindRes,
+			EarthRes:       tmpl.EarthRes,
 			SpawnX:       x,
 			SpawnY:       y,
 			SpawnMapID:   player.MapID,
@@ -1460,18 +1620,30 @@ func gmAllBuff(sess *net.Session, player *world.PlayerInfo, deps *Deps) {
 	gmMsgf(sess, "\\f=已套用 %d 個常用 buff", count)
 }
 
-// gmInvisible 切換 GM 隱身狀態（不受 Cancellation 影響的純旗標隱身）。
-func gmInvisible(sess *net.Session, player *world.PlayerInfo, deps *Deps) {
-	player.Invisible = !player.Invisible
-	SendInvisible(sess, player.CharID, player.Invisible)
+// gmInvisible 設定 GM 隱身狀態（不受 Cancellation 與偵測術影響的純旗標隱身）。
+// GM 隱身期間怪物/守衛不會索敵，非 GM 玩家看不到；其他 GM 仍看得到。
+func gmInvisible(sess *net.Session, player *world.PlayerInfo, on bool, deps *Deps) {
+	if player.GMInvisible == on {
+		if on {
+			gmMsg(sess, "\\f3GM 隱身已是開啟狀態。")
+		} else {
+			gmMsg(sess, "\\f3GM 隱身已是關閉狀態。")
+		}
+		return
+	}
+	player.GMInvisible = on
+	player.Invisible = on
+	SendInvisible(sess, player.CharID, on)
 
 	ws := deps.World
 	nearby := ws.GetNearbyPlayers(player.X, player.Y, player.MapID, sess.ID)
 
-	if player.Invisible {
-		// 隱身：周圍玩家移除我的角色顯示
+	if on {
+		// 隱身：周圍非 GM 玩家移除我的角色顯示
 		for _, other := range nearby {
-			SendRemoveObject(other.Session, player.CharID)
+			if !other.IsGM() {
+				SendRemoveObject(other.Session, player.CharID)
+			}
 		}
 		gmMsg(sess, "\\f2GM 隱身已開啟。")
 	} else {
@@ -1482,3 +1654,328 @@ func gmInvisible(sess *net.Session, player *world.PlayerInfo, deps *Deps) {
 		gmMsg(sess, "\\f2GM 隱身已關閉。")
 	}
 }
+
+// gmRename 角色改名。DB 內所有以名稱儲存的欄位一併更新；
+// 角色若在線上，同步更新世界名稱索引並重送外觀給附近玩家。
+// 用法: .rename <舊名> <新名>
+func gmRename(sess *net.Session, args []string, deps *Deps) {
+	if len(args) < 2 {
+		gmMsg(sess, "\\f3用法: .rename <舊名> <新名>")
+		return
+	}
+	oldName, newName := args[0], args[1]
+	if isBannedCharName(newName, deps) {
+		gmMsgf(sess, "\\f3名稱「%s」含有禁用字", newName)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	charID, err := deps.CharRepo.Rename(ctx, oldName, newName)
+	switch {
+	case errors.Is(err, persist.ErrNameTaken):
+		gmMsgf(sess, "\\f3名稱「%s」已被使用", newName)
+		return
+	case errors.Is(err, persist.ErrCharNotFound):
+		gmMsgf(sess, "\\f3找不到角色「%s」", oldName)
+		return
+	case err != nil:
+		deps.Log.Error("角色改名失敗", zap.Error(err))
+		gmMsg(sess, "\\f3改名失敗")
+		return
+	}
+
+	// 記憶體中的血盟名冊
+	if clanID := deps.World.Clans.GetPlayerClanID(charID); clanID > 0 {
+		if clan := deps.World.Clans.GetClan(clanID); clan != nil {
+			if m := clan.Members[charID]; m != nil {
+				m.CharName = newName
+			}
+			if clan.LeaderID == charID {
+				clan.LeaderName = newName
+			}
+		}
+	}
+
+	// 線上玩家的好友列表
+	deps.World.AllPlayers(func(p *world.PlayerInfo) {
+		for i := range p.Buddies {
+			if p.Buddies[i].CharID == charID {
+				p.Buddies[i].Name = newName
+			}
+		}
+	})
+
+	// 目標在線上：更新名稱索引並刷新外觀
+	if target := deps.World.GetByName(oldName); target != nil {
+		deps.World.RenamePlayer(target, newName)
+		for _, viewer := range deps.World.GetNearbyPlayers(target.X, target.Y, target.MapID, target.SessionID) {
+			SendPutObject(viewer.Session, target)
+		}
+		SendPutObject(target.Session, target)
+		gmMsgf(target.Session, "你的角色名稱已變更為「%s」", newName)
+	}
+
+	deps.Log.Info(fmt.Sprintf("GM 角色改名  舊名=%s  新名=%s  charID=%d", oldName, newName, charID))
+	gmMsgf(sess, "角色「%s」已改名為「%s」", oldName, newName)
+}
+
+// gmRestoreChar 恢復刪除等待期內的角色。
+// 用法: .restorechar <角色名>
+func gmRestoreChar(sess *net.Session, args []string, deps *Deps) {
+	if len(args) < 1 {
+		gmMsg(sess, "\\f3用法: .restorechar <角色名>")
+		return
+	}
+	name := args[0]
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ok, err := deps.CharRepo.Restore(ctx, name)
+	if err != nil {
+		deps.Log.Error("恢復角色失敗", zap.Error(err))
+		gmMsg(sess, "\\f3恢復角色失敗")
+		return
+	}
+	if !ok {
+		gmMsgf(sess, "\\f3角色「%s」不在刪除等待期內", name)
+		return
+	}
+
+	deps.Log.Info(fmt.Sprintf("GM 恢復角色  角色=%s", name))
+	gmMsgf(sess, "角色「%s」已恢復", name)
+}
+
+// gmMessage 預覽伺服器訊息：顯示字串表組合結果，並實際發送一次供對照客戶端顯示。
+func gmMessage(sess *net.Session, args []string, deps *Deps) {
+	if len(args) < 1 {
+		gmMsg(sess, "\\f3用法: .msg <訊息ID> [參數...]")
+		return
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil || id < 0 || id > 0xFFFF {
+		gmMsg(sess, "\\f3無效的訊息ID")
+		return
+	}
+	msgID := uint16(id)
+	msgArgs := args[1:]
+
+	if _, ok := deps.Messages.Get(msgID); !ok {
+		gmMsgf(sess, "\\f3訊息 %d 不在字串表中，僅發送原始封包", msgID)
+	} else if err := deps.Messages.Validate(msgID, len(msgArgs)); err != nil {
+		gmMsgf(sess, "\\f3參數數量不符: 需要 %d 個，提供 %d 個", deps.Messages.ArgCount(msgID), len(msgArgs))
+	}
+	gmMsgf(sess, "\\f2預覽: %s", deps.Messages.Preview(msgID, msgArgs...))
+	sendServerMessageArgs(sess, msgID, msgArgs...)
+}
+
+// parseBanDuration 解析停權時間：perm/0 = 永久（回傳 nil），
+// 其餘接受 time.ParseDuration 格式並額外支援天數（例: 3d）。
+func parseBanDuration(arg string) (*time.Time, error) {
+	if arg == "perm" || arg == "0" {
+		return nil, nil
+	}
+	var dur time.Duration
+	if days, ok := strings.CutSuffix(arg, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return nil, err
+		}
+		dur = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err := time.ParseDuration(arg)
+		if err != nil {
+			return nil, err
+		}
+		dur = d
+	}
+	if dur <= 0 {
+		return nil, errors.New("duration must be positive")
+	}
+	until := time.Now().Add(dur)
+	return &until, nil
+}
+
+// kickAccount 關閉帳號目前的連線（遊戲中、角色列表或登入排隊）。
+// 清理與存檔由 InputSystem.handleDisconnect 處理。回傳是否有連線被關閉。
+func kickAccount(account, notice string, deps *Deps) bool {
+	// 斷線保留中的角色直接存檔移出（連線已關閉）；同帳號的新連線仍須關閉
+	held := deps.LinkDead != nil && deps.LinkDead.Reclaim(account)
+	target := deps.World.Logins.SessionOf(account)
+	if target == nil {
+		return held
+	}
+	gmMsg(target, notice)
+	target.Logout = true // 踢除不進入斷線保留
+	target.Close()
+	return true
+}
+
+// gmBan 停權帳號並立即踢下線。
+// 用法: .ban <帳號> <時間|perm> <原因>
+func gmBan(sess *net.Session, args []string, deps *Deps) {
+	if len(args) < 3 {
+		gmMsg(sess, "\\f3用法: .ban <帳號> <時間|perm> <原因>  (時間例: 30m, 12h, 7d)")
+		return
+	}
+	account := strings.ToLower(args[0])
+	until, err := parseBanDuration(args[1])
+	if err != nil {
+		gmMsgf(sess, "\\f3無效的時間: %s", args[1])
+		return
+	}
+	reason := strings.Join(args[2:], " ")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ok, err := deps.AccountRepo.Ban(ctx, account, until, reason)
+	if err != nil {
+		deps.Log.Error("帳號停權失敗", zap.Error(err))
+		gmMsg(sess, "\\f3停權失敗")
+		return
+	}
+	if !ok {
+		gmMsgf(sess, "\\f3找不到帳號「%s」", account)
+		return
+	}
+
+	notice := banNotice("帳號已停權", until, reason)
+	kicked := kickAccount(account, notice, deps)
+	deps.Log.Info(fmt.Sprintf("GM 帳號停權  帳號=%s  期限=%s  原因=%s  踢線=%v  GM=%s",
+		account, args[1], reason, kicked, sess.AccountName))
+	gmMsgf(sess, "帳號「%s」%s", account, notice)
+}
+
+// gmUnban 解除帳號停權。
+// 用法: .unban <帳號>
+func gmUnban(sess *net.Session, args []string, deps *Deps) {
+	if len(args) < 1 {
+		gmMsg(sess, "\\f3用法: .unban <帳號>")
+		return
+	}
+	account := strings.ToLower(args[0])
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ok, err := deps.AccountRepo.Unban(ctx, account)
+	if err != nil {
+		deps.Log.Error("解除停權失敗", zap.Error(err))
+		gmMsg(sess, "\\f3解除停權失敗")
+		return
+	}
+	if !ok {
+		gmMsgf(sess, "\\f3找不到帳號「%s」", account)
+		return
+	}
+
+	deps.Log.Info(fmt.Sprintf("GM 解除停權  帳號=%s  GM=%s", account, sess.AccountName))
+	gmMsgf(sess, "帳號「%s」已解除停權", account)
+}
+
+// gmBanIP 將 IP 加入封鎖列表，並踢除該 IP 目前所有連線。
+// 用法: .banip <IP> <時間|perm> [原因]
+func gmBanIP(sess *net.Session, args []string, deps *Deps) {
+	if len(args) < 2 {
+		gmMsg(sess, "\\f3用法: .banip <IP> <時間|perm> [原因]")
+		return
+	}
+	ip := args[0]
+	if _, err := netip.ParseAddr(ip); err != nil {
+		gmMsgf(sess, "\\f3無效的 IP: %s", ip)
+		return
+	}
+	until, err := parseBanDuration(args[1])
+	if err != nil {
+		gmMsgf(sess, "\\f3無效的時間: %s", args[1])
+		return
+	}
+	reason := strings.Join(args[2:], " ")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := deps.AccountRepo.BanIP(ctx, ip, until, reason); err != nil {
+		deps.Log.Error("IP 封鎖失敗", zap.Error(err))
+		gmMsg(sess, "\\f3IP 封鎖失敗")
+		return
+	}
+
+	notice := banNotice("此 IP 已被封鎖", until, reason)
+	kicked := 0
+	for _, account := range deps.World.Logins.AccountsFromIP(ip) {
+		if kickAccount(account, notice, deps) {
+			kicked++
+		}
+	}
+	deps.Log.Info(fmt.Sprintf("GM IP 封鎖  ip=%s  期限=%s  原因=%s  踢線=%d  GM=%s",
+		ip, args[1], reason, kicked, sess.AccountName))
+	gmMsgf(sess, "%s（踢除 %d 個連線）", notice, kicked)
+}
+
+// gmUnbanIP 將 IP 移出封鎖列表。
+// 用法: .unbanip <IP>
+func gmUnbanIP(sess *net.Session, args []string, deps *Deps) {
+	if len(args) < 1 {
+		gmMsg(sess, "\\f3用法: .unbanip <IP>")
+		return
+	}
+	ip := args[0]
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ok, err := deps.AccountRepo.UnbanIP(ctx, ip)
+	if err != nil {
+		deps.Log.Error("解除 IP 封鎖失敗", zap.Error(err))
+		gmMsg(sess, "\\f3解除 IP 封鎖失敗")
+		return
+	}
+	if !ok {
+		gmMsgf(sess, "\\f3IP %s 不在封鎖列表中", ip)
+		return
+	}
+
+	deps.Log.Info(fmt.Sprintf("GM 解除 IP 封鎖  ip=%s  GM=%s", ip, sess.AccountName))
+	gmMsgf(sess, "IP %s 已移出封鎖列表", ip)
+}
+
+// dumpDir 世界狀態快照輸出目錄。
+const dumpDir = "dumps"
+
+// dumpMaxPlayers 快照最多記錄的玩家數（限制檔案大小）。
+const dumpMaxPlayers = 5000
+
+// gmDump 匯出世界狀態快照（線上玩家、各地圖存活 NPC 數、地面物品數）到帶時間戳的 JSON 檔。
+// 快照在遊戲迴圈內建構（世界狀態唯一寫入者，資料一致）；序列化與寫檔在背景 goroutine，
+// 不阻塞 tick。結果記錄於伺服器日誌。
+// 用法: .dump
+func gmDump(sess *net.Session, deps *Deps) {
+	snap := deps.World.Snapshot(dumpMaxPlayers)
+	path := filepath.Join(dumpDir, "world-"+snap.TakenAt.Format("20060102-150405")+".json")
+
+	go func() {
+		raw, err := json.MarshalIndent(snap, "", "  ")
+		if err == nil {
+			err = os.MkdirAll(dumpDir, 0o755)
+		}
+		if err == nil {
+			err = os.WriteFile(path, raw, 0o644)
+		}
+		if err != nil {
+			deps.Log.Error("世界狀態快照寫入失敗", zap.String("path", path), zap.Error(err))
+			return
+		}
+		deps.Log.Info(fmt.Sprintf("世界狀態快照已寫入  檔案=%s  大小=%d", path, len(raw)))
+	}()
+
+	gmMsgf(sess, "世界狀態快照: %s（玩家 %d, NPC 存活 %d 張地圖, 地面物品 %d 張地圖）",
+		path, snap.PlayerCount, len(snap.NpcsAlive), len(snap.GroundItems))
+	if snap.Truncated {
+		gmMsgf(sess, "\\f3玩家數超過 %d，僅記錄前 %d 名", dumpMaxPlayers, dumpMaxPlayers)
+	}
+	deps.Log.Info(fmt.Sprintf("GM 匯出世界狀態快照  GM=%s  檔案=%s", sess.AccountName, path))
+}
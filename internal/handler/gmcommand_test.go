@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/l1jgo/server/internal/config"
+	"github.com/l1jgo/server/internal/world"
+	"go.uber.org/zap"
+)
+
+// 一般玩家執行管理指令時只收到 GM 限定提示，不得觸及 AccountRepo 與 World（nil：觸及即 panic）。
+func TestRestrictedCommandsRequireGM(t *testing.T) {
+	deps := &Deps{Config: &config.Config{}, Log: zap.NewNop()}
+	notice := newTestSession(t)
+	requireGM(notice, &world.PlayerInfo{Session: notice})
+	notice.FlushOutput()
+	want := <-notice.OutQueue
+	for _, text := range []string{
+		".ban gm 7d test",
+		".unban gm",
+		".banip 127.0.0.1 perm test",
+		".unbanip 127.0.0.1",
+		".dump",
+		".event exp 2 1h",
+		".rename gm player",
+		".restorechar gm",
+		".msg 1",
+		".premium player 30d",
+		".invisible",
+		".invis",
+		".vis",
+	} {
+		sess := newTestSession(t)
+		p := &world.PlayerInfo{Session: sess, Name: "player"}
+		if !HandleGMCommand(sess, p, text, deps) {
+			t.Errorf("%q not consumed", text)
+		}
+		sess.FlushOutput()
+		if len(sess.OutQueue) != 1 {
+			t.Errorf("%q: sent %d packets, want the GM-only notice", text, len(sess.OutQueue))
+			continue
+		}
+		if got := <-sess.OutQueue; !bytes.Equal(got, want) {
+			t.Errorf("%q: sent something other than the GM-only notice", text)
+		}
+	}
+}
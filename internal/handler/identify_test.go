@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// identifyDesc 組合預期的 S_IdentifyDesc 內容（不含 8 位元組補齊）。
+func identifyDesc(descID, format uint16, params ...string) []byte {
+	b := []byte{245, byte(descID), byte(descID >> 8), byte(format), byte(format >> 8), byte(len(params))}
+	for _, p := range params {
+		b = append(b, p...)
+		b = append(b, 0)
+	}
+	return b
+}
+
+func TestBuildIdentifyDescEtcItems(t *testing.T) {
+	tests := []struct {
+		name string
+		item *world.InvItem
+		info *data.ItemInfo
+		want []byte
+	}{
+		{
+			"wand shows full charges",
+			&world.InvItem{Count: 1, Bless: 1},
+			&data.ItemInfo{ItemDescID: 300, Name: "wand", Weight: 15000, MaxChargeCount: 15},
+			identifyDesc(300, 137, "wand", "15", "15"),
+		},
+		{
+			"food shows volume",
+			&world.InvItem{Count: 3, Bless: 1},
+			&data.ItemInfo{ItemDescID: 301, Name: "meat", Weight: 1000, FoodVolume: 20},
+			identifyDesc(301, 136, "meat", "20", "3"),
+		},
+		{
+			"plain etcitem",
+			&world.InvItem{Count: 1, Bless: 0},
+			&data.ItemInfo{ItemDescID: 302, Name: "rock", Weight: 500},
+			identifyDesc(302, 138, "$227 rock", "1"),
+		},
+	}
+	for _, tt := range tests {
+		got := buildIdentifyDesc(tt.item, tt.info)
+		if len(got)%8 != 0 || !bytes.HasPrefix(got, tt.want) || bytes.ContainsFunc(got[len(tt.want):], func(r rune) bool { return r != 0 }) {
+			t.Errorf("%s:\n got %v\nwant %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/l1jgo/server/internal/net"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// checkInstanceEntry 傳送門進入限制地圖（instance_maps.yaml）前檢查人數、隊伍與鑰匙。
+// 通過時依設定消耗鑰匙並回傳 true；拒絕時發送原因訊息並回傳 false。
+// 已在目的地地圖內（同地圖傳送門）不受限制；GM 不受限制。
+func checkInstanceEntry(sess *net.Session, player *world.PlayerInfo, dstMapID int16, deps *Deps) bool {
+	inst := deps.Instances.Get(dstMapID)
+	if inst == nil || player.MapID == dstMapID || player.AccessLevel > 0 {
+		return true
+	}
+
+	occupants := deps.World.MapPlayers(dstMapID)
+	if inst.MaxPlayers > 0 && len(occupants) >= inst.MaxPlayers {
+		SendSystemMessage(sess, "此區域人數已滿，請稍後再來。")
+		return false
+	}
+	if inst.OneParty && len(occupants) > 0 && !instanceSameGroup(player, occupants, deps) {
+		SendSystemMessage(sess, "其他隊伍正在此區域中，請稍後再來。")
+		return false
+	}
+
+	if inst.KeyItemID > 0 {
+		key := player.Inv.FindByItemID(inst.KeyItemID)
+		if key == nil {
+			name := fmt.Sprintf("#%d", inst.KeyItemID)
+			if info := deps.Items.Get(inst.KeyItemID); info != nil {
+				name = info.Name
+			}
+			SendSystemMessage(sess, fmt.Sprintf("需要持有「%s」才能進入。", name))
+			return false
+		}
+		if inst.ConsumeKey {
+			if player.Inv.RemoveItem(key.ObjectID, 1) {
+				sendRemoveInventoryItem(sess, key.ObjectID)
+			} else {
+				sendItemCountUpdate(sess, key)
+			}
+			sendWeightUpdate(sess, player)
+		}
+	}
+	return true
+}
+
+// instanceSameGroup 區域內所有玩家是否都與 player 同一隊伍（player 無隊伍時視為不同組）。
+func instanceSameGroup(player *world.PlayerInfo, occupants []*world.PlayerInfo, deps *Deps) bool {
+	party := deps.World.Parties.GetParty(player.CharID)
+	if party == nil {
+		return false
+	}
+	for _, o := range occupants {
+		if deps.World.Parties.GetParty(o.CharID) != party {
+			return false
+		}
+	}
+	return true
+}
@@ -17,6 +17,7 @@ import (
 const (
 	msgClassCannotUse uint16 = 264 // "你的職業無法使用此道具。"
 	msgLevelTooLow    uint16 = 318 // "等級 %0以上才可使用此道具。"
+	msgMapDisabled    uint16 = 563 // "你無法在這個地方使用。"
 )
 
 // hasItemDelay 檢查物品延遲是否在冷卻中。
@@ -57,6 +58,8 @@ const (
 	SkillStatusElfBrave         int32 = 1016 // 精靈餅乾 (brave type 3, atk speed 1.15x)
 	SkillStatusRiBrave          int32 = 1017 // 生命之樹果實 (DK/IL brave)
 	SkillStatusThirdSpeed       int32 = 1027 // 三段加速 (char speed 1.15x)
+	SkillStatusSpawnProtect     int32 = 1100 // 登入/傳送保護（伺服器自訂，非 Java 技能）
+	SkillStatusStatDrain        int32 = 1101 // 怪物命中特效：能力值暫時下降（伺服器自訂）
 
 	SkillDecayPotion int32 = 71 // 腐敗藥水 debuff — blocks all potion use
 	SkillCurseBlind  int32 = 10 // CURSE_BLIND — blind curse effect
@@ -144,15 +147,28 @@ func HandlePickupItem(sess *net.Session, r *packet.Reader, deps *Deps) {
 	_ = r.ReadH() // x（未使用，取伺服器座標）
 	_ = r.ReadH() // y（未使用）
 	objectID := r.ReadD()
-	_ = r.ReadD() // count（全撿）
+	count := r.ReadD() // 0 或整堆數量 = 全撿
 
 	player := deps.World.GetBySession(sess.ID)
 	if player == nil {
 		return
 	}
 
+	// 怪物屍體（loot_corpse 模式）改走拾取屍體流程
+	if g := deps.World.GetGroundItem(objectID); g != nil && g.IsCorpse() {
+		HandleLootCorpse(sess, player, objectID, deps)
+		return
+	}
+
+	if deps.ItemGround != nil {
+		deps.ItemGround.PickupItem(sess, player, objectID, count)
+	}
+}
+
+// HandleLootCorpse 拾取怪物屍體內的掉落物。委派給 ItemGroundSystem。
+func HandleLootCorpse(sess *net.Session, player *world.PlayerInfo, corpseID int32, deps *Deps) {
 	if deps.ItemGround != nil {
-		deps.ItemGround.PickupItem(sess, player, objectID)
+		deps.ItemGround.LootCorpse(sess, player, corpseID)
 	}
 }
 
@@ -183,6 +199,14 @@ func HandleUseItem(sess *net.Session, r *packet.Reader, deps *Deps) {
 		zap.String("type", itemInfo.Type),
 	)
 
+	// 地圖禁止使用道具（Java: L1Map.isUsableItem）；穿脫裝備不受限，GM 不受限
+	if itemInfo.Category == data.CategoryEtcItem && !player.IsGM() && deps.MapData != nil {
+		if mi := deps.MapData.GetInfo(player.MapID); mi != nil && !mi.UsableItem {
+			sendServerMessage(sess, msgMapDisabled)
+			return
+		}
+	}
+
 	// Teleport scrolls have additional data in the packet: [H mapID][D bookmarkID]
 	if isTeleportScroll(invItem.ItemID) {
 		if deps.ItemUse != nil {
@@ -292,6 +316,7 @@ func buildViewName(item *world.InvItem, itemInfo *data.ItemInfo) string {
 // Java: new S_ServerMessage(msgID, arg1, arg2, ...)
 // Wire format: [H msgID][C argCount][S arg1][S arg2]...
 func sendServerMessageS(sess *net.Session, msgID uint16, args ...string) {
+	checkMessageArgs(msgID, args)
 	w := packet.NewWriterWithOpcode(packet.S_OPCODE_MESSAGE_CODE)
 	w.WriteH(msgID)
 	w.WriteC(byte(len(args)))
@@ -371,6 +396,12 @@ func handleUseEtcItem(sess *net.Session, r *packet.Reader, player *world.PlayerI
 		return
 	}
 
+	// 強化攻擊道具（etcitem attack_boost）：使用 = 開啟/關閉
+	if itemInfo.AttackBoost > 0 {
+		toggleAttackBoost(sess, player, invItem)
+		return
+	}
+
 	// Magic doll items: check doll table before potions/consumables
 	if deps.Dolls != nil {
 		if dd := deps.Dolls.Get(invItem.ItemID); dd != nil {
@@ -389,6 +420,15 @@ func handleUseEtcItem(sess *net.Session, r *packet.Reader, player *world.PlayerI
 		}
 	}
 
+	// 指定目標道具（etcitem use_skill）：復活卷軸、輔助魔法卷軸
+	if itemInfo.UseSkill > 0 {
+		if deps.ItemUse != nil && deps.ItemUse.UseTargetedItem(sess, r, player, invItem, itemInfo) &&
+			itemInfo.DelayID != 0 && itemInfo.DelayTime != 0 {
+			setItemDelay(player, itemInfo.DelayID, itemInfo.DelayTime)
+		}
+		return
+	}
+
 	// All other consumables (potions, food) → ItemUseSystem
 	if deps.ItemUse != nil {
 		consumed := deps.ItemUse.UseConsumable(sess, player, invItem, itemInfo)
@@ -401,8 +441,13 @@ func handleUseEtcItem(sess *net.Session, r *packet.Reader, player *world.PlayerI
 // ---------- Identification packets ----------
 
 // sendIdentifyDesc sends S_IdentifyDesc (opcode 245) — shows item stats on identify.
-// Format varies by item type (weapon/armor/etcitem), matching Java S_IdentifyDesc.
 func sendIdentifyDesc(sess *net.Session, item *world.InvItem, info *data.ItemInfo) {
+	sess.Send(buildIdentifyDesc(item, info))
+}
+
+// buildIdentifyDesc 建構 S_IdentifyDesc 封包位元組（不發送）。
+// Format varies by item type (weapon/armor/etcitem), matching Java S_IdentifyDesc.
+func buildIdentifyDesc(item *world.InvItem, info *data.ItemInfo) []byte {
 	w := packet.NewWriterWithOpcode(packet.S_OPCODE_IDENTIFYDESC)
 	w.WriteH(uint16(info.ItemDescID))
 
@@ -436,14 +481,31 @@ func sendIdentifyDesc(sess *net.Session, item *world.InvItem, info *data.ItemInf
 		w.WriteS(fmt.Sprintf("%d%+d", ac, item.EnchantLvl))
 
 	default:
-		// Etcitem — format 138: name + weight
-		w.WriteH(138)
-		w.WriteC(2) // param count
-		w.WriteS(name)
+		// Etcitem（Java S_IdentifyDesc type2 == 0）：依道具種類選擇格式，重量固定為最後一個參數
+		switch {
+		case info.MaxChargeCount > 0:
+			// Format 137: wand — name, charges, weight
+			// 尚未追蹤個別道具的剩餘次數，顯示滿充能次數
+			w.WriteH(137)
+			w.WriteC(3) // param count
+			w.WriteS(name)
+			w.WriteS(fmt.Sprintf("%d", info.MaxChargeCount))
+		case info.FoodVolume > 0:
+			// Format 136: food — name, food volume, weight
+			w.WriteH(136)
+			w.WriteC(3) // param count
+			w.WriteS(name)
+			w.WriteS(fmt.Sprintf("%d", info.FoodVolume))
+		default:
+			// Format 138: name + weight
+			w.WriteH(138)
+			w.WriteC(2) // param count
+			w.WriteS(name)
+		}
 		w.WriteS(fmt.Sprintf("%d", calcItemWeight(item, info)))
 	}
 
-	sess.Send(w.Bytes())
+	return w.Bytes()
 }
 
 // sendItemColor sends S_ItemColor (opcode 240) — updates item bless/color display.
@@ -482,6 +544,9 @@ func itemStatusX(item *world.InvItem, info *data.ItemInfo) byte {
 	if info != nil && !info.Tradeable {
 		statusX |= 2 // cannot trade
 	}
+	if info != nil && info.CantDelete {
+		statusX |= 4 // cannot delete
+	}
 	if info != nil && info.SafeEnchant < 0 {
 		statusX |= 8 | 16 // cannot enchant + warehouse restriction
 	}
@@ -587,6 +652,7 @@ func buildStatusBytes(item *world.InvItem, info *data.ItemInfo) []byte {
 // Java: L1ItemStatus.weapon() / armor() — 武器和防具的 hitMod 使用不同格式。
 func appendEquipSuffix(buf []byte, item *world.InvItem, info *data.ItemInfo) []byte {
 	if item.EnchantLvl != 0 {
+		// 衝裝等級為有號位元組（int8 二補數）；EnchantLvl 已由 world.ClampEnchant 限制在 -128~127，不會溢位
 		buf = append(buf, 2, byte(item.EnchantLvl))
 	}
 	if info.Category == data.CategoryWeapon && world.IsTwoHanded(info.Type) {
@@ -773,11 +839,12 @@ func sendTeleportUnlock(sess *net.Session) {
 
 // ---------- 委派給 ItemUseSystem 的薄層 ----------
 
-// GiveDrops 為擊殺的 NPC 擲骰掉落物品。委派給 ItemUseSystem。
-func GiveDrops(killer *world.PlayerInfo, npcID int32, deps *Deps) {
+// GiveDrops 為擊殺的 NPC 擲骰掉落物品，回傳實際掉落的物品。委派給 ItemUseSystem。
+func GiveDrops(killer *world.PlayerInfo, npc *world.NpcInfo, deps *Deps) []world.LootEntry {
 	if deps.ItemUse != nil {
-		deps.ItemUse.GiveDrops(killer, npcID)
+		return deps.ItemUse.GiveDrops(killer, npc)
 	}
+	return nil
 }
 
 // broadcastEffect 向自己和附近玩家廣播特效。委派給 ItemUseSystem。
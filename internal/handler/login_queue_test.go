@@ -0,0 +1,74 @@
+package handler
+
+import (
+	stdnet "net"
+	"testing"
+
+	"github.com/l1jgo/server/internal/config"
+	"github.com/l1jgo/server/internal/net"
+	"github.com/l1jgo/server/internal/net/packet"
+	"github.com/l1jgo/server/internal/world"
+	"go.uber.org/zap"
+)
+
+func TestQueueNoticeShowsPosition(t *testing.T) {
+	if got, want := queueNotice(3), "伺服器人數已滿，目前排隊順位：第 3 位，請稍候。"; got != want {
+		t.Errorf("queueNotice(3) = %q, want %q", got, want)
+	}
+}
+
+// newQueueSession 建立指定 ID 的測試連線（LoginGate 以 SessionID 區分在線帳號）。
+func newQueueSession(t *testing.T, id uint64) *net.Session {
+	c1, c2 := stdnet.Pipe()
+	t.Cleanup(func() { c1.Close(); c2.Close() })
+	return net.NewSession(c1, id, 4, 16, 0, 0, zap.NewNop())
+}
+
+// queueNotices 取出連線收到的系統訊息文字。
+func queueNotices(sess *net.Session) []string {
+	sess.FlushOutput()
+	var got []string
+	for len(sess.OutQueue) > 0 {
+		if p := <-sess.OutQueue; p[0] == packet.S_OPCODE_MESSAGE {
+			got = append(got, packet.NewReader(p[1:]).ReadS())
+		}
+	}
+	return got
+}
+
+func TestAdmitQueuedWaitsForFreeSlot(t *testing.T) {
+	deps := &Deps{Config: &config.Config{}, World: world.NewState(), Log: zap.NewNop()}
+	deps.Config.Server.MaxOnline = 1
+
+	online := newQueueSession(t, 1)
+	deps.World.Logins.Admit(online, "online")
+	first, second := newQueueSession(t, 2), newQueueSession(t, 3)
+	deps.World.Logins.Enqueue(world.QueuedLogin{Session: first, Account: "first"})
+	deps.World.Logins.Enqueue(world.QueuedLogin{Session: second, Account: "second"})
+
+	var admitted []string
+	admit := func(q world.QueuedLogin, deps *Deps) {
+		admitted = append(admitted, q.Account)
+		deps.World.Logins.Admit(q.Session, q.Account)
+	}
+
+	admitQueued(deps, admit)
+	if len(admitted) != 0 || deps.World.Logins.QueueLen() != 2 {
+		t.Fatalf("full server admitted %v", admitted)
+	}
+	if n := len(queueNotices(second)); n != 0 {
+		t.Errorf("%d position updates sent while nobody was admitted", n)
+	}
+
+	deps.World.Logins.Release(online.ID)
+	admitQueued(deps, admit)
+	if len(admitted) != 1 || admitted[0] != "first" {
+		t.Fatalf("admitted %v after one slot freed, want [first]", admitted)
+	}
+	if !deps.World.Logins.IsQueued("second") {
+		t.Error("second login left the queue without a free slot")
+	}
+	if got := queueNotices(second); len(got) != 1 || got[0] != queueNotice(1) {
+		t.Errorf("second login got %q, want its new position %q", got, queueNotice(1))
+	}
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/l1jgo/server/internal/world"
+)
+
+// lootFilterCommand 處理 .filter 指令，管理角色的掉落物過濾清單（清單內物品擊殺掉落時直接捨棄）。
+// 用法: .filter（列出）、.filter add <itemID>、.filter del <itemID>、.filter clear
+func lootFilterCommand(player *world.PlayerInfo, args []string, deps *Deps) []string {
+	limit := deps.Config.Gameplay.LootFilterMax
+	if len(args) == 0 {
+		return lootFilterList(player, deps)
+	}
+
+	sub := args[0]
+	if sub == "clear" {
+		player.LootFilter = nil
+		saveLootFilter(player, deps)
+		return []string{"已清空掉落物過濾清單"}
+	}
+	if (sub != "add" && sub != "del") || len(args) < 2 {
+		return []string{"\\f3用法: .filter [add|del <itemID>|clear]"}
+	}
+	id, err := strconv.Atoi(args[1])
+	if err != nil {
+		return []string{"\\f3無效的物品ID"}
+	}
+	itemID := int32(id)
+
+	if sub == "del" {
+		if !player.LootFilter[itemID] {
+			return []string{fmt.Sprintf("\\f3物品 %d 不在過濾清單中", itemID)}
+		}
+		delete(player.LootFilter, itemID)
+		saveLootFilter(player, deps)
+		return []string{"已從過濾清單移除: " + lootFilterItemName(itemID, deps)}
+	}
+
+	if itemID == world.AdenaItemID {
+		return []string{"\\f3金幣不可加入過濾清單"}
+	}
+	if deps.Items.Get(itemID) == nil {
+		return []string{fmt.Sprintf("\\f3物品 %d 不存在", itemID)}
+	}
+	if player.LootFilter[itemID] {
+		return []string{fmt.Sprintf("\\f3物品 %d 已在過濾清單中", itemID)}
+	}
+	if len(player.LootFilter) >= limit {
+		return []string{fmt.Sprintf("\\f3過濾清單已滿（上限 %d 項）", limit)}
+	}
+	if player.LootFilter == nil {
+		player.LootFilter = make(map[int32]bool)
+	}
+	player.LootFilter[itemID] = true
+	saveLootFilter(player, deps)
+	return []string{"已加入過濾清單: " + lootFilterItemName(itemID, deps)}
+}
+
+func lootFilterList(player *world.PlayerInfo, deps *Deps) []string {
+	if len(player.LootFilter) == 0 {
+		return []string{"掉落物過濾清單為空（.filter add <itemID> 加入）"}
+	}
+	lines := []string{fmt.Sprintf("=== 掉落物過濾清單 (%d/%d) ===", len(player.LootFilter), deps.Config.Gameplay.LootFilterMax)}
+	for _, id := range lootFilterIDs(player) {
+		lines = append(lines, fmt.Sprintf("%d  %s", id, lootFilterItemName(id, deps)))
+	}
+	return lines
+}
+
+// lootFilterIDs 回傳排序後的過濾物品 ID（存檔與列表用）。
+func lootFilterIDs(player *world.PlayerInfo) []int32 {
+	ids := make([]int32, 0, len(player.LootFilter))
+	for id := range player.LootFilter {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func lootFilterItemName(itemID int32, deps *Deps) string {
+	if info := deps.Items.Get(itemID); info != nil {
+		return info.Name
+	}
+	return strconv.Itoa(int(itemID))
+}
+
+// saveLootFilter 經寫入佇列存檔（清單變更頻率低，不併入批次存檔；佇列保證依變更順序寫入）。
+func saveLootFilter(player *world.PlayerInfo, deps *Deps) {
+	name, ids := player.Name, lootFilterIDs(player)
+	QueueWrite(deps, "loot_filter", func(ctx context.Context) error {
+		return deps.CharRepo.SaveLootFilter(ctx, name, ids)
+	})
+}
@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestLootFilterIDsSorted(t *testing.T) {
+	p := &world.PlayerInfo{LootFilter: map[int32]bool{40014: true, 23: true, 40010: true}}
+	if got, want := lootFilterIDs(p), []int32{23, 40010, 40014}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("lootFilterIDs = %v, want %v", got, want)
+	}
+	if got := lootFilterIDs(&world.PlayerInfo{}); len(got) != 0 {
+		t.Fatalf("empty filter = %v", got)
+	}
+}
@@ -161,6 +161,24 @@ func SendMailSound(sess *net.Session, objID int32) {
 	sess.Send(w.Bytes())
 }
 
+// SendParcelPickList sends S_SHOP_SELL_LIST (opcode 65) — 郵差 NPC 包裹附件選擇視窗。
+// 借用販賣視窗讓玩家勾選背包物品與數量，價格固定為 0；回應走 C_Result resultType=1。
+func SendParcelPickList(sess *net.Session, npcObjID int32, objIDs []int32) {
+	if len(objIDs) == 0 {
+		sendNoSell(sess, npcObjID)
+		return
+	}
+	w := packet.NewWriterWithOpcode(packet.S_OPCODE_SHOP_SELL_LIST)
+	w.WriteD(npcObjID)
+	w.WriteH(uint16(len(objIDs)))
+	for _, objID := range objIDs {
+		w.WriteD(objID)
+		w.WriteD(0) // price
+	}
+	w.WriteH(0x0007) // currency: adena
+	sess.Send(w.Bytes())
+}
+
 // SendSystemMessage sends a plain text system message via S_GlobalChat (opcode 243).
 // 用於「尚未開放」等提示訊息。
 func SendSystemMessage(sess *net.Session, text string) {
@@ -1,12 +1,12 @@
 package handler
 
 import (
-	"math/rand"
 	"time"
 
 	"github.com/l1jgo/server/internal/net"
 	"github.com/l1jgo/server/internal/net/packet"
 	"github.com/l1jgo/server/internal/world"
+	"go.uber.org/zap"
 )
 
 // Direction deltas indexed by heading (0-7).
@@ -41,6 +41,10 @@ func HandleMove(sess *net.Session, r *packet.Reader, deps *Deps) {
 		return
 	}
 
+	// 移動即解除登入/傳送保護，並自動起身、結束冥想
+	ClearSpawnProtect(player, deps)
+	StandUp(player, deps)
+
 	// --- 移動速度驗證（反加速外掛） ---
 	// 一般走路 ~200ms，加速 ~133ms。套用 50% 容許值（避免 tick 批次處理導致誤判）。
 	// 誤判時靜默丟棄（不觸發 rejectMove），避免全畫面彈回造成卡頓。
@@ -50,10 +54,20 @@ func HandleMove(sess *net.Session, r *packet.Reader, deps *Deps) {
 		minInterval = 66_000_000 // 133ms * 50% = 66ms
 	}
 	if player.LastMoveTime > 0 && (now-player.LastMoveTime) < minInterval {
+		noteMoveViolation(sess, player, deps, "too_fast")
 		return // 靜默丟棄：不發封包、不更新座標、不更新 LastMoveTime
 	}
 	player.LastMoveTime = now
 
+	// 移動速率區間檢查（加速外掛偵測）；處罰期間靜默丟棄
+	if now < player.SpeedPenaltyUntil {
+		return
+	}
+	if !checkMoveRate(sess, player, deps, now) {
+		rejectMove(sess, player, ws, deps)
+		return
+	}
+
 	// 永遠使用伺服器端座標（與 Java 台版行為一致）
 	curX := player.X
 	curY := player.Y
@@ -75,13 +89,13 @@ func HandleMove(sess *net.Session, r *packet.Reader, deps *Deps) {
 		if portal := deps.Portals.Get(destX, destY, player.MapID); portal != nil {
 			// 船舶碼頭需額外驗證航線時間和船票
 			isDock, allowed := CheckShipDock(destX, destY, player.MapID, player)
-			if !isDock || allowed {
+			if (!isDock || allowed) && checkInstanceEntry(sess, player, portal.DstMapID, deps) {
 				// 一般傳送門或碼頭驗證通過 → 傳送（不移動到 destX/destY）
 				cancelTradeIfActive(player, deps)
 				teleportPlayer(sess, player, portal.DstX, portal.DstY, portal.DstMapID, portal.DstHeading, deps)
 				return
 			}
-			// 碼頭驗證失敗 → 繼續正常移動（Java: dg() returns false）
+			// 碼頭驗證或限制地圖檢查失敗 → 繼續正常移動（Java: dg() returns false）
 		}
 	}
 
@@ -89,22 +103,38 @@ func HandleMove(sess *net.Session, r *packet.Reader, deps *Deps) {
 	// Java: C_MoveChar → DungeonRTable.dg() 在 DungeonTable 之後檢查
 	if deps.RandomPortals != nil {
 		if rp := deps.RandomPortals.Get(destX, destY, player.MapID); rp != nil && len(rp.Destinations) > 0 {
-			idx := rand.Intn(len(rp.Destinations))
+			idx := world.RandInt(len(rp.Destinations))
 			dst := rp.Destinations[idx]
-			cancelTradeIfActive(player, deps)
-			teleportPlayer(sess, player, dst.X, dst.Y, dst.MapID, rp.DstHeading, deps)
-			return
+			if checkInstanceEntry(sess, player, dst.MapID, deps) {
+				cancelTradeIfActive(player, deps)
+				teleportPlayer(sess, player, dst.X, dst.Y, dst.MapID, rp.DstHeading, deps)
+				return
+			}
 		}
 	}
 
+	// 地圖邊界檢查：目的地超出地圖範圍 → 拒絕並回彈（未載入地圖資料的地圖不檢查）
+	if deps.MapData != nil && deps.MapData.GetInfo(player.MapID) != nil &&
+		!deps.MapData.IsInMap(player.MapID, destX, destY) {
+		deps.MapData.SetImpassable(player.MapID, curX, curY, true)
+		noteMoveViolation(sess, player, deps, "out_of_map")
+		rejectMove(sess, player, ws, deps)
+		return
+	}
+
 	// 地形通行性檢查 + Java fallback（第 160-174 行）：
 	// 1. isPassable 失敗 → 2. CheckUtil.checkPassable 檢查目的地有無實體
-	// 地形不通 + 無實體 → 放行（信任客戶端，tile 資料可能與客戶端不完全吻合）
+	// 地形不通 + 無實體 → 放行（信任客戶端，tile 資料可能與客戶端不完全吻合）；
+	//   開啟 strict_move_passable 時改為拒絕
 	// 地形不通 + 有實體佔位 → 拒絕
 	if deps.MapData != nil && !deps.MapData.IsPassableIgnoreOccupant(player.MapID, curX, curY, int(heading)) {
-		if ws.IsOccupied(destX, destY, player.MapID, player.CharID) {
+		occupied := ws.IsOccupied(destX, destY, player.MapID, player.CharID)
+		if occupied || deps.Config.AntiCheat.StrictMovePassable {
 			// 恢復舊座標 0x80（因為上面已經清除了，拒絕時要恢復）
 			deps.MapData.SetImpassable(player.MapID, curX, curY, true)
+			if !occupied {
+				noteMoveViolation(sess, player, deps, "impassable")
+			}
 			rejectMove(sess, player, ws, deps)
 			return
 		}
@@ -151,6 +181,114 @@ func HandleMove(sess *net.Session, r *packet.Reader, deps *Deps) {
 	BroadcastToPlayers(nearby, data)
 }
 
+// expectedMoveRate 回傳玩家目前狀態下的預期最大步行速率（格/秒）。
+// 一般走路約 200ms/格；加速（綠水）或勇敢類效果約 133ms/格；緩速約 400ms/格。
+func expectedMoveRate(player *world.PlayerInfo) float64 {
+	rate := 5.0
+	switch player.MoveSpeed {
+	case 1:
+		rate *= 1.33
+	case 2:
+		rate *= 0.5
+	}
+	if player.BraveSpeed > 0 {
+		rate *= 1.33
+	}
+	return rate
+}
+
+// checkMoveRate 以固定區間統計玩家移動次數，超過預期速率即記錄並依設定處置。
+// 回傳 false 表示本次移動應被拒絕並回彈（開始處罰或被踢除）。
+func checkMoveRate(sess *net.Session, player *world.PlayerInfo, deps *Deps, now int64) bool {
+	cfg := deps.Config.AntiCheat
+	if cfg.SpeedWindowSec <= 0 {
+		return true
+	}
+
+	if player.SpeedWindowStart == 0 {
+		player.SpeedWindowStart = now
+	}
+	player.SpeedWindowMoves++
+
+	elapsed := now - player.SpeedWindowStart
+	window := int64(cfg.SpeedWindowSec) * int64(time.Second)
+	if elapsed < window {
+		return true
+	}
+
+	rate := float64(player.SpeedWindowMoves) / (float64(elapsed) / float64(time.Second))
+	allowed := expectedMoveRate(player) * cfg.SpeedTolerance
+	if cfg.SpeedThreshold > 0 && allowed > cfg.SpeedThreshold {
+		allowed = cfg.SpeedThreshold
+	}
+	player.SpeedWindowStart = now
+	player.SpeedWindowMoves = 0
+
+	if rate <= allowed {
+		player.SpeedStrikes = 0
+		return true
+	}
+
+	player.SpeedStrikes++
+	deps.Log.Warn("疑似加速外掛",
+		zap.String("account", sess.AccountName),
+		zap.String("player", player.Name),
+		zap.Uint64("session", sess.ID),
+		zap.Float64("rate", rate),
+		zap.Float64("allowed", allowed),
+		zap.Uint8("move_speed", player.MoveSpeed),
+		zap.Uint8("brave_speed", player.BraveSpeed),
+		zap.Int("strikes", player.SpeedStrikes),
+		zap.String("action", cfg.SpeedAction),
+	)
+
+	switch cfg.SpeedAction {
+	case "slow":
+		player.SpeedPenaltyUntil = now + int64(time.Second)
+		return false
+	case "kick":
+		if cfg.SpeedKickStrikes > 0 && player.SpeedStrikes >= cfg.SpeedKickStrikes {
+			deps.Log.Warn("加速外掛踢除",
+				zap.String("account", sess.AccountName),
+				zap.String("player", player.Name),
+				zap.Uint64("session", sess.ID),
+			)
+			sess.Logout = true // 踢除不進入斷線保留
+			sess.Close()
+			return false
+		}
+	}
+	return true
+}
+
+// noteMoveViolation 累計移動違規；10 秒內達 move_violation_limit 次即記錄為疑似外掛。
+func noteMoveViolation(sess *net.Session, player *world.PlayerInfo, deps *Deps, reason string) {
+	limit := deps.Config.AntiCheat.MoveViolationLimit
+	if limit <= 0 {
+		return
+	}
+	now := time.Now().UnixNano()
+	if player.MoveViolations == 0 || now-player.MoveViolationTime > int64(10*time.Second) {
+		player.MoveViolations = 0
+		player.MoveViolationTime = now
+	}
+	player.MoveViolations++
+	if player.MoveViolations < limit {
+		return
+	}
+	deps.Log.Warn("疑似移動外掛",
+		zap.String("account", sess.AccountName),
+		zap.String("player", player.Name),
+		zap.Uint64("session", sess.ID),
+		zap.String("reason", reason),
+		zap.Int("violations", player.MoveViolations),
+		zap.Int16("map", player.MapID),
+		zap.Int32("x", player.X),
+		zap.Int32("y", player.Y),
+	)
+	player.MoveViolations = 0
+}
+
 // rejectMove 碰撞拒絕：回彈玩家位置 + 重發所有附近實體。
 // 對應 Java L1PcUnlock.Pc_Unlock() 流程：
 //   S_OwnCharPack → removeAllKnownObjects → updateObject → S_CharVisualUpdate
@@ -3,7 +3,6 @@ package handler
 import (
 	"fmt"
 	"math"
-	"math/rand"
 	"strings"
 
 	"github.com/l1jgo/server/internal/data"
@@ -32,6 +31,7 @@ func HandleNpcAction(sess *net.Session, r *packet.Reader, deps *Deps) {
 
 	// Clear pending craft state — any new NPC interaction overrides
 	player.PendingCraftAction = ""
+	player.ParcelNpcObjID = 0
 
 	// --- Summon ring selection: numeric string response from "summonlist" dialog ---
 	// Java: L1ActionPc.java checks cmd.matches("[0-9]+") && isSummonMonster().
@@ -120,6 +120,12 @@ func HandleNpcAction(sess *net.Session, r *packet.Reader, deps *Deps) {
 			deps.Warehouse.SendClanWarehouseHistory(sess, player.ClanID)
 		}
 
+	// Mail parcel — 郵差 NPC（寄送包裹附件 / 領取包裹）
+	case "parcel":
+		deps.Mail.OpenParcel(sess, player, objID)
+	case "parcel-claim":
+		deps.Mail.ClaimParcels(sess, player)
+
 	// EXP recovery / PK redemption (stub)
 	case "exp":
 		sendHypertext(sess, objID, "expr")
@@ -138,6 +144,10 @@ func HandleNpcAction(sess *net.Session, r *packet.Reader, deps *Deps) {
 		handleNpcWeaponEnchant(sess, player, deps)
 	case "enca":
 		handleNpcArmorEnchant(sess, player, deps)
+	case "statreset":
+		handleNpcStatReset(sess, player, npc, deps)
+	case "fix", "repair": // 鐵匠對話的「修理武器」連結送 "fix"（Java: C_NPCAction "fix"）
+		handleNpcRepair(sess, player, npc, deps)
 
 	// "ent" 動作 — 多個 NPC 共用，依 NPC ID 分派
 	// Java: C_NPCAction.java 對 "ent" 按 npcId 做 if/else
@@ -266,6 +276,9 @@ func handleShopSell(sess *net.Session, npcID, objID int32, deps *Deps) {
 		if invItem.EnchantLvl != 0 || invItem.Bless >= 128 {
 			continue // skip enchanted/sealed
 		}
+		if info := deps.Items.Get(invItem.ItemID); info != nil && !info.Tradeable {
+			continue // skip untradeable
+		}
 		items = append(items, assessedItem{objectID: invItem.ObjectID, price: price})
 	}
 
@@ -406,6 +419,30 @@ func TeleportPlayer(sess *net.Session, player *world.PlayerInfo, x, y int32, map
 	teleportPlayer(sess, player, x, y, mapID, heading, deps)
 }
 
+// landingNudgeRadius 傳送落點被牆壁或其他實體佔用時，向外搜尋的最大格數。
+const landingNudgeRadius = 3
+
+// FindLandingTile 回傳最接近 (x,y) 的可通行且無人佔用的格子（由內而外逐圈搜尋，
+// 半徑 landingNudgeRadius）。原座標可用或找不到替代格時回傳原座標。
+// excludeID 為傳送者自身 ID（不視為佔用）。
+func FindLandingTile(deps *Deps, mapID int16, x, y int32, excludeID int32) (int32, int32) {
+	tx, ty, _ := world.SpiralSearch(x, y, landingNudgeRadius, func(tx, ty int32) bool {
+		return landingTileFree(deps, mapID, tx, ty, excludeID)
+	})
+	return tx, ty
+}
+
+// landingTileFree 判斷格子是否可作為傳送落點（地形可通行且無其他實體）。
+func landingTileFree(deps *Deps, mapID int16, x, y int32, excludeID int32) bool {
+	if deps.World.IsOccupied(x, y, mapID, excludeID) {
+		return false
+	}
+	if deps.MapData == nil {
+		return true
+	}
+	return deps.MapData.IsInMap(mapID, x, y) && deps.MapData.IsPassablePoint(mapID, x, y)
+}
+
 func teleportPlayer(sess *net.Session, player *world.PlayerInfo, x, y int32, mapID, heading int16, deps *Deps) {
 	// 傳送時釋放血盟倉庫鎖定（Java: Teleportation.java 行 122-123）
 	if player.ClanID != 0 {
@@ -418,12 +455,16 @@ func teleportPlayer(sess *net.Session, player *world.PlayerInfo, x, y int32, map
 
 	// Reset move speed timer (teleport resets speed validation)
 	player.LastMoveTime = 0
+	player.Resting = false
 
 	// Clear old tile (for NPC pathfinding)
 	if deps.MapData != nil {
 		deps.MapData.SetImpassable(player.MapID, player.X, player.Y, false)
 	}
 
+	// 落點在牆內或已被佔用時，移到最近的可用格子
+	x, y = FindLandingTile(deps, mapID, x, y, player.CharID)
+
 	// ── 收集玩家擁有的同伴（傳送前）──
 	ownedPets := deps.World.GetPetsByOwner(player.CharID)
 	ownedSummons := deps.World.GetSummonsByOwner(player.CharID)
@@ -481,6 +522,7 @@ func teleportPlayer(sess *net.Session, player *world.PlayerInfo, x, y int32, map
 	}
 
 	// 2. 更新世界狀態位置（Java: moveVisibleObject + setLocation）
+	oldMapID := player.MapID
 	deps.World.UpdatePosition(sess.ID, x, y, mapID, heading)
 
 	// 標記新格子不可通行（NPC 尋路用）
@@ -521,6 +563,11 @@ func teleportPlayer(sess *net.Session, player *world.PlayerInfo, x, y int32, map
 	// 3. S_MapID（即使同地圖也要發——客戶端傳送需要）
 	sendMapID(sess, uint16(mapID), false)
 
+	// 換地圖時發送目的地天氣（各地圖天氣可能不同）
+	if mapID != oldMapID {
+		sendWeather(sess, deps.World.WeatherAt(mapID).Code)
+	}
+
 	// 重置 Known 集合（傳送 = 完全切換場景）
 	if player.Known == nil {
 		player.Known = world.NewKnownEntities()
@@ -621,6 +668,9 @@ func teleportPlayer(sess *net.Session, player *world.PlayerInfo, x, y int32, map
 	// 限時地圖偵測（Java: Teleportation.teleportation() 中的 isTimingMap 檢查）
 	OnEnterTimedMap(sess, player, mapID)
 
+	// 傳送保護（客戶端載入新地圖期間不被怪物攻擊）
+	grantSpawnProtect(player, deps)
+
 	// Release client teleport lock (Java: S_Paralysis always sent in finally block).
 	sendTeleportUnlock(sess)
 }
@@ -710,7 +760,7 @@ func execHeal(sess *net.Session, player *world.PlayerInfo, h *data.HealerDef, de
 	switch h.HealType {
 	case "random":
 		healRange := h.HealMax - h.HealMin + 1
-		healAmt := int16(rand.Intn(healRange) + h.HealMin)
+		healAmt := int16(world.RandInt(healRange) + h.HealMin)
 		if player.HP < player.MaxHP {
 			player.HP += healAmt
 			if player.HP > player.MaxHP {
@@ -46,7 +46,7 @@ func HandleGiveItem(sess *net.Session, r *packet.Reader, deps *Deps) {
 
 	// bless >= 128 不可給予（Java: item.getBless() >= 128）
 	if invItem.Bless >= 128 {
-		sendServerMessage(sess, 210) // 不可轉移
+		sendServerMessageArgs(sess, 210, invItem.Name) // 不可轉移
 		return
 	}
 
@@ -54,7 +54,7 @@ func HandleGiveItem(sess *net.Session, r *packet.Reader, deps *Deps) {
 	if deps.Items != nil {
 		itemInfo := deps.Items.Get(invItem.ItemID)
 		if itemInfo != nil && !itemInfo.Tradeable {
-			sendServerMessage(sess, 210) // 不可轉移
+			sendServerMessageArgs(sess, 210, invItem.Name) // 不可轉移
 			return
 		}
 	}
@@ -63,7 +63,7 @@ func HandleGiveItem(sess *net.Session, r *packet.Reader, deps *Deps) {
 	if isPetCollar(invItem.ItemID) {
 		for _, pet := range deps.World.GetPetsByOwner(player.CharID) {
 			if pet.ItemObjID == invItem.ObjectID {
-				sendServerMessage(sess, 210) // 不可轉移
+				sendServerMessageArgs(sess, 210, invItem.Name) // 不可轉移
 				return
 			}
 		}
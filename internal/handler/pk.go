@@ -8,21 +8,35 @@ import (
 	"github.com/l1jgo/server/internal/world"
 )
 
-// HandleDuel processes C_DUEL (opcode 5).
+// HandleDuelRequest processes C_DUEL (opcode 5).
 // Java: C_Fight — 面對面決鬥請求。使用 FaceToFace.faceToFace(pc) 找到對面玩家。
 // 協議流程：C_DUEL → 找對面玩家 → 設定 FightId → S_Message_YN(630) → 等待回應。
-func HandleDuel(sess *net.Session, _ *packet.Reader, deps *Deps) {
+// 決鬥進行中再次送出 C_DUEL 視為投降。
+func HandleDuelRequest(sess *net.Session, _ *packet.Reader, deps *Deps) {
 	player := deps.World.GetBySession(sess.ID)
 	if player == nil || player.Dead {
 		return
 	}
 
+	// 決鬥中 → 投降
+	if player.DuelActive {
+		if partner := deps.World.GetByCharID(player.FightId); partner != nil {
+			EndDuel(partner, player, DuelEndSurrender, deps)
+		}
+		return
+	}
+
 	// 尋找面對面的玩家（已有函式：trade.go findFaceToFace）
 	target := findFaceToFace(player, deps)
 	if target == nil {
 		return
 	}
 
+	if target.RefuseDuel {
+		sendRefused(sess, target.Name, "決鬥")
+		return
+	}
+
 	// 驗證：雙方都不在決鬥中（Java: getFightId() != 0 → msg 633/634）
 	if player.FightId != 0 {
 		SendServerMessage(sess, 633) // "你正在決鬥中。"
@@ -45,11 +59,11 @@ func HandleDuel(sess *net.Session, _ *packet.Reader, deps *Deps) {
 	SendYesNoDialog(target.Session, 630, player.Name)
 }
 
-// HandleDuelResponse 處理決鬥 Y/N 回應（由 C_ATTR case 630 呼叫）。
-func HandleDuelResponse(sess *net.Session, player *world.PlayerInfo, partnerCharID int32, accepted bool, deps *Deps) {
+// HandleDuelAccept 處理決鬥 Y/N 回應（由 C_ATTR case 630 呼叫）。
+func HandleDuelAccept(sess *net.Session, player *world.PlayerInfo, partnerCharID int32, accepted bool, deps *Deps) {
 	partner := deps.World.GetByCharID(partnerCharID)
-	if partner == nil {
-		// 對方已離線，清除自己的決鬥狀態
+	if partner == nil || partner.FightId != player.CharID {
+		// 對方已離線或已取消，清除自己的決鬥狀態
 		player.FightId = 0
 		return
 	}
@@ -63,38 +77,105 @@ func HandleDuelResponse(sess *net.Session, player *world.PlayerInfo, partnerChar
 	}
 
 	// 接受決鬥：發送決鬥通知給雙方（觸發決鬥音樂）
+	player.DuelActive = true
+	partner.DuelActive = true
 	SendDuelNotify(partner.Session, partner.FightId, partner.CharID)
 	SendDuelNotify(player.Session, player.FightId, player.CharID)
+	broadcastDuelMessage(partner, player, fmt.Sprintf("%s 與 %s 開始決鬥！", partner.Name, player.Name), deps)
+}
+
+// IsDueling 雙方是否處於已接受的決鬥中（決鬥期間允許互相傷害，安全區亦同）。
+func IsDueling(a, b *world.PlayerInfo) bool {
+	return a.DuelActive && b.DuelActive && a.FightId == b.CharID && b.FightId == a.CharID
+}
+
+// 決鬥結束原因（EndDuel）。
+const (
+	DuelEndDefeat    = iota // 一方 HP 歸零或死亡
+	DuelEndSurrender        // 一方投降（決鬥中再次送出 C_DUEL）
+	DuelEndDistance         // 距離過遠或不在同一地圖
+	DuelEndLogout           // 一方離線
+)
+
+// EndDuel 結束決鬥：清除雙方狀態、停止決鬥音樂並向附近玩家廣播結果。
+// loser 為落敗 / 投降 / 離線的一方；距離中止時不分勝負。
+// [gameplay] duel_restore_on_end 開啟時，雙方（仍存活者）恢復滿 HP/MP。
+func EndDuel(winner, loser *world.PlayerInfo, reason int, deps *Deps) {
+	var msg string
+	switch reason {
+	case DuelEndDefeat:
+		msg = fmt.Sprintf("%s 在決鬥中擊敗了 %s。", winner.Name, loser.Name)
+	case DuelEndSurrender:
+		msg = fmt.Sprintf("%s 向 %s 投降，決鬥結束。", loser.Name, winner.Name)
+	case DuelEndDistance:
+		msg = fmt.Sprintf("%s 與 %s 距離過遠，決鬥中止。", winner.Name, loser.Name)
+	case DuelEndLogout:
+		msg = fmt.Sprintf("%s 離開了遊戲，決鬥結束。", loser.Name)
+	}
+
+	for _, p := range [2]*world.PlayerInfo{winner, loser} {
+		p.FightId = 0
+		p.DuelActive = false
+		if reason == DuelEndLogout && p == loser {
+			continue
+		}
+		SendDuelNotify(p.Session, 0, 0)
+		if deps.Config.Gameplay.DuelRestoreOnEnd && !p.Dead && p.HP > 0 {
+			p.HP = p.MaxHP
+			p.MP = p.MaxMP
+			p.Dirty = true
+			SendHpUpdate(p.Session, p)
+			sendMpUpdate(p.Session, p)
+		}
+	}
+	broadcastDuelMessage(winner, loser, msg, deps)
+}
+
+// broadcastDuelMessage 向雙方及其附近玩家發送決鬥系統訊息（每人一次）。
+func broadcastDuelMessage(a, b *world.PlayerInfo, msg string, deps *Deps) {
+	sent := make(map[uint64]bool)
+	for _, p := range [2]*world.PlayerInfo{a, b} {
+		for _, viewer := range deps.World.GetNearbyPlayersAt(p.X, p.Y, p.MapID) {
+			if !sent[viewer.SessionID] {
+				sent[viewer.SessionID] = true
+				sendGlobalChat(viewer.Session, 9, msg)
+			}
+		}
+	}
 }
 
 // ClearDuelOnDeath 玩家死亡時清理決鬥狀態。供 DeathSystem 呼叫。
-// 返回 true 表示此次死亡為決鬥死亡（呼叫方應跳過 PK 後果）。
-func ClearDuelOnDeath(player *world.PlayerInfo, ws *world.State) bool {
+// 返回 true 表示此次死亡發生在進行中的決鬥（對手判定勝利）。
+func ClearDuelOnDeath(player *world.PlayerInfo, deps *Deps) bool {
 	if player.FightId == 0 {
 		return false
 	}
 
-	isDuel := false
-	partner := ws.GetByCharID(player.FightId)
+	partner := deps.World.GetByCharID(player.FightId)
+	if partner != nil && IsDueling(player, partner) {
+		EndDuel(partner, player, DuelEndDefeat, deps)
+		return true
+	}
+	// 尚未接受的邀請：清除雙方
 	if partner != nil && partner.FightId == player.CharID {
-		// 互相指向 → 正式決鬥死亡
-		isDuel = true
 		partner.FightId = 0
-		SendDuelNotify(partner.Session, 0, 0)
 	}
 	player.FightId = 0
-	return isDuel
+	return false
 }
 
 // ClearDuelOnDisconnect 玩家斷線時清理對手的決鬥狀態。供 InputSystem 呼叫。
-func ClearDuelOnDisconnect(player *world.PlayerInfo, ws *world.State) {
+func ClearDuelOnDisconnect(player *world.PlayerInfo, deps *Deps) {
 	if player.FightId == 0 {
 		return
 	}
-	partner := ws.GetByCharID(player.FightId)
-	if partner != nil {
+	partner := deps.World.GetByCharID(player.FightId)
+	if partner != nil && IsDueling(player, partner) {
+		EndDuel(partner, player, DuelEndLogout, deps)
+		return
+	}
+	if partner != nil && partner.FightId == player.CharID {
 		partner.FightId = 0
-		SendDuelNotify(partner.Session, 0, 0)
 	}
 	player.FightId = 0
 }
@@ -155,6 +236,7 @@ func SendKarma(sess *net.Session, karma int32) {
 // SendServerMessageN sends S_ServerMessage with a numeric parameter.
 // Format: [H msgID][C argCount][S arg1]
 func SendServerMessageN(sess *net.Session, msgID uint16, value int32) {
+	checkMessageArgs(msgID, []string{fmt.Sprintf("%d", value)})
 	w := packet.NewWriterWithOpcode(packet.S_OPCODE_MESSAGE_CODE)
 	w.WriteH(msgID)
 	w.WriteC(1) // 1 argument
@@ -165,6 +247,7 @@ func SendServerMessageN(sess *net.Session, msgID uint16, value int32) {
 // SendServerMessageStr sends S_ServerMessage with one string parameter.
 // Format: [H msgID][C 1][S arg]
 func SendServerMessageStr(sess *net.Session, msgID uint16, arg string) {
+	checkMessageArgs(msgID, []string{arg})
 	w := packet.NewWriterWithOpcode(packet.S_OPCODE_MESSAGE_CODE)
 	w.WriteH(msgID)
 	w.WriteC(1)
@@ -175,6 +258,7 @@ func SendServerMessageStr(sess *net.Session, msgID uint16, arg string) {
 // SendRedMessage sends S_RedMessage (opcode 105) — center screen red text warning.
 // Wire format identical to S_ServerMessage: [H msgID][C argCount][S args...]
 func SendRedMessage(sess *net.Session, msgID uint16, args ...string) {
+	checkMessageArgs(msgID, args)
 	w := packet.NewWriterWithOpcode(packet.S_OPCODE_REDMESSAGE)
 	w.WriteH(msgID)
 	w.WriteC(byte(len(args)))
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/l1jgo/server/internal/world"
+)
+
+// 對方開啟拒絕決鬥時，請求不得設定 FightId 或送出 Y/N 對話框。
+func TestDuelRequestRespectsRefuseDuel(t *testing.T) {
+	deps := &Deps{World: world.NewState()}
+	sess := newTestSession(t)
+	player := &world.PlayerInfo{SessionID: sess.ID, Session: sess, CharID: 10, X: 32700, Y: 32800, MapID: 4, Heading: 2}
+	target := &world.PlayerInfo{SessionID: sess.ID + 1, Session: newTestSession(t), CharID: 20, Name: "target",
+		X: 32700 + headingDX[2], Y: 32800 + headingDY[2], MapID: 4, Heading: 6, RefuseDuel: true}
+	deps.World.AddPlayer(player)
+	deps.World.AddPlayer(target)
+
+	HandleDuelRequest(sess, nil, deps)
+	if player.FightId != 0 || target.FightId != 0 || target.PendingYesNoType != 0 {
+		t.Fatalf("duel started against a refusing target: fight %d/%d, pending %d",
+			player.FightId, target.FightId, target.PendingYesNoType)
+	}
+	sess.FlushOutput()
+	if len(sess.OutQueue) != 1 {
+		t.Errorf("requester got %d packets, want the refused notice", len(sess.OutQueue))
+	}
+
+	target.RefuseDuel = false
+	HandleDuelRequest(sess, nil, deps)
+	if player.FightId != target.CharID || target.PendingYesNoType != 630 {
+		t.Errorf("duel not requested once the target accepts duels: fight %d, pending %d",
+			player.FightId, target.PendingYesNoType)
+	}
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/l1jgo/server/internal/net"
+	"github.com/l1jgo/server/internal/persist"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// HandlePlayerCommand 處理一般玩家可用的 "." 指令（.sit / .stand / .bossrank / .refuse / .filter），
+// 在 GM 指令表之前攔截。回傳 true 表示已處理。
+func HandlePlayerCommand(sess *net.Session, player *world.PlayerInfo, text string, deps *Deps) bool {
+	fields := strings.Fields(strings.ToLower(text))
+	if len(fields) == 0 {
+		return false
+	}
+	var msgs []string
+	switch fields[0] {
+	case ".sit":
+		msgs = []string{setResting(player, true, deps)}
+	case ".stand":
+		msgs = []string{setResting(player, false, deps)}
+	case ".bossrank":
+		if deps.Config == nil || deps.Config.Gameplay.BossRankSize <= 0 {
+			return false
+		}
+		if _, ok := chatAllowed(sess, player, chatCDGlobal, text, deps); !ok {
+			return true
+		}
+		msgs = bossRankCommand(deps)
+	case ".refuse":
+		msgs = refuseCommand(player, fields[1:], deps)
+	case ".filter":
+		if deps.Config == nil || deps.Config.Gameplay.LootFilterMax <= 0 {
+			return false
+		}
+		msgs = lootFilterCommand(player, fields[1:], deps)
+	default:
+		return false
+	}
+	for _, msg := range msgs {
+		if msg != "" {
+			SendSystemMessage(sess, msg)
+		}
+	}
+	return true
+}
+
+// BossRankCache 快取 .bossrank 排行榜，遊戲迴圈只讀快取、不查詢資料庫。
+// 由寫入佇列在開服與每次頭目擊殺寫入後重新整理。
+type BossRankCache struct {
+	mu     sync.Mutex
+	rows   []persist.BossKillCount
+	loaded bool
+}
+
+// Rows 回傳目前快取的排行；false 表示尚未載入成功。
+func (c *BossRankCache) Rows() ([]persist.BossKillCount, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rows, c.loaded
+}
+
+func (c *BossRankCache) set(rows []persist.BossKillCount) {
+	c.mu.Lock()
+	c.rows, c.loaded = rows, true
+	c.mu.Unlock()
+}
+
+// RefreshBossRank 透過寫入佇列重新查詢排行並更新快取（排在先前送出的擊殺紀錄之後執行）。
+func RefreshBossRank(deps *Deps) {
+	if deps.BossRank == nil || deps.BossKillRepo == nil || deps.Config.Gameplay.BossRankSize <= 0 {
+		return
+	}
+	cache, repo, limit := deps.BossRank, deps.BossKillRepo, deps.Config.Gameplay.BossRankSize
+	QueueWrite(deps, "boss_rank", func(ctx context.Context) error {
+		rows, err := repo.TopKillers(ctx, limit)
+		if err != nil {
+			return err
+		}
+		cache.set(rows)
+		return nil
+	})
+}
+
+// bossRankCommand 顯示快取的 boss_kills 擊殺排行（前 boss_rank_size 名）。
+func bossRankCommand(deps *Deps) []string {
+	if deps.BossRank == nil {
+		return []string{"頭目擊殺排行目前無法使用。"}
+	}
+	rows, ok := deps.BossRank.Rows()
+	if !ok {
+		return []string{"頭目擊殺排行目前無法使用。"}
+	}
+	return formatBossRank(rows)
+}
+
+// formatBossRank 排行榜每列一行：「1. 名稱 - 12 隻」。
+func formatBossRank(rows []persist.BossKillCount) []string {
+	if len(rows) == 0 {
+		return []string{"目前尚無頭目擊殺紀錄。"}
+	}
+	lines := make([]string, 0, len(rows)+1)
+	lines = append(lines, "【頭目擊殺排行】")
+	for i, r := range rows {
+		lines = append(lines, fmt.Sprintf("%d. %s - %d 隻", i+1, r.Name, r.Kills))
+	}
+	return lines
+}
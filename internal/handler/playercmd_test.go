@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/l1jgo/server/internal/config"
+	"github.com/l1jgo/server/internal/persist"
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestHandlePlayerCommandIgnoresOtherText(t *testing.T) {
+	p := &world.PlayerInfo{}
+	for _, text := range []string{"hello", ".help", ".sitdown"} {
+		if HandlePlayerCommand(nil, p, text, &Deps{}) {
+			t.Errorf("%q consumed as a player command", text)
+		}
+	}
+}
+
+func TestBossRankDisabledFallsThrough(t *testing.T) {
+	deps := &Deps{Config: &config.Config{}}
+	if HandlePlayerCommand(nil, &world.PlayerInfo{}, ".bossrank", deps) {
+		t.Error(".bossrank consumed with boss_rank_size = 0")
+	}
+}
+
+// .filter 是玩家指令；未啟用（loot_filter_max = 0）時不攔截。不涉及存檔的分支不需 CharRepo。
+func TestLootFilterPlayerCommand(t *testing.T) {
+	deps := &Deps{Config: &config.Config{}}
+	sess := newTestSession(t)
+	p := &world.PlayerInfo{Session: sess}
+	if HandlePlayerCommand(sess, p, ".filter", deps) {
+		t.Fatal(".filter consumed with loot_filter_max = 0")
+	}
+
+	deps.Config.Gameplay.LootFilterMax = 5
+	if !HandlePlayerCommand(sess, p, ".filter", deps) {
+		t.Fatal(".filter not handled as a player command")
+	}
+	sess.FlushOutput()
+	if len(sess.OutQueue) != 1 {
+		t.Errorf("empty list sent %d lines, want 1", len(sess.OutQueue))
+	}
+
+	for args, want := range map[string]string{
+		"add":          "用法",
+		"add x":        "無效的物品ID",
+		"del 40010":    "不在過濾清單中",
+		"add 40308":    "金幣不可加入",
+		"remove 40010": "用法",
+	} {
+		got := lootFilterCommand(p, strings.Fields(args), deps)
+		if len(got) != 1 || !strings.Contains(got[0], want) {
+			t.Errorf(".filter %s = %q, want a reply containing %q", args, got, want)
+		}
+	}
+	if p.LootFilter != nil {
+		t.Errorf("rejected commands changed the filter: %v", p.LootFilter)
+	}
+}
+
+func TestFormatBossRank(t *testing.T) {
+	if got := formatBossRank(nil); len(got) != 1 || !strings.Contains(got[0], "尚無") {
+		t.Errorf("empty leaderboard = %q", got)
+	}
+
+	got := formatBossRank([]persist.BossKillCount{
+		{CharID: 1, Name: "Alpha", Kills: 12},
+		{CharID: 2, Name: "Beta", Kills: 3},
+	})
+	want := []string{"【頭目擊殺排行】", "1. Alpha - 12 隻", "2. Beta - 3 隻"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("formatBossRank = %q, want %q", got, want)
+	}
+}
+
+// .bossrank 只讀快取（BossKillRepo 為 nil：查詢資料庫即 panic），並受全體頻道發言冷卻限制。
+func TestBossRankServesCacheBehindCooldown(t *testing.T) {
+	deps := &Deps{Config: &config.Config{}, BossRank: &BossRankCache{}}
+	deps.Config.Gameplay.BossRankSize = 10
+	deps.Config.Gameplay.ChatGlobalCooldownMs = 60000
+	sess := newTestSession(t)
+	p := &world.PlayerInfo{Session: sess, Name: "player"}
+
+	sent := func() int {
+		sess.FlushOutput()
+		n := len(sess.OutQueue)
+		for len(sess.OutQueue) > 0 {
+			<-sess.OutQueue
+		}
+		return n
+	}
+
+	if !HandlePlayerCommand(sess, p, ".bossrank", deps) || sent() != 1 {
+		t.Fatal("unloaded cache: want the single unavailable notice")
+	}
+	p.ChatLastSent = [4]int64{}
+	deps.BossRank.set([]persist.BossKillCount{{CharID: 1, Name: "Alpha", Kills: 12}})
+	if got, want := bossRankCommand(deps), formatBossRank(deps.BossRank.rows); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("bossRankCommand = %q, want %q", got, want)
+	}
+	if !HandlePlayerCommand(sess, p, ".bossrank", deps) || sent() != 2 {
+		t.Fatal("cached leaderboard not shown")
+	}
+	if !HandlePlayerCommand(sess, p, ".bossrank", deps) || sent() != 1 {
+		t.Error("second .bossrank inside the cooldown was not refused with one notice")
+	}
+}
@@ -1,8 +1,6 @@
 package handler
 
 import (
-	"math/rand/v2"
-
 	"github.com/l1jgo/server/internal/net"
 	"github.com/l1jgo/server/internal/net/packet"
 	"github.com/l1jgo/server/internal/world"
@@ -141,7 +139,7 @@ func PolyScrollDuration(itemID int32) int {
 	case ItemBlessedPolyScroll:
 		return 2100 // 35 minutes
 	case ItemWelfarePolyPotion:
-		return 2401 + rand.IntN(2400) // 2401-4800 seconds (40-80 min)
+		return 2401 + world.RandInt(2400) // 2401-4800 seconds (40-80 min)
 	}
 	return 1800
 }
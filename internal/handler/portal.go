@@ -2,12 +2,16 @@ package handler
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/l1jgo/server/internal/net"
 	"github.com/l1jgo/server/internal/net/packet"
 	"go.uber.org/zap"
 )
 
+// portalArrivalNudge 落點為傳送門時沿朝向推移的最大格數。
+const portalArrivalNudge = 2
+
 // HandleEnterPortal processes C_ENTER_PORTAL (opcode 219).
 // Client sends the portal tile coordinates when stepping on a dungeon entrance/exit.
 // Format: [H srcX][H srcY]
@@ -60,10 +64,49 @@ func HandleEnterPortal(sess *net.Session, r *packet.Reader, deps *Deps) {
 		return
 	}
 
+	// 傳送門冷卻：剛穿過傳送門時拒絕並告知剩餘秒數（避免雙向傳送門來回彈跳）
+	now := time.Now()
+	if left := player.PortalReadyAt.Sub(now); left > 0 {
+		SendSystemMessage(sess, portalCooldownNotice(left))
+		return
+	}
+
+	// 限制進入地圖（人數上限／單一隊伍／鑰匙）
+	if !checkInstanceEntry(sess, player, portal.DstMapID, deps) {
+		player.PortalReadyAt = now.Add(time.Duration(deps.Config.World.PortalCooldownMs) * time.Millisecond)
+		return
+	}
+
 	// Auto-cancel trade when entering portal
 	cancelTradeIfActive(player, deps)
 
-	deps.Log.Info(fmt.Sprintf("傳送門傳送  角色=%s  備註=%s  目標x=%d  目標y=%d  目標地圖=%d", player.Name, portal.Note, portal.DstX, portal.DstY, portal.DstMapID))
+	dstX, dstY := portalArrival(deps, portal.DstX, portal.DstY, portal.DstMapID, portal.DstHeading)
 
-	teleportPlayer(sess, player, portal.DstX, portal.DstY, portal.DstMapID, portal.DstHeading, deps)
+	deps.Log.Info(fmt.Sprintf("傳送門傳送  角色=%s  備註=%s  目標x=%d  目標y=%d  目標地圖=%d", player.Name, portal.Note, dstX, dstY, portal.DstMapID))
+
+	player.PortalReadyAt = now.Add(time.Duration(deps.Config.World.PortalCooldownMs) * time.Millisecond)
+	teleportPlayer(sess, player, dstX, dstY, portal.DstMapID, portal.DstHeading, deps)
+}
+
+// portalCooldownNotice 組合傳送門冷卻提示（剩餘時間無條件進位到秒）。
+func portalCooldownNotice(left time.Duration) string {
+	secs := int((left + time.Second - 1) / time.Second)
+	return fmt.Sprintf("剛通過傳送門，請於 %d 秒後再進入。", secs)
+}
+
+// portalArrival 落點本身是另一個傳送門（雙向傳送門的回程入口）時，
+// 沿抵達朝向往前推一格（最多 portalArrivalNudge 格），避免一落地就再次觸發回程。
+// 前方不可通行時維持原落點；牆內／佔用的落點由 teleportPlayer 的 FindLandingTile 處理。
+func portalArrival(deps *Deps, x, y int32, mapID, heading int16) (int32, int32) {
+	if heading < 0 || heading > 7 {
+		return x, y
+	}
+	for step := 0; step < portalArrivalNudge && deps.Portals.Get(x, y, mapID) != nil; step++ {
+		nx, ny := x+headingDX[heading], y+headingDY[heading]
+		if deps.MapData != nil && !(deps.MapData.IsInMap(mapID, nx, ny) && deps.MapData.IsPassablePoint(mapID, nx, ny)) {
+			break
+		}
+		x, y = nx, ny
+	}
+	return x, y
 }
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/l1jgo/server/internal/config"
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/net/packet"
+	"github.com/l1jgo/server/internal/world"
+	"go.uber.org/zap"
+)
+
+// enterPortal 模擬客戶端送出 C_ENTER_PORTAL。
+func enterPortal(deps *Deps, p *world.PlayerInfo, x, y int32) {
+	r := packet.NewReader([]byte{219, byte(x), byte(x >> 8), byte(y), byte(y >> 8)})
+	HandleEnterPortal(p.Session, r, deps)
+}
+
+func TestTwoWayPortalCooldown(t *testing.T) {
+	// 相鄰雙向傳送門：A(32700,32800) → B 旁，B(32710,32800) → A 旁；兩者落點都在對方的入口上
+	path := filepath.Join(t.TempDir(), "portal_list.yaml")
+	body := `- {src_x: 32700, src_y: 32800, src_map_id: 4, dst_x: 32710, dst_y: 32800, dst_map_id: 4, dst_heading: 2}
+- {src_x: 32710, src_y: 32800, src_map_id: 4, dst_x: 32700, dst_y: 32800, dst_map_id: 4, dst_heading: 6}
+`
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	portals, err := data.LoadPortalTable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps := &Deps{Config: &config.Config{}, Log: zap.NewNop(), World: world.NewState(), Portals: portals}
+	deps.Config.World.PortalCooldownMs = 1000
+
+	p := &world.PlayerInfo{SessionID: 1, CharID: 1, Session: newTestSession(t), X: 32700, Y: 32800, MapID: 4}
+	deps.World.AddPlayer(p)
+
+	enterPortal(deps, p, 32700, 32800)
+	// 落點是回程入口 B，沿朝向（東）推離入口
+	if p.X != 32711 || p.Y != 32800 {
+		t.Fatalf("after portal A: at %d,%d, want 32711,32800", p.X, p.Y)
+	}
+	readyAt := p.PortalReadyAt
+
+	// 冷卻中踏上回程入口：不傳送、不重設冷卻
+	p.X = 32710
+	enterPortal(deps, p, 32710, 32800)
+	if p.X != 32710 || p.PortalReadyAt != readyAt {
+		t.Fatalf("portal B used during cooldown: at %d,%d", p.X, p.Y)
+	}
+
+	// 冷卻結束後可再次使用
+	p.PortalReadyAt = time.Now().Add(-time.Millisecond)
+	enterPortal(deps, p, 32710, 32800)
+	if p.X != 32699 {
+		t.Fatalf("after cooldown: at %d,%d, want 32699,32800", p.X, p.Y)
+	}
+}
+
+func TestPortalCooldownNotice(t *testing.T) {
+	for _, tc := range []struct {
+		left time.Duration
+		want string
+	}{
+		{300 * time.Millisecond, "剛通過傳送門，請於 1 秒後再進入。"},
+		{1500 * time.Millisecond, "剛通過傳送門，請於 2 秒後再進入。"},
+	} {
+		if got := portalCooldownNotice(tc.left); got != tc.want {
+			t.Errorf("portalCooldownNotice(%v) = %q, want %q", tc.left, got, tc.want)
+		}
+	}
+}
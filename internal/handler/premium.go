@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/l1jgo/server/internal/net"
+	"github.com/l1jgo/server/internal/persist"
+	"github.com/l1jgo/server/internal/world"
+	"go.uber.org/zap"
+)
+
+// applyPremiumBuffs 高級帳號（accounts.premium_until 未到期）進入遊戲時施加 [gameplay] premium_buffs。
+// 每次登入重新施加（覆蓋存檔恢復的同名 buff），持續時間與圖示依技能設定；
+// 記錄到期時間供 BuffTickSystem 到期收回。
+func applyPremiumBuffs(player *world.PlayerInfo, account *persist.AccountRow, deps *Deps) {
+	cfg := &deps.Config.Gameplay
+	if len(cfg.PremiumBuffs) == 0 || player.Dead || int(player.Level) < cfg.PremiumMinLevel {
+		return
+	}
+	if account == nil || !account.PremiumActive(time.Now()) {
+		return
+	}
+
+	applied := 0
+	for _, skillID := range cfg.PremiumBuffs {
+		if deps.Skill.ApplyGMBuff(player, skillID) {
+			applied++
+		}
+	}
+	if applied > 0 {
+		player.PremiumUntil = *account.PremiumUntil
+		SendSystemMessage(player.Session, fmt.Sprintf("高級帳號效果已啟動（期限至 %s）。",
+			account.PremiumUntil.Local().Format("2006-01-02 15:04")))
+	}
+}
+
+// RevokePremiumBuffs 收回高級帳號登入 buff（到期或 .premium off）。
+// Exported for system package usage.
+func RevokePremiumBuffs(player *world.PlayerInfo, deps *Deps) {
+	if player.PremiumUntil.IsZero() {
+		return
+	}
+	player.PremiumUntil = time.Time{}
+	for _, skillID := range deps.Config.Gameplay.PremiumBuffs {
+		if player.HasBuff(skillID) {
+			deps.Skill.RemoveBuffAndRevert(player, skillID)
+		}
+	}
+	player.Dirty = true
+	SendSystemMessage(player.Session, "高級帳號效果已結束。")
+}
+
+// gmPremium 設定或取消帳號的高級狀態；帳號角色在線時立即施加登入 buff。
+// 用法: .premium <帳號> <時間|off>
+func gmPremium(sess *net.Session, args []string, deps *Deps) {
+	if len(args) < 2 {
+		gmMsg(sess, "\\f3用法: .premium <帳號> <時間|off>  (時間例: 12h, 30d)")
+		return
+	}
+	account := strings.ToLower(args[0])
+	var until *time.Time
+	if args[1] != "off" {
+		t, err := parseBanDuration(args[1])
+		if err != nil || t == nil {
+			gmMsgf(sess, "\\f3無效的時間: %s", args[1])
+			return
+		}
+		until = t
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ok, err := deps.AccountRepo.SetPremium(ctx, account, until)
+	if err != nil {
+		deps.Log.Error("設定高級帳號失敗", zap.Error(err))
+		gmMsg(sess, "\\f3設定失敗")
+		return
+	}
+	if !ok {
+		gmMsgf(sess, "\\f3找不到帳號「%s」", account)
+		return
+	}
+
+	deps.Log.Info(fmt.Sprintf("GM 設定高級帳號  帳號=%s  期限=%s  GM=%s", account, args[1], sess.AccountName))
+	var online *world.PlayerInfo
+	if target := deps.World.Logins.SessionOf(account); target != nil {
+		online = deps.World.GetBySession(target.ID)
+	}
+	if until == nil {
+		gmMsgf(sess, "帳號「%s」已取消高級狀態", account)
+		if online != nil {
+			RevokePremiumBuffs(online, deps)
+		}
+		return
+	}
+	gmMsgf(sess, "帳號「%s」高級狀態至 %s", account, until.Local().Format("2006-01-02 15:04"))
+	if online != nil {
+		applyPremiumBuffs(online, &persist.AccountRow{Name: account, PremiumUntil: until}, deps)
+	}
+}
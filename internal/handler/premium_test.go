@@ -0,0 +1,90 @@
+package handler
+
+import (
+	stdnet "net"
+	"testing"
+	"time"
+
+	"github.com/l1jgo/server/internal/config"
+	"github.com/l1jgo/server/internal/net"
+	"github.com/l1jgo/server/internal/persist"
+	"github.com/l1jgo/server/internal/world"
+	"go.uber.org/zap"
+)
+
+// removeOnlySkills records RemoveBuffAndRevert calls; other SkillManager methods are unused.
+type removeOnlySkills struct {
+	SkillManager
+	removed []int32
+}
+
+func (s *removeOnlySkills) RemoveBuffAndRevert(p *world.PlayerInfo, skillID int32) {
+	s.removed = append(s.removed, skillID)
+	p.RemoveBuff(skillID)
+}
+
+func newTestSession(t *testing.T) *net.Session {
+	c1, c2 := stdnet.Pipe()
+	t.Cleanup(func() { c1.Close(); c2.Close() })
+	return net.NewSession(c1, 1, 4, 16, 0, 0, zap.NewNop())
+}
+
+func TestRevokePremiumBuffsRemovesOnlyPremiumSkills(t *testing.T) {
+	skills := &removeOnlySkills{}
+	deps := &Deps{Config: &config.Config{}, Skill: skills}
+	deps.Config.Gameplay.PremiumBuffs = []int32{26, 42, 43}
+
+	p := &world.PlayerInfo{Session: newTestSession(t)}
+	for _, id := range []int32{26, 43, 1} {
+		p.AddBuff(&world.ActiveBuff{SkillID: id, TicksLeft: 100})
+	}
+
+	RevokePremiumBuffs(p, deps)
+	if len(skills.removed) != 0 {
+		t.Fatalf("revoked %v from a player without premium buffs", skills.removed)
+	}
+
+	p.PremiumUntil = time.Now().Add(time.Hour)
+	RevokePremiumBuffs(p, deps)
+	if !p.PremiumUntil.IsZero() {
+		t.Error("PremiumUntil not cleared")
+	}
+	if len(skills.removed) != 2 || p.HasBuff(26) || p.HasBuff(43) {
+		t.Errorf("removed %v, want the premium buffs 26 and 43", skills.removed)
+	}
+	if !p.HasBuff(1) {
+		t.Error("non-premium buff 1 was removed")
+	}
+}
+
+// applyGMBuffSkills records ApplyGMBuff calls; other SkillManager methods are unused.
+type applyGMBuffSkills struct {
+	SkillManager
+	applied []int32
+}
+
+func (s *applyGMBuffSkills) ApplyGMBuff(p *world.PlayerInfo, skillID int32) bool {
+	s.applied = append(s.applied, skillID)
+	return true
+}
+
+// 高級狀態取自呼叫端已載入的帳號列（AccountRepo 為 nil：再次查詢即 panic）。
+func TestApplyPremiumBuffsUsesLoadedAccount(t *testing.T) {
+	skills := &applyGMBuffSkills{}
+	deps := &Deps{Config: &config.Config{}, Skill: skills, Log: zap.NewNop()}
+	deps.Config.Gameplay.PremiumBuffs = []int32{26, 42}
+	p := &world.PlayerInfo{Session: newTestSession(t)}
+
+	expired := time.Now().Add(-time.Hour)
+	applyPremiumBuffs(p, nil, deps)
+	applyPremiumBuffs(p, &persist.AccountRow{PremiumUntil: &expired}, deps)
+	if len(skills.applied) != 0 {
+		t.Fatalf("applied %v without an active premium", skills.applied)
+	}
+
+	until := time.Now().Add(time.Hour)
+	applyPremiumBuffs(p, &persist.AccountRow{PremiumUntil: &until}, deps)
+	if len(skills.applied) != 2 || !p.PremiumUntil.Equal(until) {
+		t.Errorf("applied %v until %v, want buffs 26 and 42 until %v", skills.applied, p.PremiumUntil, until)
+	}
+}
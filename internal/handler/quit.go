@@ -12,5 +12,6 @@ import (
 // We just close the session; InputSystem.handleDisconnect does all cleanup.
 func HandleQuit(sess *net.Session, _ *packet.Reader, deps *Deps) {
 	deps.Log.Info(fmt.Sprintf("玩家登出  session=%d  帳號=%s", sess.ID, sess.AccountName))
+	sess.Logout = true // 主動登出不進入斷線保留
 	sess.Close()
 }
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"github.com/l1jgo/server/internal/net"
+	"github.com/l1jgo/server/internal/net/packet"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// windowRefuseOption C_Windows 子類型：社交拒絕選項切換。
+const windowRefuseOption = 0x30
+
+// refuseOption 一項社交拒絕選項：C_Windows 0x30 的 option 值、.refuse 指令參數、顯示名稱與對應旗標。
+type refuseOption struct {
+	id    byte
+	key   string
+	label string
+	flag  func(p *world.PlayerInfo) *bool
+}
+
+var refuseOptions = []refuseOption{
+	{0, "whisper", "密語", func(p *world.PlayerInfo) *bool { return &p.RefuseWhisper }},
+	{1, "party", "組隊邀請", func(p *world.PlayerInfo) *bool { return &p.RefuseParty }},
+	{2, "trade", "交易", func(p *world.PlayerInfo) *bool { return &p.RefuseTrade }},
+	{3, "duel", "決鬥", func(p *world.PlayerInfo) *bool { return &p.RefuseDuel }},
+}
+
+// HandleRefuseOption 處理社交拒絕選項切換（由 HandleWindows 分派）。
+// 格式：[C option][C on]（option: 0=密語 1=組隊 2=交易 3=決鬥；on: 0=接受, 1=拒絕）
+// 設定後回傳目前狀態給客戶端，並經由寫入佇列存入 characters。
+func HandleRefuseOption(sess *net.Session, player *world.PlayerInfo, r *packet.Reader, deps *Deps) {
+	option := r.ReadC()
+	on := r.ReadC() != 0
+	for _, opt := range refuseOptions {
+		if opt.id != option {
+			continue
+		}
+		flag := opt.flag(player)
+		if *flag != on {
+			*flag = on
+			saveRefuseFlags(player, deps)
+		}
+		SendSystemMessage(sess, refuseState(opt.label, on))
+		return
+	}
+}
+
+// refuseCommand 處理 .refuse 指令（不送 C_Windows 0x30 的客戶端以聊天指令切換）。
+// 無參數列出目前狀態；".refuse <whisper|party|trade|duel>" 切換該項並寫入 characters。
+func refuseCommand(player *world.PlayerInfo, args []string, deps *Deps) []string {
+	if len(args) == 0 {
+		lines := []string{"用法: .refuse <whisper|party|trade|duel>"}
+		for _, opt := range refuseOptions {
+			lines = append(lines, refuseState(opt.label, *opt.flag(player)))
+		}
+		return lines
+	}
+	for _, opt := range refuseOptions {
+		if opt.key != args[0] {
+			continue
+		}
+		flag := opt.flag(player)
+		*flag = !*flag
+		saveRefuseFlags(player, deps)
+		return []string{refuseState(opt.label, *flag)}
+	}
+	keys := make([]string, len(refuseOptions))
+	for i, opt := range refuseOptions {
+		keys[i] = opt.key
+	}
+	return []string{"未知的選項，可用: " + strings.Join(keys, " / ")}
+}
+
+// saveRefuseFlags 經由寫入佇列依序儲存拒絕選項。
+func saveRefuseFlags(player *world.PlayerInfo, deps *Deps) {
+	if deps.CharRepo == nil {
+		return
+	}
+	charID := player.CharID
+	whisper, party, trade, duel := player.RefuseWhisper, player.RefuseParty, player.RefuseTrade, player.RefuseDuel
+	QueueWrite(deps, "refuse_flags", func(ctx context.Context) error {
+		return deps.CharRepo.SaveRefuseFlags(ctx, charID, whisper, party, trade, duel)
+	})
+}
+
+// refuseState 拒絕選項目前狀態的提示文字。
+func refuseState(label string, on bool) string {
+	if on {
+		return "拒絕" + label + "：開啟"
+	}
+	return "拒絕" + label + "：關閉"
+}
+
+// sendRefused 通知發起者對方已拒絕此類請求。
+func sendRefused(sess *net.Session, targetName, label string) {
+	SendGlobalChat(sess, 9, targetName+" 目前拒絕"+label+"。")
+}
+
+// SendRefused 匯出 sendRefused — 供 system 套件（組隊 / 交易）使用。
+func SendRefused(sess *net.Session, targetName, label string) {
+	sendRefused(sess, targetName, label)
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/l1jgo/server/internal/net/packet"
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestRefuseCommandTogglesFlags(t *testing.T) {
+	p := &world.PlayerInfo{}
+	deps := &Deps{}
+
+	if msgs := refuseCommand(p, []string{"trade"}, deps); !p.RefuseTrade || msgs[0] != "拒絕交易：開啟" {
+		t.Fatalf("first toggle: RefuseTrade=%v msgs=%q", p.RefuseTrade, msgs)
+	}
+	if p.RefuseWhisper || p.RefuseParty || p.RefuseDuel {
+		t.Fatal("toggling trade changed another flag")
+	}
+	if msgs := refuseCommand(p, []string{"trade"}, deps); p.RefuseTrade || msgs[0] != "拒絕交易：關閉" {
+		t.Fatalf("second toggle: RefuseTrade=%v msgs=%q", p.RefuseTrade, msgs)
+	}
+
+	refuseCommand(p, []string{"duel"}, deps)
+	list := refuseCommand(p, nil, deps)
+	if len(list) != 1+len(refuseOptions) || !strings.Contains(strings.Join(list, "\n"), "拒絕決鬥：開啟") {
+		t.Errorf("status list = %q", list)
+	}
+
+	if msgs := refuseCommand(p, []string{"hug"}, deps); !strings.Contains(msgs[0], "whisper") {
+		t.Errorf("unknown option reply = %q", msgs)
+	}
+}
+
+func TestRefuseOptionPacketSetsAndEchoes(t *testing.T) {
+	sess := newTestSession(t)
+	p := &world.PlayerInfo{Session: sess}
+	deps := &Deps{}
+
+	// [opcode][option=3 決鬥][on=1]
+	HandleRefuseOption(sess, p, packet.NewReader([]byte{0, 3, 1}), deps)
+	if !p.RefuseDuel || p.RefuseWhisper || p.RefuseParty || p.RefuseTrade {
+		t.Fatalf("flags after duel on: %+v", []bool{p.RefuseWhisper, p.RefuseParty, p.RefuseTrade, p.RefuseDuel})
+	}
+	sess.FlushOutput()
+	if len(sess.OutQueue) != 1 {
+		t.Fatalf("sent %d packets, want the state echo", len(sess.OutQueue))
+	}
+	<-sess.OutQueue
+
+	HandleRefuseOption(sess, p, packet.NewReader([]byte{0, 3, 0}), deps)
+	if p.RefuseDuel {
+		t.Fatal("duel refuse still on after on=0")
+	}
+
+	HandleRefuseOption(sess, p, packet.NewReader([]byte{0, 9, 1}), deps)
+	sess.FlushOutput()
+	if n := len(sess.OutQueue); n != 1 {
+		t.Errorf("unknown option: %d packets queued, want only the previous echo", n)
+	}
+}
@@ -19,6 +19,25 @@ func HandleFixWeaponList(sess *net.Session, _ *packet.Reader, deps *Deps) {
 	if player == nil || player.Inv == nil {
 		return
 	}
+	sendFixWeaponList(sess, player, deps)
+}
+
+// handleNpcRepair 修理 NPC 對話動作 "fix" / "repair"：開啟武器修理列表（僅限 repair.npc_ids）。
+func handleNpcRepair(sess *net.Session, player *world.PlayerInfo, npc *world.NpcInfo, deps *Deps) {
+	if !deps.NpcServices.Repair().IsRepairNpc(npc.NpcID) {
+		return
+	}
+	if !sendFixWeaponList(sess, player, deps) {
+		SendSystemMessage(sess, "沒有需要修理的武器。")
+	}
+}
+
+// sendFixWeaponList sends S_FixWeaponList with every damaged weapon in the
+// player's inventory. Returns false when there is nothing to repair.
+func sendFixWeaponList(sess *net.Session, player *world.PlayerInfo, deps *Deps) bool {
+	if player.Inv == nil {
+		return false
+	}
 
 	// Collect all damaged weapons in inventory
 	type damagedWeapon struct {
@@ -43,7 +62,7 @@ func HandleFixWeaponList(sess *net.Session, _ *packet.Reader, deps *Deps) {
 	}
 
 	if len(weapons) == 0 {
-		return // no damaged weapons to repair
+		return false // no damaged weapons to repair
 	}
 
 	// Build S_FixWeaponList (opcode 83)
@@ -56,6 +75,18 @@ func HandleFixWeaponList(sess *net.Session, _ *packet.Reader, deps *Deps) {
 		w.WriteC(byte(wpn.durability))
 	}
 	sess.Send(w.Bytes())
+	return true
+}
+
+// repairCost returns the adena fee to fully repair a damaged weapon:
+// damage points × repair_cost_per_durability, raised by enchant_pct% per
+// positive enchant level (npc_services.yaml repair).
+func repairCost(item *world.InvItem, deps *Deps) int32 {
+	cost := int64(item.Durability) * int64(deps.Config.Gameplay.RepairCostPerDurability)
+	if item.EnchantLvl > 0 {
+		cost += cost * int64(item.EnchantLvl) * int64(deps.NpcServices.Repair().EnchantPct) / 100
+	}
+	return int32(min(cost, math.MaxInt32))
 }
 
 // HandleSelectList processes C_PERSONAL_SHOP (opcode 20) — weapon repair selection.
@@ -78,7 +109,7 @@ func HandleSelectList(sess *net.Session, r *packet.Reader, deps *Deps) {
 
 	// Validate NPC exists and is within range (Chebyshev <= 3)
 	npc := deps.World.GetNpc(npcObjID)
-	if npc == nil {
+	if npc == nil || !deps.NpcServices.Repair().IsRepairNpc(npc.NpcID) {
 		return
 	}
 	dx := int32(math.Abs(float64(player.X - npc.X)))
@@ -100,9 +131,10 @@ func HandleSelectList(sess *net.Session, r *packet.Reader, deps *Deps) {
 	}
 
 	// Calculate repair cost
-	cost := int32(item.Durability) * int32(deps.Config.Gameplay.RepairCostPerDurability)
+	cost := repairCost(item, deps)
 	if !consumeAdena(player, cost) {
-		return // insufficient adena (silent fail, matching Java behavior)
+		sendServerMessage(sess, 189) // "金幣不足。"
+		return
 	}
 	sendAdenaUpdate(sess, player)
 
@@ -112,6 +144,10 @@ func HandleSelectList(sess *net.Session, r *packet.Reader, deps *Deps) {
 	// Send inventory update for the repaired weapon
 	sendItemCountUpdate(sess, item)
 	sendWeightUpdate(sess, player)
+	SendSystemMessage(sess, fmt.Sprintf("%s 已修理完成，花費 %d 金幣。", itemInfo.Name, cost))
+	if gfx := deps.NpcServices.Repair().Gfx; gfx > 0 {
+		broadcastEffect(sess, player, gfx, deps)
+	}
 
 	deps.Log.Info(fmt.Sprintf("武器修理完成  角色=%s  武器=%s  花費=%d",
 		player.Name, itemInfo.Name, cost))
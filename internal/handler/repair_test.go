@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/l1jgo/server/internal/config"
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestRepairCostScalesWithEnchant(t *testing.T) {
+	svc, err := data.LoadNpcServiceTable("../../data/yaml/npc_services.yaml")
+	if err != nil {
+		t.Fatalf("LoadNpcServiceTable: %v", err)
+	}
+	cfg := &config.Config{}
+	cfg.Gameplay.RepairCostPerDurability = 100
+	deps := &Deps{Config: cfg, NpcServices: svc}
+	pct := int32(svc.Repair().EnchantPct)
+
+	plain := &world.InvItem{Durability: 5}
+	if got := repairCost(plain, deps); got != 500 {
+		t.Errorf("unenchanted cost = %d, want 500", got)
+	}
+	enchanted := &world.InvItem{Durability: 5, EnchantLvl: 3}
+	if got, want := repairCost(enchanted, deps), 500+500*3*pct/100; got != want {
+		t.Errorf("+3 cost = %d, want %d", got, want)
+	}
+	negative := &world.InvItem{Durability: 5, EnchantLvl: -2}
+	if got := repairCost(negative, deps); got != 500 {
+		t.Errorf("-2 cost = %d, want 500 (no discount)", got)
+	}
+}
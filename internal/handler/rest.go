@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/world"
+)
+
+const skillMeditation int32 = 32 // 冥想術
+
+// setResting 切換坐下休息。坐下期間 HP/MP 回復依 rest_regen_pct 加成，移動或攻擊時自動起身。
+// 3.80C 角色圖檔沒有坐姿，以沉思動作（Alt+4，ActThink）表示坐下，起身播放待機動作（ActIdle）。
+// 回傳給玩家的提示訊息（空字串 = 不回應）。
+func setResting(player *world.PlayerInfo, sit bool, deps *Deps) string {
+	if !sit {
+		if !player.Resting {
+			return "你沒有坐著。"
+		}
+		StandUp(player, deps)
+		return "你站了起來。"
+	}
+	if player.Resting {
+		return "你已經坐著了。"
+	}
+	if player.Dead || player.Paralyzed || player.Sleeped {
+		return ""
+	}
+	player.Resting = true
+	broadcastRestPose(player, data.ActThink, deps)
+	return "你坐下休息（移動或攻擊會自動起身）。"
+}
+
+// StandUp 移動或攻擊時自動起身，並結束冥想術（Java: C_MoveChar 移除 MEDITATION）。
+// Exported for system package usage.
+func StandUp(player *world.PlayerInfo, deps *Deps) {
+	if player.Resting {
+		player.Resting = false
+		broadcastRestPose(player, data.ActIdle, deps)
+	}
+	if player.HasBuff(skillMeditation) && deps.Skill != nil {
+		deps.Skill.RemoveBuffAndRevert(player, skillMeditation)
+	}
+}
+
+// broadcastRestPose 向周圍玩家（含自己）播放坐下/起身動作。
+func broadcastRestPose(player *world.PlayerInfo, action byte, deps *Deps) {
+	nearby := deps.World.GetNearbyPlayersAt(player.X, player.Y, player.MapID)
+	BroadcastToPlayers(nearby, BuildActionGfx(player.CharID, action))
+}
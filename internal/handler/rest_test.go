@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/net/packet"
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestSetRestingToggles(t *testing.T) {
+	deps := &Deps{World: world.NewState()}
+	p := &world.PlayerInfo{}
+
+	if msg := setResting(p, false, deps); p.Resting || msg == "" {
+		t.Fatalf("stand while standing: resting=%v msg=%q", p.Resting, msg)
+	}
+	setResting(p, true, deps)
+	if !p.Resting {
+		t.Fatal(".sit did not sit the player down")
+	}
+	StandUp(p, deps)
+	if p.Resting {
+		t.Fatal("StandUp left the player sitting")
+	}
+
+	p.Paralyzed = true
+	if msg := setResting(p, true, deps); p.Resting || msg != "" {
+		t.Fatalf("paralyzed player sat down (msg %q)", msg)
+	}
+}
+
+// 坐下／起身動作需廣播給周圍玩家（包含自己）。
+func TestRestPoseBroadcast(t *testing.T) {
+	deps := &Deps{World: world.NewState()}
+	sitter := &world.PlayerInfo{SessionID: 1, Session: newTestSession(t), CharID: 10, X: 32700, Y: 32800, MapID: 4}
+	viewer := &world.PlayerInfo{SessionID: 2, Session: newTestSession(t), CharID: 20, X: 32702, Y: 32800, MapID: 4}
+	deps.World.AddPlayer(sitter)
+	deps.World.AddPlayer(viewer)
+
+	poses := func(p *world.PlayerInfo) []byte {
+		p.Session.FlushOutput()
+		var got []byte
+		for len(p.Session.OutQueue) > 0 {
+			if pkt := <-p.Session.OutQueue; pkt[0] == packet.S_OPCODE_ACTION {
+				got = append(got, pkt[5]) // [C opcode][D objectID][C action]
+			}
+		}
+		return got
+	}
+
+	setResting(sitter, true, deps)
+	StandUp(sitter, deps)
+	StandUp(sitter, deps) // 已站立：不再廣播
+	for _, p := range []*world.PlayerInfo{sitter, viewer} {
+		got := poses(p)
+		if len(got) != 2 || got[0] != data.ActThink || got[1] != data.ActIdle {
+			t.Errorf("%d saw poses %v, want [%d %d]", p.CharID, got, data.ActThink, data.ActIdle)
+		}
+	}
+}
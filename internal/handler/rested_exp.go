@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/l1jgo/server/internal/world"
+)
+
+// restedExpSpan 目前等級升級所需經驗（休息經驗累積與上限的基準）。
+func restedExpSpan(player *world.PlayerInfo, deps *Deps) int64 {
+	lv := int(player.Level)
+	return int64(deps.Scripting.ExpForLevel(lv+1) - deps.Scripting.ExpForLevel(lv))
+}
+
+// accrueRestedExp 登入時依離線時間累積休息經驗（[gameplay] rested_exp_*），並提示剩餘額度。
+// lastLogout 為 nil（從未存檔）時不累積；上限以目前等級計算，已超過上限的池不會被削減。
+func accrueRestedExp(player *world.PlayerInfo, lastLogout *time.Time, deps *Deps) {
+	cfg := &deps.Config.Gameplay
+	if cfg.RestedExpBonusPct <= 0 || player.Level >= 99 {
+		return
+	}
+	span := restedExpSpan(player, deps)
+	if lastLogout != nil && span > 0 && cfg.RestedExpPerHourPct > 0 {
+		if offline := time.Since(*lastLogout); offline > 0 {
+			gain := int64(float64(span) * float64(cfg.RestedExpPerHourPct) / 100 * offline.Hours())
+			limit := span * int64(cfg.RestedExpCapPct) / 100
+			if gain > 0 && player.RestedXP < limit {
+				player.RestedXP = min(player.RestedXP+gain, limit)
+				player.Dirty = true
+			}
+		}
+	}
+	if player.RestedXP > 0 {
+		SendSystemMessage(player.Session, fmt.Sprintf("休息經驗：剩餘 %d 點（擊殺經驗 +%d%%）。",
+			player.RestedXP, cfg.RestedExpBonusPct))
+	}
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/l1jgo/server/internal/config"
+	"github.com/l1jgo/server/internal/scripting"
+	"github.com/l1jgo/server/internal/world"
+	"go.uber.org/zap"
+)
+
+func newRestedDeps(t *testing.T) *Deps {
+	t.Helper()
+	engine, err := scripting.NewEngine("../../scripts", zap.NewNop())
+	if err != nil {
+		t.Fatalf("load scripts: %v", err)
+	}
+	t.Cleanup(engine.Close)
+	deps := &Deps{Config: &config.Config{}, Scripting: engine}
+	deps.Config.Gameplay.RestedExpBonusPct = 50
+	deps.Config.Gameplay.RestedExpPerHourPct = 5
+	deps.Config.Gameplay.RestedExpCapPct = 150
+	return deps
+}
+
+func TestAccrueRestedExp(t *testing.T) {
+	deps := newRestedDeps(t)
+	span := restedExpSpan(&world.PlayerInfo{Level: 20}, deps)
+	if span <= 0 {
+		t.Fatalf("level 20 EXP span = %d", span)
+	}
+	ago := func(h float64) *time.Time {
+		ts := time.Now().Add(-time.Duration(h * float64(time.Hour)))
+		return &ts
+	}
+
+	tests := []struct {
+		name       string
+		pool       int64
+		lastLogout *time.Time
+		want       int64
+	}{
+		{"從未存檔", 0, nil, 0},
+		{"離線十小時", 0, ago(10), span / 2},
+		{"累積至上限", span, ago(100), span * 3 / 2},
+		{"超過上限不削減", span * 2, ago(10), span * 2},
+	}
+	for _, tt := range tests {
+		sess := newTestSession(t)
+		p := &world.PlayerInfo{Session: sess, Level: 20, RestedXP: tt.pool}
+		accrueRestedExp(p, tt.lastLogout, deps)
+		// 容許登入耗時造成的微小誤差
+		if d := p.RestedXP - tt.want; d < 0 || d > span/1000 {
+			t.Errorf("%s: pool = %d, want %d", tt.name, p.RestedXP, tt.want)
+		}
+		sess.FlushOutput()
+		if got := len(sess.OutQueue) > 0; got != (p.RestedXP > 0) {
+			t.Errorf("%s: login message sent = %v with pool %d", tt.name, got, p.RestedXP)
+		}
+	}
+}
+
+func TestAccrueRestedExpDisabled(t *testing.T) {
+	deps := newRestedDeps(t)
+	deps.Config.Gameplay.RestedExpBonusPct = 0
+
+	sess := newTestSession(t)
+	p := &world.PlayerInfo{Session: sess, Level: 20, RestedXP: 100}
+	accrueRestedExp(p, func() *time.Time { ts := time.Now().Add(-24 * time.Hour); return &ts }(), deps)
+	if p.RestedXP != 100 || p.Dirty {
+		t.Errorf("disabled rested EXP changed the pool to %d", p.RestedXP)
+	}
+	sess.FlushOutput()
+	if len(sess.OutQueue) != 0 {
+		t.Error("disabled rested EXP sent a login message")
+	}
+}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"github.com/l1jgo/server/internal/data"
+	"go.uber.org/zap"
+)
+
+// msgCheck 啟用時比對每個送出的 S_ServerMessage 參數數量與訊息字串表。
+// 僅在 debug 日誌等級啟用（啟動時設定一次，之後唯讀）。
+var msgCheck struct {
+	table *data.MessageTable
+	log   *zap.Logger
+}
+
+// EnableMessageCheck 啟用 S_ServerMessage 參數數量檢查。由 main 在 debug 日誌等級下呼叫。
+func EnableMessageCheck(table *data.MessageTable, log *zap.Logger) {
+	msgCheck.table = table
+	msgCheck.log = log
+}
+
+// checkMessageArgs 參數數量與字串表不符時記錄警告（含預覽文字）。未啟用時無動作。
+func checkMessageArgs(msgID uint16, args []string) {
+	if msgCheck.table == nil {
+		return
+	}
+	if err := msgCheck.table.Validate(msgID, len(args)); err != nil {
+		msgCheck.log.Warn("S_ServerMessage 參數數量不符",
+			zap.Error(err),
+			zap.Strings("args", args),
+			zap.String("preview", msgCheck.table.Preview(msgID, args...)))
+	}
+}
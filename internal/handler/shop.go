@@ -26,6 +26,14 @@ func HandleBuySell(sess *net.Session, r *packet.Reader, deps *Deps) {
 		return
 	}
 
+	// 郵差 NPC 包裹附件選擇（借用販賣視窗，resultType=1）
+	if resultType == 1 && player.ParcelNpcObjID != 0 && player.ParcelNpcObjID == npcObjID {
+		if deps.Mail != nil {
+			deps.Mail.StageParcel(sess, r, count, player)
+		}
+		return
+	}
+
 	npc := deps.World.GetNpc(npcObjID)
 	if npc == nil {
 		return
@@ -109,6 +117,7 @@ func sendItemCountUpdate(sess *net.Session, item *world.InvItem) {
 
 // sendServerMessage sends S_MESSAGE_CODE (opcode 71) — system message by ID.
 func sendServerMessage(sess *net.Session, msgID uint16) {
+	checkMessageArgs(msgID, nil)
 	w := packet.NewWriterWithOpcode(packet.S_OPCODE_MESSAGE_CODE)
 	w.WriteH(msgID) // message ID in client string table
 	w.WriteC(0)     // no arguments
@@ -118,6 +127,7 @@ func sendServerMessage(sess *net.Session, msgID uint16) {
 // sendServerMessageArgs sends S_MESSAGE_CODE (opcode 71) with string arguments.
 // The client substitutes %0, %1, ... with the provided args.
 func sendServerMessageArgs(sess *net.Session, msgID uint16, args ...string) {
+	checkMessageArgs(msgID, args)
 	w := packet.NewWriterWithOpcode(packet.S_OPCODE_MESSAGE_CODE)
 	w.WriteH(msgID)
 	w.WriteC(byte(len(args)))
@@ -196,6 +206,30 @@ func SendRemoveInventoryItem(sess *net.Session, objectID int32) {
 	sendRemoveInventoryItem(sess, objectID)
 }
 
+// ConsolidateInventory 合併背包中重複的可堆疊物品（world.Inventory.Consolidate），
+// 並通知客戶端：被併入的堆疊移除、保留的堆疊更新數量。sess 為 nil 時只合併不發封包
+// （例如登入時尚未送出背包清單）。回傳合併的堆疊數。
+func ConsolidateInventory(sess *net.Session, player *world.PlayerInfo) int {
+	merges := player.Inv.Consolidate()
+	if len(merges) == 0 {
+		return 0
+	}
+	player.Dirty = true
+	if sess != nil {
+		updated := make(map[int32]bool, len(merges))
+		for _, m := range merges {
+			sendRemoveInventoryItem(sess, m.RemovedID)
+			updated[m.Into.ObjectID] = true
+		}
+		for _, it := range player.Inv.Items {
+			if updated[it.ObjectID] {
+				sendItemCountUpdate(sess, it)
+			}
+		}
+	}
+	return len(merges)
+}
+
 // SendServerMessage 匯出 sendServerMessage — 供 system 套件發送系統訊息。
 func SendServerMessage(sess *net.Session, msgID uint16) {
 	sendServerMessage(sess, msgID)
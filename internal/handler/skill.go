@@ -115,7 +115,7 @@ func RevertBuffStats(target *world.PlayerInfo, buff *world.ActiveBuff) {
 		target.MP = target.MaxMP
 	}
 	if buff.SetInvisible {
-		target.Invisible = false
+		target.Invisible = target.GMInvisible // GM 隱身不隨 buff 結束
 	}
 	if buff.SetParalyzed {
 		target.Paralyzed = false
@@ -213,7 +213,7 @@ func sendBuffIcon(target *world.PlayerInfo, skillID int32, durationSec uint16, d
 	case "dexup":
 		sendIconDexup(sess, durationSec, byte(target.Dex), icon.Param)
 	case "aura":
-		sendIconAura(sess, byte(skillID-1), durationSec)
+		sendIconAura(sess, icon.Param, durationSec)
 	case "invis":
 		sendInvisible(sess, target.CharID, durationSec > 0)
 	case "wisdom":
@@ -248,6 +248,23 @@ func sendSkillList(sess *net.Session, skills []*data.SkillInfo) {
 	sess.Send(w.Bytes())
 }
 
+// sendAllSpells re-sends the complete known-spell list (player.KnownSpells) to
+// the client — on enter-world and after bulk changes such as GM .learn.
+// Only the global cast delay exists (SkillDelayUntil, not persisted), so there
+// are no per-skill reuse timers to restore after relogin.
+func sendAllSpells(sess *net.Session, player *world.PlayerInfo, deps *Deps) {
+	if deps.Skills == nil {
+		return
+	}
+	var spells []*data.SkillInfo
+	for _, sid := range player.KnownSpells {
+		if sk := deps.Skills.Get(sid); sk != nil {
+			spells = append(spells, sk)
+		}
+	}
+	sendSkillList(sess, spells)
+}
+
 // sendAddSingleSkill sends S_AddSkill (opcode 164) — notifies the client a new spell was learned.
 // Uses S_AddSkill format: [C pageSize][28 bytes bitmask][D 0][D 0].
 func sendAddSingleSkill(sess *net.Session, skill *data.SkillInfo) {
@@ -0,0 +1,32 @@
+package handler
+
+import "github.com/l1jgo/server/internal/world"
+
+// grantSpawnProtect 登入或傳送完成後給予短暫保護（客戶端仍在載入地圖時不被怪物秒殺）。
+// 期間 NPC 不會主動索敵也不會造成傷害；持續 [combat] spawn_protect_sec 秒，
+// 玩家移動或發動攻擊時提前解除。戰鬥區域（PvP 區）不給予。
+// 不顯示 buff 圖示：客戶端沒有專用圖示，借用護盾圖示會在到期時取消真正的護盾圖示。
+func grantSpawnProtect(player *world.PlayerInfo, deps *Deps) {
+	sec := deps.Config.Combat.SpawnProtectSec
+	if sec <= 0 || player.Dead {
+		return
+	}
+	if deps.MapData != nil && deps.MapData.IsCombatZone(player.MapID, player.X, player.Y) {
+		return
+	}
+	player.AddBuff(&world.ActiveBuff{
+		SkillID:   SkillStatusSpawnProtect,
+		TicksLeft: sec * 5, // 秒 → ticks
+	})
+}
+
+// ClearSpawnProtect 提前解除登入/傳送保護（移動、近戰/遠程攻擊、施放攻擊技能時）。
+// Exported for system package usage.
+func ClearSpawnProtect(player *world.PlayerInfo, deps *Deps) {
+	player.RemoveBuff(SkillStatusSpawnProtect)
+}
+
+// HasSpawnProtect 回傳玩家是否仍在登入/傳送保護中。
+func HasSpawnProtect(player *world.PlayerInfo) bool {
+	return player.HasBuff(SkillStatusSpawnProtect)
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/l1jgo/server/internal/config"
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestSpawnProtectGrantAndClear(t *testing.T) {
+	deps := &Deps{Config: &config.Config{}}
+	deps.Config.Combat.SpawnProtectSec = 3
+	p := &world.PlayerInfo{}
+
+	grantSpawnProtect(p, deps)
+	if !HasSpawnProtect(p) {
+		t.Fatal("protection not granted")
+	}
+	if b := p.ActiveBuffs[SkillStatusSpawnProtect]; b.TicksLeft != 15 {
+		t.Fatalf("TicksLeft = %d, want 15", b.TicksLeft)
+	}
+	ClearSpawnProtect(p, deps)
+	if HasSpawnProtect(p) {
+		t.Fatal("protection not cleared")
+	}
+
+	deps.Config.Combat.SpawnProtectSec = 0
+	grantSpawnProtect(p, deps)
+	if HasSpawnProtect(p) {
+		t.Fatal("granted with spawn_protect_sec = 0")
+	}
+}
+
+// 登入保護不可借用真實技能的圖示，否則到期時會取消該技能（例如護盾）的圖示。
+func TestSpawnProtectHasNoBuffIcon(t *testing.T) {
+	icons, err := data.LoadBuffIconTable("../../data/yaml/buff_icon_map.yaml")
+	if err != nil {
+		t.Fatalf("load buff icons: %v", err)
+	}
+	if icon := icons.Get(SkillStatusSpawnProtect); icon != nil {
+		t.Fatalf("spawn protection mapped to icon %+v", icon)
+	}
+}
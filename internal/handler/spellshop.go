@@ -27,6 +27,12 @@ func openSpellShop(sess *net.Session, deps *Deps) {
 
 	available := getAvailableSpells(player, deps)
 	if len(available) == 0 {
+		// 沒有可學魔法：提示下一階的等級需求（若有）
+		if next := nextSpellTierLevel(player, deps); next > 0 {
+			SendSystemMessage(sess, fmt.Sprintf("目前沒有可學習的魔法，下一階魔法需要等級 %d。", next))
+		} else {
+			SendSystemMessage(sess, "目前沒有可學習的魔法。")
+		}
 		return
 	}
 
@@ -73,18 +79,25 @@ func HandleBuyableSpell(sess *net.Session, r *packet.Reader, deps *Deps) {
 	totalCost := int32(0)
 	var validSpells []*data.SkillInfo
 	for _, skillID := range requested {
-		if knownSet[skillID] {
-			continue // already known
-		}
 		skill := deps.Skills.Get(skillID)
 		if skill == nil {
 			continue
 		}
+		if knownSet[skillID] {
+			SendSystemMessage(sess, fmt.Sprintf("已經學會 %s。", skill.Name))
+			continue
+		}
 		// Find the matching tier for this skill
 		cost, ok := getSpellCost(skill, tiers, int(player.Level))
 		if !ok {
-			continue // class/level can't learn this
+			if minLevel := spellTierMinLevel(skill, tiers); minLevel > 0 {
+				SendSystemMessage(sess, fmt.Sprintf("%s 需要等級 %d 才能學習。", skill.Name, minLevel))
+			} else {
+				SendSystemMessage(sess, fmt.Sprintf("你的職業無法學習 %s。", skill.Name))
+			}
+			continue
 		}
+		knownSet[skillID] = true // 同一請求重複選取只收一次費用
 		totalCost += cost
 		validSpells = append(validSpells, skill)
 	}
@@ -166,6 +179,38 @@ func getSpellCost(skill *data.SkillInfo, tiers []scripting.SpellTierInfo, charLe
 	return 0, false // wrong class
 }
 
+// spellTierMinLevel returns the character level required by the class tier
+// containing the skill, or 0 if the class has no tier for it.
+func spellTierMinLevel(skill *data.SkillInfo, tiers []scripting.SpellTierInfo) int {
+	for _, tier := range tiers {
+		if skill.SkillLevel >= tier.MinSkillLevel && skill.SkillLevel <= tier.MaxSkillLevel {
+			return tier.MinCharLevel
+		}
+	}
+	return 0
+}
+
+// nextSpellTierLevel returns the lowest tier level above the player's current
+// level that still has unlearned spells, or 0 if there is none.
+func nextSpellTierLevel(player *world.PlayerInfo, deps *Deps) int {
+	tiers := deps.Scripting.GetSpellTiers(int(player.ClassType))
+	knownSet := make(map[int32]bool, len(player.KnownSpells))
+	for _, sid := range player.KnownSpells {
+		knownSet[sid] = true
+	}
+	next := 0
+	for _, sk := range deps.Skills.All() {
+		if knownSet[sk.SkillID] || sk.Name == "none" || sk.Name == "" {
+			continue
+		}
+		lv := spellTierMinLevel(sk, tiers)
+		if lv > int(player.Level) && (next == 0 || lv < next) {
+			next = lv
+		}
+	}
+	return next
+}
+
 // sendSkillBuy sends S_SkillBuy (opcode 23) — lists available spells for purchase.
 func sendSkillBuy(sess *net.Session, skills []*data.SkillInfo) {
 	w := packet.NewWriterWithOpcode(packet.S_OPCODE_SKILL_BUY)
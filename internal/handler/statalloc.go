@@ -69,36 +69,42 @@ func handleStatAlloc(sess *net.Session, attrCode uint16, confirm byte, r *packet
 			return
 		}
 		player.Str++
+		player.BonusAlloc[world.StatStr]++
 	case "dex":
 		if player.Dex >= maxStatValue {
 			sendServerMessage(sess, 481)
 			return
 		}
 		player.Dex++
+		player.BonusAlloc[world.StatDex]++
 	case "con":
 		if player.Con >= maxStatValue {
 			sendServerMessage(sess, 481)
 			return
 		}
 		player.Con++
+		player.BonusAlloc[world.StatCon]++
 	case "wis":
 		if player.Wis >= maxStatValue {
 			sendServerMessage(sess, 481)
 			return
 		}
 		player.Wis++
+		player.BonusAlloc[world.StatWis]++
 	case "int":
 		if player.Intel >= maxStatValue {
 			sendServerMessage(sess, 481)
 			return
 		}
 		player.Intel++
+		player.BonusAlloc[world.StatInt]++
 	case "cha":
 		if player.Cha >= maxStatValue {
 			sendServerMessage(sess, 481)
 			return
 		}
 		player.Cha++
+		player.BonusAlloc[world.StatCha]++
 	default:
 		return
 	}
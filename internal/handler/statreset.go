@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/l1jgo/server/internal/net"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// handleNpcStatReset 洗配點 NPC（動作 "statreset"）：收取費用後把 51 級以上已分配的
+// 配點全部退還，屬性回到配點前的數值。參數由 npc_services.yaml stat_reset 設定。
+// 與回憶蠟燭的完整角色重置（StartCharReset）不同，等級與 HP/MP 上限不變。
+func handleNpcStatReset(sess *net.Session, player *world.PlayerInfo, npc *world.NpcInfo, deps *Deps) {
+	def := deps.NpcServices.StatReset()
+	if def.NpcID == 0 || npc.NpcID != def.NpcID {
+		return
+	}
+	if player.Dead || player.InCharReset {
+		return
+	}
+	if inCombat(player, deps) {
+		SendSystemMessage(sess, "戰鬥中無法重新分配配點。")
+		return
+	}
+
+	var refund int16
+	for _, n := range player.BonusAlloc {
+		refund += n
+	}
+	if refund <= 0 {
+		SendSystemMessage(sess, "沒有可退還的配點。")
+		return
+	}
+
+	// 先檢查所有費用，確認足夠後才扣除
+	if def.Cost > 0 {
+		adena := player.Inv.FindByItemID(world.AdenaItemID)
+		if adena == nil || adena.Count < def.Cost {
+			sendServerMessage(sess, 189) // "金幣不足。"
+			return
+		}
+	}
+	var fee *world.InvItem
+	if def.ItemID > 0 {
+		fee = player.Inv.FindByItemID(def.ItemID)
+		if fee == nil || fee.Count < def.ItemCount {
+			SendServerMessage(sess, 1290) // "缺少必要道具。"
+			return
+		}
+	}
+	if def.Cost > 0 {
+		consumeAdena(player, def.Cost)
+		sendAdenaUpdate(sess, player)
+	}
+	if fee != nil {
+		if player.Inv.RemoveItem(fee.ObjectID, def.ItemCount) {
+			sendRemoveInventoryItem(sess, fee.ObjectID)
+		} else {
+			sendItemCountUpdate(sess, fee)
+		}
+	}
+
+	// 退還配點
+	player.Str -= player.BonusAlloc[world.StatStr]
+	player.Dex -= player.BonusAlloc[world.StatDex]
+	player.Con -= player.BonusAlloc[world.StatCon]
+	player.Wis -= player.BonusAlloc[world.StatWis]
+	player.Intel -= player.BonusAlloc[world.StatInt]
+	player.Cha -= player.BonusAlloc[world.StatCha]
+	player.BonusStats -= refund
+	if player.BonusStats < 0 {
+		player.BonusStats = 0
+	}
+	player.BonusAlloc = [6]int16{}
+	player.Dirty = true
+
+	// 負重上限隨 STR/CON 變動
+	sendPlayerStatus(sess, player)
+	sendAbilityScores(sess, player)
+	sendWeightUpdate(sess, player)
+	if def.Gfx > 0 {
+		broadcastEffect(sess, player, def.Gfx, deps)
+	}
+	SendSystemMessage(sess, fmt.Sprintf("已退還 %d 點配點，請重新分配。", refund))
+
+	deps.Log.Info(fmt.Sprintf("洗配點  角色=%s  退還=%d  已用配點=%d", player.Name, refund, player.BonusStats))
+
+	if player.Level >= bonusStatMinLevel {
+		total := player.Str + player.Dex + player.Con + player.Wis + player.Intel + player.Cha
+		if player.Level-50-player.BonusStats > 0 && total < maxTotalStats {
+			sendRaiseAttrDialog(sess, player.CharID)
+		}
+	}
+}
+
+// inCombat 判斷玩家是否處於戰鬥狀態：粉紅名、決鬥中，或附近有以其為仇恨目標的 NPC。
+func inCombat(player *world.PlayerInfo, deps *Deps) bool {
+	if player.PinkName || player.DuelActive {
+		return true
+	}
+	for _, npc := range deps.World.GetNearbyNpcs(player.X, player.Y, player.MapID) {
+		if npc.Dead {
+			continue
+		}
+		if npc.AggroTarget == player.SessionID || npc.HateList[player.SessionID] > 0 {
+			return true
+		}
+	}
+	return false
+}
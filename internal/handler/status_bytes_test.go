@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestAppendEquipSuffixEnchantByte(t *testing.T) {
+	info := &data.ItemInfo{Category: data.CategoryArmor}
+	tests := []struct {
+		lvl  int
+		want []byte // nil = 不寫入衝裝欄位
+	}{
+		{0, nil},
+		{1, []byte{2, 0x01}},
+		{127, []byte{2, 0x7f}},
+		{128, []byte{2, 0x7f}}, // 超出上限：夾在 +127，不會變成 -128
+		{-1, []byte{2, 0xff}},
+		{-128, []byte{2, 0x80}},
+		{-200, []byte{2, 0x80}}, // 超出下限：夾在 -128，不會變成正值
+	}
+	for _, tt := range tests {
+		item := &world.InvItem{EnchantLvl: world.ClampEnchant(tt.lvl)}
+		got := appendEquipSuffix(nil, item, info)
+		if tt.want == nil {
+			if len(got) > 0 && got[0] == 2 {
+				t.Errorf("enchant %d: wrote enchant field % x", tt.lvl, got)
+			}
+			continue
+		}
+		if len(got) < 2 || got[0] != tt.want[0] || got[1] != tt.want[1] {
+			t.Errorf("enchant %d: suffix % x, want prefix % x", tt.lvl, got, tt.want)
+		}
+		if int(int8(got[1])) != int(item.EnchantLvl) {
+			t.Errorf("enchant %d: client reads %d, item has %d", tt.lvl, int8(got[1]), item.EnchantLvl)
+		}
+	}
+}
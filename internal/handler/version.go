@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"encoding/binary"
+	"encoding/hex"
 	"time"
 
 	"github.com/l1jgo/server/internal/net"
@@ -9,10 +11,27 @@ import (
 )
 
 // HandleVersion processes C_ClientVersion (opcode 14).
+// Format: [H unknown][C language][D client build]...
 // Responds with S_ServerVersion (opcode 139) and transitions to VersionOK.
+// The reported build is kept on the session; handleLogin refuses clients
+// outside [network] min/max_client_version. The raw payload is logged for
+// rejected builds.
 func HandleVersion(sess *net.Session, r *packet.Reader, deps *Deps) {
-	// Client sends version info — we mostly ignore it and respond with server version.
-	deps.Log.Debug("received client version", zap.Uint64("session", sess.ID))
+	payload := r.ReadBytes(r.Remaining())
+	sess.ClientVersion = parseClientBuild(payload)
+	deps.Log.Debug("received client version",
+		zap.Uint64("session", sess.ID),
+		zap.Uint32("version", sess.ClientVersion),
+	)
+	if !clientVersionAllowed(sess.ClientVersion, deps) {
+		deps.Log.Warn("client version outside allowed range",
+			zap.String("ip", sess.IP),
+			zap.Uint32("version", sess.ClientVersion),
+			zap.Uint32("min", deps.Config.Network.MinClientVersion),
+			zap.Uint32("max", deps.Config.Network.MaxClientVersion),
+			zap.String("payload", hex.EncodeToString(payload)),
+		)
+	}
 
 	cfg := deps.Config
 	now := time.Now()
@@ -36,3 +55,25 @@ func HandleVersion(sess *net.Session, r *packet.Reader, deps *Deps) {
 	sess.Send(w.Bytes())
 	sess.SetState(packet.StateVersionOK)
 }
+
+// parseClientBuild reads the build number from a C_ClientVersion payload,
+// assuming [H unknown][C language][D build] (0 when the packet is shorter).
+func parseClientBuild(payload []byte) uint32 {
+	if len(payload) < 7 {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(payload[3:7])
+}
+
+// clientVersionAllowed reports whether a client build is inside the
+// configured [network] range (a zero bound is open).
+func clientVersionAllowed(version uint32, deps *Deps) bool {
+	nc := &deps.Config.Network
+	if nc.MinClientVersion > 0 && version < nc.MinClientVersion {
+		return false
+	}
+	if nc.MaxClientVersion > 0 && version > nc.MaxClientVersion {
+		return false
+	}
+	return true
+}
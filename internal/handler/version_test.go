@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/l1jgo/server/internal/config"
+	"github.com/l1jgo/server/internal/net/packet"
+	"go.uber.org/zap"
+)
+
+func TestParseClientBuild(t *testing.T) {
+	if got := parseClientBuild([]byte{0x01, 0x00, 0x03, 0xdd, 0xf4, 0xcb, 0x07, 0xff}); got != 0x07cbf4dd {
+		t.Errorf("build = 0x%08x, want 0x07cbf4dd", got)
+	}
+	if got := parseClientBuild([]byte{0x01, 0x00, 0x03}); got != 0 {
+		t.Errorf("short payload build = 0x%08x, want 0", got)
+	}
+}
+
+func TestHandleVersionRecordsClientBuild(t *testing.T) {
+	deps := &Deps{Config: &config.Config{}, Log: zap.NewNop()}
+	deps.Config.Network.MinClientVersion = 0x08000000
+
+	sess := newTestSession(t)
+	r := packet.NewReader([]byte{14, 0x01, 0x00, 0x03, 0xdd, 0xf4, 0xcb, 0x07})
+	HandleVersion(sess, r, deps)
+
+	if sess.ClientVersion != 0x07cbf4dd {
+		t.Errorf("ClientVersion = 0x%08x", sess.ClientVersion)
+	}
+	if clientVersionAllowed(sess.ClientVersion, deps) {
+		t.Error("build below min_client_version reported in range")
+	}
+	if sess.State() != packet.StateVersionOK {
+		t.Errorf("state = %v, want VersionOK", sess.State())
+	}
+}
+
+// 版本不符的客戶端在讀取帳號前就被拒絕（AccountRepo 為 nil：觸及即 panic）。
+func TestLoginRefusesOutOfRangeClient(t *testing.T) {
+	deps := &Deps{Config: &config.Config{}, Log: zap.NewNop()}
+	deps.Config.Network.MinClientVersion = 0x08000000
+
+	sess := newTestSession(t)
+	sess.ClientVersion = 0x07cbf4dd
+	handleLogin(sess, packet.NewReader([]byte{119, 'a', 0, 'p', 0}), deps, false)
+
+	sess.FlushOutput()
+	var result []byte
+	var notice string
+	for len(sess.OutQueue) > 0 {
+		switch p := <-sess.OutQueue; p[0] {
+		case packet.S_OPCODE_LOGIN_CHECK:
+			result = p
+		case packet.S_OPCODE_MESSAGE:
+			notice = packet.NewReader(p[1:]).ReadS()
+		}
+	}
+	if result == nil || result[1] != loginSystemError {
+		t.Errorf("login result %v, want refusal code %d (not the wrong-password code)", result, loginSystemError)
+	}
+	if !strings.Contains(notice, "版本") {
+		t.Errorf("version notice = %q, want an explanation of the version mismatch", notice)
+	}
+}
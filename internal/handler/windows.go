@@ -33,6 +33,10 @@ func HandleWindows(sess *net.Session, r *packet.Reader, deps *Deps) {
 		// Java: readD(changeCount) → loop { readD(bookId), readS(newName) }
 		// TODO: 實作書籤名稱修改
 
+	case windowRefuseOption:
+		// 社交拒絕選項切換（密語 / 組隊 / 交易 / 決鬥）
+		HandleRefuseOption(sess, player, r, deps)
+
 	case 6:
 		// 龍門（副本傳送門）
 		// Java: C_Windows.java case 6 — readD(itemObjID) + readD(selectDoor) → 消耗鑰匙 + 生成門衛
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// QueueWrite 將 DB 寫入交給背景寫入佇列（deps.Writes）依序執行，不阻塞遊戲迴圈。
+// 未設定佇列時（工具程式）同步執行。
+func QueueWrite(deps *Deps, what string, fn func(ctx context.Context) error) {
+	if deps.Writes != nil {
+		deps.Writes.Submit(what, fn)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := fn(ctx); err != nil {
+		deps.Log.Error("寫入失敗", zap.String("what", what), zap.Error(err))
+	}
+}
@@ -0,0 +1,126 @@
+// Package metrics collects server health figures (tick time, online count,
+// packet throughput, save durations) and serves them over HTTP for monitoring.
+//
+// The game loop only performs atomic stores; the HTTP server runs in its own
+// goroutine and reads the latest values, so scraping never blocks a tick.
+package metrics
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ewmaShift sets the smoothing of averaged durations: each sample moves the
+// average by 1/16 of the difference.
+const ewmaShift = 4
+
+// Collector holds the latest sampled values. Writers are the game loop
+// (ticks, saves, world sampler); readers are HTTP handlers.
+type Collector struct {
+	start time.Time
+
+	tickAvgNs  atomic.Int64
+	tickMaxNs  atomic.Int64 // max since the last world sample (about 1 second)
+	tickLastNs atomic.Int64
+	tickPeakNs atomic.Int64 // max of tickMaxNs as of the last world sample
+	ticks      atomic.Uint64
+
+	online    atomic.Int64
+	inWorld   atomic.Int64
+	npcsAlive atomic.Int64
+
+	packetsInPerSec  atomic.Int64
+	packetsOutPerSec atomic.Int64
+
+	saveAvgNs  atomic.Int64
+	saveLastNs atomic.Int64
+	saves      atomic.Uint64
+}
+
+// New creates a collector; uptime is measured from this call.
+func New() *Collector {
+	return &Collector{start: time.Now()}
+}
+
+// RecordTick records the duration of one full system tick.
+func (c *Collector) RecordTick(d time.Duration) {
+	ns := int64(d)
+	c.tickLastNs.Store(ns)
+	updateEWMA(&c.tickAvgNs, ns, c.ticks.Add(1) == 1)
+	if ns > c.tickMaxNs.Load() {
+		c.tickMaxNs.Store(ns)
+	}
+}
+
+// RecordSave records the duration of one batch save to the database.
+func (c *Collector) RecordSave(d time.Duration) {
+	ns := int64(d)
+	c.saveLastNs.Store(ns)
+	updateEWMA(&c.saveAvgNs, ns, c.saves.Add(1) == 1)
+}
+
+// SetWorld stores the world counts sampled by the game loop, and rolls the
+// per-window tick maximum over.
+func (c *Collector) SetWorld(online, inWorld, npcsAlive int) {
+	c.online.Store(int64(online))
+	c.inWorld.Store(int64(inWorld))
+	c.npcsAlive.Store(int64(npcsAlive))
+	c.tickPeakNs.Store(c.tickMaxNs.Swap(0))
+}
+
+// SetPacketRates stores packets received/sent per second.
+func (c *Collector) SetPacketRates(inPerSec, outPerSec int64) {
+	c.packetsInPerSec.Store(inPerSec)
+	c.packetsOutPerSec.Store(outPerSec)
+}
+
+// Status is the JSON document served at /status.
+type Status struct {
+	UptimeSec        int64   `json:"uptime_sec"`
+	Online           int64   `json:"online"`
+	InWorld          int64   `json:"in_world"`
+	NpcsAlive        int64   `json:"npcs_alive"`
+	Ticks            uint64  `json:"ticks"`
+	TickAvgMs        float64 `json:"tick_avg_ms"`
+	TickLastMs       float64 `json:"tick_last_ms"`
+	TickMaxMs        float64 `json:"tick_max_ms"`
+	PacketsInPerSec  int64   `json:"packets_in_per_sec"`
+	PacketsOutPerSec int64   `json:"packets_out_per_sec"`
+	Saves            uint64  `json:"saves"`
+	SaveAvgMs        float64 `json:"save_avg_ms"`
+	SaveLastMs       float64 `json:"save_last_ms"`
+}
+
+// Snapshot returns the current values.
+func (c *Collector) Snapshot() Status {
+	return Status{
+		UptimeSec:        int64(time.Since(c.start).Seconds()),
+		Online:           c.online.Load(),
+		InWorld:          c.inWorld.Load(),
+		NpcsAlive:        c.npcsAlive.Load(),
+		Ticks:            c.ticks.Load(),
+		TickAvgMs:        nsToMs(c.tickAvgNs.Load()),
+		TickLastMs:       nsToMs(c.tickLastNs.Load()),
+		TickMaxMs:        nsToMs(c.tickPeakNs.Load()),
+		PacketsInPerSec:  c.packetsInPerSec.Load(),
+		PacketsOutPerSec: c.packetsOutPerSec.Load(),
+		Saves:            c.saves.Load(),
+		SaveAvgMs:        nsToMs(c.saveAvgNs.Load()),
+		SaveLastMs:       nsToMs(c.saveLastNs.Load()),
+	}
+}
+
+// updateEWMA folds a sample into an exponentially weighted average. Single
+// writer (game loop), so a plain load/store pair is sufficient.
+func updateEWMA(avg *atomic.Int64, sample int64, first bool) {
+	if first {
+		avg.Store(sample)
+		return
+	}
+	cur := avg.Load()
+	avg.Store(cur + (sample-cur)>>ewmaShift)
+}
+
+func nsToMs(ns int64) float64 {
+	return float64(ns) / float64(time.Millisecond)
+}
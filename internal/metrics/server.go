@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Serve starts the monitoring HTTP server in its own goroutine:
+//
+//	/status   JSON snapshot
+//	/metrics  Prometheus text exposition format
+//
+// The returned server should be shut down on exit.
+func Serve(addr string, c *Collector, log *zap.Logger) (*http.Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("metrics listen %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(c.Snapshot())
+	})
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		writePrometheus(w, c.Snapshot())
+	})
+
+	srv := &http.Server{
+		Handler:      mux,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 5 * time.Second,
+	}
+	go func() {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			log.Error("監控 HTTP 伺服器停止", zap.Error(err))
+		}
+	}()
+	return srv, nil
+}
+
+// writePrometheus renders a snapshot as Prometheus gauges/counters.
+func writePrometheus(w http.ResponseWriter, s Status) {
+	metric := func(name, kind, help string, v any) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", name, help, name, kind, name, v)
+	}
+	metric("l1jgo_uptime_seconds", "gauge", "Seconds since server start.", s.UptimeSec)
+	metric("l1jgo_online_accounts", "gauge", "Logged-in accounts.", s.Online)
+	metric("l1jgo_players_in_world", "gauge", "Characters in the world.", s.InWorld)
+	metric("l1jgo_npcs_alive", "gauge", "Alive NPCs.", s.NpcsAlive)
+	metric("l1jgo_ticks_total", "counter", "Full system ticks run.", s.Ticks)
+	metric("l1jgo_tick_avg_ms", "gauge", "Moving average tick duration in milliseconds.", s.TickAvgMs)
+	metric("l1jgo_tick_max_ms", "gauge", "Longest tick in the last sample window in milliseconds.", s.TickMaxMs)
+	metric("l1jgo_packets_in_per_second", "gauge", "Client packets received per second.", s.PacketsInPerSec)
+	metric("l1jgo_packets_out_per_second", "gauge", "Packets sent to clients per second.", s.PacketsOutPerSec)
+	metric("l1jgo_saves_total", "counter", "Batch saves to the database.", s.Saves)
+	metric("l1jgo_save_avg_ms", "gauge", "Moving average batch save duration in milliseconds.", s.SaveAvgMs)
+	metric("l1jgo_save_last_ms", "gauge", "Last batch save duration in milliseconds.", s.SaveLastMs)
+}
@@ -2,14 +2,20 @@ package net
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 )
 
+// ErrFrameTooLarge is returned by ReadFrame when a frame's declared payload
+// exceeds the configured limit. The payload is not read or allocated.
+var ErrFrameTooLarge = errors.New("frame too large")
+
 // ReadFrame reads one L1J packet frame from r.
 // Wire format: [2 bytes LE: total length including header][payload].
 // Returns the payload bytes (without the 2-byte length header).
-func ReadFrame(r io.Reader) ([]byte, error) {
+// maxPayload > 0 rejects larger frames with ErrFrameTooLarge.
+func ReadFrame(r io.Reader, maxPayload int) ([]byte, error) {
 	var header [2]byte
 	if _, err := io.ReadFull(r, header[:]); err != nil {
 		return nil, fmt.Errorf("read frame header: %w", err)
@@ -20,6 +26,9 @@ func ReadFrame(r io.Reader) ([]byte, error) {
 	if payloadLen <= 0 || payloadLen > 65533 {
 		return nil, fmt.Errorf("invalid frame length: %d", totalLen)
 	}
+	if maxPayload > 0 && payloadLen > maxPayload {
+		return nil, fmt.Errorf("%w: %d bytes (max %d)", ErrFrameTooLarge, payloadLen, maxPayload)
+	}
 
 	payload := make([]byte, payloadLen)
 	if _, err := io.ReadFull(r, payload); err != nil {
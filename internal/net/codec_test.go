@@ -0,0 +1,36 @@
+package net
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func frame(payload []byte) []byte {
+	n := len(payload) + 2
+	return append([]byte{byte(n), byte(n >> 8)}, payload...)
+}
+
+func TestReadFrameMaxPayload(t *testing.T) {
+	payload := bytes.Repeat([]byte{0xab}, 100)
+
+	got, err := ReadFrame(bytes.NewReader(frame(payload)), 100)
+	if err != nil || !bytes.Equal(got, payload) {
+		t.Fatalf("at limit: %v, %d bytes", err, len(got))
+	}
+	if _, err := ReadFrame(bytes.NewReader(frame(payload)), 99); !errors.Is(err, ErrFrameTooLarge) {
+		t.Fatalf("over limit: err = %v, want ErrFrameTooLarge", err)
+	}
+	// 0 = 只受協定上限限制
+	if _, err := ReadFrame(bytes.NewReader(frame(payload)), 0); err != nil {
+		t.Fatalf("no limit: %v", err)
+	}
+}
+
+func TestReadFrameTooLargeDoesNotReadBody(t *testing.T) {
+	// 宣告 60000 位元組但只送出標頭：超過上限時不應等待或配置本體
+	hdr := []byte{0x62, 0xea} // 60002
+	if _, err := ReadFrame(bytes.NewReader(hdr), 4096); !errors.Is(err, ErrFrameTooLarge) {
+		t.Fatalf("err = %v, want ErrFrameTooLarge", err)
+	}
+}
@@ -8,13 +8,20 @@ import (
 
 // Reader reads L1J packet fields from a decrypted payload.
 // Byte 0 is always the opcode.
+//
+// Every read is bounds-checked: reading past the end of a truncated packet
+// returns zero / empty values instead of panicking.
 type Reader struct {
 	data []byte
 	off  int
 }
 
 func NewReader(data []byte) *Reader {
-	return &Reader{data: data, off: 1} // skip opcode byte
+	off := 1 // skip opcode byte
+	if len(data) == 0 {
+		off = 0
+	}
+	return &Reader{data: data, off: off}
 }
 
 func (r *Reader) Opcode() byte {
@@ -36,7 +43,7 @@ func (r *Reader) ReadC() byte {
 
 // ReadH reads 2 bytes as little-endian uint16.
 func (r *Reader) ReadH() uint16 {
-	if r.off+2 > len(r.data) {
+	if len(r.data)-r.off < 2 {
 		return 0
 	}
 	v := binary.LittleEndian.Uint16(r.data[r.off:])
@@ -46,7 +53,7 @@ func (r *Reader) ReadH() uint16 {
 
 // ReadD reads 4 bytes as little-endian int32.
 func (r *Reader) ReadD() int32 {
-	if r.off+4 > len(r.data) {
+	if len(r.data)-r.off < 4 {
 		return 0
 	}
 	v := int32(binary.LittleEndian.Uint32(r.data[r.off:]))
@@ -55,7 +62,11 @@ func (r *Reader) ReadD() int32 {
 }
 
 // ReadS reads a null-terminated MS950 (Big5) string and returns UTF-8.
+// An unterminated string runs to the end of the packet.
 func (r *Reader) ReadS() string {
+	if r.off >= len(r.data) {
+		return ""
+	}
 	start := r.off
 	for r.off < len(r.data) {
 		if r.data[r.off] == 0 {
@@ -92,9 +103,13 @@ func ms950ToUTF8(raw []byte) string {
 	return string(decoded)
 }
 
-// ReadBytes reads n raw bytes.
+// ReadBytes reads n raw bytes. Fewer bytes are returned if the packet is
+// shorter; n <= 0 returns nil.
 func (r *Reader) ReadBytes(n int) []byte {
-	if r.off+n > len(r.data) {
+	if n <= 0 || r.off >= len(r.data) {
+		return nil
+	}
+	if n > len(r.data)-r.off {
 		remaining := r.data[r.off:]
 		r.off = len(r.data)
 		return remaining
@@ -107,5 +122,8 @@ func (r *Reader) ReadBytes(n int) []byte {
 
 // Remaining returns the number of unread bytes.
 func (r *Reader) Remaining() int {
+	if r.off >= len(r.data) {
+		return 0
+	}
 	return len(r.data) - r.off
 }
@@ -0,0 +1,72 @@
+package packet
+
+import "testing"
+
+func TestReaderTruncatedReturnsZero(t *testing.T) {
+	r := NewReader([]byte{0x10, 0x01, 0x02, 0x03})
+	if op := r.Opcode(); op != 0x10 {
+		t.Fatalf("opcode = %#x", op)
+	}
+	if v := r.ReadD(); v != 0 {
+		t.Errorf("ReadD on 3 bytes = %d, want 0", v)
+	}
+	if v := r.ReadH(); v != 0x0201 {
+		t.Errorf("ReadH = %#x, want 0x0201", v)
+	}
+	if v := r.ReadH(); v != 0 {
+		t.Errorf("ReadH on 1 byte = %#x, want 0", v)
+	}
+	if v := r.ReadC(); v != 0x03 {
+		t.Errorf("ReadC = %#x, want 0x03", v)
+	}
+	if v, s, b := r.ReadC(), r.ReadS(), r.ReadBytes(4); v != 0 || s != "" || b != nil {
+		t.Errorf("reads past end = %d %q %v", v, s, b)
+	}
+	if r.Remaining() != 0 {
+		t.Errorf("Remaining = %d", r.Remaining())
+	}
+
+	empty := NewReader(nil)
+	if empty.Opcode() != 0 || empty.ReadD() != 0 || empty.ReadS() != "" {
+		t.Error("empty packet reads not zero")
+	}
+}
+
+func TestReaderUnterminatedString(t *testing.T) {
+	r := NewReader([]byte{0x01, 'a', 'b', 0, 'c', 'd'})
+	if s := r.ReadS(); s != "ab" {
+		t.Errorf("first ReadS = %q", s)
+	}
+	if s := r.ReadS(); s != "cd" {
+		t.Errorf("unterminated ReadS = %q", s)
+	}
+}
+
+// FuzzReader 任意資料、任意讀取順序都不得 panic，且 Remaining 不得為負。
+func FuzzReader(f *testing.F) {
+	f.Add([]byte{}, []byte{0})
+	f.Add([]byte{0x10, 0x01}, []byte{3, 2, 1, 0})
+	f.Add([]byte{0x22, 'h', 'i', 0, 0xa4, 0xa4}, []byte{4, 4, 5})
+	f.Fuzz(func(t *testing.T, data, ops []byte) {
+		r := NewReader(data)
+		r.Opcode()
+		for _, op := range ops {
+			before := r.Remaining()
+			switch op % 6 {
+			case 0:
+				r.ReadC()
+			case 1:
+				r.ReadH()
+			case 2:
+				r.ReadD()
+			case 3, 4:
+				r.ReadS()
+			case 5:
+				r.ReadBytes(int(int8(op)))
+			}
+			if after := r.Remaining(); after < 0 || after > before {
+				t.Fatalf("Remaining %d -> %d", before, after)
+			}
+		}
+	})
+}
@@ -18,11 +18,12 @@ type Server struct {
 	inSize    int
 	outSize   int
 	pktPerSec int
+	maxPacket int
 	log       *zap.Logger
 	closeCh   chan struct{}
 }
 
-func NewServer(bindAddr string, inSize, outSize, pktPerSec int, log *zap.Logger) (*Server, error) {
+func NewServer(bindAddr string, inSize, outSize, pktPerSec, maxPacket int, log *zap.Logger) (*Server, error) {
 	ln, err := net.Listen("tcp", bindAddr)
 	if err != nil {
 		return nil, err
@@ -34,6 +35,7 @@ func NewServer(bindAddr string, inSize, outSize, pktPerSec int, log *zap.Logger)
 		inSize:    inSize,
 		outSize:   outSize,
 		pktPerSec: pktPerSec,
+		maxPacket: maxPacket,
 		log:       log,
 		closeCh:   make(chan struct{}),
 	}
@@ -62,7 +64,7 @@ func (s *Server) AcceptLoop() {
 		}
 
 		id := s.nextID.Add(1)
-		sess := NewSession(conn, id, s.inSize, s.outSize, s.pktPerSec, s.log)
+		sess := NewSession(conn, id, s.inSize, s.outSize, s.pktPerSec, s.maxPacket, s.log)
 		sess.Start()
 
 		s.log.Info(fmt.Sprintf("玩家連線  session=%d  ip=%s", id, sess.IP))
@@ -1,9 +1,10 @@
 package net
 
 import (
+	crand "crypto/rand"
 	"encoding/binary"
+	"errors"
 	"fmt"
-	"math/rand"
 	"net"
 	"sync"
 	"sync/atomic"
@@ -33,9 +34,11 @@ type Session struct {
 	InQueue  chan []byte // game loop reads packets from here
 	OutQueue chan []byte // writer goroutine reads from here
 
-	IP          string
+	IP          string // remote host without the port (matches ban_ip / per-IP login limits)
 	AccountName string
 	CharName    string
+	Logout      bool // voluntary quit or kick — disconnect cleanup skips the linkdead grace
+	ClientVersion uint32 // build number reported by C_ClientVersion (0 = not reported)
 
 	outBuf [][]byte // buffered packets, flushed by OutputSystem (game loop only)
 
@@ -43,6 +46,8 @@ type Session struct {
 	closeOnce sync.Once
 	closed    atomic.Bool
 
+	maxPacket int // max inbound payload bytes (0 = protocol limit only)
+
 	// Per-second packet rate limiter (readLoop goroutine only, no lock needed)
 	pktPerSec  int   // max packets/sec (0 = unlimited)
 	pktCount   int   // packets received this second
@@ -51,21 +56,35 @@ type Session struct {
 	log *zap.Logger
 }
 
-func NewSession(conn net.Conn, id uint64, inSize, outSize, pktPerSec int, log *zap.Logger) *Session {
+func NewSession(conn net.Conn, id uint64, inSize, outSize, pktPerSec, maxPacket int, log *zap.Logger) *Session {
 	s := &Session{
 		ID:        id,
 		conn:      conn,
 		InQueue:   make(chan []byte, inSize),
 		OutQueue:  make(chan []byte, outSize),
-		IP:        conn.RemoteAddr().String(),
+		IP:        remoteHost(conn.RemoteAddr()),
 		closeCh:   make(chan struct{}),
 		pktPerSec: pktPerSec,
+		maxPacket: maxPacket,
 		log:       log.With(zap.Uint64("session", id)),
 	}
 	s.state.Store(int32(packet.StateHandshake))
 	return s
 }
 
+// remoteHost returns the host part of a remote address. Addresses without a
+// port (e.g. unix sockets, pipes in tests) are returned unchanged.
+func remoteHost(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
 func (s *Session) State() packet.SessionState {
 	return packet.SessionState(s.state.Load())
 }
@@ -77,7 +96,11 @@ func (s *Session) SetState(st packet.SessionState) {
 // Start sends the plaintext init packet, initializes the cipher, and
 // launches the reader and writer goroutines.
 func (s *Session) Start() {
-	seed := rand.Int31n(0x7FFFFFFE) + 1 // positive non-zero int32
+	// Cipher key seed: drawn from crypto/rand, deliberately outside the
+	// game RNG ([server] rand_seed) so a pinned seed never makes keys guessable.
+	var rb [4]byte
+	crand.Read(rb[:])
+	seed := int32(binary.LittleEndian.Uint32(rb[:])%0x7FFFFFFE) + 1 // positive non-zero int32
 
 	// Build init packet (plaintext, written directly — no cipher, no sendPacket)
 	// [2B LE length=18][1B opcode=150][4B LE seed][11B firstPacket]
@@ -156,8 +179,12 @@ func (s *Session) readLoop() {
 		default:
 		}
 
-		payload, err := ReadFrame(s.conn)
+		payload, err := ReadFrame(s.conn, s.maxPacket)
 		if err != nil {
+			if errors.Is(err, ErrFrameTooLarge) {
+				s.log.Warn("封包過大，斷開連線", zap.String("ip", s.IP), zap.Error(err))
+				return
+			}
 			if !s.closed.Load() {
 				s.log.Debug("讀取錯誤", zap.Error(err))
 			}
@@ -165,6 +192,7 @@ func (s *Session) readLoop() {
 		}
 
 		decrypted := s.cipher.Decrypt(payload)
+		packetsIn.Add(1)
 
 		// Per-second packet rate limiter
 		if s.pktPerSec > 0 {
@@ -217,6 +245,7 @@ func (s *Session) writeLoop() {
 // 每個封包個別加密（維持 XOR cipher 狀態序列），但只執行一次 conn.Write。
 func (s *Session) writeBatch(first []byte) bool {
 	batch := s.encryptFrame(first)
+	n := uint64(1)
 
 	// 排空 OutQueue 中所有剩餘封包
 drain:
@@ -224,10 +253,12 @@ drain:
 		select {
 		case more := <-s.OutQueue:
 			batch = append(batch, s.encryptFrame(more)...)
+			n++
 		default:
 			break drain
 		}
 	}
+	packetsOut.Add(n)
 
 	s.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
 	if _, err := s.conn.Write(batch); err != nil {
@@ -0,0 +1,23 @@
+package net
+
+import (
+	"net"
+	"testing"
+)
+
+func TestRemoteHostStripsPort(t *testing.T) {
+	cases := []struct {
+		addr net.Addr
+		want string
+	}{
+		{&net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 51234}, "203.0.113.7"},
+		{&net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 2000}, "2001:db8::1"},
+		{&net.UnixAddr{Name: "pipe", Net: "unix"}, "pipe"},
+		{nil, ""},
+	}
+	for _, c := range cases {
+		if got := remoteHost(c.addr); got != c.want {
+			t.Errorf("remoteHost(%v) = %q, want %q", c.addr, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package net
+
+import "sync/atomic"
+
+// Process-wide packet counters, updated by every session's read/write
+// goroutines and read by the metrics sampler.
+var (
+	packetsIn  atomic.Uint64
+	packetsOut atomic.Uint64
+)
+
+// PacketTotals returns the number of packets received and sent since start.
+func PacketTotals() (in, out uint64) {
+	return packetsIn.Load(), packetsOut.Load()
+}
@@ -10,6 +10,7 @@ import (
 )
 
 type AccountRow struct {
+	ID            int32
 	Name          string
 	PasswordHash  string
 	AccessLevel   int16
@@ -17,6 +18,9 @@ type AccountRow struct {
 	IP            string
 	Host          string
 	Banned            bool
+	BannedUntil       *time.Time // nil + Banned = permanent
+	BanReason         string
+	PremiumUntil      *time.Time // nil or past = not premium
 	Online            bool
 	WarehousePassword int32
 	CreatedAt         time.Time
@@ -34,14 +38,14 @@ func NewAccountRepo(db *DB) *AccountRepo {
 func (r *AccountRepo) Load(ctx context.Context, name string) (*AccountRow, error) {
 	row := &AccountRow{}
 	err := r.db.Pool.QueryRow(ctx,
-		`SELECT name, password_hash, access_level, character_slot,
-		        COALESCE(ip,''), COALESCE(host,''), banned, online, warehouse_password,
-		        created_at, last_active
+		`SELECT id, name, password_hash, access_level, character_slot,
+		        COALESCE(ip,''), COALESCE(host,''), banned, banned_until, ban_reason,
+		        premium_until, online, warehouse_password, created_at, last_active
 		 FROM accounts WHERE name = $1`, name,
 	).Scan(
-		&row.Name, &row.PasswordHash, &row.AccessLevel, &row.CharacterSlot,
-		&row.IP, &row.Host, &row.Banned, &row.Online, &row.WarehousePassword,
-		&row.CreatedAt, &row.LastActive,
+		&row.ID, &row.Name, &row.PasswordHash, &row.AccessLevel, &row.CharacterSlot,
+		&row.IP, &row.Host, &row.Banned, &row.BannedUntil, &row.BanReason,
+		&row.PremiumUntil, &row.Online, &row.WarehousePassword, &row.CreatedAt, &row.LastActive,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
@@ -66,11 +70,11 @@ func (r *AccountRepo) Create(ctx context.Context, name, rawPassword, ip, host st
 		CreatedAt:    now,
 		LastActive:   &now,
 	}
-	_, err = r.db.Pool.Exec(ctx,
+	err = r.db.Pool.QueryRow(ctx,
 		`INSERT INTO accounts (name, password_hash, ip, host, last_active)
-		 VALUES ($1, $2, $3, $4, $5)`,
+		 VALUES ($1, $2, $3, $4, $5) RETURNING id`,
 		row.Name, row.PasswordHash, row.IP, row.Host, row.LastActive,
-	)
+	).Scan(&row.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -105,3 +109,99 @@ func (r *AccountRepo) UpdateWarehousePassword(ctx context.Context, name string,
 	)
 	return err
 }
+
+// BanActive reports whether the account is banned at now. An expired timed
+// ban reads as not banned.
+func (a *AccountRow) BanActive(now time.Time) bool {
+	if !a.Banned {
+		return false
+	}
+	return a.BannedUntil == nil || now.Before(*a.BannedUntil)
+}
+
+// Ban suspends an account until the given time (nil = permanent).
+// Returns false if the account does not exist.
+func (r *AccountRepo) Ban(ctx context.Context, name string, until *time.Time, reason string) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx,
+		`UPDATE accounts SET banned = TRUE, banned_until = $2, ban_reason = $3 WHERE name = $1`,
+		name, until, reason,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Unban lifts an account ban. Returns false if the account does not exist.
+func (r *AccountRepo) Unban(ctx context.Context, name string) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx,
+		`UPDATE accounts SET banned = FALSE, banned_until = NULL, ban_reason = '' WHERE name = $1`,
+		name,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// PremiumActive reports whether the account has premium status at now.
+func (a *AccountRow) PremiumActive(now time.Time) bool {
+	return a.PremiumUntil != nil && now.Before(*a.PremiumUntil)
+}
+
+// SetPremium sets or clears (until = nil) the account's premium expiry.
+// Returns false if the account does not exist.
+func (r *AccountRepo) SetPremium(ctx context.Context, name string, until *time.Time) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx,
+		`UPDATE accounts SET premium_until = $2 WHERE name = $1`,
+		name, until,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// IPBan is one entry of the ip_bans list.
+type IPBan struct {
+	IP          string
+	BannedUntil *time.Time // nil = permanent
+	Reason      string
+}
+
+// LoadIPBan returns the active ban for an IP, or nil if the IP is not banned
+// (expired entries are ignored).
+func (r *AccountRepo) LoadIPBan(ctx context.Context, ip string) (*IPBan, error) {
+	b := &IPBan{}
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT ip, banned_until, reason FROM ip_bans
+		 WHERE ip = $1 AND (banned_until IS NULL OR banned_until > NOW())`, ip,
+	).Scan(&b.IP, &b.BannedUntil, &b.Reason)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// BanIP adds or replaces an ip_bans entry.
+func (r *AccountRepo) BanIP(ctx context.Context, ip string, until *time.Time, reason string) error {
+	_, err := r.db.Pool.Exec(ctx,
+		`INSERT INTO ip_bans (ip, banned_until, reason) VALUES ($1, $2, $3)
+		 ON CONFLICT (ip) DO UPDATE SET banned_until = EXCLUDED.banned_until,
+		                                reason = EXCLUDED.reason, created_at = NOW()`,
+		ip, until, reason,
+	)
+	return err
+}
+
+// UnbanIP removes an ip_bans entry. Returns false if the IP was not listed.
+func (r *AccountRepo) UnbanIP(ctx context.Context, ip string) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM ip_bans WHERE ip = $1`, ip)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
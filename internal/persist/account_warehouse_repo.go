@@ -0,0 +1,185 @@
+package persist
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrWarehouseStale is returned when an account warehouse row no longer
+// matches the caller's cached view (removed or reduced by another character
+// of the same account). Callers should reload the warehouse.
+var ErrWarehouseStale = errors.New("warehouse item changed since it was loaded")
+
+// LoadAccount returns all items in the account-shared warehouse.
+func (r *WarehouseRepo) LoadAccount(ctx context.Context, accountID int32) ([]WarehouseItem, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT id, account_id, char_name, item_id, count, enchant_lvl, bless, identified
+		 FROM account_warehouse WHERE account_id = $1 ORDER BY id`, accountID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []WarehouseItem
+	for rows.Next() {
+		it := WarehouseItem{WhType: 3}
+		if err := rows.Scan(
+			&it.ID, &it.AccountID, &it.CharName,
+			&it.ItemID, &it.Count, &it.EnchantLvl, &it.Bless, &it.Identified,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, it)
+	}
+	return result, rows.Err()
+}
+
+// DepositAccount inserts a new item into the account-shared warehouse.
+func (r *WarehouseRepo) DepositAccount(ctx context.Context, item WarehouseItem) (int32, error) {
+	var id int32
+	err := r.db.Pool.QueryRow(ctx,
+		`INSERT INTO account_warehouse (account_id, char_name, item_id, count, enchant_lvl, bless, identified)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		item.AccountID, item.CharName, item.ItemID, item.Count,
+		item.EnchantLvl, item.Bless, item.Identified,
+	).Scan(&id)
+	return id, err
+}
+
+// AddToAccountStack increases the count of a stackable account warehouse item.
+// Returns ErrWarehouseStale when the row no longer belongs to the account.
+func (r *WarehouseRepo) AddToAccountStack(ctx context.Context, accountID, whItemID, addCount int32) error {
+	tag, err := r.db.Pool.Exec(ctx,
+		`UPDATE account_warehouse SET count = count + $1 WHERE id = $2 AND account_id = $3`,
+		addCount, whItemID, accountID,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrWarehouseStale
+	}
+	return nil
+}
+
+// DepositAccountStack adds amount of item.ItemID (item.Count is ignored) to
+// the account warehouse in one transaction. Existing stacks are filled up to
+// maxCount and the rest goes into new rows of at most maxCount each, so
+// nothing is lost to the stack cap.
+func (r *WarehouseRepo) DepositAccountStack(ctx context.Context, item WarehouseItem, amount int64, maxCount int32) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx,
+		`SELECT id, count FROM account_warehouse
+		 WHERE account_id = $1 AND item_id = $2 AND count < $3
+		 ORDER BY id FOR UPDATE`,
+		item.AccountID, item.ItemID, maxCount,
+	)
+	if err != nil {
+		return err
+	}
+	var ids, counts []int32
+	for rows.Next() {
+		var id, count int32
+		if err := rows.Scan(&id, &count); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+		counts = append(counts, count)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	adds, newRows := splitStackDeposit(counts, amount, maxCount)
+	for i, add := range adds {
+		if add == 0 {
+			continue
+		}
+		if _, err := tx.Exec(ctx,
+			`UPDATE account_warehouse SET count = count + $1 WHERE id = $2`, add, ids[i],
+		); err != nil {
+			return err
+		}
+	}
+	for _, count := range newRows {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO account_warehouse (account_id, char_name, item_id, count, enchant_lvl, bless, identified)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			item.AccountID, item.CharName, item.ItemID, count,
+			item.EnchantLvl, item.Bless, item.Identified,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// splitStackDeposit distributes amount over stacks holding counts (each
+// capped at maxCount). It returns how much to add to each existing stack and
+// the counts of the new stacks needed for the remainder.
+func splitStackDeposit(counts []int32, amount int64, maxCount int32) (adds []int32, newRows []int32) {
+	adds = make([]int32, len(counts))
+	for i, c := range counts {
+		if amount <= 0 {
+			break
+		}
+		add := min(int64(maxCount-c), amount)
+		if add <= 0 {
+			continue
+		}
+		adds[i] = int32(add)
+		amount -= add
+	}
+	for amount > 0 {
+		n := min(amount, int64(maxCount))
+		newRows = append(newRows, int32(n))
+		amount -= n
+	}
+	return adds, newRows
+}
+
+// WithdrawAccount removes count from an account warehouse item in one
+// transaction, deleting the row when it reaches zero. Returns true if fully
+// removed, or ErrWarehouseStale when the row is gone or holds fewer than count.
+func (r *WarehouseRepo) WithdrawAccount(ctx context.Context, accountID, whItemID, count int32) (bool, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	var remaining int32
+	err = tx.QueryRow(ctx,
+		`SELECT count FROM account_warehouse WHERE id = $1 AND account_id = $2 FOR UPDATE`,
+		whItemID, accountID,
+	).Scan(&remaining)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && remaining < count) {
+		return false, ErrWarehouseStale
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if remaining == count {
+		_, err = tx.Exec(ctx, `DELETE FROM account_warehouse WHERE id = $1`, whItemID)
+	} else {
+		_, err = tx.Exec(ctx, `UPDATE account_warehouse SET count = count - $1 WHERE id = $2`, count, whItemID)
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return remaining == count, nil
+}
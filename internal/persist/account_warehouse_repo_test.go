@@ -0,0 +1,148 @@
+package persist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/l1jgo/server/internal/config"
+	"go.uber.org/zap"
+)
+
+func TestSplitStackDeposit(t *testing.T) {
+	const max = 1000
+	cases := []struct {
+		name    string
+		counts  []int32
+		amount  int64
+		adds    []int32
+		newRows []int32
+	}{
+		{"fits in existing", []int32{400}, 500, []int32{500}, nil},
+		{"fills then new row", []int32{900}, 250, []int32{100}, []int32{150}},
+		{"several new rows", nil, 2500, []int32{}, []int32{1000, 1000, 500}},
+		{"skips full stacks", []int32{1000, 990}, 30, []int32{0, 10}, []int32{20}},
+	}
+	for _, c := range cases {
+		adds, newRows := splitStackDeposit(c.counts, c.amount, max)
+		if !reflect.DeepEqual(adds, c.adds) || !reflect.DeepEqual(newRows, c.newRows) {
+			t.Errorf("%s: got adds=%v new=%v, want adds=%v new=%v", c.name, adds, newRows, c.adds, c.newRows)
+		}
+		var total int64
+		for _, v := range append(adds, newRows...) {
+			total += int64(v)
+		}
+		if total != c.amount {
+			t.Errorf("%s: deposited %d, want %d", c.name, total, c.amount)
+		}
+	}
+}
+
+// newTestWarehouseRepo connects to the database in L1JGO_TEST_DSN, migrates it
+// and creates a throwaway account. Tests are skipped when the variable is unset.
+func newTestWarehouseRepo(t *testing.T) (*WarehouseRepo, int32) {
+	t.Helper()
+	dsn := os.Getenv("L1JGO_TEST_DSN")
+	if dsn == "" {
+		t.Skip("L1JGO_TEST_DSN not set")
+	}
+	ctx := context.Background()
+	db, err := NewDB(ctx, config.DatabaseConfig{DSN: dsn, MaxOpenConns: 8}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(db.Close)
+	if err := RunMigrations(ctx, db.Pool); err != nil {
+		t.Fatal(err)
+	}
+
+	name := fmt.Sprintf("whtest%d", time.Now().UnixNano()%1e9)
+	var accountID int32
+	if err := db.Pool.QueryRow(ctx,
+		`INSERT INTO accounts (name, password_hash) VALUES ($1, '') RETURNING id`, name,
+	).Scan(&accountID); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Pool.Exec(context.Background(), `DELETE FROM accounts WHERE name = $1`, name) })
+	return NewWarehouseRepo(db), accountID
+}
+
+func TestWithdrawAccountConcurrent(t *testing.T) {
+	repo, accountID := newTestWarehouseRepo(t)
+	ctx := context.Background()
+	id, err := repo.DepositAccount(ctx, WarehouseItem{AccountID: accountID, CharName: "a", ItemID: 40308, Count: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Five characters' worth of 3-count withdrawals race for 10 items:
+	// exactly three may succeed and the stack must never go negative.
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var ok, stale int
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.WithdrawAccount(ctx, accountID, id, 3)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				ok++
+			case errors.Is(err, ErrWarehouseStale):
+				stale++
+			default:
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if ok != 3 || stale != 2 {
+		t.Fatalf("withdrawals: %d ok, %d stale, want 3 and 2", ok, stale)
+	}
+	items, err := repo.LoadAccount(ctx, accountID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Count != 1 {
+		t.Errorf("remaining stack %+v, want one row of 1", items)
+	}
+
+	full, err := repo.WithdrawAccount(ctx, accountID, id, 1)
+	if err != nil || !full {
+		t.Fatalf("withdrawing the last item: full=%v err=%v", full, err)
+	}
+	if _, err := repo.WithdrawAccount(ctx, accountID, id, 1); !errors.Is(err, ErrWarehouseStale) {
+		t.Errorf("withdraw from a removed row: %v, want ErrWarehouseStale", err)
+	}
+}
+
+func TestAccountWarehouseRejectsOtherAccount(t *testing.T) {
+	repo, accountID := newTestWarehouseRepo(t)
+	ctx := context.Background()
+	id, err := repo.DepositAccount(ctx, WarehouseItem{AccountID: accountID, CharName: "a", ItemID: 40308, Count: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other := accountID + 1_000_000
+	if _, err := repo.WithdrawAccount(ctx, other, id, 1); !errors.Is(err, ErrWarehouseStale) {
+		t.Errorf("withdraw by another account: %v, want ErrWarehouseStale", err)
+	}
+	if err := repo.AddToAccountStack(ctx, other, id, 1); !errors.Is(err, ErrWarehouseStale) {
+		t.Errorf("stack add by another account: %v, want ErrWarehouseStale", err)
+	}
+	items, err := repo.LoadAccount(ctx, accountID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Count != 5 {
+		t.Errorf("stack changed by another account: %+v", items)
+	}
+}
@@ -0,0 +1,80 @@
+package persist
+
+import "context"
+
+// BossKill is one recorded boss kill.
+type BossKill struct {
+	NpcID        int32
+	BossName     string
+	KillerCharID int32
+	KillerName   string
+	ClanName     string
+	MapID        int16
+}
+
+// BossKillCount is a leaderboard row: kills per character.
+type BossKillCount struct {
+	CharID int32
+	Name   string
+	Kills  int
+}
+
+type BossKillRepo struct {
+	db *DB
+}
+
+func NewBossKillRepo(db *DB) *BossKillRepo {
+	return &BossKillRepo{db: db}
+}
+
+// Record inserts a boss kill.
+func (r *BossKillRepo) Record(ctx context.Context, k BossKill) error {
+	_, err := r.db.Pool.Exec(ctx,
+		`INSERT INTO boss_kills (npc_id, boss_name, killer_char_id, killer_name, clan_name, map_id)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		k.NpcID, k.BossName, k.KillerCharID, k.KillerName, k.ClanName, k.MapID,
+	)
+	return err
+}
+
+// KilledBossIDs returns the boss templates that have at least one recorded kill.
+func (r *BossKillRepo) KilledBossIDs(ctx context.Context) ([]int32, error) {
+	rows, err := r.db.Pool.Query(ctx, `SELECT DISTINCT npc_id FROM boss_kills`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		result = append(result, id)
+	}
+	return result, rows.Err()
+}
+
+// TopKillers returns the characters with the most boss kills.
+func (r *BossKillRepo) TopKillers(ctx context.Context, limit int) ([]BossKillCount, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT killer_char_id, MAX(killer_name), COUNT(*) AS kills
+		 FROM boss_kills GROUP BY killer_char_id
+		 ORDER BY kills DESC, MAX(killed_at) ASC LIMIT $1`, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []BossKillCount
+	for rows.Next() {
+		var c BossKillCount
+		if err := rows.Scan(&c.CharID, &c.Name, &c.Kills); err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, rows.Err()
+}
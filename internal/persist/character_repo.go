@@ -42,6 +42,7 @@ type CharacterRow struct {
 	PKCount     int32
 	Karma       int32
 	BonusStats  int16
+	BonusAlloc  [6]int16 // 配點分配數（str, dex, con, wis, int, cha；僅 LoadByName 載入）
 	ElixirStats int16
 	PartnerID   int32
 	Food        int16
@@ -49,8 +50,24 @@ type CharacterRow struct {
 	AccessLevel int16
 	Birthday    int32
 	DeletedAt   *time.Time
+	FirstLogin  bool // 尚未發放新手禮包（僅 LoadByName 載入）
+	RestedXP    int64      // 休息經驗池（經驗值點數）
+	LastLogout  *time.Time // 最後存檔時間（僅 LoadByName 載入；nil = 從未存檔）
+
+	// 社交拒絕選項（僅 LoadByName 載入，切換時由 SaveRefuseFlags 寫入）
+	RefuseWhisper bool
+	RefuseParty   bool
+	RefuseTrade   bool
+	RefuseDuel    bool
 }
 
+// ErrNameTaken is returned by Rename when the new name is already in use,
+// including names held by characters still inside their deletion grace period.
+var ErrNameTaken = errors.New("character name already taken")
+
+// ErrCharNotFound is returned by Rename when the old name does not exist.
+var ErrCharNotFound = errors.New("character not found")
+
 type CharacterRepo struct {
 	db *DB
 }
@@ -138,14 +155,86 @@ func (r *CharacterRepo) CountByAccount(ctx context.Context, accountName string)
 	return count, err
 }
 
-func (r *CharacterRepo) SoftDelete(ctx context.Context, name string) error {
+// SoftDelete marks a character for deletion after graceDays. deleted_at holds
+// the moment the grace period ends; until then a GM can Restore the character.
+func (r *CharacterRepo) SoftDelete(ctx context.Context, name string, graceDays int) error {
 	_, err := r.db.Pool.Exec(ctx,
-		`UPDATE characters SET deleted_at = NOW() + INTERVAL '7 days' WHERE name = $1 AND deleted_at IS NULL`,
-		name,
+		`UPDATE characters SET deleted_at = NOW() + make_interval(days => $2) WHERE name = $1 AND deleted_at IS NULL`,
+		name, graceDays,
 	)
 	return err
 }
 
+// Restore cancels a pending soft delete. Returns false if the character is not
+// soft-deleted or its grace period has already ended.
+func (r *CharacterRepo) Restore(ctx context.Context, name string) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx,
+		`UPDATE characters SET deleted_at = NULL WHERE name = $1 AND deleted_at IS NOT NULL AND deleted_at > NOW()`,
+		name,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Rename changes a character's name and updates every table that stores the
+// name denormalized (clan roster, clan leader, character warehouse, buddy lists).
+// Returns the character ID on success.
+func (r *CharacterRepo) Rename(ctx context.Context, oldName, newName string) (int32, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("rename begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	if err := tx.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM characters WHERE name = $1)`, newName,
+	).Scan(&exists); err != nil {
+		return 0, fmt.Errorf("rename check: %w", err)
+	}
+	if exists {
+		return 0, ErrNameTaken
+	}
+
+	var charID int32
+	err = tx.QueryRow(ctx,
+		`UPDATE characters SET name = $1 WHERE name = $2 AND deleted_at IS NULL RETURNING id`,
+		newName, oldName,
+	).Scan(&charID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrCharNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("rename character: %w", err)
+	}
+
+	stmts := []string{
+		`UPDATE clan_members SET char_name = $1 WHERE char_id = $2`,
+		`UPDATE clans SET leader_name = $1 WHERE leader_id = $2`,
+		`UPDATE character_buddys SET buddy_name = $1 WHERE buddy_id = $2`,
+	}
+	for _, q := range stmts {
+		if _, err := tx.Exec(ctx, q, newName, charID); err != nil {
+			return 0, fmt.Errorf("rename references: %w", err)
+		}
+	}
+	for _, q := range []string{
+		`UPDATE warehouse_items SET char_name = $1 WHERE char_name = $2`,
+		`UPDATE account_warehouse SET char_name = $1 WHERE char_name = $2`,
+	} {
+		if _, err := tx.Exec(ctx, q, newName, oldName); err != nil {
+			return 0, fmt.Errorf("rename warehouse: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("rename commit: %w", err)
+	}
+	return charID, nil
+}
+
 func (r *CharacterRepo) HardDelete(ctx context.Context, name string) error {
 	_, err := r.db.Pool.Exec(ctx,
 		`DELETE FROM characters WHERE name = $1`, name,
@@ -182,19 +271,35 @@ func (r *CharacterRepo) SaveCharacter(ctx context.Context, c *CharacterRow) erro
 			lawful = $11, str = $12, dex = $13, con = $14, wis = $15, cha = $16, intel = $17,
 			bonus_stats = $18, elixir_stats = $19,
 			clan_id = $20, clan_name = $21, clan_rank = $22,
-			title = $23, karma = $24, pk_count = $25, food = $26
-		WHERE name = $27`,
+			title = $23, karma = $24, pk_count = $25, food = $26,
+			bonus_str = $27, bonus_dex = $28, bonus_con = $29,
+			bonus_wis = $30, bonus_int = $31, bonus_cha = $32,
+			rested_xp = $33, last_logout = NOW()
+		WHERE name = $34`,
 		c.Level, c.Exp, c.HP, c.MP, c.MaxHP, c.MaxMP,
 		c.X, c.Y, c.MapID, c.Heading,
 		c.Lawful, c.Str, c.Dex, c.Con, c.Wis, c.Cha, c.Intel,
 		c.BonusStats, c.ElixirStats,
 		c.ClanID, c.ClanName, c.ClanRank,
 		c.Title, c.Karma, c.PKCount, c.Food,
+		c.BonusAlloc[0], c.BonusAlloc[1], c.BonusAlloc[2],
+		c.BonusAlloc[3], c.BonusAlloc[4], c.BonusAlloc[5],
+		c.RestedXP,
 		c.Name,
 	)
 	return err
 }
 
+// SaveRefuseFlags updates the character's social refuse options.
+func (r *CharacterRepo) SaveRefuseFlags(ctx context.Context, charID int32, whisper, party, trade, duel bool) error {
+	_, err := r.db.Pool.Exec(ctx,
+		`UPDATE characters SET refuse_whisper = $1, refuse_party = $2, refuse_trade = $3, refuse_duel = $4
+		 WHERE id = $5`,
+		whisper, party, trade, duel, charID,
+	)
+	return err
+}
+
 // BookmarkRow represents a single bookmark in the JSONB bookmarks column.
 type BookmarkRow struct {
 	ID    int32  `json:"id"`
@@ -271,6 +376,41 @@ func (r *CharacterRepo) SaveKnownSpells(ctx context.Context, name string, spells
 	return err
 }
 
+// LoadLootFilter loads the loot_filter JSONB column (item IDs discarded on drop).
+func (r *CharacterRepo) LoadLootFilter(ctx context.Context, name string) ([]int32, error) {
+	var raw []byte
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT COALESCE(loot_filter, '[]'::jsonb) FROM characters WHERE name = $1 AND deleted_at IS NULL`, name,
+	).Scan(&raw)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ids []int32
+	if err := json.Unmarshal(raw, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// SaveLootFilter saves the loot_filter JSONB column for a character.
+func (r *CharacterRepo) SaveLootFilter(ctx context.Context, name string, ids []int32) error {
+	if ids == nil {
+		ids = []int32{}
+	}
+	data, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Pool.Exec(ctx,
+		`UPDATE characters SET loot_filter = $1 WHERE name = $2`,
+		data, name,
+	)
+	return err
+}
+
 // LoadCharConfig loads the raw character config blob (hotkeys, UI positions).
 func (r *CharacterRepo) LoadCharConfig(ctx context.Context, charID int32) ([]byte, error) {
 	var data []byte
@@ -358,7 +498,10 @@ func (r *CharacterRepo) LoadByName(ctx context.Context, name string) (*Character
 		        x, y, map_id, heading,
 		        lawful, title, clan_id, clan_name, clan_rank,
 		        pk_count, karma, bonus_stats, elixir_stats, partner_id,
-		        food, high_level, access_level, birthday, deleted_at
+		        food, high_level, access_level, birthday, deleted_at, first_login,
+		        refuse_whisper, refuse_party, refuse_trade, refuse_duel,
+		        bonus_str, bonus_dex, bonus_con, bonus_wis, bonus_int, bonus_cha,
+		        rested_xp, last_logout
 		 FROM characters WHERE name = $1 AND deleted_at IS NULL`, name,
 	).Scan(
 		&c.ID, &c.AccountName, &c.Name, &c.ClassType, &c.Sex, &c.ClassID,
@@ -367,7 +510,11 @@ func (r *CharacterRepo) LoadByName(ctx context.Context, name string) (*Character
 		&c.X, &c.Y, &c.MapID, &c.Heading,
 		&c.Lawful, &c.Title, &c.ClanID, &c.ClanName, &c.ClanRank,
 		&c.PKCount, &c.Karma, &c.BonusStats, &c.ElixirStats, &c.PartnerID,
-		&c.Food, &c.HighLevel, &c.AccessLevel, &c.Birthday, &c.DeletedAt,
+		&c.Food, &c.HighLevel, &c.AccessLevel, &c.Birthday, &c.DeletedAt, &c.FirstLogin,
+		&c.RefuseWhisper, &c.RefuseParty, &c.RefuseTrade, &c.RefuseDuel,
+		&c.BonusAlloc[0], &c.BonusAlloc[1], &c.BonusAlloc[2],
+		&c.BonusAlloc[3], &c.BonusAlloc[4], &c.BonusAlloc[5],
+		&c.RestedXP, &c.LastLogout,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
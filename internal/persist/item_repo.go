@@ -3,6 +3,7 @@ package persist
 import (
 	"context"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/l1jgo/server/internal/world"
 )
 
@@ -75,6 +76,41 @@ func (r *ItemRepo) SaveInventory(ctx context.Context, charID int32, inv *world.I
 	}
 	defer tx.Rollback(ctx)
 
+	if err := saveInventoryTx(ctx, tx, charID, inv, equip); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// SaveFirstLoginInventory saves the inventory and clears characters.first_login
+// in one transaction. Returns false (nothing written) if the flag was already
+// cleared, so a starter kit can never be persisted twice.
+func (r *ItemRepo) SaveFirstLoginInventory(ctx context.Context, charID int32, inv *world.Inventory, equip *world.Equipment) (bool, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE characters SET first_login = FALSE WHERE id = $1 AND first_login`, charID)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+	if err := saveInventoryTx(ctx, tx, charID, inv, equip); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// saveInventoryTx replaces all items for a character inside an open transaction.
+func saveInventoryTx(ctx context.Context, tx pgx.Tx, charID int32, inv *world.Inventory, equip *world.Equipment) error {
 	// Delete all existing items for this character
 	if _, err := tx.Exec(ctx, `DELETE FROM character_items WHERE char_id = $1`, charID); err != nil {
 		return err
@@ -102,5 +138,5 @@ func (r *ItemRepo) SaveInventory(ctx context.Context, charID int32, inv *world.I
 		}
 	}
 
-	return tx.Commit(ctx)
+	return nil
 }
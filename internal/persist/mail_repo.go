@@ -2,6 +2,7 @@ package persist
 
 import (
 	"context"
+	"fmt"
 	"time"
 )
 
@@ -16,6 +17,29 @@ type MailRow struct {
 	InboxID    int32
 	Subject    []byte
 	Content    []byte
+
+	// Parcel fields (0=plain mail, 1=unclaimed parcel, 2=claimed)
+	ParcelStatus int16
+	Adena        int32
+	ExpiresAt    *time.Time
+}
+
+// Parcel status values (mail.parcel_status).
+const (
+	ParcelNone      int16 = 0
+	ParcelUnclaimed int16 = 1
+	ParcelClaimed   int16 = 2
+)
+
+// MailAttachment is one item attached to a parcel mail.
+type MailAttachment struct {
+	ID         int32
+	MailID     int32
+	ItemID     int32
+	Count      int32
+	EnchantLvl int16
+	Bless      int16
+	Identified bool
 }
 
 type MailRepo struct {
@@ -29,7 +53,8 @@ func NewMailRepo(db *DB) *MailRepo {
 // LoadByInbox returns all mails for a given inbox owner and mail type.
 func (r *MailRepo) LoadByInbox(ctx context.Context, inboxID int32, mailType int16) ([]MailRow, error) {
 	rows, err := r.db.Pool.Query(ctx,
-		`SELECT id, type, sender, receiver, date, read_status, inbox_id, subject, content
+		`SELECT id, type, sender, receiver, date, read_status, inbox_id, subject, content,
+		        parcel_status, adena, expires_at
 		 FROM mail WHERE inbox_id = $1 AND type = $2 ORDER BY id DESC`,
 		inboxID, mailType)
 	if err != nil {
@@ -41,7 +66,8 @@ func (r *MailRepo) LoadByInbox(ctx context.Context, inboxID int32, mailType int1
 	for rows.Next() {
 		var m MailRow
 		if err := rows.Scan(&m.ID, &m.Type, &m.Sender, &m.Receiver, &m.Date,
-			&m.ReadStatus, &m.InboxID, &m.Subject, &m.Content); err != nil {
+			&m.ReadStatus, &m.InboxID, &m.Subject, &m.Content,
+			&m.ParcelStatus, &m.Adena, &m.ExpiresAt); err != nil {
 			return nil, err
 		}
 		result = append(result, m)
@@ -52,12 +78,14 @@ func (r *MailRepo) LoadByInbox(ctx context.Context, inboxID int32, mailType int1
 // GetByID returns a single mail by ID, or nil if not found.
 func (r *MailRepo) GetByID(ctx context.Context, id int32) (*MailRow, error) {
 	row := r.db.Pool.QueryRow(ctx,
-		`SELECT id, type, sender, receiver, date, read_status, inbox_id, subject, content
+		`SELECT id, type, sender, receiver, date, read_status, inbox_id, subject, content,
+		        parcel_status, adena, expires_at
 		 FROM mail WHERE id = $1`, id)
 
 	var m MailRow
 	err := row.Scan(&m.ID, &m.Type, &m.Sender, &m.Receiver, &m.Date,
-		&m.ReadStatus, &m.InboxID, &m.Subject, &m.Content)
+		&m.ReadStatus, &m.InboxID, &m.Subject, &m.Content,
+		&m.ParcelStatus, &m.Adena, &m.ExpiresAt)
 	if err != nil {
 		if err.Error() == "no rows in result set" {
 			return nil, nil
@@ -107,3 +135,168 @@ func (r *MailRepo) CountByInbox(ctx context.Context, inboxID int32, mailType int
 		inboxID, mailType).Scan(&count)
 	return count, err
 }
+
+// CountUnread returns the number of unread mails in a given inbox (all types).
+func (r *MailRepo) CountUnread(ctx context.Context, inboxID int32) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM mail WHERE inbox_id = $1 AND read_status = 0`,
+		inboxID).Scan(&count)
+	return count, err
+}
+
+// CountClaimable returns the number of unclaimed, unexpired parcels in a given inbox.
+func (r *MailRepo) CountClaimable(ctx context.Context, inboxID int32) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM mail
+		 WHERE inbox_id = $1 AND parcel_status = $2 AND expires_at > NOW()`,
+		inboxID, ParcelUnclaimed).Scan(&count)
+	return count, err
+}
+
+// WriteParcel inserts a parcel mail together with its attachments in a single
+// transaction and returns the generated mail ID.
+func (r *MailRepo) WriteParcel(ctx context.Context, m *MailRow, items []MailAttachment) (int32, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("parcel begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var id int32
+	err = tx.QueryRow(ctx,
+		`INSERT INTO mail (type, sender, receiver, date, read_status, inbox_id, subject, content,
+		                   parcel_status, adena, expires_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`,
+		m.Type, m.Sender, m.Receiver, m.Date, m.ReadStatus, m.InboxID, m.Subject, m.Content,
+		ParcelUnclaimed, m.Adena, m.ExpiresAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("parcel insert mail: %w", err)
+	}
+
+	for _, it := range items {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO mail_attachments (mail_id, item_id, count, enchant_lvl, bless, identified)
+			 VALUES ($1, $2, $3, $4, $5, $6)`,
+			id, it.ItemID, it.Count, it.EnchantLvl, it.Bless, it.Identified,
+		); err != nil {
+			return 0, fmt.Errorf("parcel insert attachment: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("parcel commit: %w", err)
+	}
+	return id, nil
+}
+
+// LoadClaimable returns all unclaimed, unexpired parcels in a given inbox.
+func (r *MailRepo) LoadClaimable(ctx context.Context, inboxID int32) ([]MailRow, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT id, type, sender, receiver, date, read_status, inbox_id, subject, content,
+		        parcel_status, adena, expires_at
+		 FROM mail WHERE inbox_id = $1 AND parcel_status = $2 AND expires_at > NOW()
+		 ORDER BY id`,
+		inboxID, ParcelUnclaimed)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []MailRow
+	for rows.Next() {
+		var m MailRow
+		if err := rows.Scan(&m.ID, &m.Type, &m.Sender, &m.Receiver, &m.Date,
+			&m.ReadStatus, &m.InboxID, &m.Subject, &m.Content,
+			&m.ParcelStatus, &m.Adena, &m.ExpiresAt); err != nil {
+			return nil, err
+		}
+		result = append(result, m)
+	}
+	return result, rows.Err()
+}
+
+// LoadAttachments returns all item attachments of a parcel mail.
+func (r *MailRepo) LoadAttachments(ctx context.Context, mailID int32) ([]MailAttachment, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT id, mail_id, item_id, count, enchant_lvl, bless, identified
+		 FROM mail_attachments WHERE mail_id = $1 ORDER BY id`, mailID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []MailAttachment
+	for rows.Next() {
+		var a MailAttachment
+		if err := rows.Scan(&a.ID, &a.MailID, &a.ItemID, &a.Count,
+			&a.EnchantLvl, &a.Bless, &a.Identified); err != nil {
+			return nil, err
+		}
+		result = append(result, a)
+	}
+	return result, rows.Err()
+}
+
+// MarkClaimed flips a parcel from unclaimed to claimed and drops its attachments.
+// Returns false if the parcel was already claimed or has expired, so the same
+// parcel can never be claimed twice.
+func (r *MailRepo) MarkClaimed(ctx context.Context, mailID int32) (bool, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("claim begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE mail SET parcel_status = $1, adena = 0
+		 WHERE id = $2 AND parcel_status = $3 AND expires_at > NOW()`,
+		ParcelClaimed, mailID, ParcelUnclaimed)
+	if err != nil {
+		return false, fmt.Errorf("claim update: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM mail_attachments WHERE mail_id = $1`, mailID); err != nil {
+		return false, fmt.Errorf("claim delete attachments: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("claim commit: %w", err)
+	}
+	return true, nil
+}
+
+// PurgeExpiredParcels forfeits all expired unclaimed parcels in a given inbox.
+// Returns the number of parcels forfeited.
+func (r *MailRepo) PurgeExpiredParcels(ctx context.Context, inboxID int32) (int64, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("purge begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM mail_attachments WHERE mail_id IN (
+		     SELECT id FROM mail WHERE inbox_id = $1 AND parcel_status = $2 AND expires_at <= NOW())`,
+		inboxID, ParcelUnclaimed); err != nil {
+		return 0, fmt.Errorf("purge attachments: %w", err)
+	}
+	tag, err := tx.Exec(ctx,
+		`UPDATE mail SET parcel_status = $1, adena = 0
+		 WHERE inbox_id = $2 AND parcel_status = $3 AND expires_at <= NOW()`,
+		ParcelNone, inboxID, ParcelUnclaimed)
+	if err != nil {
+		return 0, fmt.Errorf("purge update: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("purge commit: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
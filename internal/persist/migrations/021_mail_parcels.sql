@@ -0,0 +1,28 @@
+-- +goose Up
+
+-- 包裹信件：信件可附帶物品與金幣，收件人至郵差 NPC 領取。
+-- parcel_status: 0=一般信件, 1=待領取, 2=已領取
+ALTER TABLE mail ADD COLUMN adena INT NOT NULL DEFAULT 0;
+ALTER TABLE mail ADD COLUMN parcel_status SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE mail ADD COLUMN expires_at TIMESTAMP;
+
+CREATE TABLE mail_attachments (
+    id          SERIAL PRIMARY KEY,
+    mail_id     INT NOT NULL REFERENCES mail(id) ON DELETE CASCADE,
+    item_id     INT NOT NULL,
+    count       INT NOT NULL DEFAULT 1,
+    enchant_lvl SMALLINT NOT NULL DEFAULT 0,
+    bless       SMALLINT NOT NULL DEFAULT 0,
+    identified  BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE INDEX idx_mail_attachments_mail ON mail_attachments(mail_id);
+CREATE INDEX idx_mail_parcel ON mail(inbox_id, parcel_status);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS mail_attachments;
+DROP INDEX IF EXISTS idx_mail_parcel;
+ALTER TABLE mail DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE mail DROP COLUMN IF EXISTS parcel_status;
+ALTER TABLE mail DROP COLUMN IF EXISTS adena;
@@ -0,0 +1,10 @@
+-- +goose Up
+
+-- 首次登入旗標：新角色預設 TRUE，發放新手禮包並首次存檔後清除。
+-- 既有角色一律視為已登入過（FALSE），避免補發。
+ALTER TABLE characters ADD COLUMN first_login BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE characters ALTER COLUMN first_login SET DEFAULT TRUE;
+
+-- +goose Down
+
+ALTER TABLE characters DROP COLUMN IF EXISTS first_login;
@@ -0,0 +1,17 @@
+-- +goose Up
+
+-- 寵物歸屬與狀態：登入時依擁有者載入並重新召喚登出前在外的寵物。
+-- pet_type: 0=寵物項圈（購買/繼承）, 1=馴服術取得
+-- summoned: 存檔當下寵物是否在外（登入時自動召喚）
+ALTER TABLE character_pets ADD COLUMN IF NOT EXISTS owner_char_id INT NOT NULL DEFAULT 0;
+ALTER TABLE character_pets ADD COLUMN IF NOT EXISTS pet_type SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE character_pets ADD COLUMN IF NOT EXISTS summoned BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_character_pets_owner ON character_pets (owner_char_id);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_character_pets_owner;
+ALTER TABLE character_pets DROP COLUMN IF EXISTS summoned;
+ALTER TABLE character_pets DROP COLUMN IF EXISTS pet_type;
+ALTER TABLE character_pets DROP COLUMN IF EXISTS owner_char_id;
@@ -0,0 +1,14 @@
+-- +goose Up
+
+-- 社交拒絕選項（拒絕密語 / 組隊邀請 / 交易 / 決鬥），由 C_Windows 0x30 或 .refuse 指令切換。
+ALTER TABLE characters ADD COLUMN refuse_whisper BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE characters ADD COLUMN refuse_party   BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE characters ADD COLUMN refuse_trade   BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE characters ADD COLUMN refuse_duel    BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+
+ALTER TABLE characters DROP COLUMN IF EXISTS refuse_duel;
+ALTER TABLE characters DROP COLUMN IF EXISTS refuse_trade;
+ALTER TABLE characters DROP COLUMN IF EXISTS refuse_party;
+ALTER TABLE characters DROP COLUMN IF EXISTS refuse_whisper;
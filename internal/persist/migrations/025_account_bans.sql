@@ -0,0 +1,19 @@
+-- +goose Up
+
+-- 帳號停權期限與原因（banned_until NULL 且 banned = TRUE 表示永久停權）。
+ALTER TABLE accounts ADD COLUMN banned_until TIMESTAMPTZ;
+ALTER TABLE accounts ADD COLUMN ban_reason   TEXT NOT NULL DEFAULT '';
+
+-- IP 封鎖列表（banned_until NULL = 永久）。
+CREATE TABLE IF NOT EXISTS ip_bans (
+    ip           VARCHAR(45) PRIMARY KEY,
+    banned_until TIMESTAMPTZ,
+    reason       TEXT        NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS ip_bans;
+ALTER TABLE accounts DROP COLUMN IF EXISTS ban_reason;
+ALTER TABLE accounts DROP COLUMN IF EXISTS banned_until;
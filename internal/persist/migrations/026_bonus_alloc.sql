@@ -0,0 +1,19 @@
+-- +goose Up
+
+-- 51 級以上配點的各屬性分配數（洗配點 NPC 依此退還點數）。
+-- 既有角色的舊配點無紀錄，視為 0（不可退還）。
+ALTER TABLE characters ADD COLUMN bonus_str SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE characters ADD COLUMN bonus_dex SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE characters ADD COLUMN bonus_con SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE characters ADD COLUMN bonus_wis SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE characters ADD COLUMN bonus_int SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE characters ADD COLUMN bonus_cha SMALLINT NOT NULL DEFAULT 0;
+
+-- +goose Down
+
+ALTER TABLE characters DROP COLUMN IF EXISTS bonus_cha;
+ALTER TABLE characters DROP COLUMN IF EXISTS bonus_int;
+ALTER TABLE characters DROP COLUMN IF EXISTS bonus_wis;
+ALTER TABLE characters DROP COLUMN IF EXISTS bonus_con;
+ALTER TABLE characters DROP COLUMN IF EXISTS bonus_dex;
+ALTER TABLE characters DROP COLUMN IF EXISTS bonus_str;
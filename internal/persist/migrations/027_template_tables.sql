@@ -0,0 +1,71 @@
+-- +goose Up
+
+-- 資料模板表（[data] source = "db" 時取代 data/yaml 的對應檔案）。
+-- 每列為 YAML 檔中的一筆資料（data 欄位鍵名與 YAML 相同），由 cmd/dataimport 匯入，
+-- 可直接以 SQL 修改，伺服器重啟後生效。資料表為空時伺服器改讀 YAML 檔。
+
+CREATE TABLE IF NOT EXISTS tmpl_npcs (
+    key        INT         PRIMARY KEY, -- npc_id
+    data       JSONB       NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS tmpl_weapons (
+    key        INT         PRIMARY KEY, -- item_id
+    data       JSONB       NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS tmpl_armors (
+    key        INT         PRIMARY KEY, -- item_id
+    data       JSONB       NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS tmpl_etcitems (
+    key        INT         PRIMARY KEY, -- item_id
+    data       JSONB       NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS tmpl_spawns (
+    key        INT         PRIMARY KEY, -- position in spawn_list
+    data       JSONB       NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS tmpl_drops (
+    key        INT         PRIMARY KEY, -- mob_id
+    data       JSONB       NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS tmpl_shops (
+    key        INT         PRIMARY KEY, -- npc_id
+    data       JSONB       NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS tmpl_skills (
+    key        INT         PRIMARY KEY, -- skill_id
+    data       JSONB       NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS tmpl_mob_skills (
+    key        INT         PRIMARY KEY, -- mob_id
+    data       JSONB       NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS tmpl_mob_skills;
+DROP TABLE IF EXISTS tmpl_skills;
+DROP TABLE IF EXISTS tmpl_shops;
+DROP TABLE IF EXISTS tmpl_drops;
+DROP TABLE IF EXISTS tmpl_spawns;
+DROP TABLE IF EXISTS tmpl_etcitems;
+DROP TABLE IF EXISTS tmpl_armors;
+DROP TABLE IF EXISTS tmpl_weapons;
+DROP TABLE IF EXISTS tmpl_npcs;
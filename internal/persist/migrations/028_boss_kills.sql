@@ -0,0 +1,19 @@
+-- +goose Up
+
+-- 頭目擊殺紀錄（npc_list boss: true 的 NPC 被擊殺時寫入），供排行榜查詢。
+CREATE TABLE IF NOT EXISTS boss_kills (
+    id             BIGSERIAL   PRIMARY KEY,
+    npc_id         INTEGER     NOT NULL,
+    boss_name      VARCHAR(64) NOT NULL,
+    killer_char_id INTEGER     NOT NULL,
+    killer_name    VARCHAR(32) NOT NULL,
+    clan_name      VARCHAR(32) NOT NULL DEFAULT '',
+    map_id         SMALLINT    NOT NULL,
+    killed_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_boss_kills_killer ON boss_kills (killer_char_id);
+CREATE INDEX IF NOT EXISTS idx_boss_kills_npc ON boss_kills (npc_id, killed_at DESC);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS boss_kills;
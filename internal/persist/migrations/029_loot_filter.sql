@@ -0,0 +1,8 @@
+-- +goose Up
+
+-- 角色掉落物過濾清單（.filter 指令管理）：列出的物品 ID 擊殺掉落時直接捨棄，不放入背包。
+ALTER TABLE characters ADD COLUMN loot_filter JSONB NOT NULL DEFAULT '[]';
+
+-- +goose Down
+
+ALTER TABLE characters DROP COLUMN IF EXISTS loot_filter;
@@ -0,0 +1,8 @@
+-- +goose Up
+
+-- 高級帳號（網咖/贊助）期限；NULL 或已過期表示一般帳號。
+ALTER TABLE accounts ADD COLUMN premium_until TIMESTAMPTZ;
+
+-- +goose Down
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS premium_until;
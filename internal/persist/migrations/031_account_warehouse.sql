@@ -0,0 +1,36 @@
+-- +goose Up
+
+-- 帳號數字 ID（帳號共用倉庫的主鍵；既有帳號依序編號）。
+ALTER TABLE accounts ADD COLUMN id SERIAL UNIQUE;
+
+-- 帳號共用倉庫（個人倉庫 wh_type=3）：同帳號所有角色共用，以帳號 ID 為鍵。
+CREATE TABLE account_warehouse (
+    id            SERIAL PRIMARY KEY,
+    account_id    INT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    char_name     VARCHAR(16) NOT NULL,                 -- 存入的角色
+    item_id       INT NOT NULL,
+    count         INT NOT NULL DEFAULT 1 CHECK (count > 0),
+    enchant_lvl   SMALLINT NOT NULL DEFAULT 0,
+    bless         SMALLINT NOT NULL DEFAULT 0,
+    identified    BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_account_warehouse ON account_warehouse(account_id);
+
+-- 既有個人倉庫物品移入帳號共用倉庫
+INSERT INTO account_warehouse (account_id, char_name, item_id, count, enchant_lvl, bless, identified, created_at)
+SELECT a.id, w.char_name, w.item_id, w.count, w.enchant_lvl, w.bless, w.identified, w.created_at
+FROM warehouse_items w JOIN accounts a ON a.name = w.account_name
+WHERE w.wh_type = 3 AND w.count > 0;
+
+DELETE FROM warehouse_items WHERE wh_type = 3;
+
+-- +goose Down
+
+INSERT INTO warehouse_items (account_name, char_name, wh_type, item_id, count, enchant_lvl, bless, identified, created_at)
+SELECT a.name, w.char_name, 3, w.item_id, w.count, w.enchant_lvl, w.bless, w.identified, w.created_at
+FROM account_warehouse w JOIN accounts a ON a.id = w.account_id;
+
+DROP TABLE IF EXISTS account_warehouse;
+ALTER TABLE accounts DROP COLUMN IF EXISTS id;
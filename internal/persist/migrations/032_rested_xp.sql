@@ -0,0 +1,11 @@
+-- +goose Up
+
+-- 休息經驗：離線期間累積的經驗加成池（經驗值點數），擊殺怪物時消耗。
+-- last_logout 於每次存檔更新（含自動存檔），伺服器異常關閉時仍可估算離線起點。
+ALTER TABLE characters ADD COLUMN rested_xp BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE characters ADD COLUMN last_logout TIMESTAMPTZ;
+
+-- +goose Down
+
+ALTER TABLE characters DROP COLUMN IF EXISTS last_logout;
+ALTER TABLE characters DROP COLUMN IF EXISTS rested_xp;
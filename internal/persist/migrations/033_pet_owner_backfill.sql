@@ -0,0 +1,13 @@
+-- +goose Up
+
+-- 023 新增 owner_char_id 時既有寵物預設為 0，登入時不會被載入。
+-- 依項圈物品目前所在的角色背包回填擁有者（項圈在倉庫中的寵物於下次召喚時寫入）。
+UPDATE character_pets p
+SET owner_char_id = i.char_id
+FROM character_items i
+WHERE i.obj_id = p.item_obj_id
+  AND p.owner_char_id = 0;
+
+-- +goose Down
+
+-- 回填資料無法區分，不還原。
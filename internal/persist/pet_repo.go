@@ -4,19 +4,28 @@ import (
 	"context"
 )
 
+// Pet types (character_pets.pet_type).
+const (
+	PetTypeCollar int16 = 0 // bought / inherited pet collar
+	PetTypeTamed  int16 = 1 // obtained via Taming Monster
+)
+
 // PetRow represents a persisted pet record keyed by amulet item object ID.
 type PetRow struct {
-	ItemObjID int32  // Amulet item ObjectID (primary key)
-	ObjID     int32  // Pet NPC object ID when spawned
-	NpcID     int32  // Current NPC template ID (changes on evolution)
-	Name      string // Pet display name
-	Level     int16
-	HP        int32
-	MaxHP     int32
-	MP        int32
-	MaxMP     int32
-	Exp       int32
-	Lawful    int32
+	ItemObjID   int32  // Amulet item ObjectID (primary key)
+	ObjID       int32  // Pet NPC object ID when spawned
+	OwnerCharID int32  // Owning character ID
+	NpcID       int32  // Current NPC template ID (changes on evolution)
+	Name        string // Pet display name
+	Type        int16  // PetTypeCollar / PetTypeTamed
+	Level       int16
+	HP          int32
+	MaxHP       int32
+	MP          int32
+	MaxMP       int32
+	Exp         int32
+	Lawful      int32
+	Summoned    bool // pet was out when saved — resummon on next login
 }
 
 // PetRepo handles CRUD operations for the character_pets table.
@@ -29,40 +38,73 @@ func NewPetRepo(db *DB) *PetRepo {
 	return &PetRepo{db: db}
 }
 
-// LoadByItemObjID loads a single pet by its amulet item object ID.
-func (r *PetRepo) LoadByItemObjID(ctx context.Context, itemObjID int32) (*PetRow, error) {
-	row := r.db.Pool.QueryRow(ctx,
-		`SELECT item_obj_id, obj_id, npc_id, name, level, hp, hpmax, mp, mpmax, exp, lawful
-		 FROM character_pets WHERE item_obj_id = $1`, itemObjID,
-	)
+const petColumns = `item_obj_id, obj_id, owner_char_id, npc_id, name, pet_type,
+		        level, hp, hpmax, mp, mpmax, exp, lawful, summoned`
+
+// scanPet scans one row selected with petColumns.
+func scanPet(row interface{ Scan(dest ...any) error }) (*PetRow, error) {
 	var p PetRow
 	if err := row.Scan(
-		&p.ItemObjID, &p.ObjID, &p.NpcID, &p.Name,
-		&p.Level, &p.HP, &p.MaxHP, &p.MP, &p.MaxMP, &p.Exp, &p.Lawful,
+		&p.ItemObjID, &p.ObjID, &p.OwnerCharID, &p.NpcID, &p.Name, &p.Type,
+		&p.Level, &p.HP, &p.MaxHP, &p.MP, &p.MaxMP, &p.Exp, &p.Lawful, &p.Summoned,
 	); err != nil {
 		return nil, err
 	}
 	return &p, nil
 }
 
+// LoadByItemObjID loads a single pet by its amulet item object ID.
+func (r *PetRepo) LoadByItemObjID(ctx context.Context, itemObjID int32) (*PetRow, error) {
+	return scanPet(r.db.Pool.QueryRow(ctx,
+		`SELECT `+petColumns+`
+		 FROM character_pets WHERE item_obj_id = $1`, itemObjID,
+	))
+}
+
+// LoadByOwner loads all pets owned by a character.
+func (r *PetRepo) LoadByOwner(ctx context.Context, ownerCharID int32) ([]PetRow, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT `+petColumns+`
+		 FROM character_pets WHERE owner_char_id = $1 ORDER BY item_obj_id`, ownerCharID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []PetRow
+	for rows.Next() {
+		p, err := scanPet(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *p)
+	}
+	return result, rows.Err()
+}
+
 // Save inserts or updates a pet record (upsert by item_obj_id).
 func (r *PetRepo) Save(ctx context.Context, p *PetRow) error {
 	_, err := r.db.Pool.Exec(ctx,
-		`INSERT INTO character_pets (item_obj_id, obj_id, npc_id, name, level, hp, hpmax, mp, mpmax, exp, lawful)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		`INSERT INTO character_pets (item_obj_id, obj_id, owner_char_id, npc_id, name, pet_type,
+		                             level, hp, hpmax, mp, mpmax, exp, lawful, summoned)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		 ON CONFLICT (item_obj_id) DO UPDATE SET
-		   obj_id = EXCLUDED.obj_id,
-		   npc_id = EXCLUDED.npc_id,
-		   name   = EXCLUDED.name,
-		   level  = EXCLUDED.level,
-		   hp     = EXCLUDED.hp,
-		   hpmax  = EXCLUDED.hpmax,
-		   mp     = EXCLUDED.mp,
-		   mpmax  = EXCLUDED.mpmax,
-		   exp    = EXCLUDED.exp,
-		   lawful = EXCLUDED.lawful`,
-		p.ItemObjID, p.ObjID, p.NpcID, p.Name,
-		p.Level, p.HP, p.MaxHP, p.MP, p.MaxMP, p.Exp, p.Lawful,
+		   obj_id        = EXCLUDED.obj_id,
+		   owner_char_id = EXCLUDED.owner_char_id,
+		   npc_id        = EXCLUDED.npc_id,
+		   name          = EXCLUDED.name,
+		   pet_type      = EXCLUDED.pet_type,
+		   level         = EXCLUDED.level,
+		   hp            = EXCLUDED.hp,
+		   hpmax         = EXCLUDED.hpmax,
+		   mp            = EXCLUDED.mp,
+		   mpmax         = EXCLUDED.mpmax,
+		   exp           = EXCLUDED.exp,
+		   lawful        = EXCLUDED.lawful,
+		   summoned      = EXCLUDED.summoned`,
+		p.ItemObjID, p.ObjID, p.OwnerCharID, p.NpcID, p.Name, p.Type,
+		p.Level, p.HP, p.MaxHP, p.MP, p.MaxMP, p.Exp, p.Lawful, p.Summoned,
 	)
 	return err
 }
@@ -0,0 +1,168 @@
+package persist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TemplateSet maps one data/yaml file to its template table.
+type TemplateSet struct {
+	File  string // file name under data/yaml
+	Table string
+	Root  string // top-level YAML key holding the entry list
+	Key   string // entry field used as the primary key ("" = 1-based position in the file)
+}
+
+// TemplateSets lists the YAML tables that can be served from the database
+// ([data] source = "db") and imported with cmd/dataimport.
+var TemplateSets = []TemplateSet{
+	{File: "npc_list.yaml", Table: "tmpl_npcs", Root: "npcs", Key: "npc_id"},
+	{File: "weapon_list.yaml", Table: "tmpl_weapons", Root: "weapons", Key: "item_id"},
+	{File: "armor_list.yaml", Table: "tmpl_armors", Root: "armors", Key: "item_id"},
+	{File: "etcitem_list.yaml", Table: "tmpl_etcitems", Root: "items", Key: "item_id"},
+	{File: "spawn_list.yaml", Table: "tmpl_spawns", Root: "spawns"},
+	{File: "drop_list.yaml", Table: "tmpl_drops", Root: "drops", Key: "mob_id"},
+	{File: "shop_list.yaml", Table: "tmpl_shops", Root: "shops", Key: "npc_id"},
+	{File: "skill_list.yaml", Table: "tmpl_skills", Root: "skills", Key: "skill_id"},
+	{File: "mob_skill_list.yaml", Table: "tmpl_mob_skills", Root: "mob_skills", Key: "mob_id"},
+}
+
+// FindTemplateSet returns the template set for a data file path, or nil.
+func FindTemplateSet(path string) *TemplateSet {
+	name := filepath.Base(path)
+	for i := range TemplateSets {
+		if TemplateSets[i].File == name {
+			return &TemplateSets[i]
+		}
+	}
+	return nil
+}
+
+// TemplateRepo stores YAML data tables as one JSONB row per entry.
+type TemplateRepo struct {
+	db *DB
+}
+
+func NewTemplateRepo(db *DB) *TemplateRepo {
+	return &TemplateRepo{db: db}
+}
+
+// Import upserts the entries of one template set. With prune, rows whose key
+// is not in entries are deleted so the table mirrors the YAML file exactly.
+// Returns the number of rows written.
+func (r *TemplateRepo) Import(ctx context.Context, set *TemplateSet, entries []map[string]any, prune bool) (int, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	keys := make([]int32, 0, len(entries))
+	batch := &pgx.Batch{}
+	for i, e := range entries {
+		key := int32(i + 1)
+		if set.Key != "" {
+			k, ok := entryKey(e[set.Key])
+			if !ok {
+				return 0, fmt.Errorf("%s entry %d: missing or invalid %s", set.File, i+1, set.Key)
+			}
+			key = k
+		}
+		doc, err := json.Marshal(e)
+		if err != nil {
+			return 0, fmt.Errorf("%s entry %d: %w", set.File, i+1, err)
+		}
+		keys = append(keys, key)
+		batch.Queue(
+			`INSERT INTO `+set.Table+` (key, data) VALUES ($1, $2)
+			 ON CONFLICT (key) DO UPDATE SET data = EXCLUDED.data, updated_at = NOW()`,
+			key, doc,
+		)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return 0, err
+	}
+	if prune {
+		if _, err := tx.Exec(ctx, `DELETE FROM `+set.Table+` WHERE NOT (key = ANY($1))`, keys); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// Document rebuilds the YAML document of a template set from its table, as
+// JSON ({"<root>": [...]} ordered by key). Returns ok = false when the table
+// is empty.
+func (r *TemplateRepo) Document(ctx context.Context, set *TemplateSet) ([]byte, bool, error) {
+	rows, err := r.db.Pool.Query(ctx, `SELECT data FROM `+set.Table+` ORDER BY key`)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	var entries []json.RawMessage
+	for rows.Next() {
+		var doc []byte
+		if err := rows.Scan(&doc); err != nil {
+			return nil, false, err
+		}
+		entries = append(entries, doc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+	if len(entries) == 0 {
+		return nil, false, nil
+	}
+	raw, err := json.Marshal(map[string][]json.RawMessage{set.Root: entries})
+	if err != nil {
+		return nil, false, err
+	}
+	return raw, true, nil
+}
+
+// FileReader returns a data file reader for data.SetFileReader: template
+// files are rebuilt from their table, everything else (and any template
+// table that is still empty) is read from disk.
+func (r *TemplateRepo) FileReader() func(path string) ([]byte, error) {
+	return func(path string) ([]byte, error) {
+		set := FindTemplateSet(path)
+		if set == nil {
+			return os.ReadFile(path)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		raw, ok, err := r.Document(ctx, set)
+		if err != nil {
+			return nil, fmt.Errorf("load %s from %s: %w", set.File, set.Table, err)
+		}
+		if !ok {
+			return os.ReadFile(path)
+		}
+		return raw, nil
+	}
+}
+
+// entryKey converts a decoded YAML key value to an int32 primary key.
+func entryKey(v any) (int32, bool) {
+	switch n := v.(type) {
+	case int:
+		return int32(n), true
+	case int64:
+		return int32(n), true
+	case uint64:
+		return int32(n), true
+	case float64:
+		return int32(n), n == float64(int32(n))
+	}
+	return 0, false
+}
@@ -7,6 +7,7 @@ import (
 // WarehouseItem represents a single item stored in the warehouse.
 type WarehouseItem struct {
 	ID          int32
+	AccountID   int32 // account_warehouse only
 	AccountName string
 	CharName    string
 	WhType      int16 // 3=personal, 4=elf, 5=clan, 6=character
@@ -0,0 +1,93 @@
+package persist
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// writeTimeout bounds a single queued write.
+const writeTimeout = 3 * time.Second
+
+type queuedWrite struct {
+	what string
+	fn   func(ctx context.Context) error
+}
+
+// WriteQueue runs small per-character writes (toggles, filter lists, bank
+// deposits) on one background goroutine in submission order. The game loop
+// never waits on the database, and two writes of the same row commit in the
+// order they were made, so the last change always wins.
+//
+// Each write's ctx derives from the queue's lifetime: once Close has drained
+// the queue it is canceled, so delayed resubmissions can tell the server is
+// shutting down and stop retrying.
+type WriteQueue struct {
+	ch     chan queuedWrite
+	log    *zap.Logger
+	ctx    context.Context
+	cancel context.CancelFunc
+	mu     sync.RWMutex // guards closed against a concurrent Submit
+	closed bool
+	once   sync.Once
+	done   chan struct{}
+}
+
+// NewWriteQueue starts the worker. size is the channel buffer; Submit blocks
+// once that many writes are pending.
+func NewWriteQueue(size int, log *zap.Logger) *WriteQueue {
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &WriteQueue{
+		ch:     make(chan queuedWrite, size),
+		log:    log,
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+// Submit queues fn. Failures are logged with the what label. After Close, fn
+// runs on the caller's goroutine instead, with the queue's canceled ctx.
+func (q *WriteQueue) Submit(what string, fn func(ctx context.Context) error) {
+	w := queuedWrite{what: what, fn: fn}
+	q.mu.RLock()
+	if !q.closed {
+		q.ch <- w
+		q.mu.RUnlock()
+		return
+	}
+	q.mu.RUnlock()
+	q.exec(w)
+}
+
+// Close stops accepting writes, waits until every queued write has run, then
+// cancels the ctx given to later writes.
+func (q *WriteQueue) Close() {
+	q.once.Do(func() {
+		q.mu.Lock()
+		q.closed = true
+		close(q.ch)
+		q.mu.Unlock()
+	})
+	<-q.done
+	q.cancel()
+}
+
+func (q *WriteQueue) run() {
+	defer close(q.done)
+	for w := range q.ch {
+		q.exec(w)
+	}
+}
+
+func (q *WriteQueue) exec(w queuedWrite) {
+	ctx, cancel := context.WithTimeout(q.ctx, writeTimeout)
+	defer cancel()
+	if err := w.fn(ctx); err != nil {
+		q.log.Error("佇列寫入失敗", zap.String("what", w.what), zap.Error(err))
+	}
+}
@@ -0,0 +1,50 @@
+package persist
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestWriteQueueRunsInOrder(t *testing.T) {
+	q := NewWriteQueue(4, zap.NewNop())
+	var got []int
+	for i := 0; i < 100; i++ {
+		q.Submit("test", func(context.Context) error {
+			got = append(got, i)
+			return nil
+		})
+	}
+	q.Close()
+	if len(got) != 100 {
+		t.Fatalf("ran %d writes, want 100", len(got))
+	}
+	for i, v := range got {
+		if v != i {
+			t.Fatalf("write %d ran as #%d", v, i)
+		}
+	}
+}
+
+func TestWriteQueueCancelsWritesAfterClose(t *testing.T) {
+	q := NewWriteQueue(4, zap.NewNop())
+	var drained error
+	q.Submit("test", func(ctx context.Context) error {
+		drained = ctx.Err()
+		return nil
+	})
+	q.Close()
+	if drained != nil {
+		t.Fatalf("write drained by Close saw ctx error %v", drained)
+	}
+
+	var late error
+	q.Submit("late", func(ctx context.Context) error {
+		late = ctx.Err()
+		return nil
+	})
+	if late != context.Canceled {
+		t.Fatalf("write submitted after Close saw ctx error %v, want Canceled", late)
+	}
+}
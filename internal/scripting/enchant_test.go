@@ -0,0 +1,80 @@
+package scripting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/l1jgo/server/internal/world"
+)
+
+const (
+	scrollNormal  = 0
+	scrollBlessed = 1
+	scrollCursed  = 2
+)
+
+// enchantOutcomes 以同一情境擲 n 次，回傳各結果出現次數。
+func enchantOutcomes(e *Engine, bless, lvl, n int) map[string]int {
+	out := make(map[string]int)
+	for i := 0; i < n; i++ {
+		r := e.CalcEnchant(EnchantContext{
+			ScrollBless: bless, EnchantLvl: lvl, SafeEnchant: 6, Category: 1,
+			WeaponChance: 0.68, ArmorChance: 0.52, MaxEnchant: 12,
+		})
+		out[r.Result]++
+		if r.Result == "success" && lvl+r.Amount > 12 {
+			out["past_cap"]++
+		}
+	}
+	return out
+}
+
+func TestEnchantScrollClasses(t *testing.T) {
+	world.SetRand(world.NewRand(7))
+	t.Cleanup(func() { world.SetRand(world.NewRand(time.Now().UnixNano())) })
+	e := newTestEngine(t)
+	const n = 500
+
+	tests := []struct {
+		name  string
+		bless int
+		lvl   int
+		allow map[string]bool // 允許出現的結果
+		need  []string        // 必須出現的結果
+	}{
+		{"一般/安定值內", scrollNormal, 5, map[string]bool{"success": true}, []string{"success"}},
+		{"祝福/安定值內", scrollBlessed, 5, map[string]bool{"success": true}, []string{"success"}},
+		{"詛咒/安定值內", scrollCursed, 5, map[string]bool{"minus": true}, []string{"minus"}},
+		{"一般/超過安定值", scrollNormal, 10, map[string]bool{"success": true, "nochange": true, "break": true}, []string{"success", "break"}},
+		{"祝福/超過安定值", scrollBlessed, 10, map[string]bool{"success": true, "nochange": true}, []string{"success", "nochange"}},
+		{"詛咒/超過安定值", scrollCursed, 10, map[string]bool{"minus": true}, []string{"minus"}},
+		{"一般/上限", scrollNormal, 12, map[string]bool{"nochange": true, "break": true}, []string{"break"}},
+		{"祝福/上限", scrollBlessed, 12, map[string]bool{"nochange": true}, []string{"nochange"}},
+		{"詛咒/上限", scrollCursed, 12, map[string]bool{"minus": true}, []string{"minus"}},
+	}
+	for _, tt := range tests {
+		got := enchantOutcomes(e, tt.bless, tt.lvl, n)
+		if got["past_cap"] > 0 {
+			t.Errorf("%s: %d successes pushed the level past the cap", tt.name, got["past_cap"])
+		}
+		delete(got, "past_cap")
+		for res, cnt := range got {
+			if !tt.allow[res] {
+				t.Errorf("%s: unexpected %q x%d", tt.name, res, cnt)
+			}
+		}
+		for _, res := range tt.need {
+			if got[res] == 0 {
+				t.Errorf("%s: %q never rolled in %d tries (%v)", tt.name, res, n, got)
+			}
+		}
+	}
+}
+
+func TestCursedScrollBreaksAtMinusSeven(t *testing.T) {
+	e := newTestEngine(t)
+	r := e.CalcEnchant(EnchantContext{ScrollBless: scrollCursed, EnchantLvl: -7, SafeEnchant: 6, Category: 2, MaxEnchant: 12})
+	if r.Result != "break" {
+		t.Fatalf("cursed scroll at -7: %q, want break", r.Result)
+	}
+}
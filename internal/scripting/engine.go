@@ -1,9 +1,11 @@
 package scripting
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	lua "github.com/yuin/gopher-lua"
 	"go.uber.org/zap"
@@ -12,10 +14,19 @@ import (
 // Engine wraps a single gopher-lua VM for game logic execution.
 // Single-goroutine access only (game loop). Hot-reload planned via atomic swap.
 type Engine struct {
-	vm  *lua.LState
-	log *zap.Logger
+	vm      *lua.LState
+	log     *zap.Logger
+	timeout time.Duration // per-call limit ([lua] timeout); 0 = unlimited
 }
 
+// Safe results for a combat script that is missing, raises an error or runs
+// past the call timeout: a miss / zero damage, so a broken script never
+// deals damage.
+var (
+	combatFallback      = CombatResult{}
+	skillDamageFallback = SkillDamageResult{HitCount: 1}
+)
+
 // NewEngine creates a Lua engine and loads all scripts from the given directory.
 func NewEngine(scriptsDir string, log *zap.Logger) (*Engine, error) {
 	vm := lua.NewState(lua.Options{
@@ -24,6 +35,7 @@ func NewEngine(scriptsDir string, log *zap.Logger) (*Engine, error) {
 
 	// Set API version global
 	vm.SetGlobal("API_VERSION", lua.LNumber(1))
+	installRandom(vm)
 
 	e := &Engine{vm: vm, log: log}
 
@@ -82,9 +94,13 @@ type CombatContext struct {
 	AttackerWeapon int // max weapon damage (0 = fist = 4)
 	AttackerHitMod int // equipment hit modifier
 	AttackerDmgMod int // equipment damage modifier
+	AttackerBowHitMod int // bow hit modifier (equipment + buffs)
+	AttackerBowDmgMod int // bow damage modifier (equipment + buffs)
+	AttackerEnchant   int // weapon enchant minus wear (0 for NPCs / bare hands)
 	TargetAC       int
 	TargetLevel    int
 	TargetMR       int
+	TargetDodge    int // target dodge bonus (0 for NPCs)
 	TargetClassType int // 目標職業（-1=NPC, 0-7=玩家職業）— AC 防禦加成用
 }
 
@@ -97,9 +113,11 @@ type RangedCombatContext struct {
 	AttackerArrowDmg  int // arrow damage bonus
 	AttackerBowHitMod int // bow hit modifier (equipment + buffs)
 	AttackerBowDmgMod int // bow damage modifier (equipment + buffs)
+	AttackerEnchant   int // bow enchant minus wear
 	TargetAC          int
 	TargetLevel       int
 	TargetMR          int
+	TargetDodge       int // target dodge bonus (0 for NPCs)
 	TargetClassType   int // 目標職業（-1=NPC, 0-7=玩家職業）
 }
 
@@ -114,7 +132,7 @@ func (e *Engine) CalcMeleeAttack(ctx CombatContext) CombatResult {
 	fn := e.vm.GetGlobal("calc_melee_attack")
 	if fn == lua.LNil {
 		e.log.Error("lua function calc_melee_attack not found")
-		return CombatResult{IsHit: true, Damage: 1}
+		return combatFallback
 	}
 
 	// Build context table
@@ -127,22 +145,26 @@ func (e *Engine) CalcMeleeAttack(ctx CombatContext) CombatResult {
 	atk.RawSetString("weapon_dmg", lua.LNumber(ctx.AttackerWeapon))
 	atk.RawSetString("hit_mod", lua.LNumber(ctx.AttackerHitMod))
 	atk.RawSetString("dmg_mod", lua.LNumber(ctx.AttackerDmgMod))
+	atk.RawSetString("bow_hit_mod", lua.LNumber(ctx.AttackerBowHitMod))
+	atk.RawSetString("bow_dmg_mod", lua.LNumber(ctx.AttackerBowDmgMod))
+	atk.RawSetString("enchant", lua.LNumber(ctx.AttackerEnchant))
 	t.RawSetString("attacker", atk)
 
 	tgt := e.vm.NewTable()
 	tgt.RawSetString("ac", lua.LNumber(ctx.TargetAC))
 	tgt.RawSetString("level", lua.LNumber(ctx.TargetLevel))
 	tgt.RawSetString("mr", lua.LNumber(ctx.TargetMR))
+	tgt.RawSetString("dodge", lua.LNumber(ctx.TargetDodge))
 	tgt.RawSetString("class_type", lua.LNumber(ctx.TargetClassType))
 	t.RawSetString("target", tgt)
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
 	}, t); err != nil {
 		e.log.Error("lua calc_melee_attack error", zap.Error(err))
-		return CombatResult{IsHit: true, Damage: 1}
+		return combatFallback
 	}
 
 	result := e.vm.Get(-1)
@@ -151,7 +173,7 @@ func (e *Engine) CalcMeleeAttack(ctx CombatContext) CombatResult {
 	rt, ok := result.(*lua.LTable)
 	if !ok {
 		e.log.Error("lua calc_melee_attack returned non-table")
-		return CombatResult{IsHit: true, Damage: 1}
+		return combatFallback
 	}
 
 	return CombatResult{
@@ -165,7 +187,7 @@ func (e *Engine) CalcRangedAttack(ctx RangedCombatContext) CombatResult {
 	fn := e.vm.GetGlobal("calc_ranged_attack")
 	if fn == lua.LNil {
 		e.log.Error("lua function calc_ranged_attack not found")
-		return CombatResult{IsHit: true, Damage: 1}
+		return combatFallback
 	}
 
 	t := e.vm.NewTable()
@@ -178,22 +200,24 @@ func (e *Engine) CalcRangedAttack(ctx RangedCombatContext) CombatResult {
 	atk.RawSetString("arrow_dmg", lua.LNumber(ctx.AttackerArrowDmg))
 	atk.RawSetString("bow_hit_mod", lua.LNumber(ctx.AttackerBowHitMod))
 	atk.RawSetString("bow_dmg_mod", lua.LNumber(ctx.AttackerBowDmgMod))
+	atk.RawSetString("enchant", lua.LNumber(ctx.AttackerEnchant))
 	t.RawSetString("attacker", atk)
 
 	tgt := e.vm.NewTable()
 	tgt.RawSetString("ac", lua.LNumber(ctx.TargetAC))
 	tgt.RawSetString("level", lua.LNumber(ctx.TargetLevel))
 	tgt.RawSetString("mr", lua.LNumber(ctx.TargetMR))
+	tgt.RawSetString("dodge", lua.LNumber(ctx.TargetDodge))
 	tgt.RawSetString("class_type", lua.LNumber(ctx.TargetClassType))
 	t.RawSetString("target", tgt)
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
 	}, t); err != nil {
 		e.log.Error("lua calc_ranged_attack error", zap.Error(err))
-		return CombatResult{IsHit: true, Damage: 1}
+		return combatFallback
 	}
 
 	result := e.vm.Get(-1)
@@ -202,7 +226,7 @@ func (e *Engine) CalcRangedAttack(ctx RangedCombatContext) CombatResult {
 	rt, ok := result.(*lua.LTable)
 	if !ok {
 		e.log.Error("lua calc_ranged_attack returned non-table")
-		return CombatResult{IsHit: true, Damage: 1}
+		return combatFallback
 	}
 
 	return CombatResult{
@@ -258,7 +282,7 @@ func (e *Engine) CalcSkillDamage(ctx SkillDamageContext) SkillDamageResult {
 	fn := e.vm.GetGlobal("calc_skill_damage")
 	if fn == lua.LNil {
 		e.log.Error("lua function calc_skill_damage not found")
-		return SkillDamageResult{Damage: 1, HitCount: 1}
+		return skillDamageFallback
 	}
 
 	t := e.vm.NewTable()
@@ -298,13 +322,13 @@ func (e *Engine) CalcSkillDamage(ctx SkillDamageContext) SkillDamageResult {
 	tgt.RawSetString("mp", lua.LNumber(ctx.TargetMP))
 	t.RawSetString("target", tgt)
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
 	}, t); err != nil {
 		e.log.Error("lua calc_skill_damage error", zap.Error(err))
-		return SkillDamageResult{Damage: 1, HitCount: 1}
+		return skillDamageFallback
 	}
 
 	result := e.vm.Get(-1)
@@ -313,7 +337,7 @@ func (e *Engine) CalcSkillDamage(ctx SkillDamageContext) SkillDamageResult {
 	rt, ok := result.(*lua.LTable)
 	if !ok {
 		e.log.Error("lua calc_skill_damage returned non-table")
-		return SkillDamageResult{Damage: 1, HitCount: 1}
+		return skillDamageFallback
 	}
 
 	hitCount := int(lua.LVAsNumber(rt.RawGetString("hit_count")))
@@ -366,7 +390,7 @@ func (e *Engine) GetBuffEffect(skillID, targetLevel int) *BuffEffect {
 		return nil
 	}
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
@@ -435,7 +459,7 @@ func (e *Engine) IsNonCancellable(skillID int) bool {
 		return false
 	}
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
@@ -485,7 +509,7 @@ func (e *Engine) GetPotionEffect(itemID int) *PotionEffect {
 		return nil
 	}
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
@@ -543,7 +567,7 @@ func (e *Engine) GetCharCreateData(classType int) *CharCreateData {
 		return nil
 	}
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
@@ -614,7 +638,7 @@ func (e *Engine) GetResurrectEffect(skillID int) *ResurrectResult {
 		return nil
 	}
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
@@ -659,7 +683,7 @@ func (e *Engine) GetSpellTiers(classType int) []SpellTierInfo {
 		return nil
 	}
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
@@ -694,84 +718,7 @@ func (e *Engine) GetSpellTiers(classType int) []SpellTierInfo {
 	return tiers
 }
 
-// --- Death/Respawn Bridge ---
-
-// RespawnLocation holds a respawn coordinate.
-type RespawnLocation struct {
-	X, Y int
-	Map  int
-}
-
-// GetRespawnLocation calls Lua get_respawn_location(map_id).
-func (e *Engine) GetRespawnLocation(mapID int) *RespawnLocation {
-	fn := e.vm.GetGlobal("get_respawn_location")
-	if fn == lua.LNil {
-		return nil
-	}
-
-	if err := e.vm.CallByParam(lua.P{
-		Fn:      fn,
-		NRet:    1,
-		Protect: true,
-	}, lua.LNumber(mapID)); err != nil {
-		e.log.Error("lua get_respawn_location error", zap.Error(err))
-		return nil
-	}
-
-	result := e.vm.Get(-1)
-	e.vm.Pop(1)
-
-	if result == lua.LNil {
-		return nil
-	}
-
-	rt, ok := result.(*lua.LTable)
-	if !ok {
-		return nil
-	}
-
-	return &RespawnLocation{
-		X:   lInt(rt, "x"),
-		Y:   lInt(rt, "y"),
-		Map: lInt(rt, "map"),
-	}
-}
-
-// GetHomeScrollLocation 呼叫 Lua get_home_scroll_location(map_id, x, y)。
-// 回家卷軸依地圖和座標找最近城鎮（非主大陸查映射表，主大陸找最近城鎮）。
-func (e *Engine) GetHomeScrollLocation(mapID, x, y int) *RespawnLocation {
-	fn := e.vm.GetGlobal("get_home_scroll_location")
-	if fn == lua.LNil {
-		return nil
-	}
-
-	if err := e.vm.CallByParam(lua.P{
-		Fn:      fn,
-		NRet:    1,
-		Protect: true,
-	}, lua.LNumber(mapID), lua.LNumber(x), lua.LNumber(y)); err != nil {
-		e.log.Error("lua get_home_scroll_location error", zap.Error(err))
-		return nil
-	}
-
-	result := e.vm.Get(-1)
-	e.vm.Pop(1)
-
-	if result == lua.LNil {
-		return nil
-	}
-
-	rt, ok := result.(*lua.LTable)
-	if !ok {
-		return nil
-	}
-
-	return &RespawnLocation{
-		X:   lInt(rt, "x"),
-		Y:   lInt(rt, "y"),
-		Map: lInt(rt, "map"),
-	}
-}
+// --- Death Bridge ---
 
 // CalcDeathExpPenalty calls Lua calc_death_exp_penalty(level, exp).
 func (e *Engine) CalcDeathExpPenalty(level, exp int) int {
@@ -788,11 +735,12 @@ type EnchantContext struct {
 	Category     int     // 1=weapon, 2=armor
 	WeaponChance float64 // config success rate for weapons
 	ArmorChance  float64 // config success rate for armor
+	MaxEnchant   int     // enchant cap (level cannot rise past it)
 }
 
 // EnchantResult is returned by the Lua enchant function.
 type EnchantResult struct {
-	Result string // "success", "fail", "break", "minus"
+	Result string // "success", "nochange", "break", "minus" ("fail" on script error)
 	Amount int    // enchant delta
 }
 
@@ -811,8 +759,9 @@ func (e *Engine) CalcEnchant(ctx EnchantContext) EnchantResult {
 	t.RawSetString("category", lua.LNumber(ctx.Category))
 	t.RawSetString("weapon_chance", lua.LNumber(ctx.WeaponChance))
 	t.RawSetString("armor_chance", lua.LNumber(ctx.ArmorChance))
+	t.RawSetString("max_enchant", lua.LNumber(ctx.MaxEnchant))
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
@@ -851,6 +800,7 @@ type MobSkillEntry struct {
 
 // AIContext holds pre-packed data for NPC AI decisions.
 type AIContext struct {
+	ID         int // NPC object ID (pass to world.* queries)
 	NpcID      int
 	X, Y       int
 	MapID      int
@@ -885,7 +835,7 @@ type AIContext struct {
 
 // AICommand is a single action returned by Lua AI.
 type AICommand struct {
-	Type    string // "attack", "ranged_attack", "skill", "move_toward", "wander", "lose_aggro", "idle"
+	Type    string // "attack", "ranged_attack", "skill", "move_toward", "flee", "wander", "lose_aggro", "idle"
 	SkillID int
 	ActID   int
 	GfxID   int // mob-specific spell effect override (0 = use skill's CastGfx)
@@ -901,6 +851,7 @@ func (e *Engine) RunNpcAI(ctx AIContext) []AICommand {
 
 	// Build context table
 	t := e.vm.NewTable()
+	t.RawSetString("id", lua.LNumber(ctx.ID))
 	t.RawSetString("npc_id", lua.LNumber(ctx.NpcID))
 	t.RawSetString("x", lua.LNumber(ctx.X))
 	t.RawSetString("y", lua.LNumber(ctx.Y))
@@ -956,7 +907,7 @@ func (e *Engine) RunNpcAI(ctx AIContext) []AICommand {
 	}
 	t.RawSetString("skills", skillsTbl)
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
@@ -993,7 +944,7 @@ func (e *Engine) RunNpcAI(ctx AIContext) []AICommand {
 func (e *Engine) CalcNpcMelee(ctx CombatContext) CombatResult {
 	fn := e.vm.GetGlobal("calc_npc_melee")
 	if fn == lua.LNil {
-		return CombatResult{IsHit: true, Damage: 1}
+		return combatFallback
 	}
 
 	t := e.vm.NewTable()
@@ -1005,21 +956,26 @@ func (e *Engine) CalcNpcMelee(ctx CombatContext) CombatResult {
 	atk.RawSetString("weapon_dmg", lua.LNumber(ctx.AttackerWeapon))
 	atk.RawSetString("hit_mod", lua.LNumber(ctx.AttackerHitMod))
 	atk.RawSetString("dmg_mod", lua.LNumber(ctx.AttackerDmgMod))
+	atk.RawSetString("bow_hit_mod", lua.LNumber(ctx.AttackerBowHitMod))
+	atk.RawSetString("bow_dmg_mod", lua.LNumber(ctx.AttackerBowDmgMod))
+	atk.RawSetString("enchant", lua.LNumber(ctx.AttackerEnchant))
 	t.RawSetString("attacker", atk)
 
 	tgt := e.vm.NewTable()
 	tgt.RawSetString("ac", lua.LNumber(ctx.TargetAC))
 	tgt.RawSetString("level", lua.LNumber(ctx.TargetLevel))
 	tgt.RawSetString("mr", lua.LNumber(ctx.TargetMR))
+	tgt.RawSetString("dodge", lua.LNumber(ctx.TargetDodge))
+	tgt.RawSetString("class_type", lua.LNumber(ctx.TargetClassType))
 	t.RawSetString("target", tgt)
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
 	}, t); err != nil {
 		e.log.Error("lua calc_npc_melee error", zap.Error(err))
-		return CombatResult{IsHit: true, Damage: 1}
+		return combatFallback
 	}
 
 	res := e.vm.Get(-1)
@@ -1027,7 +983,7 @@ func (e *Engine) CalcNpcMelee(ctx CombatContext) CombatResult {
 
 	rt2, ok := res.(*lua.LTable)
 	if !ok {
-		return CombatResult{IsHit: true, Damage: 1}
+		return combatFallback
 	}
 
 	return CombatResult{
@@ -1040,7 +996,7 @@ func (e *Engine) CalcNpcMelee(ctx CombatContext) CombatResult {
 func (e *Engine) CalcNpcRanged(ctx CombatContext) CombatResult {
 	fn := e.vm.GetGlobal("calc_npc_ranged")
 	if fn == lua.LNil {
-		return CombatResult{IsHit: true, Damage: 1}
+		return combatFallback
 	}
 
 	t := e.vm.NewTable()
@@ -1052,21 +1008,26 @@ func (e *Engine) CalcNpcRanged(ctx CombatContext) CombatResult {
 	atk.RawSetString("weapon_dmg", lua.LNumber(ctx.AttackerWeapon))
 	atk.RawSetString("hit_mod", lua.LNumber(ctx.AttackerHitMod))
 	atk.RawSetString("dmg_mod", lua.LNumber(ctx.AttackerDmgMod))
+	atk.RawSetString("bow_hit_mod", lua.LNumber(ctx.AttackerBowHitMod))
+	atk.RawSetString("bow_dmg_mod", lua.LNumber(ctx.AttackerBowDmgMod))
+	atk.RawSetString("enchant", lua.LNumber(ctx.AttackerEnchant))
 	t.RawSetString("attacker", atk)
 
 	tgt := e.vm.NewTable()
 	tgt.RawSetString("ac", lua.LNumber(ctx.TargetAC))
 	tgt.RawSetString("level", lua.LNumber(ctx.TargetLevel))
 	tgt.RawSetString("mr", lua.LNumber(ctx.TargetMR))
+	tgt.RawSetString("dodge", lua.LNumber(ctx.TargetDodge))
+	tgt.RawSetString("class_type", lua.LNumber(ctx.TargetClassType))
 	t.RawSetString("target", tgt)
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
 	}, t); err != nil {
 		e.log.Error("lua calc_npc_ranged error", zap.Error(err))
-		return CombatResult{IsHit: true, Damage: 1}
+		return combatFallback
 	}
 
 	res := e.vm.Get(-1)
@@ -1074,7 +1035,7 @@ func (e *Engine) CalcNpcRanged(ctx CombatContext) CombatResult {
 
 	rt2, ok := res.(*lua.LTable)
 	if !ok {
-		return CombatResult{IsHit: true, Damage: 1}
+		return combatFallback
 	}
 
 	return CombatResult{
@@ -1102,7 +1063,7 @@ func (e *Engine) CalcPKLawfulPenalty(killerLevel int, killerLawful int32) PKLawf
 	t.RawSetString("killer_level", lua.LNumber(killerLevel))
 	t.RawSetString("killer_lawful", lua.LNumber(killerLawful))
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
@@ -1131,7 +1092,8 @@ type PKItemDropResult struct {
 }
 
 // CalcPKItemDrop calls Lua calc_pk_item_drop(ctx).
-func (e *Engine) CalcPKItemDrop(victimLawful int32) PKItemDropResult {
+// ratePct scales the drop chance (100 = formula as-is).
+func (e *Engine) CalcPKItemDrop(victimLawful int32, ratePct int) PKItemDropResult {
 	fn := e.vm.GetGlobal("calc_pk_item_drop")
 	if fn == lua.LNil {
 		return PKItemDropResult{}
@@ -1139,8 +1101,9 @@ func (e *Engine) CalcPKItemDrop(victimLawful int32) PKItemDropResult {
 
 	t := e.vm.NewTable()
 	t.RawSetString("victim_lawful", lua.LNumber(victimLawful))
+	t.RawSetString("rate_pct", lua.LNumber(ratePct))
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
@@ -1176,7 +1139,7 @@ func (e *Engine) GetPKTimers() PKTimers {
 		return PKTimers{PinkNameTicks: 900, WantedTicks: 432000}
 	}
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
@@ -1212,7 +1175,7 @@ func (e *Engine) GetPKThresholds() PKThresholds {
 		return PKThresholds{Warning: 5, Punish: 10}
 	}
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
@@ -1262,7 +1225,7 @@ func (e *Engine) CalcDurabilityDamage(ctx DurabilityContext) DurabilityResult {
 	t.RawSetString("bless", lua.LNumber(ctx.Bless))
 	t.RawSetString("current_durability", lua.LNumber(ctx.CurrentDurability))
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
@@ -1328,7 +1291,7 @@ func (e *Engine) CalcHPRegenAmount(ctx HPRegenContext) int {
 		t.RawSetString("has_additional_fire", lua.LFalse)
 	}
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
@@ -1387,7 +1350,7 @@ func (e *Engine) CalcMPRegenAmount(ctx MPRegenContext) int {
 		t.RawSetString("has_blue_potion", lua.LFalse)
 	}
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
@@ -1432,7 +1395,7 @@ func (e *Engine) callIntFunc(name string, args ...int) int {
 		lArgs[i] = lua.LNumber(a)
 	}
 
-	if err := e.vm.CallByParam(lua.P{
+	if err := e.call(lua.P{
 		Fn:      fn,
 		NRet:    1,
 		Protect: true,
@@ -1446,6 +1409,63 @@ func (e *Engine) callIntFunc(name string, args ...int) int {
 	return int(lua.LVAsNumber(result))
 }
 
+// WorldQuery is the read-only world view exposed to AI scripts.
+// Implementations must not modify game state.
+type WorldQuery interface {
+	// CountNearbyAllies counts other living NPCs of the same template within
+	// rng tiles of the NPC with object ID npcID.
+	CountNearbyAllies(npcID int32, rng int) int
+	// CountNearbyPlayers counts living, visible players within rng tiles.
+	CountNearbyPlayers(npcID int32, rng int) int
+}
+
+// MaxQueryRange caps the range scripts may pass to world queries (tiles).
+const MaxQueryRange = 20
+
+// SetWorldQuery registers the Lua `world` table:
+//
+//	world.countNearbyAllies(id, range)
+//	world.countNearbyPlayers(id, range)
+//
+// id is the NPC object ID (ctx.id in npc_ai). range is clamped to
+// 0..MaxQueryRange. Unknown NPCs count as 0.
+func (e *Engine) SetWorldQuery(q WorldQuery) {
+	count := func(fn func(int32, int) int) lua.LGFunction {
+		return func(L *lua.LState) int {
+			id := int32(L.CheckNumber(1))
+			rng := int(L.OptNumber(2, MaxQueryRange))
+			rng = max(0, min(rng, MaxQueryRange))
+			L.Push(lua.LNumber(fn(id, rng)))
+			return 1
+		}
+	}
+	tbl := e.vm.NewTable()
+	tbl.RawSetString("countNearbyAllies", e.vm.NewFunction(count(q.CountNearbyAllies)))
+	tbl.RawSetString("countNearbyPlayers", e.vm.NewFunction(count(q.CountNearbyPlayers)))
+	e.vm.SetGlobal("world", tbl)
+}
+
+// SetCallTimeout limits how long a single script call may run. A call that
+// exceeds it (e.g. an infinite loop) is aborted with an error, which the
+// caller logs before returning its safe default. 0 disables the limit.
+func (e *Engine) SetCallTimeout(d time.Duration) {
+	e.timeout = d
+}
+
+// call runs a protected Lua call under the per-call timeout. The VM checks
+// the context between instructions, so a runaway script cannot stall the
+// game loop.
+func (e *Engine) call(p lua.P, args ...lua.LValue) error {
+	if e.timeout <= 0 {
+		return e.vm.CallByParam(p, args...)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+	e.vm.SetContext(ctx)
+	defer e.vm.RemoveContext()
+	return e.vm.CallByParam(p, args...)
+}
+
 // Close shuts down the Lua VM.
 func (e *Engine) Close() {
 	e.vm.Close()
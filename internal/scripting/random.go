@@ -0,0 +1,48 @@
+package scripting
+
+import (
+	"github.com/l1jgo/server/internal/world"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// randomFloatSteps is the resolution of math.random() called without
+// arguments.
+const randomFloatSteps = 1 << 30
+
+// installRandom routes Lua's math.random through world.RandInt so scripted
+// rolls (combat, enchant, drops, level-up) follow the game-wide seed set
+// from [server] rand_seed. math.randomseed becomes a no-op: reseeding from a
+// script would break reproducibility for every other caller.
+func installRandom(vm *lua.LState) {
+	mathTbl, ok := vm.GetGlobal("math").(*lua.LTable)
+	if !ok {
+		return
+	}
+	mathTbl.RawSetString("random", vm.NewFunction(luaRandom))
+	mathTbl.RawSetString("randomseed", vm.NewFunction(func(L *lua.LState) int {
+		L.CheckNumber(1)
+		return 0
+	}))
+}
+
+// luaRandom mirrors Lua 5.1 math.random: no arguments → float in [0,1),
+// (m) → integer in [1,m], (m, n) → integer in [m,n].
+func luaRandom(L *lua.LState) int {
+	switch L.GetTop() {
+	case 0:
+		L.Push(lua.LNumber(float64(world.RandInt(randomFloatSteps)) / randomFloatSteps))
+	case 1:
+		n := L.CheckInt(1)
+		if n < 1 {
+			L.ArgError(1, "interval is empty")
+		}
+		L.Push(lua.LNumber(world.RandInt(n) + 1))
+	default:
+		lo, hi := L.CheckInt(1), L.CheckInt(2)
+		if lo > hi {
+			L.ArgError(2, "interval is empty")
+		}
+		L.Push(lua.LNumber(world.RandInt(hi-lo+1) + lo))
+	}
+	return 1
+}
@@ -0,0 +1,102 @@
+package scripting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/l1jgo/server/internal/world"
+	lua "github.com/yuin/gopher-lua"
+	"go.uber.org/zap"
+)
+
+func newTestEngine(t *testing.T) *Engine {
+	t.Helper()
+	e, err := NewEngine("../../scripts", zap.NewNop())
+	if err != nil {
+		t.Fatalf("load scripts: %v", err)
+	}
+	t.Cleanup(e.Close)
+	return e
+}
+
+// rollSequence 以固定種子執行一串戰鬥與強化擲骰。
+func rollSequence(t *testing.T, seed int64) []any {
+	t.Helper()
+	world.SetRand(world.NewRand(seed))
+	e := newTestEngine(t)
+	var out []any
+	for i := 0; i < 50; i++ {
+		out = append(out, e.CalcMeleeAttack(CombatContext{
+			AttackerLevel: 30, AttackerSTR: 18, AttackerDEX: 15, AttackerWeapon: 12,
+			TargetAC: 0, TargetLevel: 30, TargetClassType: -1,
+		}))
+		out = append(out, e.CalcEnchant(EnchantContext{
+			EnchantLvl: 7, SafeEnchant: 6, Category: 1,
+			WeaponChance: 1, ArmorChance: 1, MaxEnchant: 15,
+		}))
+	}
+	return out
+}
+
+func TestScriptRollsFollowGameSeed(t *testing.T) {
+	t.Cleanup(func() { world.SetRand(world.NewRand(time.Now().UnixNano())) })
+
+	a := rollSequence(t, 42)
+	b := rollSequence(t, 42)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("roll %d differs with the same seed: %+v vs %+v", i, a[i], b[i])
+		}
+	}
+	c := rollSequence(t, 43)
+	same := true
+	for i := range a {
+		if a[i] != c[i] {
+			same = false
+			break
+		}
+	}
+	if same {
+		t.Fatal("different seeds produced identical roll sequences")
+	}
+}
+
+func TestLuaRandomRanges(t *testing.T) {
+	t.Cleanup(func() { world.SetRand(world.NewRand(time.Now().UnixNano())) })
+	world.SetRand(world.NewRand(1))
+	e := newTestEngine(t)
+
+	check := func(expr string, lo, hi float64) {
+		t.Helper()
+		for i := 0; i < 200; i++ {
+			if err := e.vm.DoString("__r = " + expr); err != nil {
+				t.Fatalf("%s: %v", expr, err)
+			}
+			v := float64(e.vm.GetGlobal("__r").(lua.LNumber))
+			if v < lo || v > hi {
+				t.Fatalf("%s = %v, want in [%v,%v]", expr, v, lo, hi)
+			}
+		}
+	}
+	check("math.random()", 0, 0.9999999999)
+	check("math.random(6)", 1, 6)
+	check("math.random(-3, 3)", -3, 3)
+	check("math.random(5, 5)", 5, 5)
+
+	if err := e.vm.DoString("math.random(0)"); err == nil {
+		t.Fatal("math.random(0) should raise 'interval is empty'")
+	}
+	if err := e.vm.DoString("math.random(3, 1)"); err == nil {
+		t.Fatal("math.random(3, 1) should raise 'interval is empty'")
+	}
+	// 腳本重新設定種子不影響遊戲亂數
+	world.SetRand(world.NewRand(7))
+	want := world.RandInt(1 << 20)
+	world.SetRand(world.NewRand(7))
+	if err := e.vm.DoString("math.randomseed(123)"); err != nil {
+		t.Fatal(err)
+	}
+	if got := world.RandInt(1 << 20); got != want {
+		t.Fatalf("math.randomseed changed the game RNG: %d != %d", got, want)
+	}
+}
@@ -0,0 +1,49 @@
+package scripting
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRunawayScriptIsAborted(t *testing.T) {
+	e := newTestEngine(t)
+	e.SetCallTimeout(50 * time.Millisecond)
+	if err := e.vm.DoString(`
+		function npc_ai(ctx) while true do end end
+		function calc_npc_melee(ctx) local n = 0 while true do n = n + 1 end end
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if cmds := e.RunNpcAI(AIContext{ID: 1, HP: 10, MaxHP: 10}); len(cmds) != 0 {
+		t.Errorf("runaway npc_ai returned %d commands, want none", len(cmds))
+	}
+	if res := e.CalcNpcMelee(CombatContext{AttackerLevel: 10, TargetAC: 10}); res.IsHit || res.Damage != 0 {
+		t.Errorf("runaway calc_npc_melee = %+v, want a zero-damage miss", res)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("two runaway calls took %v with a 50ms limit", elapsed)
+	}
+
+	// VM 仍可正常服務後續呼叫
+	if r := e.CalcEnchant(EnchantContext{ScrollBless: 2, EnchantLvl: 0, MaxEnchant: 12}); r.Result != "minus" {
+		t.Errorf("call after timeout = %+v, want minus", r)
+	}
+}
+
+func TestScriptErrorReturnsSafeDefaults(t *testing.T) {
+	e := newTestEngine(t)
+	if err := e.vm.DoString(`
+		function npc_ai(ctx) error("boom") end
+		function calc_skill_damage(ctx) error("boom") end
+	`); err != nil {
+		t.Fatal(err)
+	}
+	if cmds := e.RunNpcAI(AIContext{ID: 1, HP: 10, MaxHP: 10}); len(cmds) != 0 {
+		t.Errorf("failing npc_ai returned %d commands", len(cmds))
+	}
+	if res := e.CalcSkillDamage(SkillDamageContext{SkillID: 4, DamageValue: 10}); res.Damage != 0 {
+		t.Errorf("failing calc_skill_damage = %+v, want zero damage", res)
+	}
+}
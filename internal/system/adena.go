@@ -0,0 +1,132 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/persist"
+	"github.com/l1jgo/server/internal/world"
+	"go.uber.org/zap"
+)
+
+// adenaCarryLimit 回傳身上金幣上限（adena_carry_max，不超過背包堆疊上限）。
+func adenaCarryLimit(deps *handler.Deps) int64 {
+	limit := int64(deps.Config.Gameplay.AdenaCarryMax)
+	if limit <= 0 || limit > world.MaxStackCount {
+		limit = world.MaxStackCount
+	}
+	return limit
+}
+
+// giveAdena 將金幣加入玩家背包並發送更新封包。超出身上上限的部分存入個人倉庫並通知玩家。
+// 回傳實際放入背包的數量。呼叫端負責背包空間檢查。
+func giveAdena(deps *handler.Deps, player *world.PlayerInfo, amount int64) int64 {
+	if amount <= 0 {
+		return 0
+	}
+	adena := player.Inv.FindByItemID(world.AdenaItemID)
+	var have int64
+	if adena != nil {
+		have = int64(adena.Count)
+	}
+	carry := amount
+	if room := adenaCarryLimit(deps) - have; carry > room {
+		carry = max(room, 0)
+	}
+
+	if carry > 0 {
+		name, gfx := "金幣", int32(318)
+		if info := deps.Items.Get(world.AdenaItemID); info != nil {
+			name, gfx = info.Name, info.InvGfx
+		}
+		item := player.Inv.AddItem(world.AdenaItemID, int32(carry), name, gfx, 0, true, 1)
+		if adena != nil {
+			handler.SendItemCountUpdate(player.Session, item)
+		} else {
+			handler.SendAddItem(player.Session, item)
+		}
+	}
+	if overflow := amount - carry; overflow > 0 {
+		bankAdena(deps, player, overflow)
+	}
+	return carry
+}
+
+// 溢出金幣存入倉庫的重試設定：存入為單一交易，失敗即整筆未寫入，重試不會重複入帳。
+// 第 n 次重試在 bankAdenaBackoff×2^(n-1) 後重新排入寫入佇列。
+const (
+	bankAdenaAttempts = 4
+	bankAdenaBackoff  = time.Second
+)
+
+// bankAdena 將超出身上上限的金幣存入帳號個人倉庫（填滿既有金幣堆疊，其餘另開新堆疊，不會遺失）。
+// 寫入交給背景寫入佇列，不阻塞遊戲迴圈；寫入失敗時延後重新排入佇列，
+// 用盡次數或佇列關閉時才記錄帳號與數量供補發。
+func bankAdena(deps *handler.Deps, player *world.PlayerInfo, amount int64) {
+	sess := player.Session
+	item := persist.WarehouseItem{
+		AccountID:   player.AccountID,
+		AccountName: sess.AccountName,
+		CharName:    player.Name,
+		WhType:      handler.WhTypePersonal,
+		ItemID:      world.AdenaItemID,
+		Bless:       1,
+		Identified:  true,
+	}
+	name := player.Name
+	deposit := func(ctx context.Context) error {
+		return deps.WarehouseRepo.DepositAccountStack(ctx, item, amount, world.MaxStackCount)
+	}
+	queueRetry(deps, "bank_adena", bankAdenaAttempts, bankAdenaBackoff, deposit, func(attempts int, err error) {
+		deps.Log.Error("溢出金幣存入倉庫失敗（需補發）",
+			zap.String("player", name), zap.Int32("account_id", item.AccountID),
+			zap.Int64("amount", amount), zap.Int("attempts", attempts), zap.Error(err))
+	})
+	// 倉庫快取已過期，下次開啟時重新載入
+	if player.WarehouseType == handler.WhTypePersonal {
+		player.WarehouseItems = nil
+	}
+	handler.SendGlobalChat(sess, 9, fmt.Sprintf("金幣已達攜帶上限，%d 金幣已存入個人倉庫", amount))
+	deps.Log.Info(fmt.Sprintf("溢出金幣存入倉庫  角色=%s  數量=%d", player.Name, amount))
+}
+
+// queueRetry 經由寫入佇列執行 fn。失敗時不在寫入 worker 上等待，而是以 time.AfterFunc
+// 在 backoff×2^(n-1) 後重新排入佇列，其他寫入照常進行。
+// 用盡 attempts 次，或寫入的 ctx 已取消（佇列已關閉）時停止重試，並以嘗試次數與最後錯誤呼叫 giveUp。
+func queueRetry(deps *handler.Deps, what string, attempts int, backoff time.Duration,
+	fn func(ctx context.Context) error, giveUp func(attempts int, err error)) {
+	var try func(n int)
+	try = func(n int) {
+		handler.QueueWrite(deps, what, func(ctx context.Context) error {
+			err := fn(ctx)
+			if err == nil {
+				return nil
+			}
+			if n >= attempts || errors.Is(ctx.Err(), context.Canceled) {
+				giveUp(n, err)
+				return err
+			}
+			deps.Log.Warn("佇列寫入失敗，稍後重試",
+				zap.String("what", what), zap.Int("attempt", n), zap.Error(err))
+			time.AfterFunc(backoff<<(n-1), func() { try(n + 1) })
+			return nil
+		})
+	}
+	try(1)
+}
+
+// stackRoom 回傳背包中 itemID 還能再疊加的數量：金幣依身上上限（adena_carry_max），
+// 其他可堆疊物品依堆疊上限（world.MaxStackCount）。背包內沒有該物品時即為上限本身。
+func stackRoom(deps *handler.Deps, player *world.PlayerInfo, itemID int32) int64 {
+	limit := int64(world.MaxStackCount)
+	if itemID == world.AdenaItemID {
+		limit = adenaCarryLimit(deps)
+	}
+	if it := player.Inv.FindByItemID(itemID); it != nil {
+		return max(limit-int64(it.Count), 0)
+	}
+	return limit
+}
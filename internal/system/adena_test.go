@@ -0,0 +1,131 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/l1jgo/server/internal/config"
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/persist"
+	"github.com/l1jgo/server/internal/world"
+	"go.uber.org/zap"
+)
+
+func TestStackRoomRespectsAdenaCarryMax(t *testing.T) {
+	deps := &handler.Deps{Config: &config.Config{}}
+	deps.Config.Gameplay.AdenaCarryMax = 1_000_000
+	p := &world.PlayerInfo{Inv: world.NewInventory()}
+
+	if got := stackRoom(deps, p, world.AdenaItemID); got != 1_000_000 {
+		t.Fatalf("empty bag adena room = %d, want 1000000", got)
+	}
+	p.Inv.AddItem(world.AdenaItemID, 999_000, "金幣", 0, 0, true, 1)
+	if got := stackRoom(deps, p, world.AdenaItemID); got != 1_000 {
+		t.Fatalf("adena room = %d, want 1000", got)
+	}
+
+	// 其他可堆疊物品依堆疊上限
+	p.Inv.AddItem(40014, world.MaxStackCount-5, "藥水", 0, 0, true, 1)
+	if got := stackRoom(deps, p, 40014); got != 5 {
+		t.Fatalf("potion room = %d, want 5", got)
+	}
+}
+
+func TestAdenaCarryLimitDefaultsToStackCap(t *testing.T) {
+	deps := &handler.Deps{Config: &config.Config{}}
+	if got := adenaCarryLimit(deps); got != world.MaxStackCount {
+		t.Fatalf("unset limit = %d, want %d", got, world.MaxStackCount)
+	}
+	deps.Config.Gameplay.AdenaCarryMax = 3_000_000_000
+	if got := adenaCarryLimit(deps); got != world.MaxStackCount {
+		t.Fatalf("limit above stack cap = %d, want %d", got, world.MaxStackCount)
+	}
+}
+
+// 失敗的寫入延後重新排入佇列：等待期間其他寫入照常執行，之後重試成功。
+func TestQueueRetryDoesNotHoldTheWorker(t *testing.T) {
+	deps := &handler.Deps{Writes: persist.NewWriteQueue(4, zap.NewNop()), Log: zap.NewNop()}
+	defer deps.Writes.Close()
+
+	attempts := make(chan int, 4)
+	calls := 0
+	fn := func(context.Context) error {
+		calls++
+		attempts <- calls
+		if calls == 1 {
+			return errors.New("db down")
+		}
+		return nil
+	}
+	queueRetry(deps, "test", 4, 50*time.Millisecond, fn, func(n int, err error) {
+		t.Errorf("gave up after %d attempts: %v", n, err)
+	})
+	<-attempts
+
+	other := make(chan struct{})
+	deps.Writes.Submit("other", func(context.Context) error {
+		close(other)
+		return nil
+	})
+	select {
+	case <-other:
+	case n := <-attempts:
+		t.Fatalf("attempt %d ran before the write queued behind the failure", n)
+	case <-time.After(time.Second):
+		t.Fatal("worker stalled during the retry backoff")
+	}
+	select {
+	case <-attempts:
+	case <-time.After(time.Second):
+		t.Fatal("failed write was never retried")
+	}
+}
+
+func TestQueueRetryGivesUp(t *testing.T) {
+	deps := &handler.Deps{Writes: persist.NewWriteQueue(4, zap.NewNop()), Log: zap.NewNop()}
+	defer deps.Writes.Close()
+
+	gaveUp := make(chan string, 1)
+	calls := 0
+	queueRetry(deps, "test", 3, time.Millisecond, func(context.Context) error {
+		calls++
+		return fmt.Errorf("attempt %d", calls)
+	}, func(n int, err error) {
+		gaveUp <- fmt.Sprintf("%d %v", n, err)
+	})
+	select {
+	case got := <-gaveUp:
+		if got != "3 attempt 3" {
+			t.Fatalf("gave up with %q, want the 3rd attempt's error", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("never gave up")
+	}
+}
+
+// 佇列關閉後（ctx 已取消）不再重試。
+func TestQueueRetryStopsWhenQueueCloses(t *testing.T) {
+	deps := &handler.Deps{Writes: persist.NewWriteQueue(4, zap.NewNop()), Log: zap.NewNop()}
+
+	gaveUp := make(chan int, 1)
+	queueRetry(deps, "test", 10, 20*time.Millisecond, func(ctx context.Context) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return errors.New("db down")
+	}, func(n int, err error) {
+		gaveUp <- n
+	})
+	deps.Writes.Close()
+	select {
+	case n := <-gaveUp:
+		if n != 2 {
+			t.Fatalf("gave up after %d attempts, want 2 (the first retry after Close)", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("kept retrying after the queue closed")
+	}
+}
@@ -0,0 +1,43 @@
+package system
+
+import (
+	"fmt"
+
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// consumeAttackBoost 玩家開啟強化攻擊道具時，每次攻擊（不論命中）消耗 1 個。
+// hit 為 true 時回傳額外傷害並在目標播放特效；道具用完時自動關閉並通知，攻擊照常進行。
+// 於 Lua 公式之後、clampDamage 之前呼叫。
+func consumeAttackBoost(deps *handler.Deps, player *world.PlayerInfo, targetID int32, nearby []*world.PlayerInfo, hit bool) int32 {
+	if player.AttackBoostItem == 0 {
+		return 0
+	}
+	info := deps.Items.Get(player.AttackBoostItem)
+	item := player.Inv.FindByItemID(player.AttackBoostItem)
+	if info == nil || info.AttackBoost <= 0 || item == nil || item.Count <= 0 {
+		name := "強化攻擊道具"
+		if info != nil {
+			name = info.Name
+		}
+		player.AttackBoostItem = 0
+		handler.SendSystemMessage(player.Session, fmt.Sprintf("%s已用完，已自動關閉。", name))
+		return 0
+	}
+
+	if player.Inv.RemoveItem(item.ObjectID, 1) {
+		handler.SendRemoveInventoryItem(player.Session, item.ObjectID)
+	} else {
+		handler.SendItemCountUpdate(player.Session, item)
+	}
+	handler.SendWeightUpdate(player.Session, player)
+
+	if !hit {
+		return 0
+	}
+	if info.AttackBoostGfx > 0 {
+		handler.BroadcastToPlayers(nearby, handler.BuildSkillEffect(targetID, info.AttackBoostGfx))
+	}
+	return info.AttackBoost
+}
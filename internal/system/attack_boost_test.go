@@ -0,0 +1,37 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestConsumeAttackBoost(t *testing.T) {
+	deps := newTestDeps(t)
+	deps.Items = newTestItems(t, `  - {item_id: 49000, name: 強化石, stackable: true, attack_boost: 5, attack_boost_gfx: 0}
+`)
+	p := &world.PlayerInfo{Session: newTestSession(t, 1), Inv: world.NewInventory()}
+	p.Inv.AddItem(49000, 2, "強化石", 0, 0, true, 1)
+
+	// 未開啟：不消耗、不加傷
+	if bonus := consumeAttackBoost(deps, p, 1, nil, true); bonus != 0 || p.Inv.FindByItemID(49000).Count != 2 {
+		t.Fatal("booster used while toggled off")
+	}
+
+	p.AttackBoostItem = 49000
+	// 命中：消耗 1 個並加傷
+	if bonus := consumeAttackBoost(deps, p, 1, nil, true); bonus != 5 {
+		t.Errorf("hit bonus = %d, want 5", bonus)
+	}
+	// 未命中：仍消耗，但不加傷
+	if bonus := consumeAttackBoost(deps, p, 1, nil, false); bonus != 0 {
+		t.Errorf("miss bonus = %d, want 0", bonus)
+	}
+	if p.Inv.FindByItemID(49000) != nil {
+		t.Fatal("booster stack not consumed")
+	}
+	// 用完：自動關閉，攻擊照常（無加傷）
+	if bonus := consumeAttackBoost(deps, p, 1, nil, true); bonus != 0 || p.AttackBoostItem != 0 {
+		t.Errorf("empty booster: bonus %d, toggle %d", bonus, p.AttackBoostItem)
+	}
+}
@@ -0,0 +1,74 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/l1jgo/server/internal/world"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestInAttackReach(t *testing.T) {
+	deps := newTestDeps(t)
+	deps.Config.AntiCheat.MeleeReachTolerance = 1
+	deps.Items = newTestItemsWithWeapons(t, `  - {item_id: 1, name: 匕首, type: dagger, range: 1}
+  - {item_id: 2, name: 長弓, type: bow, range: -1}
+  - {item_id: 3, name: 短弓, type: bow, range: 5}
+`, "")
+
+	tests := []struct {
+		name   string
+		weapon int32 // 0 = 空手
+		melee  bool
+		dist   int32
+		want   bool
+	}{
+		{"空手相鄰", 0, true, 1, true},
+		{"空手容差內", 0, true, 2, true},
+		{"空手超出", 0, true, 3, false},
+		{"近戰武器容差內", 1, true, 2, true},
+		{"近戰武器超出", 1, true, 3, false},
+		{"弓預設射程", 2, false, bowDefaultReach, true},
+		{"弓預設射程外", 2, false, bowDefaultReach + 1, false},
+		{"弓射程無容差", 3, false, 6, false},
+		{"弓射程內", 3, false, 5, true},
+	}
+	for _, tt := range tests {
+		p := &world.PlayerInfo{X: 32700, Y: 32800, Inv: world.NewInventory()}
+		if tt.weapon != 0 {
+			p.Equip.Set(world.SlotWeapon, &world.InvItem{ItemID: tt.weapon})
+		}
+		reach, _ := weaponAttack(p, deps)
+		if got := inAttackReach(deps, p, reach, tt.melee, p.X+tt.dist, p.Y-tt.dist); got != tt.want {
+			t.Errorf("%s: inAttackReach(reach %d, dist %d) = %v, want %v", tt.name, reach, tt.dist, got, tt.want)
+		}
+	}
+}
+
+func TestAttackReachViolationLogged(t *testing.T) {
+	deps := newTestDeps(t)
+	deps.Config.AntiCheat.AttackViolationLimit = 3
+	core, logs := observer.New(zapcore.WarnLevel)
+	deps.Log = zap.New(core)
+
+	p := &world.PlayerInfo{Name: "tester", X: 32700, Y: 32800}
+	for i := 0; i < 2; i++ {
+		inAttackReach(deps, p, 1, true, p.X+5, p.Y)
+	}
+	if logs.Len() != 0 {
+		t.Fatal("logged before reaching attack_violation_limit")
+	}
+	inAttackReach(deps, p, 1, true, p.X+5, p.Y)
+	if logs.Len() != 1 {
+		t.Fatalf("got %d warnings, want 1", logs.Len())
+	}
+	if p.AttackViolations != 0 {
+		t.Errorf("violation count not reset after logging: %d", p.AttackViolations)
+	}
+	// 距離內的攻擊不計違規
+	inAttackReach(deps, p, 1, true, p.X+1, p.Y)
+	if p.AttackViolations != 0 {
+		t.Errorf("in-reach attack counted as violation")
+	}
+}
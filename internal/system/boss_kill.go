@@ -0,0 +1,79 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/persist"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// onBossKilled 頭目（npc_list boss: true）被擊殺：依設定全服公告擊殺者、血盟與掉落物，
+// 該頭目首次被擊殺時另發首殺公告，並寫入 boss_kills 擊殺紀錄（供 .bossrank 排行榜）。
+func onBossKilled(npc *world.NpcInfo, killer *world.PlayerInfo, drops []world.LootEntry, deps *handler.Deps) {
+	cfg := &deps.Config.Gameplay
+	firstBlood := deps.World.MarkBossKilled(npc.NpcID)
+	var pkts [][]byte
+	if cfg.BossKillAnnounce {
+		who := killer.Name
+		if killer.ClanName != "" {
+			who = fmt.Sprintf("【%s】%s", killer.ClanName, killer.Name)
+		}
+		pkts = append(pkts, handler.BuildGreenMessage(fmt.Sprintf("\\f3%s 擊敗了頭目 %s！", who, npc.Name)))
+		if cfg.BossKillAnnounceDrops && len(drops) > 0 {
+			pkts = append(pkts, handler.BuildGreenMessage("\\f2掉落："+bossDropList(drops, deps)))
+		}
+	}
+	if firstBlood && cfg.BossFirstBlood {
+		pkts = append(pkts, handler.BuildGreenMessage(fmt.Sprintf("\\f3首殺！%s 成為第一位擊敗 %s 的勇者！", killer.Name, npc.Name)))
+	}
+	if len(pkts) > 0 {
+		deps.World.AllPlayers(func(p *world.PlayerInfo) {
+			for _, pkt := range pkts {
+				p.Session.Send(pkt)
+			}
+		})
+	}
+
+	deps.Log.Info(fmt.Sprintf("頭目被擊殺  頭目=%s  擊殺者=%s  血盟=%s  掉落=%d",
+		npc.Name, killer.Name, killer.ClanName, len(drops)))
+
+	if deps.BossKillRepo == nil {
+		return
+	}
+	rec := persist.BossKill{
+		NpcID:        npc.NpcID,
+		BossName:     npc.Name,
+		KillerCharID: killer.CharID,
+		KillerName:   killer.Name,
+		ClanName:     killer.ClanName,
+		MapID:        npc.MapID,
+	}
+	repo := deps.BossKillRepo
+	handler.QueueWrite(deps, "boss_kill", func(ctx context.Context) error {
+		return repo.Record(ctx, rec)
+	})
+	handler.RefreshBossRank(deps)
+}
+
+// bossDropList 將掉落物組成公告字串（例：+7 長劍、金幣 (5000)）。
+func bossDropList(drops []world.LootEntry, deps *handler.Deps) string {
+	names := make([]string, 0, len(drops))
+	for _, d := range drops {
+		info := deps.Items.Get(d.ItemID)
+		if info == nil {
+			continue
+		}
+		name := info.Name
+		if d.EnchantLvl > 0 {
+			name = fmt.Sprintf("+%d %s", d.EnchantLvl, name)
+		}
+		if d.Count > 1 {
+			name = fmt.Sprintf("%s (%d)", name, d.Count)
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, "、")
+}
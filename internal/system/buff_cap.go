@@ -0,0 +1,96 @@
+package system
+
+import (
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/scripting"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// skillTypeCurse 技能 Type 位元：詛咒/負面效果（data.SkillInfo.Type）。
+const skillTypeCurse = 4
+
+// ensureBuffSlot 依 [gameplay] max_active_buffs 為新 buff 騰出空間。
+// 已達上限時依 buff_overflow 移除最早的 buff 或拒絕新 buff；回傳 false 表示拒絕。
+// 替換同技能、衝突排除（eff.Exclusions）會空出的格子與控制類效果不受限制。
+func (s *SkillSystem) ensureBuffSlot(target *world.PlayerInfo, skill *data.SkillInfo, eff *scripting.BuffEffect) bool {
+	if s.fitBuff(target, skill, eff, true) {
+		return true
+	}
+	handler.SendSystemMessage(target.Session, "身上的輔助效果已達上限。")
+	return false
+}
+
+// canTakeBuff 施法前預檢（不移除任何 buff）：buff_overflow = "reject" 且目標 buff 已滿時回傳 false，
+// 讓呼叫端在扣除 MP/材料前拒絕施法。
+func (s *SkillSystem) canTakeBuff(target *world.PlayerInfo, skill *data.SkillInfo) bool {
+	if skill.BuffDuration <= 0 {
+		return true
+	}
+	eff := s.deps.Scripting.GetBuffEffect(int(skill.SkillID), int(target.Level))
+	return s.fitBuff(target, skill, eff, false)
+}
+
+// fitBuff 判斷新 buff 是否放得下；evict 為 true 時依 drop_oldest 實際移除最早的 buff。
+func (s *SkillSystem) fitBuff(target *world.PlayerInfo, skill *data.SkillInfo, eff *scripting.BuffEffect, evict bool) bool {
+	cfg := &s.deps.Config.Gameplay
+	if cfg.MaxActiveBuffs <= 0 || target.HasBuff(skill.SkillID) {
+		return true
+	}
+	if skill.Type&skillTypeCurse != 0 || (eff != nil && (eff.Paralyzed || eff.Sleeped)) ||
+		s.buffExempt(skill.SkillID, nil) {
+		return true
+	}
+
+	excluded := make(map[int32]bool)
+	if eff != nil {
+		for _, id := range eff.Exclusions {
+			excluded[int32(id)] = true
+		}
+	}
+	for {
+		var oldestID int32
+		var oldest *world.ActiveBuff
+		count := 0
+		for id, b := range target.ActiveBuffs {
+			if excluded[id] || s.buffExempt(id, b) {
+				continue
+			}
+			count++
+			if oldest == nil || b.Seq < oldest.Seq {
+				oldestID, oldest = id, b
+			}
+		}
+		if count < cfg.MaxActiveBuffs {
+			return true
+		}
+		if cfg.BuffOverflow == "reject" {
+			return false
+		}
+		if !evict {
+			return true
+		}
+		s.expireBuff(target, oldestID, oldest)
+	}
+}
+
+// buffExempt 判斷 buff 是否不佔 buff 上限：變身、伺服器自訂狀態（傳送保護、能力削弱）、
+// 不可取消狀態、麻痺/睡眠、詛咒類技能。buff 為 nil 時僅依技能判斷（新 buff 尚未建立）。
+func (s *SkillSystem) buffExempt(skillID int32, buff *world.ActiveBuff) bool {
+	switch skillID {
+	case handler.SkillShapeChange, handler.SkillStatusSpawnProtect, handler.SkillStatusStatDrain:
+		return true
+	}
+	if s.deps.Scripting.IsNonCancellable(int(skillID)) {
+		return true
+	}
+	if buff != nil && (buff.SetParalyzed || buff.SetSleeped) {
+		return true
+	}
+	if s.deps.Skills != nil {
+		if sk := s.deps.Skills.Get(skillID); sk != nil && sk.Type&skillTypeCurse != 0 {
+			return true
+		}
+	}
+	return false
+}
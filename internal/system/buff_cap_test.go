@@ -0,0 +1,54 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestCanTakeBuffRejectsWhenFull(t *testing.T) {
+	deps := newTestDeps(t)
+	deps.Config.Gameplay.MaxActiveBuffs = 2
+	deps.Config.Gameplay.BuffOverflow = "reject"
+	s := NewSkillSystem(deps)
+
+	target := &world.PlayerInfo{Level: 30, ActiveBuffs: map[int32]*world.ActiveBuff{
+		26: {SkillID: 26, Seq: 1},
+		42: {SkillID: 42, Seq: 2},
+	}}
+	haste := &data.SkillInfo{SkillID: 43, BuffDuration: 300}
+	if s.canTakeBuff(target, haste) {
+		t.Fatal("reject mode: canTakeBuff = true with a full buff bar")
+	}
+
+	// 重新施放已有的 buff 只是替換，不佔新格
+	if !s.canTakeBuff(target, &data.SkillInfo{SkillID: 26, BuffDuration: 300}) {
+		t.Fatal("refreshing an active buff should be allowed")
+	}
+	// 詛咒類不計入上限
+	if !s.canTakeBuff(target, &data.SkillInfo{SkillID: 20, BuffDuration: 30, Type: skillTypeCurse}) {
+		t.Fatal("curses should never be refused")
+	}
+	// 無持續時間的技能不建立 buff
+	if !s.canTakeBuff(target, &data.SkillInfo{SkillID: 1}) {
+		t.Fatal("instant skills should never be refused")
+	}
+}
+
+func TestCanTakeBuffDropOldestNeverEvictsOnPrecheck(t *testing.T) {
+	deps := newTestDeps(t)
+	deps.Config.Gameplay.MaxActiveBuffs = 1
+	deps.Config.Gameplay.BuffOverflow = "drop_oldest"
+	s := NewSkillSystem(deps)
+
+	target := &world.PlayerInfo{Level: 30, ActiveBuffs: map[int32]*world.ActiveBuff{
+		26: {SkillID: 26, Seq: 1},
+	}}
+	if !s.canTakeBuff(target, &data.SkillInfo{SkillID: 43, BuffDuration: 300}) {
+		t.Fatal("drop_oldest: precheck should allow the cast")
+	}
+	if !target.HasBuff(26) {
+		t.Fatal("precheck removed an active buff")
+	}
+}
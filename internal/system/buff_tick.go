@@ -22,6 +22,7 @@ func NewBuffTickSystem(ws *world.State, deps *handler.Deps) *BuffTickSystem {
 func (s *BuffTickSystem) Phase() coresys.Phase { return coresys.PhaseUpdate }
 
 func (s *BuffTickSystem) Update(_ time.Duration) {
+	now := time.Now()
 	s.world.AllPlayers(func(p *world.PlayerInfo) {
 		// Track buff count to detect expirations (dirty flag for persistence).
 		prevBuffCount := len(p.ActiveBuffs)
@@ -29,6 +30,9 @@ func (s *BuffTickSystem) Update(_ time.Duration) {
 		tickItemMagicEnchants(p, s.deps)
 		TickPlayerPoison(p, s.deps)
 		TickPlayerCurse(p, s.deps)
+		if !p.PremiumUntil.IsZero() && !now.Before(p.PremiumUntil) {
+			handler.RevokePremiumBuffs(p, s.deps) // 高級帳號到期
+		}
 		if len(p.ActiveBuffs) < prevBuffCount {
 			p.Dirty = true
 		}
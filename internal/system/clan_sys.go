@@ -682,11 +682,7 @@ func canGrantRank(myRank, targetRank int16) bool {
 
 // SetTitle 設定稱號。
 func (s *ClanSystem) SetTitle(sess *net.Session, player *world.PlayerInfo, charName, title string) {
-	// 截斷稱號（Java: 16 字元）
-	if len(title) > 48 { // ~16 CJK 字 × 3 bytes UTF-8
-		title = title[:48]
-	}
-
+	// 長度與字元已由 HandleTitle 驗證（[character] title_max_length）
 	settingSelf := charName == player.Name
 
 	if settingSelf {
@@ -715,6 +711,15 @@ func (s *ClanSystem) SetTitle(sess *net.Session, player *world.PlayerInfo, charN
 			}
 		}
 
+		// 設定費用（清除稱號不收費）
+		if cost := s.deps.Config.Character.TitleChangeCost; cost > 0 && title != "" {
+			if !handler.ConsumeAdena(player, int32(cost)) {
+				handler.SendServerMessage(sess, 189) // 金幣不足
+				return
+			}
+			handler.SendAdenaUpdate(sess, player)
+		}
+
 		// 套用稱號
 		player.Title = title
 		player.Dirty = true
@@ -9,6 +9,7 @@ import (
 	"github.com/l1jgo/server/internal/handler"
 	"github.com/l1jgo/server/internal/scripting"
 	"github.com/l1jgo/server/internal/world"
+	"go.uber.org/zap"
 )
 
 // CombatSystem 處理佇列中的攻擊請求（Phase 2）。
@@ -42,10 +43,16 @@ func (s *CombatSystem) AddExp(player *world.PlayerInfo, expGain int32) {
 
 func (s *CombatSystem) Update(_ time.Duration) {
 	for _, req := range s.requests {
-		if req.IsMelee {
-			s.processMeleeAttack(req.AttackerSessionID, req.TargetID)
-		} else {
+		// 以伺服器端裝備判定攻擊模式：持遠程武器一律走遠程流程（需相符彈藥），
+		// 避免客戶端以 C_ATTACK 用弓近戰或以 C_FAR_ATTACK 用近戰武器遠程攻擊
+		ranged := !req.IsMelee
+		if player := s.deps.World.GetBySession(req.AttackerSessionID); player != nil {
+			ranged = handler.RangedWeaponType(player, s.deps) != ""
+		}
+		if ranged {
 			s.processRangedAttack(req.AttackerSessionID, req.TargetID)
+		} else {
+			s.processMeleeAttack(req.AttackerSessionID, req.TargetID)
 		}
 	}
 	s.requests = s.requests[:0]
@@ -71,11 +78,18 @@ func (s *CombatSystem) processMeleeAttack(sessID uint64, targetID int32) *handle
 		s.deps.Skill.CancelAbsoluteBarrier(player)
 	}
 
+	// 攻擊即解除登入/傳送保護，並自動起身、結束冥想
+	handler.ClearSpawnProtect(player, s.deps)
+	handler.StandUp(player, s.deps)
+
 	// 隱身：攻擊時自動解除（Java: L1BuffUtil.cancelInvisibility）
 	if player.Invisible && s.deps.Skill != nil {
 		s.deps.Skill.CancelInvisibility(player)
 	}
 
+	// 武器攻擊距離與動作（空手 = 距離 1）
+	reach, action := weaponAttack(player, s.deps)
+
 	// 查找目標 — 可能是 NPC 或玩家
 	npc := ws.GetNpc(targetID)
 	if npc == nil || npc.Dead {
@@ -99,41 +113,30 @@ func (s *CombatSystem) processMeleeAttack(sessID uint64, targetID int32) *handle
 			return nil
 		}
 
-		player.Heading = CalcHeading(player.X, player.Y, npc.X, npc.Y)
+		player.Heading = handler.CalcHeading(player.X, player.Y, npc.X, npc.Y)
 		nearby := ws.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID)
-		for _, viewer := range nearby {
-			handler.SendAttackPacket(viewer.Session, player.CharID, npc.ID, 0, player.Heading)
-		}
+		atkData := handler.BuildActionAttackPacket(action, player.CharID, npc.ID, 0, player.Heading)
+		handler.BroadcastToPlayers(nearby, atkData)
 		return nil
 	}
 
-	// 距離檢查（切比雪夫 <= 2，近戰容差）
-	dx := player.X - npc.X
-	dy := player.Y - npc.Y
-	if dx < 0 {
-		dx = -dx
-	}
-	if dy < 0 {
-		dy = -dy
-	}
-	dist := dx
-	if dy > dist {
-		dist = dy
-	}
-	if dist > 2 {
+	// 距離檢查（切比雪夫，依武器 Range + 近戰容差）
+	if !inAttackReach(s.deps, player, reach, true, npc.X, npc.Y) {
 		return nil
 	}
 
 	// 面向目標
-	player.Heading = CalcHeading(player.X, player.Y, npc.X, npc.Y)
+	player.Heading = handler.CalcHeading(player.X, player.Y, npc.X, npc.Y)
 
 	// 從裝備武器取得傷害
 	weaponDmg := 4 // 空手傷害
+	enchant := 0
 	targetSize := npc.Size
 	if targetSize == "" {
 		targetSize = "small"
 	}
 	if wpn := player.Equip.Weapon(); wpn != nil {
+		enchant = wpn.EffectiveEnchant()
 		if info := s.deps.Items.Get(wpn.ItemID); info != nil {
 			if targetSize == "large" && info.DmgLarge > 0 {
 				weaponDmg = info.DmgLarge
@@ -151,6 +154,9 @@ func (s *CombatSystem) processMeleeAttack(sessID uint64, targetID int32) *handle
 		AttackerWeapon: weaponDmg,
 		AttackerHitMod: int(player.HitMod),
 		AttackerDmgMod: int(player.DmgMod),
+		AttackerBowHitMod: int(player.BowHitMod),
+		AttackerBowDmgMod: int(player.BowDmgMod),
+		AttackerEnchant:   enchant,
 		TargetAC:        int(npc.AC),
 		TargetLevel:     int(npc.Level),
 		TargetMR:        int(npc.MR),
@@ -162,10 +168,13 @@ func (s *CombatSystem) processMeleeAttack(sessID uint64, targetID int32) *handle
 	if !result.IsHit {
 		damage = 0
 	}
-
 	// 取附近玩家用於廣播
 	nearby := ws.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID)
 
+	// 強化攻擊道具（每次攻擊消耗，命中加傷）
+	damage += consumeAttackBoost(s.deps, player, npc.ID, nearby, damage > 0)
+	damage = clampDamage(s.deps, damage, 0, "melee")
+
 	// 武器技能觸發（命中時機率觸發額外傷害 + GFX）
 	if damage > 0 {
 		if wpn := player.Equip.Weapon(); wpn != nil {
@@ -175,9 +184,7 @@ func (s *CombatSystem) processMeleeAttack(sessID uint64, targetID int32) *handle
 	}
 
 	// 廣播攻擊動畫
-	for _, viewer := range nearby {
-		handler.SendAttackPacket(viewer.Session, player.CharID, npc.ID, damage, player.Heading)
-	}
+	handler.BroadcastToPlayers(nearby, handler.BuildActionAttackPacket(action, player.CharID, npc.ID, damage, player.Heading))
 
 	// 浮動傷害數字（GFX 12266-12315 數字 / 12316 MISS）
 	if player.AttackView {
@@ -202,14 +209,8 @@ func (s *CombatSystem) processMeleeAttack(sessID uint64, targetID int32) *handle
 		// 受傷累加仇恨（Java: L1HateList.add）
 		AddHate(npc, sessID, damage)
 
-		// 廣播 HP 條更新
-		hpRatio := int16(0)
-		if npc.MaxHP > 0 {
-			hpRatio = int16((npc.HP * 100) / npc.MaxHP)
-		}
-		for _, viewer := range nearby {
-			handler.SendHpMeter(viewer.Session, npc.ID, hpRatio)
-		}
+		// 廣播 HP 條更新（節流）
+		broadcastNpcHpMeter(npc, nearby, s.deps)
 
 		// 檢查死亡
 		if npc.HP <= 0 {
@@ -239,6 +240,10 @@ func (s *CombatSystem) processRangedAttack(sessID uint64, targetID int32) *handl
 		s.deps.Skill.CancelAbsoluteBarrier(player)
 	}
 
+	// 攻擊即解除登入/傳送保護，並自動起身、結束冥想
+	handler.ClearSpawnProtect(player, s.deps)
+	handler.StandUp(player, s.deps)
+
 	// 隱身：攻擊時自動解除
 	if player.Invisible && s.deps.Skill != nil {
 		s.deps.Skill.CancelInvisibility(player)
@@ -256,7 +261,7 @@ func (s *CombatSystem) processRangedAttack(sessID uint64, targetID int32) *handl
 
 	// 非戰鬥 NPC（商人等）：只播放攻擊動畫，不造成傷害
 	if !isAttackableNpc(npc.Impl) {
-		player.Heading = CalcHeading(player.X, player.Y, npc.X, npc.Y)
+		player.Heading = handler.CalcHeading(player.X, player.Y, npc.X, npc.Y)
 		handler.SendArrowAttackPacket(player.Session, player.CharID, npc.ID, 0, player.Heading,
 			player.X, player.Y, npc.X, npc.Y)
 		nearby := ws.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID)
@@ -270,27 +275,16 @@ func (s *CombatSystem) processRangedAttack(sessID uint64, targetID int32) *handl
 		return nil
 	}
 
-	// 距離檢查（切比雪夫 <= 10，遠程）
-	dx := player.X - npc.X
-	dy := player.Y - npc.Y
-	if dx < 0 {
-		dx = -dx
-	}
-	if dy < 0 {
-		dy = -dy
-	}
-	dist := dx
-	if dy > dist {
-		dist = dy
-	}
-	if dist > 10 {
+	// 距離檢查（切比雪夫，依武器射程）
+	reach, _ := weaponAttack(player, s.deps)
+	if !inAttackReach(s.deps, player, reach, false, npc.X, npc.Y) {
 		return nil
 	}
 
-	player.Heading = CalcHeading(player.X, player.Y, npc.X, npc.Y)
+	player.Heading = handler.CalcHeading(player.X, player.Y, npc.X, npc.Y)
 
 	// 從背包找到並消耗箭矢
-	arrow := FindArrow(player, s.deps)
+	arrow := handler.FindArrow(player, s.deps)
 	if arrow == nil {
 		handler.SendGlobalChat(player.Session, 9, "\\f3沒有箭矢。")
 		return nil
@@ -312,11 +306,13 @@ func (s *CombatSystem) processRangedAttack(sessID uint64, targetID int32) *handl
 
 	// 從裝備弓取得傷害
 	bowDmg := 1
+	enchant := 0
 	targetSize := npc.Size
 	if targetSize == "" {
 		targetSize = "small"
 	}
 	if wpn := player.Equip.Weapon(); wpn != nil {
+		enchant = wpn.EffectiveEnchant()
 		if info := s.deps.Items.Get(wpn.ItemID); info != nil {
 			if targetSize == "large" && info.DmgLarge > 0 {
 				bowDmg = info.DmgLarge
@@ -335,6 +331,7 @@ func (s *CombatSystem) processRangedAttack(sessID uint64, targetID int32) *handl
 		AttackerArrowDmg:  arrowDmg,
 		AttackerBowHitMod: int(player.BowHitMod),
 		AttackerBowDmgMod: int(player.BowDmgMod),
+		AttackerEnchant:   enchant,
 		TargetAC:          int(npc.AC),
 		TargetLevel:       int(npc.Level),
 		TargetMR:          int(npc.MR),
@@ -346,9 +343,12 @@ func (s *CombatSystem) processRangedAttack(sessID uint64, targetID int32) *handl
 	if !result.IsHit {
 		damage = 0
 	}
-
 	nearby := ws.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID)
 
+	// 強化攻擊道具（每次攻擊消耗，命中加傷）
+	damage += consumeAttackBoost(s.deps, player, npc.ID, nearby, damage > 0)
+	damage = clampDamage(s.deps, damage, 0, "ranged")
+
 	// 武器技能觸發（命中時機率觸發額外傷害 + GFX）
 	if damage > 0 {
 		if wpn := player.Equip.Weapon(); wpn != nil {
@@ -390,13 +390,7 @@ func (s *CombatSystem) processRangedAttack(sessID uint64, targetID int32) *handl
 		// 受傷累加仇恨
 		AddHate(npc, sessID, damage)
 
-		hpRatio := int16(0)
-		if npc.MaxHP > 0 {
-			hpRatio = int16((npc.HP * 100) / npc.MaxHP)
-		}
-		for _, viewer := range nearby {
-			handler.SendHpMeter(viewer.Session, npc.ID, hpRatio)
-		}
+		broadcastNpcHpMeter(npc, nearby, s.deps)
 
 		if npc.HP <= 0 {
 			return handleNpcDeath(npc, player, nearby, s.deps)
@@ -408,6 +402,7 @@ func (s *CombatSystem) processRangedAttack(sessID uint64, targetID int32) *handl
 // ==================== NPC 死亡處理 ====================
 
 // handleNpcDeath 處理 NPC 死亡：動畫、經驗、重生計時。
+// killer 為 nil 時（例如召喚獸/寵物的主人已離線）只做死亡處理，不發放經驗、善惡與掉落，回傳 nil。
 // 回傳 NpcKillResult 供 CombatSystem 發出事件。
 func handleNpcDeath(npc *world.NpcInfo, killer *world.PlayerInfo, nearby []*world.PlayerInfo, deps *handler.Deps) *handler.NpcKillResult {
 	npc.Dead = true
@@ -429,14 +424,30 @@ func handleNpcDeath(npc *world.NpcInfo, killer *world.PlayerInfo, nearby []*worl
 	// 延遲移除（Java: NPC_DELETION_TIME = 10 秒 = 50 ticks）
 	npc.DeleteTimer = 50
 
+	// 死亡分裂（npc_list split_into）
+	splitOnDeath(npc, killer, deps)
+
+	if killer == nil {
+		ClearHateList(npc)
+		if npc.RespawnDelay > 0 {
+			npc.RespawnTimer = npc.RespawnDelay * 5
+		}
+		return nil
+	}
+
+	// 擊殺歸屬（[combat] kill_credit：最後一擊／總傷害最高／首位出手）
+	killer = resolveKillCredit(npc, killer, deps)
+
 	// 守衛：無經驗、無善惡、無掉落（Java: L1GuardInstance 無獎勵邏輯）
 	expGain := int32(0)
 	if npc.Impl != "L1Guard" {
 		// 計算基礎經驗（套用伺服器經驗倍率）
 		baseExp := npc.Exp
-		if deps.Config.Rates.ExpRate > 0 {
-			baseExp = int32(float64(baseExp) * deps.Config.Rates.ExpRate)
+		if expRate := currentExpRate(deps); expRate > 0 {
+			baseExp = int32(float64(baseExp) * expRate)
 		}
+		// 地圖經驗倍率（map_list exp_multiplier：練功地圖加成、城鎮為 0）
+		baseExp = int32(float64(baseExp) * mapExpMultiplier(deps, npc.MapID))
 
 		// 按仇恨比例分配經驗（Java: CalcExp.calcExp）
 		totalHate := GetTotalHate(npc)
@@ -449,7 +460,7 @@ func handleNpcDeath(npc *world.NpcInfo, killer *world.PlayerInfo, nearby []*worl
 				}
 				share := baseExp * hate / totalHate
 				if share > 0 {
-					addExp(p, share, deps)
+					addExp(p, withRestedBonus(p, share, deps), deps)
 				}
 			}
 			expGain = baseExp
@@ -457,7 +468,7 @@ func handleNpcDeath(npc *world.NpcInfo, killer *world.PlayerInfo, nearby []*worl
 			// 單人或無仇恨列表：全部給 killer（向下相容）
 			expGain = baseExp
 			if expGain > 0 {
-				addExp(killer, expGain, deps)
+				addExp(killer, withRestedBonus(killer, expGain, deps), deps)
 			}
 		}
 
@@ -468,6 +479,7 @@ func handleNpcDeath(npc *world.NpcInfo, killer *world.PlayerInfo, nearby []*worl
 				if deps.Config.Rates.PetExpRate > 0 {
 					petExp = int32(float64(petExp) * deps.Config.Rates.PetExpRate)
 				}
+				petExp = int32(float64(petExp) * mapExpMultiplier(deps, npc.MapID))
 				if petExp > 0 && deps.PetLife != nil {
 					deps.PetLife.AddPetExp(pet, petExp)
 					handler.SendPetHpMeter(killer.Session, pet.ID, pet.HP, pet.MaxHP)
@@ -478,8 +490,18 @@ func handleNpcDeath(npc *world.NpcInfo, killer *world.PlayerInfo, nearby []*worl
 		// 善惡值只給 killer（最高仇恨者）
 		deps.PvP.AddLawfulFromNpc(killer, npc.Lawful)
 
-		// 掉落物只給 killer
-		handler.GiveDrops(killer, npc.NpcID, deps)
+		// 掉落物只給 killer（loot_corpse 模式：留在屍體內，由擊殺者優先拾取）
+		var drops []world.LootEntry
+		if deps.Config.Gameplay.LootCorpse {
+			drops = spawnLootCorpse(deps, npc, killer)
+		} else {
+			drops = handler.GiveDrops(killer, npc, deps)
+		}
+
+		// 頭目擊殺：全服公告與擊殺紀錄
+		if tmpl := deps.Npcs.Get(npc.NpcID); tmpl != nil && tmpl.Boss {
+			onBossKilled(npc, killer, drops, deps)
+		}
 	}
 
 	// 清空仇恨列表（防止殘留影響重生）
@@ -520,6 +542,14 @@ func handleNpcDeath(npc *world.NpcInfo, killer *world.PlayerInfo, nearby []*worl
 	return killResult
 }
 
+// handleCompanionKill 召喚獸/寵物擊殺 NPC：經驗、善惡與掉落歸屬主人（主人離線時僅做死亡處理）。
+// 回傳主人（可能為 nil）。
+func handleCompanionKill(npc *world.NpcInfo, ownerCharID int32, nearby []*world.PlayerInfo, deps *handler.Deps) *world.PlayerInfo {
+	master := deps.World.GetByCharID(ownerCharID)
+	handleNpcDeath(npc, master, nearby, deps)
+	return master
+}
+
 // ==================== 經驗值與升級 ====================
 
 const (
@@ -570,41 +600,73 @@ func addExp(player *world.PlayerInfo, expGain int32, deps *handler.Deps) {
 
 // ==================== 戰鬥工具函式 ====================
 
-// 方向偏移查找表（8 方向）
-var combatHeadingDX = [8]int32{0, 1, 1, 1, 0, -1, -1, -1}
-var combatHeadingDY = [8]int32{-1, -1, 0, 1, 1, 1, 0, -1}
-
-// CalcHeading 計算從 (sx,sy) 到 (tx,ty) 的朝向方向。
-func CalcHeading(sx, sy, tx, ty int32) int16 {
-	ddx := tx - sx
-	ddy := ty - sy
-	if ddx > 0 {
-		ddx = 1
-	} else if ddx < 0 {
-		ddx = -1
-	}
-	if ddy > 0 {
-		ddy = 1
-	} else if ddy < 0 {
-		ddy = -1
-	}
-	for i := int16(0); i < 8; i++ {
-		if combatHeadingDX[i] == ddx && combatHeadingDY[i] == ddy {
-			return i
+// bowDefaultReach 弓類 Range <= 0（-1 = 全畫面）時的射程。
+const bowDefaultReach = 10
+
+// weaponAttack 依裝備武器的模板 Range 與類型計算攻擊距離與攻擊動作代碼。
+// 空手：距離 1、動作 1。近戰武器 Range <= 0 視為 1；遠程武器 Range <= 0 使用預設射程。
+func weaponAttack(player *world.PlayerInfo, deps *handler.Deps) (reach int32, action byte) {
+	wpn := player.Equip.Weapon()
+	if wpn == nil {
+		return 1, 1
+	}
+	info := deps.Items.Get(wpn.ItemID)
+	if info == nil {
+		return 1, 1
+	}
+	reach = int32(info.Range)
+	if reach <= 0 {
+		reach = 1
+		if world.IsRangedWeapon(info.Type) {
+			reach = bowDefaultReach
 		}
 	}
-	return 0
+	return reach, world.WeaponAttackAction(info.Type)
 }
 
-// FindArrow 在玩家背包中找到第一支可用的箭矢。
-func FindArrow(player *world.PlayerInfo, deps *handler.Deps) *world.InvItem {
-	for _, item := range player.Inv.Items {
-		info := deps.Items.Get(item.ItemID)
-		if info != nil && info.ItemType == "arrow" && item.Count > 0 {
-			return item
-		}
+// inAttackReach 判斷目標 (tx, ty) 是否在攻擊距離內；近戰另加 [anti_cheat] melee_reach_tolerance。
+// 超出距離時累計攻擊違規並回傳 false（攻擊不生效）。
+func inAttackReach(deps *handler.Deps, player *world.PlayerInfo, reach int32, melee bool, tx, ty int32) bool {
+	if melee {
+		reach += int32(max(deps.Config.AntiCheat.MeleeReachTolerance, 0))
 	}
-	return nil
+	if chebyshevDist(player.X, player.Y, tx, ty) <= reach {
+		return true
+	}
+	noteAttackViolation(deps, player, reach, tx, ty)
+	return false
+}
+
+// noteAttackViolation 累計攻擊距離違規；10 秒內達 attack_violation_limit 次即記錄為疑似外掛。
+func noteAttackViolation(deps *handler.Deps, player *world.PlayerInfo, reach int32, tx, ty int32) {
+	limit := deps.Config.AntiCheat.AttackViolationLimit
+	if limit <= 0 {
+		return
+	}
+	now := time.Now().UnixNano()
+	if player.AttackViolations == 0 || now-player.AttackViolationTime > int64(10*time.Second) {
+		player.AttackViolations = 0
+		player.AttackViolationTime = now
+	}
+	player.AttackViolations++
+	if player.AttackViolations < limit {
+		return
+	}
+	account := ""
+	if player.Session != nil {
+		account = player.Session.AccountName
+	}
+	deps.Log.Warn("疑似攻擊距離外掛",
+		zap.String("account", account),
+		zap.String("player", player.Name),
+		zap.Int("violations", player.AttackViolations),
+		zap.Int32("reach", reach),
+		zap.Int32("dist", chebyshevDist(player.X, player.Y, tx, ty)),
+		zap.Int16("map", player.MapID),
+		zap.Int32("x", player.X),
+		zap.Int32("y", player.Y),
+	)
+	player.AttackViolations = 0
 }
 
 // isAttackableNpc 判斷 NPC 是否可被攻擊（會受到傷害）。
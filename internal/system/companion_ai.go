@@ -8,7 +8,6 @@ import (
 	"github.com/l1jgo/server/internal/handler"
 	gonet "github.com/l1jgo/server/internal/net"
 	"github.com/l1jgo/server/internal/net/packet"
-	"github.com/l1jgo/server/internal/persist"
 	"github.com/l1jgo/server/internal/world"
 )
 
@@ -236,7 +235,7 @@ func (s *CompanionAISystem) summonAttackTarget(sum *world.SummonInfo) {
 
 	// 扣血
 	targetNpc.HP -= dmg
-	heading := calcNpcHeading(sum.X, sum.Y, targetNpc.X, targetNpc.Y)
+	heading := handler.CalcHeading(sum.X, sum.Y, targetNpc.X, targetNpc.Y)
 
 	// 廣播攻擊動畫
 	nearby := ws.GetNearbyPlayersAt(sum.X, sum.Y, sum.MapID)
@@ -264,22 +263,7 @@ func (s *CompanionAISystem) summonAttackTarget(sum *world.SummonInfo) {
 	if targetNpc.HP <= 0 {
 		targetNpc.HP = 0
 		sum.AggroTarget = 0
-		if master != nil {
-			handleNpcDeath(targetNpc, master, nearby, s.deps)
-		} else {
-			// 主人離線：僅做基礎死亡處理（無經驗/掉落）
-			targetNpc.Dead = true
-			ws.NpcDied(targetNpc)
-			targetNpc.DeleteTimer = 50
-			if targetNpc.RespawnDelay > 0 {
-				targetNpc.RespawnTimer = targetNpc.RespawnDelay * 5
-			}
-			ClearHateList(targetNpc)
-			for _, viewer := range nearby {
-				handler.SendActionGfx(viewer.Session, targetNpc.ID, 8)
-				handler.SendNpcDeadPack(viewer.Session, targetNpc)
-			}
-		}
+		handleCompanionKill(targetNpc, sum.OwnerCharID, nearby, s.deps)
 	}
 }
 
@@ -426,6 +410,7 @@ func (s *CompanionAISystem) respawnOriginalNpc(f *world.FollowerInfo) {
 		GfxID:   tmpl.GfxID,
 		Name:    tmpl.Name,
 		NameID:  tmpl.NameID,
+		Title:   tmpl.Title,
 		Level:   tmpl.Level,
 		HP:      tmpl.HP,
 		MaxHP:   tmpl.HP,
@@ -439,6 +424,7 @@ func (s *CompanionAISystem) respawnOriginalNpc(f *world.FollowerInfo) {
 		Size:    tmpl.Size,
 		MR:        tmpl.MR,
 		PoisonAtk: tmpl.PoisonAtk,
+		Tameable:  tmpl.Tameable,
 		X:         f.SpawnX,
 		Y:         f.SpawnY,
 		MapID:     f.SpawnMapID,
@@ -449,7 +435,7 @@ func (s *CompanionAISystem) respawnOriginalNpc(f *world.FollowerInfo) {
 	s.world.AddNpc(npc)
 	nearby := s.world.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID)
 	for _, viewer := range nearby {
-		sendNpcPack(viewer.Session, npc)
+		handler.SendNpcPack(viewer.Session, npc)
 	}
 }
 
@@ -635,7 +621,7 @@ func (s *CompanionAISystem) petAttackTarget(pet *world.PetInfo) {
 
 	// 扣血
 	targetNpc.HP -= dmg
-	heading := calcNpcHeading(pet.X, pet.Y, targetNpc.X, targetNpc.Y)
+	heading := handler.CalcHeading(pet.X, pet.Y, targetNpc.X, targetNpc.Y)
 
 	// 廣播攻擊動畫
 	nearby := ws.GetNearbyPlayersAt(pet.X, pet.Y, pet.MapID)
@@ -688,37 +674,25 @@ func (s *CompanionAISystem) petAttackTarget(pet *world.PetInfo) {
 	}
 	pet.AttackTimer = atkCooldown
 
-	// NPC 死亡 → 統一走 handleNpcDeath（含經驗分配、掉落、善惡）+ 寵物自身經驗
+	// NPC 死亡 → 統一走 handleNpcDeath（含經驗分配、掉落、善惡，並給予主人同地圖寵物經驗）
 	if targetNpc.HP <= 0 {
 		targetNpc.HP = 0
 		pet.AggroTarget = 0
 
-		if master != nil {
-			handleNpcDeath(targetNpc, master, nearby, s.deps)
-		} else {
-			// 主人離線：僅做基礎死亡處理
-			targetNpc.Dead = true
-			ws.NpcDied(targetNpc)
-			targetNpc.DeleteTimer = 50
-			if targetNpc.RespawnDelay > 0 {
-				targetNpc.RespawnTimer = targetNpc.RespawnDelay * 5
-			}
-			ClearHateList(targetNpc)
-			for _, viewer := range nearby {
-				handler.SendActionGfx(viewer.Session, targetNpc.ID, 8)
-				handler.SendNpcDeadPack(viewer.Session, targetNpc)
-			}
-		}
+		master = handleCompanionKill(targetNpc, pet.OwnerCharID, nearby, s.deps)
 
-		// 寵物自身經驗（獨立於玩家經驗分配）
-		petExp := targetNpc.Exp
-		if s.deps.Config.Rates.PetExpRate > 0 {
-			petExp = int32(float64(petExp) * s.deps.Config.Rates.PetExpRate)
-		}
-		if petExp > 0 && s.deps.PetLife != nil {
-			s.deps.PetLife.AddPetExp(pet, petExp)
-			if master != nil {
-				sendCompanionHpMeter(master.Session, pet.ID, pet.HP, pet.MaxHP)
+		// 主人不在同地圖時 handleNpcDeath 不會給這隻寵物經驗，由此補上（避免重複給予）
+		if master == nil || master.MapID != pet.MapID {
+			petExp := targetNpc.Exp
+			if s.deps.Config.Rates.PetExpRate > 0 {
+				petExp = int32(float64(petExp) * s.deps.Config.Rates.PetExpRate)
+			}
+			petExp = int32(float64(petExp) * mapExpMultiplier(s.deps, targetNpc.MapID))
+			if petExp > 0 && s.deps.PetLife != nil {
+				s.deps.PetLife.AddPetExp(pet, petExp)
+				if master != nil {
+					sendCompanionHpMeter(master.Session, pet.ID, pet.HP, pet.MaxHP)
+				}
 			}
 		}
 	}
@@ -731,19 +705,7 @@ func (s *CompanionAISystem) savePetToDB(pet *world.PetInfo) {
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
-	s.deps.PetRepo.Save(ctx, &persist.PetRow{
-		ItemObjID: pet.ItemObjID,
-		ObjID:     pet.ID,
-		NpcID:     pet.NpcID,
-		Name:      pet.Name,
-		Level:     pet.Level,
-		HP:        pet.HP,
-		MaxHP:     pet.MaxHP,
-		MP:        pet.MP,
-		MaxMP:     pet.MaxMP,
-		Exp:       pet.Exp,
-		Lawful:    pet.Lawful,
-	})
+	s.deps.PetRepo.Save(ctx, petRowOf(pet, true))
 }
 
 // ========================================================================
@@ -795,7 +757,7 @@ func (s *CompanionAISystem) companionMoveToward(objID int32, curX, curY int32, m
 		if c.x == curX && c.y == curY {
 			continue
 		}
-		h := calcNpcHeading(curX, curY, c.x, c.y)
+		h := handler.CalcHeading(curX, curY, c.x, c.y)
 		if maps != nil && !maps.IsPassable(mapID, curX, curY, int(h)) {
 			continue
 		}
@@ -816,7 +778,7 @@ func (s *CompanionAISystem) companionMoveToward(objID int32, curX, curY int32, m
 		return
 	}
 	// All blocked — try pass-through as last resort
-	h := calcNpcHeading(curX, curY, mx, my)
+	h := handler.CalcHeading(curX, curY, mx, my)
 	if maps == nil || maps.IsPassableIgnoreOccupant(mapID, curX, curY, int(h)) {
 		oldX, oldY := curX, curY
 		updatePos(objID, mx, my, h)
@@ -0,0 +1,107 @@
+package system
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// dropRecorder 記錄 GiveDrops 的擊殺者；其餘 ItemUseManager 方法不應被呼叫。
+type dropRecorder struct {
+	handler.ItemUseManager
+	killers []*world.PlayerInfo
+}
+
+func (d *dropRecorder) GiveDrops(killer *world.PlayerInfo, npc *world.NpcInfo) []world.LootEntry {
+	d.killers = append(d.killers, killer)
+	return nil
+}
+
+// lawfulRecorder 記錄 AddLawfulFromNpc 的擊殺者。
+type lawfulRecorder struct {
+	handler.PvPManager
+	killers []*world.PlayerInfo
+}
+
+func (l *lawfulRecorder) AddLawfulFromNpc(killer *world.PlayerInfo, npcLawful int32) {
+	l.killers = append(l.killers, killer)
+}
+
+func newCompanionKillFixture(t *testing.T) (*CompanionAISystem, *handler.Deps, *dropRecorder, *lawfulRecorder) {
+	t.Helper()
+	deps := newTestDeps(t)
+	npcs, err := data.LoadNpcTable(filepath.Join("..", "..", "data", "yaml", "npc_list.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps.Npcs = npcs
+	deps.World = world.NewState()
+	drops := &dropRecorder{}
+	lawful := &lawfulRecorder{}
+	deps.ItemUse = drops
+	deps.PvP = lawful
+	return NewCompanionAISystem(deps.World, deps), deps, drops, lawful
+}
+
+func TestSummonKillCreditsOwner(t *testing.T) {
+	s, deps, drops, lawful := newCompanionKillFixture(t)
+	owner := &world.PlayerInfo{
+		SessionID: 1, Session: newTestSession(t, 1), CharID: 100, Name: "owner",
+		X: 32690, Y: 32800, MapID: 4, Level: 1, HP: 20, MaxHP: 20,
+	}
+	deps.World.AddPlayer(owner)
+	sum := &world.SummonInfo{
+		ID: world.NextNpcID(), OwnerCharID: owner.CharID, HP: 50, MaxHP: 50, AtkDmg: 100, Ranged: 1,
+		X: 32700, Y: 32800, MapID: 4,
+	}
+	deps.World.AddSummon(sum)
+	mob := &world.NpcInfo{
+		ID: world.NextNpcID(), NpcID: 45060, Impl: "L1Monster", HP: 1, MaxHP: 1, Exp: 7,
+		X: 32701, Y: 32800, MapID: 4,
+	}
+	deps.World.AddNpc(mob)
+	sum.AggroTarget = mob.ID
+
+	s.summonAttackTarget(sum)
+
+	if !mob.Dead {
+		t.Fatal("mob survived a lethal summon hit")
+	}
+	if owner.Exp != 7 {
+		t.Errorf("owner EXP = %d, want 7", owner.Exp)
+	}
+	if len(drops.killers) != 1 || drops.killers[0] != owner {
+		t.Errorf("drops went to %v, want the owner", drops.killers)
+	}
+	if len(lawful.killers) != 1 || lawful.killers[0] != owner {
+		t.Errorf("lawful went to %v, want the owner", lawful.killers)
+	}
+}
+
+func TestSummonKillWithOwnerOffline(t *testing.T) {
+	s, deps, drops, _ := newCompanionKillFixture(t)
+	sum := &world.SummonInfo{
+		ID: world.NextNpcID(), OwnerCharID: 100, HP: 50, MaxHP: 50, AtkDmg: 100, Ranged: 1,
+		X: 32700, Y: 32800, MapID: 4,
+	}
+	deps.World.AddSummon(sum)
+	mob := &world.NpcInfo{
+		ID: world.NextNpcID(), NpcID: 45060, Impl: "L1Monster", HP: 1, MaxHP: 1, Exp: 7,
+		X: 32701, Y: 32800, MapID: 4, RespawnDelay: 10,
+	}
+	deps.World.AddNpc(mob)
+	sum.AggroTarget = mob.ID
+
+	s.summonAttackTarget(sum)
+
+	// 主人離線：完整死亡處理（屍體、重生計時），但無任何獎勵
+	if !mob.Dead || mob.DeleteTimer == 0 || mob.RespawnTimer != 50 {
+		t.Fatalf("dead %v, delete timer %d, respawn timer %d", mob.Dead, mob.DeleteTimer, mob.RespawnTimer)
+	}
+	if len(drops.killers) != 0 {
+		t.Error("drops handed out with the owner offline")
+	}
+}
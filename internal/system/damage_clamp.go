@@ -0,0 +1,47 @@
+package system
+
+import (
+	"math"
+
+	"github.com/l1jgo/server/internal/handler"
+	"go.uber.org/zap"
+)
+
+// scalePvPDamage 依 [combat] pvp_damage_rate 調整玩家對玩家的傷害（僅 PvP 路徑呼叫，
+// 在 Lua 公式之後、clampDamage 之前套用）。倍率大於 0 時命中至少保留 1 點傷害。
+func scalePvPDamage(deps *handler.Deps, dmg int32) int32 {
+	rate := deps.Config.Combat.PvPDamageRate
+	if dmg <= 0 || rate == 1 {
+		return dmg
+	}
+	if rate <= 0 {
+		return 0
+	}
+	return max(int32(math.Round(float64(dmg)*rate)), 1)
+}
+
+// clampDamage 將 Lua 公式算出的傷害限制在 [combat] min_damage～max_damage 之間，
+// skillCap > 0 時另以技能 max_damage 為上限。0 傷害（未命中、魔防抵抗）維持 0；
+// 負值一律歸 0，避免公式錯誤造成「負傷害補血」。發生限制時記錄日誌。
+func clampDamage(deps *handler.Deps, dmg int32, skillCap int, source string) int32 {
+	out := dmg
+	if out < 0 {
+		out = 0
+	} else if out > 0 {
+		cfg := deps.Config.Combat
+		if cfg.MinDamage > 0 && out < int32(cfg.MinDamage) {
+			out = int32(cfg.MinDamage)
+		}
+		if cfg.MaxDamage > 0 && out > int32(cfg.MaxDamage) {
+			out = int32(cfg.MaxDamage)
+		}
+		if skillCap > 0 && out > int32(skillCap) {
+			out = int32(skillCap)
+		}
+	}
+	if out != dmg {
+		deps.Log.Debug("傷害超出限制，已修正",
+			zap.String("source", source), zap.Int32("raw", dmg), zap.Int32("clamped", out))
+	}
+	return out
+}
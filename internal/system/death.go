@@ -20,17 +20,39 @@ func NewDeathSystem(deps *handler.Deps) *DeathSystem {
 
 // ==================== 玩家死亡 ====================
 
+// 死亡原因
+const (
+	deathPvE  = iota // 怪物、毒、環境等
+	deathPvP         // 被玩家擊殺
+	deathDuel        // 決鬥落敗
+)
+
 // KillPlayer implements handler.DeathManager — 處理玩家死亡。
 func (s *DeathSystem) KillPlayer(player *world.PlayerInfo) {
+	s.killPlayer(player, deathPvE)
+}
+
+// KillPlayerInPvP implements handler.DeathManager — 被玩家擊殺（PK 掉落由 PvPSystem 處理）。
+func (s *DeathSystem) KillPlayerInPvP(player *world.PlayerInfo) {
+	s.killPlayer(player, deathPvP)
+}
+
+// KillDuelLoser implements handler.DeathManager — 決鬥落敗死亡，不扣經驗。
+func (s *DeathSystem) KillDuelLoser(player *world.PlayerInfo) {
+	s.killPlayer(player, deathDuel)
+}
+
+func (s *DeathSystem) killPlayer(player *world.PlayerInfo, cause int) {
 	if player.Dead {
 		return
 	}
 
 	// 決鬥死亡：清除雙方決鬥狀態（在其他處理之前）
-	handler.ClearDuelOnDeath(player, s.deps.World)
+	handler.ClearDuelOnDeath(player, s.deps)
 
 	player.Dead = true
 	player.HP = 0
+	player.Resting = false
 
 	// 死亡玩家不再佔用格子
 	s.deps.World.VacateEntity(player.MapID, player.X, player.Y, player.CharID)
@@ -58,9 +80,19 @@ func (s *DeathSystem) KillPlayer(player *world.PlayerInfo) {
 	// 發送 HP 更新（0）
 	handler.SendHpUpdate(player.Session, player)
 
+	// 無懲罰地圖（map_list penalty: false）：不扣經驗、不掉落（Java: isEnabledDeathPenalty）
+	penalty := s.deps.MapData == nil || s.deps.MapData.DeathPenalty(player.MapID)
+
 	// Lua 經驗懲罰（scripts/core/levelup.lua）：等級經驗範圍的 5%
-	applyDeathExpPenalty(player, s.deps)
-	handler.SendExpUpdate(player.Session, player.Level, player.Exp)
+	if cause != deathDuel && penalty {
+		applyDeathExpPenalty(player, s.deps)
+		handler.SendExpUpdate(player.Session, player.Level, player.Exp)
+	}
+
+	// PvE 死亡掉落（可關閉；PK 掉落在 PvPSystem.processPKKill 處理）
+	if cause == deathPvE && penalty && s.deps.Config.Gameplay.DeathDropOnPvE {
+		dropItemsOnDeath(player, s.deps)
+	}
 
 	// 發出 PlayerDied 事件（下一 tick 可讀取）
 	if s.deps.Bus != nil {
@@ -99,8 +131,8 @@ func (s *DeathSystem) ProcessRestart(sess *net.Session, player *world.PlayerInfo
 	}
 	player.Food = int16(s.deps.Config.Gameplay.InitialFood)
 
-	// 取得重生位置（Lua: scripts/world/respawn.lua）
-	rx, ry, rmap := getBackLocation(player.MapID, s.deps)
+	// 取得重生位置（data/yaml/getback_list.yaml）
+	rx, ry, rmap := getBackLocation(player, s.deps)
 
 	// 清除舊格子碰撞
 	if s.deps.MapData != nil {
@@ -209,7 +241,7 @@ func (s *DeathSystem) ProcessRestart(sess *net.Session, player *world.PlayerInfo
 	}
 
 	// 發送天氣
-	handler.SendWeather(sess, s.deps.World.Weather)
+	handler.SendWeather(sess, s.deps.World.WeatherAt(rmap).Code)
 
 	s.deps.Log.Info(fmt.Sprintf("玩家重新開始  角色=%s  x=%d  y=%d  地圖=%d", player.Name, rx, ry, rmap))
 }
@@ -224,11 +256,93 @@ func applyDeathExpPenalty(player *world.PlayerInfo, deps *handler.Deps) {
 	}
 }
 
-// getBackLocation 透過 Lua 取得重生座標。
-func getBackLocation(mapID int16, deps *handler.Deps) (int32, int32, int16) {
-	loc := deps.Scripting.GetRespawnLocation(int(mapID))
-	if loc != nil {
-		return int32(loc.X), int32(loc.Y), int16(loc.Map)
+// getBackLocation 依回城座標表取得重生座標（data/yaml/getback_list.yaml，查無時為銀騎士村莊）。
+func getBackLocation(player *world.PlayerInfo, deps *handler.Deps) (int32, int32, int16) {
+	loc := deps.Getback.Restart(player.MapID, player.Lawful)
+	return loc.X, loc.Y, loc.MapID
+}
+
+// dropItemsOnDeath 死亡掉落：依 Lua 公式（善惡值）決定是否掉落及件數，
+// 機率乘上設定倍率，粉紅名狀態再乘上粉紅名倍率。PK 與 PvE 死亡共用。
+func dropItemsOnDeath(victim *world.PlayerInfo, deps *handler.Deps) {
+	cfg := deps.Config.Gameplay
+	if !cfg.DeathDropEnabled {
+		return
+	}
+
+	ratePct := cfg.DeathDropRatePct
+	if victim.PinkName {
+		ratePct = ratePct * cfg.DeathDropPinkNamePct / 100
+	}
+	dropResult := deps.Scripting.CalcPKItemDrop(victim.Lawful, ratePct)
+	if !dropResult.ShouldDrop || dropResult.Count <= 0 {
+		return
+	}
+
+	for i := 0; i < dropResult.Count; i++ {
+		candidates := deathDropCandidates(victim, deps)
+		if len(candidates) == 0 {
+			return
+		}
+		dropOneItemOnDeath(victim, candidates[world.RandInt(len(candidates))], deps)
+	}
+}
+
+// deathDropCandidates 列出可死亡掉落的物品：排除金幣、不可交易、不可銷毀、封印物品；
+// 穿戴中的裝備僅在 death_drop_equipped 開啟時列入。
+func deathDropCandidates(victim *world.PlayerInfo, deps *handler.Deps) []*world.InvItem {
+	var result []*world.InvItem
+	for _, item := range victim.Inv.Items {
+		if item.ItemID == world.AdenaItemID || item.Bless >= 128 {
+			continue
+		}
+		if item.Equipped && !deps.Config.Gameplay.DeathDropEquipped {
+			continue
+		}
+		info := deps.Items.Get(item.ItemID)
+		if info == nil || !info.Tradeable || info.CantDelete {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// dropOneItemOnDeath 將一個物品從死者背包掉落到屍體位置並廣播。
+func dropOneItemOnDeath(victim *world.PlayerInfo, item *world.InvItem, deps *handler.Deps) {
+	itemInfo := deps.Items.Get(item.ItemID)
+
+	// 脫裝備
+	if item.Equipped {
+		slot := deps.Equip.FindEquippedSlot(victim, item)
+		if slot != world.SlotNone {
+			deps.Equip.UnequipSlot(victim.Session, victim, slot)
+		}
+	}
+
+	dropCount := int32(1)
+	if item.Stackable && item.Count > 0 {
+		dropCount = item.Count
 	}
-	return 33084, 33391, 4
+
+	victim.Inv.RemoveItem(item.ObjectID, 0)
+	handler.SendRemoveInventoryItem(victim.Session, item.ObjectID)
+	handler.SendWeightUpdate(victim.Session, victim)
+
+	placeGroundItem(deps, &world.GroundItem{
+		ID:         item.ObjectID,
+		ItemID:     item.ItemID,
+		Count:      dropCount,
+		EnchantLvl: item.EnchantLvl,
+		Name:       groundDisplayName(itemInfo.Name, item.EnchantLvl, dropCount),
+		GrdGfx:     itemInfo.GrdGfx,
+		X:          victim.X,
+		Y:          victim.Y,
+		MapID:      victim.MapID,
+		Grade:      byte(itemInfo.Grade),
+	}, itemInfo.Name, item.Stackable)
+
+	handler.SendServerMessageStr(victim.Session, 638, itemInfo.Name)
+
+	deps.Log.Info(fmt.Sprintf("死亡掉落物品  角色=%s  道具=%s  數量=%d", victim.Name, itemInfo.Name, dropCount))
 }
@@ -12,11 +12,9 @@ package system
 // 計時器以 tick 計數驅動（5 ticks ≈ 1 秒），Phase 3（PostUpdate）。
 
 import (
-	"math/rand"
 	"time"
 
 	coresys "github.com/l1jgo/server/internal/core/system"
-	"github.com/l1jgo/server/internal/data"
 	"github.com/l1jgo/server/internal/handler"
 	"github.com/l1jgo/server/internal/net"
 	"github.com/l1jgo/server/internal/world"
@@ -161,25 +159,11 @@ func (s *DragonDoorSystem) SpawnKeeper(sess *net.Session, player *world.PlayerIn
 	}
 
 	// 計算生成座標（玩家附近 ±2）
-	x := player.X + int32(rand.Intn(5)) - 2
-	y := player.Y + int32(rand.Intn(5)) - 2
+	x := player.X + int32(world.RandInt(5)) - 2
+	y := player.Y + int32(world.RandInt(5)) - 2
 
 	// 解析動畫速度
-	atkSpeed := tmpl.AtkSpeed
-	moveSpeed := tmpl.PassiveSpeed
-	if s.deps.SprTable != nil {
-		gfx := int(tmpl.GfxID)
-		if tmpl.AtkSpeed != 0 {
-			if v := s.deps.SprTable.GetAttackSpeed(gfx, data.ActAttack); v > 0 {
-				atkSpeed = int16(v)
-			}
-		}
-		if tmpl.PassiveSpeed != 0 {
-			if v := s.deps.SprTable.GetMoveSpeed(gfx, data.ActWalk); v > 0 {
-				moveSpeed = int16(v)
-			}
-		}
-	}
+	speeds := s.deps.SprTable.ResolveNpcSpeeds(tmpl)
 
 	// 走路型用 "L1DragonKeeper"（NpcAISystem 會跳過），戰鬥型用 "L1Monster"（正常 AI）
 	impl := "L1DragonKeeper"
@@ -188,38 +172,41 @@ func (s *DragonDoorSystem) SpawnKeeper(sess *net.Session, player *world.PlayerIn
 	}
 
 	npc := &world.NpcInfo{
-		ID:           world.NextNpcID(),
-		NpcID:        tmpl.NpcID,
-		Impl:         impl,
-		GfxID:        tmpl.GfxID,
-		Name:         tmpl.Name,
-		NameID:       tmpl.NameID,
-		Level:        tmpl.Level,
-		X:            x,
-		Y:            y,
-		MapID:        player.MapID,
-		Heading:      int16(rand.Intn(8)),
-		HP:           tmpl.HP,
-		MaxHP:        tmpl.HP,
-		MP:           tmpl.MP,
-		MaxMP:        tmpl.MP,
-		AC:           tmpl.AC,
-		STR:          tmpl.STR,
-		DEX:          tmpl.DEX,
-		Exp:          tmpl.Exp,
-		Lawful:       tmpl.Lawful,
-		Size:         tmpl.Size,
-		MR:           tmpl.MR,
-		Undead:       tmpl.Undead,
-		Agro:         false, // 門衛不主動攻擊
-		AtkDmg:       int32(tmpl.Level) + int32(tmpl.STR)/3,
-		Ranged:       tmpl.Ranged,
-		AtkSpeed:     atkSpeed,
-		MoveSpeed:    moveSpeed,
-		SpawnX:       x,
-		SpawnY:       y,
-		SpawnMapID:   player.MapID,
-		RespawnDelay: 0, // 動態生成：不重生
+		ID:             world.NextNpcID(),
+		NpcID:          tmpl.NpcID,
+		Impl:           impl,
+		GfxID:          tmpl.GfxID,
+		Name:           tmpl.Name,
+		NameID:         tmpl.NameID,
+		Title:          tmpl.Title,
+		Level:          tmpl.Level,
+		X:              x,
+		Y:              y,
+		MapID:          player.MapID,
+		Heading:        int16(world.RandInt(8)),
+		HP:             tmpl.HP,
+		MaxHP:          tmpl.HP,
+		MP:             tmpl.MP,
+		MaxMP:          tmpl.MP,
+		AC:             tmpl.AC,
+		STR:            tmpl.STR,
+		DEX:            tmpl.DEX,
+		Exp:            tmpl.Exp,
+		Lawful:         tmpl.Lawful,
+		Size:           tmpl.Size,
+		MR:             tmpl.MR,
+		Undead:         tmpl.Undead,
+		Agro:           false, // 門衛不主動攻擊
+		AtkDmg:         int32(tmpl.Level) + int32(tmpl.STR)/3,
+		Ranged:         tmpl.Ranged,
+		AtkSpeed:       speeds.Atk,
+		MoveSpeed:      speeds.Move,
+		RunSpeed:       speeds.Run,
+		RangedAtkSpeed: speeds.RangedAtk,
+		SpawnX:         x,
+		SpawnY:         y,
+		SpawnMapID:     player.MapID,
+		RespawnDelay:   0, // 動態生成：不重生
 	}
 	s.ws.AddNpc(npc)
 
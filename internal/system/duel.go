@@ -0,0 +1,55 @@
+package system
+
+import (
+	"time"
+
+	coresys "github.com/l1jgo/server/internal/core/system"
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// DuelSystem 定期檢查進行中的決鬥，雙方距離超過 [gameplay] duel_max_distance
+// 或不在同一地圖時中止決鬥。Phase 3 (PostUpdate)。
+type DuelSystem struct {
+	world     *world.State
+	deps      *handler.Deps
+	tickCount int
+	interval  int // 每 N tick 檢查一次
+}
+
+func NewDuelSystem(ws *world.State, deps *handler.Deps, intervalTicks int) *DuelSystem {
+	return &DuelSystem{
+		world:    ws,
+		deps:     deps,
+		interval: intervalTicks,
+	}
+}
+
+func (s *DuelSystem) Phase() coresys.Phase { return coresys.PhasePostUpdate }
+
+func (s *DuelSystem) Update(_ time.Duration) {
+	s.tickCount++
+	if s.tickCount < s.interval {
+		return
+	}
+	s.tickCount = 0
+
+	maxDist := int32(s.deps.Config.Gameplay.DuelMaxDistance)
+	var ended [][2]*world.PlayerInfo
+	s.world.AllPlayers(func(p *world.PlayerInfo) {
+		if !p.DuelActive {
+			return
+		}
+		partner := s.world.GetByCharID(p.FightId)
+		// 每組只由角色 ID 較小的一方檢查
+		if partner == nil || !handler.IsDueling(p, partner) || p.CharID > partner.CharID {
+			return
+		}
+		if p.MapID != partner.MapID || (maxDist > 0 && chebyshev32(p.X, p.Y, partner.X, partner.Y) > maxDist) {
+			ended = append(ended, [2]*world.PlayerInfo{p, partner})
+		}
+	})
+	for _, pair := range ended {
+		handler.EndDuel(pair[0], pair[1], handler.DuelEndDistance, s.deps)
+	}
+}
@@ -0,0 +1,77 @@
+package system
+
+import (
+	"fmt"
+
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/net"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// 能量感測（技能 23）弱點屬性特效（Java: WEAK_ELEMENTAL → S_SkillSound）
+const (
+	gfxWeakEarth int32 = 2169
+	gfxWeakFire  int32 = 2167
+	gfxWeakWater int32 = 2166
+	gfxWeakWind  int32 = 2168
+)
+
+// senseInfo 能量感測讀取的目標資料（玩家與 NPC 共用）。
+type senseInfo struct {
+	id                       int32
+	name                     string
+	level                    int16
+	hp, maxHP, mp, maxMP     int32
+	ac, mr                   int16
+	fire, water, wind, earth int16
+}
+
+func playerSenseInfo(p *world.PlayerInfo) senseInfo {
+	return senseInfo{
+		id: p.CharID, name: p.Name, level: p.Level,
+		hp: int32(p.HP), maxHP: int32(p.MaxHP), mp: int32(p.MP), maxMP: int32(p.MaxMP),
+		ac: p.AC, mr: p.MR,
+		fire: p.FireRes, water: p.WaterRes, wind: p.WindRes, earth: p.EarthRes,
+	}
+}
+
+func npcSenseInfo(npc *world.NpcInfo) senseInfo {
+	return senseInfo{
+		id: npc.ID, name: npc.Name, level: npc.Level,
+		hp: npc.HP, maxHP: npc.MaxHP, mp: npc.MP, maxMP: npc.MaxMP,
+		ac: npc.AC, mr: npc.MR,
+		fire: npc.FireRes, water: npc.WaterRes, wind: npc.WindRes, earth: npc.EarthRes,
+	}
+}
+
+// energySense 能量感測：對施法者顯示目標 HP 條（S_HPMeter），並在目標身上播放
+// 弱點屬性特效（抗性為負的屬性，Java: WEAK_ELEMENTAL）。3.80C 客戶端沒有目標
+// 能力值視窗，完整數值（等級、MP、AC、MR、四屬性抗性）僅以系統訊息提供給 GM 除錯。
+func energySense(sess *net.Session, caster *world.PlayerInfo, t senseInfo, nearby []*world.PlayerInfo) {
+	hpRatio := int16(0)
+	if t.maxHP > 0 && t.hp > 0 {
+		hpRatio = int16(t.hp * 100 / t.maxHP)
+	}
+	sess.Send(handler.BuildHpMeter(t.id, hpRatio))
+
+	for _, weak := range []struct {
+		res int16
+		gfx int32
+	}{
+		{t.earth, gfxWeakEarth},
+		{t.fire, gfxWeakFire},
+		{t.water, gfxWeakWater},
+		{t.wind, gfxWeakWind},
+	} {
+		if weak.res < 0 {
+			handler.BroadcastToPlayers(nearby, handler.BuildSkillEffect(t.id, weak.gfx))
+		}
+	}
+
+	if caster.IsGM() {
+		handler.SendGlobalChat(sess, 9, fmt.Sprintf(
+			"\\f2【%s】 Lv.%d  HP:%d/%d  MP:%d/%d  AC:%d  MR:%d  火:%d 水:%d 風:%d 地:%d",
+			t.name, t.level, t.hp, t.maxHP, t.mp, t.maxMP, t.ac, t.mr,
+			t.fire, t.water, t.wind, t.earth))
+	}
+}
@@ -0,0 +1,71 @@
+package system
+
+import (
+	"bytes"
+	stdnet "net"
+	"testing"
+
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/net"
+	"github.com/l1jgo/server/internal/world"
+	"go.uber.org/zap"
+)
+
+// sentPackets 送出並取回 session 本 tick 的所有封包。
+func sentPackets(sess *net.Session) [][]byte {
+	sess.FlushOutput()
+	var out [][]byte
+	for len(sess.OutQueue) > 0 {
+		out = append(out, <-sess.OutQueue)
+	}
+	return out
+}
+
+func TestEnergySenseOnNpc(t *testing.T) {
+	c1, c2 := stdnet.Pipe()
+	t.Cleanup(func() { c1.Close(); c2.Close() })
+	sess := net.NewSession(c1, 1, 4, 16, 0, 0, zap.NewNop())
+	caster := &world.PlayerInfo{SessionID: 1, Session: sess, CharID: 1, Name: "mage"}
+
+	npc := &world.NpcInfo{
+		ID: 5000, Name: "orc", Level: 12, HP: 30, MaxHP: 120,
+		FireRes: -10, WaterRes: 20, WindRes: 0, EarthRes: -5,
+	}
+	energySense(sess, caster, npcSenseInfo(npc), []*world.PlayerInfo{caster})
+
+	got := sentPackets(sess)
+	want := [][]byte{
+		handler.BuildHpMeter(npc.ID, 25),
+		handler.BuildSkillEffect(npc.ID, gfxWeakEarth),
+		handler.BuildSkillEffect(npc.ID, gfxWeakFire),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("sent %d packets, want %d (HP meter + 2 weak elements, no chat)", len(got), len(want))
+	}
+	for i := range want {
+		if !bytes.Equal(got[i], want[i]) {
+			t.Errorf("packet %d = % x, want % x", i, got[i], want[i])
+		}
+	}
+}
+
+func TestEnergySenseGMGetsStatDump(t *testing.T) {
+	c1, c2 := stdnet.Pipe()
+	t.Cleanup(func() { c1.Close(); c2.Close() })
+	sess := net.NewSession(c1, 1, 4, 16, 0, 0, zap.NewNop())
+	gm := &world.PlayerInfo{SessionID: 1, Session: sess, CharID: 1, Name: "gm", AccessLevel: 200}
+	target := &world.PlayerInfo{CharID: 2, Name: "target", Level: 40, HP: 0, MaxHP: 300}
+
+	energySense(sess, gm, playerSenseInfo(target), nil)
+
+	got := sentPackets(sess)
+	if len(got) != 2 {
+		t.Fatalf("GM got %d packets, want HP meter + stat dump", len(got))
+	}
+	if !bytes.Equal(got[0], handler.BuildHpMeter(target.CharID, 0)) {
+		t.Errorf("dead target HP meter = % x", got[0])
+	}
+	if !bytes.Contains(got[1], []byte("Lv.40")) {
+		t.Errorf("GM stat dump missing level: % x", got[1])
+	}
+}
@@ -3,6 +3,7 @@ package system
 import (
 	"fmt"
 
+	"github.com/l1jgo/server/internal/config"
 	"github.com/l1jgo/server/internal/data"
 	"github.com/l1jgo/server/internal/handler"
 	"github.com/l1jgo/server/internal/net"
@@ -82,7 +83,9 @@ func (s *EquipSystem) EquipWeapon(sess *net.Session, player *world.PlayerInfo, i
 	sendEquipSlotUpdate(sess, invItem.ObjectID, world.SlotWeapon, true)
 
 	// 套裝偵測
+	prevSetID := player.ActiveSetID
 	newSetPoly, oldSetPoly := s.updateArmorSetOnEquip(player, invItem.ItemID)
+	s.notifyArmorSetChange(sess, player, prevSetID)
 
 	// 重新計算裝備屬性
 	s.RecalcEquipStats(sess, player)
@@ -235,7 +238,9 @@ func (s *EquipSystem) EquipArmor(sess *net.Session, player *world.PlayerInfo, in
 	sendEquipSlotUpdate(sess, invItem.ObjectID, slot, true)
 
 	// 套裝偵測
+	prevSetID := player.ActiveSetID
 	newSetPoly, oldSetPoly := s.updateArmorSetOnEquip(player, invItem.ItemID)
+	s.notifyArmorSetChange(sess, player, prevSetID)
 
 	// 重新計算裝備屬性
 	s.RecalcEquipStats(sess, player)
@@ -272,7 +277,9 @@ func (s *EquipSystem) UnequipSlot(sess *net.Session, player *world.PlayerInfo, s
 	player.Equip.Set(slot, nil)
 
 	// 檢查是否破壞了護甲套裝
+	prevSetID := player.ActiveSetID
 	brokenSetPoly := s.updateArmorSetOnUnequip(player)
+	s.notifyArmorSetChange(sess, player, prevSetID)
 
 	// 脫下武器時清除視覺
 	if slot == world.SlotWeapon {
@@ -311,7 +318,7 @@ func (s *EquipSystem) FindEquippedSlot(player *world.PlayerInfo, item *world.Inv
 // RecalcEquipStats 重新計算裝備屬性並發送更新封包。
 func (s *EquipSystem) RecalcEquipStats(sess *net.Session, player *world.PlayerInfo) {
 	old := player.EquipBonuses
-	applyEquipStats(player, s.deps.Items, s.deps.ArmorSets)
+	applyEquipStats(player, s.deps.Items, s.deps.ArmorSets, &s.deps.Config.Enchant)
 
 	// 發送更新封包
 	handler.SendPlayerStatus(sess, player)
@@ -332,7 +339,7 @@ func (s *EquipSystem) RecalcEquipStats(sess *net.Session, player *world.PlayerIn
 func (s *EquipSystem) InitEquipStats(player *world.PlayerInfo) {
 	player.AC = int16(s.deps.Config.Gameplay.BaseAC)
 	detectActiveArmorSet(player, s.deps.ArmorSets)
-	applyEquipStats(player, s.deps.Items, s.deps.ArmorSets)
+	applyEquipStats(player, s.deps.Items, s.deps.ArmorSets, &s.deps.Config.Enchant)
 }
 
 // SendEquipList 發送完整裝備欄位列表封包（登入時用）。
@@ -432,10 +439,37 @@ func (s *EquipSystem) updateArmorSetOnUnequip(player *world.PlayerInfo) (brokenP
 	return 0
 }
 
+// notifyArmorSetChange 套裝啟動/解除時通知玩家（armor_set_notice），啟動時播放特效（armor_set_gfx）。
+func (s *EquipSystem) notifyArmorSetChange(sess *net.Session, player *world.PlayerInfo, prevSetID int) {
+	if player.ActiveSetID == prevSetID || s.deps.ArmorSets == nil {
+		return
+	}
+	cfg := &s.deps.Config.Gameplay
+	if prevSetID != 0 && cfg.ArmorSetNotice {
+		if old := s.deps.ArmorSets.GetByID(prevSetID); old != nil {
+			handler.SendSystemMessage(sess, fmt.Sprintf("套裝效果解除：%s", old.Name))
+		}
+	}
+	if player.ActiveSetID == 0 {
+		return
+	}
+	set := s.deps.ArmorSets.GetByID(player.ActiveSetID)
+	if set == nil {
+		return
+	}
+	if cfg.ArmorSetNotice {
+		handler.SendSystemMessage(sess, fmt.Sprintf("套裝效果啟動：%s", set.Name))
+	}
+	if cfg.ArmorSetGfx > 0 {
+		nearby := s.deps.World.GetNearbyPlayersAt(player.X, player.Y, player.MapID)
+		handler.BroadcastToPlayers(nearby, handler.BuildSkillEffect(player.CharID, cfg.ArmorSetGfx))
+	}
+}
+
 // applyEquipStats 計算裝備屬性加成並應用到玩家（不發送封包）。
-func applyEquipStats(player *world.PlayerInfo, items *data.ItemTable, armorSets *data.ArmorSetTable) {
+func applyEquipStats(player *world.PlayerInfo, items *data.ItemTable, armorSets *data.ArmorSetTable, enchant *config.EnchantConfig) {
 	old := player.EquipBonuses
-	neo := calcEquipStats(player, items, armorSets)
+	neo := calcEquipStats(player, items, armorSets, enchant)
 
 	player.AC += int16(neo.AC - old.AC)
 	player.Str += int16(neo.AddStr - old.AddStr)
@@ -466,7 +500,7 @@ func applyEquipStats(player *world.PlayerInfo, items *data.ItemTable, armorSets
 }
 
 // calcEquipStats 計算玩家所有裝備的屬性加成總和（含套裝加成）。
-func calcEquipStats(player *world.PlayerInfo, items *data.ItemTable, armorSets *data.ArmorSetTable) world.EquipStats {
+func calcEquipStats(player *world.PlayerInfo, items *data.ItemTable, armorSets *data.ArmorSetTable, enchant *config.EnchantConfig) world.EquipStats {
 	var stats world.EquipStats
 	for i := world.EquipSlot(1); i < world.SlotMax; i++ {
 		invItem := player.Equip.Get(i)
@@ -485,10 +519,11 @@ func calcEquipStats(player *world.PlayerInfo, items *data.ItemTable, armorSets *
 		}
 		stats.HitMod += info.HitMod
 		stats.DmgMod += info.DmgMod
-		// 武器衝裝加成
+		// 武器衝裝加成（[enchant] 每級加成，可依武器類型覆寫）
 		if i == world.SlotWeapon && invItem.EnchantLvl > 0 {
-			stats.HitMod += int(invItem.EnchantLvl) / 2
-			stats.DmgMod += int(invItem.EnchantLvl)
+			hit, dmg := weaponEnchantBonus(enchant, info.Type, int(invItem.EnchantLvl))
+			stats.HitMod += hit
+			stats.DmgMod += dmg
 		}
 		// NPC 魔法附加加成
 		if invItem.DmgByMagic > 0 && invItem.DmgMagicExpiry > 0 {
@@ -537,6 +572,16 @@ func calcEquipStats(player *world.PlayerInfo, items *data.ItemTable, armorSets *
 	return stats
 }
 
+// weaponEnchantBonus 回傳武器衝裝等級帶來的命中／傷害加成。
+// 武器類型在 [enchant.weapon_type] 有設定時使用該設定，否則使用全域每級加成。
+func weaponEnchantBonus(cfg *config.EnchantConfig, weaponType string, level int) (hit, dmg int) {
+	hitPer, dmgPer := cfg.WeaponHitPerLevel, cfg.WeaponDmgPerLevel
+	if scale, ok := cfg.WeaponType[weaponType]; ok {
+		hitPer, dmgPer = scale.HitPerLevel, scale.DmgPerLevel
+	}
+	return int(float64(level) * hitPer), int(float64(level) * dmgPer)
+}
+
 // ==================== 輔助函式 ====================
 
 // canClassUse 檢查職業是否可使用物品。
@@ -0,0 +1,115 @@
+package system
+
+import (
+	"bytes"
+	stdnet "net"
+	"path/filepath"
+	"testing"
+
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/net"
+	"github.com/l1jgo/server/internal/world"
+	"go.uber.org/zap"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
+// equipStatSnapshot 套裝可能影響的玩家屬性。
+type equipStatSnapshot struct {
+	AC, Str, Dex, Con, Intel, Wis, Cha   int16
+	MaxHP, MaxMP, HPR, MPR, SP, MR       int16
+	HitMod, DmgMod, BowHitMod, BowDmgMod int16
+}
+
+func snapshotEquipStats(p *world.PlayerInfo) equipStatSnapshot {
+	return equipStatSnapshot{
+		p.AC, p.Str, p.Dex, p.Con, p.Intel, p.Wis, p.Cha,
+		p.MaxHP, p.MaxMP, p.HPR, p.MPR, p.SP, p.MR,
+		p.HitMod, p.DmgMod, p.BowHitMod, p.BowDmgMod,
+	}
+}
+
+func TestArmorSetEquipUnequipRevertsStats(t *testing.T) {
+	deps := newTestDeps(t)
+	deps.World = world.NewState()
+	deps.Config.Gameplay.ArmorSetNotice = true
+	dir := filepath.Join("..", "..", "data", "yaml")
+	items, err := data.LoadItemTable(filepath.Join(dir, "weapon_list.yaml"), filepath.Join(dir, "armor_list.yaml"), filepath.Join(dir, "etcitem_list.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sets, err := data.LoadArmorSetTable(filepath.Join(dir, "armor_set_list.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps.Items, deps.ArmorSets = items, sets
+	const setID = 43 // 四大軍王套裝：HP/MP +30、HPR/MPR +10、CHA +3，無變身
+	set := sets.GetByID(setID)
+	if set == nil {
+		t.Fatalf("armor set %d missing", setID)
+	}
+
+	c1, c2 := stdnet.Pipe()
+	t.Cleanup(func() { c1.Close(); c2.Close() })
+	sess := net.NewSession(c1, 1, 4, 256, 0, 0, zap.NewNop())
+	p := &world.PlayerInfo{
+		SessionID: 1, Session: sess, CharID: 1, Name: "royal", ClassType: 0, Level: 50,
+		AC: 10, Str: 12, Dex: 12, Con: 12, Intel: 12, Wis: 12, Cha: 12,
+		HP: 100, MaxHP: 100, MP: 50, MaxMP: 50, Inv: world.NewInventory(),
+	}
+	deps.World.AddPlayer(p)
+	before := snapshotEquipStats(p)
+
+	s := NewEquipSystem(deps)
+	// 長袍須在斗篷之前穿上
+	order := []int32{20109, 20178, 20200, 20057}
+	var worn []*world.InvItem
+	for _, id := range order {
+		info := items.Get(id)
+		it := p.Inv.AddItem(id, 1, info.Name, 0, 0, false, 1)
+		s.EquipArmor(sess, p, it, info)
+		if !it.Equipped {
+			t.Fatalf("%s (%d) not equipped", info.Name, id)
+		}
+		worn = append(worn, it)
+	}
+	if p.ActiveSetID != setID {
+		t.Fatalf("active set = %d, want %d", p.ActiveSetID, setID)
+	}
+	if got := p.MaxHP - before.MaxHP; got != int16(set.HP) {
+		t.Errorf("set MaxHP bonus = %d, want %d", got, set.HP)
+	}
+	if got := p.Cha - before.Cha; got != int16(set.Cha) {
+		t.Errorf("set CHA bonus = %d, want %d", got, set.Cha)
+	}
+
+	// 先脫斗篷：套裝解除
+	for i := len(worn) - 1; i >= 0; i-- {
+		s.EquipArmor(sess, p, worn[i], items.Get(worn[i].ItemID))
+		if worn[i].Equipped {
+			t.Fatalf("%d still equipped", worn[i].ItemID)
+		}
+		if i == len(worn)-1 && p.ActiveSetID != 0 {
+			t.Fatal("set still active after removing a piece")
+		}
+	}
+	if after := snapshotEquipStats(p); after != before {
+		t.Errorf("stats after equip→unequip\n got %+v\nwant %+v", after, before)
+	}
+
+	big5 := func(s string) []byte {
+		b, err := traditionalchinese.Big5.NewEncoder().Bytes([]byte(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	onMsg, offMsg := big5("套裝效果啟動"), big5("套裝效果解除")
+	var on, off bool
+	for _, pkt := range sentPackets(sess) {
+		on = on || bytes.Contains(pkt, onMsg)
+		off = off || bytes.Contains(pkt, offMsg)
+	}
+	if !on || !off {
+		t.Errorf("set notices: activate %v, break %v", on, off)
+	}
+}
@@ -0,0 +1,116 @@
+package system
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/l1jgo/server/internal/net/packet"
+	"github.com/l1jgo/server/internal/world"
+)
+
+const fullBagNotice = "背包已滿，部分掉落物已放在地上。"
+
+func newGiveDropsFixture(t *testing.T, freeSlots int) (*ItemUseSystem, *world.PlayerInfo, *world.NpcInfo) {
+	t.Helper()
+	deps := newTestDeps(t)
+	deps.World = world.NewState()
+	deps.Items = newTestItemsWithWeapons(t, `  - {item_id: 1, name: 長劍, type: sword, weight: 1}
+  - {item_id: 2, name: 短劍, type: dagger, weight: 1}
+  - {item_id: 3, name: 雙手劍, type: tohandsword, weight: 1}
+`, "")
+	deps.Drops = newTestDrops(t, `drops:
+  - mob_id: 45008
+    items:
+      - {item_id: 1, min: 1, max: 1, chance: 1000000}
+      - {item_id: 2, min: 1, max: 1, chance: 1000000}
+      - {item_id: 3, min: 1, max: 1, chance: 1000000}
+`, "global_drops: []\n")
+
+	killer := &world.PlayerInfo{
+		SessionID: 1, Session: newTestSession(t, 1), CharID: 1, Name: "killer", Level: 50, Str: 18, Con: 18,
+		X: 32700, Y: 32800, MapID: 4, Inv: world.NewInventory(),
+	}
+	for i := 0; i < world.MaxInventorySize-freeSlots; i++ {
+		killer.Inv.AddItem(int32(100000+i), 1, "雜物", 0, 0, false, 1)
+	}
+	deps.World.AddPlayer(killer)
+	npc := &world.NpcInfo{ID: world.NextNpcID(), NpcID: 45008, X: 32705, Y: 32802, MapID: 4}
+	return NewItemUseSystem(deps), killer, npc
+}
+
+// groundLoot 回傳 (x, y) 上屬於擊殺者的地面掉落物 ID。
+func groundLoot(s *ItemUseSystem, killer *world.PlayerInfo, x, y int32) []int32 {
+	var ids []int32
+	for _, g := range s.deps.World.GetNearbyGroundItems(x, y, killer.MapID) {
+		if g.X == x && g.Y == y && g.Loot && g.OwnerID == killer.CharID {
+			ids = append(ids, g.ItemID)
+		}
+	}
+	return ids
+}
+
+// countNotices 計算送給擊殺者的「背包已滿」系統訊息數。
+func countNotices(killer *world.PlayerInfo) int {
+	w := packet.NewWriterWithOpcode(packet.S_OPCODE_MESSAGE)
+	w.WriteC(9)
+	w.WriteS(fullBagNotice)
+	want := w.Bytes()
+	n := 0
+	for _, p := range sentPackets(killer.Session) {
+		if bytes.Equal(p, want) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestGiveDropsFullBagKeepsLoot(t *testing.T) {
+	t.Run("擊殺者腳下", func(t *testing.T) {
+		s, killer, npc := newGiveDropsFixture(t, 0)
+		s.deps.Config.Gameplay.FullBagDrop = "killer"
+		s.deps.Config.Gameplay.FullBagNotice = true
+
+		got := s.GiveDrops(killer, npc)
+		if len(got) != 3 {
+			t.Errorf("returned %d drops, want all 3", len(got))
+		}
+		if ids := groundLoot(s, killer, killer.X, killer.Y); len(ids) != 3 {
+			t.Errorf("loot at killer's feet %v, want 3 items", ids)
+		}
+		if ids := groundLoot(s, killer, npc.X, npc.Y); len(ids) != 0 {
+			t.Errorf("loot at corpse %v, want none", ids)
+		}
+		if n := countNotices(killer); n != 1 {
+			t.Errorf("sent %d full-bag notices, want 1", n)
+		}
+	})
+
+	t.Run("怪物死亡處", func(t *testing.T) {
+		s, killer, npc := newGiveDropsFixture(t, 0)
+		s.deps.Config.Gameplay.FullBagDrop = "corpse"
+
+		s.GiveDrops(killer, npc)
+		if ids := groundLoot(s, killer, npc.X, npc.Y); len(ids) != 3 {
+			t.Errorf("loot at corpse %v, want 3 items", ids)
+		}
+		if n := countNotices(killer); n != 0 {
+			t.Errorf("sent %d notices with full_bag_notice off", n)
+		}
+	})
+
+	t.Run("剩一格", func(t *testing.T) {
+		s, killer, npc := newGiveDropsFixture(t, 1)
+		s.deps.Config.Gameplay.FullBagNotice = true
+
+		s.GiveDrops(killer, npc)
+		if killer.Inv.FindByItemID(1) == nil {
+			t.Error("first drop not added to the free slot")
+		}
+		if ids := groundLoot(s, killer, killer.X, killer.Y); len(ids) != 2 || ids[0] == 1 || ids[1] == 1 {
+			t.Errorf("ground loot %v, want the two drops that did not fit", ids)
+		}
+		if n := countNotices(killer); n != 1 {
+			t.Errorf("sent %d full-bag notices, want 1", n)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// newTestDrops 以給定的 drop_list 與 global_drops YAML 建立掉落表。
+func newTestDrops(t *testing.T, dropList, globalDrops string) *data.DropTable {
+	t.Helper()
+	dir := t.TempDir()
+	dropPath, globalPath := filepath.Join(dir, "drop_list.yaml"), filepath.Join(dir, "global_drops.yaml")
+	for path, body := range map[string]string{dropPath: dropList, globalPath: globalDrops} {
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	drops, err := data.LoadDropTable(dropPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := drops.LoadGlobalDrops(globalPath); err != nil {
+		t.Fatal(err)
+	}
+	return drops
+}
+
+func TestRollDropsAddsScopedGlobalDrops(t *testing.T) {
+	deps := newTestDeps(t)
+	deps.Items = newTestItems(t, `  - {item_id: 40010, name: 治癒藥水, stackable: true}
+  - {item_id: 41159, name: 活動硬幣, stackable: true}
+`)
+	deps.Drops = newTestDrops(t, `drops:
+  - mob_id: 45008
+    items:
+      - {item_id: 40010, min: 1, max: 1, chance: 1000000}
+`, `global_drops:
+  - {item_id: 41159, min: 2, max: 2, chance: 1000000, map_ids: [4], min_level: 10}
+`)
+
+	tests := []struct {
+		name  string
+		npcID int32
+		mapID int16
+		level int16
+		want  []int32
+	}{
+		{"自身掉落與全域掉落", 45008, 4, 10, []int32{40010, 41159}},
+		{"無自身掉落表仍擲全域", 45009, 4, 20, []int32{41159}},
+		{"地圖不符", 45008, 0, 20, []int32{40010}},
+		{"等級不足", 45008, 4, 9, []int32{40010}},
+	}
+	for _, tt := range tests {
+		got := rollDrops(deps, &world.NpcInfo{NpcID: tt.npcID, MapID: tt.mapID, Level: tt.level})
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: dropped %v, want items %v", tt.name, got, tt.want)
+			continue
+		}
+		for i, loot := range got {
+			if loot.ItemID != tt.want[i] {
+				t.Errorf("%s: drop %d = %d, want %d", tt.name, i, loot.ItemID, tt.want[i])
+			}
+			if loot.ItemID == 41159 && loot.Count != 2 {
+				t.Errorf("%s: global drop count %d, want 2", tt.name, loot.Count)
+			}
+		}
+	}
+}
+
+func TestGlobalDropsIgnoreDropRate(t *testing.T) {
+	world.SetRand(world.NewRand(3))
+	t.Cleanup(func() { world.SetRand(world.NewRand(time.Now().UnixNano())) })
+	deps := newTestDeps(t)
+	deps.Config.Rates.DropRate = 1000
+	deps.Items = newTestItems(t, "  - {item_id: 41159, name: 活動硬幣, stackable: true}\n")
+	// 0.1%：若套用 1000 倍掉寶率會變成必掉
+	deps.Drops = newTestDrops(t, "drops: []\n", "global_drops:\n  - {item_id: 41159, min: 1, max: 1, chance: 1000}\n")
+
+	var n int
+	for i := 0; i < 200; i++ {
+		n += len(rollDrops(deps, &world.NpcInfo{NpcID: 45008, MapID: 4, Level: 1}))
+	}
+	if n > 10 {
+		t.Errorf("global drop hit %d/200 times at 0.1%%; drop_rate must not scale it", n)
+	}
+}
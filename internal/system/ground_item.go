@@ -1,6 +1,7 @@
 package system
 
 import (
+	"fmt"
 	"time"
 
 	coresys "github.com/l1jgo/server/internal/core/system"
@@ -9,22 +10,60 @@ import (
 )
 
 // GroundItemSystem removes expired ground items and broadcasts S_RemoveObject
-// to nearby players. Phase 3 (PostUpdate).
+// to nearby players, and announces loot whose owner-priority window ended.
+// When auto-loot is enabled it also pulls nearby owned loot into each
+// player's inventory once per second. Phase 3 (PostUpdate).
 type GroundItemSystem struct {
-	world *world.State
+	world   *world.State
+	deps    *handler.Deps
+	loot    *ItemGroundSystem
+	tickAcc int
 }
 
-func NewGroundItemSystem(ws *world.State) *GroundItemSystem {
-	return &GroundItemSystem{world: ws}
+func NewGroundItemSystem(ws *world.State, deps *handler.Deps, loot *ItemGroundSystem) *GroundItemSystem {
+	return &GroundItemSystem{world: ws, deps: deps, loot: loot}
 }
 
 func (s *GroundItemSystem) Phase() coresys.Phase { return coresys.PhasePostUpdate }
 
 func (s *GroundItemSystem) Update(_ time.Duration) {
-	expired := s.world.TickGroundItems()
+	expired, released := s.world.TickGroundItems()
 	for _, g := range expired {
 		nearby := s.world.GetNearbyPlayersAt(g.X, g.Y, g.MapID)
 		data := handler.BuildRemoveObject(g.ID)
 		handler.BroadcastToPlayers(nearby, data)
 	}
+	if len(released) > 0 && s.deps.Config.Gameplay.LootFreeNotice {
+		s.announceFreeLoot(released)
+	}
+
+	if !s.deps.Config.Gameplay.AutoLoot {
+		return
+	}
+	// 自動拾取：每 5 tick（約 1 秒）一次
+	s.tickAcc++
+	if s.tickAcc < 5 {
+		return
+	}
+	s.tickAcc = 0
+	s.world.AllPlayers(func(p *world.PlayerInfo) {
+		s.loot.AutoLoot(p)
+	})
+}
+
+// announceFreeLoot 通知附近玩家擁有者優先期間已結束的掉落物（屍體逐一通知，地面物品合併件數）。
+func (s *GroundItemSystem) announceFreeLoot(released []*world.GroundItem) {
+	items := make(map[*world.PlayerInfo]int)
+	for _, g := range released {
+		for _, p := range s.world.GetNearbyPlayersAt(g.X, g.Y, g.MapID) {
+			if g.IsCorpse() {
+				handler.SendSystemMessage(p.Session, fmt.Sprintf("「%s」已開放所有人拾取。", g.Name))
+			} else {
+				items[p]++
+			}
+		}
+	}
+	for p, n := range items {
+		handler.SendSystemMessage(p.Session, fmt.Sprintf("附近 %d 件掉落物已開放所有人撿取。", n))
+	}
 }
@@ -1,6 +1,11 @@
 package system
 
-import "github.com/l1jgo/server/internal/world"
+import (
+	"slices"
+
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/world"
+)
 
 // AddHate 累加仇恨值並維護 AggroTarget 快取。
 // 若新仇恨累計超過當前目標，自動切換 AggroTarget。
@@ -12,6 +17,9 @@ func AddHate(npc *world.NpcInfo, sessionID uint64, damage int32) {
 	if npc.HateList == nil {
 		npc.HateList = make(map[uint64]int32)
 	}
+	if len(npc.HateList) == 0 {
+		npc.FirstTagger = sessionID
+	}
 	npc.HateList[sessionID] += damage
 
 	// 首次受擊或仇恨超過當前目標 → 切換
@@ -48,11 +56,15 @@ func RemoveHateTarget(npc *world.NpcInfo, sessionID uint64) {
 	if npc.HateList != nil {
 		delete(npc.HateList, sessionID)
 	}
+	if npc.FirstTagger == sessionID {
+		npc.FirstTagger = 0
+	}
 }
 
 // ClearHateList 清空仇恨列表（NPC 死亡或重生時呼叫）。
 func ClearHateList(npc *world.NpcInfo) {
 	npc.HateList = nil
+	npc.FirstTagger = 0
 }
 
 // GetTotalHate 回傳所有仇恨的累計總值（經驗分配用）。
@@ -63,3 +75,89 @@ func GetTotalHate(npc *world.NpcInfo) int32 {
 	}
 	return total
 }
+
+// 擊殺歸屬規則（[combat] kill_credit）
+const (
+	killCreditLastHit    = "last_hit"    // 最後一擊者（預設）
+	killCreditMostDamage = "most_damage" // 累積傷害最高的隊伍／個人
+	killCreditFirstTag   = "first_tag"   // 第一個造成傷害者
+)
+
+// resolveKillCredit 依 kill_credit 決定擊殺歸屬者（善惡值、掉落、寵物經驗、擊殺事件）。
+// 經驗值仍依仇恨比例分配；歸屬對象已離線、死亡或不在同地圖時退回最後一擊者。
+func resolveKillCredit(npc *world.NpcInfo, finisher *world.PlayerInfo, deps *handler.Deps) *world.PlayerInfo {
+	switch deps.Config.Combat.KillCredit {
+	case killCreditMostDamage:
+		if p := mostDamageCredit(npc, deps); p != nil {
+			return p
+		}
+	case killCreditFirstTag:
+		if p := creditCandidate(npc, npc.FirstTagger, deps); p != nil {
+			return p
+		}
+	}
+	return finisher
+}
+
+// mostDamageCredit 以隊伍為單位加總傷害，取總傷害最高的隊伍（無隊伍者單獨計算），
+// 再由該隊伍中個人傷害最高者取得歸屬。
+// 依 SessionID 排序走訪仇恨列表，同分時由 SessionID 較小者（較早連線）優先，結果與 map 順序無關。
+func mostDamageCredit(npc *world.NpcInfo, deps *handler.Deps) *world.PlayerInfo {
+	type group struct {
+		total  int32
+		top    *world.PlayerInfo
+		topDmg int32
+	}
+	sids := make([]uint64, 0, len(npc.HateList))
+	for sid := range npc.HateList {
+		sids = append(sids, sid)
+	}
+	slices.Sort(sids)
+
+	groups := make(map[uint64]*group)
+	var order []*group // 依首位成員 SessionID 排序
+	for _, sid := range sids {
+		hate := npc.HateList[sid]
+		p := creditCandidate(npc, sid, deps)
+		if p == nil {
+			continue
+		}
+		// 隊伍以 PartyID 位元反轉為鍵，避免與 SessionID 衝突
+		key := sid
+		if p.PartyID > 0 {
+			key = ^uint64(p.PartyID)
+		}
+		g := groups[key]
+		if g == nil {
+			g = &group{}
+			groups[key] = g
+			order = append(order, g)
+		}
+		g.total += hate
+		if hate > g.topDmg {
+			g.top, g.topDmg = p, hate
+		}
+	}
+	var best *group
+	for _, g := range order {
+		if best == nil || g.total > best.total {
+			best = g
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return best.top
+}
+
+// creditCandidate 回傳可取得擊殺歸屬的玩家（在線、存活、與 NPC 同地圖且有造成傷害）。
+func creditCandidate(npc *world.NpcInfo, sessionID uint64, deps *handler.Deps) *world.PlayerInfo {
+	if sessionID == 0 || npc.HateList[sessionID] <= 0 {
+		return nil
+	}
+	p := deps.World.GetBySession(sessionID)
+	if p == nil || p.Dead || p.MapID != npc.MapID {
+		return nil
+	}
+	return p
+}
@@ -0,0 +1,54 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestMostDamageCreditTiesAreStable(t *testing.T) {
+	deps := newTestDeps(t)
+	deps.World = world.NewState()
+	deps.Config.Combat.KillCredit = killCreditMostDamage
+	players := map[uint64]*world.PlayerInfo{}
+	for sid := uint64(1); sid <= 6; sid++ {
+		p := &world.PlayerInfo{SessionID: sid, CharID: int32(sid), MapID: 4, X: 100, Y: int32(100 + sid)}
+		deps.World.AddPlayer(p)
+		players[sid] = p
+	}
+	// 隊伍 7（2、5）與單人 3 同為 60 傷害：SessionID 較小的一方（隊伍首位 2）勝出，
+	// 隊伍內 2 與 5 同傷害時由 2 取得歸屬。
+	players[2].PartyID = 7
+	players[5].PartyID = 7
+	npc := &world.NpcInfo{MapID: 4, HateList: map[uint64]int32{6: 10, 5: 30, 3: 60, 2: 30, 4: 20}}
+
+	for i := 0; i < 50; i++ {
+		if got := resolveKillCredit(npc, players[6], deps); got != players[2] {
+			t.Fatalf("run %d: credit went to session %d, want 2", i, got.SessionID)
+		}
+	}
+
+	// 明確最高者不受排序影響
+	npc.HateList[3] = 61
+	if got := resolveKillCredit(npc, players[6], deps); got != players[3] {
+		t.Fatalf("credit went to session %d, want 3", got.SessionID)
+	}
+}
+
+func TestKillCreditFallsBackToFinisher(t *testing.T) {
+	deps := newTestDeps(t)
+	deps.World = world.NewState()
+	deps.Config.Combat.KillCredit = killCreditFirstTag
+	finisher := &world.PlayerInfo{SessionID: 2, CharID: 2, MapID: 4}
+	deps.World.AddPlayer(finisher)
+
+	npc := &world.NpcInfo{MapID: 4}
+	AddHate(npc, 1, 50) // 首位出手者已離線
+	AddHate(npc, 2, 10)
+	if npc.FirstTagger != 1 {
+		t.Fatalf("FirstTagger = %d, want 1", npc.FirstTagger)
+	}
+	if got := resolveKillCredit(npc, finisher, deps); got != finisher {
+		t.Fatalf("credit went to session %d, want finisher", got.SessionID)
+	}
+}
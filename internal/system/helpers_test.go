@@ -0,0 +1,74 @@
+package system
+
+import (
+	stdnet "net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/l1jgo/server/internal/config"
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/net"
+	"github.com/l1jgo/server/internal/scripting"
+	"go.uber.org/zap"
+)
+
+// newTestDeps 建立測試用 Deps：空白設定 + 載入 scripts/ 的 Lua 引擎。
+func newTestDeps(t *testing.T) *handler.Deps {
+	t.Helper()
+	engine, err := scripting.NewEngine("../../scripts", zap.NewNop())
+	if err != nil {
+		t.Fatalf("load scripts: %v", err)
+	}
+	t.Cleanup(engine.Close)
+	return &handler.Deps{
+		Config:    &config.Config{},
+		Log:       zap.NewNop(),
+		Scripting: engine,
+	}
+}
+
+// newTestItems 以給定的 etcitem YAML（items: 清單內容）建立物品表；武器與防具為空。
+func newTestItems(t *testing.T, etcItems string) *data.ItemTable {
+	t.Helper()
+	return newTestItemsWithWeapons(t, "", etcItems)
+}
+
+// newTestItemsWithWeapons 同 newTestItems，另以 weapons YAML（weapons: 清單內容，空字串 = 無）建立武器。
+func newTestItemsWithWeapons(t *testing.T, weapons, etcItems string) *data.ItemTable {
+	t.Helper()
+	dir := t.TempDir()
+	if weapons == "" {
+		weapons = " []\n"
+	} else {
+		weapons = "\n" + weapons
+	}
+	if etcItems == "" {
+		etcItems = " []\n"
+	} else {
+		etcItems = "\n" + etcItems
+	}
+	files := map[string]string{
+		"weapon.yaml":  "weapons:" + weapons,
+		"armor.yaml":   "armors: []\n",
+		"etcitem.yaml": "items:" + etcItems,
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	items, err := data.LoadItemTable(filepath.Join(dir, "weapon.yaml"), filepath.Join(dir, "armor.yaml"), filepath.Join(dir, "etcitem.yaml"))
+	if err != nil {
+		t.Fatalf("load items: %v", err)
+	}
+	return items
+}
+
+// newTestSession returns an unstarted session over an in-memory pipe.
+func newTestSession(t *testing.T, id uint64) *net.Session {
+	c1, c2 := stdnet.Pipe()
+	t.Cleanup(func() { c1.Close(); c2.Close() })
+	return net.NewSession(c1, id, 4, 16, 0, 0, zap.NewNop())
+}
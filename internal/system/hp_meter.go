@@ -0,0 +1,62 @@
+package system
+
+import (
+	"time"
+
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// broadcastNpcHpMeter 廣播 NPC HP 條給附近玩家，並依設定節流。
+// 群戰（範圍魔法、高攻速）時同一隻 NPC 每 tick 可能受擊多次，逐次廣播會造成大量封包。
+// 規則：死亡（HP 0）一律立即發送；HP% 跨越區段時立即發送；
+// 同一區段內的連續受擊則至少間隔 hp_meter_throttle_ticks 才再發送一次，
+// 被節流的最後一次變化由 flushNpcHpMeter 在間隔到期後補送。
+func broadcastNpcHpMeter(npc *world.NpcInfo, nearby []*world.PlayerInfo, deps *handler.Deps) {
+	hpRatio := npcHpRatio(npc)
+
+	cfg := deps.Config.Network
+	bucket := hpRatio
+	if cfg.HpMeterBucketPct > 0 {
+		bucket = hpRatio / int16(cfg.HpMeterBucketPct)
+	}
+
+	now := time.Now()
+	if npc.HP > 0 && cfg.HpMeterThrottleTicks > 0 && bucket == npc.HpMeterBucket {
+		interval := time.Duration(cfg.HpMeterThrottleTicks) * cfg.TickRate
+		if now.Sub(npc.HpMeterSentAt) < interval {
+			npc.HpMeterPending = true
+			return
+		}
+	}
+
+	npc.HpMeterSentAt = now
+	npc.HpMeterBucket = bucket
+	npc.HpMeterPending = false
+	handler.BroadcastToPlayers(nearby, handler.BuildHpMeter(npc.ID, hpRatio))
+}
+
+// flushNpcHpMeter 補送被節流的 HP 條：節流間隔到期後廣播 NPC 目前的 HP%，
+// 確保區段內最後一次受擊的數值最終會送達。由 NpcAISystem 每 tick 呼叫。
+func flushNpcHpMeter(npc *world.NpcInfo, ws *world.State, deps *handler.Deps) {
+	if !npc.HpMeterPending {
+		return
+	}
+	cfg := deps.Config.Network
+	interval := time.Duration(cfg.HpMeterThrottleTicks) * cfg.TickRate
+	if time.Since(npc.HpMeterSentAt) < interval {
+		return
+	}
+	npc.HpMeterSentAt = time.Now()
+	npc.HpMeterPending = false
+	nearby := ws.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID)
+	handler.BroadcastToPlayers(nearby, handler.BuildHpMeter(npc.ID, npcHpRatio(npc)))
+}
+
+// npcHpRatio 回傳 NPC 目前 HP 百分比（0-100）。
+func npcHpRatio(npc *world.NpcInfo) int16 {
+	if npc.MaxHP > 0 && npc.HP > 0 {
+		return int16((npc.HP * 100) / npc.MaxHP)
+	}
+	return 0
+}
@@ -0,0 +1,54 @@
+package system
+
+import (
+	"testing"
+	"time"
+
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestHpMeterThrottledChangeIsFlushed(t *testing.T) {
+	deps := newTestDeps(t)
+	deps.Config.Network.TickRate = 200 * time.Millisecond
+	deps.Config.Network.HpMeterThrottleTicks = 3
+	deps.Config.Network.HpMeterBucketPct = 10
+	ws := world.NewState()
+	npc := &world.NpcInfo{ID: 1, HP: 100, MaxHP: 100}
+
+	// 第一次受擊立即送出
+	npc.HP = 95
+	broadcastNpcHpMeter(npc, nil, deps)
+	if npc.HpMeterPending {
+		t.Fatal("first hit left pending")
+	}
+	sentAt := npc.HpMeterSentAt
+
+	// 同區段連擊被節流，標記待補送
+	npc.HP = 92
+	broadcastNpcHpMeter(npc, nil, deps)
+	if !npc.HpMeterPending || npc.HpMeterSentAt != sentAt {
+		t.Fatal("hit inside the throttle window was not deferred")
+	}
+
+	// 間隔未到不補送
+	flushNpcHpMeter(npc, ws, deps)
+	if !npc.HpMeterPending {
+		t.Fatal("flushed before the throttle interval elapsed")
+	}
+
+	// 間隔到期後補送並清除標記
+	npc.HpMeterSentAt = time.Now().Add(-time.Second)
+	flushNpcHpMeter(npc, ws, deps)
+	if npc.HpMeterPending {
+		t.Fatal("pending change not flushed after the interval")
+	}
+
+	// 死亡一律立即送出並清除待補送
+	npc.HP = 91
+	broadcastNpcHpMeter(npc, nil, deps)
+	npc.HP = 0
+	broadcastNpcHpMeter(npc, nil, deps)
+	if npc.HpMeterPending || npc.HpMeterBucket != 0 {
+		t.Fatalf("death not sent immediately: pending=%v bucket=%d", npc.HpMeterPending, npc.HpMeterBucket)
+	}
+}
@@ -30,6 +30,8 @@ type InputSystem struct {
 	mapData      *data.MapDataTable
 	petRepo      *persist.PetRepo
 	hauntedHouse handler.HauntedHouseManager // 鬼屋副本（斷線時移除成員）
+	deps         *handler.Deps               // 斷線清理（決鬥結束通知）
+	linkDead     map[uint64]*net.Session     // 斷線保留中的連線（SessionID → 已關閉的 session）
 }
 
 func NewInputSystem(
@@ -67,6 +69,11 @@ func (s *InputSystem) SetHauntedHouse(hh handler.HauntedHouseManager) {
 	s.hauntedHouse = hh
 }
 
+// SetDeps 設定共用依賴（斷線時結束決鬥並通知對手用）。
+func (s *InputSystem) SetDeps(deps *handler.Deps) {
+	s.deps = deps
+}
+
 func (s *InputSystem) Phase() coresys.Phase { return coresys.PhaseInput }
 
 func (s *InputSystem) Update(_ time.Duration) {
@@ -92,6 +99,9 @@ doneNew:
 	}
 doneDead:
 
+	// 斷線保留倒數：到期後存檔並移出世界
+	s.tickLinkDead()
+
 	// Drain packets from each session (up to maxPerTick per session)
 	for id, sess := range s.store.Raw() {
 		if sess.IsClosed() {
@@ -152,9 +162,19 @@ doneDead:
 	})
 }
 
-// handleDisconnect cleans up when a session closes:
-// removes from world state, broadcasts S_REMOVE_OBJECT, saves position, marks offline.
+// handleDisconnect runs when a session closes. An unexpected drop outside a
+// safe zone leaves the character in-world for [character] linkdead_sec
+// (see linkdead.go); otherwise the character is removed at once.
 func (s *InputSystem) handleDisconnect(sess *net.Session) {
+	if s.holdLinkDead(sess) {
+		return
+	}
+	s.removeDisconnected(sess)
+}
+
+// removeDisconnected removes the session's character from world state,
+// broadcasts S_REMOVE_OBJECT, saves it and marks the account offline.
+func (s *InputSystem) removeDisconnected(sess *net.Session) {
 	// Clear player tile before removal (for NPC pathfinding)
 	if pre := s.worldState.GetBySession(sess.ID); pre != nil && s.mapData != nil {
 		s.mapData.SetImpassable(pre.MapID, pre.X, pre.Y, false)
@@ -163,32 +183,12 @@ func (s *InputSystem) handleDisconnect(sess *net.Session) {
 	// Remove from world state and broadcast removal
 	player := s.worldState.RemovePlayer(sess.ID)
 	if player != nil {
-		// Clean up trade if in progress — restore partner's items (items are deducted on add-to-trade)
-		if player.TradePartnerID != 0 {
-			partner := s.worldState.GetByCharID(player.TradePartnerID)
-			if partner != nil {
-				// Restore partner's deducted trade items back to their inventory
-				restoreTradeItemsOnDisconnect(partner)
-				if partner.TradeWindowOpen {
-					sendTradeStatusPacket(partner.Session, 1) // 1 = cancelled
-				}
-				partner.TradePartnerID = 0
-				partner.TradeWindowOpen = false
-				partner.TradeOk = false
-				partner.TradeItems = nil
-				partner.TradeGold = 0
-			}
-			// Disconnecting player's items are lost (they disconnected mid-trade)
-			// Items were already deducted but player is gone — restore to inventory for DB save
-			restoreTradeItemsOnDisconnect(player)
-			player.TradePartnerID = 0
-			player.TradeWindowOpen = false
-			player.TradeItems = nil
-			player.TradeGold = 0
-		}
+		s.cancelTradeOnDisconnect(player)
 
-		// 決鬥中斷線：清除對手的決鬥狀態
-		handler.ClearDuelOnDisconnect(player, s.worldState)
+		// 決鬥中斷線：結束決鬥並通知對手
+		if s.deps != nil {
+			handler.ClearDuelOnDisconnect(player, s.deps)
+		}
 
 		// Clean up party membership — matching Java breakup logic:
 		// Leader leaves or only 2 members → dissolve entire party.
@@ -349,6 +349,7 @@ func (s *InputSystem) handleDisconnect(sess *net.Session) {
 			Cha:         player.Cha - int16(eq.AddCha) - bCha,
 			Intel:       player.Intel - int16(eq.AddInt) - bIntel,
 			BonusStats:  player.BonusStats,
+			BonusAlloc:  player.BonusAlloc,
 			ElixirStats: player.ElixirStats,
 			ClanID:      player.ClanID,
 			ClanName:    player.ClanName,
@@ -356,6 +357,7 @@ func (s *InputSystem) handleDisconnect(sess *net.Session) {
 			Title:       player.Title,
 			Karma:       player.Karma,
 			PKCount:     player.PKCount,
+			RestedXP:    player.RestedXP,
 		}
 		if err := s.charRepo.SaveCharacter(ctx, row); err != nil {
 			s.log.Error("斷線存檔角色失敗",
@@ -415,14 +417,45 @@ func (s *InputSystem) handleDisconnect(sess *net.Session) {
 		}
 	}
 
-	// Mark account offline
-	if sess.AccountName != "" {
+	// 釋放在線名額與登入排隊
+	s.worldState.Logins.Release(sess.ID)
+
+	// Mark account offline（同帳號已重新登入、正在選角時保持在線）
+	if sess.AccountName != "" && s.worldState.Logins.SessionOf(sess.AccountName) == nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 		s.accountRepo.SetOnline(ctx, sess.AccountName, false)
 		cancel()
 	}
 }
 
+// cancelTradeOnDisconnect cancels an in-progress trade — restores both sides'
+// items (items are deducted on add-to-trade) and notifies the partner.
+func (s *InputSystem) cancelTradeOnDisconnect(player *world.PlayerInfo) {
+	if player.TradePartnerID == 0 {
+		return
+	}
+	partner := s.worldState.GetByCharID(player.TradePartnerID)
+	if partner != nil {
+		// Restore partner's deducted trade items back to their inventory
+		restoreTradeItemsOnDisconnect(partner)
+		if partner.TradeWindowOpen {
+			sendTradeStatusPacket(partner.Session, 1) // 1 = cancelled
+		}
+		partner.TradePartnerID = 0
+		partner.TradeWindowOpen = false
+		partner.TradeOk = false
+		partner.TradeItems = nil
+		partner.TradeGold = 0
+	}
+	// Disconnecting player's items are lost (they disconnected mid-trade)
+	// Items were already deducted but player is gone — restore to inventory for DB save
+	restoreTradeItemsOnDisconnect(player)
+	player.TradePartnerID = 0
+	player.TradeWindowOpen = false
+	player.TradeItems = nil
+	player.TradeGold = 0
+}
+
 // bookmarksToRows converts world.Bookmark slice to persist.BookmarkRow slice for JSONB storage.
 func bookmarksToRows(bms []world.Bookmark) []persist.BookmarkRow {
 	rows := make([]persist.BookmarkRow, len(bms))
@@ -634,19 +667,7 @@ func (s *InputSystem) cleanupCompanions(player *world.PlayerInfo) {
 		// Save pet state to DB
 		if s.petRepo != nil {
 			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-			s.petRepo.Save(ctx, &persist.PetRow{
-				ItemObjID: pet.ItemObjID,
-				ObjID:     pet.ID,
-				NpcID:     pet.NpcID,
-				Name:      pet.Name,
-				Level:     pet.Level,
-				HP:        pet.HP,
-				MaxHP:     pet.MaxHP,
-				MP:        pet.MP,
-				MaxMP:     pet.MaxMP,
-				Exp:       pet.Exp,
-				Lawful:    pet.Lawful,
-			})
+			s.petRepo.Save(ctx, petRowOf(pet, true)) // 登入時自動重新召喚
 			cancel()
 		}
 		ws.RemovePet(pet.ID)
@@ -686,7 +707,7 @@ func (s *InputSystem) cleanupCompanions(player *world.PlayerInfo) {
 			ws.AddNpc(npc)
 			respawnNearby := ws.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID)
 			for _, viewer := range respawnNearby {
-				sendNpcPackForInput(viewer.Session, npc)
+				handler.SendNpcPack(viewer.Session, npc)
 			}
 		}
 	}
@@ -707,41 +728,6 @@ func sendRemoveCompanionPacket(sess *net.Session, objID int32) {
 	sess.Send(w.Bytes())
 }
 
-// sendNpcPackForInput sends S_PUT_OBJECT (87) for an NPC — minimal version for input system.
-// Duplicated from handler/broadcast.go to avoid circular imports.
-func sendNpcPackForInput(sess *net.Session, npc *world.NpcInfo) {
-	w := packet.NewWriterWithOpcode(packet.S_OPCODE_PUT_OBJECT)
-	w.WriteH(uint16(npc.X))
-	w.WriteH(uint16(npc.Y))
-	w.WriteD(npc.ID)
-	w.WriteH(uint16(npc.GfxID))
-
-	status := byte(0)
-	if npc.Dead {
-		status = 8
-	}
-	w.WriteC(status)
-	w.WriteC(byte(npc.Heading))
-	w.WriteC(0) // light
-	w.WriteC(byte(npc.MoveSpeed))
-	w.WriteD(0)
-	w.WriteH(0)
-	w.WriteS(npc.NameID)
-	w.WriteS(npc.Name)
-	w.WriteC(0) // poison
-	w.WriteD(0)
-	w.WriteS("")
-	w.WriteS("")
-	w.WriteC(0)
-	w.WriteC(0xFF)
-	w.WriteC(0)
-	w.WriteC(0)
-	w.WriteC(0)
-	w.WriteC(0xFF)
-	w.WriteC(0xFF)
-	sess.Send(w.Bytes())
-}
-
 func boolToInt(b bool) int {
 	if b {
 		return 1
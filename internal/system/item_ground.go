@@ -6,12 +6,29 @@ package system
 import (
 	"fmt"
 
+	"github.com/l1jgo/server/internal/data"
 	"github.com/l1jgo/server/internal/handler"
 	"github.com/l1jgo/server/internal/net"
 	"github.com/l1jgo/server/internal/world"
 	"go.uber.org/zap"
 )
 
+// 物品轉移限制訊息
+const (
+	msgCannotDelete   uint16 = 125 // "你不能放棄它。"
+	msgCannotTransfer uint16 = 210 // "%0是不可轉移的…"
+)
+
+// itemTransferable 回傳物品是否可離開玩家背包（掉落、交易、存倉、販賣）。
+// 不可交易（tradeable: false）或封印（bless >= 128）的物品皆不可轉移。
+func itemTransferable(deps *handler.Deps, item *world.InvItem) bool {
+	if item.Bless >= 128 {
+		return false
+	}
+	info := deps.Items.Get(item.ItemID)
+	return info == nil || info.Tradeable
+}
+
 // ItemGroundSystem 實作 handler.ItemGroundManager。
 type ItemGroundSystem struct {
 	deps *handler.Deps
@@ -34,6 +51,17 @@ func (s *ItemGroundSystem) DestroyItem(sess *net.Session, player *world.PlayerIn
 		return
 	}
 
+	// 不可銷毀物品（Java: isCantDelete → S_ServerMessage 125）
+	if info := s.deps.Items.Get(item.ItemID); info != nil && info.CantDelete {
+		handler.SendServerMessage(sess, msgCannotDelete)
+		return
+	}
+	// 封印物品不可銷毀
+	if item.Bless >= 128 {
+		handler.SendServerMessageArgs(sess, msgCannotTransfer, item.Name)
+		return
+	}
+
 	if count <= 0 {
 		count = item.Count
 	}
@@ -68,6 +96,12 @@ func (s *ItemGroundSystem) DropItem(sess *net.Session, player *world.PlayerInfo,
 		return
 	}
 
+	// 不可交易或封印的物品不可掉落（Java: C_DropItem → S_ServerMessage 210）
+	if !itemTransferable(s.deps, item) {
+		handler.SendServerMessageArgs(sess, msgCannotTransfer, item.Name)
+		return
+	}
+
 	if count <= 0 {
 		count = item.Count
 	}
@@ -90,43 +124,30 @@ func (s *ItemGroundSystem) DropItem(sess *net.Session, player *world.PlayerInfo,
 
 	// 查詢地面圖示
 	grdGfx := int32(0)
+	grade := byte(0)
+	stackable := itemID == world.AdenaItemID
 	itemInfo := s.deps.Items.Get(itemID)
 	if itemInfo != nil {
 		grdGfx = itemInfo.GrdGfx
+		grade = byte(itemInfo.Grade)
+		stackable = stackable || itemInfo.Stackable
 	}
 
-	// 建構顯示名稱
-	displayName := itemName
-	if enchantLvl > 0 {
-		displayName = fmt.Sprintf("+%d %s", enchantLvl, displayName)
-	} else if enchantLvl < 0 {
-		displayName = fmt.Sprintf("%d %s", enchantLvl, displayName)
-	}
-	if count > 1 {
-		displayName = fmt.Sprintf("%s (%d)", displayName, count)
-	}
-
-	// 在玩家位置建立地面物品
-	gndItem := &world.GroundItem{
+	// 在玩家位置建立地面物品（可與同格相同物品合併）
+	gndItem := placeGroundItem(s.deps, &world.GroundItem{
 		ID:         world.NextGroundItemID(),
 		ItemID:     itemID,
 		Count:      count,
 		EnchantLvl: enchantLvl,
-		Name:       displayName,
+		Name:       groundDisplayName(itemName, enchantLvl, count),
 		GrdGfx:     grdGfx,
 		X:          player.X,
 		Y:          player.Y,
 		MapID:      player.MapID,
 		OwnerID:    player.CharID,
 		TTL:        5 * 60 * 5, // 5 分鐘（200ms tick）
-	}
-	s.deps.World.AddGroundItem(gndItem)
-
-	// 廣播給附近玩家（含自己）
-	nearby := s.deps.World.GetNearbyPlayersAt(player.X, player.Y, player.MapID)
-	for _, viewer := range nearby {
-		handler.SendDropItem(viewer.Session, gndItem)
-	}
+		Grade:      grade,
+	}, itemName, stackable)
 
 	s.deps.Log.Debug("物品掉落至地面",
 		zap.String("player", player.Name),
@@ -136,88 +157,167 @@ func (s *ItemGroundSystem) DropItem(sess *net.Session, player *world.PlayerInfo,
 	)
 }
 
-// PickupItem 從地面撿取物品。
-func (s *ItemGroundSystem) PickupItem(sess *net.Session, player *world.PlayerInfo, objectID int32) {
+// PickupItem 從地面撿取物品。count > 0 且小於整堆數量時只撿取部分，其餘留在地面。
+func (s *ItemGroundSystem) PickupItem(sess *net.Session, player *world.PlayerInfo, objectID, count int32) {
 	if player.Dead {
 		return
 	}
 
 	gndItem := s.deps.World.GetGroundItem(objectID)
-	if gndItem == nil {
+	if gndItem == nil || gndItem.IsCorpse() {
 		return
 	}
 
 	// 距離檢查（Chebyshev <= 3）
-	dx := player.X - gndItem.X
-	dy := player.Y - gndItem.Y
-	if dx < 0 {
-		dx = -dx
+	if groundDist(player, gndItem) > 3 {
+		return
 	}
-	if dy < 0 {
-		dy = -dy
+
+	// 地圖檢查
+	if player.MapID != gndItem.MapID {
+		return
 	}
-	dist := dx
-	if dy > dist {
-		dist = dy
+
+	// 擁有者優先期間：僅擁有者或其隊友可撿取
+	if !s.canLoot(player, gndItem) {
+		handler.SendSystemMessage(sess, "此物品目前僅限擁有者或其隊友撿取。")
+		return
 	}
-	if dist > 3 {
+
+	s.pickup(sess, player, gndItem, count, false)
+}
+
+// AutoLoot 將附近屬於玩家的怪物掉落物自動收入背包（由 GroundItemSystem 每秒呼叫）。
+// 背包已滿或超重時靜默略過，物品留在地面；過濾清單內的物品不自動拾取。
+func (s *ItemGroundSystem) AutoLoot(player *world.PlayerInfo) {
+	if player.Dead {
 		return
 	}
+	radius := int32(s.deps.Config.Gameplay.AutoLootRadius)
+	for _, gndItem := range s.deps.World.GetNearbyGroundItems(player.X, player.Y, player.MapID) {
+		if !gndItem.Loot || gndItem.OwnerID != player.CharID || player.LootFilter[gndItem.ItemID] {
+			continue
+		}
+		if groundDist(player, gndItem) > radius {
+			continue
+		}
+		s.pickup(player.Session, player, gndItem, 0, true)
+	}
+}
 
-	// 地圖檢查
-	if player.MapID != gndItem.MapID {
+// canLoot 檢查擁有者優先期間內，玩家是否為擁有者或擊殺當下的隊友（見 tagLoot）。
+func (s *ItemGroundSystem) canLoot(player *world.PlayerInfo, gndItem *world.GroundItem) bool {
+	if gndItem.OwnerTicks <= 0 || gndItem.OwnerID == 0 || gndItem.OwnerID == player.CharID {
+		return true
+	}
+	for _, memberID := range gndItem.OwnerParty {
+		if memberID == player.CharID {
+			return true
+		}
+	}
+	return false
+}
+
+// tagLoot 為怪物掉落物（地面物品或屍體）標記擁有者與優先期間（[gameplay] loot_owner_seconds）。
+// loot_owner_policy 為 "party" 時記錄擊殺當下的隊伍成員，之後加入隊伍者不可撿取。
+func tagLoot(deps *handler.Deps, gndItem *world.GroundItem, killer *world.PlayerInfo) {
+	cfg := &deps.Config.Gameplay
+	gndItem.OwnerID = killer.CharID
+	gndItem.OwnerTicks = cfg.LootOwnerSeconds * 5
+	if gndItem.OwnerTicks <= 0 || cfg.LootOwnerPolicy != "party" {
 		return
 	}
+	if party := deps.World.Parties.GetParty(killer.CharID); party != nil {
+		gndItem.OwnerParty = append([]int32(nil), party.Members...)
+	}
+}
+
+// pickup 將地面物品移入玩家背包（背包空間、負重檢查後）。count 為撿取數量（0 = 整堆；
+// 僅可堆疊物品可部分撿取，剩餘數量留在地面並重新廣播）。quiet 為 true 時不發送失敗訊息。
+func (s *ItemGroundSystem) pickup(sess *net.Session, player *world.PlayerInfo, gndItem *world.GroundItem, count int32, quiet bool) bool {
+	itemInfo := s.deps.Items.Get(gndItem.ItemID)
+	stackable := false
+	if itemInfo != nil {
+		stackable = itemInfo.Stackable || gndItem.ItemID == world.AdenaItemID
+	}
+	take := gndItem.Count
+	if stackable && count > 0 && count < gndItem.Count {
+		take = count
+	}
+	existing := player.Inv.FindByItemID(gndItem.ItemID)
+	wasExisting := existing != nil && stackable
+
+	// 堆疊上限：只撿取背包堆疊放得下的數量，其餘留在地面（金幣超出上限的部分由 giveAdena 存入倉庫）
+	if wasExisting && gndItem.ItemID != world.AdenaItemID {
+		room := stackRoom(s.deps, player, gndItem.ItemID)
+		if room <= 0 {
+			if !quiet {
+				handler.SendSystemMessage(sess, fmt.Sprintf("%s已達攜帶上限。", existing.Name))
+			}
+			return false
+		}
+		take = int32(min(int64(take), room))
+	}
 
 	// 背包空間檢查
-	if player.Inv.IsFull() {
-		handler.SendServerMessage(sess, 263) // 背包已滿
-		return
+	if player.Inv.IsFull() && !wasExisting {
+		if !quiet {
+			handler.SendServerMessage(sess, 263) // 背包已滿
+		}
+		return false
 	}
 
 	// 負重檢查
-	pickupInfo := s.deps.Items.Get(gndItem.ItemID)
-	if pickupInfo != nil {
-		addWeight := pickupInfo.Weight * gndItem.Count
+	if itemInfo != nil {
+		addWeight := itemInfo.Weight * take
 		maxW := world.PlayerMaxWeight(player)
 		if player.Inv.IsOverWeight(addWeight, maxW) {
-			handler.SendServerMessage(sess, 82) // 此物品太重了，所以你無法攜帶。
-			return
+			if !quiet {
+				handler.SendServerMessage(sess, 82) // 此物品太重了，所以你無法攜帶。
+			}
+			return false
 		}
 	}
 
-	// 從世界移除
-	s.deps.World.RemoveGroundItem(objectID)
+	if take < gndItem.Count {
+		// 部分撿取：剩餘數量留在地面
+		gndItem.Count -= take
+		if itemInfo != nil {
+			gndItem.Name = groundDisplayName(itemInfo.Name, gndItem.EnchantLvl, gndItem.Count)
+		}
+		refreshGroundItem(s.deps, gndItem)
+	} else {
+		// 從世界移除
+		s.deps.World.RemoveGroundItem(gndItem.ID)
+
+		// 廣播移除給附近玩家
+		nearby := s.deps.World.GetNearbyPlayersAt(gndItem.X, gndItem.Y, gndItem.MapID)
+		for _, viewer := range nearby {
+			handler.SendRemoveObject(viewer.Session, gndItem.ID)
+		}
+	}
 
-	// 廣播移除給附近玩家
-	nearby := s.deps.World.GetNearbyPlayersAt(gndItem.X, gndItem.Y, gndItem.MapID)
-	for _, viewer := range nearby {
-		handler.SendRemoveObject(viewer.Session, gndItem.ID)
+	// 金幣：依身上上限入袋，超出部分存入個人倉庫
+	if gndItem.ItemID == world.AdenaItemID {
+		giveAdena(s.deps, player, int64(take))
+		handler.SendWeightUpdate(sess, player)
+		return true
 	}
 
 	// 加入背包
-	itemInfo := s.deps.Items.Get(gndItem.ItemID)
 	itemName := gndItem.Name
 	invGfx := int32(0)
 	weight := int32(0)
-	stackable := false
+	bless := byte(0)
 	if itemInfo != nil {
 		itemName = itemInfo.Name
 		invGfx = itemInfo.InvGfx
 		weight = itemInfo.Weight
-		stackable = itemInfo.Stackable || gndItem.ItemID == world.AdenaItemID
-	}
-
-	existing := player.Inv.FindByItemID(gndItem.ItemID)
-	wasExisting := existing != nil && stackable
-
-	bless := byte(0)
-	if itemInfo != nil {
 		bless = byte(itemInfo.Bless)
 	}
 	invItem := player.Inv.AddItem(
 		gndItem.ItemID,
-		gndItem.Count,
+		take,
 		itemName,
 		invGfx,
 		weight,
@@ -227,6 +327,11 @@ func (s *ItemGroundSystem) PickupItem(sess *net.Session, player *world.PlayerInf
 	invItem.EnchantLvl = gndItem.EnchantLvl
 	if itemInfo != nil {
 		invItem.UseType = itemInfo.UseTypeID
+		// 怪物掉落的裝備預設未鑑定（與直接入袋一致）
+		if gndItem.Loot && !wasExisting &&
+			(itemInfo.Category == data.CategoryWeapon || itemInfo.Category == data.CategoryArmor) {
+			invItem.Identified = false
+		}
 	}
 
 	if wasExisting {
@@ -241,8 +346,190 @@ func (s *ItemGroundSystem) PickupItem(sess *net.Session, player *world.PlayerInf
 	s.deps.Log.Debug("撿取物品",
 		zap.String("player", player.Name),
 		zap.Int32("item_id", gndItem.ItemID),
-		zap.Int32("count", gndItem.Count),
+		zap.Int32("count", take),
+		zap.Bool("auto", quiet),
 	)
+	return true
+}
+
+// spillLoot 背包放不下的怪物掉落物改放在地上 (x, y, mapID)，擁有者為擊殺者並設定優先期間。
+func spillLoot(deps *handler.Deps, killer *world.PlayerInfo, itemInfo *data.ItemInfo, count int32, enchantLvl int8, x, y int32, mapID int16) {
+	gndItem := &world.GroundItem{
+		ID:         world.NextGroundItemID(),
+		ItemID:     itemInfo.ItemID,
+		Count:      count,
+		EnchantLvl: enchantLvl,
+		Name:       groundDisplayName(itemInfo.Name, enchantLvl, count),
+		GrdGfx:     itemInfo.GrdGfx,
+		X:          x,
+		Y:          y,
+		MapID:      mapID,
+		TTL:        5 * 60 * 5, // 5 分鐘（200ms tick）
+		Loot:       true,
+		Grade:      byte(itemInfo.Grade),
+	}
+	tagLoot(deps, gndItem, killer)
+	placeGroundItem(deps, gndItem, itemInfo.Name, itemInfo.Stackable || itemInfo.ItemID == world.AdenaItemID)
+}
+
+// groundDisplayName 組合地面物品顯示名稱（強化值前綴、數量大於 1 時加上數量）。
+func groundDisplayName(name string, enchantLvl int8, count int32) string {
+	if enchantLvl > 0 {
+		name = fmt.Sprintf("+%d %s", enchantLvl, name)
+	} else if enchantLvl < 0 {
+		name = fmt.Sprintf("%d %s", enchantLvl, name)
+	}
+	if count > 1 {
+		name = fmt.Sprintf("%s (%d)", name, count)
+	}
+	return name
+}
+
+// placeGroundItem 將物品放到地面並廣播給附近玩家，回傳地面上的物件。
+// [gameplay] ground_stack 開啟且為可堆疊物品時，併入同格相同的物品堆（同物品、強化值、擁有權，
+// 合併後不超過堆疊上限，見 World.FindGroundStack）：累加數量、更新名稱、延長存在時間，只廣播該物品堆。
+// baseName 為未含強化值與數量的物品名稱。
+func placeGroundItem(deps *handler.Deps, gndItem *world.GroundItem, baseName string, stackable bool) *world.GroundItem {
+	if stackable && deps.Config.Gameplay.GroundStack {
+		if pile := deps.World.FindGroundStack(gndItem); pile != nil {
+			pile.Count += gndItem.Count
+			pile.Name = groundDisplayName(baseName, pile.EnchantLvl, pile.Count)
+			if pile.TTL > 0 {
+				if gndItem.TTL <= 0 {
+					pile.TTL = 0 // 新放入的物品不會消失 → 整堆不消失
+				} else {
+					pile.TTL = max(pile.TTL, gndItem.TTL)
+				}
+			}
+			pile.OwnerTicks = max(pile.OwnerTicks, gndItem.OwnerTicks)
+			refreshGroundItem(deps, pile)
+			return pile
+		}
+	}
+	deps.World.AddGroundItem(gndItem)
+
+	// 廣播給附近玩家（含自己）
+	nearby := deps.World.GetNearbyPlayersAt(gndItem.X, gndItem.Y, gndItem.MapID)
+	for _, viewer := range nearby {
+		handler.SendDropItem(viewer.Session, gndItem)
+	}
+	return gndItem
+}
+
+// refreshGroundItem 地面物品數量或名稱變動後重新廣播（同一物件 ID 先移除再放置）。
+func refreshGroundItem(deps *handler.Deps, gndItem *world.GroundItem) {
+	nearby := deps.World.GetNearbyPlayersAt(gndItem.X, gndItem.Y, gndItem.MapID)
+	for _, viewer := range nearby {
+		handler.SendRemoveObject(viewer.Session, gndItem.ID)
+		handler.SendDropItem(viewer.Session, gndItem)
+	}
+}
+
+// LootCorpse 拾取怪物屍體內的掉落物（loot_corpse 模式）。擁有者優先期間內僅限擁有者或其隊友。
+// 放得下的物品逐一移入背包，放不下的留在屍體內；拾取者過濾清單內的物品也留在屍體內（僅此拾取者略過，
+// 擁有者與隊友仍可拾取）。取空後移除屍體並廣播。
+func (s *ItemGroundSystem) LootCorpse(sess *net.Session, player *world.PlayerInfo, corpseID int32) {
+	if player.Dead {
+		return
+	}
+	corpse := s.deps.World.GetGroundItem(corpseID)
+	if corpse == nil || !corpse.IsCorpse() {
+		return
+	}
+	if player.MapID != corpse.MapID || groundDist(player, corpse) > 3 {
+		return
+	}
+	if !s.canLoot(player, corpse) {
+		handler.SendSystemMessage(sess, "此屍體目前僅限擊殺者或其隊友拾取。")
+		return
+	}
+
+	remaining, full := takeCorpseLoot(corpse.Corpse, player.LootFilter, func(loot world.LootEntry) bool {
+		itemInfo := s.deps.Items.Get(loot.ItemID)
+		// 物品資料不存在：視為已取走（丟棄）
+		return itemInfo == nil || addLootToInventory(s.deps, player, itemInfo, loot)
+	})
+	if len(remaining) > 0 {
+		corpse.Corpse = remaining
+		if full {
+			handler.SendServerMessage(sess, 263) // 背包已滿／超重，剩餘物品留在屍體內
+		}
+		return
+	}
+
+	s.deps.World.RemoveGroundItem(corpse.ID)
+	nearby := s.deps.World.GetNearbyPlayersAt(corpse.X, corpse.Y, corpse.MapID)
+	handler.BroadcastToPlayers(nearby, handler.BuildRemoveObject(corpse.ID))
+
+	s.deps.Log.Debug("拾取屍體",
+		zap.String("player", player.Name),
+		zap.Int32("corpse_id", corpse.ID),
+	)
+}
+
+// takeCorpseLoot 依拾取者過濾清單分配屍體內容：過濾清單內的物品原樣保留（不影響其他可拾取者），
+// 其餘交給 take 入袋，take 回傳 false（放不下）者保留並令 full 為 true。
+func takeCorpseLoot(loot []world.LootEntry, filter map[int32]bool, take func(world.LootEntry) bool) (remaining []world.LootEntry, full bool) {
+	remaining = make([]world.LootEntry, 0, len(loot))
+	for _, entry := range loot {
+		if filter[entry.ItemID] {
+			remaining = append(remaining, entry)
+			continue
+		}
+		if !take(entry) {
+			remaining = append(remaining, entry)
+			full = true
+		}
+	}
+	return remaining, full
+}
+
+// spawnLootCorpse 在 NPC 死亡位置放置屍體，內含擲骰結果（擁有者為擊殺者）。無掉落時不放置。
+// 回傳本次擲出的掉落物。
+func spawnLootCorpse(deps *handler.Deps, npc *world.NpcInfo, killer *world.PlayerInfo) []world.LootEntry {
+	loot := rollDrops(deps, npc)
+	if len(loot) == 0 {
+		return nil
+	}
+	sec := deps.Config.Gameplay.LootCorpseSec
+	if sec <= 0 {
+		sec = 60
+	}
+	corpse := &world.GroundItem{
+		ID:     world.NextGroundItemID(),
+		Count:  1,
+		Name:   fmt.Sprintf("%s的屍體", npc.Name),
+		GrdGfx: deps.Config.Gameplay.LootCorpseGfx,
+		X:      npc.X,
+		Y:      npc.Y,
+		MapID:  npc.MapID,
+		TTL:    sec * 5,
+		Corpse: loot,
+	}
+	tagLoot(deps, corpse, killer)
+	deps.World.AddGroundItem(corpse)
+
+	nearby := deps.World.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID)
+	for _, viewer := range nearby {
+		handler.SendDropItem(viewer.Session, corpse)
+	}
+	return loot
+}
+
+// groundDist 回傳玩家與地面物品的 Chebyshev 距離。
+func groundDist(player *world.PlayerInfo, gndItem *world.GroundItem) int32 {
+	dx := player.X - gndItem.X
+	dy := player.Y - gndItem.Y
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	if dy > dx {
+		return dy
+	}
+	return dx
 }
 
 // SendAddItem 需要的匯出確認 — 已有 handler.SendAddItem（預設不帶 itemInfo 時使用 item 內部資料）。
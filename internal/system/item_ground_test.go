@@ -0,0 +1,64 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestTakeCorpseLootKeepsFilteredItemsForOthers(t *testing.T) {
+	loot := []world.LootEntry{
+		{ItemID: 40308, Count: 100},
+		{ItemID: 40014, Count: 1}, // 在拾取者過濾清單中
+		{ItemID: 40010, Count: 2},
+	}
+	filter := map[int32]bool{40014: true}
+	var taken []int32
+	remaining, full := takeCorpseLoot(loot, filter, func(e world.LootEntry) bool {
+		taken = append(taken, e.ItemID)
+		return true
+	})
+	if full {
+		t.Fatal("full = true, want false")
+	}
+	if len(taken) != 2 || taken[0] != 40308 || taken[1] != 40010 {
+		t.Fatalf("taken = %v, want [40308 40010]", taken)
+	}
+	if len(remaining) != 1 || remaining[0].ItemID != 40014 {
+		t.Fatalf("remaining = %v, want the filtered entry", remaining)
+	}
+
+	// 另一名無過濾清單的隊友可拾取剩餘物品
+	remaining, _ = takeCorpseLoot(remaining, nil, func(world.LootEntry) bool { return true })
+	if len(remaining) != 0 {
+		t.Fatalf("second looter left %v", remaining)
+	}
+}
+
+func TestTakeCorpseLootReportsFullBag(t *testing.T) {
+	loot := []world.LootEntry{{ItemID: 1, Count: 1}, {ItemID: 2, Count: 1}}
+	remaining, full := takeCorpseLoot(loot, nil, func(e world.LootEntry) bool { return e.ItemID == 1 })
+	if !full {
+		t.Fatal("full = false, want true")
+	}
+	if len(remaining) != 1 || remaining[0].ItemID != 2 {
+		t.Fatalf("remaining = %v, want item 2", remaining)
+	}
+}
+
+func TestCanLootHonoursPartySnapshot(t *testing.T) {
+	s := &ItemGroundSystem{}
+	item := &world.GroundItem{OwnerID: 1, OwnerTicks: 10, OwnerParty: []int32{1, 2}}
+	for _, c := range []struct {
+		charID int32
+		want   bool
+	}{{1, true}, {2, true}, {3, false}} {
+		if got := s.canLoot(&world.PlayerInfo{CharID: c.charID}, item); got != c.want {
+			t.Errorf("canLoot(char %d) = %v, want %v", c.charID, got, c.want)
+		}
+	}
+	item.OwnerTicks = 0 // 優先期間結束：任何人可撿取
+	if !s.canLoot(&world.PlayerInfo{CharID: 3}, item) {
+		t.Error("released item should be lootable by anyone")
+	}
+}
@@ -2,7 +2,6 @@ package system
 
 import (
 	"fmt"
-	"math/rand"
 	"strconv"
 	"time"
 
@@ -46,7 +45,7 @@ func (s *ItemUseSystem) UseConsumable(sess *net.Session, player *world.PlayerInf
 			// Java ref: Potion.UseHeallingPotion — 總是消耗、總是播放音效/訊息。
 			// 高斯隨機 ±20%: healHp *= (gaussian/5 + 1)
 			if pot.Amount > 0 {
-				healAmt := float64(pot.Amount) * (rand.NormFloat64()/5.0 + 1.0)
+				healAmt := float64(pot.Amount) * (world.RandNorm()/5.0 + 1.0)
 				if healAmt < 1 {
 					healAmt = 1
 				}
@@ -71,7 +70,7 @@ func (s *ItemUseSystem) UseConsumable(sess *net.Session, player *world.PlayerInf
 			if pot.Amount > 0 {
 				mpAmt := pot.Amount
 				if pot.Range > 0 {
-					mpAmt = pot.Amount + rand.Intn(pot.Range)
+					mpAmt = pot.Amount + world.RandInt(pot.Range)
 				}
 				if player.MP < player.MaxMP {
 					player.MP += int16(mpAmt)
@@ -239,6 +238,7 @@ func (s *ItemUseSystem) EnchantItem(sess *net.Session, r *packet.Reader, player
 		Category:     category,
 		WeaponChance: s.deps.Config.Enchant.WeaponChance,
 		ArmorChance:  s.deps.Config.Enchant.ArmorChance,
+		MaxEnchant:   maxEnchantLevel(s.deps.Config.Enchant.MaxEnchant),
 	})
 
 	// 消耗卷軸
@@ -259,7 +259,7 @@ func (s *ItemUseSystem) EnchantItem(sess *net.Session, r *packet.Reader, player
 
 	switch result.Result {
 	case "success":
-		target.EnchantLvl += int8(result.Amount)
+		target.EnchantLvl = world.ClampEnchant(int(target.EnchantLvl) + result.Amount)
 		handler.SendItemStatusUpdate(sess, target, targetInfo)
 		handler.SendItemNameUpdate(sess, target, targetInfo)
 		sendEffectOnPlayer(sess, player.CharID, 2583) // 衝裝成功 GFX
@@ -278,6 +278,11 @@ func (s *ItemUseSystem) EnchantItem(sess *net.Session, r *packet.Reader, player
 
 		s.deps.Log.Info(fmt.Sprintf("衝裝成功  角色=%s  道具=%s  衝裝等級=%d", player.Name, targetInfo.Name, target.EnchantLvl))
 
+		if s.shouldAnnounceEnchant(target.EnchantLvl) {
+			s.announceEnchant(player, fmt.Sprintf("\\f2恭喜！【%s】成功將【%s】衝裝至 +%d！",
+				player.Name, targetInfo.Name, target.EnchantLvl))
+		}
+
 	case "nochange":
 		// S_ServerMessage 160: "%0%s 發出強烈 %1 光芒但 %2"
 		handler.SendServerMessageArgs(sess, 160, itemLogName, lightColor, "$248")
@@ -303,9 +308,14 @@ func (s *ItemUseSystem) EnchantItem(sess *net.Session, r *packet.Reader, player
 
 		s.deps.Log.Info(fmt.Sprintf("衝裝碎裂  角色=%s  道具=%s", player.Name, targetInfo.Name))
 
+		if s.shouldAnnounceEnchant(target.EnchantLvl) {
+			s.announceEnchant(player, fmt.Sprintf("\\f3可惜！【%s】的【+%d %s】在衝裝時蒸發了…",
+				player.Name, target.EnchantLvl, targetInfo.Name))
+		}
+
 	case "minus":
 		// 詛咒卷軸: -N
-		target.EnchantLvl -= int8(result.Amount)
+		target.EnchantLvl = world.ClampEnchant(int(target.EnchantLvl) - result.Amount)
 		handler.SendItemStatusUpdate(sess, target, targetInfo)
 		handler.SendItemNameUpdate(sess, target, targetInfo)
 
@@ -319,6 +329,25 @@ func (s *ItemUseSystem) EnchantItem(sess *net.Session, r *packet.Reader, player
 	}
 }
 
+// shouldAnnounceEnchant 衝裝等級是否達到 [enchant] announce_level 公告門檻。
+func (s *ItemUseSystem) shouldAnnounceEnchant(lvl int8) bool {
+	threshold := s.deps.Config.Enchant.AnnounceLevel
+	return threshold > 0 && int(lvl) >= threshold
+}
+
+// announceEnchant 公告衝裝結果：announce_scope = "global" 送全服，否則送畫面內玩家（含自己）。
+func (s *ItemUseSystem) announceEnchant(player *world.PlayerInfo, msg string) {
+	data := handler.BuildGreenMessage(msg)
+	if s.deps.Config.Enchant.AnnounceScope == "global" {
+		s.deps.World.AllPlayers(func(p *world.PlayerInfo) {
+			p.Session.Send(data)
+		})
+		return
+	}
+	player.Session.Send(data)
+	handler.BroadcastToPlayers(s.deps.World.GetNearbyPlayers(player.X, player.Y, player.MapID, player.SessionID), data)
+}
+
 // ---------- 鑑定卷軸 ----------
 
 // IdentifyItem 處理鑑定卷軸使用。
@@ -473,6 +502,11 @@ func (s *ItemUseSystem) UseTeleportScroll(sess *net.Session, r *packet.Reader, p
 		}
 	}
 
+	// 地圖限制（與傳送術相同）：書籤傳送需 escapable，隨機傳送需 teleportable
+	if s.teleportBlocked(sess, player, target == nil) {
+		return
+	}
+
 	if target != nil {
 		// 書籤傳送
 		removed := player.Inv.RemoveItem(invItem.ObjectID, 1)
@@ -494,7 +528,7 @@ func (s *ItemUseSystem) UseTeleportScroll(sess *net.Session, r *packet.Reader, p
 
 		s.deps.Log.Info(fmt.Sprintf("書籤傳送  角色=%s  書籤=%s  x=%d  y=%d  地圖=%d", player.Name, target.Name, target.X, target.Y, target.MapID))
 	} else {
-		// 無書籤 → 200 格內隨機傳送 (Java: randomLocation(200, true))
+		// 無書籤 → 半徑內隨機傳送 (Java: randomLocation(200, true))；半徑依卷軸設定
 		removed := player.Inv.RemoveItem(invItem.ObjectID, 1)
 		if removed {
 			handler.SendRemoveInventoryItem(sess, invItem.ObjectID)
@@ -503,13 +537,14 @@ func (s *ItemUseSystem) UseTeleportScroll(sess *net.Session, r *packet.Reader, p
 		}
 		handler.SendWeightUpdate(sess, player)
 
+		radius := s.teleportScrollRadius(invItem.ItemID)
 		curMap := player.MapID
 		newX := player.X
 		newY := player.Y
-		minRX := player.X - 200
-		maxRX := player.X + 200
-		minRY := player.Y - 200
-		maxRY := player.Y + 200
+		minRX := player.X - radius
+		maxRX := player.X + radius
+		minRY := player.Y - radius
+		maxRY := player.Y + radius
 		if s.deps.MapData != nil {
 			if mi := s.deps.MapData.GetInfo(curMap); mi != nil {
 				if minRX < mi.StartX {
@@ -554,6 +589,18 @@ func (s *ItemUseSystem) UseTeleportScroll(sess *net.Session, r *packet.Reader, p
 	}
 }
 
+// teleportScrollRadius 回傳傳送卷軸的隨機傳送半徑：etcitem teleport_radius，
+// 未設定時使用 [gameplay] teleport_scroll_radius（預設 200）。
+func (s *ItemUseSystem) teleportScrollRadius(itemID int32) int32 {
+	if info := s.deps.Items.Get(itemID); info != nil && info.TeleportRadius > 0 {
+		return info.TeleportRadius
+	}
+	if r := s.deps.Config.Gameplay.TeleportScrollRadius; r > 0 {
+		return int32(r)
+	}
+	return 200
+}
+
 // UseHomeScroll 處理回家卷軸使用。
 // Java ref: C_ItemUSe.java lines 1503-1511, L1Teleport.teleport()
 func (s *ItemUseSystem) UseHomeScroll(sess *net.Session, player *world.PlayerInfo, invItem *world.InvItem) {
@@ -561,12 +608,14 @@ func (s *ItemUseSystem) UseHomeScroll(sess *net.Session, player *world.PlayerInf
 		return
 	}
 
-	// 取得回家目的地（依地圖和座標找最近城鎮，非死亡重生點）
-	loc := s.deps.Scripting.GetHomeScrollLocation(int(player.MapID), int(player.X), int(player.Y))
-	if loc == nil {
-		loc = &scripting.RespawnLocation{X: 33089, Y: 33397, Map: 4}
+	// 禁止脫出的地圖不可使用回家卷軸
+	if s.teleportBlocked(sess, player, false) {
+		return
 	}
 
+	// 取得回家目的地（主大陸找最近城鎮，其他地圖查回城座標表）
+	loc := s.deps.Getback.Home(player.MapID, player.X, player.Y, player.Lawful)
+
 	// 取消交易
 	if s.deps.Trade != nil {
 		s.deps.Trade.CancelIfActive(player)
@@ -589,9 +638,9 @@ func (s *ItemUseSystem) UseHomeScroll(sess *net.Session, player *world.PlayerInf
 	}
 
 	// 傳送到重生點
-	handler.TeleportPlayer(sess, player, int32(loc.X), int32(loc.Y), int16(loc.Map), 5, s.deps)
+	handler.TeleportPlayer(sess, player, loc.X, loc.Y, loc.MapID, 5, s.deps)
 
-	s.deps.Log.Info(fmt.Sprintf("回家卷軸  角色=%s  目標=(%d,%d) 地圖=%d", player.Name, loc.X, loc.Y, loc.Map))
+	s.deps.Log.Info(fmt.Sprintf("回家卷軸  角色=%s  目標=(%d,%d) 地圖=%d", player.Name, loc.X, loc.Y, loc.MapID))
 }
 
 // UseFixedTeleportScroll 處理指定傳送卷軸使用。
@@ -601,6 +650,11 @@ func (s *ItemUseSystem) UseFixedTeleportScroll(sess *net.Session, player *world.
 		return
 	}
 
+	// 禁止脫出的地圖不可使用指定傳送卷軸
+	if s.teleportBlocked(sess, player, false) {
+		return
+	}
+
 	// 取消交易
 	if s.deps.Trade != nil {
 		s.deps.Trade.CancelIfActive(player)
@@ -629,110 +683,291 @@ func (s *ItemUseSystem) UseFixedTeleportScroll(sess *net.Session, player *world.
 		player.Name, itemInfo.Name, itemInfo.LocX, itemInfo.LocY, itemInfo.LocMapID))
 }
 
-// ---------- 掉落系統 ----------
+// teleportBlocked 檢查目前地圖是否禁止卷軸傳送（Java: L1Map.isEscapable / isTeleportable）。
+// random=true 為原地圖隨機傳送（檢查 teleportable），否則為離開地圖的傳送（檢查 escapable）。
+// 被禁止時發送拒絕訊息並解除客戶端傳送鎖定，卷軸不消耗。
+func (s *ItemUseSystem) teleportBlocked(sess *net.Session, player *world.PlayerInfo, random bool) bool {
+	if s.deps.MapData == nil {
+		return false
+	}
+	mi := s.deps.MapData.GetInfo(player.MapID)
+	if mi == nil {
+		return false
+	}
+	switch {
+	case random && !mi.Teleportable:
+		handler.SendServerMessage(sess, 276) // "在此無法使用傳送。"
+	case !random && !mi.Escapable:
+		handler.SendServerMessage(sess, 79)
+	default:
+		return false
+	}
+	handler.SendParalysis(sess, handler.TeleportUnlock)
+	return true
+}
 
-// GiveDrops 為擊殺的 NPC 擲骰掉落物品並加入擊殺者背包。
-func (s *ItemUseSystem) GiveDrops(killer *world.PlayerInfo, npcID int32) {
-	if s.deps.Drops == nil {
-		return
+// ---------- 指定目標道具 ----------
+
+// defaultTargetItemRange 指定目標道具的預設距離（etcitem use_range 與技能 ranged 皆未設定時）。
+const defaultTargetItemRange = 10
+
+// UseTargetedItem 處理需指定目標的消耗品（復活卷軸、輔助魔法卷軸）。回傳 true 表示已消耗。
+// C_USE_ITEM 接續資料: [D targetObjectID]（0 或自己 = 對自己使用）。
+// 目標須為同地圖、距離內的玩家；use_type "res" 只能對死亡玩家使用，其餘只能對存活玩家使用。
+// 目標無效時發送訊息並不消耗道具。
+func (s *ItemUseSystem) UseTargetedItem(sess *net.Session, r *packet.Reader, player *world.PlayerInfo, invItem *world.InvItem, itemInfo *data.ItemInfo) bool {
+	if player.Dead {
+		return false
 	}
-	dropList := s.deps.Drops.Get(npcID)
-	if dropList == nil {
-		return
+	skill := s.deps.Skills.Get(itemInfo.UseSkill)
+	if skill == nil {
+		s.deps.Log.Warn("指定目標道具技能不存在",
+			zap.Int32("item_id", invItem.ItemID), zap.Int32("use_skill", itemInfo.UseSkill))
+		return false
 	}
+	res := itemInfo.UseType == "res"
 
-	dropRate := s.deps.Config.Rates.DropRate
-	goldRate := s.deps.Config.Rates.GoldRate
+	target := player
+	if targetID := r.ReadD(); targetID != 0 && targetID != player.CharID {
+		target = s.deps.World.GetByCharID(targetID)
+		if target == nil {
+			handler.SendSystemMessage(sess, "只能對玩家使用此道具。")
+			return false
+		}
+	}
+	if res && target == player {
+		handler.SendSystemMessage(sess, "請選擇要復活的對象。")
+		return false
+	}
+	if target.MapID != player.MapID || chebyshevDist(player.X, player.Y, target.X, target.Y) > s.targetItemRange(itemInfo, skill) {
+		handler.SendSystemMessage(sess, "目標距離太遠。")
+		return false
+	}
 
-	for _, drop := range dropList {
-		chance := drop.Chance
-		if drop.ItemID == world.AdenaItemID {
-			if goldRate > 0 {
-				chance = int(float64(chance) * goldRate)
-			}
-		} else {
-			if dropRate > 0 {
-				chance = int(float64(chance) * dropRate)
-			}
+	if res {
+		if !target.Dead {
+			handler.SendSystemMessage(sess, "只能對死亡的玩家使用此道具。")
+			return false
 		}
-		if chance > 1000000 {
-			chance = 1000000
+		if mi := s.deps.MapData.GetInfo(player.MapID); mi != nil && !mi.Resurrection {
+			handler.SendSystemMessage(sess, "此地區無法使用復活道具。")
+			return false
+		}
+		if !s.deps.Skill.ResurrectPlayer(target, player, skill.SkillID) {
+			return false
 		}
+	} else {
+		if target.Dead {
+			handler.SendSystemMessage(sess, "無法對死亡的玩家使用此道具。")
+			return false
+		}
+		if skill.BuffDuration <= 0 {
+			s.deps.Log.Debug("unhandled targeted item skill",
+				zap.Int32("item_id", invItem.ItemID), zap.Int32("use_skill", skill.SkillID))
+			return false
+		}
+		if !s.deps.Skill.ApplyGMBuff(target, skill.SkillID) {
+			return false
+		}
+	}
+
+	removed := player.Inv.RemoveItem(invItem.ObjectID, 1)
+	if removed {
+		handler.SendRemoveInventoryItem(sess, invItem.ObjectID)
+	} else {
+		handler.SendItemCountUpdate(sess, invItem)
+	}
+	handler.SendWeightUpdate(sess, player)
 
-		roll := world.RandInt(1000000)
-		if roll >= chance {
+	if skill.CastGfx > 0 {
+		nearby := s.deps.World.GetNearbyPlayersAt(target.X, target.Y, target.MapID)
+		handler.BroadcastToPlayers(nearby, handler.BuildSkillEffect(target.CharID, skill.CastGfx))
+	}
+
+	s.deps.Log.Info(fmt.Sprintf("指定目標道具  角色=%s  道具=%s  目標=%s  技能ID=%d",
+		player.Name, itemInfo.Name, target.Name, skill.SkillID))
+	return true
+}
+
+// targetItemRange 指定目標道具的最大距離：etcitem use_range > 技能 ranged > 預設值。
+func (s *ItemUseSystem) targetItemRange(itemInfo *data.ItemInfo, skill *data.SkillInfo) int32 {
+	if itemInfo.UseRange > 0 {
+		return itemInfo.UseRange
+	}
+	if skill.Ranged > 0 {
+		return int32(skill.Ranged)
+	}
+	return defaultTargetItemRange
+}
+
+// ---------- 掉落系統 ----------
+
+// GiveDrops 為擊殺的 NPC 擲骰掉落物品並加入擊殺者背包；放不下的改放在地上
+// （full_bag_drop：擊殺者腳下或怪物死亡處），不會消失。
+// 回傳本次擲出的掉落物（頭目擊殺公告用）；擊殺者過濾清單內的物品直接捨棄，不列入回傳值。
+func (s *ItemUseSystem) GiveDrops(killer *world.PlayerInfo, npc *world.NpcInfo) []world.LootEntry {
+	drops := rollDrops(s.deps, npc)
+	x, y, mapID := killer.X, killer.Y, killer.MapID
+	if s.deps.Config.Gameplay.FullBagDrop == "corpse" {
+		x, y, mapID = npc.X, npc.Y, npc.MapID
+	}
+	spilled := false
+	kept := drops[:0]
+	for _, loot := range drops {
+		// 掉落物過濾（.filter）：直接捨棄，不入袋也不掉在地上
+		if killer.LootFilter[loot.ItemID] {
 			continue
 		}
-
-		if killer.Inv.IsFull() {
-			break
+		kept = append(kept, loot)
+		itemInfo := s.deps.Items.Get(loot.ItemID)
+		// 背包已滿或超重：掉落物放在地上（擁有者優先撿取）
+		if !addLootToInventory(s.deps, killer, itemInfo, loot) {
+			spillLoot(s.deps, killer, itemInfo, loot.Count, loot.EnchantLvl, x, y, mapID)
+			spilled = true
 		}
+	}
+	if spilled && s.deps.Config.Gameplay.FullBagNotice {
+		handler.SendSystemMessage(killer.Session, "背包已滿，部分掉落物已放在地上。")
+	}
+	return kept
+}
 
-		qty := int32(drop.Min)
-		if drop.Max > drop.Min {
-			qty = int32(drop.Min + world.RandInt(drop.Max-drop.Min+1))
+// rollDrops 依掉落表與目前倍率擲骰，再擲全域掉落（drop_list global_drops，不套用倍率），
+// 回傳實際掉落的物品（僅含物品資料存在者）。
+func rollDrops(deps *handler.Deps, npc *world.NpcInfo) []world.LootEntry {
+	if deps.Drops == nil {
+		return nil
+	}
+
+	dropRate := deps.Config.Rates.DropRate
+	goldRate := deps.Config.Rates.GoldRate
+	if deps.RateEvent != nil {
+		dropRate = deps.RateEvent.DropRate()
+		goldRate = deps.RateEvent.GoldRate()
+	}
+
+	var result []world.LootEntry
+	for _, drop := range deps.Drops.Get(npc.NpcID) {
+		if loot, ok := rollDrop(deps, drop, dropRate, goldRate); ok {
+			result = append(result, loot)
 		}
-		if qty <= 0 {
-			qty = 1
+	}
+	for i := range deps.Drops.Global() {
+		g := &deps.Drops.Global()[i]
+		if !g.Applies(npc.MapID, npc.Level) {
+			continue
 		}
-
-		if drop.ItemID == world.AdenaItemID && goldRate > 0 {
-			qty = int32(float64(qty) * goldRate)
-			if qty <= 0 {
-				qty = 1
-			}
+		if loot, ok := rollDrop(deps, g.DropItem, 0, 0); ok {
+			result = append(result, loot)
 		}
+	}
+	return result
+}
 
-		itemInfo := s.deps.Items.Get(drop.ItemID)
-		if itemInfo == nil {
-			continue
+// rollDrop 擲骰單一掉落項目；dropRate/goldRate <= 0 時不調整機率與金幣數量。
+func rollDrop(deps *handler.Deps, drop data.DropItem, dropRate, goldRate float64) (world.LootEntry, bool) {
+	chance := drop.Chance
+	if drop.ItemID == world.AdenaItemID {
+		if goldRate > 0 {
+			chance = int(float64(chance) * goldRate)
 		}
+	} else {
+		if dropRate > 0 {
+			chance = int(float64(chance) * dropRate)
+		}
+	}
+	if chance > 1000000 {
+		chance = 1000000
+	}
 
-		stackable := itemInfo.Stackable || drop.ItemID == world.AdenaItemID
-		existing := killer.Inv.FindByItemID(drop.ItemID)
-		wasExisting := existing != nil && stackable
+	roll := world.RandInt(1000000)
+	if roll >= chance {
+		return world.LootEntry{}, false
+	}
 
-		item := killer.Inv.AddItem(
-			drop.ItemID,
-			qty,
-			itemInfo.Name,
-			itemInfo.InvGfx,
-			itemInfo.Weight,
-			stackable,
-			byte(itemInfo.Bless),
-		)
-		item.EnchantLvl = int8(drop.EnchantLevel)
-		item.UseType = itemInfo.UseTypeID
-		// 怪物掉落的裝備預設未鑑定（暗名、無屬性）
-		if itemInfo.Category == data.CategoryWeapon || itemInfo.Category == data.CategoryArmor {
-			item.Identified = false
-		}
+	qty := int32(drop.Min)
+	if drop.Max > drop.Min {
+		qty = int32(drop.Min + world.RandInt(drop.Max-drop.Min+1))
+	}
+	if qty <= 0 {
+		qty = 1
+	}
 
-		if wasExisting {
-			handler.SendItemCountUpdate(killer.Session, item)
-		} else {
-			handler.SendAddItem(killer.Session, item)
+	if drop.ItemID == world.AdenaItemID && goldRate > 0 {
+		qty = int32(float64(qty) * goldRate)
+		if qty <= 0 {
+			qty = 1
 		}
-		handler.SendWeightUpdate(killer.Session, killer)
+	}
 
-		// 通知玩家掉落
-		if drop.ItemID == world.AdenaItemID {
-			msg := fmt.Sprintf("獲得 %d 金幣", qty)
-			handler.SendGlobalChat(killer.Session, 9, msg)
-		} else {
-			name := itemInfo.Name
-			if drop.EnchantLevel > 0 {
-				name = fmt.Sprintf("+%d %s", drop.EnchantLevel, name)
-			}
-			if qty > 1 {
-				msg := fmt.Sprintf("獲得 %s (%d)", name, qty)
-				handler.SendGlobalChat(killer.Session, 9, msg)
-			} else {
-				msg := fmt.Sprintf("獲得 %s", name)
-				handler.SendGlobalChat(killer.Session, 9, msg)
-			}
+	if deps.Items.Get(drop.ItemID) == nil {
+		return world.LootEntry{}, false
+	}
+	return world.LootEntry{
+		ItemID:     drop.ItemID,
+		Count:      qty,
+		EnchantLvl: world.ClampEnchant(drop.EnchantLevel),
+	}, true
+}
+
+// addLootToInventory 將一筆怪物掉落物加入玩家背包並通知。
+// 背包已滿或超重時不加入並回傳 false（由呼叫端決定留在地面或屍體內）。
+func addLootToInventory(deps *handler.Deps, player *world.PlayerInfo, itemInfo *data.ItemInfo, loot world.LootEntry) bool {
+	qty := loot.Count
+	stackable := itemInfo.Stackable || loot.ItemID == world.AdenaItemID
+	existing := player.Inv.FindByItemID(loot.ItemID)
+	wasExisting := existing != nil && stackable
+
+	if (player.Inv.IsFull() && !wasExisting) ||
+		player.Inv.IsOverWeight(itemInfo.Weight*qty, world.PlayerMaxWeight(player)) {
+		return false
+	}
+
+	// 金幣：超出身上上限的部分存入個人倉庫
+	if loot.ItemID == world.AdenaItemID {
+		if giveAdena(deps, player, int64(qty)) > 0 {
+			handler.SendWeightUpdate(player.Session, player)
+			handler.SendGlobalChat(player.Session, 9, fmt.Sprintf("獲得 %d 金幣", qty))
 		}
+		return true
+	}
+
+	item := player.Inv.AddItem(
+		loot.ItemID,
+		qty,
+		itemInfo.Name,
+		itemInfo.InvGfx,
+		itemInfo.Weight,
+		stackable,
+		byte(itemInfo.Bless),
+	)
+	item.EnchantLvl = loot.EnchantLvl
+	item.UseType = itemInfo.UseTypeID
+	// 怪物掉落的裝備預設未鑑定（暗名、無屬性）
+	if !wasExisting && (itemInfo.Category == data.CategoryWeapon || itemInfo.Category == data.CategoryArmor) {
+		item.Identified = false
+	}
+
+	if wasExisting {
+		handler.SendItemCountUpdate(player.Session, item)
+	} else {
+		handler.SendAddItem(player.Session, item)
 	}
+	handler.SendWeightUpdate(player.Session, player)
+
+	// 通知玩家掉落
+	name := itemInfo.Name
+	if loot.EnchantLvl > 0 {
+		name = fmt.Sprintf("+%d %s", loot.EnchantLvl, name)
+	}
+	if qty > 1 {
+		msg := fmt.Sprintf("獲得 %s (%d)", name, qty)
+		handler.SendGlobalChat(player.Session, 9, msg)
+	} else {
+		msg := fmt.Sprintf("獲得 %s", name)
+		handler.SendGlobalChat(player.Session, 9, msg)
+	}
+	return true
 }
 
 // ---------- 加速/勇敢效果 ----------
@@ -946,6 +1181,14 @@ func enchantScrollBless(itemID int32, yamlBless int) int {
 	return yamlBless
 }
 
+// maxEnchantLevel 回傳有效衝裝上限（設定 0 或超出 int8 範圍時為 127）。
+func maxEnchantLevel(cfgMax int) int {
+	if cfgMax <= 0 || cfgMax > 127 {
+		return 127
+	}
+	return cfgMax
+}
+
 // ---------- 領域專用封包 ----------
 
 func sendHpUpdate(sess *net.Session, player *world.PlayerInfo) {
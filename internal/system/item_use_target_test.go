@@ -0,0 +1,157 @@
+package system
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/net/packet"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// targetSkillRecorder 記錄指定目標道具觸發的復活與 buff。
+type targetSkillRecorder struct {
+	handler.SkillManager
+	resurrected []*world.PlayerInfo
+	buffed      []*world.PlayerInfo
+}
+
+func (r *targetSkillRecorder) ResurrectPlayer(target, _ *world.PlayerInfo, _ int32) bool {
+	r.resurrected = append(r.resurrected, target)
+	target.Dead = false
+	return true
+}
+
+func (r *targetSkillRecorder) ApplyGMBuff(target *world.PlayerInfo, _ int32) bool {
+	r.buffed = append(r.buffed, target)
+	return true
+}
+
+type targetItemFixture struct {
+	s      *ItemUseSystem
+	skill  *targetSkillRecorder
+	user   *world.PlayerInfo
+	ally   *world.PlayerInfo
+	scroll *world.InvItem
+}
+
+func newTargetItemFixture(t *testing.T) *targetItemFixture {
+	t.Helper()
+	deps := newTestDeps(t)
+	deps.World = world.NewState()
+	skills, err := data.LoadSkillTable(filepath.Join("..", "..", "data", "yaml", "skill_list.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps.Skills = skills
+	mapPath := filepath.Join(t.TempDir(), "map_list.yaml")
+	if err := os.WriteFile(mapPath, []byte(`maps:
+  - {map_id: 4, start_x: 32600, end_x: 32800, start_y: 32700, end_y: 32900, resurrection: true}
+  - {map_id: 5, start_x: 32600, end_x: 32800, start_y: 32700, end_y: 32900, resurrection: false}
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if deps.MapData, err = data.LoadMapData(mapPath, t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	rec := &targetSkillRecorder{}
+	deps.Skill = rec
+
+	user := &world.PlayerInfo{
+		SessionID: 1, Session: newTestSession(t, 1), CharID: 1, Name: "user",
+		X: 32700, Y: 32800, MapID: 4, HP: 100, MaxHP: 100, Inv: world.NewInventory(),
+	}
+	ally := &world.PlayerInfo{
+		SessionID: 2, Session: newTestSession(t, 2), CharID: 2, Name: "ally",
+		X: 32703, Y: 32800, MapID: 4, HP: 100, MaxHP: 100, Inv: world.NewInventory(),
+	}
+	deps.World.AddPlayer(user)
+	deps.World.AddPlayer(ally)
+	scroll := user.Inv.AddItem(40089, 5, "復活卷軸", 0, 0, true, 1)
+	return &targetItemFixture{s: NewItemUseSystem(deps), skill: rec, user: user, ally: ally, scroll: scroll}
+}
+
+// use 模擬 C_USE_ITEM 接續資料 [D targetObjectID]。
+func (f *targetItemFixture) use(info *data.ItemInfo, targetID int32) bool {
+	buf := make([]byte, 5)
+	binary.LittleEndian.PutUint32(buf[1:], uint32(targetID))
+	return f.s.UseTargetedItem(f.user.Session, packet.NewReader(buf), f.user, f.scroll, info)
+}
+
+var (
+	resScrollInfo  = &data.ItemInfo{ItemID: 40089, Name: "復活卷軸", UseType: "res", UseSkill: 18}
+	buffScrollInfo = &data.ItemInfo{ItemID: 40089, Name: "通暢氣脈術卷軸", UseType: "normal", UseSkill: 26}
+)
+
+func TestTargetedItemResurrectsDeadAlly(t *testing.T) {
+	f := newTargetItemFixture(t)
+	f.ally.Dead = true
+
+	if !f.use(resScrollInfo, f.ally.CharID) {
+		t.Fatal("resurrection scroll on a dead ally in range was not consumed")
+	}
+	if len(f.skill.resurrected) != 1 || f.skill.resurrected[0] != f.ally {
+		t.Fatalf("resurrected %v, want the ally", f.skill.resurrected)
+	}
+	if f.scroll.Count != 4 {
+		t.Errorf("scroll count = %d, want 4", f.scroll.Count)
+	}
+}
+
+func TestTargetedItemRejectsInvalidTargets(t *testing.T) {
+	tests := []struct {
+		name   string
+		info   *data.ItemInfo
+		setup  func(f *targetItemFixture)
+		target func(f *targetItemFixture) int32
+	}{
+		{"復活存活玩家", resScrollInfo, func(*targetItemFixture) {}, allyID},
+		{"復活自己", resScrollInfo, func(f *targetItemFixture) { f.ally.Dead = true }, func(*targetItemFixture) int32 { return 0 }},
+		{"目標不存在", resScrollInfo, func(f *targetItemFixture) { f.ally.Dead = true }, func(*targetItemFixture) int32 { return 999 }},
+		{"超出距離", resScrollInfo, func(f *targetItemFixture) { f.ally.Dead = true; f.ally.X = f.user.X + 11 }, allyID},
+		{"不同地圖", resScrollInfo, func(f *targetItemFixture) { f.ally.Dead = true; f.ally.MapID = 5 }, allyID},
+		{"禁止復活地圖", resScrollInfo, func(f *targetItemFixture) { f.ally.Dead = true; f.user.MapID, f.ally.MapID = 5, 5 }, allyID},
+		{"buff 死亡玩家", buffScrollInfo, func(f *targetItemFixture) { f.ally.Dead = true }, allyID},
+		{"buff 超出預設距離", buffScrollInfo, func(f *targetItemFixture) { f.ally.Y = f.user.Y + defaultTargetItemRange + 1 }, allyID},
+		{"使用者已死亡", buffScrollInfo, func(f *targetItemFixture) { f.user.Dead = true }, allyID},
+	}
+	for _, tt := range tests {
+		f := newTargetItemFixture(t)
+		tt.setup(f)
+		sentPackets(f.user.Session) // 清除前置封包
+		if f.use(tt.info, tt.target(f)) {
+			t.Errorf("%s: item consumed", tt.name)
+		}
+		if f.scroll.Count != 5 {
+			t.Errorf("%s: scroll count = %d, want 5", tt.name, f.scroll.Count)
+		}
+		if len(f.skill.resurrected)+len(f.skill.buffed) != 0 {
+			t.Errorf("%s: effect applied to an invalid target", tt.name)
+		}
+		if !f.user.Dead && len(sentPackets(f.user.Session)) == 0 {
+			t.Errorf("%s: no rejection message", tt.name)
+		}
+	}
+}
+
+func allyID(f *targetItemFixture) int32 { return f.ally.CharID }
+
+func TestTargetedBuffItemAppliesToTargetOrSelf(t *testing.T) {
+	f := newTargetItemFixture(t)
+
+	if !f.use(buffScrollInfo, f.ally.CharID) {
+		t.Fatal("buff scroll on a living ally was not consumed")
+	}
+	if !f.use(buffScrollInfo, 0) {
+		t.Fatal("buff scroll without a target was not consumed")
+	}
+	if len(f.skill.buffed) != 2 || f.skill.buffed[0] != f.ally || f.skill.buffed[1] != f.user {
+		t.Fatalf("buffed %v, want ally then self", f.skill.buffed)
+	}
+	if f.scroll.Count != 3 {
+		t.Errorf("scroll count = %d, want 3", f.scroll.Count)
+	}
+}
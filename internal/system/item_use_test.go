@@ -0,0 +1,32 @@
+package system
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/l1jgo/server/internal/data"
+)
+
+func TestTeleportScrollRadius(t *testing.T) {
+	deps := newTestDeps(t)
+	yaml := filepath.Join("..", "..", "data", "yaml")
+	items, err := data.LoadItemTable(filepath.Join(yaml, "weapon_list.yaml"), filepath.Join(yaml, "armor_list.yaml"), filepath.Join(yaml, "etcitem_list.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps.Items = items
+	s := NewItemUseSystem(deps)
+
+	// 未設定 [gameplay] teleport_scroll_radius 時退回 200
+	if r := s.teleportScrollRadius(40100); r != 200 {
+		t.Errorf("40100 radius = %d, want 200", r)
+	}
+	deps.Config.Gameplay.TeleportScrollRadius = 150
+	if r := s.teleportScrollRadius(40100); r != 150 {
+		t.Errorf("40100 radius = %d, want config 150", r)
+	}
+	// 象牙塔瞬間移動卷軸以 etcitem teleport_radius 覆寫
+	if r := s.teleportScrollRadius(40099); r != 50 {
+		t.Errorf("40099 radius = %d, want 50", r)
+	}
+}
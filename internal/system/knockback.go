@@ -0,0 +1,79 @@
+package system
+
+import (
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// knockbackDest 計算技能擊退/拉近的目的地。
+// 沿施法者與目標連線方向逐格推進（拉近則反向），遇到不可通行或被佔用的格子時提前停止，
+// 因此實際位移可能短於 skill.KnockbackDist。回傳目的地座標與實際移動格數。
+func knockbackDest(ws *world.State, maps *data.MapDataTable, skill *data.SkillInfo,
+	casterX, casterY, x, y int32, mapID int16) (int32, int32, int) {
+	if skill.Knockback == data.KnockbackNone || (casterX == x && casterY == y) {
+		return x, y, 0
+	}
+	sx, sy := sign32(x-casterX), sign32(y-casterY)
+	if skill.Knockback == data.KnockbackPull {
+		sx, sy = -sx, -sy
+	}
+	h := int(handler.CalcHeading(0, 0, sx, sy))
+
+	moved := 0
+	for moved < skill.KnockbackDist {
+		nx, ny := x+sx, y+sy
+		// 拉近時不可越過或踩到施法者
+		if skill.Knockback == data.KnockbackPull && nx == casterX && ny == casterY {
+			break
+		}
+		if maps != nil && !maps.IsPassable(mapID, x, y, h) {
+			break
+		}
+		if ws.OccupantAt(nx, ny, mapID) > 0 {
+			break
+		}
+		x, y = nx, ny
+		moved++
+	}
+	return x, y, moved
+}
+
+// knockbackNpc 對 NPC 套用技能擊退/拉近，逐格廣播移動封包給附近玩家。
+func knockbackNpc(deps *handler.Deps, skill *data.SkillInfo, casterX, casterY int32, npc *world.NpcInfo) {
+	if npc.Dead || skill.Knockback == data.KnockbackNone {
+		return
+	}
+	ws := deps.World
+	destX, destY, moved := knockbackDest(ws, deps.MapData, skill, casterX, casterY, npc.X, npc.Y, npc.MapID)
+	if moved == 0 {
+		return
+	}
+	h := handler.CalcHeading(npc.X, npc.Y, destX, destY)
+	for npc.X != destX || npc.Y != destY {
+		npcExecuteMove(ws, npc, npc.X+sign32(destX-npc.X), npc.Y+sign32(destY-npc.Y), h, deps.MapData)
+	}
+}
+
+// knockbackPlayer 對玩家套用技能擊退/拉近。
+// 以同地圖傳送同步位置：附近玩家看到角色移至新座標，目標客戶端也一併更新自身位置。
+func knockbackPlayer(deps *handler.Deps, skill *data.SkillInfo, casterX, casterY int32, target *world.PlayerInfo) {
+	if target.Dead || skill.Knockback == data.KnockbackNone || target.Session == nil {
+		return
+	}
+	destX, destY, moved := knockbackDest(deps.World, deps.MapData, skill, casterX, casterY, target.X, target.Y, target.MapID)
+	if moved == 0 {
+		return
+	}
+	handler.TeleportPlayer(target.Session, target, destX, destY, target.MapID, target.Heading, deps)
+}
+
+func sign32(v int32) int32 {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
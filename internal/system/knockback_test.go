@@ -0,0 +1,52 @@
+package system
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestKnockbackDest(t *testing.T) {
+	ws := world.NewState()
+	push := &data.SkillInfo{Knockback: data.KnockbackPush, KnockbackDist: 3}
+	pull := &data.SkillInfo{Knockback: data.KnockbackPull, KnockbackDist: 5}
+
+	// 推離施法者，無阻擋時走滿距離
+	if x, y, n := knockbackDest(ws, nil, push, 100, 100, 101, 100, 4); x != 104 || y != 100 || n != 3 {
+		t.Errorf("push east = %d,%d moved %d, want 104,100 moved 3", x, y, n)
+	}
+	// 斜向推離
+	if x, y, n := knockbackDest(ws, nil, push, 100, 100, 99, 99, 4); x != 96 || y != 96 || n != 3 {
+		t.Errorf("push north-west = %d,%d moved %d, want 96,96 moved 3", x, y, n)
+	}
+	// 拉近停在施法者前一格
+	if x, y, n := knockbackDest(ws, nil, pull, 100, 100, 103, 100, 4); x != 101 || y != 100 || n != 2 {
+		t.Errorf("pull = %d,%d moved %d, want 101,100 moved 2", x, y, n)
+	}
+	// 路徑被佔用時縮短位移
+	blocker := NewNpcFromTemplate(&data.NpcTemplate{NpcID: 45060, HP: 10}, nil, 103, 100, 4, 0)
+	ws.AddNpc(blocker)
+	if x, y, n := knockbackDest(ws, nil, push, 100, 100, 101, 100, 4); x != 102 || y != 100 || n != 1 {
+		t.Errorf("blocked push = %d,%d moved %d, want 102,100 moved 1", x, y, n)
+	}
+	// 與施法者同格、或技能未設定擊退時不移動
+	if _, _, n := knockbackDest(ws, nil, push, 100, 100, 100, 100, 4); n != 0 {
+		t.Errorf("same tile moved %d", n)
+	}
+	if _, _, n := knockbackDest(ws, nil, &data.SkillInfo{}, 100, 100, 101, 100, 4); n != 0 {
+		t.Errorf("no knockback moved %d", n)
+	}
+}
+
+func TestEarthquakeKnocksBack(t *testing.T) {
+	skills, err := data.LoadSkillTable(filepath.Join("..", "..", "data", "yaml", "skill_list.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk := skills.Get(62)
+	if sk == nil || sk.Knockback != data.KnockbackPush || sk.KnockbackDist != 1 {
+		t.Fatalf("skill 62 knockback = %+v", sk)
+	}
+}
@@ -0,0 +1,107 @@
+package system
+
+import (
+	"fmt"
+
+	"github.com/l1jgo/server/internal/net"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// holdLinkDead 連線意外中斷時讓角色暫留世界（linkdead），讓進行中的戰鬥公平結算。
+// 保留期間角色無法操作（沒有連線），怪物仍可攻擊；到期後由 tickLinkDead 存檔並移出。
+// 主動登出/踢除、安全區域、已死亡或 [character] linkdead_sec = 0 時不保留。
+// 回傳 true 表示已進入保留，呼叫端不應執行移除流程。
+func (s *InputSystem) holdLinkDead(sess *net.Session) bool {
+	if s.deps == nil || sess.Logout {
+		return false
+	}
+	sec := s.deps.Config.Character.LinkDeadSec
+	if sec <= 0 {
+		return false
+	}
+	player := s.worldState.GetBySession(sess.ID)
+	if player == nil || player.Dead || player.LinkDead {
+		return false
+	}
+	if s.mapData != nil && s.mapData.IsSafetyZone(player.MapID, player.X, player.Y) {
+		return false
+	}
+
+	// 交易無法在斷線狀態完成，立即取消並歸還雙方物品
+	s.cancelTradeOnDisconnect(player)
+
+	player.LinkDead = true
+	player.LinkDeadTicks = sec * 5 // 秒 → ticks
+	if s.linkDead == nil {
+		s.linkDead = make(map[uint64]*net.Session)
+	}
+	s.linkDead[sess.ID] = sess
+	s.log.Info(fmt.Sprintf("斷線保留  角色=%s  帳號=%s  秒數=%d", player.Name, sess.AccountName, sec))
+	return true
+}
+
+// tickLinkDead 斷線保留倒數，到期者存檔並移出世界。
+func (s *InputSystem) tickLinkDead() {
+	for id, sess := range s.linkDead {
+		player := s.worldState.GetBySession(id)
+		if player != nil {
+			player.LinkDeadTicks--
+			if player.LinkDeadTicks > 0 {
+				continue
+			}
+		}
+		delete(s.linkDead, id)
+		s.removeDisconnected(sess)
+	}
+}
+
+// heldSession 回傳帳號保留中的（已關閉）連線，沒有則回傳 nil。
+func (s *InputSystem) heldSession(account string) *net.Session {
+	for _, sess := range s.linkDead {
+		if sess.AccountName == account {
+			return sess
+		}
+	}
+	return nil
+}
+
+// Holding 回傳帳號是否有斷線保留中的角色。
+func (s *InputSystem) Holding(account string) bool {
+	return s.heldSession(account) != nil
+}
+
+// Reattach 新連線進入世界時接回保留中的角色：同一角色則把現有 PlayerInfo 換到新連線
+// （仇恨、在線名額一併轉移），不經存檔/重新載入；選擇其他角色則照常存檔移出保留中的角色。
+func (s *InputSystem) Reattach(sess *net.Session, charID int32) *world.PlayerInfo {
+	old := s.heldSession(sess.AccountName)
+	if old == nil {
+		return nil
+	}
+	delete(s.linkDead, old.ID)
+	player := s.worldState.GetBySession(old.ID)
+	if player == nil || player.CharID != charID {
+		s.removeDisconnected(old)
+		return nil
+	}
+
+	s.worldState.Logins.Release(old.ID)
+	s.worldState.RebindSession(player, sess)
+	player.LinkDead = false
+	player.LinkDeadTicks = 0
+	s.log.Info(fmt.Sprintf("斷線保留結束（接回）  角色=%s  帳號=%s", player.Name, sess.AccountName))
+	return player
+}
+
+// Reclaim 立即結束帳號的斷線保留（GM 踢除時）：角色存檔後移出世界並標記離線。
+func (s *InputSystem) Reclaim(account string) bool {
+	for id, sess := range s.linkDead {
+		if sess.AccountName != account {
+			continue
+		}
+		delete(s.linkDead, id)
+		s.removeDisconnected(sess)
+		s.log.Info(fmt.Sprintf("斷線保留結束（踢除）  帳號=%s", account))
+		return true
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/l1jgo/server/internal/net"
+	"github.com/l1jgo/server/internal/world"
+	"go.uber.org/zap"
+)
+
+func TestReattachSwapsSessionOnHeldPlayer(t *testing.T) {
+	ws := world.NewState()
+	oldSess := newTestSession(t, 1)
+	oldSess.AccountName = "acct"
+	held := &world.PlayerInfo{SessionID: 1, Session: oldSess, CharID: 7, Name: "held",
+		X: 1000, Y: 1000, MapID: 4, HP: 33, LinkDead: true, LinkDeadTicks: 20}
+	ws.AddPlayer(held)
+	ws.Logins.Admit(oldSess, "acct")
+
+	s := &InputSystem{worldState: ws, log: zap.NewNop(),
+		linkDead: map[uint64]*net.Session{1: oldSess}}
+	if !s.Holding("acct") || s.Holding("other") {
+		t.Fatal("Holding does not reflect the held account")
+	}
+
+	newSess := newTestSession(t, 2)
+	newSess.AccountName = "acct"
+	ws.Logins.Admit(newSess, "acct")
+
+	got := s.Reattach(newSess, 7)
+	if got != held {
+		t.Fatalf("Reattach returned %v, want the held PlayerInfo", got)
+	}
+	if held.Session != newSess || held.LinkDead || held.LinkDeadTicks != 0 || held.HP != 33 {
+		t.Errorf("held player not resumed in place: %+v", held)
+	}
+	if s.Holding("acct") {
+		t.Error("account still held after reattach")
+	}
+	if ws.Logins.SessionOf("acct") != newSess {
+		t.Error("old session still holds the online slot")
+	}
+}
@@ -0,0 +1,38 @@
+package system
+
+import (
+	"time"
+
+	coresys "github.com/l1jgo/server/internal/core/system"
+	"github.com/l1jgo/server/internal/handler"
+)
+
+// LoginQueueSystem 定期放行登入排隊：在線人數低於 [server] max_online 時，
+// 依排隊順序完成登入。Phase 3 (PostUpdate)。
+type LoginQueueSystem struct {
+	deps      *handler.Deps
+	tickCount int
+	interval  int // 每 N tick 檢查一次
+}
+
+func NewLoginQueueSystem(deps *handler.Deps, intervalTicks int) *LoginQueueSystem {
+	return &LoginQueueSystem{
+		deps:     deps,
+		interval: intervalTicks,
+	}
+}
+
+func (s *LoginQueueSystem) Phase() coresys.Phase { return coresys.PhasePostUpdate }
+
+func (s *LoginQueueSystem) Update(_ time.Duration) {
+	s.tickCount++
+	if s.tickCount < s.interval {
+		return
+	}
+	s.tickCount = 0
+
+	if s.deps.World.Logins.QueueLen() == 0 {
+		return
+	}
+	handler.AdmitQueuedLogins(s.deps)
+}
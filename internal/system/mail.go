@@ -7,6 +7,7 @@ import (
 
 	"github.com/l1jgo/server/internal/handler"
 	"github.com/l1jgo/server/internal/net"
+	"github.com/l1jgo/server/internal/net/packet"
 	"github.com/l1jgo/server/internal/persist"
 	"github.com/l1jgo/server/internal/world"
 	"go.uber.org/zap"
@@ -66,6 +67,12 @@ func (s *MailSystem) ReadMail(sess *net.Session, player *world.PlayerInfo, mailI
 	// 發送內容
 	readType := byte(0x10) + byte(mailType)
 	handler.SendMailContent(sess, mailID, readType, mail.Content)
+
+	// 待領包裹提示
+	if mail.ParcelStatus == persist.ParcelUnclaimed && mail.ExpiresAt != nil {
+		handler.SendSystemMessage(sess, fmt.Sprintf("此信件附有包裹，請於 %s 前至郵差處領取。",
+			mail.ExpiresAt.Format("2006-01-02 15:04")))
+	}
 }
 
 // SendMail 寄出一封一般信件。
@@ -73,8 +80,21 @@ func (s *MailSystem) SendMail(sess *net.Session, player *world.PlayerInfo, recei
 	// 解析主旨與內文
 	subject, content := parseMailText(rawText)
 
-	// 扣除寄信費用
-	if !consumeAdena(player, int32(s.deps.Config.Gameplay.MailSendCost)) {
+	// 郵差 NPC 預先選擇的包裹附件（寄出前重新驗證）
+	attachments, parcelAdena, ok := s.collectParcel(sess, player)
+	if !ok {
+		handler.SendMailResult(sess, 0x20, false)
+		return
+	}
+	isParcel := len(attachments) > 0 || parcelAdena > 0
+
+	// 扣除寄信費用（包裹附帶的金幣也必須足夠）
+	cost := int32(s.deps.Config.Gameplay.MailSendCost)
+	if player.Inv.GetAdena() < cost+parcelAdena {
+		handler.SendServerMessage(sess, 189) // "金幣不足。"
+		return
+	}
+	if !consumeAdena(player, cost) {
 		handler.SendServerMessage(sess, 189) // "金幣不足。"
 		return
 	}
@@ -147,13 +167,23 @@ func (s *MailSystem) SendMail(sess *net.Session, player *world.PlayerInfo, recei
 		Subject:    subject,
 		Content:    content,
 	}
-	receiverMailID, err := s.deps.MailRepo.Write(ctx, receiverMail)
+	var receiverMailID int32
+	if isParcel {
+		receiverMailID, err = s.writeParcel(ctx, player, receiverCharID, receiverMail, attachments, parcelAdena)
+	} else {
+		receiverMailID, err = s.deps.MailRepo.Write(ctx, receiverMail)
+	}
 	if err != nil {
 		s.deps.Log.Error("寫入收件信失敗", zap.Error(err))
 		handler.SendMailResult(sess, 0x20, false)
 		return
 	}
 
+	// 包裹寫入成功 — 從寄件者背包移除附件
+	if isParcel {
+		s.removeParcelItems(sess, player)
+	}
+
 	// 通知寄件者（備份）
 	handler.SendMailNotify(sess, player.Name, senderMailID, true, subject)
 
@@ -162,10 +192,14 @@ func (s *MailSystem) SendMail(sess *net.Session, player *world.PlayerInfo, recei
 		handler.SendMailNotify(receiver.Session, player.Name, receiverMailID, false, subject)
 		// 音效通知（skill sound 1091）
 		handler.SendMailSound(receiver.Session, receiver.CharID)
+		if isParcel {
+			handler.SendSystemMessage(receiver.Session,
+				fmt.Sprintf("%s 寄給你一個包裹，請至郵差處領取。", player.Name))
+		}
 	}
 
-	s.deps.Log.Info(fmt.Sprintf("信件寄出  寄件=%s  收件=%s  senderID=%d  receiverID=%d",
-		player.Name, receiverName, senderMailID, receiverMailID))
+	s.deps.Log.Info(fmt.Sprintf("信件寄出  寄件=%s  收件=%s  senderID=%d  receiverID=%d  附件=%d  金幣=%d",
+		player.Name, receiverName, senderMailID, receiverMailID, len(attachments), parcelAdena))
 
 	handler.SendMailResult(sess, 0x20, true)
 }
@@ -184,6 +218,10 @@ func (s *MailSystem) DeleteMail(sess *net.Session, player *world.PlayerInfo, mai
 	if mail == nil || mail.InboxID != player.CharID {
 		return
 	}
+	if isPendingParcel(mail) {
+		handler.SendSystemMessage(sess, "此信件的包裹尚未領取，無法刪除。")
+		return
+	}
 
 	if err := s.deps.MailRepo.Delete(ctx, mailID); err != nil {
 		s.deps.Log.Error("刪除信件失敗", zap.Error(err))
@@ -241,7 +279,7 @@ func (s *MailSystem) BulkDelete(sess *net.Session, player *world.PlayerInfo, sub
 			s.deps.Log.Error("批次刪除查詢失敗", zap.Error(err))
 			continue
 		}
-		if mail == nil || mail.InboxID != player.CharID {
+		if mail == nil || mail.InboxID != player.CharID || isPendingParcel(mail) {
 			continue
 		}
 
@@ -254,8 +292,321 @@ func (s *MailSystem) BulkDelete(sess *net.Session, player *world.PlayerInfo, sub
 	}
 }
 
+// OpenParcel 郵差 NPC 開啟包裹附件選擇視窗（列出可寄送的背包物品）。
+// 由 NPC 動作 "parcel" 呼叫。
+func (s *MailSystem) OpenParcel(sess *net.Session, player *world.PlayerInfo, npcObjID int32) {
+	objIDs := make([]int32, 0, len(player.Inv.Items))
+	for _, it := range player.Inv.Items {
+		if canAttachToParcel(s.deps, it) {
+			objIDs = append(objIDs, it.ObjectID)
+		}
+	}
+	player.ParcelNpcObjID = npcObjID
+	handler.SendParcelPickList(sess, npcObjID, objIDs)
+}
+
+// StageParcel 記錄玩家勾選的包裹附件。物品仍留在背包，寄信成功時才扣除。
+// 由 handler.HandleBuySell 在郵差附件視窗回應時呼叫。
+func (s *MailSystem) StageParcel(sess *net.Session, r *packet.Reader, count int, player *world.PlayerInfo) {
+	player.ParcelNpcObjID = 0
+	player.ParcelItems = nil
+	if count <= 0 || count > 100 {
+		return
+	}
+
+	maxItems := s.deps.Config.Gameplay.MailMaxAttachments
+	entries := make([]world.ParcelEntry, 0, count)
+	seen := make(map[int32]bool, count)
+	itemCount := 0
+	var adena int32
+	for i := 0; i < count; i++ {
+		objID := r.ReadD()
+		qty := r.ReadD()
+		if qty <= 0 {
+			qty = 1
+		}
+		if seen[objID] {
+			continue
+		}
+		invItem := player.Inv.FindByObjectID(objID)
+		if invItem == nil || !canAttachToParcel(s.deps, invItem) {
+			continue
+		}
+		seen[objID] = true
+		if qty > invItem.Count {
+			qty = invItem.Count
+		}
+		if invItem.ItemID == world.AdenaItemID {
+			adena = qty
+		} else {
+			itemCount++
+		}
+		entries = append(entries, world.ParcelEntry{ObjectID: objID, Count: qty})
+	}
+
+	if itemCount > maxItems {
+		handler.SendSystemMessage(sess, fmt.Sprintf("包裹最多只能附帶 %d 件物品。", maxItems))
+		return
+	}
+	if len(entries) == 0 {
+		return
+	}
+
+	player.ParcelItems = entries
+	handler.SendSystemMessage(sess, fmt.Sprintf("已選擇 %d 件物品、%d 金幣作為包裹，請寫信寄出。", itemCount, adena))
+}
+
+// ClaimParcels 領取所有待領包裹：物品放入背包、金幣加入持有金幣。
+// 逾期未領的包裹會先作廢。由 NPC 動作 "parcel-claim" 呼叫。
+func (s *MailSystem) ClaimParcels(sess *net.Session, player *world.PlayerInfo) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if n, err := s.deps.MailRepo.PurgeExpiredParcels(ctx, player.CharID); err != nil {
+		s.deps.Log.Error("清除逾期包裹失敗", zap.Error(err))
+	} else if n > 0 {
+		handler.SendSystemMessage(sess, fmt.Sprintf("有 %d 個包裹已超過領取期限而作廢。", n))
+	}
+
+	parcels, err := s.deps.MailRepo.LoadClaimable(ctx, player.CharID)
+	if err != nil {
+		s.deps.Log.Error("讀取待領包裹失敗", zap.Error(err))
+		return
+	}
+	if len(parcels) == 0 {
+		handler.SendSystemMessage(sess, "目前沒有可領取的包裹。")
+		return
+	}
+
+	claimed := 0
+	for _, m := range parcels {
+		items, err := s.deps.MailRepo.LoadAttachments(ctx, m.ID)
+		if err != nil {
+			s.deps.Log.Error("讀取包裹附件失敗", zap.Error(err))
+			continue
+		}
+		if player.Inv.Size()+parcelSlotsNeeded(s.deps, player.Inv, items) > world.MaxInventorySize {
+			handler.SendServerMessage(sess, 263) // 背包已滿
+			break
+		}
+
+		// 先標記已領取（DB 原子操作），成功後才發放，避免重複領取
+		ok, err := s.deps.MailRepo.MarkClaimed(ctx, m.ID)
+		if err != nil {
+			s.deps.Log.Error("標記包裹已領取失敗", zap.Error(err))
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		for _, it := range items {
+			s.giveAttachment(sess, player, it)
+		}
+		if m.Adena > 0 {
+			giveAdena(s.deps, player, int64(m.Adena))
+		}
+		claimed++
+
+		s.deps.Log.Info(fmt.Sprintf("包裹領取  角色=%s  寄件=%s  mailID=%d  附件=%d  金幣=%d",
+			player.Name, m.Sender, m.ID, len(items), m.Adena))
+	}
+
+	if claimed > 0 {
+		player.Dirty = true
+		handler.ConsolidateInventory(sess, player)
+		handler.SendWeightUpdate(sess, player)
+		handler.SendSystemMessage(sess, fmt.Sprintf("已領取 %d 個包裹。", claimed))
+	}
+}
+
+// NotifyUnread 登入時提示未讀信件數與待領包裹數。
+func (s *MailSystem) NotifyUnread(sess *net.Session, player *world.PlayerInfo) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	unread, err := s.deps.MailRepo.CountUnread(ctx, player.CharID)
+	if err != nil {
+		s.deps.Log.Error("查詢未讀信件失敗", zap.Error(err))
+		return
+	}
+	parcels, err := s.deps.MailRepo.CountClaimable(ctx, player.CharID)
+	if err != nil {
+		s.deps.Log.Error("查詢待領包裹失敗", zap.Error(err))
+		return
+	}
+
+	if unread > 0 {
+		handler.SendSystemMessage(sess, fmt.Sprintf("你有 %d 封未讀信件。", unread))
+	}
+	if parcels > 0 {
+		handler.SendSystemMessage(sess, fmt.Sprintf("你有 %d 個包裹待領取，請至郵差處領取。", parcels))
+	}
+}
+
+// collectParcel 重新驗證暫存的包裹附件，回傳 DB 附件列表與附帶金幣。
+// 背包內容已變動（物品消失、裝備中、數量不足）時回傳 ok=false 並清除暫存。
+func (s *MailSystem) collectParcel(sess *net.Session, player *world.PlayerInfo) (items []persist.MailAttachment, adena int32, ok bool) {
+	if len(player.ParcelItems) == 0 {
+		return nil, 0, true
+	}
+
+	for _, e := range player.ParcelItems {
+		invItem := player.Inv.FindByObjectID(e.ObjectID)
+		if invItem == nil || !canAttachToParcel(s.deps, invItem) || invItem.Count < e.Count {
+			player.ParcelItems = nil
+			handler.SendSystemMessage(sess, "包裹附件已變動，請重新至郵差處選擇。")
+			return nil, 0, false
+		}
+		if invItem.ItemID == world.AdenaItemID {
+			adena += e.Count
+			continue
+		}
+		items = append(items, persist.MailAttachment{
+			ItemID:     invItem.ItemID,
+			Count:      e.Count,
+			EnchantLvl: int16(invItem.EnchantLvl),
+			Bless:      int16(invItem.Bless),
+			Identified: invItem.Identified,
+		})
+	}
+
+	if len(items) > s.deps.Config.Gameplay.MailMaxAttachments {
+		player.ParcelItems = nil
+		handler.SendSystemMessage(sess, fmt.Sprintf("包裹最多只能附帶 %d 件物品。", s.deps.Config.Gameplay.MailMaxAttachments))
+		return nil, 0, false
+	}
+	return items, adena, true
+}
+
+// writeParcel 先寫入 WAL，再將包裹信件與附件寫入收件者信箱。
+func (s *MailSystem) writeParcel(ctx context.Context, player *world.PlayerInfo, receiverCharID int32, m *persist.MailRow, items []persist.MailAttachment, adena int32) (int32, error) {
+	var walEntries []persist.WALEntry
+	for _, it := range items {
+		walEntries = append(walEntries, persist.WALEntry{
+			TxType:     "mail",
+			FromChar:   player.CharID,
+			ToChar:     receiverCharID,
+			ItemID:     it.ItemID,
+			Count:      it.Count,
+			EnchantLvl: it.EnchantLvl,
+		})
+	}
+	if adena > 0 {
+		walEntries = append(walEntries, persist.WALEntry{
+			TxType:     "mail",
+			FromChar:   player.CharID,
+			ToChar:     receiverCharID,
+			ItemID:     world.AdenaItemID,
+			GoldAmount: int64(adena),
+		})
+	}
+	if s.deps.WALRepo != nil {
+		if err := s.deps.WALRepo.WriteWAL(ctx, walEntries); err != nil {
+			return 0, err
+		}
+	}
+
+	expires := m.Date.AddDate(0, 0, s.deps.Config.Gameplay.MailParcelExpireDays)
+	m.Adena = adena
+	m.ExpiresAt = &expires
+	return s.deps.MailRepo.WriteParcel(ctx, m, items)
+}
+
+// removeParcelItems 包裹寄出後從寄件者背包扣除附件並清除暫存。
+func (s *MailSystem) removeParcelItems(sess *net.Session, player *world.PlayerInfo) {
+	for _, e := range player.ParcelItems {
+		invItem := player.Inv.FindByObjectID(e.ObjectID)
+		if invItem == nil {
+			continue
+		}
+		if player.Inv.RemoveItem(e.ObjectID, e.Count) {
+			handler.SendRemoveInventoryItem(sess, e.ObjectID)
+		} else {
+			handler.SendItemCountUpdate(sess, invItem)
+		}
+	}
+	player.ParcelItems = nil
+	player.Dirty = true
+	handler.SendWeightUpdate(sess, player)
+}
+
+// giveAttachment 將一項包裹附件放入背包。
+func (s *MailSystem) giveAttachment(sess *net.Session, player *world.PlayerInfo, it persist.MailAttachment) {
+	itemInfo := s.deps.Items.Get(it.ItemID)
+	name := fmt.Sprintf("item#%d", it.ItemID)
+	invGfx := int32(0)
+	weight := int32(0)
+	stackable := false
+	var useType byte
+	if itemInfo != nil {
+		name = itemInfo.Name
+		invGfx = itemInfo.InvGfx
+		weight = itemInfo.Weight
+		stackable = itemInfo.Stackable
+		useType = itemInfo.UseTypeID
+	}
+
+	if stackable {
+		if existing := parcelStackFor(player.Inv, it); existing != nil {
+			existing.Count += it.Count
+			handler.SendItemCountUpdate(sess, existing)
+			return
+		}
+	}
+
+	// 以不可堆疊方式新增，避免 AddItem 依 ItemID 併入強化值或鑑定狀態不同的堆疊
+	item := player.Inv.AddItem(it.ItemID, it.Count, name, invGfx, weight, false, byte(it.Bless))
+	item.Stackable = stackable
+	item.EnchantLvl = world.ClampEnchant(int(it.EnchantLvl))
+	item.Identified = it.Identified
+	item.UseType = useType
+	handler.SendAddItem(sess, item, itemInfo)
+}
+
 // --- 輔助函式 ---
 
+// canAttachToParcel 判斷背包物品是否可作為包裹附件：沿用交易/倉庫的可轉移檢查
+// （不可交易、封印），並排除裝備中與不可銷毀的物品。
+func canAttachToParcel(deps *handler.Deps, it *world.InvItem) bool {
+	if it.Equipped || !itemTransferable(deps, it) {
+		return false
+	}
+	info := deps.Items.Get(it.ItemID)
+	return info == nil || !info.CantDelete
+}
+
+// parcelStackFor 回傳可併入附件的既有堆疊：只合併同物品、同祝福、同強化、同鑑定狀態
+// 且合併後不超過堆疊上限的未裝備堆疊；沒有時回傳 nil（附件另佔一格）。
+func parcelStackFor(inv *world.Inventory, it persist.MailAttachment) *world.InvItem {
+	for _, cur := range inv.Items {
+		if cur.ItemID == it.ItemID && !cur.Equipped &&
+			cur.Bless == byte(it.Bless) && cur.EnchantLvl == world.ClampEnchant(int(it.EnchantLvl)) &&
+			cur.Identified == it.Identified &&
+			int64(cur.Count)+int64(it.Count) <= world.MaxStackCount {
+			return cur
+		}
+	}
+	return nil
+}
+
+// parcelSlotsNeeded 計算領取附件需要的新背包格數：可堆疊且能併入既有堆疊（parcelStackFor）的附件不佔新格。
+func parcelSlotsNeeded(deps *handler.Deps, inv *world.Inventory, items []persist.MailAttachment) int {
+	slots := 0
+	for _, it := range items {
+		if info := deps.Items.Get(it.ItemID); info != nil && info.Stackable && parcelStackFor(inv, it) != nil {
+			continue
+		}
+		slots++
+	}
+	return slots
+}
+
+// isPendingParcel 判斷信件是否為尚未領取且未逾期的包裹。
+func isPendingParcel(m *persist.MailRow) bool {
+	return m.ParcelStatus == persist.ParcelUnclaimed && m.ExpiresAt != nil && m.ExpiresAt.After(time.Now())
+}
+
 // consumeAdena 扣除玩家金幣。成功回傳 true，不足回傳 false。
 func consumeAdena(player *world.PlayerInfo, amount int32) bool {
 	adena := player.Inv.FindByItemID(world.AdenaItemID)
@@ -0,0 +1,81 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/l1jgo/server/internal/persist"
+	"github.com/l1jgo/server/internal/world"
+)
+
+const mailTestItems = `  - {item_id: 40308, name: 金幣, stackable: true, tradeable: true}
+  - {item_id: 40010, name: 治癒藥水, stackable: true, tradeable: true}
+  - {item_id: 40100, name: 任務信物, tradeable: false}
+  - {item_id: 40200, name: 不可刪除, tradeable: true, cant_delete: true}
+`
+
+func TestCanAttachToParcelMatchesTradeRules(t *testing.T) {
+	deps := newTestDeps(t)
+	deps.Items = newTestItems(t, mailTestItems)
+
+	cases := []struct {
+		name string
+		item *world.InvItem
+		want bool
+	}{
+		{"tradeable", &world.InvItem{ItemID: 40010}, true},
+		{"adena", &world.InvItem{ItemID: 40308}, true},
+		{"equipped", &world.InvItem{ItemID: 40010, Equipped: true}, false},
+		{"sealed", &world.InvItem{ItemID: 40010, Bless: 128}, false},
+		{"untradeable", &world.InvItem{ItemID: 40100}, false},
+		{"cant delete", &world.InvItem{ItemID: 40200}, false},
+	}
+	for _, c := range cases {
+		if got := canAttachToParcel(deps, c.item); got != c.want {
+			t.Errorf("%s: canAttachToParcel = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestParcelStackForOnlyMergesIdenticalStacks(t *testing.T) {
+	inv := world.NewInventory()
+	plain := inv.AddItem(40010, 5, "治癒藥水", 0, 0, true, 1)
+	plain.Identified = true
+
+	att := persist.MailAttachment{ItemID: 40010, Count: 3, Bless: 1, Identified: true}
+	if got := parcelStackFor(inv, att); got != plain {
+		t.Fatalf("identical attachment not merged: got %v", got)
+	}
+
+	for name, a := range map[string]persist.MailAttachment{
+		"enchanted":    {ItemID: 40010, Count: 3, Bless: 1, Identified: true, EnchantLvl: 1},
+		"unidentified": {ItemID: 40010, Count: 3, Bless: 1},
+		"cursed":       {ItemID: 40010, Count: 3, Bless: 2, Identified: true},
+		"overflow":     {ItemID: 40010, Count: world.MaxStackCount, Bless: 1, Identified: true},
+	} {
+		if got := parcelStackFor(inv, a); got != nil {
+			t.Errorf("%s: merged into %+v, want a new stack", name, got)
+		}
+	}
+
+	plain.Equipped = true
+	if got := parcelStackFor(inv, att); got != nil {
+		t.Fatal("merged into an equipped stack")
+	}
+}
+
+func TestParcelSlotsNeededSkipsMergedStacks(t *testing.T) {
+	deps := newTestDeps(t)
+	deps.Items = newTestItems(t, mailTestItems)
+	inv := world.NewInventory()
+	potion := inv.AddItem(40010, 5, "治癒藥水", 0, 0, true, 1)
+	potion.Identified = true
+
+	items := []persist.MailAttachment{
+		{ItemID: 40010, Count: 3, Bless: 1, Identified: true},                // 併入既有藥水
+		{ItemID: 40010, Count: 3, Bless: 1, Identified: true, EnchantLvl: 1}, // 強化值不同：新格
+		{ItemID: 40100, Count: 1, Bless: 1, Identified: true},                // 不可堆疊：新格
+	}
+	if got := parcelSlotsNeeded(deps, inv, items); got != 2 {
+		t.Fatalf("slots needed = %d, want 2", got)
+	}
+}
@@ -0,0 +1,61 @@
+package system
+
+import (
+	"time"
+
+	coresys "github.com/l1jgo/server/internal/core/system"
+	"github.com/l1jgo/server/internal/metrics"
+	"github.com/l1jgo/server/internal/net"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// MetricsSystem 定期取樣世界狀態（在線人數、存活 NPC）與封包流量寫入監控收集器。
+// 僅做原子寫入，HTTP 讀取在獨立 goroutine，不影響遊戲迴圈。Phase 3 (PostUpdate)。
+type MetricsSystem struct {
+	world     *world.State
+	mc        *metrics.Collector
+	tickCount int
+	interval  int // 每 N tick 取樣一次
+
+	lastSample time.Time
+	lastIn     uint64
+	lastOut    uint64
+}
+
+func NewMetricsSystem(ws *world.State, mc *metrics.Collector, intervalTicks int) *MetricsSystem {
+	in, out := net.PacketTotals()
+	return &MetricsSystem{
+		world:      ws,
+		mc:         mc,
+		interval:   intervalTicks,
+		lastSample: time.Now(),
+		lastIn:     in,
+		lastOut:    out,
+	}
+}
+
+func (s *MetricsSystem) Phase() coresys.Phase { return coresys.PhasePostUpdate }
+
+func (s *MetricsSystem) Update(_ time.Duration) {
+	s.tickCount++
+	if s.tickCount < s.interval {
+		return
+	}
+	s.tickCount = 0
+
+	alive := 0
+	for _, npc := range s.world.NpcList() {
+		if !npc.Dead {
+			alive++
+		}
+	}
+	s.mc.SetWorld(s.world.OnlineCount(), s.world.PlayerCount(), alive)
+
+	// 封包速率：與上次取樣的差值除以經過秒數
+	now := time.Now()
+	in, out := net.PacketTotals()
+	if sec := now.Sub(s.lastSample).Seconds(); sec > 0 {
+		s.mc.SetPacketRates(int64(float64(in-s.lastIn)/sec), int64(float64(out-s.lastOut)/sec))
+	}
+	s.lastSample, s.lastIn, s.lastOut = now, in, out
+}
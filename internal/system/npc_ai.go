@@ -1,7 +1,6 @@
 package system
 
 import (
-	"math/rand"
 	"time"
 
 	coresys "github.com/l1jgo/server/internal/core/system"
@@ -17,27 +16,41 @@ import (
 // execution, Lua handles all decision logic. Guard NPCs use a simpler Go-only
 // AI path. Phase 2 (Update).
 type NpcAISystem struct {
-	world *world.State
-	deps  *handler.Deps
+	world  *world.State
+	deps   *handler.Deps
+	active []*world.NpcInfo // 本 tick 需要執行 AI 的 NPC（可重用 buffer）
 }
 
 func NewNpcAISystem(ws *world.State, deps *handler.Deps) *NpcAISystem {
+	if deps.Scripting != nil {
+		deps.Scripting.SetWorldQuery(npcWorldQuery{ws: ws})
+	}
 	return &NpcAISystem{world: ws, deps: deps}
 }
 
 func (s *NpcAISystem) Phase() coresys.Phase { return coresys.PhaseUpdate }
 
 func (s *NpcAISystem) Update(_ time.Duration) {
-	for _, npc := range s.world.NpcList() {
+	// 只處理至少一名玩家視野內的 NPC；遠離所有玩家的 NPC 暫停（計時器不遞減）
+	s.active = s.world.ActiveNpcs(s.active)
+	for _, npc := range s.active {
 		if npc.Dead {
 			continue
 		}
+		flushNpcHpMeter(npc, s.world, s.deps)
 		// Guard AI: separate branch — simple Go logic, no Lua needed.
 		if npc.Impl == "L1Guard" {
 			s.tickGuardAI(npc)
 			continue
 		}
 		if npc.Impl != "L1Monster" {
+			// 其他 NPC（商人等）僅在設定巡邏路線時移動
+			if len(npc.Patrol) > 0 {
+				if npc.MoveTimer > 0 {
+					npc.MoveTimer--
+				}
+				s.tickPatrol(npc)
+			}
 			continue
 		}
 		s.tickMonsterAI(npc)
@@ -70,7 +83,7 @@ func (s *NpcAISystem) tickMonsterAI(npc *world.NpcInfo) {
 	var target *world.PlayerInfo
 	if npc.AggroTarget != 0 {
 		target = s.world.GetBySession(npc.AggroTarget)
-		if target == nil || target.Dead || target.MapID != npc.MapID {
+		if target == nil || target.Dead || target.GMInvisible || target.MapID != npc.MapID {
 			// 當前目標失效 → 從仇恨列表移除，嘗試回退到次高仇恨
 			RemoveHateTarget(npc, npc.AggroTarget)
 			npc.AggroTarget = 0
@@ -78,7 +91,7 @@ func (s *NpcAISystem) tickMonsterAI(npc *world.NpcInfo) {
 			// 嘗試仇恨列表中的下一個目標
 			if nextSID := GetMaxHateTarget(npc); nextSID != 0 {
 				if nextTarget := s.world.GetBySession(nextSID); nextTarget != nil &&
-					!nextTarget.Dead && nextTarget.MapID == npc.MapID {
+					!nextTarget.Dead && !nextTarget.GMInvisible && nextTarget.MapID == npc.MapID {
 					npc.AggroTarget = nextSID
 					target = nextTarget
 				} else {
@@ -96,8 +109,10 @@ func (s *NpcAISystem) tickMonsterAI(npc *world.NpcInfo) {
 	if target == nil && npc.Agro {
 		nearbyPlayers = s.world.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID)
 		bestDist := int32(999)
+		// 霧天縮短索敵範圍（weather.yaml effects.fog.aggro_range_pct）
+		agroRange := s.deps.Weather.AggroRange(s.world.WeatherAt(npc.MapID).Kind, 8)
 		for _, p := range nearbyPlayers {
-			if p.Dead {
+			if p.Dead || p.GMInvisible {
 				continue
 			}
 			// Skip players in safety zones (Java: getZoneType() == 1)
@@ -105,8 +120,12 @@ func (s *NpcAISystem) tickMonsterAI(npc *world.NpcInfo) {
 				s.deps.MapData.IsSafetyZone(p.MapID, p.X, p.Y) {
 				continue
 			}
+			// 登入/傳送保護中不主動索敵
+			if handler.HasSpawnProtect(p) {
+				continue
+			}
 			dist := chebyshev32(npc.X, npc.Y, p.X, p.Y)
-			if dist <= 8 && dist < bestDist {
+			if dist <= agroRange && dist < bestDist {
 				bestDist = dist
 				target = p
 			}
@@ -128,6 +147,12 @@ func (s *NpcAISystem) tickMonsterAI(npc *world.NpcInfo) {
 		}
 	}
 
+	// 無目標且有巡邏路線 → 巡邏取代 Lua 閒晃（失去目標後從目前路點繼續）
+	if target == nil && len(npc.Patrol) > 0 {
+		s.tickPatrol(npc)
+		return
+	}
+
 	// --- Build AIContext for Lua ---
 	targetDist := int32(0)
 	targetID, targetAC, targetLevel := 0, 0, 0
@@ -161,6 +186,7 @@ func (s *NpcAISystem) tickMonsterAI(npc *world.NpcInfo) {
 	}
 
 	ctx := scripting.AIContext{
+		ID:          int(npc.ID),
 		NpcID:       int(npc.NpcID),
 		X:           int(npc.X),
 		Y:           int(npc.Y),
@@ -202,7 +228,7 @@ func (s *NpcAISystem) tickMonsterAI(npc *world.NpcInfo) {
 		case "ranged_attack":
 			if target != nil {
 				s.npcRangedAttack(npc, target)
-				setNpcAtkCooldown(npc)
+				setNpcRangedCooldown(npc)
 			}
 		case "skill":
 			if target != nil {
@@ -212,10 +238,20 @@ func (s *NpcAISystem) tickMonsterAI(npc *world.NpcInfo) {
 		case "move_toward":
 			if target != nil {
 				npcMoveToward(s.world, npc, target.X, target.Y, s.deps.MapData)
-				npc.MoveTimer = calcNpcMoveTicks(npc)
+				npc.MoveTimer = calcNpcChaseTicks(npc)
+			}
+		case "flee":
+			// 往目標反方向移動一格（以外推點作為移動目標）
+			if target != nil {
+				npcMoveToward(s.world, npc, 2*npc.X-target.X, 2*npc.Y-target.Y, s.deps.MapData)
+				npc.MoveTimer = calcNpcChaseTicks(npc)
 			}
 		case "wander":
-			npcWander(s.world, npc, cmd.Dir, s.deps.MapData)
+			radius := npc.WanderRadius
+			if radius <= 0 {
+				radius = int32(s.deps.Config.World.WanderRadius)
+			}
+			npcWander(s.world, npc, cmd.Dir, radius, s.deps.MapData)
 		case "lose_aggro":
 			npc.AggroTarget = 0
 		}
@@ -249,7 +285,7 @@ func (s *NpcAISystem) tickGuardAI(npc *world.NpcInfo) {
 	var target *world.PlayerInfo
 	if npc.AggroTarget != 0 {
 		target = s.world.GetBySession(npc.AggroTarget)
-		if target == nil || target.Dead || target.MapID != npc.MapID {
+		if target == nil || target.Dead || target.GMInvisible || target.MapID != npc.MapID {
 			npc.AggroTarget = 0
 			target = nil
 		}
@@ -258,6 +294,12 @@ func (s *NpcAISystem) tickGuardAI(npc *world.NpcInfo) {
 			npc.AggroTarget = 0
 			target = nil
 		}
+		// 通緝/粉紅名到期或目標進入安全區 → 立即放棄追擊並返回崗位
+		if target != nil && !s.guardShouldPursue(npc, target) {
+			RemoveHateTarget(npc, npc.AggroTarget)
+			npc.AggroTarget = 0
+			target = nil
+		}
 	}
 
 	// --- Target search: scan for wanted players ---
@@ -268,7 +310,7 @@ func (s *NpcAISystem) tickGuardAI(npc *world.NpcInfo) {
 			if p.Dead || p.Invisible {
 				continue
 			}
-			if p.WantedTicks <= 0 && !p.PinkName {
+			if !isGuardWanted(p) || s.inSafetyZone(p) {
 				continue
 			}
 			dist := chebyshev32(npc.X, npc.Y, p.X, p.Y)
@@ -295,25 +337,40 @@ func (s *NpcAISystem) tickGuardAI(npc *world.NpcInfo) {
 			if npc.AttackTimer <= 0 {
 				if npc.Ranged > 1 {
 					s.npcRangedAttack(npc, target)
+					setNpcRangedCooldown(npc)
 				} else {
 					s.npcMeleeAttack(npc, target)
+					setNpcAtkCooldown(npc)
 				}
-				setNpcAtkCooldown(npc)
 			}
 		} else {
 			if npc.MoveTimer <= 0 {
 				npcMoveToward(s.world, npc, target.X, target.Y, s.deps.MapData)
-				moveTicks := calcNpcMoveTicks(npc)
+				moveTicks := calcNpcChaseTicks(npc)
 				npc.MoveTimer = moveTicks
 			}
 		}
 		return
 	}
 
+	// --- No target: patrol route ---
+	// 牽引距離以目前路段（上一個路點 → 目前路點）量測，而非下一個路點：
+	// 長路段或遠離路點 0 的出生點不會觸發瞬移；超出時回到路段起點繼續巡邏。
+	if len(npc.Patrol) > 0 {
+		if patrolLeashDist(npc) > guardLeashRange {
+			prev := npc.Patrol[(npc.PatrolIdx+len(npc.Patrol)-1)%len(npc.Patrol)]
+			s.guardTeleportTo(npc, prev.X, prev.Y)
+			npc.PatrolWait, npc.PatrolStuck = 0, 0
+			return
+		}
+		s.tickPatrol(npc)
+		return
+	}
+
 	// --- No target: return home ---
 	if npc.X != npc.SpawnX || npc.Y != npc.SpawnY {
 		homeDist := chebyshev32(npc.X, npc.Y, npc.SpawnX, npc.SpawnY)
-		if homeDist > 30 {
+		if homeDist > guardLeashRange {
 			s.guardTeleportHome(npc)
 			return
 		}
@@ -325,10 +382,92 @@ func (s *NpcAISystem) tickGuardAI(npc *world.NpcInfo) {
 	}
 }
 
+// guardLeashRange 警衛離開出生點（或巡邏路段）超過此距離時瞬移回去。
+const guardLeashRange = 30
+
+// patrolLeashDist 回傳 NPC 到目前巡邏路段（上一個路點 → PatrolIdx 路點）的最短 Chebyshev 距離。
+func patrolLeashDist(npc *world.NpcInfo) int32 {
+	wp := npc.Patrol[npc.PatrolIdx]
+	d := chebyshev32(npc.X, npc.Y, wp.X, wp.Y)
+	if d <= guardLeashRange {
+		return d // 快速路徑：接近目標路點時不必掃描路段
+	}
+	prev := npc.Patrol[(npc.PatrolIdx+len(npc.Patrol)-1)%len(npc.Patrol)]
+	dx, dy := wp.X-prev.X, wp.Y-prev.Y
+	steps := chebyshev32(prev.X, prev.Y, wp.X, wp.Y)
+	for i := int32(0); i < steps; i++ {
+		x := prev.X + dx*i/steps
+		y := prev.Y + dy*i/steps
+		d = min(d, chebyshev32(npc.X, npc.Y, x, y))
+	}
+	return d
+}
+
+// patrolStuckLimit 連續多少步沒有前進就跳到下一個路點（路點被佔用或被地形卡住）。
+const patrolStuckLimit = 10
+
+// tickPatrol 沿巡邏路線循環移動：抵達路點後停留 PatrolPause ticks 再前往下一個路點。
+// 呼叫端負責遞減 MoveTimer。
+func (s *NpcAISystem) tickPatrol(npc *world.NpcInfo) {
+	if npc.PatrolWait > 0 {
+		npc.PatrolWait--
+		return
+	}
+	if npc.MoveTimer > 0 {
+		return
+	}
+	wp := npc.Patrol[npc.PatrolIdx]
+	if (npc.X == wp.X && npc.Y == wp.Y) || npc.PatrolStuck >= patrolStuckLimit {
+		npc.PatrolIdx = (npc.PatrolIdx + 1) % len(npc.Patrol)
+		npc.PatrolStuck = 0
+		npc.PatrolWait = npc.PatrolPause
+		return
+	}
+	before := chebyshev32(npc.X, npc.Y, wp.X, wp.Y)
+	npcMoveToward(s.world, npc, wp.X, wp.Y, s.deps.MapData)
+	if chebyshev32(npc.X, npc.Y, wp.X, wp.Y) < before {
+		npc.PatrolStuck = 0
+	} else {
+		npc.PatrolStuck++
+	}
+	npc.MoveTimer = calcNpcMoveTicks(npc)
+}
+
+// isGuardWanted reports whether guards should hunt the player on sight
+// (wanted for PK, or temporarily pink-named).
+func isGuardWanted(p *world.PlayerInfo) bool {
+	return p.WantedTicks > 0 || p.PinkName
+}
+
+// guardShouldPursue 判斷守衛是否繼續追擊當前目標。
+// 目標在安全區一律放棄；否則需仍為通緝/粉紅名，或曾攻擊過守衛（仇恨列表中有記錄，反擊）。
+func (s *NpcAISystem) guardShouldPursue(npc *world.NpcInfo, target *world.PlayerInfo) bool {
+	if s.inSafetyZone(target) {
+		return false
+	}
+	if isGuardWanted(target) {
+		return true
+	}
+	return npc.HateList[target.SessionID] > 0
+}
+
+// inSafetyZone 檢查玩家是否站在安全區域（Java: getZoneType() == 1）。
+func (s *NpcAISystem) inSafetyZone(p *world.PlayerInfo) bool {
+	return s.deps.MapData != nil && s.deps.MapData.IsSafetyZone(p.MapID, p.X, p.Y)
+}
+
 // guardTeleportHome instantly moves a guard back to its spawn point.
 func (s *NpcAISystem) guardTeleportHome(npc *world.NpcInfo) {
+	s.guardTeleportTo(npc, npc.SpawnX, npc.SpawnY)
+}
+
+// guardTeleportTo instantly moves a guard to (x, y) on its spawn map.
+func (s *NpcAISystem) guardTeleportTo(npc *world.NpcInfo, x, y int32) {
 	oldX, oldY := npc.X, npc.Y
 
+	// 目的地被佔用或在牆內時，移到最近的可用格子
+	homeX, homeY := handler.FindLandingTile(s.deps, npc.SpawnMapID, x, y, npc.ID)
+
 	// 通知舊位置附近玩家：移除 NPC + 解鎖格子
 	oldNearby := s.world.GetNearbyPlayersAt(oldX, oldY, npc.MapID)
 	rmData := handler.BuildRemoveObject(npc.ID)
@@ -337,17 +476,17 @@ func (s *NpcAISystem) guardTeleportHome(npc *world.NpcInfo) {
 	// Update map passability
 	if s.deps.MapData != nil {
 		s.deps.MapData.SetImpassable(npc.MapID, oldX, oldY, false)
-		s.deps.MapData.SetImpassable(npc.SpawnMapID, npc.SpawnX, npc.SpawnY, true)
+		s.deps.MapData.SetImpassable(npc.SpawnMapID, homeX, homeY, true)
 	}
 
 	// Update position (NPC AOI grid + entity grid)
-	s.world.UpdateNpcPosition(npc.ID, npc.SpawnX, npc.SpawnY, 0)
+	s.world.UpdateNpcPosition(npc.ID, homeX, homeY, 0)
 	npc.MapID = npc.SpawnMapID
 
 	// 通知新位置附近玩家：顯示 NPC + 封鎖格子
 	newNearby := s.world.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID)
 	for _, viewer := range newNearby {
-		sendNpcPack(viewer.Session, npc)
+		handler.SendNpcPack(viewer.Session, npc)
 	}
 }
 
@@ -359,6 +498,11 @@ func (s *NpcAISystem) npcMeleeAttack(npc *world.NpcInfo, target *world.PlayerInf
 		npc.AggroTarget = 0 // NPC 無法攻擊屏障目標，清除仇恨
 		return
 	}
+	// 目標登入/傳送保護中：不受傷害
+	if handler.HasSpawnProtect(target) {
+		npc.AggroTarget = 0
+		return
+	}
 
 	// 被攻擊時解除睡眠
 	if target.Sleeped {
@@ -369,21 +513,24 @@ func (s *NpcAISystem) npcMeleeAttack(npc *world.NpcInfo, target *world.PlayerInf
 		handler.SendParalysis(target.Session, handler.SleepRemove)
 	}
 
-	npc.Heading = calcNpcHeading(npc.X, npc.Y, target.X, target.Y)
+	npc.Heading = handler.CalcHeading(npc.X, npc.Y, target.X, target.Y)
 
 	res := s.deps.Scripting.CalcNpcMelee(scripting.CombatContext{
-		AttackerLevel:  int(npc.Level),
-		AttackerSTR:    int(npc.STR),
-		AttackerDEX:    int(npc.DEX),
-		AttackerWeapon: int(npc.AtkDmg),
-		TargetAC:       int(target.AC),
-		TargetLevel:    int(target.Level),
+		AttackerLevel:   int(npc.Level),
+		AttackerSTR:     int(npc.STR),
+		AttackerDEX:     int(npc.DEX),
+		AttackerWeapon:  int(npc.AtkDmg),
+		TargetAC:        int(target.AC),
+		TargetLevel:     int(target.Level),
+		TargetDodge:     int(target.Dodge),
+		TargetClassType: int(target.ClassType),
 	})
 
 	damage := int32(res.Damage)
 	if !res.IsHit || damage < 0 {
 		damage = 0
 	}
+	damage = clampDamage(s.deps, damage, 0, "npc_melee")
 
 	nearby := s.world.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID)
 
@@ -409,18 +556,13 @@ func (s *NpcAISystem) npcMeleeAttack(npc *world.NpcInfo, target *world.PlayerInf
 				damage = 0
 				// 如果 NPC 被反彈殺死
 				if npc.HP <= 0 {
-					hpData := handler.BuildHpMeter(npc.ID, 0)
-					handler.BroadcastToPlayers(nearby, hpData)
+					broadcastNpcHpMeter(npc, nearby, s.deps)
 					handleNpcDeath(npc, target, nearby, s.deps)
 					npc.AggroTarget = 0
 					return
 				}
-				// 廣播 NPC HP 條
-				hpRatio := int16(0)
-				if npc.MaxHP > 0 {
-					hpRatio = int16((npc.HP * 100) / npc.MaxHP)
-				}
-				handler.BroadcastToPlayers(nearby, handler.BuildHpMeter(npc.ID, hpRatio))
+				// 廣播 NPC HP 條（節流）
+				broadcastNpcHpMeter(npc, nearby, s.deps)
 			}
 		}
 	}
@@ -446,6 +588,9 @@ func (s *NpcAISystem) npcMeleeAttack(npc *world.NpcInfo, target *world.PlayerInf
 	if npc.PoisonAtk > 0 {
 		ApplyNpcPoisonAttack(npc, target, s.world, s.deps)
 	}
+
+	// 模板命中特效（npc_list.yaml on_hit）
+	s.applyNpcOnHit(npc, target)
 }
 
 func (s *NpcAISystem) npcRangedAttack(npc *world.NpcInfo, target *world.PlayerInfo) {
@@ -454,6 +599,11 @@ func (s *NpcAISystem) npcRangedAttack(npc *world.NpcInfo, target *world.PlayerIn
 		npc.AggroTarget = 0
 		return
 	}
+	// 目標登入/傳送保護中：不受傷害
+	if handler.HasSpawnProtect(target) {
+		npc.AggroTarget = 0
+		return
+	}
 
 	// 被攻擊時解除睡眠
 	if target.Sleeped {
@@ -464,21 +614,24 @@ func (s *NpcAISystem) npcRangedAttack(npc *world.NpcInfo, target *world.PlayerIn
 		handler.SendParalysis(target.Session, handler.SleepRemove)
 	}
 
-	npc.Heading = calcNpcHeading(npc.X, npc.Y, target.X, target.Y)
+	npc.Heading = handler.CalcHeading(npc.X, npc.Y, target.X, target.Y)
 
 	res := s.deps.Scripting.CalcNpcRanged(scripting.CombatContext{
-		AttackerLevel:  int(npc.Level),
-		AttackerSTR:    int(npc.STR),
-		AttackerDEX:    int(npc.DEX),
-		AttackerWeapon: int(npc.AtkDmg),
-		TargetAC:       int(target.AC),
-		TargetLevel:    int(target.Level),
+		AttackerLevel:   int(npc.Level),
+		AttackerSTR:     int(npc.STR),
+		AttackerDEX:     int(npc.DEX),
+		AttackerWeapon:  int(npc.AtkDmg),
+		TargetAC:        int(target.AC),
+		TargetLevel:     int(target.Level),
+		TargetDodge:     int(target.Dodge),
+		TargetClassType: int(target.ClassType),
 	})
 
 	damage := int32(res.Damage)
 	if !res.IsHit || damage < 0 {
 		damage = 0
 	}
+	damage = clampDamage(s.deps, damage, 0, "npc_ranged")
 
 	nearby := s.world.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID)
 	rngData := buildNpcRangedAttack(npc.ID, target.CharID, damage, npc.Heading,
@@ -503,6 +656,9 @@ func (s *NpcAISystem) npcRangedAttack(npc *world.NpcInfo, target *world.PlayerIn
 	if npc.PoisonAtk > 0 {
 		ApplyNpcPoisonAttack(npc, target, s.world, s.deps)
 	}
+
+	// 模板命中特效（npc_list.yaml on_hit）
+	s.applyNpcOnHit(npc, target)
 }
 
 // executeNpcSkill handles an NPC using a skill on a player.
@@ -512,6 +668,11 @@ func (s *NpcAISystem) executeNpcSkill(npc *world.NpcInfo, target *world.PlayerIn
 		npc.AggroTarget = 0
 		return
 	}
+	// 目標登入/傳送保護中：不受傷害
+	if handler.HasSpawnProtect(target) {
+		npc.AggroTarget = 0
+		return
+	}
 
 	skill := s.deps.Skills.Get(int32(skillID))
 	if skill == nil {
@@ -527,7 +688,7 @@ func (s *NpcAISystem) executeNpcSkill(npc *world.NpcInfo, target *world.PlayerIn
 		}
 	}
 
-	npc.Heading = calcNpcHeading(npc.X, npc.Y, target.X, target.Y)
+	npc.Heading = handler.CalcHeading(npc.X, npc.Y, target.X, target.Y)
 	nearby := s.world.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID)
 
 	// Spell visual effect: mob-specific gfx_id takes priority, fallback to skill's CastGfx
@@ -553,12 +714,17 @@ func (s *NpcAISystem) executeNpcSkill(npc *world.NpcInfo, target *world.PlayerIn
 			TargetAC:        int(target.AC),
 			TargetLevel:     int(target.Level),
 			TargetMR:        int(target.MR),
+			TargetFireRes:   int(target.FireRes),
+			TargetWaterRes:  int(target.WaterRes),
+			TargetWindRes:   int(target.WindRes),
+			TargetEarthRes:  int(target.EarthRes),
 		}
 		res := s.deps.Scripting.CalcSkillDamage(sctx)
 		damage := int32(res.Damage)
 		if damage < 1 {
 			damage = 1
 		}
+		damage = clampDamage(s.deps, weatherSkillDamage(s.deps, npc.MapID, skill.Attr, damage), skill.MaxDamage, "npc_skill")
 
 		useType := byte(6) // ranged magic
 		if skill.Area > 0 {
@@ -569,6 +735,11 @@ func (s *NpcAISystem) executeNpcSkill(npc *world.NpcInfo, target *world.PlayerIn
 			npc.X, npc.Y, target.X, target.Y)
 		handler.BroadcastToPlayers(nearby, skillAtkData)
 
+		// 屬性魔法命中特效（讓玩家看出被哪種屬性擊中；抗性已於 magic.lua 減傷）
+		if eg := s.elementHitGfx(skill.Attr); eg > 0 {
+			handler.BroadcastToPlayers(nearby, handler.BuildSkillEffect(target.CharID, eg))
+		}
+
 		target.HP -= int16(damage)
 		target.Dirty = true
 		if target.HP <= 0 {
@@ -578,6 +749,9 @@ func (s *NpcAISystem) executeNpcSkill(npc *world.NpcInfo, target *world.PlayerIn
 			return
 		}
 		sendHPUpdate(target.Session, target.HP, target.MaxHP)
+
+		// 擊退/拉近
+		knockbackPlayer(s.deps, skill, npc.X, npc.Y, target)
 	} else {
 		// 非傷害技能（debuff）：發送特效 + 套用 debuff 狀態
 		if gfx > 0 {
@@ -591,6 +765,56 @@ func (s *NpcAISystem) executeNpcSkill(npc *world.NpcInfo, target *world.PlayerIn
 	}
 }
 
+// elementHitGfx 回傳屬性魔法命中特效 GFX（[combat] element_hit_gfx，0 = 無）。
+// attr 為 skill_list 屬性位元：1=地, 2=火, 4=水, 8=風；多重屬性取第一個符合者。
+func (s *NpcAISystem) elementHitGfx(attr int) int32 {
+	gfx := s.deps.Config.Combat.ElementHitGfx
+	for i, bit := range [4]int{1, 2, 4, 8} {
+		if attr&bit != 0 && i < len(gfx) {
+			return int32(gfx[i])
+		}
+	}
+	return 0
+}
+
+// ---------- Lua world queries ----------
+
+// npcWorldQuery 提供 AI 腳本唯讀的周遭查詢（scripting.WorldQuery）。
+type npcWorldQuery struct {
+	ws *world.State
+}
+
+// CountNearbyAllies 計算範圍內同模板的其他存活 NPC 數量（群體 AI 用）。
+func (q npcWorldQuery) CountNearbyAllies(npcID int32, rng int) int {
+	npc := q.ws.GetNpc(npcID)
+	if npc == nil {
+		return 0
+	}
+	n := 0
+	for _, other := range q.ws.GetNearbyNpcs(npc.X, npc.Y, npc.MapID) {
+		if other.ID != npc.ID && other.NpcID == npc.NpcID &&
+			chebyshev32(npc.X, npc.Y, other.X, other.Y) <= int32(rng) {
+			n++
+		}
+	}
+	return n
+}
+
+// CountNearbyPlayers 計算範圍內存活且可見的玩家數量（GM 隱身不計）。
+func (q npcWorldQuery) CountNearbyPlayers(npcID int32, rng int) int {
+	npc := q.ws.GetNpc(npcID)
+	if npc == nil {
+		return 0
+	}
+	n := 0
+	for _, p := range q.ws.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID) {
+		if !p.Dead && !p.GMInvisible && chebyshev32(npc.X, npc.Y, p.X, p.Y) <= int32(rng) {
+			n++
+		}
+	}
+	return n
+}
+
 // ---------- NPC Movement ----------
 
 // npcMoveToward moves NPC 1 tile toward a target position.
@@ -632,7 +856,7 @@ func npcMoveToward(ws *world.State, npc *world.NpcInfo, tx, ty int32, maps *data
 		if c.x == npc.X && c.y == npc.Y {
 			continue
 		}
-		h := calcNpcHeading(npc.X, npc.Y, c.x, c.y)
+		h := handler.CalcHeading(npc.X, npc.Y, c.x, c.y)
 
 		if maps != nil && !maps.IsPassable(npc.MapID, npc.X, npc.Y, int(h)) {
 			continue
@@ -646,7 +870,7 @@ func npcMoveToward(ws *world.State, npc *world.NpcInfo, tx, ty int32, maps *data
 		return
 	}
 	// All candidates blocked — last resort: pass through
-	h := calcNpcHeading(npc.X, npc.Y, mx, my)
+	h := handler.CalcHeading(npc.X, npc.Y, mx, my)
 	if maps == nil || maps.IsPassableIgnoreOccupant(npc.MapID, npc.X, npc.Y, int(h)) {
 		npcExecuteMove(ws, npc, mx, my, h, maps)
 	}
@@ -669,23 +893,33 @@ func npcExecuteMove(ws *world.State, npc *world.NpcInfo, moveX, moveY int32, hea
 }
 
 // npcWander handles idle wandering. dir: 0-7=new direction, -1=continue, -2=toward spawn.
-func npcWander(ws *world.State, npc *world.NpcInfo, dir int, maps *data.MapDataTable) {
+// radius > 0 bounds the drift: a step that would leave the radius around
+// SpawnX/SpawnY is turned back toward spawn instead.
+func npcWander(ws *world.State, npc *world.NpcInfo, dir int, radius int32, maps *data.MapDataTable) {
 	wanderTicks := calcNpcMoveTicks(npc)
 
 	if dir == -1 {
 		// Continue current direction
 	} else if dir == -2 {
-		npc.WanderDir = calcNpcHeading(npc.X, npc.Y, npc.SpawnX, npc.SpawnY)
-		npc.WanderDist = rand.Intn(5) + 2
+		npc.WanderDir = handler.CalcHeading(npc.X, npc.Y, npc.SpawnX, npc.SpawnY)
+		npc.WanderDist = world.RandInt(5) + 2
 	} else {
 		npc.WanderDir = int16(dir)
-		npc.WanderDist = rand.Intn(5) + 2
+		npc.WanderDist = world.RandInt(5) + 2
 	}
 
 	if npc.WanderDist <= 0 {
 		return
 	}
 
+	if radius > 0 && npc.MapID == npc.SpawnMapID {
+		nx := npc.X + npcHeadingDX[npc.WanderDir]
+		ny := npc.Y + npcHeadingDY[npc.WanderDir]
+		if chebyshev32(nx, ny, npc.SpawnX, npc.SpawnY) > radius {
+			npc.WanderDir = handler.CalcHeading(npc.X, npc.Y, npc.SpawnX, npc.SpawnY)
+		}
+	}
+
 	if maps != nil && !maps.IsPassable(npc.MapID, npc.X, npc.Y, int(npc.WanderDir)) {
 		npc.WanderDist = 0
 		return
@@ -713,14 +947,28 @@ func npcWander(ws *world.State, npc *world.NpcInfo, dir int, maps *data.MapDataT
 // ---------- Shared utilities ----------
 
 func setNpcAtkCooldown(npc *world.NpcInfo) {
+	npc.AttackTimer = calcNpcAtkTicks(npc.AtkSpeed)
+}
+
+// setNpcRangedCooldown 遠程攻擊冷卻：使用遠程攻擊動畫速度，未設定時沿用近戰速度。
+func setNpcRangedCooldown(npc *world.NpcInfo) {
+	speed := npc.RangedAtkSpeed
+	if speed <= 0 {
+		speed = npc.AtkSpeed
+	}
+	npc.AttackTimer = calcNpcAtkTicks(speed)
+}
+
+// calcNpcAtkTicks 將攻擊動畫毫秒換算為冷卻 tick 數（預設 10，最少 3）。
+func calcNpcAtkTicks(speed int16) int {
 	atkCooldown := 10
-	if npc.AtkSpeed > 0 {
-		atkCooldown = int(npc.AtkSpeed) / 200
+	if speed > 0 {
+		atkCooldown = int(speed) / 200
 		if atkCooldown < 3 {
 			atkCooldown = 3
 		}
 	}
-	npc.AttackTimer = atkCooldown
+	return atkCooldown
 }
 
 func chebyshev32(x1, y1, x2, y2 int32) int32 {
@@ -738,30 +986,10 @@ func chebyshev32(x1, y1, x2, y2 int32) int32 {
 	return dx
 }
 
+// npcHeadingDX/DY 8 方向位移查找表（遊走用；朝向計算統一使用 handler.CalcHeading）。
 var npcHeadingDX = [8]int32{0, 1, 1, 1, 0, -1, -1, -1}
 var npcHeadingDY = [8]int32{-1, -1, 0, 1, 1, 1, 0, -1}
 
-func calcNpcHeading(sx, sy, tx, ty int32) int16 {
-	ddx := tx - sx
-	ddy := ty - sy
-	if ddx > 0 {
-		ddx = 1
-	} else if ddx < 0 {
-		ddx = -1
-	}
-	if ddy > 0 {
-		ddy = 1
-	} else if ddy < 0 {
-		ddy = -1
-	}
-	for i := int16(0); i < 8; i++ {
-		if npcHeadingDX[i] == ddx && npcHeadingDY[i] == ddy {
-			return i
-		}
-	}
-	return 0
-}
-
 // ---------- Packet helpers ----------
 // These are local to the system package to avoid circular imports.
 
@@ -808,34 +1036,6 @@ func buildNpcRangedAttack(attackerID, targetID, damage int32, heading int16, ax,
 	return w.Bytes()
 }
 
-func sendNpcPack(sess *gonet.Session, npc *world.NpcInfo) {
-	w := packet.NewWriterWithOpcode(packet.S_OPCODE_PUT_OBJECT)
-	w.WriteH(uint16(npc.X))
-	w.WriteH(uint16(npc.Y))
-	w.WriteD(npc.ID)
-	w.WriteH(uint16(npc.GfxID))
-	w.WriteC(0)
-	w.WriteC(byte(npc.Heading))
-	w.WriteC(0)
-	w.WriteC(0)
-	w.WriteD(npc.Exp)
-	w.WriteH(0)
-	w.WriteS(npc.NameID)
-	w.WriteS("")
-	w.WriteC(0x00)
-	w.WriteD(0)
-	w.WriteS("")
-	w.WriteS("")
-	w.WriteC(0x00)
-	w.WriteC(0xFF)
-	w.WriteC(0x00)
-	w.WriteC(byte(npc.Level))
-	w.WriteC(0xFF)
-	w.WriteC(0xFF)
-	w.WriteC(0x00)
-	sess.Send(w.Bytes())
-}
-
 // buildNpcUseAttackSkill 建構 NPC 技能攻擊封包位元組（不發送）。
 func buildNpcUseAttackSkill(casterID, targetID int32, damage int16, heading int16, gfxID int32, useType byte, cx, cy, tx, ty int32) []byte {
 	npcArrowSeqNum++
@@ -952,12 +1152,25 @@ func removeNpcDebuffEffect(npc *world.NpcInfo, skillID int32, ws *world.State) {
 	}
 }
 
-// calcNpcMoveTicks 計算 NPC 移動間隔 tick 數。
+// calcNpcMoveTicks 計算 NPC 移動間隔 tick 數（漫遊/返回出生點，走路速度）。
 // 緩速 debuff（29/76/152）時移動間隔翻倍。
 func calcNpcMoveTicks(npc *world.NpcInfo) int {
+	return npcMoveTicks(npc, npc.MoveSpeed)
+}
+
+// calcNpcChaseTicks 計算 NPC 追擊/逃離目標時的移動間隔 tick 數（跑步速度，未設定時沿用走路速度）。
+func calcNpcChaseTicks(npc *world.NpcInfo) int {
+	speed := npc.RunSpeed
+	if speed <= 0 {
+		speed = npc.MoveSpeed
+	}
+	return npcMoveTicks(npc, speed)
+}
+
+func npcMoveTicks(npc *world.NpcInfo, speed int16) int {
 	moveTicks := 4
-	if npc.MoveSpeed > 0 {
-		moveTicks = int(npc.MoveSpeed) / 200
+	if speed > 0 {
+		moveTicks = int(speed) / 200
 		if moveTicks < 2 {
 			moveTicks = 2
 		}
@@ -1005,12 +1218,8 @@ func tickNpcPoison(npc *world.NpcInfo, ws *world.State, deps *handler.Deps) {
 		if npc.HP <= 1 {
 			npc.HP = 1
 		}
-		// 廣播 HP 條給所有附近玩家
+		// 廣播 HP 條給所有附近玩家（節流）
 		nearby := ws.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID)
-		hpRatio := int16(0)
-		if npc.MaxHP > 0 {
-			hpRatio = int16((npc.HP * 100) / npc.MaxHP)
-		}
-		handler.BroadcastToPlayers(nearby, handler.BuildHpMeter(npc.ID, hpRatio))
+		broadcastNpcHpMeter(npc, nearby, deps)
 	}
 }
@@ -0,0 +1,140 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestPatrolLeashDistMeasuresSegment(t *testing.T) {
+	npc := &world.NpcInfo{
+		Patrol: []data.PatrolPoint{{X: 100, Y: 100}, {X: 100, Y: 160}},
+	}
+	// 走向路點 1 的途中：離路點 1 超過 30 格，但仍在路段上
+	npc.PatrolIdx = 1
+	npc.X, npc.Y = 100, 110
+	if d := patrolLeashDist(npc); d != 0 {
+		t.Fatalf("on segment: dist = %d, want 0", d)
+	}
+	// 偏離路段 5 格
+	npc.X = 105
+	if d := patrolLeashDist(npc); d != 5 {
+		t.Fatalf("beside segment: dist = %d, want 5", d)
+	}
+	// 偏離路段 40 格
+	npc.X = 140
+	if d := patrolLeashDist(npc); d <= guardLeashRange {
+		t.Fatalf("off route: dist = %d, want > %d", d, guardLeashRange)
+	}
+}
+
+func TestGuardPatrolFarFromSpawnDoesNotLoop(t *testing.T) {
+	deps := newTestDeps(t)
+	ws := world.NewState()
+	deps.World = ws
+	s := NewNpcAISystem(ws, deps)
+
+	// 出生點離路點 0 超過 30 格
+	npc := &world.NpcInfo{
+		ID: world.NextNpcID(), Impl: "L1Guard", HP: 100, MaxHP: 100,
+		X: 10, Y: 10, SpawnX: 10, SpawnY: 10,
+		Patrol: []data.PatrolPoint{{X: 100, Y: 100}, {X: 100, Y: 140}},
+	}
+	ws.AddNpc(npc)
+
+	// 第一次：遠離整條路線 → 瞬移到目前路段起點（路點 1）
+	s.tickGuardAI(npc)
+	if npc.X != 100 || npc.Y != 140 {
+		t.Fatalf("after leash: at (%d,%d), want (100,140)", npc.X, npc.Y)
+	}
+	// 之後沿路段走向路點 0（距離 40 格），不應再瞬移
+	for i := 0; i < 200; i++ {
+		s.tickGuardAI(npc)
+		if npc.X != 100 {
+			t.Fatalf("tick %d: left the segment at (%d,%d)", i, npc.X, npc.Y)
+		}
+	}
+	if npc.Y >= 140 {
+		t.Fatalf("guard did not advance along the route: y = %d", npc.Y)
+	}
+}
+
+func TestNpcChaseTicksUseRunSpeed(t *testing.T) {
+	tests := []struct {
+		name      string
+		move, run int16
+		debuff    int32
+		wantWalk  int
+		wantChase int
+	}{
+		{"未設定速度", 0, 0, 0, 4, 4},
+		{"未設定跑步速度沿用走路", 1000, 0, 0, 5, 5},
+		{"跑步較快", 1000, 400, 0, 5, 2},
+		{"緩速翻倍", 1000, 600, 29, 10, 6},
+	}
+	for _, tt := range tests {
+		npc := &world.NpcInfo{MoveSpeed: tt.move, RunSpeed: tt.run}
+		if tt.debuff != 0 {
+			npc.AddDebuff(tt.debuff, 10)
+		}
+		if got := calcNpcMoveTicks(npc); got != tt.wantWalk {
+			t.Errorf("%s: walk ticks %d, want %d", tt.name, got, tt.wantWalk)
+		}
+		if got := calcNpcChaseTicks(npc); got != tt.wantChase {
+			t.Errorf("%s: chase ticks %d, want %d", tt.name, got, tt.wantChase)
+		}
+	}
+}
+
+func TestGuardDropsChaseWhenWantedExpires(t *testing.T) {
+	deps := newTestDeps(t)
+	ws := world.NewState()
+	deps.World = ws
+	s := NewNpcAISystem(ws, deps)
+
+	guard := &world.NpcInfo{
+		ID: world.NextNpcID(), Impl: "L1Guard", HP: 100, MaxHP: 100, MapID: 4,
+		X: 100, Y: 100, SpawnX: 100, SpawnY: 100, SpawnMapID: 4,
+	}
+	ws.AddNpc(guard)
+	pk := &world.PlayerInfo{
+		SessionID: 1, Session: newTestSession(t, 1), CharID: 1, Name: "pk",
+		X: 106, Y: 100, MapID: 4, HP: 100, MaxHP: 100, WantedTicks: 1000,
+	}
+	ws.AddPlayer(pk)
+
+	// 追擊途中（尚未進入攻擊距離）
+	for i := 0; i < 20 && guard.X < 103; i++ {
+		s.tickGuardAI(guard)
+	}
+	if guard.AggroTarget != pk.SessionID || guard.X < 103 || guard.X >= 105 {
+		t.Fatalf("guard not chasing: aggro %d at (%d,%d)", guard.AggroTarget, guard.X, guard.Y)
+	}
+
+	// 通緝到期：下一個 tick 立即放棄並走回崗位
+	pk.WantedTicks = 0
+	s.tickGuardAI(guard)
+	if guard.AggroTarget != 0 {
+		t.Fatal("guard kept its target after the wanted flag expired")
+	}
+	for i := 0; i < 100 && guard.X != guard.SpawnX; i++ {
+		s.tickGuardAI(guard)
+		if guard.AggroTarget != 0 {
+			t.Fatalf("tick %d: guard re-acquired an unflagged player", i)
+		}
+	}
+	if guard.X != guard.SpawnX || guard.Y != guard.SpawnY {
+		t.Errorf("guard at (%d,%d), want back at its post", guard.X, guard.Y)
+	}
+
+	// 曾攻擊守衛者：旗標到期後仍持續反擊
+	pk.WantedTicks = 1000
+	s.tickGuardAI(guard)
+	guard.HateList = map[uint64]int32{pk.SessionID: 10}
+	pk.WantedTicks = 0
+	s.tickGuardAI(guard)
+	if guard.AggroTarget != pk.SessionID {
+		t.Error("guard dropped a player who attacked it")
+	}
+}
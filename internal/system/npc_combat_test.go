@@ -0,0 +1,88 @@
+package system
+
+import (
+	"testing"
+	"time"
+
+	"github.com/l1jgo/server/internal/net"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// fakeDeath 記錄 KillPlayer / KillPlayerInPvP 呼叫。
+type fakeDeath struct{ killed, pvpKilled []*world.PlayerInfo }
+
+func (d *fakeDeath) KillPlayer(p *world.PlayerInfo)                 { d.killed = append(d.killed, p) }
+func (d *fakeDeath) KillPlayerInPvP(p *world.PlayerInfo)            { d.pvpKilled = append(d.pvpKilled, p) }
+func (d *fakeDeath) KillDuelLoser(p *world.PlayerInfo)              {}
+func (d *fakeDeath) ProcessRestart(*net.Session, *world.PlayerInfo) {}
+
+func newMeleeFixture(t *testing.T, hp int16) (*NpcAISystem, *fakeDeath, *world.NpcInfo, *world.PlayerInfo) {
+	t.Helper()
+	world.SetRand(world.NewRand(1))
+	t.Cleanup(func() { world.SetRand(world.NewRand(time.Now().UnixNano())) })
+
+	deps := newTestDeps(t)
+	death := &fakeDeath{}
+	deps.Death = death
+	ws := world.NewState()
+	deps.World = ws
+	s := NewNpcAISystem(ws, deps)
+
+	npc := &world.NpcInfo{
+		ID: world.NextNpcID(), Impl: "L1Monster", HP: 100, MaxHP: 100,
+		Level: 50, STR: 18, DEX: 18, AtkDmg: 10, X: 32701, Y: 32801, MapID: 4,
+	}
+	target := &world.PlayerInfo{
+		SessionID: 1, Session: newTestSession(t, 1), CharID: 1, Name: "tester",
+		X: 32700, Y: 32800, MapID: 4, Level: 1, AC: 10, HP: hp, MaxHP: hp,
+	}
+	npc.AggroTarget = target.SessionID
+	return s, death, npc, target
+}
+
+func TestNpcMeleeAttackAppliesDamage(t *testing.T) {
+	s, death, npc, target := newMeleeFixture(t, 10000)
+
+	for i := 0; i < 20 && target.HP == target.MaxHP; i++ {
+		s.npcMeleeAttack(npc, target)
+	}
+	if target.HP == target.MaxHP {
+		t.Fatal("20 melee swings never hit an AC 10 target")
+	}
+	if !target.Dirty {
+		t.Error("damaged player not marked dirty")
+	}
+	if npc.Heading != 7 {
+		t.Errorf("npc heading = %d, want 7 (facing the target)", npc.Heading)
+	}
+	if len(death.killed) != 0 {
+		t.Error("player killed by non-lethal damage")
+	}
+}
+
+func TestNpcMeleeAttackKillsPlayer(t *testing.T) {
+	s, death, npc, target := newMeleeFixture(t, 1)
+
+	for i := 0; i < 20 && len(death.killed) == 0; i++ {
+		s.npcMeleeAttack(npc, target)
+	}
+	if len(death.killed) != 1 || death.killed[0] != target {
+		t.Fatalf("KillPlayer calls = %d, want 1", len(death.killed))
+	}
+	if target.HP != 0 {
+		t.Errorf("dead player HP = %d, want 0", target.HP)
+	}
+	if npc.AggroTarget != 0 {
+		t.Error("npc kept aggro on the dead player")
+	}
+}
+
+func TestNpcMeleeAttackBlockedByAbsoluteBarrier(t *testing.T) {
+	s, _, npc, target := newMeleeFixture(t, 100)
+	target.AbsoluteBarrier = true
+
+	s.npcMeleeAttack(npc, target)
+	if target.HP != 100 || npc.AggroTarget != 0 {
+		t.Fatalf("barrier: HP %d, aggro %d", target.HP, npc.AggroTarget)
+	}
+}
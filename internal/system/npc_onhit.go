@@ -0,0 +1,111 @@
+package system
+
+import (
+	"fmt"
+
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// onHitStatNames 能力值代碼 → 顯示名稱（命中特效訊息用）。
+var onHitStatNames = map[string]string{
+	"str": "力量", "dex": "敏捷", "con": "體質",
+	"int": "智力", "wis": "精神", "cha": "魅力",
+}
+
+// applyNpcOnHit 怪物近戰/遠程攻擊造成傷害後，依模板 on_hit 設定逐項擲骰觸發命中特效。
+// 呼叫端須確認目標仍存活。
+func (s *NpcAISystem) applyNpcOnHit(npc *world.NpcInfo, target *world.PlayerInfo) {
+	if s.deps.Npcs == nil {
+		return
+	}
+	tmpl := s.deps.Npcs.Get(npc.NpcID)
+	if tmpl == nil || len(tmpl.OnHit) == 0 {
+		return
+	}
+	for i := range tmpl.OnHit {
+		eff := &tmpl.OnHit[i]
+		if world.RandInt(100) >= eff.Chance {
+			continue
+		}
+		if !s.applyOnHitEffect(target, eff) {
+			continue
+		}
+		if eff.Gfx > 0 {
+			nearby := s.world.GetNearbyPlayersAt(target.X, target.Y, target.MapID)
+			handler.BroadcastToPlayers(nearby, handler.BuildSkillEffect(target.CharID, eff.Gfx))
+		}
+	}
+}
+
+// applyOnHitEffect 套用單一命中特效，回傳是否實際生效（已中毒、已被削弱等情況不生效）。
+func (s *NpcAISystem) applyOnHitEffect(target *world.PlayerInfo, eff *data.OnHitEffect) bool {
+	switch eff.Type {
+	case data.OnHitPoison:
+		// 單毒限制：與 ApplyNpcPoisonAttack 相同
+		if target.PoisonType != 0 {
+			return false
+		}
+		infectNpcPoison(target, eff.Poison, s.deps)
+		return true
+
+	case data.OnHitStatDrain:
+		// 同時只保留一層削弱，到期由 tickPlayerBuffs 還原
+		if target.HasBuff(handler.SkillStatusStatDrain) {
+			return false
+		}
+		buff := &world.ActiveBuff{
+			SkillID:   handler.SkillStatusStatDrain,
+			TicksLeft: eff.Duration * 5, // 秒 → ticks
+		}
+		switch eff.Stat {
+		case "str":
+			buff.DeltaStr = -onHitDrainAmount(eff.Amount, target.Str)
+			target.Str += buff.DeltaStr
+		case "dex":
+			buff.DeltaDex = -onHitDrainAmount(eff.Amount, target.Dex)
+			target.Dex += buff.DeltaDex
+		case "con":
+			buff.DeltaCon = -onHitDrainAmount(eff.Amount, target.Con)
+			target.Con += buff.DeltaCon
+		case "int":
+			buff.DeltaIntel = -onHitDrainAmount(eff.Amount, target.Intel)
+			target.Intel += buff.DeltaIntel
+		case "wis":
+			buff.DeltaWis = -onHitDrainAmount(eff.Amount, target.Wis)
+			target.Wis += buff.DeltaWis
+		case "cha":
+			buff.DeltaCha = -onHitDrainAmount(eff.Amount, target.Cha)
+			target.Cha += buff.DeltaCha
+		default:
+			return false
+		}
+		drop := -(buff.DeltaStr + buff.DeltaDex + buff.DeltaCon + buff.DeltaIntel + buff.DeltaWis + buff.DeltaCha)
+		if drop <= 0 {
+			return false
+		}
+		target.AddBuff(buff)
+		target.Dirty = true
+		handler.SendPlayerStatus(target.Session, target)
+		handler.SendSystemMessage(target.Session,
+			fmt.Sprintf("你的%s暫時下降了 %d 點（%d 秒）。", onHitStatNames[eff.Stat], drop, eff.Duration))
+		return true
+
+	case data.OnHitMPDrain:
+		if target.MP <= 0 {
+			return false
+		}
+		target.MP -= min(eff.Amount, target.MP)
+		target.Dirty = true
+		sendMpUpdate(target.Session, target)
+		handler.SendSystemMessage(target.Session, "你的魔力被吸取了。")
+		return true
+	}
+	return false
+}
+
+// onHitDrainAmount 計算實際削弱點數（能力值最低保留 1）。
+func onHitDrainAmount(amount, cur int16) int16 {
+	return max(min(amount, cur-1), 0)
+}
@@ -0,0 +1,81 @@
+package system
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/world"
+)
+
+func newOnHitFixture(t *testing.T) (*NpcAISystem, *world.PlayerInfo) {
+	t.Helper()
+	deps := newTestDeps(t)
+	deps.World = world.NewState()
+	p := &world.PlayerInfo{
+		SessionID: 1, Session: newTestSession(t, 1), CharID: 1, Name: "target",
+		Str: 12, Dex: 12, Con: 2, MP: 30, MaxMP: 30, HP: 50, MaxHP: 50, Inv: world.NewInventory(),
+	}
+	deps.World.AddPlayer(p)
+	return NewNpcAISystem(deps.World, deps), p
+}
+
+func TestOnHitStatDrainRevertsOnExpiry(t *testing.T) {
+	s, p := newOnHitFixture(t)
+	eff := &data.OnHitEffect{Type: data.OnHitStatDrain, Chance: 100, Stat: "str", Amount: 2, Duration: 1}
+
+	if !s.applyOnHitEffect(p, eff) || p.Str != 10 {
+		t.Fatalf("drain: applied, STR = %d, want 10", p.Str)
+	}
+	// 同時只保留一層削弱
+	if s.applyOnHitEffect(p, eff) || p.Str != 10 {
+		t.Fatalf("second drain stacked: STR = %d", p.Str)
+	}
+
+	icons, err := data.LoadBuffIconTable(filepath.Join("..", "..", "data", "yaml", "buff_icon_map.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.deps.BuffIcons = icons
+	skills := NewSkillSystem(s.deps)
+	for i := 0; i < eff.Duration*5; i++ {
+		skills.tickPlayerBuffs(p)
+	}
+	if p.Str != 12 || p.HasBuff(handler.SkillStatusStatDrain) {
+		t.Fatalf("after expiry: STR = %d, buff %v", p.Str, p.HasBuff(handler.SkillStatusStatDrain))
+	}
+}
+
+func TestOnHitStatDrainKeepsStatAtOne(t *testing.T) {
+	s, p := newOnHitFixture(t)
+	eff := &data.OnHitEffect{Type: data.OnHitStatDrain, Chance: 100, Stat: "con", Amount: 5, Duration: 10}
+	if !s.applyOnHitEffect(p, eff) || p.Con != 1 {
+		t.Fatalf("CON = %d, want 1", p.Con)
+	}
+	p.RemoveBuff(handler.SkillStatusStatDrain)
+	p.Con = 1
+	if s.applyOnHitEffect(p, eff) {
+		t.Fatal("drain at 1 reported as applied")
+	}
+}
+
+func TestOnHitMPDrainAndPoison(t *testing.T) {
+	s, p := newOnHitFixture(t)
+
+	if !s.applyOnHitEffect(p, &data.OnHitEffect{Type: data.OnHitMPDrain, Chance: 100, Amount: 50}) || p.MP != 0 {
+		t.Fatalf("MP = %d, want 0", p.MP)
+	}
+	if s.applyOnHitEffect(p, &data.OnHitEffect{Type: data.OnHitMPDrain, Chance: 100, Amount: 5}) {
+		t.Fatal("MP drain applied with no MP left")
+	}
+
+	poison := &data.OnHitEffect{Type: data.OnHitPoison, Chance: 100, Poison: 2}
+	if !s.applyOnHitEffect(p, poison) || p.PoisonType != 2 || !p.Silenced {
+		t.Fatalf("silence poison: type %d, silenced %v", p.PoisonType, p.Silenced)
+	}
+	// 單毒限制
+	if s.applyOnHitEffect(p, &data.OnHitEffect{Type: data.OnHitPoison, Chance: 100, Poison: 1}) || p.PoisonType != 2 {
+		t.Fatal("second poison replaced the first")
+	}
+}
@@ -24,6 +24,7 @@ func NewNpcRespawnSystem(ws *world.State, maps *data.MapDataTable) *NpcRespawnSy
 func (s *NpcRespawnSystem) Phase() coresys.Phase { return coresys.PhaseUpdate }
 
 func (s *NpcRespawnSystem) Update(_ time.Duration) {
+	var splitDone []int32
 	for _, npc := range s.world.NpcList() {
 		if !npc.Dead {
 			continue
@@ -38,6 +39,10 @@ func (s *NpcRespawnSystem) Update(_ time.Duration) {
 				nearby := s.world.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID)
 				rmData := handler.BuildRemoveObject(npc.ID)
 				handler.BroadcastToPlayers(nearby, rmData)
+				// 死亡分裂生成的子怪物不重生，屍體消失後移出世界
+				if npc.SplitGen > 0 {
+					splitDone = append(splitDone, npc.ID)
+				}
 			}
 			continue // 等刪除階段完成才開始重生計時
 		}
@@ -50,27 +55,25 @@ func (s *NpcRespawnSystem) Update(_ time.Duration) {
 			}
 		}
 	}
+	for _, id := range splitDone {
+		s.world.RemoveNpc(id)
+	}
 }
 
 func (s *NpcRespawnSystem) respawnNpc(npc *world.NpcInfo) {
-	// Find unoccupied spawn tile
-	spawnX, spawnY := npc.SpawnX, npc.SpawnY
-	if s.world.IsOccupied(spawnX, spawnY, npc.SpawnMapID, npc.ID) {
-		// Spiral search radius 1~3 for nearest empty tile
-		found := false
-		for r := int32(1); r <= 3 && !found; r++ {
-			for dx := -r; dx <= r && !found; dx++ {
-				for dy := -r; dy <= r && !found; dy++ {
-					tx, ty := spawnX+dx, spawnY+dy
-					if !s.world.IsOccupied(tx, ty, npc.SpawnMapID, npc.ID) {
-						spawnX, spawnY = tx, ty
-						found = true
-					}
-				}
-			}
+	// Area spawns pick a fresh tile in their rectangle; the new tile also
+	// becomes the NPC's home for leash/return-home AI.
+	if npc.SpawnArea != nil {
+		if x, y, ok := PickAreaTile(s.world, s.maps, npc.SpawnMapID, npc.SpawnArea, npc.ID); ok {
+			npc.SpawnX, npc.SpawnY = x, y
 		}
 	}
 
+	// Find unoccupied spawn tile: spiral search radius 1~3 for nearest empty tile
+	spawnX, spawnY, _ := world.SpiralSearch(npc.SpawnX, npc.SpawnY, 3, func(x, y int32) bool {
+		return !s.world.IsOccupied(x, y, npc.SpawnMapID, npc.ID)
+	})
+
 	npc.Dead = false
 	npc.HP = npc.MaxHP
 	npc.MP = npc.MaxMP
@@ -82,6 +85,9 @@ func (s *NpcRespawnSystem) respawnNpc(npc *world.NpcInfo) {
 	npc.AttackTimer = 0
 	npc.MoveTimer = 0
 	npc.StuckTicks = 0
+	npc.PatrolIdx = 0 // 巡邏從第一個路點重新開始
+	npc.PatrolWait = 0
+	npc.PatrolStuck = 0
 	npc.Paralyzed = false
 	npc.Sleeped = false
 	npc.ActiveDebuffs = nil
@@ -100,6 +106,83 @@ func (s *NpcRespawnSystem) respawnNpc(npc *world.NpcInfo) {
 	// 通知附近玩家：顯示 NPC + 封鎖格子
 	nearby := s.world.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID)
 	for _, viewer := range nearby {
-		sendNpcPack(viewer.Session, npc)
+		handler.SendNpcPack(viewer.Session, npc)
+	}
+}
+
+// NewNpcFromTemplate builds an NPC instance from its template at (x, y) with
+// that point as its spawn/home point and no respawn. Animation-based speeds
+// come from sprTable (mirrors Java L1NpcInstance.initStats); sprTable may be
+// nil (template values only). The caller adds it to the world.
+func NewNpcFromTemplate(tmpl *data.NpcTemplate, sprTable *data.SprTable, x, y int32, mapID, heading int16) *world.NpcInfo {
+	speeds := sprTable.ResolveNpcSpeeds(tmpl)
+	return &world.NpcInfo{
+		ID:             world.NextNpcID(),
+		NpcID:          tmpl.NpcID,
+		Impl:           tmpl.Impl,
+		GfxID:          tmpl.GfxID,
+		Name:           tmpl.Name,
+		NameID:         tmpl.NameID,
+		Title:          tmpl.Title,
+		Level:          tmpl.Level,
+		X:              x,
+		Y:              y,
+		MapID:          mapID,
+		Heading:        heading,
+		HP:             tmpl.HP,
+		MaxHP:          tmpl.HP,
+		MP:             tmpl.MP,
+		MaxMP:          tmpl.MP,
+		AC:             tmpl.AC,
+		STR:            tmpl.STR,
+		DEX:            tmpl.DEX,
+		Exp:            tmpl.Exp,
+		Lawful:         tmpl.Lawful,
+		Size:           tmpl.Size,
+		MR:             tmpl.MR,
+		Undead:         tmpl.Undead,
+		Agro:           tmpl.Agro,
+		Tameable:       tmpl.Tameable,
+		AtkDmg:         int32(tmpl.Level) + int32(tmpl.STR)/3,
+		Ranged:         tmpl.Ranged,
+		AtkSpeed:       speeds.Atk,
+		MoveSpeed:      speeds.Move,
+		RunSpeed:       speeds.Run,
+		RangedAtkSpeed: speeds.RangedAtk,
+		PoisonAtk:      tmpl.PoisonAtk,
+		WanderRadius:   tmpl.WanderRadius,
+		ImmuneParalyze: tmpl.ImmuneParalyze,
+		ImmuneSleep:    tmpl.ImmuneSleep,
+		ImmunePoison:   tmpl.ImmunePoison,
+		FireRes:        tmpl.FireRes,
+		WaterRes:       tmpl.WaterRes,
+		WindRes:        tmpl.WindRes,
+		EarthRes:       tmpl.EarthRes,
+		SpawnX:         x,
+		SpawnY:         y,
+		SpawnMapID:     mapID,
+	}
+}
+
+// areaPickAttempts bounds the random tile search in PickAreaTile.
+const areaPickAttempts = 50
+
+// PickAreaTile returns a random passable, unoccupied tile inside area.
+// Returns ok = false if no such tile was found within areaPickAttempts tries;
+// callers then fall back to the NPC's previous spawn point.
+func PickAreaTile(ws *world.State, maps *data.MapDataTable, mapID int16, area *data.SpawnArea, excludeID int32) (int32, int32, bool) {
+	w := int(area.X2 - area.X1 + 1)
+	h := int(area.Y2 - area.Y1 + 1)
+	for i := 0; i < areaPickAttempts; i++ {
+		x := area.X1 + int32(world.RandInt(w))
+		y := area.Y1 + int32(world.RandInt(h))
+		if maps != nil && (!maps.IsInMap(mapID, x, y) || !maps.IsPassablePoint(mapID, x, y)) {
+			continue
+		}
+		if ws.IsOccupied(x, y, mapID, excludeID) {
+			continue
+		}
+		return x, y, true
 	}
+	return 0, 0, false
 }
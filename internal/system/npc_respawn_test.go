@@ -0,0 +1,79 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestPickAreaTileStaysInsideArea(t *testing.T) {
+	ws := world.NewState()
+	area := &data.SpawnArea{X1: 100, Y1: 200, X2: 102, Y2: 201}
+	for i := 0; i < 100; i++ {
+		x, y, ok := PickAreaTile(ws, nil, 4, area, 0)
+		if !ok {
+			t.Fatal("no tile picked in an empty area")
+		}
+		if x < area.X1 || x > area.X2 || y < area.Y1 || y > area.Y2 {
+			t.Fatalf("picked (%d,%d) outside %+v", x, y, *area)
+		}
+	}
+}
+
+func TestPickAreaTileSkipsOccupied(t *testing.T) {
+	ws := world.NewState()
+	area := &data.SpawnArea{X1: 100, Y1: 200, X2: 101, Y2: 200}
+	blocker := &world.NpcInfo{ID: world.NextNpcID(), X: 100, Y: 200, MapID: 4, HP: 1, MaxHP: 1}
+	ws.AddNpc(blocker)
+	for i := 0; i < 50; i++ {
+		x, y, ok := PickAreaTile(ws, nil, 4, area, 0)
+		if !ok || x != 101 || y != 200 {
+			t.Fatalf("picked (%d,%d,%v), want the only free tile (101,200)", x, y, ok)
+		}
+	}
+	// 被自己佔用的格子可以重用（重生時排除自身）
+	if _, _, ok := PickAreaTile(ws, nil, 4, &data.SpawnArea{X1: 100, Y1: 200, X2: 100, Y2: 200}, blocker.ID); !ok {
+		t.Fatal("excludeID tile rejected")
+	}
+}
+
+func TestNewNpcFromTemplate(t *testing.T) {
+	tmpl := &data.NpcTemplate{
+		NpcID:        45060,
+		Impl:         "L1Monster",
+		GfxID:        1234,
+		Name:         "slime",
+		Level:        10,
+		HP:           80,
+		MP:           5,
+		STR:          12,
+		Agro:         true,
+		AtkSpeed:     800,
+		PassiveSpeed: 640,
+	}
+	a := NewNpcFromTemplate(tmpl, nil, 32700, 32800, 4, 3)
+	b := NewNpcFromTemplate(tmpl, nil, 32700, 32800, 4, 3)
+	if a.ID == b.ID {
+		t.Fatalf("object IDs not unique: %d", a.ID)
+	}
+	if a.NpcID != 45060 || a.Impl != "L1Monster" || a.GfxID != 1234 || a.Level != 10 || !a.Agro {
+		t.Errorf("template fields not copied: %+v", a)
+	}
+	if a.HP != 80 || a.MaxHP != 80 || a.MP != 5 || a.MaxMP != 5 {
+		t.Errorf("HP/MP = %d/%d %d/%d, want full pools", a.HP, a.MaxHP, a.MP, a.MaxMP)
+	}
+	if a.AtkDmg != 14 {
+		t.Errorf("AtkDmg = %d, want 14", a.AtkDmg)
+	}
+	if a.X != 32700 || a.Y != 32800 || a.MapID != 4 || a.Heading != 3 {
+		t.Errorf("position = %d,%d map %d heading %d", a.X, a.Y, a.MapID, a.Heading)
+	}
+	if a.SpawnX != a.X || a.SpawnY != a.Y || a.SpawnMapID != a.MapID {
+		t.Errorf("spawn point %d,%d map %d, want current position", a.SpawnX, a.SpawnY, a.SpawnMapID)
+	}
+	// nil SprTable falls back to the template speeds.
+	if a.AtkSpeed != 800 || a.RangedAtkSpeed != 800 || a.MoveSpeed != 640 || a.RunSpeed != 640 {
+		t.Errorf("speeds = atk %d ranged %d move %d run %d", a.AtkSpeed, a.RangedAtkSpeed, a.MoveSpeed, a.RunSpeed)
+	}
+}
@@ -0,0 +1,70 @@
+package system
+
+import (
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// splitRadius 子怪物生成位置離屍體的最大距離（格）。
+const splitRadius = 2
+
+// splitOnDeath 死亡分裂（npc_list split_into）：在屍體附近生成子怪物，封鎖格子並廣播外觀。
+// 子怪物代數為父代數 + 1，父代數已達 [gameplay] npc_split_max_depth 時不再分裂。
+// 子怪物不重生，屍體消失後由 NpcRespawnSystem 移出世界；attacker 不為 nil 時以其為仇恨目標。
+func splitOnDeath(npc *world.NpcInfo, attacker *world.PlayerInfo, deps *handler.Deps) {
+	if npc.SplitGen >= deps.Config.Gameplay.NpcSplitMaxDepth || deps.Npcs == nil {
+		return
+	}
+	tmpl := deps.Npcs.Get(npc.NpcID)
+	if tmpl == nil || len(tmpl.SplitInto) == 0 {
+		return
+	}
+
+	nearby := deps.World.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID)
+	for _, sp := range tmpl.SplitInto {
+		childTmpl := deps.Npcs.Get(sp.NpcID)
+		if childTmpl == nil {
+			continue
+		}
+		for i := 0; i < sp.Count; i++ {
+			x, y, ok := splitTile(npc, deps)
+			if !ok {
+				return // 周圍已無空位
+			}
+			child := NewNpcFromTemplate(childTmpl, deps.SprTable, x, y, npc.MapID, int16(world.RandInt(8)))
+			child.SplitGen = npc.SplitGen + 1
+			deps.World.AddNpc(child)
+			if deps.MapData != nil {
+				deps.MapData.SetImpassable(child.MapID, child.X, child.Y, true)
+			}
+			if attacker != nil {
+				AddHate(child, attacker.SessionID, 1)
+			}
+			for _, viewer := range nearby {
+				handler.SendNpcPack(viewer.Session, child)
+			}
+		}
+	}
+}
+
+// splitTile 在屍體周圍 splitRadius 格內隨機挑選可通行且無人佔據的格子。
+func splitTile(npc *world.NpcInfo, deps *handler.Deps) (int32, int32, bool) {
+	var free [][2]int32
+	for dx := int32(-splitRadius); dx <= splitRadius; dx++ {
+		for dy := int32(-splitRadius); dy <= splitRadius; dy++ {
+			x, y := npc.X+dx, npc.Y+dy
+			if deps.World.IsOccupied(x, y, npc.MapID, 0) {
+				continue
+			}
+			if deps.MapData != nil && !(deps.MapData.IsInMap(npc.MapID, x, y) && deps.MapData.IsPassablePoint(npc.MapID, x, y)) {
+				continue
+			}
+			free = append(free, [2]int32{x, y})
+		}
+	}
+	if len(free) == 0 {
+		return 0, 0, false
+	}
+	p := free[world.RandInt(len(free))]
+	return p[0], p[1], true
+}
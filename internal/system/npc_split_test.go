@@ -0,0 +1,51 @@
+package system
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestSlimeSplitFollowsDepthCap(t *testing.T) {
+	deps := newTestDeps(t)
+	npcs, err := data.LoadNpcTable(filepath.Join("..", "..", "data", "yaml", "npc_list.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps.Npcs = npcs
+	deps.World = world.NewState()
+	slime := NewNpcFromTemplate(npcs.Get(45060), nil, 32700, 32800, 4, 0)
+
+	// 預設 npc_split_max_depth = 0：不分裂
+	splitOnDeath(slime, nil, deps)
+	if n := len(deps.World.NpcList()); n != 0 {
+		t.Fatalf("split with splitting disabled: %d NPCs spawned", n)
+	}
+
+	deps.Config.Gameplay.NpcSplitMaxDepth = 1
+	splitOnDeath(slime, nil, deps)
+	children := deps.World.NpcList()
+	if len(children) != 2 {
+		t.Fatalf("spawned %d children, want 2", len(children))
+	}
+	for _, c := range children {
+		if c.NpcID != 45060 || c.SplitGen != 1 || abs32(c.X-slime.X) > splitRadius || abs32(c.Y-slime.Y) > splitRadius {
+			t.Errorf("child %+v", c)
+		}
+	}
+
+	// 子代已達上限，不再分裂
+	splitOnDeath(children[0], nil, deps)
+	if n := len(deps.World.NpcList()); n != 2 {
+		t.Fatalf("child split past the cap: %d NPCs", n)
+	}
+}
+
+func abs32(v int32) int32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,47 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestNpcWanderStaysWithinRadius(t *testing.T) {
+	ws := world.NewState()
+	npc := &world.NpcInfo{
+		ID: world.NextNpcID(), HP: 10, MaxHP: 10, MapID: 4, SpawnMapID: 4,
+		X: 32700, Y: 32800, SpawnX: 32700, SpawnY: 32800,
+	}
+	ws.AddNpc(npc)
+
+	const radius = 5
+	for i := 0; i < 5000; i++ {
+		dir := -1 // 沿原方向繼續
+		switch {
+		case i%50 == 0:
+			dir = 2 // 一直往東：未設上限時會一路漂走
+		case i%7 == 0:
+			dir = world.RandInt(8)
+		}
+		npcWander(ws, npc, dir, radius, nil)
+		if d := chebyshev32(npc.X, npc.Y, npc.SpawnX, npc.SpawnY); d > radius {
+			t.Fatalf("tick %d: %d tiles from spawn, radius %d", i, d, radius)
+		}
+	}
+}
+
+func TestNpcWanderUnboundedDrifts(t *testing.T) {
+	ws := world.NewState()
+	npc := &world.NpcInfo{
+		ID: world.NextNpcID(), HP: 10, MaxHP: 10, MapID: 4, SpawnMapID: 4,
+		X: 32700, Y: 32800, SpawnX: 32700, SpawnY: 32800,
+	}
+	ws.AddNpc(npc)
+
+	for i := 0; i < 100; i++ {
+		npcWander(ws, npc, 2, 0, nil)
+	}
+	if npc.X-npc.SpawnX != 100 {
+		t.Fatalf("radius 0 should not bound wandering: moved %d tiles east", npc.X-npc.SpawnX)
+	}
+}
@@ -36,6 +36,10 @@ func (s *PartySystem) Invite(sess *net.Session, player *world.PlayerInfo, target
 		handler.SendServerMessage(sess, 952) // 對象不在畫面內
 		return
 	}
+	if target.RefuseParty {
+		handler.SendRefused(sess, target.Name, "組隊邀請")
+		return
+	}
 
 	// 目標已在隊伍中
 	if s.deps.World.Parties.IsInParty(target.CharID) {
@@ -97,6 +101,10 @@ func (s *PartySystem) ChatInvite(sess *net.Session, player *world.PlayerInfo, ta
 		handler.SendServerMessage(sess, 952) // 對象不在畫面內
 		return
 	}
+	if target.RefuseParty {
+		handler.SendRefused(sess, target.Name, "組隊邀請")
+		return
+	}
 	if s.deps.World.ChatParties.IsInParty(target.CharID) {
 		handler.SendServerMessage(sess, 415) // 您無法邀請已經參加其他隊伍的人。
 		return
@@ -6,36 +6,45 @@ import (
 
 	coresys "github.com/l1jgo/server/internal/core/system"
 	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/metrics"
 	"github.com/l1jgo/server/internal/persist"
 	"github.com/l1jgo/server/internal/world"
 	"go.uber.org/zap"
 )
 
 // PersistenceSystem periodically auto-saves all online players' character data,
-// inventory, bookmarks, known spells, and active buffs. Phase 5 (Persist).
+// inventory, bookmarks, known spells, active buffs, and summoned pets. Phase 5 (Persist).
 type PersistenceSystem struct {
 	world     *world.State
 	charRepo  *persist.CharacterRepo
 	itemRepo  *persist.ItemRepo
 	buffRepo  *persist.BuffRepo
 	walRepo   *persist.WALRepo
+	petRepo   *persist.PetRepo
 	log       *zap.Logger
+	metrics   *metrics.Collector // 存檔耗時（nil = 不記錄）
 	tickCount int
 	interval  int // auto-save every N ticks
 }
 
-func NewPersistenceSystem(ws *world.State, charRepo *persist.CharacterRepo, itemRepo *persist.ItemRepo, buffRepo *persist.BuffRepo, walRepo *persist.WALRepo, log *zap.Logger, intervalTicks int) *PersistenceSystem {
+func NewPersistenceSystem(ws *world.State, charRepo *persist.CharacterRepo, itemRepo *persist.ItemRepo, buffRepo *persist.BuffRepo, walRepo *persist.WALRepo, petRepo *persist.PetRepo, log *zap.Logger, intervalTicks int) *PersistenceSystem {
 	return &PersistenceSystem{
 		world:    ws,
 		charRepo: charRepo,
 		itemRepo: itemRepo,
 		buffRepo: buffRepo,
 		walRepo:  walRepo,
+		petRepo:  petRepo,
 		log:      log,
 		interval: intervalTicks,
 	}
 }
 
+// SetMetrics 設定監控收集器（記錄批次存檔耗時）。
+func (s *PersistenceSystem) SetMetrics(mc *metrics.Collector) {
+	s.metrics = mc
+}
+
 func (s *PersistenceSystem) Phase() coresys.Phase { return coresys.PhasePersist }
 
 func (s *PersistenceSystem) Update(_ time.Duration) {
@@ -60,6 +69,10 @@ func (s *PersistenceSystem) saveAllPlayers() {
 // savePlayers persists player data. If dirtyOnly is true, only saves players
 // whose Dirty flag is set and resets the flag after successful save.
 func (s *PersistenceSystem) savePlayers(dirtyOnly bool) {
+	if s.metrics != nil {
+		start := time.Now()
+		defer func() { s.metrics.RecordSave(time.Since(start)) }()
+	}
 	count := 0
 	s.world.AllPlayers(func(p *world.PlayerInfo) {
 		if dirtyOnly && !p.Dirty {
@@ -102,6 +115,7 @@ func (s *PersistenceSystem) savePlayers(dirtyOnly bool) {
 			Cha:        p.Cha - int16(eq.AddCha) - bCha,
 			Intel:      p.Intel - int16(eq.AddInt) - bIntel,
 			BonusStats:  p.BonusStats,
+			BonusAlloc:  p.BonusAlloc,
 			ElixirStats: p.ElixirStats,
 			ClanID:      p.ClanID,
 			ClanName:   p.ClanName,
@@ -110,6 +124,7 @@ func (s *PersistenceSystem) savePlayers(dirtyOnly bool) {
 			Karma:      p.Karma,
 			PKCount:    p.PKCount,
 			Food:       p.Food,
+			RestedXP:   p.RestedXP,
 		}
 		if err := s.charRepo.SaveCharacter(ctx, row); err != nil {
 			s.log.Error("自動存檔角色失敗", zap.String("name", p.Name), zap.Error(err))
@@ -145,6 +160,7 @@ func (s *PersistenceSystem) savePlayers(dirtyOnly bool) {
 	if count > 0 {
 		s.log.Info("自動存檔完成", zap.Int("玩家數", count))
 	}
+	s.savePets(dirtyOnly)
 
 	// Mark WAL entries as processed after successful batch save.
 	// This prevents replay of already-persisted economic transactions on crash recovery.
@@ -157,4 +173,28 @@ func (s *PersistenceSystem) savePlayers(dirtyOnly bool) {
 	}
 }
 
+// savePets persists online players' summoned pets (marked as out, so they are
+// resummoned on next login). Pets track their own Dirty flag independently of
+// the owner.
+func (s *PersistenceSystem) savePets(dirtyOnly bool) {
+	if s.petRepo == nil {
+		return
+	}
+	s.world.AllPlayers(func(p *world.PlayerInfo) {
+		for _, pet := range s.world.GetPetsByOwner(p.CharID) {
+			if dirtyOnly && !pet.Dirty {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			err := s.petRepo.Save(ctx, petRowOf(pet, true))
+			cancel()
+			if err != nil {
+				s.log.Error("自動存檔寵物失敗", zap.String("name", p.Name), zap.Error(err))
+				continue
+			}
+			pet.Dirty = false
+		}
+	})
+}
+
 // bookmarksToRows is defined in input.go (shared within the system package).
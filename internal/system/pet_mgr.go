@@ -15,6 +15,7 @@ import (
 	"github.com/l1jgo/server/internal/net/packet"
 	"github.com/l1jgo/server/internal/persist"
 	"github.com/l1jgo/server/internal/world"
+	"go.uber.org/zap"
 )
 
 // PetSystem 實作 handler.PetLifecycleManager。
@@ -56,6 +57,10 @@ func (s *PetSystem) UsePetCollar(sess *net.Session, player *world.PlayerInfo, in
 		handler.SendServerMessage(sess, 319) // "你的魅力值不夠。"
 		return
 	}
+	if !petSlotFree(player, int(player.Cha)) {
+		handler.SendServerMessage(sess, 489) // 你無法一次控制那麼多寵物
+		return
+	}
 
 	// 從 DB 載入寵物
 	if s.deps.PetRepo == nil {
@@ -67,11 +72,65 @@ func (s *PetSystem) UsePetCollar(sess *net.Session, player *world.PlayerInfo, in
 	if err != nil || petRow == nil {
 		return
 	}
+	if pet := s.spawnPet(sess, player, invItem.ObjectID, petRow); pet != nil {
+		pet.Dirty = true // 下次自動存檔記錄為「在外」
+	}
+}
+
+// RestorePets 登入時重新召喚登出前在外的寵物（character_pets.summoned）。
+// 項圈已不在背包、寵物已死亡、地圖禁止召喚或魅力不足者略過，維持收回狀態。
+func (s *PetSystem) RestorePets(sess *net.Session, player *world.PlayerInfo) {
+	if s.deps.PetRepo == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	rows, err := s.deps.PetRepo.LoadByOwner(ctx, player.CharID)
+	if err != nil {
+		s.deps.Log.Error("載入寵物失敗", zap.String("name", player.Name), zap.Error(err))
+		return
+	}
+
+	if s.deps.MapData != nil {
+		if md := s.deps.MapData.GetInfo(player.MapID); md != nil && !md.RecallPets {
+			return
+		}
+	}
+
+	for i := range rows {
+		row := &rows[i]
+		if !row.Summoned || row.HP <= 0 {
+			continue
+		}
+		if player.Inv.FindByObjectID(row.ItemObjID) == nil || s.deps.World.GetPetByItemObjID(row.ItemObjID) != nil {
+			continue
+		}
+		if !canRestorePet(player, s.CalcUsedPetCost(player.CharID)) {
+			break
+		}
+		s.spawnPet(sess, player, row.ItemObjID, row)
+	}
+}
+
+// petSlotFree 回傳玩家是否還能多控制一隻寵物/召喚獸（world.MaxPets 數量上限）。
+// charisma 為計算上限用的 CHA（含職業加成時由呼叫端加入）。
+func petSlotFree(player *world.PlayerInfo, charisma int) bool {
+	return player.ActivePets < world.MaxPets(charisma)
+}
+
+// canRestorePet 登入重新召喚前的檢查：剩餘 CHA 足夠且未達寵物數量上限，與手動召喚相同。
+func canRestorePet(player *world.PlayerInfo, usedCost int) bool {
+	return int(player.Cha)-usedCost >= 6 && petSlotFree(player, int(player.Cha))
+}
+
+// spawnPet 依 DB 記錄在主人附近生成寵物並發送外觀與控制面板。
+func (s *PetSystem) spawnPet(sess *net.Session, player *world.PlayerInfo, itemObjID int32, petRow *persist.PetRow) *world.PetInfo {
+	ws := s.deps.World
 
 	// 查詢 NPC 模板
 	tmpl := s.deps.Npcs.Get(petRow.NpcID)
 	if tmpl == nil {
-		return
+		return nil
 	}
 
 	// 查詢寵物類型（升級資訊）
@@ -110,7 +169,7 @@ func (s *PetSystem) UsePetCollar(sess *net.Session, player *world.PlayerInfo, in
 	pet := &world.PetInfo{
 		ID:          world.NextNpcID(),
 		OwnerCharID: player.CharID,
-		ItemObjID:   invItem.ObjectID,
+		ItemObjID:   itemObjID,
 		NpcID:       petRow.NpcID,
 		Name:        petRow.Name,
 		Level:       petRow.Level,
@@ -120,6 +179,7 @@ func (s *PetSystem) UsePetCollar(sess *net.Session, player *world.PlayerInfo, in
 		MaxMP:       maxMP,
 		Exp:         petRow.Exp,
 		Lawful:      petRow.Lawful,
+		Tamed:       petRow.Type == persist.PetTypeTamed,
 		GfxID:       tmpl.GfxID,
 		NameID:      tmpl.NameID,
 		MoveSpeed:   tmpl.PassiveSpeed,
@@ -158,6 +218,31 @@ func (s *PetSystem) UsePetCollar(sess *net.Session, player *world.PlayerInfo, in
 			broadcastNpcChat(ws, pet.ID, pet.X, pet.Y, pet.MapID, fmt.Sprintf("$%d", msgID))
 		}
 	}
+	return pet
+}
+
+// petRowOf 將寵物目前狀態轉為 DB 記錄。summoned 表示存檔時寵物是否在外（下次登入自動召喚）。
+func petRowOf(pet *world.PetInfo, summoned bool) *persist.PetRow {
+	petType := persist.PetTypeCollar
+	if pet.Tamed {
+		petType = persist.PetTypeTamed
+	}
+	return &persist.PetRow{
+		ItemObjID:   pet.ItemObjID,
+		ObjID:       pet.ID,
+		OwnerCharID: pet.OwnerCharID,
+		NpcID:       pet.NpcID,
+		Name:        pet.Name,
+		Type:        petType,
+		Level:       pet.Level,
+		HP:          pet.HP,
+		MaxHP:       pet.MaxHP,
+		MP:          pet.MP,
+		MaxMP:       pet.MaxMP,
+		Exp:         pet.Exp,
+		Lawful:      pet.Lawful,
+		Summoned:    summoned,
+	}
 }
 
 // HandlePetAction 處理寵物控制指令。
@@ -263,6 +348,7 @@ func (s *PetSystem) DismissPet(pet *world.PetInfo, player *world.PlayerInfo) {
 			Size:       tmpl.Size,
 			MR:         tmpl.MR,
 			PoisonAtk:  tmpl.PoisonAtk,
+			Tameable:   tmpl.Tameable,
 			X:          pet.X,
 			Y:          pet.Y,
 			MapID:      pet.MapID,
@@ -298,19 +384,7 @@ func (s *PetSystem) CollectPet(pet *world.PetInfo, player *world.PlayerInfo) {
 	// 儲存到 DB
 	if s.deps.PetRepo != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-		s.deps.PetRepo.Save(ctx, &persist.PetRow{
-			ItemObjID: pet.ItemObjID,
-			ObjID:     pet.ID,
-			NpcID:     pet.NpcID,
-			Name:      pet.Name,
-			Level:     pet.Level,
-			HP:        pet.HP,
-			MaxHP:     pet.MaxHP,
-			MP:        pet.MP,
-			MaxMP:     pet.MaxMP,
-			Exp:       pet.Exp,
-			Lawful:    pet.Lawful,
-		})
+		s.deps.PetRepo.Save(ctx, petRowOf(pet, false))
 		cancel()
 	}
 
@@ -616,8 +690,6 @@ var petNoEquipNpcIDs = map[int32]bool{
 // TameNpc 處理馴服野生 NPC 為寵物。
 // Java: C_GiveItem.tamePet() — HP < 1/3、CHA 職業加成、divisor 機制、馴服後立即生成。
 func (s *PetSystem) TameNpc(sess *net.Session, player *world.PlayerInfo, npc *world.NpcInfo) {
-	ws := s.deps.World
-
 	log.Printf("[TameNpc] 開始馴服 npcID=%d npcName=%s HP=%d/%d playerCHA=%d class=%d",
 		npc.NpcID, npc.Name, npc.HP, npc.MaxHP, player.Cha, player.ClassType)
 
@@ -681,16 +753,30 @@ func (s *PetSystem) TameNpc(sess *net.Session, player *world.PlayerInfo, npc *wo
 	log.Printf("[TameNpc] CHA 計算: base=%d classBonus後=%d usedCost=%d 剩餘=%d divisor=%d petCount=%d",
 		player.Cha, charisma+usedCost, usedCost, charisma, divisor, charisma/divisor)
 
-	if charisma/divisor <= 0 {
+	if charisma/divisor <= 0 || !petSlotFree(player, charisma+usedCost) {
 		handler.SendServerMessage(sess, 489) // 你無法一次控制那麼多寵物
 		return
 	}
 
+	s.AdoptNpc(sess, player, npc)
+}
+
+// AdoptNpc 將野生 NPC 轉為玩家的寵物：建立項圈、寫入 DB（重新登入後仍在），並立即生成於世界。
+// 呼叫端須先完成可馴服、CHA 等驗證。回傳 false 表示背包已滿或項圈建立失敗。
+// 由道具馴服（TameNpc）與技能 36 共用。
+func (s *PetSystem) AdoptNpc(sess *net.Session, player *world.PlayerInfo, npc *world.NpcInfo) bool {
+	ws := s.deps.World
+
+	tmpl := s.deps.Npcs.Get(npc.NpcID)
+	if tmpl == nil {
+		return false
+	}
+
 	// 背包空間檢查
 	if player.Inv.Size() >= 180 {
 		log.Printf("[TameNpc] 背包已滿 size=%d", player.Inv.Size())
 		handler.SendServerMessage(sess, 263) // 背包已滿
-		return
+		return false
 	}
 
 	// 移除野生 NPC
@@ -713,7 +799,7 @@ func (s *PetSystem) TameNpc(sess *net.Session, player *world.PlayerInfo, npc *wo
 	}
 	if collarInfo == nil {
 		log.Printf("[TameNpc] 項圈建立失敗 — deps.Items=%v", s.deps.Items != nil)
-		return
+		return false
 	}
 
 	log.Printf("[TameNpc] 馴服成功！項圈 objID=%d，準備生成寵物", collarInfo.ObjectID)
@@ -729,16 +815,19 @@ func (s *PetSystem) TameNpc(sess *net.Session, player *world.PlayerInfo, npc *wo
 	if s.deps.PetRepo != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 		s.deps.PetRepo.Save(ctx, &persist.PetRow{
-			ItemObjID: collarInfo.ObjectID,
-			NpcID:     npc.NpcID,
-			Name:      petName,
-			Level:     npc.Level,
-			HP:        npc.MaxHP,
-			MaxHP:     npc.MaxHP,
-			MP:        npc.MaxMP,
-			MaxMP:     npc.MaxMP,
-			Exp:       750, // Java 預設馴服初始經驗
-			Lawful:    0,
+			ItemObjID:   collarInfo.ObjectID,
+			OwnerCharID: player.CharID,
+			NpcID:       npc.NpcID,
+			Name:        petName,
+			Type:        persist.PetTypeTamed,
+			Summoned:    true,
+			Level:       npc.Level,
+			HP:          npc.MaxHP,
+			MaxHP:       npc.MaxHP,
+			MP:          npc.MaxMP,
+			MaxMP:       npc.MaxMP,
+			Exp:         750, // Java 預設馴服初始經驗
+			Lawful:      0,
 		})
 		cancel()
 	}
@@ -759,6 +848,7 @@ func (s *PetSystem) TameNpc(sess *net.Session, player *world.PlayerInfo, npc *wo
 		MaxMP:       npc.MaxMP,
 		Exp:         750,
 		Lawful:      0,
+		Tamed:       true,
 		GfxID:       tmpl.GfxID,
 		NameID:      tmpl.NameID,
 		MoveSpeed:   tmpl.PassiveSpeed,
@@ -789,6 +879,7 @@ func (s *PetSystem) TameNpc(sess *net.Session, player *world.PlayerInfo, npc *wo
 	// 發送寵物控制面板和 HP 條給主人
 	handler.SendPetCtrlMenu(sess, pet, true)
 	handler.SendPetHpMeter(sess, pet.ID, pet.HP, pet.MaxHP)
+	return true
 }
 
 // Pet collar item IDs（與 handler/pet.go 相同常數，供馴服/進化使用）。
@@ -889,18 +980,9 @@ func (s *PetSystem) evolvePet(sess *net.Session, player *world.PlayerInfo, pet *
 	// 儲存新 DB 記錄
 	if s.deps.PetRepo != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-		s.deps.PetRepo.Save(ctx, &persist.PetRow{
-			ItemObjID: newCollar.ObjectID,
-			NpcID:     pet.NpcID,
-			Name:      pet.Name,
-			Level:     pet.Level,
-			HP:        pet.HP,
-			MaxHP:     pet.MaxHP,
-			MP:        pet.MP,
-			MaxMP:     pet.MaxMP,
-			Exp:       pet.Exp,
-			Lawful:    pet.Lawful,
-		})
+		row := petRowOf(pet, true)
+		row.ItemObjID = newCollar.ObjectID
+		s.deps.PetRepo.Save(ctx, row)
 		cancel()
 	}
 
@@ -0,0 +1,41 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestCanRestorePetRespectsMaxPets(t *testing.T) {
+	p := &world.PlayerInfo{Cha: 18} // MaxPets(18) = 3
+	if !canRestorePet(p, 0) {
+		t.Fatal("first pet rejected")
+	}
+	p.ActivePets = 2
+	if !canRestorePet(p, 6) {
+		t.Fatal("third pet rejected with CHA to spare")
+	}
+	p.ActivePets = 3
+	if canRestorePet(p, 0) {
+		t.Fatal("restored past MaxPets")
+	}
+	p.ActivePets = 0
+	if canRestorePet(p, 13) {
+		t.Fatal("restored with less than 6 CHA left")
+	}
+}
+
+func TestPetSlotFreeUsesCharismaCap(t *testing.T) {
+	p := &world.PlayerInfo{ActivePets: world.MaxPetCount - 1}
+	if !petSlotFree(p, 99) {
+		t.Fatal("slot below MaxPetCount reported full")
+	}
+	p.ActivePets = world.MaxPetCount
+	if petSlotFree(p, 99) {
+		t.Fatal("MaxPetCount not enforced at high CHA")
+	}
+	p.ActivePets = 1
+	if petSlotFree(p, 11) { // MaxPets(11) = 1
+		t.Fatal("low CHA allowed a second pet")
+	}
+}
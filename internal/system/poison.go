@@ -186,7 +186,12 @@ func ApplyNpcPoisonAttack(npc *world.NpcInfo, target *world.PlayerInfo, ws *worl
 		return
 	}
 
-	switch npc.PoisonAtk {
+	infectNpcPoison(target, npc.PoisonAtk, deps)
+}
+
+// infectNpcPoison 以怪物施毒類型感染玩家（1=傷害毒, 2=沉默毒, 4=麻痺毒）。呼叫端負責機率與單毒判定。
+func infectNpcPoison(target *world.PlayerInfo, poisonAtk byte, deps *handler.Deps) {
+	switch poisonAtk {
 	case 1: // 傷害毒（Java: L1DamagePoison.doInfection(_npc, target, 3000, 20)）
 		target.PoisonType = 1
 		target.PoisonTicksLeft = 150 // 30 秒 = 150 ticks
@@ -2,7 +2,6 @@ package system
 
 import (
 	"fmt"
-	"math/rand"
 
 	"github.com/l1jgo/server/internal/core/event"
 	"github.com/l1jgo/server/internal/handler"
@@ -28,23 +27,25 @@ func (s *PvPSystem) HandlePvPAttack(attacker, target *world.PlayerInfo) {
 		return
 	}
 
+	// 距離判定（依武器 Range + 近戰容差）
+	reach, action := weaponAttack(attacker, s.deps)
+	if !inAttackReach(s.deps, attacker, reach, true, target.X, target.Y) {
+		return
+	}
+
 	attacker.Heading = handler.CalcHeading(attacker.X, attacker.Y, target.X, target.Y)
 
 	// 目標絕對屏障：免疫所有傷害（Java: L1AttackPc.dmg0 — AbsoluteBarrier 返回 true）
 	if target.AbsoluteBarrier {
 		nearby := s.deps.World.GetNearbyPlayersAt(target.X, target.Y, target.MapID)
-		for _, viewer := range nearby {
-			handler.SendAttackPacket(viewer.Session, attacker.CharID, target.CharID, 0, attacker.Heading)
-		}
+		handler.BroadcastToPlayers(nearby, handler.BuildActionAttackPacket(action, attacker.CharID, target.CharID, 0, attacker.Heading))
 		return
 	}
 
 	// 安全區內只播放動畫，不造成傷害（Java: isSafetyZone 檢查）
-	if s.inSafetyZone(attacker) || s.inSafetyZone(target) {
+	if !handler.IsDueling(attacker, target) && (s.inSafetyZone(attacker) || s.inSafetyZone(target)) {
 		nearby := s.deps.World.GetNearbyPlayersAt(target.X, target.Y, target.MapID)
-		for _, viewer := range nearby {
-			handler.SendAttackPacket(viewer.Session, attacker.CharID, target.CharID, 0, attacker.Heading)
-		}
+		handler.BroadcastToPlayers(nearby, handler.BuildActionAttackPacket(action, attacker.CharID, target.CharID, 0, attacker.Heading))
 		return
 	}
 
@@ -57,7 +58,9 @@ func (s *PvPSystem) HandlePvPAttack(attacker, target *world.PlayerInfo) {
 
 	// 近戰傷害計算
 	weaponDmg := 4 // 空手
+	enchant := 0
 	if wpn := attacker.Equip.Weapon(); wpn != nil {
+		enchant = wpn.EffectiveEnchant()
 		if info := s.deps.Items.Get(wpn.ItemID); info != nil {
 			if info.DmgSmall > 0 {
 				weaponDmg = info.DmgSmall
@@ -66,15 +69,20 @@ func (s *PvPSystem) HandlePvPAttack(attacker, target *world.PlayerInfo) {
 	}
 
 	ctx := scripting.CombatContext{
-		AttackerLevel:  int(attacker.Level),
-		AttackerSTR:    int(attacker.Str),
-		AttackerDEX:    int(attacker.Dex),
-		AttackerWeapon: weaponDmg,
-		AttackerHitMod: int(attacker.HitMod),
-		AttackerDmgMod: int(attacker.DmgMod),
-		TargetAC:       int(target.AC),
-		TargetLevel:    int(target.Level),
-		TargetMR:       0,
+		AttackerLevel:     int(attacker.Level),
+		AttackerSTR:       int(attacker.Str),
+		AttackerDEX:       int(attacker.Dex),
+		AttackerWeapon:    weaponDmg,
+		AttackerHitMod:    int(attacker.HitMod),
+		AttackerDmgMod:    int(attacker.DmgMod),
+		AttackerBowHitMod: int(attacker.BowHitMod),
+		AttackerBowDmgMod: int(attacker.BowDmgMod),
+		AttackerEnchant:   enchant,
+		TargetAC:          int(target.AC),
+		TargetLevel:       int(target.Level),
+		TargetMR:          0,
+		TargetDodge:       int(target.Dodge),
+		TargetClassType:   int(target.ClassType),
 	}
 	result := s.deps.Scripting.CalcMeleeAttack(ctx)
 
@@ -82,9 +90,12 @@ func (s *PvPSystem) HandlePvPAttack(attacker, target *world.PlayerInfo) {
 	if !result.IsHit {
 		damage = 0
 	}
-
 	nearby := s.deps.World.GetNearbyPlayersAt(target.X, target.Y, target.MapID)
 
+	// 強化攻擊道具（每次攻擊消耗，命中加傷）
+	damage += consumeAttackBoost(s.deps, attacker, target.CharID, nearby, damage > 0)
+	damage = clampDamage(s.deps, scalePvPDamage(s.deps, damage), 0, "pvp_melee")
+
 	// 反擊屏障（skill 91）：PvP 近戰機率反彈（Java: L1AttackPc.calcCounterBarrierDamage）
 	if damage > 0 && target.HasBuff(91) {
 		if world.RandInt(100)+1 <= 25 {
@@ -98,15 +109,17 @@ func (s *PvPSystem) HandlePvPAttack(attacker, target *world.PlayerInfo) {
 				handler.SendHpUpdate(attacker.Session, attacker)
 				damage = 0 // 反彈後原傷害歸零
 				if attacker.HP <= 0 {
-					s.deps.Death.KillPlayer(attacker)
+					if handler.IsDueling(attacker, target) {
+						s.duelDefeat(target, attacker)
+					} else {
+						s.deps.Death.KillPlayerInPvP(attacker)
+					}
 				}
 			}
 		}
 	}
 
-	for _, viewer := range nearby {
-		handler.SendAttackPacket(viewer.Session, attacker.CharID, target.CharID, damage, attacker.Heading)
-	}
+	handler.BroadcastToPlayers(nearby, handler.BuildActionAttackPacket(action, attacker.CharID, target.CharID, damage, attacker.Heading))
 
 	// 浮動傷害數字（PvP 近戰）
 	if attacker.AttackView {
@@ -121,10 +134,10 @@ func (s *PvPSystem) HandlePvPAttack(attacker, target *world.PlayerInfo) {
 		handler.SendHpUpdate(target.Session, target)
 
 		if target.HP <= 0 {
-			// 在 KillPlayer 之前保存決鬥狀態（KillPlayer 會清除 FightId）
-			isDuel := attacker.FightId == target.CharID && target.FightId == attacker.CharID
-			s.deps.Death.KillPlayer(target)
-			if !isDuel {
+			if handler.IsDueling(attacker, target) {
+				s.duelDefeat(attacker, target)
+			} else {
+				s.deps.Death.KillPlayerInPvP(target)
 				s.processPKKill(attacker, target)
 			}
 		}
@@ -140,20 +153,9 @@ func (s *PvPSystem) HandlePvPFarAttack(attacker, target *world.PlayerInfo) {
 
 	attacker.Heading = handler.CalcHeading(attacker.X, attacker.Y, target.X, target.Y)
 
-	// 距離判定
-	dx := attacker.X - target.X
-	dy := attacker.Y - target.Y
-	if dx < 0 {
-		dx = -dx
-	}
-	if dy < 0 {
-		dy = -dy
-	}
-	dist := dx
-	if dy > dist {
-		dist = dy
-	}
-	if dist > 10 {
+	// 距離判定（依武器射程）
+	reach, _ := weaponAttack(attacker, s.deps)
+	if !inAttackReach(s.deps, attacker, reach, false, target.X, target.Y) {
 		return
 	}
 
@@ -173,7 +175,7 @@ func (s *PvPSystem) HandlePvPFarAttack(attacker, target *world.PlayerInfo) {
 	}
 
 	// 安全區內只播放動畫
-	if s.inSafetyZone(attacker) || s.inSafetyZone(target) {
+	if !handler.IsDueling(attacker, target) && (s.inSafetyZone(attacker) || s.inSafetyZone(target)) {
 		handler.SendArrowAttackPacket(attacker.Session, attacker.CharID, target.CharID, 0, attacker.Heading,
 			attacker.X, attacker.Y, target.X, target.Y)
 		nearby := s.deps.World.GetNearbyPlayersAt(target.X, target.Y, target.MapID)
@@ -213,7 +215,9 @@ func (s *PvPSystem) HandlePvPFarAttack(attacker, target *world.PlayerInfo) {
 	}
 
 	bowDmg := 1
+	enchant := 0
 	if wpn := attacker.Equip.Weapon(); wpn != nil {
+		enchant = wpn.EffectiveEnchant()
 		if info := s.deps.Items.Get(wpn.ItemID); info != nil {
 			if info.DmgSmall > 0 {
 				bowDmg = info.DmgSmall
@@ -229,9 +233,12 @@ func (s *PvPSystem) HandlePvPFarAttack(attacker, target *world.PlayerInfo) {
 		AttackerArrowDmg:  arrowDmg,
 		AttackerBowHitMod: int(attacker.BowHitMod),
 		AttackerBowDmgMod: int(attacker.BowDmgMod),
+		AttackerEnchant:   enchant,
 		TargetAC:          int(target.AC),
 		TargetLevel:       int(target.Level),
 		TargetMR:          0,
+		TargetDodge:       int(target.Dodge),
+		TargetClassType:   int(target.ClassType),
 	}
 	result := s.deps.Scripting.CalcRangedAttack(ctx)
 
@@ -239,6 +246,9 @@ func (s *PvPSystem) HandlePvPFarAttack(attacker, target *world.PlayerInfo) {
 	if !result.IsHit {
 		damage = 0
 	}
+	damage += consumeAttackBoost(s.deps, attacker, target.CharID,
+		s.deps.World.GetNearbyPlayersAt(target.X, target.Y, target.MapID), damage > 0) // 強化攻擊道具
+	damage = clampDamage(s.deps, scalePvPDamage(s.deps, damage), 0, "pvp_ranged")
 
 	handler.SendArrowAttackPacket(attacker.Session, attacker.CharID, target.CharID, damage, attacker.Heading,
 		attacker.X, attacker.Y, target.X, target.Y)
@@ -264,9 +274,10 @@ func (s *PvPSystem) HandlePvPFarAttack(attacker, target *world.PlayerInfo) {
 		handler.SendHpUpdate(target.Session, target)
 
 		if target.HP <= 0 {
-			isDuel := attacker.FightId == target.CharID && target.FightId == attacker.CharID
-			s.deps.Death.KillPlayer(target)
-			if !isDuel {
+			if handler.IsDueling(attacker, target) {
+				s.duelDefeat(attacker, target)
+			} else {
+				s.deps.Death.KillPlayerInPvP(target)
 				s.processPKKill(attacker, target)
 			}
 		}
@@ -301,6 +312,17 @@ func (s *PvPSystem) AddLawfulFromNpc(killer *world.PlayerInfo, npcLawful int32)
 //  內部函式
 // ========================================================================
 
+// duelDefeat 決鬥中目標 HP 歸零：決鬥結束，不計 PK、不掉落物品、不扣經驗。
+// duel_restore_on_end 開啟時落敗者不死亡，由 EndDuel 恢復雙方 HP/MP。
+func (s *PvPSystem) duelDefeat(winner, loser *world.PlayerInfo) {
+	if s.deps.Config.Gameplay.DuelRestoreOnEnd {
+		loser.HP = 1 // EndDuel 只恢復存活者
+		handler.EndDuel(winner, loser, handler.DuelEndDefeat, s.deps)
+		return
+	}
+	s.deps.Death.KillDuelLoser(loser)
+}
+
 // inSafetyZone 檢查玩家是否在安全區。
 func (s *PvPSystem) inSafetyZone(p *world.PlayerInfo) bool {
 	if s.deps.MapData == nil {
@@ -398,82 +420,12 @@ func (s *PvPSystem) processPKKill(killer, victim *world.PlayerInfo) {
 		})
 	}
 
-	s.dropItemsOnPKDeath(victim)
+	dropItemsOnDeath(victim, s.deps)
 
 	// 擊殺公告（Java: S_GreenMessage — 受害者等級 ≥ KillMessageLevel 時全伺服器廣播）
 	s.broadcastKillMessage(killer, victim)
 }
 
-// dropItemsOnPKDeath 根據 Lua 公式從受害者身上掉落物品。
-func (s *PvPSystem) dropItemsOnPKDeath(victim *world.PlayerInfo) {
-	dropResult := s.deps.Scripting.CalcPKItemDrop(victim.Lawful)
-	if !dropResult.ShouldDrop || dropResult.Count <= 0 {
-		return
-	}
-
-	for i := 0; i < dropResult.Count; i++ {
-		s.dropOneItem(victim)
-	}
-}
-
-// dropOneItem 隨機選擇一個物品從受害者背包掉落到地面。
-func (s *PvPSystem) dropOneItem(victim *world.PlayerInfo) {
-	if len(victim.Inv.Items) == 0 {
-		return
-	}
-
-	idx := rand.Intn(len(victim.Inv.Items))
-	item := victim.Inv.Items[idx]
-
-	if item.ItemID == world.AdenaItemID {
-		return
-	}
-
-	itemInfo := s.deps.Items.Get(item.ItemID)
-	if itemInfo == nil || !itemInfo.Tradeable {
-		return
-	}
-
-	// 脫裝備
-	if item.Equipped {
-		slot := s.deps.Equip.FindEquippedSlot(victim, item)
-		if slot != world.SlotNone {
-			s.deps.Equip.UnequipSlot(victim.Session, victim, slot)
-		}
-	}
-
-	dropCount := int32(1)
-	if item.Stackable && item.Count > 0 {
-		dropCount = item.Count
-	}
-
-	gndItem := &world.GroundItem{
-		ID:         item.ObjectID,
-		ItemID:     item.ItemID,
-		Count:      dropCount,
-		EnchantLvl: item.EnchantLvl,
-		Name:       itemInfo.Name,
-		GrdGfx:     itemInfo.GrdGfx,
-		X:          victim.X,
-		Y:          victim.Y,
-		MapID:      victim.MapID,
-	}
-	s.deps.World.AddGroundItem(gndItem)
-
-	victim.Inv.RemoveItem(item.ObjectID, 0)
-	handler.SendRemoveInventoryItem(victim.Session, item.ObjectID)
-	handler.SendWeightUpdate(victim.Session, victim)
-
-	nearby := s.deps.World.GetNearbyPlayersAt(victim.X, victim.Y, victim.MapID)
-	for _, viewer := range nearby {
-		handler.SendDropItem(viewer.Session, gndItem)
-	}
-
-	handler.SendServerMessageStr(victim.Session, 638, itemInfo.Name)
-
-	s.deps.Log.Info(fmt.Sprintf("PK 死亡掉落物品  受害者=%s  道具=%s  數量=%d", victim.Name, itemInfo.Name, dropCount))
-}
-
 // breakPlayerSleep 被攻擊時解除玩家睡眠狀態（Java: L1PcInstance.wakeUp）。
 func (s *PvPSystem) breakPlayerSleep(target *world.PlayerInfo) {
 	target.Sleeped = false
@@ -0,0 +1,50 @@
+package system
+
+import (
+	"testing"
+	"time"
+
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestCounterBarrierKillInDuelEndsDuel(t *testing.T) {
+	world.SetRand(world.NewRand(5))
+	t.Cleanup(func() { world.SetRand(world.NewRand(time.Now().UnixNano())) })
+
+	deps := newTestDeps(t)
+	deps.World = world.NewState()
+	deps.Config.Combat.PvPDamageRate = 1
+	deps.Config.Gameplay.DuelRestoreOnEnd = true
+	deps.Items = newTestItemsWithWeapons(t, "  - {item_id: 1, name: 雙手劍, type: tohandsword, dmg_small: 50, dmg_large: 50}\n", "")
+	death := &fakeDeath{}
+	deps.Death = death
+
+	attacker := &world.PlayerInfo{
+		SessionID: 1, Session: newTestSession(t, 1), CharID: 1, Name: "attacker", Level: 50, Str: 18, Dex: 18,
+		X: 32700, Y: 32800, MapID: 4, HP: 50, MaxHP: 200, MP: 10, MaxMP: 80, Inv: world.NewInventory(),
+	}
+	target := &world.PlayerInfo{
+		SessionID: 2, Session: newTestSession(t, 2), CharID: 2, Name: "knight", Level: 50, AC: 10,
+		X: 32701, Y: 32800, MapID: 4, HP: 30000, MaxHP: 30000, Inv: world.NewInventory(),
+	}
+	target.Equip.Set(world.SlotWeapon, target.Inv.AddItem(1, 1, "雙手劍", 0, 0, false, 1))
+	target.AddBuff(&world.ActiveBuff{SkillID: 91, TicksLeft: 1000})
+	attacker.FightId, target.FightId = target.CharID, attacker.CharID
+	attacker.DuelActive, target.DuelActive = true, true
+	deps.World.AddPlayer(attacker)
+	deps.World.AddPlayer(target)
+
+	s := NewPvPSystem(deps)
+	for i := 0; i < 200 && attacker.DuelActive; i++ {
+		s.HandlePvPAttack(attacker, target)
+	}
+	if attacker.DuelActive || target.DuelActive {
+		t.Fatal("counter barrier never ended the duel")
+	}
+	if len(death.pvpKilled) != 0 {
+		t.Errorf("duel loser killed by counter barrier: %d KillPlayerInPvP calls", len(death.pvpKilled))
+	}
+	if attacker.HP <= 0 || attacker.Dead {
+		t.Errorf("duel loser HP %d dead %v, want alive", attacker.HP, attacker.Dead)
+	}
+}
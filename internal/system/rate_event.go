@@ -0,0 +1,210 @@
+package system
+
+import (
+	"fmt"
+	"time"
+
+	coresys "github.com/l1jgo/server/internal/core/system"
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/world"
+	"go.uber.org/zap"
+)
+
+// 活動倍率種類
+const (
+	rateKindExp  = "exp"
+	rateKindDrop = "drop"
+	rateKindGold = "gold"
+)
+
+// 活動時間格式（設定檔 [event] start / end）
+const eventTimeLayout = "2006-01-02 15:04"
+
+// rateEvent 記錄單項進行中的活動倍率。
+type rateEvent struct {
+	rate  float64
+	until time.Time
+}
+
+// RateEventSystem 管理「雙倍週末」等限時活動倍率。
+// 設定檔 [event] 可排程一段時間窗，GM 亦可用 .event 臨時開啟；
+// 活動期間覆寫 [rates] 的經驗/掉寶/金幣倍率，到期自動恢復並全服公告。
+// Phase 3（PostUpdate），每秒檢查一次到期。
+type RateEventSystem struct {
+	deps   *handler.Deps
+	events map[string]*rateEvent // kind → 進行中的活動
+
+	schedStart time.Time
+	schedEnd   time.Time
+	schedDone  bool // 排程時間窗已觸發過（避免重複開啟）
+	tickAcc    int
+}
+
+// NewRateEventSystem 建構活動倍率系統，並解析設定檔中的排程時間窗。
+func NewRateEventSystem(deps *handler.Deps) *RateEventSystem {
+	s := &RateEventSystem{
+		deps:   deps,
+		events: make(map[string]*rateEvent),
+	}
+	cfg := deps.Config.Event
+	if cfg.Start == "" || cfg.End == "" {
+		s.schedDone = true
+		return s
+	}
+	start, err1 := time.ParseInLocation(eventTimeLayout, cfg.Start, time.Local)
+	end, err2 := time.ParseInLocation(eventTimeLayout, cfg.End, time.Local)
+	if err1 != nil || err2 != nil || !end.After(start) {
+		deps.Log.Warn("活動倍率時間設定無效，已忽略排程",
+			zap.String("start", cfg.Start), zap.String("end", cfg.End))
+		s.schedDone = true
+		return s
+	}
+	s.schedStart = start
+	s.schedEnd = end
+	return s
+}
+
+func (s *RateEventSystem) Phase() coresys.Phase { return coresys.PhasePostUpdate }
+
+func (s *RateEventSystem) Update(_ time.Duration) {
+	// 每 5 tick（約 1 秒）檢查一次
+	s.tickAcc++
+	if s.tickAcc < 5 {
+		return
+	}
+	s.tickAcc = 0
+
+	now := time.Now()
+
+	// 排程時間窗開始
+	if !s.schedDone && !now.Before(s.schedStart) {
+		s.schedDone = true
+		if now.Before(s.schedEnd) {
+			cfg := s.deps.Config.Event
+			for kind, rate := range map[string]float64{
+				rateKindExp:  cfg.ExpRate,
+				rateKindDrop: cfg.DropRate,
+				rateKindGold: cfg.GoldRate,
+			} {
+				if rate > 0 {
+					s.start(kind, rate, s.schedEnd)
+				}
+			}
+		}
+	}
+
+	// 到期恢復
+	for kind, ev := range s.events {
+		if now.Before(ev.until) {
+			continue
+		}
+		delete(s.events, kind)
+		s.announce(fmt.Sprintf("\\f3%s活動已結束，倍率恢復為 %.1f 倍。", rateKindLabel(kind), s.baseRate(kind)))
+		s.deps.Log.Info(fmt.Sprintf("活動倍率結束  種類=%s", kind))
+	}
+}
+
+// ExpRate 回傳目前生效的經驗倍率。
+func (s *RateEventSystem) ExpRate() float64 { return s.current(rateKindExp) }
+
+// DropRate 回傳目前生效的掉寶倍率。
+func (s *RateEventSystem) DropRate() float64 { return s.current(rateKindDrop) }
+
+// GoldRate 回傳目前生效的金幣倍率。
+func (s *RateEventSystem) GoldRate() float64 { return s.current(rateKindGold) }
+
+// StartEvent 開啟單項活動倍率，持續 dur 後自動恢復。
+func (s *RateEventSystem) StartEvent(kind string, rate float64, dur time.Duration) bool {
+	if rateKindLabel(kind) == "" || rate <= 0 || dur <= 0 {
+		return false
+	}
+	s.start(kind, rate, time.Now().Add(dur))
+	return true
+}
+
+// StopEvent 立即結束所有進行中的活動。
+func (s *RateEventSystem) StopEvent() {
+	for kind := range s.events {
+		delete(s.events, kind)
+		s.announce(fmt.Sprintf("\\f3%s活動已結束，倍率恢復為 %.1f 倍。", rateKindLabel(kind), s.baseRate(kind)))
+	}
+}
+
+// Status 回傳各項倍率的目前狀態。
+func (s *RateEventSystem) Status() []string {
+	lines := make([]string, 0, 3)
+	for _, kind := range []string{rateKindExp, rateKindDrop, rateKindGold} {
+		if ev := s.events[kind]; ev != nil {
+			lines = append(lines, fmt.Sprintf("%s: %.1f 倍（活動中，至 %s）",
+				rateKindLabel(kind), ev.rate, ev.until.Format(eventTimeLayout)))
+		} else {
+			lines = append(lines, fmt.Sprintf("%s: %.1f 倍", rateKindLabel(kind), s.baseRate(kind)))
+		}
+	}
+	return lines
+}
+
+func (s *RateEventSystem) start(kind string, rate float64, until time.Time) {
+	s.events[kind] = &rateEvent{rate: rate, until: until}
+	s.announce(fmt.Sprintf("\\f2%s活動開始！倍率 %.1f 倍，至 %s 結束。",
+		rateKindLabel(kind), rate, until.Format(eventTimeLayout)))
+	s.deps.Log.Info(fmt.Sprintf("活動倍率開始  種類=%s  倍率=%.1f  結束=%s",
+		kind, rate, until.Format(eventTimeLayout)))
+}
+
+func (s *RateEventSystem) current(kind string) float64 {
+	if ev := s.events[kind]; ev != nil {
+		return ev.rate
+	}
+	return s.baseRate(kind)
+}
+
+func (s *RateEventSystem) baseRate(kind string) float64 {
+	rates := s.deps.Config.Rates
+	switch kind {
+	case rateKindExp:
+		return rates.ExpRate
+	case rateKindDrop:
+		return rates.DropRate
+	case rateKindGold:
+		return rates.GoldRate
+	}
+	return 0
+}
+
+// announce 全服綠色公告。
+func (s *RateEventSystem) announce(msg string) {
+	data := handler.BuildGreenMessage(msg)
+	s.deps.World.AllPlayers(func(p *world.PlayerInfo) {
+		p.Session.Send(data)
+	})
+}
+
+func rateKindLabel(kind string) string {
+	switch kind {
+	case rateKindExp:
+		return "經驗值"
+	case rateKindDrop:
+		return "掉寶"
+	case rateKindGold:
+		return "金幣"
+	}
+	return ""
+}
+
+// mapExpMultiplier 回傳地圖的擊殺經驗倍率（map_list exp_multiplier，預設 1.0）。
+// 疊加於 currentExpRate 之上。
+func mapExpMultiplier(deps *handler.Deps, mapID int16) float64 {
+	if deps.MapData == nil {
+		return 1.0
+	}
+	return deps.MapData.ExpMultiplier(mapID)
+}
+
+// currentExpRate 回傳目前生效的經驗倍率（含活動覆寫）。
+func currentExpRate(deps *handler.Deps) float64 {
+	if deps.RateEvent != nil {
+		return deps.RateEvent.ExpRate()
+	}
+	return deps.Config.Rates.ExpRate
+}
@@ -3,6 +3,7 @@ package system
 import (
 	"time"
 
+	"github.com/l1jgo/server/internal/config"
 	coresys "github.com/l1jgo/server/internal/core/system"
 	"github.com/l1jgo/server/internal/net"
 	"github.com/l1jgo/server/internal/net/packet"
@@ -25,14 +26,18 @@ import (
 //
 // Approach: count ticks. HP regen triggers every hpInterval ticks (level-based).
 // MP regen triggers every mpInterval ticks (fixed ~16 seconds = 80 ticks).
+//
+// Resting (.sit) scales HP and MP regen by rest_regen_pct; Meditation (skill 32)
+// scales MP regen by meditation_mp_regen_pct. Both stack.
 type RegenSystem struct {
 	world     *world.State
 	lua       *scripting.Engine
+	gameplay  *config.GameplayConfig
 	tickCount int
 }
 
-func NewRegenSystem(ws *world.State, lua *scripting.Engine) *RegenSystem {
-	return &RegenSystem{world: ws, lua: lua}
+func NewRegenSystem(ws *world.State, lua *scripting.Engine, gameplay *config.GameplayConfig) *RegenSystem {
+	return &RegenSystem{world: ws, lua: lua, gameplay: gameplay}
 }
 
 func (s *RegenSystem) Phase() coresys.Phase { return coresys.PhasePostUpdate }
@@ -90,6 +95,9 @@ func (s *RegenSystem) tickHPRegen(p *world.PlayerInfo) {
 		HasExoticVitalize: p.HasBuff(226),
 		HasAdditionalFire: p.HasBuff(238),
 	})
+	if p.Resting {
+		amount = scaleRegen(amount, s.gameplay.RestRegenPct)
+	}
 	if amount == 0 {
 		return
 	}
@@ -131,6 +139,12 @@ func (s *RegenSystem) tickMPRegen(p *world.PlayerInfo) {
 		HasAdditionalFire: p.HasBuff(238),
 		HasBluePotion:     p.HasBuff(1002),
 	})
+	if p.Resting {
+		amount = scaleRegen(amount, s.gameplay.RestRegenPct)
+	}
+	if p.HasBuff(32) { // Meditation
+		amount = scaleRegen(amount, s.gameplay.MeditationMpRegenPct)
+	}
 	if amount == 0 {
 		return
 	}
@@ -151,6 +165,15 @@ func (s *RegenSystem) tickMPRegen(p *world.PlayerInfo) {
 	sendMPUpdatePacket(p.Session, p.MP, p.MaxMP)
 }
 
+// scaleRegen applies a percent multiplier to a positive regen amount.
+// Penalties (negative amounts, e.g. from hunger or weight) are left as is.
+func scaleRegen(amount, pct int) int {
+	if amount <= 0 || pct <= 0 {
+		return amount
+	}
+	return amount * pct / 100
+}
+
 // ---------- Packet helpers ----------
 // These duplicate the minimal packet builders to avoid circular import with handler/.
 
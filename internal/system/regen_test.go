@@ -0,0 +1,18 @@
+package system
+
+import "testing"
+
+func TestScaleRegen(t *testing.T) {
+	cases := []struct{ amount, pct, want int }{
+		{10, 150, 15},
+		{10, 100, 10},
+		{10, 0, 10},   // 未設定 = 不加成
+		{-3, 200, -3}, // 飢餓/負重懲罰不放大
+		{0, 200, 0},
+	}
+	for _, c := range cases {
+		if got := scaleRegen(c.amount, c.pct); got != c.want {
+			t.Errorf("scaleRegen(%d, %d) = %d, want %d", c.amount, c.pct, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package system
+
+import (
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// withRestedBonus 擊殺經驗套用休息經驗加成（[gameplay] rested_exp_bonus_pct），
+// 加成部分由休息經驗池扣除，池不足時僅給剩餘額度；池用盡時提示玩家。
+func withRestedBonus(player *world.PlayerInfo, expGain int32, deps *handler.Deps) int32 {
+	pct := deps.Config.Gameplay.RestedExpBonusPct
+	if pct <= 0 || player.RestedXP <= 0 || expGain <= 0 {
+		return expGain
+	}
+	bonus := min(int64(expGain)*int64(pct)/100, player.RestedXP)
+	if bonus <= 0 {
+		return expGain
+	}
+	player.RestedXP -= bonus
+	player.Dirty = true
+	if player.RestedXP == 0 {
+		handler.SendSystemMessage(player.Session, "休息經驗已用盡。")
+	}
+	return expGain + int32(bonus)
+}
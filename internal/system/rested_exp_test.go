@@ -0,0 +1,44 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestWithRestedBonusDrawsFromPool(t *testing.T) {
+	deps := newTestDeps(t)
+	deps.Config.Gameplay.RestedExpBonusPct = 50
+
+	tests := []struct {
+		name     string
+		pool     int64
+		exp      int32
+		want     int32
+		wantPool int64
+	}{
+		{"池充足", 1000, 100, 150, 950},
+		{"池不足只給剩餘", 20, 100, 120, 0},
+		{"池為空", 0, 100, 100, 0},
+		{"無經驗", 1000, 0, 0, 1000},
+	}
+	for _, tt := range tests {
+		p := &world.PlayerInfo{Session: newTestSession(t, 1), RestedXP: tt.pool}
+		if got := withRestedBonus(p, tt.exp, deps); got != tt.want || p.RestedXP != tt.wantPool {
+			t.Errorf("%s: exp %d pool %d, want exp %d pool %d", tt.name, got, p.RestedXP, tt.want, tt.wantPool)
+		}
+		// 池剛好用盡時提示玩家
+		if msgs := len(sentPackets(p.Session)); (msgs > 0) != (tt.pool > 0 && p.RestedXP == 0) {
+			t.Errorf("%s: sent %d messages", tt.name, msgs)
+		}
+	}
+}
+
+func TestWithRestedBonusDisabled(t *testing.T) {
+	deps := newTestDeps(t)
+	p := &world.PlayerInfo{Session: newTestSession(t, 1), RestedXP: 1000}
+
+	if got := withRestedBonus(p, 100, deps); got != 100 || p.RestedXP != 1000 {
+		t.Errorf("rested_exp_bonus_pct 0: exp %d pool %d, want 100 / 1000", got, p.RestedXP)
+	}
+}
@@ -179,6 +179,10 @@ func (s *ShopSystem) SellToNpc(sess *net.Session, r *packet.Reader, count int, p
 		if invItem == nil {
 			continue
 		}
+		// 不可交易/封印物品不可販賣（收購清單已過濾，此處防偽造封包）
+		if !itemTransferable(s.deps, invItem) {
+			continue
+		}
 
 		// 查詢該物品的收購價格
 		var purchPrice int32
@@ -211,24 +215,8 @@ func (s *ShopSystem) SellToNpc(sess *net.Session, r *packet.Reader, count int, p
 	}
 
 	if totalEarned > 0 {
-		// 給予金幣
-		adena := player.Inv.FindByItemID(world.AdenaItemID)
-		wasExisting := adena != nil
-
-		adenaInfo := s.deps.Items.Get(world.AdenaItemID)
-		adenaName := "Adena"
-		adenaGfx := int32(318)
-		if adenaInfo != nil {
-			adenaName = adenaInfo.Name
-			adenaGfx = adenaInfo.InvGfx
-		}
-
-		item := player.Inv.AddItem(world.AdenaItemID, int32(totalEarned), adenaName, adenaGfx, 0, true, 1)
-		if wasExisting {
-			handler.SendItemCountUpdate(sess, item)
-		} else {
-			handler.SendAddItem(sess, item)
-		}
+		// 給予金幣（超出身上上限的部分存入個人倉庫）
+		giveAdena(s.deps, player, totalEarned)
 	}
 	handler.SendWeightUpdate(sess, player)
 
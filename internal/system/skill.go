@@ -18,6 +18,7 @@ const (
 	skillMsgNotEnoughMP uint16 = 278 // "因魔力不足而無法使用魔法。"
 	skillMsgNotEnoughHP uint16 = 279 // "因體力不足而無法使用魔法。"
 	skillMsgCastFail    uint16 = 280 // "施展魔法失敗。"
+	skillMsgMapDisabled uint16 = 563 // "你無法在這個地方使用。"
 )
 
 // calcMagicLevel 計算職業魔法等級（Go 側鏡像，與 Lua class_feature.lua 一致）。
@@ -143,6 +144,14 @@ func (s *SkillSystem) processSkill(sessID uint64, skillID, targetID int32) {
 
 	// --- 驗證 ---
 
+	// 地圖禁止施法（Java: L1Map.isUsableSkill），GM 不受限
+	if !player.IsGM() && s.deps.MapData != nil {
+		if mi := s.deps.MapData.GetInfo(player.MapID); mi != nil && !mi.UsableSkill {
+			handler.SendServerMessage(sess, skillMsgMapDisabled)
+			return
+		}
+	}
+
 	// 絕對屏障：施法時自動解除（Java: C_UseSkill.java 第 353-358 行）
 	if player.AbsoluteBarrier {
 		s.cancelAbsoluteBarrier(player)
@@ -153,6 +162,12 @@ func (s *SkillSystem) processSkill(sessID uint64, skillID, targetID int32) {
 		s.cancelInvisibility(player)
 	}
 
+	// 施放攻擊技能即解除登入/傳送保護，並自動起身、結束冥想
+	if skill.Target == "attack" {
+		handler.ClearSpawnProtect(player, s.deps)
+		handler.StandUp(player, s.deps)
+	}
+
 	// 麻痺/暈眩/凍結/睡眠/沉默時無法施法
 	if player.Paralyzed || player.Sleeped || player.Silenced {
 		return
@@ -245,6 +260,12 @@ func (s *SkillSystem) processSkill(sessID uint64, skillID, targetID int32) {
 		}
 	}
 
+	// --- buff 數量上限預檢（buff_overflow = "reject"）：在扣除 MP/材料前拒絕 ---
+	if target := s.castBuffTarget(player, skill, targetID); target != nil && !s.canTakeBuff(target, skill) {
+		handler.SendSystemMessage(sess, "目標身上的輔助效果已達上限。")
+		return
+	}
+
 	// --- 消耗資源（MP、HP、材料）---
 	if skill.MpConsume > 0 {
 		player.MP -= int16(skill.MpConsume)
@@ -268,11 +289,7 @@ func (s *SkillSystem) processSkill(sessID uint64, skillID, targetID int32) {
 	}
 
 	// --- 設定全域冷卻 ---
-	delay := skill.ReuseDelay
-	if delay <= 0 {
-		delay = 1000
-	}
-	player.SkillDelayUntil = now.Add(time.Duration(delay) * time.Millisecond)
+	player.SkillDelayUntil = now.Add(s.skillReuseDelay(player, skill))
 
 	// --- 復活技能：特殊路由 ---
 	if s.isResurrectionSkill(skill) {
@@ -308,6 +325,33 @@ func (s *SkillSystem) processSkill(sessID uint64, skillID, targetID int32) {
 	}
 }
 
+// castBuffTarget 回傳施法會取得 buff 的玩家（供 buff 上限預檢），無玩家 buff 時回傳 nil。
+// 目標解析與 executeBuffSkill 相同：目標 ID 為 0 或自己 → 施法者；其他玩家 → 該玩家；NPC → nil。
+func (s *SkillSystem) castBuffTarget(player *world.PlayerInfo, skill *data.SkillInfo, targetID int32) *world.PlayerInfo {
+	if skill.BuffDuration <= 0 || skill.Target == "attack" || s.isResurrectionSkill(skill) {
+		return nil
+	}
+	switch skill.SkillID {
+	case 21, 12, 107: // 物品強化技能：buff 套用在施法者身上（targetID 為物品）
+		return player
+	case 73, 100:
+		return nil
+	}
+	if skill.Target != "buff" || targetID == 0 || targetID == player.CharID {
+		return player
+	}
+	return s.deps.World.GetByCharID(targetID)
+}
+
+// refundSkillMP 施法在套用 buff 時被拒絕（buff 上限），退還本次消耗的 MP。
+func (s *SkillSystem) refundSkillMP(sess *net.Session, player *world.PlayerInfo, skill *data.SkillInfo) {
+	if skill.MpConsume <= 0 {
+		return
+	}
+	player.MP = min(player.MP+int16(skill.MpConsume), player.MaxMP)
+	sendMpUpdate(sess, player)
+}
+
 // consumeSkillResources 扣除 MP/HP/材料並設定冷卻。
 func (s *SkillSystem) consumeSkillResources(sess *net.Session, player *world.PlayerInfo, skill *data.SkillInfo) {
 	if skill.MpConsume > 0 {
@@ -330,11 +374,30 @@ func (s *SkillSystem) consumeSkillResources(sess *net.Session, player *world.Pla
 			handler.SendWeightUpdate(sess, player)
 		}
 	}
+	player.SkillDelayUntil = time.Now().Add(s.skillReuseDelay(player, skill))
+}
+
+// skillReuseDelay 回傳技能冷卻時間：加速／勇敢狀態依 [combat] cast_haste_pct / cast_brave_pct
+// 縮短（兩者相加），並以 cast_min_delay_ms 為下限。技能本身冷卻低於下限時不受影響。
+func (s *SkillSystem) skillReuseDelay(player *world.PlayerInfo, skill *data.SkillInfo) time.Duration {
 	delay := skill.ReuseDelay
 	if delay <= 0 {
 		delay = 1000
 	}
-	player.SkillDelayUntil = time.Now().Add(time.Duration(delay) * time.Millisecond)
+	cfg := &s.deps.Config.Combat
+	cut := 0
+	if player.MoveSpeed == 1 {
+		cut += cfg.CastHastePct
+	}
+	if player.BraveSpeed > 0 {
+		cut += cfg.CastBravePct
+	}
+	if cut > 0 {
+		reduced := delay * (100 - min(cut, 100)) / 100
+		floor := min(cfg.CastMinDelayMs, delay)
+		delay = max(reduced, floor)
+	}
+	return time.Duration(delay) * time.Millisecond
 }
 
 // ========================================================================
@@ -446,6 +509,17 @@ func (s *SkillSystem) resurrectPlayer(target *world.PlayerInfo, caster *world.Pl
 	s.deps.Log.Info(fmt.Sprintf("玩家復活  目標=%s  施法者=%s  技能ID=%d", target.Name, caster.Name, skill.SkillID))
 }
 
+// ResurrectPlayer 以復活技能效果復活死亡玩家（復活道具用，不檢查機率與 MP）。
+// 技能不存在、非復活技能或目標未死亡時回傳 false。
+func (s *SkillSystem) ResurrectPlayer(target, caster *world.PlayerInfo, skillID int32) bool {
+	skill := s.deps.Skills.Get(skillID)
+	if skill == nil || !s.isResurrectionSkill(skill) || !target.Dead {
+		return false
+	}
+	s.resurrectPlayer(target, caster, skill)
+	return true
+}
+
 // ========================================================================
 //  攻擊技能
 // ========================================================================
@@ -474,7 +548,7 @@ func (s *SkillSystem) executeAttackSkill(sess *net.Session, player *world.Player
 		return
 	}
 
-	player.Heading = CalcHeading(player.X, player.Y, npc.X, npc.Y)
+	player.Heading = handler.CalcHeading(player.X, player.Y, npc.X, npc.Y)
 
 	// 起死回生術 (18)：對不死族 NPC 機率即死
 	if skill.SkillID == 18 {
@@ -482,9 +556,13 @@ func (s *SkillSystem) executeAttackSkill(sess *net.Session, player *world.Player
 		return
 	}
 
-	// Triple Arrow (132)：消耗 1 箭矢
+	// Triple Arrow (132)：需裝備弓並消耗 1 箭矢
 	if skill.SkillID == 132 {
-		arrow := FindArrow(player, s.deps)
+		if world.AmmoType(handler.RangedWeaponType(player, s.deps)) != "arrow" {
+			handler.SendServerMessage(sess, skillMsgCastFail)
+			return
+		}
+		arrow := handler.FindArrow(player, s.deps)
 		if arrow == nil {
 			handler.SendServerMessage(sess, skillMsgCastFail)
 			return
@@ -537,6 +615,10 @@ func (s *SkillSystem) executeAttackSkill(sess *net.Session, player *world.Player
 			TargetLevel:        int(n.Level),
 			TargetMR:           int(n.MR),
 			TargetMP:           int(n.MP),
+			TargetFireRes:      int(n.FireRes),
+			TargetWaterRes:     int(n.WaterRes),
+			TargetWindRes:      int(n.WindRes),
+			TargetEarthRes:     int(n.EarthRes),
 		}
 	}
 
@@ -548,7 +630,19 @@ func (s *SkillSystem) executeAttackSkill(sess *net.Session, player *world.Player
 	}
 
 	res := s.deps.Scripting.CalcSkillDamage(buildCtx(npc))
-	hits := []hitTarget{{npc: npc, dmg: int32(res.Damage), hitCount: res.HitCount, drainMP: int32(res.DrainMP)}}
+	hits := []hitTarget{{npc: npc, dmg: clampDamage(s.deps, weatherSkillDamage(s.deps, player.MapID, skill.Attr, int32(res.Damage)), skill.MaxDamage, "skill"), hitCount: res.HitCount, drainMP: int32(res.DrainMP)}}
+
+	// 貫穿技能：命中施法者→目標直線上（延伸至射程）的所有 NPC，彈道終點為直線末端
+	piercing := skill.Through && skill.Area == 0
+	endX, endY := npc.X, npc.Y
+	if piercing {
+		var line []*world.NpcInfo
+		line, endX, endY = s.collectPiercingTargets(player, npc, maxRange)
+		for _, other := range line {
+			r := s.deps.Scripting.CalcSkillDamage(buildCtx(other))
+			hits = append(hits, hitTarget{npc: other, dmg: clampDamage(s.deps, weatherSkillDamage(s.deps, player.MapID, skill.Attr, int32(r.Damage)), skill.MaxDamage, "skill"), hitCount: r.HitCount, drainMP: int32(r.DrainMP)})
+		}
+	}
 
 	if skill.Area > 0 {
 		allNpcs := ws.GetNearbyNpcs(npc.X, npc.Y, npc.MapID)
@@ -558,7 +652,7 @@ func (s *SkillSystem) executeAttackSkill(sess *net.Session, player *world.Player
 			}
 			if chebyshevDist(npc.X, npc.Y, other.X, other.Y) <= int32(skill.Area) {
 				r := s.deps.Scripting.CalcSkillDamage(buildCtx(other))
-				hits = append(hits, hitTarget{npc: other, dmg: int32(r.Damage), hitCount: r.HitCount, drainMP: int32(r.DrainMP)})
+				hits = append(hits, hitTarget{npc: other, dmg: clampDamage(s.deps, weatherSkillDamage(s.deps, player.MapID, skill.Attr, int32(r.Damage)), skill.MaxDamage, "skill"), hitCount: r.HitCount, drainMP: int32(r.DrainMP)})
 			}
 		}
 	}
@@ -566,9 +660,11 @@ func (s *SkillSystem) executeAttackSkill(sess *net.Session, player *world.Player
 	nearby := ws.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID)
 
 	isPhysicalSkill := skill.DamageValue == 0 && skill.DamageDice == 0
+	// 三重矢：每段為獨立的弓箭攻擊（Java: TRIPLE_ARROW 連續 3 次 onAction → S_UseArrowSkill）
+	arrowSkill := skill.SkillID == 132
 
 	useType := byte(6)
-	if skill.Area > 0 {
+	if skill.Area > 0 || piercing {
 		useType = 8
 	}
 
@@ -580,8 +676,21 @@ func (s *SkillSystem) executeAttackSkill(sess *net.Session, player *world.Player
 
 		for h := 0; h < hitsToApply; h++ {
 			dmg := t.dmg
+			if h > 0 {
+				// 後續段數各自判定命中與傷害，避免 3 段顯示同一數值
+				dmg = clampDamage(s.deps, weatherSkillDamage(s.deps, player.MapID, skill.Attr, int32(s.deps.Scripting.CalcSkillDamage(buildCtx(t.npc)).Damage)), skill.MaxDamage, "skill")
+			}
 
-			if isPhysicalSkill {
+			if arrowSkill {
+				// 每段一個箭矢封包（序號遞增），客戶端依序播放 3 支箭
+				for _, viewer := range nearby {
+					handler.SendArrowAttackPacket(viewer.Session, player.CharID, t.npc.ID, dmg, player.Heading,
+						player.X, player.Y, t.npc.X, t.npc.Y)
+				}
+				if h == 0 && skill.CastGfx > 0 {
+					handler.BroadcastToPlayers(nearby, handler.BuildSkillEffect(player.CharID, skill.CastGfx))
+				}
+			} else if isPhysicalSkill {
 				atkData := handler.BuildAttackPacket(player.CharID, t.npc.ID, dmg, player.Heading)
 				handler.BroadcastToPlayers(nearby, atkData)
 				if skill.CastGfx > 0 {
@@ -593,10 +702,14 @@ func (s *SkillSystem) executeAttackSkill(sess *net.Session, player *world.Player
 				if gfxID <= 0 {
 					gfxID = int32(skill.ActionID)
 				}
+				tx, ty := t.npc.X, t.npc.Y
+				if piercing {
+					tx, ty = endX, endY
+				}
 				for _, viewer := range nearby {
 					handler.SendUseAttackSkill(viewer.Session, player.CharID, t.npc.ID,
 						int16(dmg), player.Heading, gfxID, useType,
-						int32(player.X), int32(player.Y), int32(t.npc.X), int32(t.npc.Y))
+						int32(player.X), int32(player.Y), tx, ty)
 				}
 			}
 
@@ -623,12 +736,7 @@ func (s *SkillSystem) executeAttackSkill(sess *net.Session, player *world.Player
 			// 技能傷害累加仇恨
 			AddHate(t.npc, sess.ID, dmg)
 
-			hpRatio := int16(0)
-			if t.npc.MaxHP > 0 {
-				hpRatio = int16((t.npc.HP * 100) / t.npc.MaxHP)
-			}
-			hpData := handler.BuildHpMeter(t.npc.ID, hpRatio)
-			handler.BroadcastToPlayers(nearby, hpData)
+			broadcastNpcHpMeter(t.npc, nearby, s.deps)
 
 			if t.npc.HP <= 0 {
 				handleNpcDeath(t.npc, player, nearby, s.deps)
@@ -637,6 +745,13 @@ func (s *SkillSystem) executeAttackSkill(sess *net.Session, player *world.Player
 		}
 	}
 
+	// 擊退/拉近：傷害後仍存活的目標依技能設定位移
+	if skill.Knockback != data.KnockbackNone {
+		for _, t := range hits {
+			knockbackNpc(s.deps, skill, player.X, player.Y, t.npc)
+		}
+	}
+
 	// 吸血系技能：傷害轉為治療（Java: CHILL_TOUCH / VAMPIRIC_TOUCH — heal = this._dmg）
 	if skill.SkillID == 28 || skill.SkillID == 10 {
 		totalDmg := int16(0)
@@ -656,7 +771,7 @@ func (s *SkillSystem) executeAttackSkill(sess *net.Session, player *world.Player
 	// 22=寒冰氣息, 30=岩牢, 80=冰雪颶風
 	if skill.SkillID == 22 || skill.SkillID == 30 || skill.SkillID == 80 {
 		for _, t := range hits {
-			if t.npc.Dead || t.npc.Paralyzed || t.npc.HasDebuff(22) || t.npc.HasDebuff(30) || t.npc.HasDebuff(50) || t.npc.HasDebuff(80) {
+			if t.npc.Dead || t.npc.ImmuneParalyze || t.npc.Paralyzed || t.npc.HasDebuff(22) || t.npc.HasDebuff(30) || t.npc.HasDebuff(50) || t.npc.HasDebuff(80) {
 				continue
 			}
 			if s.checkNpcMRResist(player, t.npc, skill.SkillID) {
@@ -679,6 +794,40 @@ func (s *SkillSystem) executeAttackSkill(sess *net.Session, player *world.Player
 	}
 }
 
+// collectPiercingTargets 沿施法者→目標方向逐格前進（越過目標延伸至 maxRange），
+// 收集路徑上除主目標外的存活 NPC。以地圖通行判定作為視線，遇到障礙即停止。
+// 回傳路徑 NPC 與彈道終點座標。
+func (s *SkillSystem) collectPiercingTargets(player *world.PlayerInfo, target *world.NpcInfo, maxRange int32) ([]*world.NpcInfo, int32, int32) {
+	ws := s.deps.World
+	maps := s.deps.MapData
+	x, y := player.X, player.Y
+	heading := handler.CalcHeading(x, y, target.X, target.Y)
+	reached := false
+	var result []*world.NpcInfo
+	for step := int32(0); step < maxRange; step++ {
+		// 抵達目標前每步重新朝目標修正方向，之後沿最後方向直線延伸
+		if x == target.X && y == target.Y {
+			reached = true
+		}
+		if !reached {
+			heading = handler.CalcHeading(x, y, target.X, target.Y)
+		}
+		if maps != nil && !maps.IsPassableIgnoreOccupant(player.MapID, x, y, int(heading)) {
+			break
+		}
+		x += npcHeadingDX[heading]
+		y += npcHeadingDY[heading]
+		id := ws.OccupantAt(x, y, player.MapID)
+		if id == 0 || id == target.ID {
+			continue
+		}
+		if other := ws.GetNpc(id); other != nil && !other.Dead {
+			result = append(result, other)
+		}
+	}
+	return result, x, y
+}
+
 // executeTurnUndead 起死回生術（skill 18）— 對不死族 NPC 機率即死。
 // Java 參考: L1SkillUse.java TYPE_CURSE 分支，undeadType == 1 || 3 時 _dmg = currentHp。
 // GFX：不走攻擊動畫，走 ActionGfx + SkillEffect（Java 明確排除 Turn Undead 的 S_UseAttackSkill）。
@@ -736,8 +885,7 @@ func (s *SkillSystem) executeTurnUndead(sess *net.Session, player *world.PlayerI
 	// 即死傷害累加仇恨
 	AddHate(npc, sess.ID, dmg)
 
-	hpData := handler.BuildHpMeter(npc.ID, 0)
-	handler.BroadcastToPlayers(nearby, hpData)
+	broadcastNpcHpMeter(npc, nearby, s.deps)
 
 	_ = dmg // 即死傷害值，用於 handleNpcDeath 的經驗計算
 	handleNpcDeath(npc, player, nearby, s.deps)
@@ -820,12 +968,9 @@ func (s *SkillSystem) executeBuffSkill(sess *net.Session, player *world.PlayerIn
 			BroadcastPlayerPoison(target, 1, s.deps) // 綠色
 		}
 
-	case 23: // 能量感測 — 顯示目標的等級、HP/MP、屬性抗性等資訊
+	case 23: // 能量感測 — 目標 HP 條 + 弱點屬性特效（GM 另顯示完整數值）
 		if target.CharID != player.CharID {
-			msg := fmt.Sprintf("\\f2【%s】 Lv.%d  HP:%d/%d  MP:%d/%d  AC:%d  MR:%d  火:%d 水:%d 風:%d 地:%d",
-				target.Name, target.Level, target.HP, target.MaxHP, target.MP, target.MaxMP,
-				target.AC, target.MR, target.FireRes, target.WaterRes, target.WindRes, target.EarthRes)
-			handler.SendGlobalChat(sess, 9, msg)
+			energySense(sess, player, playerSenseInfo(target), nearby)
 		}
 
 	case 20, 40: // 闇盲咒術 / 黑闇之影
@@ -917,8 +1062,11 @@ func (s *SkillSystem) executeBuffSkill(sess *net.Session, player *world.PlayerIn
 		}
 	}
 
-	// 套用 buff 效果
-	s.applyBuffEffect(target, skill)
+	// 套用 buff 效果（buff 上限拒絕：不顯示效果並退還 MP）
+	if !s.applyBuffEffect(target, skill) {
+		s.refundSkillMP(sess, player, skill)
+		return
+	}
 
 	// 效果 GFX
 	if skill.CastGfx > 0 {
@@ -946,7 +1094,7 @@ func (s *SkillSystem) executeNpcDebuffSkill(sess *net.Session, player *world.Pla
 		return
 	}
 
-	player.Heading = CalcHeading(player.X, player.Y, npc.X, npc.Y)
+	player.Heading = handler.CalcHeading(player.X, player.Y, npc.X, npc.Y)
 
 	// 對 NPC 施放 debuff 技能 → 累加仇恨（讓 NPC 追擊施法者）
 	AddHate(npc, sess.ID, 1)
@@ -955,6 +1103,15 @@ func (s *SkillSystem) executeNpcDebuffSkill(sess *net.Session, player *world.Pla
 
 	handler.BroadcastToPlayers(nearby, handler.BuildActionGfx(player.CharID, byte(skill.ActionID)))
 
+	// 模板免疫旗標：對應類型的控制技能直接無效
+	if npcImmuneToSkill(npc, skill.SkillID) {
+		if skill.SkillID != 80 { // 冰雪颶風的傷害已結算，只是不凍結
+			handler.SendServerMessage(sess, skillMsgCastFail)
+		}
+		s.deps.Log.Debug("NPC 免疫控制技能", zap.String("npc", npc.Name), zap.Int32("skill", skill.SkillID))
+		return
+	}
+
 	switch skill.SkillID {
 	case 87: // 衝擊之暈 — 需要雙手劍
 		wpn := player.Equip.Weapon()
@@ -1139,6 +1296,9 @@ func (s *SkillSystem) executeNpcDebuffSkill(sess *net.Session, player *world.Pla
 		}
 		s.deps.Log.Info(fmt.Sprintf("疾病術  施法者=%s  NPC=%s  持續=%d秒", player.Name, npc.Name, dur))
 
+	case 23: // 能量感測（NPC）— 目標 HP 條 + 弱點屬性特效（Java: WEAK_ELEMENTAL）
+		energySense(sess, player, npcSenseInfo(npc), nearby)
+
 	case 44: // 魔法相消術 — 解除 NPC 所有 debuff + 狀態（Java: CANCELLATION.java:158-167）
 		// 清除所有 debuffs
 		for debuffID := range npc.ActiveDebuffs {
@@ -1165,6 +1325,21 @@ func (s *SkillSystem) executeNpcDebuffSkill(sess *net.Session, player *world.Pla
 	}
 }
 
+// npcImmuneToSkill 依 NPC 模板的免疫旗標判定控制技能是否無效。
+// 麻痺類：87 衝擊之暈、157 大地屏障、33 木乃伊詛咒、22/30/50/80 凍結系；
+// 睡眠類：66 沉睡之霧、103 暗黑盲咒；中毒類：11 毒咒。
+func npcImmuneToSkill(npc *world.NpcInfo, skillID int32) bool {
+	switch skillID {
+	case 87, 157, 33, 22, 30, 50, 80:
+		return npc.ImmuneParalyze
+	case 66, 103:
+		return npc.ImmuneSleep
+	case 11:
+		return npc.ImmunePoison
+	}
+	return false
+}
+
 // checkNpcMRResist 檢查 NPC 魔法抗性。
 func (s *SkillSystem) checkNpcMRResist(caster *world.PlayerInfo, npc *world.NpcInfo, _ int32) bool {
 	prob := 50 + (int(caster.Level)-int(npc.Level))*5 + int(caster.Intel)*2 - int(npc.MR)
@@ -1225,9 +1400,9 @@ func (s *SkillSystem) executeSelfSkill(sess *net.Session, player *world.PlayerIn
 
 	case 13, 72: // 無所遁形術 / 強力無所遁形術 — 揭示附近隱身玩家
 		// Java 參考: L1SkillUse.detection() — 移除 buff 60（隱身術）和 97（暗影閃避）
-		// 注意: GM 隱身免疫（isGmInvis），待 GM 系統實作後加入
+		// GM 隱身免疫（Java: isGmInvis）
 		for _, tgt := range nearby {
-			if tgt.CharID == player.CharID {
+			if tgt.CharID == player.CharID || tgt.GMInvisible {
 				continue
 			}
 			if tgt.HasBuff(60) || tgt.HasBuff(97) {
@@ -1390,9 +1565,13 @@ func (s *SkillSystem) executeSelfSkill(sess *net.Session, player *world.PlayerIn
 				TargetAC:           int(npc.AC),
 				TargetLevel:        int(npc.Level),
 				TargetMR:           int(npc.MR),
+				TargetFireRes:      int(npc.FireRes),
+				TargetWaterRes:     int(npc.WaterRes),
+				TargetWindRes:      int(npc.WindRes),
+				TargetEarthRes:     int(npc.EarthRes),
 			}
 			res := s.deps.Scripting.CalcSkillDamage(ctx)
-			dmg := int32(res.Damage)
+			dmg := clampDamage(s.deps, weatherSkillDamage(s.deps, player.MapID, skill.Attr, int32(res.Damage)), skill.MaxDamage, "skill")
 			handler.BroadcastToPlayers(nearby, handler.BuildSkillEffect(npc.ID, skill.CastGfx))
 			// 浮動傷害數字（自我範圍攻擊技能，魔防抵抗時顯示 MISS）
 			if player.AttackView {
@@ -1404,18 +1583,17 @@ func (s *SkillSystem) executeSelfSkill(sess *net.Session, player *world.PlayerIn
 			}
 			// 攻擊技能傷害累加仇恨
 			AddHate(npc, sess.ID, dmg)
-			hpRatio := int16(0)
-			if npc.MaxHP > 0 {
-				hpRatio = int16((npc.HP * 100) / npc.MaxHP)
-			}
-			handler.BroadcastToPlayers(nearby, handler.BuildHpMeter(npc.ID, hpRatio))
+			broadcastNpcHpMeter(npc, nearby, s.deps)
 			if npc.HP <= 0 {
 				handleNpcDeath(npc, player, nearby, s.deps)
 				continue
 			}
 
+			// 擊退/拉近
+			knockbackNpc(s.deps, skill, player.X, player.Y, npc)
+
 			// 冰雪颶風：傷害後凍結判定（Java: calcProbabilityMagic → setFrozen + S_Poison 灰色）
-			if skill.SkillID == 80 && !npc.Paralyzed && !npc.HasDebuff(50) && !npc.HasDebuff(80) {
+			if skill.SkillID == 80 && !npc.ImmuneParalyze && !npc.Paralyzed && !npc.HasDebuff(50) && !npc.HasDebuff(80) {
 				if s.checkNpcMRResist(player, npc, skill.SkillID) {
 					dur := skill.BuffDuration
 					if dur <= 0 {
@@ -1429,8 +1607,11 @@ func (s *SkillSystem) executeSelfSkill(sess *net.Session, player *world.PlayerIn
 		}
 	}
 
-	// 套用 buff 效果
-	s.applyBuffEffect(player, skill)
+	// 套用 buff 效果（buff 上限拒絕：不顯示效果並退還 MP）
+	if !s.applyBuffEffect(player, skill) {
+		s.refundSkillMP(sess, player, skill)
+		return
+	}
 
 	// 負重強化：套用時立即更新負重顯示
 	if skill.SkillID == 14 || skill.SkillID == 218 {
@@ -1469,6 +1650,12 @@ func (s *SkillSystem) executeArmorEnchant(sess *net.Session, player *world.Playe
 		return
 	}
 
+	// 套用 AC-3 buff（簡化：Java 是物品級 enchant，Go 用玩家 buff 代替）；buff 上限拒絕時退還 MP
+	if !s.applyBuffEffect(player, skill) {
+		s.refundSkillMP(sess, player, skill)
+		return
+	}
+
 	// 施法動畫 + GFX
 	nearby := s.deps.World.GetNearbyPlayersAt(player.X, player.Y, player.MapID)
 	handler.BroadcastToPlayers(nearby, handler.BuildActionGfx(player.CharID, byte(skill.ActionID)))
@@ -1476,9 +1663,6 @@ func (s *SkillSystem) executeArmorEnchant(sess *net.Session, player *world.Playe
 		handler.BroadcastToPlayers(nearby, handler.BuildSkillEffect(player.CharID, skill.CastGfx))
 	}
 
-	// 套用 AC-3 buff（簡化：Java 是物品級 enchant，Go 用玩家 buff 代替）
-	s.applyBuffEffect(player, skill)
-
 	// 成功訊息（Java: S_ServerMessage 161 "{item} 的 {效果} 增加了。"）
 	handler.SendServerMessage(sess, 161)
 }
@@ -1500,6 +1684,12 @@ func (s *SkillSystem) executeWeaponEnchant(sess *net.Session, player *world.Play
 		return
 	}
 
+	// 套用 buff 效果；buff 上限拒絕時退還 MP
+	if !s.applyBuffEffect(player, skill) {
+		s.refundSkillMP(sess, player, skill)
+		return
+	}
+
 	// 施法動畫 + GFX
 	nearby := s.deps.World.GetNearbyPlayersAt(player.X, player.Y, player.MapID)
 	handler.BroadcastToPlayers(nearby, handler.BuildActionGfx(player.CharID, byte(skill.ActionID)))
@@ -1507,9 +1697,6 @@ func (s *SkillSystem) executeWeaponEnchant(sess *net.Session, player *world.Play
 		handler.BroadcastToPlayers(nearby, handler.BuildSkillEffect(player.CharID, skill.CastGfx))
 	}
 
-	// 套用 buff 效果
-	s.applyBuffEffect(player, skill)
-
 	handler.SendServerMessage(sess, 161)
 }
 
@@ -1792,7 +1979,7 @@ func (s *SkillSystem) sendBuffIcon(target *world.PlayerInfo, skillID int32, dura
 	case "dexup":
 		handler.SendIconDexup(sess, durationSec, byte(target.Dex), icon.Param)
 	case "aura":
-		handler.SendIconAura(sess, byte(skillID-1), durationSec)
+		handler.SendIconAura(sess, icon.Param, durationSec)
 	case "invis":
 		handler.SendInvisible(sess, target.CharID, durationSec > 0)
 	case "wisdom":
@@ -1807,10 +1994,11 @@ func (s *SkillSystem) cancelBuffIcon(target *world.PlayerInfo, skillID int32) {
 	s.sendBuffIcon(target, skillID, 0)
 }
 
-// applyBuffEffect 套用屬性變化並註冊 buff 計時器。
-func (s *SkillSystem) applyBuffEffect(target *world.PlayerInfo, skill *data.SkillInfo) {
+// applyBuffEffect 套用屬性變化並註冊 buff 計時器。回傳 false 表示 buff 上限拒絕（未套用任何效果），
+// 呼叫端不可再發送圖示、特效或訊息。無持續時間的技能視為成功（不建立 buff）。
+func (s *SkillSystem) applyBuffEffect(target *world.PlayerInfo, skill *data.SkillInfo) bool {
 	if skill.BuffDuration <= 0 {
-		return
+		return true
 	}
 
 	buff := &world.ActiveBuff{
@@ -1820,6 +2008,11 @@ func (s *SkillSystem) applyBuffEffect(target *world.PlayerInfo, skill *data.Skil
 
 	eff := s.deps.Scripting.GetBuffEffect(int(skill.SkillID), int(target.Level))
 
+	// buff 數量上限（[gameplay] max_active_buffs）
+	if !s.ensureBuffSlot(target, skill, eff) {
+		return false
+	}
+
 	if eff != nil {
 		// 移除衝突 buff
 		for _, exID := range eff.Exclusions {
@@ -1938,6 +2131,7 @@ func (s *SkillSystem) applyBuffEffect(target *world.PlayerInfo, skill *data.Skil
 	}
 
 	s.sendBuffIcon(target, skill.SkillID, uint16(skill.BuffDuration))
+	return true
 }
 
 // ApplyNpcDebuff NPC 對玩家施放 debuff 技能（麻痺/睡眠/減速等）。
@@ -1963,6 +2157,11 @@ func (s *SkillSystem) CancelAbsoluteBarrier(player *world.PlayerInfo) {
 // cancelInvisibility 解除隱身效果（Java: L1BuffUtil.cancelInvisibility）。
 // 攻擊/施法時呼叫。移除隱身 buff 並通知周圍玩家重新顯示此角色。
 func (s *SkillSystem) cancelInvisibility(player *world.PlayerInfo) {
+	// GM 隱身不因攻擊/施法解除（僅 .vis 可關閉）
+	if player.GMInvisible {
+		return
+	}
+
 	// 移除隱身術 (60) 和暗隱術 (97) 的 buff
 	s.removeBuffAndRevert(player, 60)
 	s.removeBuffAndRevert(player, 97)
@@ -1994,7 +2193,9 @@ func (s *SkillSystem) ApplyGMBuff(player *world.PlayerInfo, skillID int32) bool
 	if skill == nil {
 		return false
 	}
-	s.applyBuffEffect(player, skill)
+	if !s.applyBuffEffect(player, skill) {
+		return false
+	}
 	dur := uint16(skill.BuffDuration)
 	if dur == 0 {
 		dur = 300 // 預設 5 分鐘
@@ -2111,7 +2312,7 @@ func (s *SkillSystem) revertBuffStats(target *world.PlayerInfo, buff *world.Acti
 		target.MP = target.MaxMP
 	}
 	if buff.SetInvisible {
-		target.Invisible = false
+		target.Invisible = target.GMInvisible // GM 隱身不隨 buff 結束
 	}
 	if buff.SetParalyzed {
 		target.Paralyzed = false
@@ -2234,6 +2435,74 @@ func (s *SkillSystem) cancelAllBuffs(target *world.PlayerInfo) {
 //  Buff 計時器
 // ========================================================================
 
+// expireBuff 移除 buff 並完整還原其效果（屬性、圖示、速度、麻痺/睡眠等客戶端狀態）。
+// 到期與 buff 上限擠出共用。
+func (s *SkillSystem) expireBuff(p *world.PlayerInfo, skillID int32, buff *world.ActiveBuff) {
+	s.revertBuffStats(p, buff)
+	delete(p.ActiveBuffs, skillID)
+
+	s.cancelBuffIcon(p, skillID)
+
+	if skillID == handler.SkillShapeChange && s.deps.Polymorph != nil {
+		s.deps.Polymorph.UndoPoly(p)
+	}
+
+	if buff.SetMoveSpeed > 0 {
+		p.MoveSpeed = 0
+		p.HasteTicks = 0
+		s.sendSpeedToAll(p, 0, 0)
+	}
+	if buff.SetBraveSpeed > 0 {
+		p.BraveSpeed = 0
+		p.BraveTicks = 0
+		s.sendBraveToAll(p, 0, 0)
+	}
+
+	// 麻痺/睡眠/致盲到期
+	if buff.SetParalyzed {
+		switch skillID {
+		case 87:
+			handler.SendParalysis(p.Session, handler.StunRemove)
+		case 157, 50, 80, 22, 30:
+			handler.SendParalysis(p.Session, handler.FreezeRemove)
+			// 清除灰色色調
+			broadcastPlayerPoison(p, 0, s.deps)
+		default:
+			handler.SendParalysis(p.Session, handler.ParalysisRemove)
+		}
+	}
+	if buff.SetSleeped {
+		handler.SendParalysis(p.Session, handler.SleepRemove)
+	}
+	if skillID == 20 || skillID == 40 {
+		handler.SendCurseBlind(p.Session, 0)
+	}
+
+	// 慎重藥水到期
+	if skillID == handler.SkillStatusWisdomPotion {
+		p.WisdomSP = 0
+		p.WisdomTicks = 0
+	}
+
+	// 怪物命中特效削弱到期
+	if skillID == handler.SkillStatusStatDrain {
+		handler.SendSystemMessage(p.Session, "你的能力值已恢復。")
+	}
+
+	// 負重強化到期：更新負重顯示
+	if skillID == 14 || skillID == 218 {
+		handler.SendWeightUpdate(p.Session, p)
+	}
+
+	if s.deps.Skills != nil {
+		if sk := s.deps.Skills.Get(skillID); sk != nil && sk.SysMsgStop > 0 {
+			handler.SendServerMessage(p.Session, uint16(sk.SysMsgStop))
+		}
+	}
+
+	handler.SendPlayerStatus(p.Session, p)
+}
+
 // tickPlayerBuffs 每 tick 遞減 buff 計時器並處理到期。
 func (s *SkillSystem) tickPlayerBuffs(p *world.PlayerInfo) {
 	if p.ActiveBuffs == nil {
@@ -2245,64 +2514,7 @@ func (s *SkillSystem) tickPlayerBuffs(p *world.PlayerInfo) {
 		}
 		buff.TicksLeft--
 		if buff.TicksLeft <= 0 {
-			s.revertBuffStats(p, buff)
-			delete(p.ActiveBuffs, skillID)
-
-			s.cancelBuffIcon(p, skillID)
-
-			if skillID == handler.SkillShapeChange && s.deps.Polymorph != nil {
-				s.deps.Polymorph.UndoPoly(p)
-			}
-
-			if buff.SetMoveSpeed > 0 {
-				p.MoveSpeed = 0
-				p.HasteTicks = 0
-				s.sendSpeedToAll(p, 0, 0)
-			}
-			if buff.SetBraveSpeed > 0 {
-				p.BraveSpeed = 0
-				p.BraveTicks = 0
-				s.sendBraveToAll(p, 0, 0)
-			}
-
-			// 麻痺/睡眠/致盲到期
-			if buff.SetParalyzed {
-				switch skillID {
-				case 87:
-					handler.SendParalysis(p.Session, handler.StunRemove)
-				case 157, 50, 80, 22, 30:
-					handler.SendParalysis(p.Session, handler.FreezeRemove)
-					// 清除灰色色調
-					broadcastPlayerPoison(p, 0, s.deps)
-				default:
-					handler.SendParalysis(p.Session, handler.ParalysisRemove)
-				}
-			}
-			if buff.SetSleeped {
-				handler.SendParalysis(p.Session, handler.SleepRemove)
-			}
-			if skillID == 20 || skillID == 40 {
-				handler.SendCurseBlind(p.Session, 0)
-			}
-
-			// 慎重藥水到期
-			if skillID == handler.SkillStatusWisdomPotion {
-				p.WisdomSP = 0
-				p.WisdomTicks = 0
-			}
-
-			// 負重強化到期：更新負重顯示
-			if skillID == 14 || skillID == 218 {
-				handler.SendWeightUpdate(p.Session, p)
-			}
-
-			if s.deps.Skills != nil {
-				if sk := s.deps.Skills.Get(skillID); sk != nil && sk.SysMsgStop > 0 {
-					handler.SendServerMessage(p.Session, uint16(sk.SysMsgStop))
-				}
-			}
-
-			handler.SendPlayerStatus(p.Session, p)
+			s.expireBuff(p, skillID, buff)
 		} else if buff.SetParalyzed && buff.TicksLeft%25 == 0 {
 			// 3.80C 客戶端灰色色調會自動淡出，每 5 秒重發維持視覺
 			switch skillID {
@@ -0,0 +1,63 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/world"
+)
+
+func TestCollectPiercingTargetsStraightLine(t *testing.T) {
+	deps := newTestDeps(t)
+	ws := world.NewState()
+	deps.World = ws
+	s := NewSkillSystem(deps)
+
+	player := &world.PlayerInfo{SessionID: 1, CharID: 1, Name: "caster", X: 100, Y: 100, MapID: 4}
+	ws.AddPlayer(player)
+	npcAt := func(x, y int32) *world.NpcInfo {
+		n := &world.NpcInfo{ID: world.NextNpcID(), X: x, Y: y, MapID: 4, HP: 10, MaxHP: 10}
+		ws.AddNpc(n)
+		return n
+	}
+	front1 := npcAt(101, 100)
+	front2 := npcAt(102, 100)
+	target := npcAt(103, 100)
+	behind := npcAt(105, 100)
+	outOfRange := npcAt(110, 100)
+	offLine := npcAt(102, 101)
+	dead := npcAt(104, 100)
+	dead.Dead = true
+
+	line, endX, endY := s.collectPiercingTargets(player, target, 6)
+
+	want := []*world.NpcInfo{front1, front2, behind}
+	if len(line) != len(want) {
+		t.Fatalf("hit %d NPCs, want %d", len(line), len(want))
+	}
+	for i, n := range want {
+		if line[i] != n {
+			t.Fatalf("hit[%d] at (%d,%d), want (%d,%d)", i, line[i].X, line[i].Y, n.X, n.Y)
+		}
+	}
+	for _, n := range line {
+		if n == target || n == outOfRange || n == offLine || n == dead {
+			t.Fatalf("unexpected hit at (%d,%d)", n.X, n.Y)
+		}
+	}
+	if endX != 106 || endY != 100 {
+		t.Fatalf("projectile ends at (%d,%d), want (106,100)", endX, endY)
+	}
+}
+
+// 出貨資料中至少一個單體攻擊技能設定貫穿，否則貫穿路徑永遠不會執行。
+func TestShippedSkillListHasPiercingSkill(t *testing.T) {
+	skills, err := data.LoadSkillTable("../../data/yaml/skill_list.yaml")
+	if err != nil {
+		t.Fatalf("load skills: %v", err)
+	}
+	sk := skills.Get(17) // 極光雷電
+	if sk == nil || !sk.Through || sk.Area != 0 {
+		t.Fatalf("skill 17 = %+v, want a single-target piercing skill", sk)
+	}
+}
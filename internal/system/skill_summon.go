@@ -191,8 +191,11 @@ func (s *SummonSystem) ExecuteSummonMonster(sess *net.Session, player *world.Pla
 		return
 	}
 
-	// 計算可召喚數量
+	// 計算可召喚數量（CHA 消耗 + 數量上限）
 	count := availCHA / petCost
+	if room := world.MaxPets(baseCHA) - player.ActivePets; count > room {
+		count = room
+	}
 	if count <= 0 {
 		handler.SendServerMessage(sess, msgTooManyPets)
 		return
@@ -265,7 +268,8 @@ func (s *SummonSystem) ExecuteSummonMonster(sess *net.Session, player *world.Pla
 // ========================================================================
 
 // ExecuteTamingMonster 處理技能 36（馴服怪物）。
-// 馴服活著的 NPC 並轉為施法者擁有的召喚獸。
+// 對可馴服（Tameable）的怪物施放，依施法者 CHA 與目標等級擲骰；
+// 成功後轉為施法者的寵物（項圈 + DB 持久化），交由寵物 AI 控制。
 func (s *SummonSystem) ExecuteTamingMonster(sess *net.Session, player *world.PlayerInfo, skill *data.SkillInfo, targetID int32) {
 	ws := s.deps.World
 
@@ -276,16 +280,13 @@ func (s *SummonSystem) ExecuteTamingMonster(sess *net.Session, player *world.Pla
 		return
 	}
 
-	// 檢查可馴服標記
-	tmpl := s.deps.Npcs.Get(npc.NpcID)
-	if tmpl == nil || !tmpl.Tameable {
+	// 檢查可馴服標記（只對 L1Monster 有效）
+	if !npc.Tameable || npc.Impl != "L1Monster" {
 		handler.SendServerMessage(sess, msgInvalidTarget)
 		return
 	}
 
-	// 只對 L1Monster 有效
-	if npc.Impl != "L1Monster" {
-		handler.SendServerMessage(sess, msgInvalidTarget)
+	if s.deps.PetLife == nil {
 		return
 	}
 
@@ -299,66 +300,47 @@ func (s *SummonSystem) ExecuteTamingMonster(sess *net.Session, player *world.Pla
 	}
 	usedCHA := s.calcUsedPetCost(player.CharID)
 	availCHA := charisma - usedCHA
-	if availCHA < 6 {
+	if availCHA < 6 || player.ActivePets >= world.MaxPets(charisma) {
 		handler.SendServerMessage(sess, msgTooManyPets)
 		return
 	}
 
+	// 背包須能放入項圈（AdoptNpc 失敗時不應已扣除 MP／材料）
+	if player.Inv.IsFull() {
+		handler.SendServerMessage(sess, 263) // 背包已滿
+		return
+	}
+	if s.deps.Npcs.Get(npc.NpcID) == nil || s.deps.Items.Get(petCollarNormal) == nil {
+		handler.SendServerMessage(sess, msgInvalidTarget)
+		return
+	}
+
 	// 所有驗證通過 — 消耗資源
 	handler.ConsumeSkillResources(sess, player, skill)
 
-	// 從 NPC 建立召喚獸
-	sum := &world.SummonInfo{
-		ID:          world.NextNpcID(),
-		OwnerCharID: player.CharID,
-		NpcID:       npc.NpcID,
-		GfxID:       npc.GfxID,
-		NameID:      npc.NameID,
-		Name:        npc.Name,
-		Level:       npc.Level,
-		HP:          npc.HP,
-		MaxHP:       npc.MaxHP,
-		MP:          npc.MP,
-		MaxMP:       npc.MaxMP,
-		AC:          npc.AC,
-		STR:         npc.STR,
-		DEX:         npc.DEX,
-		MR:          npc.MR,
-		AtkDmg:      npc.AtkDmg,
-		AtkSpeed:    npc.AtkSpeed,
-		MoveSpd:     npc.MoveSpeed,
-		Ranged:      npc.Ranged,
-		Lawful:      npc.Lawful,
-		Size:        npc.Size,
-		PetCost:     6,
-		X:           npc.X,
-		Y:           npc.Y,
-		MapID:       npc.MapID,
-		Heading:     npc.Heading,
-		Status:      world.SummonRest, // 馴服的召喚獸預設休憩
-		Tamed:       true,
-		TimerTicks:  0, // 永久（馴服無計時器）
+	// 成功率：CHA 高於目標等級越多越容易（10% ~ 90%）
+	chance := tamingChance(charisma, int(npc.Level))
+	if world.RandInt(100) >= chance {
+		handler.SendServerMessage(sess, msgSummonCastFail)
+		return
 	}
 
-	// 移除原始 NPC + 解鎖格子
-	npc.Dead = true
-	ws.NpcDied(npc)
-	nearby := ws.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID)
-	for _, viewer := range nearby {
-		handler.SendRemoveObject(viewer.Session, npc.ID)
+	if !s.deps.PetLife.AdoptNpc(sess, player, npc) {
+		s.deps.Log.Warn("馴服怪物：轉為寵物失敗", zap.String("player", player.Name), zap.Int32("npc_id", npc.NpcID))
 	}
+}
 
-	// 加入召喚獸
-	ws.AddSummon(sum)
-
-	masterName := player.Name
-	nearby = ws.GetNearbyPlayersAt(sum.X, sum.Y, sum.MapID)
-	for _, viewer := range nearby {
-		isOwner := viewer.CharID == player.CharID
-		handler.SendSummonPack(viewer.Session, sum, isOwner, masterName)
+// tamingChance 計算技能 36 的馴服成功率（百分比）。
+// 基礎 50%，CHA 每高於目標等級 1 點 +2%，反之 -2%。
+func tamingChance(charisma, npcLevel int) int {
+	chance := 50 + (charisma-npcLevel)*2
+	if chance < 10 {
+		chance = 10
 	}
-	handler.SendSummonPack(sess, sum, true, masterName)
-	handler.SendSummonMenu(sess, sum)
+	if chance > 90 {
+		chance = 90
+	}
+	return chance
 }
 
 // ========================================================================
@@ -400,7 +382,7 @@ func (s *SummonSystem) ExecuteCreateZombie(sess *net.Session, player *world.Play
 	}
 	usedCHA := s.calcUsedPetCost(player.CharID)
 	availCHA := charisma - usedCHA
-	if availCHA < 6 {
+	if availCHA < 6 || player.ActivePets >= world.MaxPets(charisma) {
 		handler.SendServerMessage(sess, msgTooManyPets)
 		return
 	}
@@ -485,22 +467,60 @@ func (s *SummonSystem) ExecuteCreateZombie(sess *net.Session, player *world.Play
 // ========================================================================
 
 // ExecuteReturnToNature 處理技能 145（歸返自然）。
-// 非馴服的召喚獸銷毀；馴服的釋放回 NPC 型態。
+// 解散施法者所有召喚獸：非馴服的銷毀、馴服的釋放回 NPC 型態；
+// 寵物依設定放回野外或收回項圈。可依設定退還部分召喚術 MP。
 func (s *SummonSystem) ExecuteReturnToNature(sess *net.Session, player *world.PlayerInfo, skill *data.SkillInfo) {
 	ws := s.deps.World
 	summons := ws.GetSummonsByOwner(player.CharID)
-	if len(summons) == 0 {
+	var pets []*world.PetInfo
+	for _, pet := range ws.GetPetsByOwner(player.CharID) {
+		if !pet.Dead {
+			pets = append(pets, pet)
+		}
+	}
+	if len(summons) == 0 && len(pets) == 0 {
 		return
 	}
 
 	// 驗證通過（有召喚獸） — 消耗資源
 	handler.ConsumeSkillResources(sess, player, skill)
 
+	refunded := 0
 	for _, sum := range summons {
 		if sum.Tamed {
 			s.liberateSummon(sum)
 		} else {
 			s.killSummon(sum)
+			refunded++
+		}
+	}
+
+	if s.deps.PetLife != nil {
+		release := s.deps.Config.Gameplay.ReturnToNatureReleasePets
+		for _, pet := range pets {
+			if release {
+				s.deps.PetLife.DismissPet(pet, player)
+			} else {
+				s.deps.PetLife.CollectPet(pet, player)
+			}
+		}
+	}
+
+	// 重置數量上限計數（存活的召喚獸/寵物已全部移除）
+	player.ActivePets = 0
+
+	// 退還召喚術（技能 51）MP
+	pct := s.deps.Config.Gameplay.ReturnToNatureMPRefundPct
+	if pct > 0 && refunded > 0 && s.deps.Skills != nil {
+		if summonSkill := s.deps.Skills.Get(51); summonSkill != nil {
+			mp := int16(summonSkill.MpConsume * pct / 100 * refunded)
+			if mp > 0 {
+				player.MP += mp
+				if player.MP > player.MaxMP {
+					player.MP = player.MaxMP
+				}
+				sendMpUpdate(sess, player)
+			}
 		}
 	}
 }
@@ -556,6 +576,7 @@ func (s *SummonSystem) liberateSummon(sum *world.SummonInfo) {
 		DEX:       sum.DEX,
 		MR:        sum.MR,
 		PoisonAtk: tmpl.PoisonAtk,
+		Tameable:  tmpl.Tameable,
 		Exp:       0, // 釋放的 NPC 不給經驗
 		Lawful:    sum.Lawful,
 		Size:      sum.Size,
@@ -0,0 +1,90 @@
+package system
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/net"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// adoptRecorder 記錄 AdoptNpc 呼叫；其他 PetLifecycleManager 方法不會用到。
+type adoptRecorder struct {
+	handler.PetLifecycleManager
+	adopted []*world.NpcInfo
+}
+
+func (r *adoptRecorder) CalcUsedPetCost(int32) int { return 0 }
+
+func (r *adoptRecorder) AdoptNpc(_ *net.Session, _ *world.PlayerInfo, npc *world.NpcInfo) bool {
+	r.adopted = append(r.adopted, npc)
+	return true
+}
+
+func newTamingFixture(t *testing.T, npcID int32) (*SummonSystem, *adoptRecorder, *world.PlayerInfo, *world.NpcInfo) {
+	t.Helper()
+	world.SetRand(world.NewRand(2))
+	t.Cleanup(func() { world.SetRand(world.NewRand(time.Now().UnixNano())) })
+
+	deps := newTestDeps(t)
+	deps.World = world.NewState()
+	npcs, err := data.LoadNpcTable(filepath.Join("..", "..", "data", "yaml", "npc_list.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps.Npcs = npcs
+	deps.Items = newTestItems(t, "  - {item_id: 40314, name: 項圈}\n")
+	pets := &adoptRecorder{}
+	deps.PetLife = pets
+
+	player := &world.PlayerInfo{
+		SessionID: 1, Session: newTestSession(t, 1), CharID: 1, Name: "tamer", ClassType: 2,
+		Level: 40, Cha: 60, MP: 100, MaxMP: 100, X: 32700, Y: 32800, MapID: 4, Inv: world.NewInventory(),
+	}
+	npc := &world.NpcInfo{
+		ID: world.NextNpcID(), NpcID: npcID, Impl: "L1Monster", Tameable: true,
+		Level: 1, HP: 10, MaxHP: 10, X: 32701, Y: 32800, MapID: 4,
+	}
+	deps.World.AddNpc(npc)
+	return NewSummonSystem(deps), pets, player, npc
+}
+
+var tamingSkill = &data.SkillInfo{SkillID: 36, MpConsume: 10}
+
+func TestTamingMonsterKeepsResourcesWhenAdoptionImpossible(t *testing.T) {
+	t.Run("背包已滿", func(t *testing.T) {
+		s, pets, player, npc := newTamingFixture(t, 45008)
+		for i := 0; i < world.MaxInventorySize; i++ {
+			player.Inv.AddItem(int32(100000+i), 1, "雜物", 0, 0, false, 1)
+		}
+		s.ExecuteTamingMonster(player.Session, player, tamingSkill, npc.ID)
+		if player.MP != 100 || len(pets.adopted) != 0 {
+			t.Errorf("full bag: MP %d, adopted %d", player.MP, len(pets.adopted))
+		}
+	})
+	t.Run("無怪物樣板", func(t *testing.T) {
+		s, pets, player, npc := newTamingFixture(t, 9999999)
+		s.ExecuteTamingMonster(player.Session, player, tamingSkill, npc.ID)
+		if player.MP != 100 || len(pets.adopted) != 0 {
+			t.Errorf("missing template: MP %d, adopted %d", player.MP, len(pets.adopted))
+		}
+	})
+}
+
+func TestTamingMonsterAdopts(t *testing.T) {
+	s, pets, player, npc := newTamingFixture(t, 45008)
+	// CHA 遠高於目標等級：成功率 90%
+	for i := 0; i < 10 && len(pets.adopted) == 0; i++ {
+		player.MP = 100
+		s.ExecuteTamingMonster(player.Session, player, tamingSkill, npc.ID)
+		if player.MP != 90 {
+			t.Fatalf("cast %d: MP %d, want 90", i, player.MP)
+		}
+	}
+	if len(pets.adopted) != 1 || pets.adopted[0] != npc {
+		t.Fatalf("adopted %v, want the target", pets.adopted)
+	}
+}
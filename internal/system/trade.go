@@ -26,6 +26,11 @@ func NewTradeSystem(deps *handler.Deps) *TradeSystem {
 // InitiateTrade 向目標發送交易確認對話框。
 // player 和 target 已由 handler 找到並驗證過。
 func (s *TradeSystem) InitiateTrade(sess *net.Session, player, target *world.PlayerInfo) {
+	if target.RefuseTrade {
+		handler.SendRefused(sess, target.Name, "交易")
+		return
+	}
+
 	// 目標已在交易中
 	if target.TradePartnerID != 0 {
 		handler.SendGlobalChat(sess, 9, fmt.Sprintf("%s 正在進行交易中。", target.Name))
@@ -325,6 +330,10 @@ func (s *TradeSystem) executeTrade(p1, p2 *world.PlayerInfo) {
 		s.addGoldToPlayer(p1, p2.TradeGold)
 	}
 
+	// 合併重複堆疊
+	handler.ConsolidateInventory(p1.Session, p1)
+	handler.ConsolidateInventory(p2.Session, p2)
+
 	// 關閉交易視窗（0 = 交易完成）
 	sendTradeStatus(p1.Session, 0)
 	sendTradeStatus(p2.Session, 0)
@@ -365,16 +374,9 @@ func (s *TradeSystem) addTradeItemToPlayer(receiver *world.PlayerInfo, item *wor
 	handler.SendWeightUpdate(receiver.Session, receiver)
 }
 
-// addGoldToPlayer 將金幣加入接收方（來源已扣除）。
+// addGoldToPlayer 將金幣加入接收方（來源已扣除）。超出身上上限的部分存入個人倉庫。
 func (s *TradeSystem) addGoldToPlayer(receiver *world.PlayerInfo, amount int32) {
-	adena := receiver.Inv.FindByItemID(world.AdenaItemID)
-	if adena != nil {
-		adena.Count += amount
-		handler.SendItemCountUpdate(receiver.Session, adena)
-	} else {
-		newItem := receiver.Inv.AddItem(world.AdenaItemID, amount, "金幣", 0, 0, true, 1)
-		handler.SendAddItem(receiver.Session, newItem)
-	}
+	giveAdena(s.deps, receiver, int64(amount))
 	handler.SendWeightUpdate(receiver.Session, receiver)
 }
 
@@ -428,14 +430,7 @@ func (s *TradeSystem) restoreTradeItems(p *world.PlayerInfo) {
 	}
 
 	if p.TradeGold > 0 {
-		adena := p.Inv.FindByItemID(world.AdenaItemID)
-		if adena != nil {
-			adena.Count += p.TradeGold
-			handler.SendItemCountUpdate(p.Session, adena)
-		} else {
-			newItem := p.Inv.AddItem(world.AdenaItemID, p.TradeGold, "金幣", 0, 0, true, 1)
-			handler.SendAddItem(p.Session, newItem)
-		}
+		giveAdena(s.deps, p, int64(p.TradeGold))
 	}
 	handler.SendWeightUpdate(p.Session, p)
 }
@@ -0,0 +1,91 @@
+package system
+
+import (
+	"encoding/binary"
+	stdnet "net"
+	"path/filepath"
+	"testing"
+
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/net"
+	"github.com/l1jgo/server/internal/net/packet"
+	"github.com/l1jgo/server/internal/world"
+	"go.uber.org/zap"
+)
+
+// arrowPacketMinLen S_UseArrowSkill 至座標欄位為止的長度（近戰攻擊封包較短）：
+// [C op][C act][D atk][D tgt][H dmg][C head][D seq][H gfx][C type][H ax][H ay][H tx][H ty]
+const arrowPacketMinLen = 28
+
+func TestTripleArrowSendsThreeSequencedArrows(t *testing.T) {
+	deps := newTestDeps(t)
+	deps.World = world.NewState()
+	deps.Items = newTestItemsWithWeapons(t,
+		"  - {item_id: 1, name: 長弓, type: bow, range: -1, dmg_small: 5, dmg_large: 5}\n",
+		"  - {item_id: 40743, name: 箭, item_type: arrow, stackable: true}\n")
+	skills, err := data.LoadSkillTable(filepath.Join("..", "..", "data", "yaml", "skill_list.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	skill := skills.Get(132)
+	if skill == nil {
+		t.Fatal("skill 132 missing from skill_list.yaml")
+	}
+
+	c1, c2 := stdnet.Pipe()
+	t.Cleanup(func() { c1.Close(); c2.Close() })
+	sess := net.NewSession(c1, 1, 4, 64, 0, 0, zap.NewNop())
+
+	player := &world.PlayerInfo{
+		SessionID: 1, Session: sess, CharID: 7, Name: "archer", Level: 30, Str: 12, Dex: 18,
+		X: 32700, Y: 32800, MapID: 4, HP: 100, MaxHP: 100, Inv: world.NewInventory(),
+	}
+	bow := player.Inv.AddItem(1, 1, "長弓", 0, 0, false, 1)
+	player.Equip.Set(world.SlotWeapon, bow)
+	player.Inv.AddItem(40743, 10, "箭", 0, 0, true, 1)
+	deps.World.AddPlayer(player)
+
+	npc := &world.NpcInfo{
+		ID: world.NextNpcID(), Impl: "L1Monster", Level: 1, HP: 30000, MaxHP: 30000,
+		X: 32704, Y: 32800, MapID: 4,
+	}
+	deps.World.AddNpc(npc)
+
+	NewSkillSystem(deps).executeAttackSkill(sess, player, skill, npc.ID)
+	sess.FlushOutput()
+
+	var seqs []uint32
+	for len(sess.OutQueue) > 0 {
+		p := <-sess.OutQueue
+		if len(p) < arrowPacketMinLen || p[0] != packet.S_OPCODE_ATTACK {
+			continue
+		}
+		if p[1] != 1 {
+			t.Errorf("arrow action = %d, want 1", p[1])
+		}
+		if atk, tgt := int32(binary.LittleEndian.Uint32(p[2:])), int32(binary.LittleEndian.Uint32(p[6:])); atk != player.CharID || tgt != npc.ID {
+			t.Errorf("arrow from %d to %d, want %d to %d", atk, tgt, player.CharID, npc.ID)
+		}
+		if p[12] != byte(player.Heading) || player.Heading != 2 {
+			t.Errorf("heading byte %d, player heading %d, want 2", p[12], player.Heading)
+		}
+		if gfx, typ := binary.LittleEndian.Uint16(p[17:]), p[19]; gfx != 66 || typ != 0 {
+			t.Errorf("arrow gfx %d type %d, want 66 / 0", gfx, typ)
+		}
+		if tx := binary.LittleEndian.Uint16(p[24:]); int32(tx) != npc.X {
+			t.Errorf("arrow target x = %d, want %d", tx, npc.X)
+		}
+		seqs = append(seqs, binary.LittleEndian.Uint32(p[13:]))
+	}
+	if len(seqs) != 3 {
+		t.Fatalf("sent %d arrow packets, want 3", len(seqs))
+	}
+	for i := 1; i < len(seqs); i++ {
+		if seqs[i] <= seqs[i-1] {
+			t.Errorf("arrow sequence not increasing: %v", seqs)
+		}
+	}
+	if a := player.Inv.FindByItemID(40743); a == nil || a.Count != 9 {
+		t.Error("triple arrow should consume exactly one arrow")
+	}
+}
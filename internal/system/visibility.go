@@ -58,9 +58,9 @@ func (s *VisibilitySystem) updatePlayerVisibility(p *world.PlayerInfo) {
 	for _, other := range nearby {
 		currentSet[other.CharID] = struct{}{}
 
-		// 隱身玩家不可見（Java: isInvisble() 檢查）
+		// 隱身玩家不可見（Java: isInvisble() 檢查）；GM 看得到 GM 隱身的其他 GM
 		// 自己永遠看得到自己（但 GetNearbyPlayers 已排除自己）
-		if other.Invisible {
+		if other.Invisible && !(other.GMInvisible && p.IsGM()) {
 			// 如果之前看得到、現在隱身了 → 從畫面移除
 			if _, known := p.Known.Players[other.CharID]; known {
 				handler.SendRemoveObject(p.Session, other.CharID)
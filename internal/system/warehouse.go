@@ -2,6 +2,7 @@ package system
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/l1jgo/server/internal/handler"
@@ -177,7 +178,12 @@ func (s *WarehouseSystem) loadWarehouseCache(sess *net.Session, player *world.Pl
 		items, err = s.deps.WarehouseRepo.LoadByCharName(ctx, player.Name, whType)
 	case handler.WhTypeClan:
 		items, err = s.deps.WarehouseRepo.Load(ctx, player.ClanName, whType)
-	default: // Personal, Elf
+	case handler.WhTypePersonal:
+		if player.AccountID == 0 {
+			return fmt.Errorf("帳號 ID 未載入: %s", sess.AccountName)
+		}
+		items, err = s.deps.WarehouseRepo.LoadAccount(ctx, player.AccountID)
+	default: // Elf
 		items, err = s.deps.WarehouseRepo.Load(ctx, sess.AccountName, whType)
 	}
 	if err != nil {
@@ -260,8 +266,15 @@ func (s *WarehouseSystem) handleWarehouseDeposit(sess *net.Session, r *packet.Re
 			qty = invItem.Count
 		}
 
-		// 血盟倉庫：封印物品（bless >= 128）不可存入
-		if whType == handler.WhTypeClan && invItem.Bless >= 128 {
+		// 封印物品（bless >= 128）只能存入角色專屬倉庫，不可放入帳號共用/血盟倉庫
+		if whType != handler.WhTypeCharacter && invItem.Bless >= 128 {
+			handler.SendServerMessageArgs(sess, msgCannotTransfer, invItem.Name)
+			continue
+		}
+
+		// 不可交易物品不可存入任何倉庫（Java: isTradable → S_ServerMessage 210）
+		if info := s.deps.Items.Get(invItem.ItemID); info != nil && !info.Tradeable {
+			handler.SendServerMessageArgs(sess, msgCannotTransfer, invItem.Name)
 			continue
 		}
 
@@ -278,9 +291,14 @@ func (s *WarehouseSystem) handleWarehouseDeposit(sess *net.Session, r *packet.Re
 		// 檢查倉庫中是否已有同種可堆疊物品
 		if stackable {
 			found := false
-			for _, wc := range player.WarehouseItems {
+			for i, wc := range player.WarehouseItems {
 				if wc.ItemID == invItem.ItemID {
-					err := s.deps.WarehouseRepo.AddToStack(ctx, wc.DbID, qty)
+					err := s.addToStack(ctx, player, whType, wc, qty)
+					if errors.Is(err, persist.ErrWarehouseStale) {
+						// 其他角色已領出此堆疊：移除過期快取，改為新增一筆
+						player.WarehouseItems = append(player.WarehouseItems[:i], player.WarehouseItems[i+1:]...)
+						break
+					}
 					if err != nil {
 						s.deps.Log.Error("倉庫堆疊新增失敗", zap.Error(err))
 						continue
@@ -307,6 +325,7 @@ func (s *WarehouseSystem) handleWarehouseDeposit(sess *net.Session, r *packet.Re
 
 		// 新增倉庫物品
 		whItem := persist.WarehouseItem{
+			AccountID:   player.AccountID,
 			AccountName: dbAccountName,
 			CharName:    player.Name,
 			WhType:      whType,
@@ -317,7 +336,7 @@ func (s *WarehouseSystem) handleWarehouseDeposit(sess *net.Session, r *packet.Re
 			Identified:  invItem.Identified,
 		}
 
-		dbID, err := s.deps.WarehouseRepo.Deposit(ctx, whItem)
+		dbID, err := s.depositRow(ctx, whItem)
 		if err != nil {
 			s.deps.Log.Error("倉庫存入失敗", zap.Error(err))
 			continue
@@ -432,13 +451,31 @@ func (s *WarehouseSystem) handleWarehouseWithdraw(sess *net.Session, r *packet.R
 		if qty > wc.Count {
 			qty = wc.Count
 		}
+		// 可堆疊物品不超過堆疊上限（金幣依身上上限 adena_carry_max），超出部分留在倉庫
+		if wc.Stackable {
+			if room := stackRoom(s.deps, player, wc.ItemID); int64(qty) > room {
+				if room <= 0 {
+					handler.SendSystemMessage(sess, fmt.Sprintf("%s已達攜帶上限。", wc.Name))
+					continue
+				}
+				qty = int32(room)
+			}
+		}
 
 		if player.Inv.IsFull() {
 			handler.SendServerMessage(sess, 263)
 			break
 		}
 
-		fullyRemoved, err := s.deps.WarehouseRepo.Withdraw(ctx, wc.DbID, qty)
+		fullyRemoved, err := s.withdrawRow(ctx, player, whType, wc, qty)
+		if errors.Is(err, persist.ErrWarehouseStale) {
+			// 快取與 DB 不一致（同帳號其他角色已變動）：重新載入，請玩家重新開啟
+			handler.SendSystemMessage(sess, "倉庫內容已變更，請重新開啟倉庫。")
+			if err := s.loadWarehouseCache(sess, player, whType); err != nil {
+				player.WarehouseItems = nil
+			}
+			break
+		}
 		if err != nil {
 			s.deps.Log.Error("倉庫取出失敗", zap.Error(err))
 			continue
@@ -462,7 +499,7 @@ func (s *WarehouseSystem) handleWarehouseWithdraw(sess *net.Session, r *packet.R
 			wc.Stackable,
 			byte(wc.Bless),
 		)
-		item.EnchantLvl = int8(wc.EnchantLvl)
+		item.EnchantLvl = world.ClampEnchant(int(wc.EnchantLvl))
 		item.Identified = wc.Identified
 		item.UseType = wc.UseType
 
@@ -524,3 +561,27 @@ func (s *WarehouseSystem) releaseClanWarehouseLock(player *world.PlayerInfo) {
 		clan.WarehouseUsingCharID = 0
 	}
 }
+
+// addToStack 增加倉庫既有堆疊數量（個人倉庫為帳號共用倉庫，以帳號 ID 驗證擁有者）。
+func (s *WarehouseSystem) addToStack(ctx context.Context, player *world.PlayerInfo, whType int16, wc *world.WarehouseCache, qty int32) error {
+	if whType == handler.WhTypePersonal {
+		return s.deps.WarehouseRepo.AddToAccountStack(ctx, player.AccountID, wc.DbID, qty)
+	}
+	return s.deps.WarehouseRepo.AddToStack(ctx, wc.DbID, qty)
+}
+
+// depositRow 新增一筆倉庫物品，回傳 DB ID。
+func (s *WarehouseSystem) depositRow(ctx context.Context, item persist.WarehouseItem) (int32, error) {
+	if item.WhType == handler.WhTypePersonal {
+		return s.deps.WarehouseRepo.DepositAccount(ctx, item)
+	}
+	return s.deps.WarehouseRepo.Deposit(ctx, item)
+}
+
+// withdrawRow 自倉庫扣除數量；帳號共用倉庫在 DB 數量不足時回傳 persist.ErrWarehouseStale。
+func (s *WarehouseSystem) withdrawRow(ctx context.Context, player *world.PlayerInfo, whType int16, wc *world.WarehouseCache, qty int32) (bool, error) {
+	if whType == handler.WhTypePersonal {
+		return s.deps.WarehouseRepo.WithdrawAccount(ctx, player.AccountID, wc.DbID, qty)
+	}
+	return s.deps.WarehouseRepo.Withdraw(ctx, wc.DbID, qty)
+}
@@ -0,0 +1,62 @@
+package system
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/net/packet"
+	"github.com/l1jgo/server/internal/world"
+)
+
+// depositPacket 組出 C_RESULT 存入清單 [D objectID][D count]...（含開頭 opcode 位元組）。
+func depositPacket(items ...*world.InvItem) *packet.Reader {
+	buf := make([]byte, 1, 1+8*len(items))
+	for _, it := range items {
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(it.ObjectID))
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(it.Count))
+	}
+	return packet.NewReader(buf)
+}
+
+func TestAccountWarehouseRejectsSealedAndUntradeable(t *testing.T) {
+	deps := newTestDeps(t)
+	deps.Items = newTestItems(t, `  - {item_id: 40010, name: 治癒藥水, stackable: true, tradeable: true}
+  - {item_id: 49000, name: 任務道具, tradeable: false}
+`)
+	// WarehouseRepo 為 nil：任何 DB 存取都會 panic，確保被拒絕的物品不會寫入
+	s := NewWarehouseSystem(deps)
+
+	sess := newTestSession(t, 1)
+	player := &world.PlayerInfo{SessionID: 1, Session: sess, CharID: 1, Name: "depositor", AccountID: 7, Inv: world.NewInventory()}
+	sealed := player.Inv.AddItem(40010, 3, "治癒藥水", 0, 0, true, 128)
+	quest := player.Inv.AddItem(49000, 1, "任務道具", 0, 0, false, 1)
+
+	s.handleWarehouseDeposit(sess, depositPacket(sealed, quest), 2, player, handler.WhTypePersonal)
+
+	if sealed.Count != 3 || player.Inv.FindByObjectID(sealed.ObjectID) == nil {
+		t.Error("sealed item left the inventory")
+	}
+	if player.Inv.FindByObjectID(quest.ObjectID) == nil {
+		t.Error("untradeable item left the inventory")
+	}
+	var rejected int
+	for _, p := range sentPackets(sess) {
+		if p[0] == packet.S_OPCODE_MESSAGE_CODE && binary.LittleEndian.Uint16(p[1:]) == msgCannotTransfer {
+			rejected++
+		}
+	}
+	if rejected != 2 {
+		t.Errorf("got %d message-210 rejections, want 2", rejected)
+	}
+}
+
+func TestAccountWarehouseNeedsAccountID(t *testing.T) {
+	s := NewWarehouseSystem(newTestDeps(t))
+	sess := newTestSession(t, 1)
+	player := &world.PlayerInfo{SessionID: 1, Session: sess, Name: "noaccount"}
+
+	if err := s.loadWarehouseCache(sess, player, handler.WhTypePersonal); err == nil {
+		t.Fatal("loaded the account warehouse without an account ID")
+	}
+}
@@ -145,14 +145,8 @@ func processWeaponSkillAoE(player *world.PlayerInfo, primaryTarget *world.NpcInf
 			handler.SendActionGfx(viewer.Session, target.ID, 2) // ACTION_Damage = 2
 		}
 
-		// 血量更新
-		hpRatio := int16(0)
-		if target.MaxHP > 0 {
-			hpRatio = int16((target.HP * 100) / target.MaxHP)
-		}
-		for _, viewer := range nearby {
-			handler.SendHpMeter(viewer.Session, target.ID, hpRatio)
-		}
+		// 血量更新（節流）
+		broadcastNpcHpMeter(target, nearby, deps)
 
 		// 死亡檢查
 		if target.HP <= 0 {
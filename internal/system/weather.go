@@ -4,18 +4,23 @@ import (
 	"time"
 
 	coresys "github.com/l1jgo/server/internal/core/system"
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/handler"
 	"github.com/l1jgo/server/internal/net/packet"
 	"github.com/l1jgo/server/internal/world"
 )
 
 // WeatherSystem checks for game-hour changes and randomizes weather on each
-// new hour. Broadcasts S_WEATHER to all online players. Phase 3 (PostUpdate).
+// new hour. Maps listed in weather.yaml roll their own weather; every other
+// map follows the global weather. Broadcasts S_WEATHER to all online players.
+// Phase 3 (PostUpdate).
 type WeatherSystem struct {
 	world *world.State
+	table *data.WeatherTable
 }
 
-func NewWeatherSystem(ws *world.State) *WeatherSystem {
-	return &WeatherSystem{world: ws}
+func NewWeatherSystem(ws *world.State, table *data.WeatherTable) *WeatherSystem {
+	return &WeatherSystem{world: ws, table: table}
 }
 
 func (s *WeatherSystem) Phase() coresys.Phase { return coresys.PhasePostUpdate }
@@ -27,16 +32,41 @@ func (s *WeatherSystem) Update(_ time.Duration) {
 		// First tick — initialize without broadcast
 		s.world.LastHour = curHour
 		s.world.RandomizeWeather()
+		s.rollMaps()
 		return
 	}
 	if curHour != s.world.LastHour {
 		s.world.LastHour = curHour
 		s.world.RandomizeWeather()
-		weather := s.world.Weather
+		changed := s.rollMaps()
 		s.world.AllPlayers(func(p *world.PlayerInfo) {
+			weather := s.world.WeatherAt(p.MapID)
 			w := packet.NewWriterWithOpcode(packet.S_OPCODE_WEATHER)
-			w.WriteC(weather)
+			w.WriteC(weather.Code)
 			p.Session.Send(w.Bytes())
+			// 霧沒有客戶端天氣效果，以訊息提示
+			if changed[p.MapID] && weather.Kind == data.WeatherFog {
+				handler.SendSystemMessage(p.Session, "四周起霧了，怪物較難察覺你的存在。")
+			}
 		})
 	}
 }
+
+// rollMaps rolls weather for every map in the table and returns the maps
+// whose weather kind changed.
+func (s *WeatherSystem) rollMaps() map[int16]bool {
+	changed := make(map[int16]bool)
+	for _, mapID := range s.table.MapIDs() {
+		kind, ok := s.table.Roll(mapID, world.RandInt)
+		if ok && s.world.SetMapWeather(mapID, kind) {
+			changed[mapID] = true
+		}
+	}
+	return changed
+}
+
+// weatherSkillDamage 依施法地點天氣調整屬性魔法傷害（weather.yaml effects.*.element_dmg_pct），
+// 於 Lua 公式之後、clampDamage 之前套用。
+func weatherSkillDamage(deps *handler.Deps, mapID int16, attr int, dmg int32) int32 {
+	return deps.Weather.ElementDamage(deps.World.WeatherAt(mapID).Kind, attr, dmg)
+}
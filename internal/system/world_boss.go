@@ -0,0 +1,171 @@
+package system
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/l1jgo/server/internal/core/event"
+	coresys "github.com/l1jgo/server/internal/core/system"
+	"github.com/l1jgo/server/internal/data"
+	"github.com/l1jgo/server/internal/handler"
+	"github.com/l1jgo/server/internal/world"
+	"go.uber.org/zap"
+)
+
+// bossEntry 記錄單一排程頭目的執行期狀態。
+type bossEntry struct {
+	def        data.BossSpawn
+	next       time.Time // 下次生成時間
+	npcObjID   int32     // 目前場上的實例（0 = 無）
+	firstBlood bool      // 本次實例是否已記錄首位攻擊者
+}
+
+// WorldBossSystem 依 boss_schedule.yaml 定時生成世界頭目並全服公告。
+// 前一隻仍存活（或屍體尚未消失）時不重複生成；記錄首位攻擊者與擊殺者。
+// 與一般 spawn_list 的「死亡後重生」不同，排程頭目不設重生計時，屍體消失後即移除。
+// Phase 3（PostUpdate），每秒檢查一次。
+type WorldBossSystem struct {
+	ws      *world.State
+	deps    *handler.Deps
+	bosses  []*bossEntry
+	tickAcc int
+}
+
+// NewWorldBossSystem 建構世界頭目系統，並計算各頭目的首次生成時間。
+func NewWorldBossSystem(ws *world.State, deps *handler.Deps, schedule []data.BossSpawn) *WorldBossSystem {
+	s := &WorldBossSystem{ws: ws, deps: deps}
+	now := time.Now()
+	for _, def := range schedule {
+		if deps.Npcs.Get(def.NpcID) == nil {
+			deps.Log.Warn("世界頭目: 未知的 NPC ID", zap.Int32("npc_id", def.NpcID))
+			continue
+		}
+		s.bosses = append(s.bosses, &bossEntry{def: def, next: def.NextSpawn(now)})
+	}
+	return s
+}
+
+func (s *WorldBossSystem) Phase() coresys.Phase { return coresys.PhasePostUpdate }
+
+func (s *WorldBossSystem) Update(_ time.Duration) {
+	// 每 5 tick（約 1 秒）檢查一次
+	s.tickAcc++
+	if s.tickAcc < 5 {
+		return
+	}
+	s.tickAcc = 0
+
+	now := time.Now()
+	for _, b := range s.bosses {
+		s.trackInstance(b)
+
+		if now.Before(b.next) {
+			continue
+		}
+		b.next = b.def.NextSpawn(now)
+		if b.npcObjID != 0 {
+			s.deps.Log.Info(fmt.Sprintf("世界頭目仍存活，略過本次生成  npc=%d  下次=%s",
+				b.def.NpcID, b.next.Format("2006-01-02 15:04")))
+			continue
+		}
+		s.spawn(b)
+	}
+}
+
+// OnEntityKilled 訂閱 EntityKilled 事件：排程頭目被擊殺時全服公告並記錄。
+func (s *WorldBossSystem) OnEntityKilled(ev event.EntityKilled) {
+	for _, b := range s.bosses {
+		if b.npcObjID == 0 || b.npcObjID != ev.NpcID {
+			continue
+		}
+		killerName := "?"
+		if killer := s.ws.GetByCharID(ev.KillerCharID); killer != nil {
+			killerName = killer.Name
+		}
+		bossName := s.bossName(b)
+		// 模板標記 boss 的頭目已由 onBossKilled 公告，不重複
+		if tmpl := s.deps.Npcs.Get(b.def.NpcID); tmpl == nil || !tmpl.Boss {
+			s.announce(fmt.Sprintf("\\f3%s 擊敗了世界頭目 %s！", killerName, bossName))
+		}
+		s.deps.Log.Info(fmt.Sprintf("世界頭目被擊殺  頭目=%s  擊殺者=%s  下次=%s",
+			bossName, killerName, b.next.Format("2006-01-02 15:04")))
+		return
+	}
+}
+
+// trackInstance 追蹤場上實例：記錄首位攻擊者，屍體消失後移除並釋放名額。
+func (s *WorldBossSystem) trackInstance(b *bossEntry) {
+	if b.npcObjID == 0 {
+		return
+	}
+	npc := s.ws.GetNpc(b.npcObjID)
+	if npc == nil {
+		b.npcObjID = 0
+		return
+	}
+
+	if !npc.Dead {
+		if !b.firstBlood && len(npc.HateList) > 0 {
+			for sessID := range npc.HateList {
+				if p := s.ws.GetBySession(sessID); p != nil {
+					b.firstBlood = true
+					s.deps.Log.Info(fmt.Sprintf("世界頭目首位攻擊者  頭目=%s  角色=%s", npc.Name, p.Name))
+					break
+				}
+			}
+		}
+		return
+	}
+
+	// 死亡：等 NpcRespawnSystem 的屍體計時結束後才從世界移除
+	if npc.DeleteTimer <= 0 {
+		s.ws.RemoveNpc(npc.ID)
+		b.npcObjID = 0
+	}
+}
+
+// spawn 生成頭目實例（NewNpcFromTemplate，與啟動時 spawnNpcs 相同），廣播外觀並全服公告。
+func (s *WorldBossSystem) spawn(b *bossEntry) {
+	tmpl := s.deps.Npcs.Get(b.def.NpcID)
+	if tmpl == nil {
+		return
+	}
+
+	npc := NewNpcFromTemplate(tmpl, s.deps.SprTable, b.def.X, b.def.Y, b.def.MapID, b.def.Heading) // 排程生成：不重生
+	s.ws.AddNpc(npc)
+	if s.deps.MapData != nil {
+		s.deps.MapData.SetImpassable(npc.MapID, npc.X, npc.Y, true)
+	}
+
+	// 廣播給附近玩家
+	nearby := s.ws.GetNearbyPlayersAt(npc.X, npc.Y, npc.MapID)
+	for _, viewer := range nearby {
+		handler.SendNpcPack(viewer.Session, npc)
+	}
+
+	b.npcObjID = npc.ID
+	b.firstBlood = false
+
+	msg := b.def.Announce
+	if msg == "" {
+		msg = fmt.Sprintf("世界頭目 %s 出現了！", npc.Name)
+	}
+	s.announce("\\f2" + msg)
+	s.deps.Log.Info(fmt.Sprintf("世界頭目生成  頭目=%s  地圖=%d  座標=(%d,%d)  下次=%s",
+		npc.Name, npc.MapID, npc.X, npc.Y, b.next.Format("2006-01-02 15:04")))
+}
+
+func (s *WorldBossSystem) bossName(b *bossEntry) string {
+	if tmpl := s.deps.Npcs.Get(b.def.NpcID); tmpl != nil {
+		return tmpl.Name
+	}
+	return fmt.Sprintf("npc#%d", b.def.NpcID)
+}
+
+// announce 全服綠色公告。
+func (s *WorldBossSystem) announce(msg string) {
+	pkt := handler.BuildGreenMessage(msg)
+	s.ws.AllPlayers(func(p *world.PlayerInfo) {
+		p.Session.Send(pkt)
+	})
+}
@@ -2,11 +2,21 @@ package world
 
 // AOIGrid implements a cell-based Area of Interest system.
 // Cell size is chosen so that a 3x3 neighbourhood of cells fully covers
-// the visibility range (Chebyshev distance 20).
+// the default visibility range (Chebyshev distance 20). Larger view ranges
+// widen the scanned neighbourhood via SetRange.
 // Accessed only from the game loop goroutine — no locks.
 
 const cellSize = 20
 
+// cellSpan returns how many cells on each side must be scanned so that the
+// neighbourhood covers the given view range.
+func cellSpan(viewRange int32) int32 {
+	if viewRange <= cellSize {
+		return 1
+	}
+	return (viewRange + cellSize - 1) / cellSize
+}
+
 type cellKey struct {
 	mapID int16
 	cx    int32
@@ -23,14 +33,21 @@ func toCellCoord(v int32) int32 {
 // AOIGrid tracks which sessions are in which cells.
 type AOIGrid struct {
 	cells map[cellKey]map[uint64]struct{} // cellKey → set of sessionIDs
+	span  int32                           // cells scanned on each side of the centre cell
 }
 
 func NewAOIGrid() *AOIGrid {
 	return &AOIGrid{
 		cells: make(map[cellKey]map[uint64]struct{}),
+		span:  1,
 	}
 }
 
+// SetRange adjusts the scanned neighbourhood to cover the given view range.
+func (g *AOIGrid) SetRange(viewRange int32) {
+	g.span = cellSpan(viewRange)
+}
+
 func (g *AOIGrid) key(x, y int32, mapID int16) cellKey {
 	return cellKey{mapID: mapID, cx: toCellCoord(x), cy: toCellCoord(y)}
 }
@@ -69,14 +86,14 @@ func (g *AOIGrid) Move(sessionID uint64, oldX, oldY int32, oldMap int16, newX, n
 	g.Add(sessionID, newX, newY, newMap)
 }
 
-// GetNearby returns all session IDs in a 3x3 neighbourhood of cells
+// GetNearby returns all session IDs in the neighbourhood of cells (3x3 by default)
 // around the given position. Caller does fine-grained distance filtering.
 func (g *AOIGrid) GetNearby(x, y int32, mapID int16) []uint64 {
 	cx := toCellCoord(x)
 	cy := toCellCoord(y)
 	var result []uint64
-	for dx := int32(-1); dx <= 1; dx++ {
-		for dy := int32(-1); dy <= 1; dy++ {
+	for dx := -g.span; dx <= g.span; dx++ {
+		for dy := -g.span; dy <= g.span; dy++ {
 			k := cellKey{mapID: mapID, cx: cx + dx, cy: cy + dy}
 			for sid := range g.cells[k] {
 				result = append(result, sid)
@@ -92,8 +109,8 @@ func (g *AOIGrid) GetNearbyInto(x, y int32, mapID int16, buf []uint64) []uint64
 	buf = buf[:0]
 	cx := toCellCoord(x)
 	cy := toCellCoord(y)
-	for dx := int32(-1); dx <= 1; dx++ {
-		for dy := int32(-1); dy <= 1; dy++ {
+	for dx := -g.span; dx <= g.span; dx++ {
+		for dy := -g.span; dy <= g.span; dy++ {
 			k := cellKey{mapID: mapID, cx: cx + dx, cy: cy + dy}
 			for sid := range g.cells[k] {
 				buf = append(buf, sid)
@@ -108,14 +125,21 @@ func (g *AOIGrid) GetNearbyInto(x, y int32, mapID int16, buf []uint64) []uint64
 // Separate type to avoid type assertions on the hot path.
 type NpcAOIGrid struct {
 	cells map[cellKey]map[int32]struct{}
+	span  int32
 }
 
 func NewNpcAOIGrid() *NpcAOIGrid {
 	return &NpcAOIGrid{
 		cells: make(map[cellKey]map[int32]struct{}),
+		span:  1,
 	}
 }
 
+// SetRange adjusts the scanned neighbourhood to cover the given view range.
+func (g *NpcAOIGrid) SetRange(viewRange int32) {
+	g.span = cellSpan(viewRange)
+}
+
 func (g *NpcAOIGrid) key(x, y int32, mapID int16) cellKey {
 	return cellKey{mapID: mapID, cx: toCellCoord(x), cy: toCellCoord(y)}
 }
@@ -154,13 +178,13 @@ func (g *NpcAOIGrid) Move(npcID int32, oldX, oldY int32, oldMap int16, newX, new
 	g.Add(npcID, newX, newY, newMap)
 }
 
-// GetNearby returns all NPC IDs in a 3x3 neighbourhood of cells.
+// GetNearby returns all NPC IDs in the neighbourhood of cells (3x3 by default).
 func (g *NpcAOIGrid) GetNearby(x, y int32, mapID int16) []int32 {
 	cx := toCellCoord(x)
 	cy := toCellCoord(y)
 	var result []int32
-	for dx := int32(-1); dx <= 1; dx++ {
-		for dy := int32(-1); dy <= 1; dy++ {
+	for dx := -g.span; dx <= g.span; dx++ {
+		for dy := -g.span; dy <= g.span; dy++ {
 			k := cellKey{mapID: mapID, cx: cx + dx, cy: cy + dy}
 			for nid := range g.cells[k] {
 				result = append(result, nid)
@@ -175,8 +199,8 @@ func (g *NpcAOIGrid) GetNearbyInto(x, y int32, mapID int16, buf []int32) []int32
 	buf = buf[:0]
 	cx := toCellCoord(x)
 	cy := toCellCoord(y)
-	for dx := int32(-1); dx <= 1; dx++ {
-		for dy := int32(-1); dy <= 1; dy++ {
+	for dx := -g.span; dx <= g.span; dx++ {
+		for dy := -g.span; dy <= g.span; dy++ {
 			k := cellKey{mapID: mapID, cx: cx + dx, cy: cy + dy}
 			for nid := range g.cells[k] {
 				buf = append(buf, nid)
@@ -0,0 +1,20 @@
+package world
+
+// SetKilledBosses seeds the set of boss templates already killed at least once
+// (loaded from boss_kills at boot). Replaces any previous set.
+func (s *State) SetKilledBosses(npcIDs []int32) {
+	clear(s.killedBosses)
+	for _, id := range npcIDs {
+		s.killedBosses[id] = true
+	}
+}
+
+// MarkBossKilled records a kill of the given boss template and reports whether
+// it is the first one ever (first blood).
+func (s *State) MarkBossKilled(npcID int32) bool {
+	if s.killedBosses[npcID] {
+		return false
+	}
+	s.killedBosses[npcID] = true
+	return true
+}
@@ -0,0 +1,23 @@
+package world
+
+import "testing"
+
+func TestMarkBossKilledFirstBloodOnce(t *testing.T) {
+	s := NewState()
+	s.SetKilledBosses([]int32{45516})
+
+	if s.MarkBossKilled(45516) {
+		t.Error("boss with a recorded kill reported as first blood")
+	}
+	if !s.MarkBossKilled(45545) {
+		t.Error("first kill of 45545 not reported as first blood")
+	}
+	if s.MarkBossKilled(45545) {
+		t.Error("second kill of 45545 reported as first blood")
+	}
+
+	s.SetKilledBosses(nil)
+	if !s.MarkBossKilled(45516) {
+		t.Error("SetKilledBosses did not replace the previous set")
+	}
+}
@@ -142,7 +142,7 @@ func (s *State) DollCount() int {
 	return len(s.dolls)
 }
 
-// GetNearbyDolls returns all dolls visible from the given position (Chebyshev <= 20).
+// GetNearbyDolls returns all dolls visible from the given position (Chebyshev <= view range).
 func (s *State) GetNearbyDolls(x, y int32, mapID int16) []*DollInfo {
 	nearbyIDs := s.npcAoi.GetNearby(x, y, mapID)
 	var result []*DollInfo
@@ -163,7 +163,7 @@ func (s *State) GetNearbyDolls(x, y int32, mapID int16) []*DollInfo {
 		if dy > dist {
 			dist = dy
 		}
-		if dist <= 20 {
+		if dist <= s.viewRange {
 			result = append(result, doll)
 		}
 	}
@@ -89,6 +89,23 @@ func IsTwoHanded(weaponType string) bool {
 	return false
 }
 
+// AmmoType returns the etcitem type a ranged weapon consumes per shot:
+// "arrow" for bows, "sting" for gauntlets, "" for melee weapons.
+func AmmoType(weaponType string) string {
+	switch weaponType {
+	case "bow", "singlebow":
+		return "arrow"
+	case "gauntlet":
+		return "sting"
+	}
+	return ""
+}
+
+// IsRangedWeapon returns true for weapon types that attack from range with ammo.
+func IsRangedWeapon(weaponType string) bool {
+	return AmmoType(weaponType) != ""
+}
+
 // WeaponVisualID maps a weapon type string to the client's visual animation byte.
 // This byte is sent in S_CHANGE_DESC (opcode 119) and S_PUT_OBJECT (opcode 87).
 func WeaponVisualID(weaponType string) byte {
@@ -99,8 +116,10 @@ func WeaponVisualID(weaponType string) byte {
 		return 46
 	case "tohandsword":
 		return 50
-	case "bow":
+	case "bow", "singlebow":
 		return 20
+	case "gauntlet":
+		return 62
 	case "spear", "singlespear":
 		return 24
 	case "blunt", "tohandblunt":
@@ -116,6 +135,18 @@ func WeaponVisualID(weaponType string) byte {
 	}
 }
 
+// WeaponAttackAction maps a weapon type string to the client's attack action
+// code sent in S_ATTACK (opcode 30). Each weapon stance's attack action is the
+// visual ID + 1 (sword 4 → 5, bow 20 → 21, claw 58 → 59); no weapon uses the
+// plain attack action 1.
+func WeaponAttackAction(weaponType string) byte {
+	visual := WeaponVisualID(weaponType)
+	if visual == 0 {
+		return 1
+	}
+	return visual + 1
+}
+
 // EquipStats holds the cumulative stat bonuses from all equipped items.
 type EquipStats struct {
 	AC        int
@@ -120,7 +120,7 @@ func (s *State) FollowerCount() int {
 	return len(s.followers)
 }
 
-// GetNearbyFollowers returns all alive followers visible from the given position (Chebyshev <= 20).
+// GetNearbyFollowers returns all alive followers visible from the given position (Chebyshev <= view range).
 func (s *State) GetNearbyFollowers(x, y int32, mapID int16) []*FollowerInfo {
 	nearbyIDs := s.npcAoi.GetNearby(x, y, mapID)
 	var result []*FollowerInfo
@@ -141,7 +141,7 @@ func (s *State) GetNearbyFollowers(x, y int32, mapID int16) []*FollowerInfo {
 		if dy > dist {
 			dist = dy
 		}
-		if dist <= 20 {
+		if dist <= s.viewRange {
 			result = append(result, f)
 		}
 	}
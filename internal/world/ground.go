@@ -27,6 +27,26 @@ type GroundItem struct {
 	X          int32
 	Y          int32
 	MapID      int16
-	OwnerID    int32 // CharID of dropper (0 = anyone can pick up)
-	TTL        int   // ticks remaining until auto-delete (0 = permanent)
+	OwnerID    int32   // CharID of dropper (0 = anyone can pick up)
+	TTL        int     // ticks remaining until auto-delete (0 = permanent)
+	Loot       bool    // monster loot spilled at the killer's feet (eligible for auto-loot)
+	OwnerTicks int     // ticks remaining in which only the owner (or party) may pick up
+	OwnerParty []int32 // CharIDs of the owner's party when the loot was tagged (nil = owner only)
+	Grade      byte    // item rarity from the template (0 = normal); colors the ground name
+
+	// Corpse holds the rolled drops when this object is an NPC corpse
+	// (gameplay.loot_corpse). Non-nil marks a corpse; it is removed once emptied.
+	Corpse []LootEntry
+}
+
+// LootEntry is one rolled monster drop waiting to be looted.
+type LootEntry struct {
+	ItemID     int32
+	Count      int32
+	EnchantLvl int8
+}
+
+// IsCorpse reports whether the ground object is a lootable NPC corpse.
+func (g *GroundItem) IsCorpse() bool {
+	return g.Corpse != nil
 }
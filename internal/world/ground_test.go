@@ -0,0 +1,69 @@
+package world
+
+import "testing"
+
+func TestFindGroundStackUsesTileIndex(t *testing.T) {
+	s := NewState()
+	pile := &GroundItem{ID: 1, ItemID: 40014, Count: 10, X: 100, Y: 200, MapID: 4}
+	other := &GroundItem{ID: 2, ItemID: 40014, Count: 10, X: 101, Y: 200, MapID: 4}
+	s.AddGroundItem(pile)
+	s.AddGroundItem(other)
+
+	drop := &GroundItem{ItemID: 40014, Count: 5, X: 100, Y: 200, MapID: 4}
+	if got := s.FindGroundStack(drop); got != pile {
+		t.Fatalf("FindGroundStack = %v, want pile on the same tile", got)
+	}
+
+	s.RemoveGroundItem(pile.ID)
+	if got := s.FindGroundStack(drop); got != nil {
+		t.Fatalf("removed pile still found: %v", got)
+	}
+	if len(s.groundTiles) != 1 {
+		t.Fatalf("tile index holds %d tiles, want 1", len(s.groundTiles))
+	}
+}
+
+func TestFindGroundStackRespectsStackCap(t *testing.T) {
+	s := NewState()
+	s.AddGroundItem(&GroundItem{ID: 1, ItemID: 40308, Count: MaxStackCount - 3, X: 1, Y: 1, MapID: 4})
+	if got := s.FindGroundStack(&GroundItem{ItemID: 40308, Count: 4, X: 1, Y: 1, MapID: 4}); got != nil {
+		t.Fatal("merge would exceed MaxStackCount")
+	}
+	if got := s.FindGroundStack(&GroundItem{ItemID: 40308, Count: 3, X: 1, Y: 1, MapID: 4}); got == nil {
+		t.Fatal("merge up to MaxStackCount should be allowed")
+	}
+}
+
+func TestTickGroundItemsUnindexesExpired(t *testing.T) {
+	s := NewState()
+	s.AddGroundItem(&GroundItem{ID: 1, ItemID: 40014, Count: 1, X: 1, Y: 1, MapID: 4, TTL: 1})
+	expired, _ := s.TickGroundItems()
+	if len(expired) != 1 || len(s.groundTiles) != 0 {
+		t.Fatalf("expired=%d tiles=%d, want 1 and 0", len(expired), len(s.groundTiles))
+	}
+}
+
+func TestFindGroundStackKeepsOwnershipApart(t *testing.T) {
+	s := NewState()
+	owned := &GroundItem{ID: 1, ItemID: 40308, Count: 10, X: 1, Y: 1, MapID: 4,
+		OwnerID: 7, OwnerTicks: 50, OwnerParty: []int32{7, 8}}
+	released := &GroundItem{ID: 2, ItemID: 40308, Count: 10, X: 2, Y: 1, MapID: 4, OwnerID: 7}
+	s.AddGroundItem(owned)
+	s.AddGroundItem(released)
+
+	// 同隊伍（順序不同）可合併
+	if got := s.FindGroundStack(&GroundItem{ItemID: 40308, Count: 1, X: 1, Y: 1, MapID: 4,
+		OwnerID: 7, OwnerTicks: 75, OwnerParty: []int32{8, 7}}); got != owned {
+		t.Fatal("same owner and party should merge")
+	}
+	// 不同隊伍不可合併
+	if got := s.FindGroundStack(&GroundItem{ItemID: 40308, Count: 1, X: 1, Y: 1, MapID: 4,
+		OwnerID: 7, OwnerTicks: 75, OwnerParty: []int32{7, 9}}); got != nil {
+		t.Fatal("different party merged into owned pile")
+	}
+	// 已釋放的物品堆不可被新的擁有期間重新鎖定
+	if got := s.FindGroundStack(&GroundItem{ItemID: 40308, Count: 1, X: 2, Y: 1, MapID: 4,
+		OwnerID: 7, OwnerTicks: 75}); got != nil {
+		t.Fatal("owned drop merged into released pile")
+	}
+}
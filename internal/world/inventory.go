@@ -2,21 +2,13 @@ package world
 
 import (
 	"math"
-	"math/rand"
 	"sync/atomic"
 )
 
-// RandInt returns a random int in [0, n). Safe to call from game loop goroutine.
-func RandInt(n int) int {
-	if n <= 0 {
-		return 0
-	}
-	return rand.Intn(n)
-}
-
 const (
 	MaxInventorySize = 180
 	AdenaItemID      = 40308
+	MaxStackCount    = 2_000_000_000 // Java L1Inventory.MAX_AMOUNT
 )
 
 // itemObjIDCounter generates unique item object IDs.
@@ -66,6 +58,30 @@ type InvItem struct {
 	AcMagicExpiry  int   // ticks remaining (0 = no effect)
 }
 
+// EffectiveEnchant returns the enchant level that counts in combat:
+// EnchantLvl minus weapon wear (Durability).
+func (it *InvItem) EffectiveEnchant() int {
+	return int(it.EnchantLvl) - int(it.Durability)
+}
+
+// Enchant levels are stored as int8 (client status byte is signed).
+const (
+	MinEnchantLvl = -128
+	MaxEnchantLvl = 127
+)
+
+// ClampEnchant converts an enchant level to int8, clamping to the supported
+// range instead of wrapping (e.g. a +127 item never becomes -128).
+func ClampEnchant(v int) int8 {
+	if v > MaxEnchantLvl {
+		return MaxEnchantLvl
+	}
+	if v < MinEnchantLvl {
+		return MinEnchantLvl
+	}
+	return int8(v)
+}
+
 // Inventory holds a player's in-memory item list.
 // Accessed only from the game loop goroutine.
 type Inventory struct {
@@ -162,6 +178,53 @@ func (inv *Inventory) RemoveItem(objectID int32, count int32) (removed bool) {
 	return false
 }
 
+// StackMerge records one stack folded into another by Consolidate.
+type StackMerge struct {
+	Into      *InvItem // surviving stack (Count already updated)
+	RemovedID int32    // ObjectID of the stack that was folded in and removed
+}
+
+// Consolidate merges duplicate stacks of the same stackable item (same ItemID,
+// Bless and EnchantLvl) into one. An equipped stack is kept as the survivor;
+// other equipped stacks are never removed. A merge that would push a stack
+// past MaxStackCount is skipped. Does NOT send packets — caller is responsible.
+func (inv *Inventory) Consolidate() []StackMerge {
+	type stackKey struct {
+		itemID  int32
+		bless   byte
+		enchant int8
+	}
+	keep := make(map[stackKey]*InvItem)
+	for _, it := range inv.Items {
+		if !it.Stackable {
+			continue
+		}
+		k := stackKey{it.ItemID, it.Bless, it.EnchantLvl}
+		if cur, ok := keep[k]; !ok || (it.Equipped && !cur.Equipped) {
+			keep[k] = it
+		}
+	}
+
+	var merges []StackMerge
+	kept := inv.Items[:0]
+	for _, it := range inv.Items {
+		if it.Stackable && !it.Equipped {
+			into := keep[stackKey{it.ItemID, it.Bless, it.EnchantLvl}]
+			if into != it && int64(into.Count)+int64(it.Count) <= MaxStackCount {
+				into.Count += it.Count
+				merges = append(merges, StackMerge{Into: into, RemovedID: it.ObjectID})
+				continue
+			}
+		}
+		kept = append(kept, it)
+	}
+	for i := len(kept); i < len(inv.Items); i++ {
+		inv.Items[i] = nil
+	}
+	inv.Items = kept
+	return merges
+}
+
 // GetAdena returns the current adena count.
 func (inv *Inventory) GetAdena() int32 {
 	item := inv.FindByItemID(AdenaItemID)
@@ -0,0 +1,19 @@
+package world
+
+import "testing"
+
+func TestClampEnchant(t *testing.T) {
+	tests := []struct {
+		in   int
+		want int8
+	}{
+		{0, 0}, {15, 15},
+		{126, 126}, {127, 127}, {128, 127}, {300, 127},
+		{-127, -127}, {-128, -128}, {-129, -128}, {-300, -128},
+	}
+	for _, tt := range tests {
+		if got := ClampEnchant(tt.in); got != tt.want {
+			t.Errorf("ClampEnchant(%d) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
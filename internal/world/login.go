@@ -0,0 +1,117 @@
+package world
+
+import "github.com/l1jgo/server/internal/net"
+
+// QueuedLogin is an authenticated login: either admitted, or waiting for a
+// free slot while the server is at its max_online cap.
+type QueuedLogin struct {
+	Session *net.Session
+	Account string
+}
+
+// LoginGate tracks logged-in accounts (by session) and the login queue.
+// Accessed only from the game loop goroutine — no locks needed.
+type LoginGate struct {
+	online map[uint64]QueuedLogin // SessionID → logged-in session
+	queue  []QueuedLogin
+}
+
+func NewLoginGate() *LoginGate {
+	return &LoginGate{
+		online: make(map[uint64]QueuedLogin),
+	}
+}
+
+// Admit marks a session as logged in.
+func (g *LoginGate) Admit(sess *net.Session, account string) {
+	g.online[sess.ID] = QueuedLogin{Session: sess, Account: account}
+}
+
+// SessionOf returns the session logged in (or queued) under an account, or
+// nil if there is none.
+func (g *LoginGate) SessionOf(account string) *net.Session {
+	for _, l := range g.online {
+		if l.Account == account {
+			return l.Session
+		}
+	}
+	for _, q := range g.queue {
+		if q.Account == account {
+			return q.Session
+		}
+	}
+	return nil
+}
+
+// Release removes a session from the online set and the queue (disconnect).
+func (g *LoginGate) Release(sessID uint64) {
+	delete(g.online, sessID)
+	for i, q := range g.queue {
+		if q.Session.ID == sessID {
+			g.queue = append(g.queue[:i], g.queue[i+1:]...)
+			break
+		}
+	}
+}
+
+// AccountsFromIP returns the accounts logged in (or queued) from an address.
+func (g *LoginGate) AccountsFromIP(ip string) []string {
+	var result []string
+	for _, l := range g.online {
+		if l.Session.IP == ip {
+			result = append(result, l.Account)
+		}
+	}
+	for _, q := range g.queue {
+		if q.Session.IP == ip {
+			result = append(result, q.Account)
+		}
+	}
+	return result
+}
+
+// OnlineCount returns the number of logged-in accounts (in-world or at the
+// character list).
+func (g *LoginGate) OnlineCount() int {
+	return len(g.online)
+}
+
+// Enqueue appends a login to the queue and returns its 1-based position.
+func (g *LoginGate) Enqueue(q QueuedLogin) int {
+	g.queue = append(g.queue, q)
+	return len(g.queue)
+}
+
+// IsQueued reports whether an account already has a login waiting.
+func (g *LoginGate) IsQueued(account string) bool {
+	for _, q := range g.queue {
+		if q.Account == account {
+			return true
+		}
+	}
+	return false
+}
+
+// QueueLen returns the number of waiting logins.
+func (g *LoginGate) QueueLen() int {
+	return len(g.queue)
+}
+
+// Waiting returns the queued logins in admission order (position = index+1).
+// The slice is owned by the gate; callers must not modify it.
+func (g *LoginGate) Waiting() []QueuedLogin {
+	return g.queue
+}
+
+// PopQueue removes and returns the oldest waiting login whose connection is
+// still open. Returns false when the queue is empty.
+func (g *LoginGate) PopQueue() (QueuedLogin, bool) {
+	for len(g.queue) > 0 {
+		q := g.queue[0]
+		g.queue = g.queue[1:]
+		if !q.Session.IsClosed() {
+			return q, true
+		}
+	}
+	return QueuedLogin{}, false
+}
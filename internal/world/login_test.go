@@ -0,0 +1,34 @@
+package world
+
+import (
+	stdnet "net"
+	"testing"
+
+	"github.com/l1jgo/server/internal/net"
+	"go.uber.org/zap"
+)
+
+func TestLoginQueuePositions(t *testing.T) {
+	g := NewLoginGate()
+	var sessions []*net.Session
+	for i := uint64(1); i <= 3; i++ {
+		c1, c2 := stdnet.Pipe()
+		defer c1.Close()
+		defer c2.Close()
+		sess := net.NewSession(c1, i, 4, 16, 0, 0, zap.NewNop())
+		sessions = append(sessions, sess)
+		if pos := g.Enqueue(QueuedLogin{Session: sess, Account: "acct" + string(rune('0'+i))}); pos != int(i) {
+			t.Fatalf("enqueue %d: position %d", i, pos)
+		}
+	}
+
+	// 第一位放行、第二位斷線後，第三位應排到第一位
+	if q, ok := g.PopQueue(); !ok || q.Session != sessions[0] {
+		t.Fatal("PopQueue did not return the oldest login")
+	}
+	g.Release(sessions[1].ID)
+	waiting := g.Waiting()
+	if len(waiting) != 1 || waiting[0].Session != sessions[2] {
+		t.Fatalf("waiting = %+v, want only session 3", waiting)
+	}
+}
@@ -1,6 +1,11 @@
 package world
 
-import "sync/atomic"
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/l1jgo/server/internal/data"
+)
 
 // npcIDCounter generates unique NPC object IDs.
 // Starts at 200_000_000 to avoid collision with character DB IDs.
@@ -15,44 +20,83 @@ func NextNpcID() int32 {
 	return npcIDCounter.Add(1)
 }
 
+// agroNameLawful is the lawful value reported in S_PUT_OBJECT for aggressive
+// NPCs whose template lawful is higher (0 = always use the template lawful).
+var agroNameLawful int32
+
+// SetAgroNameLawful sets the name-color lawful for aggressive NPCs ([world] npc_agro_name_lawful).
+func SetAgroNameLawful(v int32) {
+	agroNameLawful = v
+}
+
+// NameLawful returns the lawful value sent in S_PUT_OBJECT. The client colors
+// NPC names by it (negative = red shades), so aggressive monsters are pushed
+// down to agroNameLawful and everything else uses its template lawful.
+func (n *NpcInfo) NameLawful() int16 {
+	lawful := n.Lawful
+	if n.Agro && agroNameLawful != 0 && lawful > agroNameLawful {
+		lawful = agroNameLawful
+	}
+	return int16(max(min(lawful, 32767), -32768))
+}
+
 // NpcInfo holds runtime data for an NPC currently in-world.
 // Accessed only from the game loop goroutine — no locks.
 type NpcInfo struct {
-	ID      int32 // unique object ID (from NextNpcID)
-	NpcID   int32 // template ID
-	Impl    string // L1Monster, L1Merchant, L1Guard, etc.
-	GfxID   int32
-	Name    string
-	NameID  string // client string table key (e.g. "$936")
-	Level   int16
-	X       int32
-	Y       int32
-	MapID   int16
-	Heading int16
-	HP      int32
-	MaxHP   int32
-	MP      int32
-	MaxMP   int32
-	AC      int16
-	STR     int16
-	DEX     int16
-	Exp     int32  // exp reward on kill
-	Lawful  int32
-	Size    string // "small" or "large"
-	MR      int16
-	Undead  bool
-	Agro    bool   // true = aggressive, attacks players on sight
-	AtkDmg  int32  // damage per attack (simplified: Level + STR/3)
-	Ranged  int16  // attack range (1 = melee, >1 = ranged attacker)
-	AtkSpeed   int16 // attack animation speed (ms, 0 = default)
-	MoveSpeed  int16 // passive/move speed (ms, 0 = default)
-	PoisonAtk  byte  // 怪物施毒能力（從模板載入）: 0=無, 1=傷害毒, 2=沉默毒, 4=麻痺毒
+	activeGen uint32 // State.ActiveNpcs dedup stamp
+
+	ID             int32  // unique object ID (from NextNpcID)
+	NpcID          int32  // template ID
+	Impl           string // L1Monster, L1Merchant, L1Guard, etc.
+	GfxID          int32
+	Name           string
+	NameID         string // client string table key (e.g. "$936")
+	Title          string // shown under the name (from template)
+	Level          int16
+	X              int32
+	Y              int32
+	MapID          int16
+	Heading        int16
+	HP             int32
+	MaxHP          int32
+	MP             int32
+	MaxMP          int32
+	AC             int16
+	STR            int16
+	DEX            int16
+	Exp            int32 // exp reward on kill
+	Lawful         int32
+	Size           string // "small" or "large"
+	MR             int16
+	Undead         bool
+	Agro           bool  // true = aggressive, attacks players on sight
+	Tameable       bool  // 可被技能 36（馴服怪物）馴服（從模板載入）
+	AtkDmg         int32 // damage per attack (simplified: Level + STR/3)
+	Ranged         int16 // attack range (1 = melee, >1 = ranged attacker)
+	AtkSpeed       int16 // attack animation speed (ms, 0 = default)
+	MoveSpeed      int16 // passive/move speed (ms, 0 = default)
+	RunSpeed       int16 // chase move speed (ms, 0 = MoveSpeed)
+	RangedAtkSpeed int16 // ranged attack animation speed (ms, 0 = AtkSpeed)
+	PoisonAtk      byte  // 怪物施毒能力（從模板載入）: 0=無, 1=傷害毒, 2=沉默毒, 4=麻痺毒
+	WanderRadius   int32 // max wander distance from spawn (0 = [world] wander_radius)
+
+	// Status immunities and element resistances (from template).
+	// Negative resistance = elemental weakness.
+	ImmuneParalyze bool
+	ImmuneSleep    bool
+	ImmunePoison   bool
+	FireRes        int16
+	WaterRes       int16
+	WindRes        int16
+	EarthRes       int16
 
 	// Spawn data for respawning
 	SpawnX       int32
 	SpawnY       int32
 	SpawnMapID   int16
 	RespawnDelay int // seconds
+	SpawnArea    *data.SpawnArea // spawn rectangle (nil = fixed point at SpawnX/SpawnY)
+	SplitGen     int             // spawn-on-death generation (0 = spawned normally); split children are removed after death
 
 	// State
 	Dead         bool
@@ -62,6 +106,7 @@ type NpcInfo struct {
 	// AI state — 仇恨系統
 	AggroTarget  uint64           // SessionID of hate target (0 = no target)，由仇恨列表驅動
 	HateList     map[uint64]int32 // 仇恨列表 — key=SessionID, value=累積傷害仇恨值
+	FirstTagger  uint64           // SessionID of the first player to damage this NPC (kill_credit = first_tag)
 	AttackTimer  int    // ticks until next attack (cooldown)
 	MoveTimer    int    // ticks until next move towards target
 	StuckTicks   int    // consecutive ticks blocked by another entity (for stuck detection)
@@ -71,6 +116,13 @@ type NpcInfo struct {
 	WanderDir    int16 // current wander heading (0-7)
 	WanderTimer  int   // ticks until next wander step
 
+	// Patrol route (spawn_list patrol); replaces wandering / returning home when set
+	Patrol      []data.PatrolPoint // waypoints, walked in a loop
+	PatrolPause int                // ticks to wait at each waypoint
+	PatrolIdx   int                // waypoint currently walked to
+	PatrolWait  int                // ticks left waiting at the reached waypoint
+	PatrolStuck int                // consecutive steps that made no progress
+
 	// 負面狀態（debuff）
 	Paralyzed     bool           // 麻痺/凍結/暈眩 — 跳過所有 AI 行為
 	Sleeped       bool           // 睡眠 — 跳過所有 AI 行為，受傷時解除
@@ -80,6 +132,18 @@ type NpcInfo struct {
 	PoisonDmgAmt      int32  // 每次扣血量（0=無毒）
 	PoisonDmgTimer    int    // 距下次扣血的 tick 計數（每 15 tick 扣一次）
 	PoisonAttackerSID uint64 // 施毒者 SessionID（仇恨歸屬用）
+
+	// HP 條廣播節流（見 system.broadcastNpcHpMeter）
+	HpMeterSentAt  time.Time // 上次廣播時間
+	HpMeterBucket  int16     // 上次廣播的 HP% 區段
+	HpMeterPending bool      // 節流期間有未廣播的 HP 變化，間隔到期後補送
+}
+
+// busy reports whether the NPC has state that must keep ticking even with no
+// player nearby: an aggro target, debuff timers or spell poison.
+func (n *NpcInfo) busy() bool {
+	return n.AggroTarget != 0 || len(n.ActiveDebuffs) > 0 || n.PoisonDmgAmt > 0 ||
+		n.Paralyzed || n.Sleeped
 }
 
 // HasDebuff 檢查 NPC 是否有指定 debuff。
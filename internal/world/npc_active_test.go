@@ -0,0 +1,101 @@
+package world
+
+import (
+	"fmt"
+	"testing"
+)
+
+func addTestNpc(s *State, x, y int32) *NpcInfo {
+	npc := &NpcInfo{ID: NextNpcID(), X: x, Y: y, MapID: 4, HP: 10, MaxHP: 10}
+	s.AddNpc(npc)
+	return npc
+}
+
+func containsNpc(list []*NpcInfo, npc *NpcInfo) bool {
+	for _, n := range list {
+		if n == npc {
+			return true
+		}
+	}
+	return false
+}
+
+func TestActiveNpcsParksOnlyIdleNpcs(t *testing.T) {
+	s := NewState()
+	p := &PlayerInfo{SessionID: 1, CharID: 1, Name: "p", X: 1000, Y: 1000, MapID: 4}
+	s.AddPlayer(p)
+
+	near := addTestNpc(s, 1005, 1000)
+	farIdle := addTestNpc(s, 2000, 2000)
+	chasing := addTestNpc(s, 1010, 1000)
+	debuffed := addTestNpc(s, 1000, 1010)
+	poisoned := addTestNpc(s, 990, 1000)
+
+	active := s.ActiveNpcs(nil)
+	for _, npc := range []*NpcInfo{near, chasing, debuffed, poisoned} {
+		if !containsNpc(active, npc) {
+			t.Fatalf("npc at (%d,%d) near the player not active", npc.X, npc.Y)
+		}
+	}
+	if containsNpc(active, farIdle) {
+		t.Fatal("idle NPC far from every player is active")
+	}
+
+	// 玩家離開後：有目標、debuff、中毒的 NPC 繼續運作，閒置的暫停
+	chasing.AggroTarget = p.SessionID
+	debuffed.AddDebuff(64, 50)
+	poisoned.PoisonDmgAmt = 5
+	s.RemovePlayer(p.SessionID)
+
+	active = s.ActiveNpcs(active)
+	if containsNpc(active, near) {
+		t.Fatal("idle NPC stayed active after the player left")
+	}
+	for _, npc := range []*NpcInfo{chasing, debuffed, poisoned} {
+		if !containsNpc(active, npc) {
+			t.Fatalf("busy npc at (%d,%d) parked", npc.X, npc.Y)
+		}
+	}
+
+	// 狀態清除後下一個 tick 即暫停
+	chasing.AggroTarget = 0
+	debuffed.RemoveDebuff(64)
+	poisoned.PoisonDmgAmt = 0
+	if active = s.ActiveNpcs(active); len(active) != 0 {
+		t.Fatalf("%d NPCs still active with no players and no busy state", len(active))
+	}
+}
+
+func TestActiveNpcsDropsRemovedNpcs(t *testing.T) {
+	s := NewState()
+	s.AddPlayer(&PlayerInfo{SessionID: 1, CharID: 1, Name: "p", X: 100, Y: 100, MapID: 4})
+	npc := addTestNpc(s, 101, 100)
+	npc.AggroTarget = 1
+	active := s.ActiveNpcs(nil)
+	s.RemoveNpc(npc.ID)
+	if active = s.ActiveNpcs(active); containsNpc(active, npc) {
+		t.Fatal("removed NPC carried over")
+	}
+}
+
+// BenchmarkActiveNpcs 20k NPC 散布於 1000x1000 地圖、5 名玩家。
+func BenchmarkActiveNpcs(b *testing.B) {
+	s := NewState()
+	rng := NewRand(1)
+	for i := 0; i < 20000; i++ {
+		addTestNpc(s, 32000+int32(rng.Intn(1000)), 32000+int32(rng.Intn(1000)))
+	}
+	for i := 0; i < 5; i++ {
+		s.AddPlayer(&PlayerInfo{
+			SessionID: uint64(i + 1), CharID: int32(i + 1), Name: fmt.Sprintf("p%d", i),
+			X: 32100 + int32(i)*150, Y: 32500, MapID: 4,
+		})
+	}
+	var active []*NpcInfo
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		active = s.ActiveNpcs(active)
+	}
+	b.ReportMetric(float64(len(active)), "npcs/tick")
+}
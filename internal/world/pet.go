@@ -41,6 +41,7 @@ type PetInfo struct {
 	MaxMP       int32
 	Exp         int32
 	Lawful      int32
+	Tamed       bool // obtained via Taming Monster (persisted as pet_type)
 
 	// Appearance (from NPC template)
 	GfxID     int32
@@ -106,6 +107,9 @@ func (s *State) AddPet(pet *PetInfo) {
 	s.pets[pet.ID] = pet
 	s.npcAoi.Add(pet.ID, pet.X, pet.Y, pet.MapID)
 	s.entity.Occupy(pet.MapID, pet.X, pet.Y, pet.ID)
+	if !pet.Dead {
+		s.adjustActivePets(pet.OwnerCharID, 1)
+	}
 }
 
 // RemovePet removes a pet from the world and frees its tile.
@@ -120,6 +124,9 @@ func (s *State) RemovePet(petID int32) *PetInfo {
 	s.npcAoi.Remove(pet.ID, pet.X, pet.Y, pet.MapID)
 	s.entity.Vacate(pet.MapID, pet.X, pet.Y, pet.ID)
 	delete(s.pets, petID)
+	if !pet.Dead {
+		s.adjustActivePets(pet.OwnerCharID, -1)
+	}
 	return pet
 }
 
@@ -185,8 +192,38 @@ func (s *State) TeleportPet(petID int32, newX, newY int32, newMapID, heading int
 }
 
 // PetDied releases the tile occupied by a dead pet (keeps pet in world for collection).
+// The dead pet no longer counts toward the owner's ActivePets.
 func (s *State) PetDied(pet *PetInfo) {
 	s.entity.Vacate(pet.MapID, pet.X, pet.Y, pet.ID)
+	s.adjustActivePets(pet.OwnerCharID, -1)
+}
+
+// MaxPetCount is the hard cap on pets + summons a single player may control.
+const MaxPetCount = 5
+
+// MaxPets returns how many pets/summons a player may control at once for the
+// given charisma (class bonus included): one per 6 CHA, at least 1, at most MaxPetCount.
+func MaxPets(cha int) int {
+	n := cha / 6
+	if n < 1 {
+		n = 1
+	}
+	if n > MaxPetCount {
+		n = MaxPetCount
+	}
+	return n
+}
+
+// adjustActivePets updates the owner's ActivePets counter (no-op if offline).
+func (s *State) adjustActivePets(ownerCharID int32, delta int) {
+	owner := s.GetByCharID(ownerCharID)
+	if owner == nil {
+		return
+	}
+	owner.ActivePets += delta
+	if owner.ActivePets < 0 {
+		owner.ActivePets = 0
+	}
 }
 
 // PetCount returns total pet count in-world.
@@ -0,0 +1,61 @@
+package world
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Rand is the random source used by game logic (combat, drops, enchant, AI).
+// *rand.Rand satisfies it. Replace it with SetRand to pin a seed.
+type Rand interface {
+	Intn(n int) int
+	NormFloat64() float64
+}
+
+// lockedRand guards a *rand.Rand so the source can be shared safely with the
+// few callers that run outside the game loop goroutine.
+type lockedRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func (l *lockedRand) Intn(n int) int {
+	l.mu.Lock()
+	v := l.r.Intn(n)
+	l.mu.Unlock()
+	return v
+}
+
+func (l *lockedRand) NormFloat64() float64 {
+	l.mu.Lock()
+	v := l.r.NormFloat64()
+	l.mu.Unlock()
+	return v
+}
+
+var rng Rand = NewRand(time.Now().UnixNano())
+
+// NewRand returns a goroutine-safe random source seeded with seed.
+func NewRand(seed int64) Rand {
+	return &lockedRand{r: rand.New(rand.NewSource(seed))}
+}
+
+// SetRand replaces the game-wide random source. Call before the game loop starts
+// (at boot from config, or in tests) — it is not synchronized with readers.
+func SetRand(src Rand) {
+	rng = src
+}
+
+// RandInt returns a random int in [0, n). Safe to call from game loop goroutine.
+func RandInt(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return rng.Intn(n)
+}
+
+// RandNorm returns a normally distributed float64 (mean 0, stddev 1).
+func RandNorm() float64 {
+	return rng.NormFloat64()
+}
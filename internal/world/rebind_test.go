@@ -0,0 +1,47 @@
+package world
+
+import (
+	stdnet "net"
+	"testing"
+
+	"github.com/l1jgo/server/internal/net"
+	"go.uber.org/zap"
+)
+
+func TestRebindSessionMovesIndexesAndAggro(t *testing.T) {
+	s := NewState()
+	p := &PlayerInfo{SessionID: 1, CharID: 7, Name: "held", X: 1000, Y: 1000, MapID: 4}
+	s.AddPlayer(p)
+	npc := addTestNpc(s, 1003, 1000)
+	npc.AggroTarget = 1
+	npc.HateList = map[uint64]int32{1: 40}
+
+	c1, c2 := stdnet.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+	sess := net.NewSession(c1, 9, 4, 16, 0, 0, zap.NewNop())
+
+	s.RebindSession(p, sess)
+
+	if s.GetBySession(1) != nil {
+		t.Error("old session ID still resolves to the player")
+	}
+	if s.GetBySession(9) != p || p.Session != sess || p.SessionID != 9 {
+		t.Fatal("player not bound to the new session")
+	}
+	if s.GetByCharID(7) != p {
+		t.Error("char index lost the player")
+	}
+	if npc.AggroTarget != 9 || npc.HateList[9] != 40 || len(npc.HateList) != 1 {
+		t.Errorf("aggro not moved: target=%d hate=%v", npc.AggroTarget, npc.HateList)
+	}
+	found := false
+	for _, other := range s.GetNearbyPlayers(1001, 1000, 4, 0) {
+		if other == p {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("AOI grid does not find the player under the new session")
+	}
+}
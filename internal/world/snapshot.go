@@ -0,0 +1,104 @@
+package world
+
+import (
+	"sort"
+	"time"
+)
+
+// Snapshot is a point-in-time dump of world state for desync/crash diagnosis.
+// Built on the game loop goroutine (the only writer), so it is consistent;
+// it holds only copied values and is safe to marshal on another goroutine.
+type Snapshot struct {
+	TakenAt     time.Time        `json:"taken_at"`
+	PlayerCount int              `json:"player_count"`
+	Truncated   bool             `json:"truncated"` // players beyond the limit were omitted
+	Players     []PlayerSnapshot `json:"players"`
+	NpcsAlive   map[int16]int    `json:"npcs_alive_by_map"`
+	NpcsDead    int              `json:"npcs_dead"`
+	GroundItems map[int16]int    `json:"ground_items_by_map"`
+}
+
+// PlayerSnapshot is the per-player part of a Snapshot.
+type PlayerSnapshot struct {
+	CharID   int32   `json:"char_id"`
+	Name     string  `json:"name"`
+	Account  string  `json:"account"`
+	MapID    int16   `json:"map_id"`
+	X        int32   `json:"x"`
+	Y        int32   `json:"y"`
+	HP       int16   `json:"hp"`
+	MaxHP    int16   `json:"max_hp"`
+	MP       int16   `json:"mp"`
+	MaxMP    int16   `json:"max_mp"`
+	Dead     bool    `json:"dead,omitempty"`
+	LinkDead bool    `json:"linkdead,omitempty"`
+	Buffs    []int32 `json:"buffs"`     // active buff skill IDs, ascending
+	InvSlots int     `json:"inv_slots"` // inventory entries
+	InvTotal int64   `json:"inv_total"` // sum of stack counts
+}
+
+// Snapshot captures online players (at most maxPlayers, ordered by CharID),
+// alive NPC counts per map and ground item counts per map.
+// maxPlayers <= 0 means no limit.
+func (s *State) Snapshot(maxPlayers int) *Snapshot {
+	snap := &Snapshot{
+		TakenAt:     time.Now(),
+		PlayerCount: len(s.bySession),
+		NpcsAlive:   make(map[int16]int),
+		GroundItems: make(map[int16]int),
+	}
+
+	players := make([]*PlayerInfo, 0, len(s.bySession))
+	for _, p := range s.bySession {
+		players = append(players, p)
+	}
+	sort.Slice(players, func(i, j int) bool { return players[i].CharID < players[j].CharID })
+	if maxPlayers > 0 && len(players) > maxPlayers {
+		players = players[:maxPlayers]
+		snap.Truncated = true
+	}
+
+	snap.Players = make([]PlayerSnapshot, 0, len(players))
+	for _, p := range players {
+		ps := PlayerSnapshot{
+			CharID:   p.CharID,
+			Name:     p.Name,
+			MapID:    p.MapID,
+			X:        p.X,
+			Y:        p.Y,
+			HP:       p.HP,
+			MaxHP:    p.MaxHP,
+			MP:       p.MP,
+			MaxMP:    p.MaxMP,
+			Dead:     p.Dead,
+			LinkDead: p.LinkDead,
+			Buffs:    make([]int32, 0, len(p.ActiveBuffs)),
+		}
+		if p.Session != nil {
+			ps.Account = p.Session.AccountName
+		}
+		for id := range p.ActiveBuffs {
+			ps.Buffs = append(ps.Buffs, id)
+		}
+		sort.Slice(ps.Buffs, func(i, j int) bool { return ps.Buffs[i] < ps.Buffs[j] })
+		if p.Inv != nil {
+			ps.InvSlots = len(p.Inv.Items)
+			for _, it := range p.Inv.Items {
+				ps.InvTotal += int64(it.Count)
+			}
+		}
+		snap.Players = append(snap.Players, ps)
+	}
+
+	for _, npc := range s.npcList {
+		if npc.Dead {
+			snap.NpcsDead++
+			continue
+		}
+		snap.NpcsAlive[npc.MapID]++
+	}
+	for _, g := range s.groundItems {
+		snap.GroundItems[g.MapID]++
+	}
+	return snap
+}
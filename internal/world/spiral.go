@@ -0,0 +1,24 @@
+package world
+
+// SpiralSearch returns the tile nearest to (x,y) for which free reports true,
+// checking (x,y) first and then each square ring out to radius. Returns
+// ok = false when no tile within radius qualifies.
+func SpiralSearch(x, y, radius int32, free func(x, y int32) bool) (int32, int32, bool) {
+	if free(x, y) {
+		return x, y, true
+	}
+	for r := int32(1); r <= radius; r++ {
+		for dx := -r; dx <= r; dx++ {
+			for dy := -r; dy <= r; dy++ {
+				// 只檢查本圈外框（內圈已檢查過）
+				if dx != -r && dx != r && dy != -r && dy != r {
+					continue
+				}
+				if free(x+dx, y+dy) {
+					return x + dx, y + dy, true
+				}
+			}
+		}
+	}
+	return x, y, false
+}
@@ -0,0 +1,39 @@
+package world
+
+import "testing"
+
+func TestSpiralSearchPrefersNearestRing(t *testing.T) {
+	blocked := map[[2]int32]bool{{10, 10}: true}
+	free := func(x, y int32) bool { return !blocked[[2]int32{x, y}] }
+
+	if x, y, ok := SpiralSearch(20, 20, 3, free); !ok || x != 20 || y != 20 {
+		t.Errorf("free origin moved to (%d,%d) ok=%v", x, y, ok)
+	}
+
+	x, y, ok := SpiralSearch(10, 10, 3, free)
+	if !ok || max(abs32(x-10), abs32(y-10)) != 1 {
+		t.Errorf("blocked origin landed at (%d,%d) ok=%v, want ring 1", x, y, ok)
+	}
+
+	// Block rings 0-1 entirely: the result must come from ring 2.
+	for dx := int32(-1); dx <= 1; dx++ {
+		for dy := int32(-1); dy <= 1; dy++ {
+			blocked[[2]int32{10 + dx, 10 + dy}] = true
+		}
+	}
+	x, y, ok = SpiralSearch(10, 10, 3, free)
+	if !ok || max(abs32(x-10), abs32(y-10)) != 2 {
+		t.Errorf("landed at (%d,%d) ok=%v, want ring 2", x, y, ok)
+	}
+
+	if x, y, ok := SpiralSearch(10, 10, 3, func(int32, int32) bool { return false }); ok || x != 10 || y != 10 {
+		t.Errorf("no free tile: got (%d,%d) ok=%v, want origin and false", x, y, ok)
+	}
+}
+
+func abs32(v int32) int32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -1,12 +1,22 @@
 package world
 
 import (
-	"math/rand"
+	"sort"
 	"time"
 
 	"github.com/l1jgo/server/internal/net"
 )
 
+// Bonus stat indexes into PlayerInfo.BonusAlloc.
+const (
+	StatStr = iota
+	StatDex
+	StatCon
+	StatWis
+	StatInt
+	StatCha
+)
+
 // PlayerInfo holds in-memory data for a player currently in-world.
 // Accessed only from the game loop goroutine — no locks needed.
 type PlayerInfo struct {
@@ -38,6 +48,7 @@ type PlayerInfo struct {
 	Cha       int16
 	Exp        int32 // cumulative total exp
 	BonusStats  int16 // number of bonus stat points already allocated (level 51+)
+	BonusAlloc  [6]int16 // 配點在各屬性的分配數（索引 StatStr..StatCha）；洗配點時依此退還
 	ElixirStats int16 // 萬能藥使用次數（洗點時用於計算可分配點數）
 	Speed      byte  // 0=normal, 1=fast, etc.
 	MoveSpeed  byte  // 0=normal, 1=hasted (green potion), 2=slowed
@@ -62,13 +73,17 @@ type PlayerInfo struct {
 	Dodge      int16 // dodge bonus
 	Food         int16 // satiety 0-225 (225=full); sent in S_STATUS
 	FoodFullTime int64 // 飽食度達 225 的時刻（Unix 秒）；-1=未滿（Java: _h_time，生存吶喊用）
+	RestedXP     int64 // 休息經驗池：擊殺怪物的額外經驗由此扣除（離線時累積）
 	PKCount       int32 // PK kill count
 	Karma         int32 // 善惡值（Java: L1Karma）— 正=善, 負=惡
 	PinkName      bool  // temporary red name (180 seconds after attacking blue player)
 	PinkNameTicks int   // remaining ticks for pink name timer
 	WantedTicks   int   // >0 = wanted by guards (24h = 432000 ticks at 200ms/tick)
 	FightId           int32 // 0=無決鬥, >0=決鬥對手角色 ID（Java: L1PcInstance.fightId）
+	DuelActive        bool  // 決鬥已被接受並進行中（FightId 在邀請送出時即設定）
 	WarehousePassword int32 // 倉庫密碼（0=未設定, >0=6位數密碼）。從帳號載入。
+	AccountID         int32 // 帳號 ID（accounts.id），帳號共用倉庫的鍵。從帳號載入。
+	PremiumUntil      time.Time // 高級帳號登入 buff 生效期限（零值=未生效）；到期或 .premium off 時收回
 	RegenHPAcc int   // HP regen accumulator: counts 1-second ticks since last HP regen
 
 	// 角色重置（洗點）暫存欄位（Java: tempMaxLevel, tempLevel, tempElixirstats 等）
@@ -78,15 +93,41 @@ type PlayerInfo struct {
 	ResetElixirStats int16 // 萬能藥額外點數
 
 	Dead             bool // true when HP <= 0, waiting for restart
+	LinkDead         bool // 斷線保留中（連線已中斷，角色暫留世界直到 LinkDeadTicks 歸零）
+	LinkDeadTicks    int  // 斷線保留剩餘 ticks
 	Invisible        bool // true when under Invisibility
+	GMInvisible      bool // GM 隱身（.invis）— 偵測術無法揭示；NPC 與非 GM 玩家皆看不到
+	AccessLevel      int16 // GM 權限等級（角色與帳號取較高者；0 = 一般玩家）
 	Paralyzed        bool // true when frozen/stunned/bound
 	Sleeped          bool // true when under sleep effect
 	Silenced         bool // 沉默狀態（沉默毒 / silence 技能）— 禁止施法
 	AbsoluteBarrier  bool // 絕對屏障（skill 78）— 免疫所有傷害，攻擊/施法/使用道具時解除
+	Resting          bool // 坐下休息（.sit）— 回復加成，移動/攻擊時自動起身
+	AttackBoostItem  int32 // 已開啟的強化攻擊道具 ItemID（0 = 關閉）；每次攻擊消耗 1 個
 	AttackView       bool // 浮動傷害數字開關（Java: is_attack_view，預設 true，聊天輸入 dmg 切換）
+	RefuseWhisper    bool // 拒絕密語（社交拒絕選項，持久化）
+	RefuseParty      bool // 拒絕組隊邀請
+	RefuseTrade      bool // 拒絕交易請求
+	RefuseDuel       bool // 拒絕決鬥請求
 
 	LastMoveTime int64 // time.Now().UnixNano() of last accepted move (0 = no throttle)
 
+	ChatLastSent [4]int64 // 各聊天頻道最後發言時間（UnixNano）：0=全體/大喊/交易 1=密語 2=隊伍 3=血盟
+
+	// 移動違規計數（超出地圖、不可通行、移動過快）— 用於記錄疑似外掛
+	MoveViolations    int
+	MoveViolationTime int64 // UnixNano of the first violation in the current 10s window
+
+	// 攻擊距離違規計數（目標超出武器射程）— 用於記錄疑似距離外掛
+	AttackViolations    int
+	AttackViolationTime int64 // UnixNano of the first violation in the current 10s window
+
+	// 移動速率量測（加速外掛偵測）
+	SpeedWindowStart  int64 // UnixNano of the current measurement window start
+	SpeedWindowMoves  int   // accepted moves in the current window
+	SpeedStrikes      int   // consecutive flagged windows
+	SpeedPenaltyUntil int64 // UnixNano until which moves are dropped ("slow" action)
+
 	TempCharGfx int32 // 0=use ClassID; >0=current polymorph GFX sprite
 	PolyID      int32 // current polymorph poly_id (for equip/skill checks; 0=not polymorphed)
 	ActiveSetID int   // armor set ID currently active (0=none); cleared when set is incomplete
@@ -95,6 +136,10 @@ type PlayerInfo struct {
 	// Set by executeSummonMonster when ring equipped; cleared by HandleNpcAction on numeric response.
 	SummonSelectionMode bool
 
+	// ActivePets counts the player's living pets + summons in-world.
+	// Maintained by State.AddPet/RemovePet/PetDied/AddSummon/RemoveSummon; capped by MaxPets(CHA).
+	ActivePets int
+
 	Inv          *Inventory // in-memory inventory
 	Equip        Equipment  // equipped items (value type, zero-initialized = all slots empty)
 	EquipBonuses EquipStats // cached equipment stat contributions (for diff on equip/unequip)
@@ -118,8 +163,12 @@ type PlayerInfo struct {
 	// Global cast cooldown: cannot cast any spell before this time (Java: isSkillDelay)
 	SkillDelayUntil time.Time
 
+	// Portal cooldown: portals are ignored before this time ([world] portal_cooldown_ms)
+	PortalReadyAt time.Time
+
 	// Active buffs: skillID → remaining ticks. Decremented each tick; removed at 0.
 	ActiveBuffs map[int32]*ActiveBuff
+	buffSeq     uint64 // last ActiveBuff.Seq handed out
 
 	// Warehouse: temporary cache while warehouse UI is open
 	WarehouseItems []*WarehouseCache // loaded from DB on open, nil when closed
@@ -136,6 +185,10 @@ type PlayerInfo struct {
 	TradeItems      []*InvItem // items offered in trade
 	TradeGold       int32      // gold offered in trade
 
+	// Mail parcel: attachments picked at a post NPC, sent with the next mail
+	ParcelNpcObjID int32         // post NPC whose attachment picker is open (0 = none)
+	ParcelItems    []ParcelEntry // staged attachments (inventory objID + count)
+
 	// --- 中毒系統（Java L1Poison）---
 	// PoisonType: 0=無, 1=傷害毒, 2=沉默毒, 3=麻痺毒延遲中, 4=麻痺毒已麻痺
 	PoisonType      byte
@@ -184,6 +237,9 @@ type PlayerInfo struct {
 	// key=DelayID (如 502=道具共用), value=到期時間
 	ItemDelays map[int]time.Time
 
+	// 掉落物過濾（.filter 指令）：列出的物品擊殺掉落時直接捨棄（持久化於 characters.loot_filter）
+	LootFilter map[int32]bool
+
 	// 限時地圖定時器（Java: MapTimerThread / character_maps_time）
 	MapTimeUsed       map[int]int // key=組別 OrderID, value=已使用秒數
 	MapTimerGroupIdx  int         // 當前所在限時地圖組 OrderID（-1 或 0 = 不在限時地圖）
@@ -196,6 +252,13 @@ type PlayerInfo struct {
 	Dirty bool
 }
 
+// ParcelEntry is one inventory item staged as a mail parcel attachment.
+// Items stay in the inventory until the mail is actually sent.
+type ParcelEntry struct {
+	ObjectID int32
+	Count    int32
+}
+
 // BuddyEntry represents a single buddy in the player's friend list.
 type BuddyEntry struct {
 	CharID int32
@@ -205,7 +268,7 @@ type BuddyEntry struct {
 // WarehouseCache maps a temporary objectID to a DB warehouse item.
 type WarehouseCache struct {
 	TempObjID  int32
-	DbID       int32 // warehouse_items.id in DB
+	DbID       int32 // warehouse_items.id (account_warehouse.id for the personal warehouse)
 	ItemID     int32
 	Count      int32
 	EnchantLvl int16
@@ -252,6 +315,13 @@ type ActiveBuff struct {
 	SetParalyzed        bool // buff paralyzed/froze player
 	SetSleeped          bool // buff put player to sleep
 	SetAbsoluteBarrier  bool // buff 設定了絕對屏障（到期/移除時清 flag）
+
+	Seq uint64 // application order, assigned by AddBuff (buff cap evicts the lowest)
+}
+
+// IsGM returns true if the player has any GM access level.
+func (p *PlayerInfo) IsGM() bool {
+	return p.AccessLevel > 0
 }
 
 // HasBuff returns true if the player has the given skill effect active.
@@ -277,6 +347,8 @@ func (p *PlayerInfo) AddBuff(buff *ActiveBuff) *ActiveBuff {
 		p.ActiveBuffs = make(map[int32]*ActiveBuff)
 	}
 	old := p.ActiveBuffs[buff.SkillID]
+	p.buffSeq++
+	buff.Seq = p.buffSeq
 	p.ActiveBuffs[buff.SkillID] = buff
 	return old
 }
@@ -436,19 +508,32 @@ type State struct {
 	dolls     map[int32]*DollInfo     // doll object ID → DollInfo
 	followers map[int32]*FollowerInfo // follower object ID → FollowerInfo
 
-	groundItems map[int32]*GroundItem // ground item object ID → GroundItem
+	groundItems map[int32]*GroundItem         // ground item object ID → GroundItem
+	groundTiles map[groundTile][]*GroundItem // tile → ground items on it (stack lookups)
+
+	mapPlayers map[int16]map[int32]*PlayerInfo // map ID → char ID → player (instance occupancy)
+
+	viewRange int32 // Chebyshev visibility radius for all GetNearby* queries
 
 	Parties     *PartyManager
 	ChatParties *ChatPartyManager
 	Clans       *ClanManager
+	Logins      *LoginGate // 登入人數與排隊（max_online）
 
 	// Weather & game time (accessed from game loop only)
-	Weather  byte // current weather type (0=clear, 1-3=snow, 17-19=rain)
-	LastHour int  // last game hour for hour-change detection (-1 = uninitialized)
+	Weather    byte                 // current weather type (0=clear, 1-3=snow, 17-19=rain)
+	LastHour   int                  // last game hour for hour-change detection (-1 = uninitialized)
+	mapWeather map[int16]MapWeather // per-map weather rolled from weather.yaml (overrides Weather)
+
+	killedBosses map[int32]bool // boss template IDs with at least one recorded kill (first-blood detection)
 
 	// 可重用 AOI 查詢 buffer（遊戲迴圈單線程，無需鎖）
 	aoiBuf    []uint64
 	npcAoiBuf []int32
+
+	// ActiveNpcs 去重世代與查詢 buffer
+	activeGen uint32
+	activeBuf []int32
 }
 
 // RandomizeWeather picks a random weather with weighted distribution.
@@ -456,17 +541,61 @@ type State struct {
 // some variety but keep clear weather dominant (~60%) to avoid constant rain/snow.
 // Valid values: 0=clear, 1-3=snow, 17-19=rain (Java confirms 17-19, not 16).
 func (s *State) RandomizeWeather() {
-	roll := rand.Intn(10) // 0-9
+	roll := RandInt(10) // 0-9
 	switch {
 	case roll < 6: // 60% clear
 		s.Weather = 0
 	case roll < 8: // 20% snow (light)
-		s.Weather = byte(1 + rand.Intn(3)) // 1, 2, or 3
+		s.Weather = byte(1 + RandInt(3)) // 1, 2, or 3
 	default: // 20% rain (light)
-		s.Weather = byte(17 + rand.Intn(3)) // 17, 18, or 19
+		s.Weather = byte(17 + RandInt(3)) // 17, 18, or 19
 	}
 }
 
+// MapWeather is the weather of one map.
+type MapWeather struct {
+	Kind string // "clear", "snow", "rain", "fog" (data.Weather*)
+	Code byte   // S_WEATHER value sent to clients
+}
+
+// SetMapWeather sets a map's weather kind and picks a matching client code.
+// Fog has no client weather effect and is sent as clear.
+// Returns true when the kind changed.
+func (s *State) SetMapWeather(mapID int16, kind string) bool {
+	if s.mapWeather == nil {
+		s.mapWeather = make(map[int16]MapWeather)
+	}
+	var code byte
+	switch kind {
+	case "snow":
+		code = byte(1 + RandInt(3))
+	case "rain":
+		code = byte(17 + RandInt(3))
+	}
+	prev, had := s.mapWeather[mapID]
+	s.mapWeather[mapID] = MapWeather{Kind: kind, Code: code}
+	return !had || prev.Kind != kind
+}
+
+// WeatherAt returns the weather of mapID, falling back to the global Weather.
+func (s *State) WeatherAt(mapID int16) MapWeather {
+	if w, ok := s.mapWeather[mapID]; ok {
+		return w
+	}
+	switch {
+	case s.Weather >= 1 && s.Weather <= 3:
+		return MapWeather{Kind: "snow", Code: s.Weather}
+	case s.Weather >= 17 && s.Weather <= 19:
+		return MapWeather{Kind: "rain", Code: s.Weather}
+	}
+	return MapWeather{Kind: "clear", Code: s.Weather}
+}
+
+// ClearMapWeather drops all per-map weather so every map follows Weather.
+func (s *State) ClearMapWeather() {
+	clear(s.mapWeather)
+}
+
 func NewState() *State {
 	return &State{
 		bySession:   make(map[uint64]*PlayerInfo),
@@ -478,6 +607,7 @@ func NewState() *State {
 		Parties:     NewPartyManager(),
 		ChatParties: NewChatPartyManager(),
 		Clans:       NewClanManager(),
+		Logins:      NewLoginGate(),
 		npcs:        make(map[int32]*NpcInfo),
 		doors:       make(map[int32]*DoorInfo),
 		pets:        make(map[int32]*PetInfo),
@@ -485,10 +615,34 @@ func NewState() *State {
 		dolls:       make(map[int32]*DollInfo),
 		followers:   make(map[int32]*FollowerInfo),
 		groundItems: make(map[int32]*GroundItem),
+		groundTiles: make(map[groundTile][]*GroundItem),
+		mapPlayers:  make(map[int16]map[int32]*PlayerInfo),
+		killedBosses: make(map[int32]bool),
+		viewRange:   DefaultViewRange,
 		LastHour:    -1,
 	}
 }
 
+// DefaultViewRange is the visibility radius used when none is configured
+// (Java PC_RECOGNIZE_RANGE).
+const DefaultViewRange int32 = 20
+
+// SetViewRange changes the visibility radius for every GetNearby* query and
+// widens the AOI cell scan to match. Values <= 0 restore the default.
+func (s *State) SetViewRange(r int32) {
+	if r <= 0 {
+		r = DefaultViewRange
+	}
+	s.viewRange = r
+	s.aoi.SetRange(r)
+	s.npcAoi.SetRange(r)
+}
+
+// ViewRange returns the current visibility radius.
+func (s *State) ViewRange() int32 {
+	return s.viewRange
+}
+
 // AddPlayer registers a player in the world.
 func (s *State) AddPlayer(p *PlayerInfo) {
 	s.bySession[p.SessionID] = p
@@ -496,6 +650,7 @@ func (s *State) AddPlayer(p *PlayerInfo) {
 	s.byName[p.Name] = p
 	s.aoi.Add(p.SessionID, p.X, p.Y, p.MapID)
 	s.entity.Occupy(p.MapID, p.X, p.Y, p.CharID)
+	s.enterMap(p)
 }
 
 // RemovePlayer removes a player from the world.
@@ -506,12 +661,44 @@ func (s *State) RemovePlayer(sessionID uint64) *PlayerInfo {
 	}
 	s.aoi.Remove(sessionID, p.X, p.Y, p.MapID)
 	s.entity.Vacate(p.MapID, p.X, p.Y, p.CharID)
+	s.leaveMap(p)
 	delete(s.bySession, sessionID)
 	delete(s.byCharID, p.CharID)
 	delete(s.byName, p.Name)
 	return p
 }
 
+// RebindSession moves an in-world player (held linkdead) onto a new
+// connection. The session index, AOI entry and NPC aggro/hate keyed by the
+// old session ID all follow the player.
+func (s *State) RebindSession(p *PlayerInfo, sess *net.Session) {
+	oldID := p.SessionID
+	s.aoi.Remove(oldID, p.X, p.Y, p.MapID)
+	delete(s.bySession, oldID)
+
+	p.SessionID = sess.ID
+	p.Session = sess
+	s.bySession[sess.ID] = p
+	s.aoi.Add(sess.ID, p.X, p.Y, p.MapID)
+
+	for _, npc := range s.npcList {
+		if npc.AggroTarget == oldID {
+			npc.AggroTarget = sess.ID
+		}
+		if hate, ok := npc.HateList[oldID]; ok {
+			delete(npc.HateList, oldID)
+			npc.HateList[sess.ID] = hate
+		}
+	}
+}
+
+// RenamePlayer changes an online player's name and updates the name index.
+func (s *State) RenamePlayer(p *PlayerInfo, newName string) {
+	delete(s.byName, p.Name)
+	p.Name = newName
+	s.byName[newName] = p
+}
+
 // GetBySession returns a player by session ID.
 func (s *State) GetBySession(sessionID uint64) *PlayerInfo {
 	return s.bySession[sessionID]
@@ -534,16 +721,54 @@ func (s *State) UpdatePosition(sessionID uint64, newX, newY int32, newMapID int1
 		return
 	}
 	oldX, oldY, oldMap := p.X, p.Y, p.MapID
+	if oldMap != newMapID {
+		s.leaveMap(p)
+	}
 	p.X = newX
 	p.Y = newY
 	p.MapID = newMapID
 	p.Heading = heading
 	s.aoi.Move(sessionID, oldX, oldY, oldMap, newX, newY, newMapID)
 	s.entity.Move(oldMap, oldX, oldY, newX, newY, p.CharID)
+	if oldMap != newMapID {
+		s.enterMap(p)
+	}
+}
+
+// MapPlayers returns the players currently on mapID.
+func (s *State) MapPlayers(mapID int16) []*PlayerInfo {
+	players := make([]*PlayerInfo, 0, len(s.mapPlayers[mapID]))
+	for _, p := range s.mapPlayers[mapID] {
+		players = append(players, p)
+	}
+	return players
+}
+
+// MapPlayerCount returns the number of players currently on mapID.
+func (s *State) MapPlayerCount(mapID int16) int {
+	return len(s.mapPlayers[mapID])
+}
+
+func (s *State) enterMap(p *PlayerInfo) {
+	m := s.mapPlayers[p.MapID]
+	if m == nil {
+		m = make(map[int32]*PlayerInfo)
+		s.mapPlayers[p.MapID] = m
+	}
+	m[p.CharID] = p
+}
+
+func (s *State) leaveMap(p *PlayerInfo) {
+	if m := s.mapPlayers[p.MapID]; m != nil {
+		delete(m, p.CharID)
+		if len(m) == 0 {
+			delete(s.mapPlayers, p.MapID)
+		}
+	}
 }
 
 // GetNearbyPlayers returns all players visible to the given position.
-// Uses Chebyshev distance <= 20 (matching Java PC_RECOGNIZE_RANGE).
+// Uses Chebyshev distance <= view range (default 20, matching Java PC_RECOGNIZE_RANGE).
 func (s *State) GetNearbyPlayers(x, y int32, mapID int16, excludeSession uint64) []*PlayerInfo {
 	s.aoiBuf = s.aoi.GetNearbyInto(x, y, mapID, s.aoiBuf)
 	nearbyIDs := s.aoiBuf
@@ -569,13 +794,19 @@ func (s *State) GetNearbyPlayers(x, y int32, mapID int16, excludeSession uint64)
 		if dy > dist {
 			dist = dy
 		}
-		if dist <= 20 {
+		if dist <= s.viewRange {
 			result = append(result, p)
 		}
 	}
 	return result
 }
 
+// OnlineCount returns the number of logged-in accounts, including those still
+// at the character list. This is the count max_online is enforced against.
+func (s *State) OnlineCount() int {
+	return s.Logins.OnlineCount()
+}
+
 // PlayerCount returns the number of players in-world.
 func (s *State) PlayerCount() int {
 	return len(s.bySession)
@@ -603,7 +834,7 @@ func (s *State) GetNpc(id int32) *NpcInfo {
 	return s.npcs[id]
 }
 
-// GetNearbyNpcs returns all alive NPCs visible from the given position (Chebyshev <= 20).
+// GetNearbyNpcs returns all alive NPCs visible from the given position (Chebyshev <= view range).
 // Uses NPC AOI grid for O(cells) lookup instead of O(N) full scan.
 func (s *State) GetNearbyNpcs(x, y int32, mapID int16) []*NpcInfo {
 	s.npcAoiBuf = s.npcAoi.GetNearbyInto(x, y, mapID, s.npcAoiBuf)
@@ -626,7 +857,7 @@ func (s *State) GetNearbyNpcs(x, y int32, mapID int16) []*NpcInfo {
 		if dy > dist {
 			dist = dy
 		}
-		if dist <= 20 {
+		if dist <= s.viewRange {
 			result = append(result, npc)
 		}
 	}
@@ -660,7 +891,7 @@ func (s *State) GetNearbyNpcsForVis(x, y int32, mapID int16) []*NpcInfo {
 		if dy > dist {
 			dist = dy
 		}
-		if dist <= 20 {
+		if dist <= s.viewRange {
 			result = append(result, npc)
 		}
 	}
@@ -729,6 +960,64 @@ func (s *State) NpcList() []*NpcInfo {
 	return s.npcList
 }
 
+// ActiveNpcs returns the alive NPCs whose AI must run this tick, sorted by
+// object ID (stable AI order for seeded RNG): every NPC within view range of
+// at least one in-world player, plus NPCs from the previous result that are
+// still busy (chasing a target, debuffed or poisoned) after the players left.
+// Only idle NPCs far from every player are parked.
+// The set is rebuilt from the NPC AOI grid around each player, so its cost
+// scales with players × nearby NPCs rather than the total NPC count.
+// buf must be the previous result (or nil); it is reused for the new one.
+func (s *State) ActiveNpcs(buf []*NpcInfo) []*NpcInfo {
+	s.activeGen++
+	gen := s.activeGen
+
+	// An NPC only becomes busy while a player is near, so carrying busy NPCs
+	// over from the last tick keeps them running until their state clears.
+	kept := buf[:0]
+	for _, npc := range buf {
+		if npc.Dead || !npc.busy() || s.npcs[npc.ID] != npc {
+			continue
+		}
+		npc.activeGen = gen
+		kept = append(kept, npc)
+	}
+	buf = kept
+
+	for _, p := range s.bySession {
+		s.activeBuf = s.npcAoi.GetNearbyInto(p.X, p.Y, p.MapID, s.activeBuf)
+		for _, nid := range s.activeBuf {
+			npc := s.npcs[nid]
+			if npc == nil || npc.Dead || npc.activeGen == gen || npc.MapID != p.MapID {
+				continue
+			}
+			if chebyshev(npc.X, npc.Y, p.X, p.Y) > s.viewRange {
+				continue
+			}
+			npc.activeGen = gen
+			buf = append(buf, npc)
+		}
+	}
+	sort.Slice(buf, func(i, j int) bool { return buf[i].ID < buf[j].ID })
+	return buf
+}
+
+// chebyshev returns the Chebyshev (king-move) distance between two tiles.
+func chebyshev(x1, y1, x2, y2 int32) int32 {
+	dx := x1 - x2
+	dy := y1 - y2
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	if dy > dx {
+		return dy
+	}
+	return dx
+}
+
 // NpcCount returns total NPC count.
 func (s *State) NpcCount() int {
 	return len(s.npcs)
@@ -803,7 +1092,7 @@ func (s *State) GetDoor(id int32) *DoorInfo {
 	return s.doors[id]
 }
 
-// GetNearbyDoors returns all doors visible from the given position (Chebyshev <= 20).
+// GetNearbyDoors returns all doors visible from the given position (Chebyshev <= view range).
 func (s *State) GetNearbyDoors(x, y int32, mapID int16) []*DoorInfo {
 	var result []*DoorInfo
 	for _, door := range s.doors {
@@ -822,7 +1111,7 @@ func (s *State) GetNearbyDoors(x, y int32, mapID int16) []*DoorInfo {
 		if dy > dist {
 			dist = dy
 		}
-		if dist <= 20 {
+		if dist <= s.viewRange {
 			result = append(result, door)
 		}
 	}
@@ -859,7 +1148,7 @@ func (s *State) DoorCount() int {
 	return len(s.doors)
 }
 
-// GetNearbyPets returns all alive pets visible from the given position (Chebyshev <= 20).
+// GetNearbyPets returns all alive pets visible from the given position (Chebyshev <= view range).
 func (s *State) GetNearbyPets(x, y int32, mapID int16) []*PetInfo {
 	s.npcAoiBuf = s.npcAoi.GetNearbyInto(x, y, mapID, s.npcAoiBuf)
 	nearbyIDs := s.npcAoiBuf
@@ -881,7 +1170,7 @@ func (s *State) GetNearbyPets(x, y int32, mapID int16) []*PetInfo {
 		if dy > dist {
 			dist = dy
 		}
-		if dist <= 20 {
+		if dist <= s.viewRange {
 			result = append(result, pet)
 		}
 	}
@@ -890,9 +1179,22 @@ func (s *State) GetNearbyPets(x, y int32, mapID int16) []*PetInfo {
 
 // --- Ground item methods ---
 
+// groundTile identifies one map tile in the ground item index.
+type groundTile struct {
+	mapID int16
+	x, y  int32
+}
+
+func tileOf(item *GroundItem) groundTile {
+	return groundTile{mapID: item.MapID, x: item.X, y: item.Y}
+}
+
 // AddGroundItem registers a ground item in the world.
+// Ground items never move, so the tile index is keyed by the initial position.
 func (s *State) AddGroundItem(item *GroundItem) {
 	s.groundItems[item.ID] = item
+	key := tileOf(item)
+	s.groundTiles[key] = append(s.groundTiles[key], item)
 }
 
 // RemoveGroundItem removes a ground item from the world.
@@ -902,15 +1204,70 @@ func (s *State) RemoveGroundItem(id int32) *GroundItem {
 		return nil
 	}
 	delete(s.groundItems, id)
+	s.unindexGroundItem(item)
 	return item
 }
 
+func (s *State) unindexGroundItem(item *GroundItem) {
+	key := tileOf(item)
+	list := s.groundTiles[key]
+	for i, g := range list {
+		if g == item {
+			list = append(list[:i], list[i+1:]...)
+			break
+		}
+	}
+	if len(list) == 0 {
+		delete(s.groundTiles, key)
+	} else {
+		s.groundTiles[key] = list
+	}
+}
+
+// FindGroundStack returns a pile on item's tile that item can be merged into:
+// same template, enchant level and ownership (owner, loot flag, whether the
+// owner window is still running and the tagged party), not a corpse, and with
+// room for item.Count below MaxStackCount. Returns nil if there is none.
+// Matching the party keeps a merge from re-locking a released pile or
+// extending the window for a stale member list.
+func (s *State) FindGroundStack(item *GroundItem) *GroundItem {
+	for _, g := range s.groundTiles[tileOf(item)] {
+		if g.ItemID == item.ItemID && g.EnchantLvl == item.EnchantLvl &&
+			g.OwnerID == item.OwnerID && g.Loot == item.Loot && !g.IsCorpse() &&
+			(g.OwnerTicks > 0) == (item.OwnerTicks > 0) && sameParty(g.OwnerParty, item.OwnerParty) &&
+			int64(g.Count)+int64(item.Count) <= MaxStackCount {
+			return g
+		}
+	}
+	return nil
+}
+
+// sameParty reports whether two loot party snapshots hold the same members.
+func sameParty(a, b []int32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, id := range a {
+		found := false
+		for _, other := range b {
+			if other == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 // GetGroundItem returns a ground item by its object ID.
 func (s *State) GetGroundItem(id int32) *GroundItem {
 	return s.groundItems[id]
 }
 
-// GetNearbyGroundItems returns all ground items visible from the given position (Chebyshev <= 20).
+// GetNearbyGroundItems returns all ground items visible from the given position (Chebyshev <= view range).
 func (s *State) GetNearbyGroundItems(x, y int32, mapID int16) []*GroundItem {
 	var result []*GroundItem
 	for _, item := range s.groundItems {
@@ -929,24 +1286,32 @@ func (s *State) GetNearbyGroundItems(x, y int32, mapID int16) []*GroundItem {
 		if dy > dist {
 			dist = dy
 		}
-		if dist <= 20 {
+		if dist <= s.viewRange {
 			result = append(result, item)
 		}
 	}
 	return result
 }
 
-// TickGroundItems decrements TTL on ground items and returns expired ones.
-func (s *State) TickGroundItems() []*GroundItem {
-	var expired []*GroundItem
+// TickGroundItems decrements TTL and owner-priority timers on ground items.
+// It returns the expired items and the owned items whose priority window
+// ended this tick (now free-for-all).
+func (s *State) TickGroundItems() (expired, released []*GroundItem) {
 	for id, item := range s.groundItems {
+		if item.OwnerTicks > 0 {
+			item.OwnerTicks--
+			if item.OwnerTicks == 0 && item.OwnerID != 0 {
+				released = append(released, item)
+			}
+		}
 		if item.TTL > 0 {
 			item.TTL--
 			if item.TTL <= 0 {
 				expired = append(expired, item)
 				delete(s.groundItems, id)
+				s.unindexGroundItem(item)
 			}
 		}
 	}
-	return expired
+	return expired, released
 }
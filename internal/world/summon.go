@@ -70,6 +70,9 @@ func (s *State) AddSummon(sum *SummonInfo) {
 	s.summons[sum.ID] = sum
 	s.npcAoi.Add(sum.ID, sum.X, sum.Y, sum.MapID)
 	s.entity.Occupy(sum.MapID, sum.X, sum.Y, sum.ID)
+	if !sum.Dead {
+		s.adjustActivePets(sum.OwnerCharID, 1)
+	}
 }
 
 // RemoveSummon removes a summon from the world and frees its tile.
@@ -84,6 +87,9 @@ func (s *State) RemoveSummon(summonID int32) *SummonInfo {
 	s.npcAoi.Remove(sum.ID, sum.X, sum.Y, sum.MapID)
 	s.entity.Vacate(sum.MapID, sum.X, sum.Y, sum.ID)
 	delete(s.summons, summonID)
+	if !sum.Dead {
+		s.adjustActivePets(sum.OwnerCharID, -1)
+	}
 	return sum
 }
 
@@ -150,7 +156,7 @@ func (s *State) SummonCount() int {
 	return len(s.summons)
 }
 
-// GetNearbySummons returns all alive summons visible from the given position (Chebyshev <= 20).
+// GetNearbySummons returns all alive summons visible from the given position (Chebyshev <= view range).
 func (s *State) GetNearbySummons(x, y int32, mapID int16) []*SummonInfo {
 	nearbyIDs := s.npcAoi.GetNearby(x, y, mapID)
 	var result []*SummonInfo
@@ -171,7 +177,7 @@ func (s *State) GetNearbySummons(x, y int32, mapID int16) []*SummonInfo {
 		if dy > dist {
 			dist = dy
 		}
-		if dist <= 20 {
+		if dist <= s.viewRange {
 			result = append(result, sum)
 		}
 	}
@@ -7,9 +7,23 @@
 --   "ranged_attack"  - ranged attack current target
 --   "skill"          - use skill {skill_id, act_id} on target
 --   "move_toward"    - move 1 tile toward target
+--   "flee"           - move 1 tile away from target
 --   "wander"         - move 1 tile in direction {dir} (-1 = continue current)
 --   "lose_aggro"     - clear aggro target
 --   "idle"           - do nothing
+--
+-- Read-only world queries (ctx.id = NPC object ID, range clamped to 0..20):
+--   world.countNearbyAllies(ctx.id, range)  - other live NPCs of the same template
+--   world.countNearbyPlayers(ctx.id, range) - live, visible players
+
+-- Fleeing when outnumbered. Disabled by default (flee_hp_pct = 0).
+-- A wounded mob runs from its target when players in flee_range outnumber
+-- it and its allies by at least flee_outnumber.
+AI_FLEE = {
+    flee_hp_pct     = 0,  -- flee at or below this HP% (0 = never)
+    flee_range      = 8,
+    flee_outnumber  = 2,
+}
 
 function npc_ai(ctx)
     -- Has aggro target
@@ -41,6 +55,10 @@ function ai_with_target(ctx)
 
     local in_range = ctx.target_dist <= atk_range
 
+    if ctx.can_move and should_flee(ctx) then
+        return {{ type = "flee" }}
+    end
+
     -- In attack range: fight or wait for cooldown (NEVER move)
     if in_range then
         if ctx.can_attack then
@@ -76,6 +94,20 @@ function ai_with_target(ctx)
     return {{ type = "idle" }}
 end
 
+-- Returns true when the NPC is wounded and outnumbered (see AI_FLEE).
+function should_flee(ctx)
+    local cfg = AI_FLEE
+    if cfg.flee_hp_pct <= 0 or ctx.max_hp <= 0 or world == nil then
+        return false
+    end
+    if ctx.hp * 100 > ctx.max_hp * cfg.flee_hp_pct then
+        return false
+    end
+    local players = world.countNearbyPlayers(ctx.id, cfg.flee_range)
+    local allies = world.countNearbyAllies(ctx.id, cfg.flee_range)
+    return players - (allies + 1) >= cfg.flee_outnumber
+end
+
 -- Try to use a mob skill. Returns a command table or nil.
 function try_use_skill(ctx)
     local skills = ctx.skills
@@ -125,6 +125,8 @@ function calc_physical_skill(ctx)
 
     local damage = 0
     local hit_count = 1
+    -- Triple Arrow always fires 3 arrows; the server rolls each extra arrow separately
+    if sid == 132 then hit_count = 3 end
 
     if is_hit then
         local base = math.random(1, weapon_dmg)
@@ -134,8 +136,6 @@ function calc_physical_skill(ctx)
         -- Skill-specific bonuses
         if sid == 108 then      -- Critical Strike: guaranteed extra damage
             damage = damage + level + math.floor(str / 3)
-        elseif sid == 132 then  -- Triple Arrow: 3 separate hits
-            hit_count = 3
         elseif sid == 187 then  -- Slaughterer: heavy melee
             damage = damage + math.floor(level / 2)
         elseif sid == 203 then  -- Smash/Rampage
@@ -1,5 +1,11 @@
 -- Melee combat formula for PC attacking NPC
 -- Receives a context table, returns {is_hit, damage}
+--
+-- ctx.attacker = {level, str, dex, weapon_dmg, hit_mod, dmg_mod, bow_hit_mod, bow_dmg_mod, enchant}
+-- ctx.target = {ac, level, mr, dodge, class_type}
+--   enchant    = weapon enchant minus wear (not added to damage by default)
+--   dodge      = target dodge bonus (0 for NPCs)
+--   class_type = -1 for NPCs, 0-7 for players
 
 function calc_melee_attack(ctx)
     local atk = ctx.attacker
@@ -64,8 +70,8 @@ end
 -- Ranged (bow) combat formula for PC attacking NPC
 -- Java: L1Attack — bow uses DEX for both hit and damage
 --
--- ctx.attacker = {level, str, dex, bow_dmg, arrow_dmg, bow_hit_mod, bow_dmg_mod}
--- ctx.target = {ac, level, mr}
+-- ctx.attacker = {level, str, dex, bow_dmg, arrow_dmg, bow_hit_mod, bow_dmg_mod, enchant}
+-- ctx.target = {ac, level, mr, dodge, class_type}
 ---------------------------------------------------------------------
 function calc_ranged_attack(ctx)
     local atk = ctx.attacker
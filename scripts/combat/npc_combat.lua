@@ -3,7 +3,7 @@
 
 -- NPC melee attack against player
 -- ctx.attacker = {level, str, dex, weapon_dmg, hit_mod, dmg_mod}
--- ctx.target = {ac, level, mr}
+-- ctx.target = {ac, level, mr, dodge, class_type}
 function calc_npc_melee(ctx)
     local atk = ctx.attacker
     local tgt = ctx.target
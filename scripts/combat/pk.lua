@@ -59,12 +59,13 @@ function calc_pk_lawful_penalty(ctx)
 end
 
 -- calc_pk_item_drop(ctx) -> {should_drop, count}
--- ctx = {victim_lawful}
+-- ctx = {victim_lawful, rate_pct}
 --
 -- Java formula:
 --   lostRate = ((lawful + 32768) / 1000 - 65) * 4
 --   If lostRate >= 0, no drop.
 --   Negate lostRate, then double for red-named (lawful < 0).
+--   Scale by rate_pct (server config, 100 = unchanged).
 --   Roll 1-1000: if roll > lostRate, no drop.
 --   Drop count based on lawful brackets:
 --     <= -30000: 1-4 items
@@ -91,6 +92,10 @@ function calc_pk_item_drop(ctx)
         lost_rate = lost_rate * 2
     end
 
+    -- Server-configured multiplier (death_drop_rate_pct / pink name)
+    local rate_pct = ctx.rate_pct or 100
+    lost_rate = math.floor(lost_rate * rate_pct / 100)
+
     -- Roll the dice (1-1000)
     local roll = math.random(1, 1000)
     if roll > lost_rate then
@@ -5,12 +5,18 @@
 -- ctx.category:     1=weapon, 2=armor
 -- ctx.weapon_chance: config rate (0.0-1.0, default 0.68 = Java 68)
 -- ctx.armor_chance:  config rate (0.0-1.0, default 0.52 = Java 52)
+-- ctx.max_enchant:   enchant cap (level never rises past it)
 --
 -- Returns: { result = "success"/"nochange"/"break"/"minus", amount = N }
---   success:  +amount enchant levels
+--   success:  +amount enchant levels (clamped to the cap)
 --   nochange: intense light but nothing happens
---   break:    equipment destroyed (normal scroll +9+ / cursed scroll <= -7)
+--   break:    equipment destroyed (normal scroll above safe / cursed scroll <= -7)
 --   minus:    -amount enchant levels (cursed scroll)
+--
+-- Scroll classes:
+--   normal:  above safe enchant a failed roll breaks the item
+--   blessed: never breaks; a failed roll (or any use at the cap) is "nochange"
+--   cursed:  always the minus path, regardless of safe enchant or cap
 
 -- Java RandomELevel: blessed scrolls can give +1/+2/+3 at low enchant levels.
 -- At +0~2: 31%(+1), 44%(+2), 24%(+3)
@@ -33,6 +39,23 @@ local function random_e_level(enchant_lvl, scroll_bless)
     return 1
 end
 
+-- Success amount, clamped so the level never passes the cap.
+local function success_amount(ctx, max_enchant)
+    local amount = random_e_level(ctx.enchant_lvl, ctx.scroll_bless)
+    if ctx.enchant_lvl + amount > max_enchant then
+        amount = max_enchant - ctx.enchant_lvl
+    end
+    return amount
+end
+
+-- Failed roll: blessed scrolls never destroy the item.
+local function failure(ctx)
+    if ctx.scroll_bless == 1 then
+        return { result = "nochange", amount = 0 }
+    end
+    return { result = "break", amount = 0 }
+end
+
 function calc_enchant(ctx)
     -- Cursed scroll (bless == 2): always -1, break at <= -7
     if ctx.scroll_bless == 2 then
@@ -42,10 +65,20 @@ function calc_enchant(ctx)
         return { result = "minus", amount = 1 }
     end
 
+    local max_enchant = ctx.max_enchant or 127
+    if max_enchant <= 0 then max_enchant = 127 end
+
+    -- At the cap: the level cannot rise. Blessed scrolls do nothing;
+    -- normal scrolls above safe enchant still risk breaking the item.
+    if ctx.enchant_lvl >= max_enchant then
+        if ctx.scroll_bless == 1 or ctx.enchant_lvl < ctx.safe_enchant then
+            return { result = "nochange", amount = 0 }
+        end
+    end
+
     -- Below safe enchant: always succeed
     if ctx.enchant_lvl < ctx.safe_enchant then
-        local amount = random_e_level(ctx.enchant_lvl, ctx.scroll_bless)
-        return { result = "success", amount = amount }
+        return { result = "success", amount = success_amount(ctx, max_enchant) }
     end
 
     -- At or above safe enchant: level-dependent formula
@@ -85,14 +118,16 @@ function calc_enchant(ctx)
 
     -- Three-outcome system at +9+ (Java: success / nochange / break)
     if rnd < chance then
-        -- Success
-        local amount = random_e_level(ctx.enchant_lvl, ctx.scroll_bless)
-        return { result = "success", amount = amount }
+        -- Success (at the cap a normal scroll's "success" is only light)
+        if ctx.enchant_lvl >= max_enchant then
+            return { result = "nochange", amount = 0 }
+        end
+        return { result = "success", amount = success_amount(ctx, max_enchant) }
     elseif ctx.enchant_lvl >= 9 and rnd < (chance * 2) then
         -- No-change zone (only at +9+): intense light, nothing happens
         return { result = "nochange", amount = 0 }
     else
-        -- Failed: equipment breaks (normal and blessed alike)
-        return { result = "break", amount = 0 }
+        -- Failed: normal scrolls break the item, blessed scrolls do nothing
+        return failure(ctx)
     end
 end